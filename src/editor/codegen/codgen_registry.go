@@ -44,6 +44,7 @@ func init() {
 	RegisterType[engine.EntityId]()
 	RegisterType[engine.Host]()
 	RegisterType[engine.UpdateId]()
+	RegisterType[content_id.BoneMap]()
 	RegisterType[content_id.Css]()
 	RegisterType[content_id.Font]()
 	RegisterType[content_id.Html]()
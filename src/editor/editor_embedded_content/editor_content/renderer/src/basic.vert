@@ -4,6 +4,13 @@
 #define LAYOUT_VERT_COLOR 0
 #define LAYOUT_VERT_FLAGS 1
 
+#ifdef VERTEX_ANIMATION
+	#define SAMPLER_COUNT 2
+	#define LAYOUT_VERT_VAT_ANIMATION 2
+	#define LAYOUT_VERT_VAT_BOUNDS_MIN 3
+	#define LAYOUT_VERT_VAT_BOUNDS_SIZE 4
+#endif
+
 #define LAYOUT_FRAG_COLOR 0
 #define LAYOUT_FRAG_FLAGS 1
 #define LAYOUT_FRAG_POS 2
@@ -17,7 +24,7 @@ void main() {
 	fragColor = Color * color;
 	fragFlags = flags;
 	fragTexCoords = UV0;
-	fragNormal = mat3(model) * Normal;
+	fragNormal = mat3(model) * localNormal();
 	writeStandardPosition();
 	vec4 wp = worldPosition();
 	fragViewDir = cameraPosition.xyz - wp.xyz;
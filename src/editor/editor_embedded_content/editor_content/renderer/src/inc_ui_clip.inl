@@ -0,0 +1,99 @@
+// Clip shapes (clip-path) and gradient masks (mask-image) of UI elements.
+// Everything is in UI world space (pixels, y up). The shape is packed into
+// two floats, the first holds the kind of shape, the polygon point or mask
+// stop count, the kind of mask and if the mask repeats (see packClipShape
+// in ui/panel_clip.go), the second is the corner radius of an inset. The
+// data holds the polygon points (2 per column), or the center and radii of
+// the shape followed by the geometry, stop positions and stop values of the
+// mask
+#define CLIP_SHAPE_NONE    0
+#define CLIP_SHAPE_ELLIPSE 1
+#define CLIP_SHAPE_INSET   2
+#define CLIP_SHAPE_POLYGON 3
+
+#define CLIP_MASK_NONE   0
+#define CLIP_MASK_LINEAR 1
+#define CLIP_MASK_RADIAL 2
+
+vec2 clipPolygonPoint(mat4 points, int i) {
+	vec4 pair = points[i / 2];
+	return (i % 2 == 0) ? pair.xy : pair.zw;
+}
+
+// https://iquilezles.org/articles/distfunctions2d/
+float clipPolygonSDF(vec2 p, mat4 points, int count) {
+	vec2 first = clipPolygonPoint(points, 0);
+	float d = dot(p - first, p - first);
+	float s = 1.0;
+	for (int i = 0, j = count - 1; i < count; j = i, i++) {
+		vec2 vi = clipPolygonPoint(points, i);
+		vec2 vj = clipPolygonPoint(points, j);
+		vec2 e = vj - vi;
+		vec2 w = p - vi;
+		vec2 b = w - e * clamp(dot(w, e) / max(dot(e, e), 0.0001), 0.0, 1.0);
+		d = min(d, dot(b, b));
+		bvec3 c = bvec3(p.y >= vi.y, p.y < vj.y, e.x * w.y > e.y * w.x);
+		if (all(c) || all(not(c))) {
+			s *= -1.0;
+		}
+	}
+	return s * sqrt(d);
+}
+
+float clipShapeSDF(vec2 p, int kind, int count, float radius, mat4 data) {
+	vec4 rect = data[0];
+	if (kind == CLIP_SHAPE_ELLIPSE) {
+		vec2 radii = max(rect.zw, vec2(0.0001));
+		return (length((p - rect.xy) / radii) - 1.0) * min(radii.x, radii.y);
+	} else if (kind == CLIP_SHAPE_INSET) {
+		vec2 q = abs(p - rect.xy) - rect.zw + radius;
+		return min(max(q.x, q.y), 0.0) + length(max(q, 0.0)) - radius;
+	} else if (kind == CLIP_SHAPE_POLYGON) {
+		return clipPolygonSDF(p, data, count);
+	}
+	return -1.0;
+}
+
+float clipMaskValue(vec2 p, int kind, int count, bool repeating, mat4 data) {
+	vec4 geometry = data[1];
+	vec4 stops = data[2];
+	vec4 values = data[3];
+	float t = 0.0;
+	if (kind == CLIP_MASK_LINEAR) {
+		t = (dot(p, geometry.xy) - geometry.z) / max(geometry.w, 0.0001);
+	} else {
+		t = length((p - geometry.xy) / max(geometry.zw, vec2(0.0001)));
+	}
+	if (repeating) {
+		float span = stops[count - 1] - stops.x;
+		if (span > 0.0001) {
+			t = stops.x + mod(t - stops.x, span);
+		}
+	}
+	float value = values.x;
+	for (int i = 1; i < count; i++) {
+		if (t > stops[i - 1]) {
+			float len = max(stops[i] - stops[i - 1], 0.0001);
+			value = mix(values[i - 1], values[i], clamp((t - stops[i - 1]) / len, 0.0, 1.0));
+		}
+	}
+	return value;
+}
+
+// clipAlpha is the coverage of the fragment by the clip shape (with a one
+// pixel soft edge) multiplied by the value of the mask. The position holds
+// the fragment in xy and the packed shape in zw
+float clipAlpha(vec4 pos, mat4 data) {
+	int packed = int(pos.z + 0.5);
+	int kind = packed & 3;
+	int count = (packed >> 2) & 15;
+	int mask = (packed >> 6) & 3;
+	float alpha = 1.0;
+	if (kind != CLIP_SHAPE_NONE) {
+		alpha = clamp(0.5 - clipShapeSDF(pos.xy, kind, count, pos.w, data), 0.0, 1.0);
+	}
+	if (mask != CLIP_MASK_NONE && count > 0) {
+		alpha *= clipMaskValue(pos.xy, mask, count, (packed & 256) != 0, data);
+	}
+	return alpha;
+}
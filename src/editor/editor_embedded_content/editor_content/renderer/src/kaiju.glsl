@@ -180,6 +180,15 @@ layout(set = 0, binding = 0) readonly uniform UniformBufferObject {
 #ifdef LAYOUT_VERT_FRUSTUM_PROJECTION
 	layout(location = LOCATION_START+LAYOUT_VERT_FRUSTUM_PROJECTION) in mat4 frustumProjection;
 #endif
+#ifdef LAYOUT_VERT_VAT_ANIMATION
+	layout(location = LOCATION_START+LAYOUT_VERT_VAT_ANIMATION) in vec4 vatAnimation;
+#endif
+#ifdef LAYOUT_VERT_VAT_BOUNDS_MIN
+	layout(location = LOCATION_START+LAYOUT_VERT_VAT_BOUNDS_MIN) in vec4 vatBoundsMin;
+#endif
+#ifdef LAYOUT_VERT_VAT_BOUNDS_SIZE
+	layout(location = LOCATION_START+LAYOUT_VERT_VAT_BOUNDS_SIZE) in vec4 vatBoundsSize;
+#endif
 
 #ifdef LAYOUT_FRAG_COLOR
 	layout(location = LAYOUT_FRAG_COLOR) FRAG_INOUT vec4 fragColor;
@@ -297,9 +306,64 @@ layout(set = 0, binding = 0) readonly uniform UniformBufferObject {
 		return skinMatrix() * vec4(Position, 1.0);
 	}
 
+#ifdef VERTEX_ANIMATION
+	// The vertex animation texture is the last texture of the material, each
+	// vertex uses 3 rows: high position bytes, low position bytes, and normal
+	ivec2 vertexAnimationTexel(int frame, int row) {
+		int width = int(vatBoundsMin.w);
+		int rowsPerFrame = int(vatBoundsSize.w);
+		return ivec2(gl_VertexIndex % width,
+			frame * rowsPerFrame + (gl_VertexIndex / width) * 3 + row);
+	}
+
+	vec3 vertexAnimationFramePosition(int frame) {
+		vec3 hi = texelFetch(textures[SAMPLER_COUNT-1], vertexAnimationTexel(frame, 0), 0).rgb;
+		vec3 lo = texelFetch(textures[SAMPLER_COUNT-1], vertexAnimationTexel(frame, 1), 0).rgb;
+		vec3 q = (round(hi * 255.0) * 256.0 + round(lo * 255.0)) / 65535.0;
+		return vatBoundsMin.xyz + q * vatBoundsSize.xyz;
+	}
+
+	vec3 vertexAnimationFrameNormal(int frame) {
+		vec3 n = texelFetch(textures[SAMPLER_COUNT-1], vertexAnimationTexel(frame, 2), 0).rgb;
+		return n * 2.0 - 1.0;
+	}
+
+	// vertexAnimationFrames returns the two frames to blend between and the
+	// amount to blend between them for the current time
+	vec3 vertexAnimationFrames() {
+		float frameCount = max(vatAnimation.y, 1.0);
+		float t = max(time - vatAnimation.z, 0.0) * vatAnimation.w * vatAnimation.x;
+		float f = frameCount > 1.0 ? mod(t, frameCount - 1.0) : 0.0;
+		float f0 = floor(f);
+		return vec3(f0, min(f0 + 1.0, frameCount - 1.0), f - f0);
+	}
+
+	vec3 vertexAnimationPosition() {
+		vec3 frames = vertexAnimationFrames();
+		return mix(vertexAnimationFramePosition(int(frames.x)),
+			vertexAnimationFramePosition(int(frames.y)), frames.z);
+	}
+
+	vec3 vertexAnimationNormal() {
+		vec3 frames = vertexAnimationFrames();
+		return normalize(mix(vertexAnimationFrameNormal(int(frames.x)),
+			vertexAnimationFrameNormal(int(frames.y)), frames.z));
+	}
+#endif
+
+	vec3 localNormal() {
+	#ifdef VERTEX_ANIMATION
+		return vertexAnimationNormal();
+	#else
+		return Normal;
+	#endif
+	}
+
 	vec4 worldPosition() {
 	#ifdef SKINNING
 		return skinWorldPosition();
+	#elif defined(VERTEX_ANIMATION)
+		return model * vec4(vertexAnimationPosition(), 1.0);
 	#else
 		return model * vec4(Position, 1.0);
 	#endif
@@ -5,6 +5,8 @@ layout(location = 1) in vec4 fragBGColor;
 layout(location = 2) in vec2 fragTexCoord;
 layout(location = 3) in vec2 fragPxRange;
 layout(location = 4) in vec2 fragTexRange;
+layout(location = 5) in vec4 fragClipPos;
+layout(location = 6) in mat4 fragClipData;
 
 layout(binding = 1) uniform sampler2D texSampler;
 
@@ -13,6 +15,8 @@ layout(location = 0) out vec4 outColor;
 layout(location = 1) out float reveal;
 #endif
 
+#include "inc_ui_clip.inl"
+
 float median(float r, float g, float b) {
 	return max(min(r, g), min(max(r, g), b));
 }
@@ -30,5 +34,6 @@ void main() {
 	float opacity = clamp(dist * screenPxRange() + 0.5, 0.0, 1.0);
 
 	vec4 unWeightedColor = mix(fragBGColor, fragColor, opacity);
+	unWeightedColor.a *= clipAlpha(fragClipPos, fragClipData);
 #include "inc_fragment_oit_block.inl"
 }
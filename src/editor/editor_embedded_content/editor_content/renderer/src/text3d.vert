@@ -6,13 +6,17 @@ layout(location = LOCATION_START) in vec4 uvs;
 layout(location = LOCATION_START+1) in vec4 fgColor;
 layout(location = LOCATION_START+2) in vec4 bgColor;
 layout(location = LOCATION_START+3) in vec4 scissor;
-layout(location = LOCATION_START+4) in vec2 pxRange;
+// zw is the packed clip shape, see inc_ui_clip.inl
+layout(location = LOCATION_START+4) in vec4 pxRange;
+layout(location = LOCATION_START+5) in mat4 clipData;
 
 layout(location = 0) out vec4 fragColor;
 layout(location = 1) out vec4 fragBGColor;
 layout(location = 2) out vec2 fragTexCoord;
 layout(location = 3) out vec2 fragPxRange;
 layout(location = 4) out vec2 fragTexRange;
+layout(location = 5) out vec4 fragClipPos;
+layout(location = 6) out mat4 fragClipData;
 
 void main() {
     vec2 uv = UV0;
@@ -23,5 +27,8 @@ void main() {
 	fragColor = Color * fgColor;
 	fragBGColor = bgColor;
 	gl_Position = projection * view * model * vec4(Position, 1.0);
-	fragPxRange = pxRange;
+	fragPxRange = pxRange.xy;
+	// Clipping is only done for UI text
+	fragClipPos = vec4(0.0);
+	fragClipData = mat4(0.0);
 }
\ No newline at end of file
@@ -12,7 +12,9 @@ layout(location = LOCATION_START+6) in vec4 borderSize;
 layout(location = LOCATION_START+7) in mat4 borderColor;
 layout(location = LOCATION_START+11) in vec2 borderLen;
 layout(location = LOCATION_START+12) in vec4 outlineColor;
-layout(location = LOCATION_START+13) in vec2 outlineSize;
+// zw is the packed clip shape, see inc_ui_clip.inl
+layout(location = LOCATION_START+13) in vec4 outlineSize;
+layout(location = LOCATION_START+14) in mat4 clipData;
 
 layout(location = 0) out vec4 fragColor;
 layout(location = 1) out vec4 fragBGColor;
@@ -25,6 +27,8 @@ layout(location = 10) out vec2 fragBorderLen;
 layout(location = 11) out vec4 fragUvs;
 layout(location = 12) out vec4 fragOutlineColor;
 layout(location = 13) out vec2 fragOutlineSize;
+layout(location = 14) out vec4 fragClipPos;
+layout(location = 15) out mat4 fragClipData;
 
 void main() {
 	vec4 vPos = model * vec4(Position, 1.0);
@@ -49,7 +53,9 @@ void main() {
 	fragUvs = uvs;
 	fragUvs.y = v;
 	fragOutlineColor = outlineColor;
-	fragOutlineSize = outlineSize;
+	fragOutlineSize = outlineSize.xy;
+	fragClipPos = vec4(vPos.xy, outlineSize.zw);
+	fragClipData = clipData;
 
 	gl_ClipDistance[0] = vPos.x - scissor.x;
 	gl_ClipDistance[1] = vPos.y - scissor.y;
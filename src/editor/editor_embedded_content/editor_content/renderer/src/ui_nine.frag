@@ -11,6 +11,8 @@ layout(location = 10) in vec2 fragNineSliceEdgeLen;
 layout(location = 11) in vec4 fragUvs;
 layout(location = 12) in vec4 fragOutlineColor;
 layout(location = 13) in vec2 fragOutlineSize;
+layout(location = 14) in vec4 fragClipPos;
+layout(location = 15) in mat4 fragClipData;
 
 layout(binding = 1) uniform sampler2D texSampler;
 
@@ -21,6 +23,8 @@ layout(location = 1) out float reveal;
 
 const float edgeSoftness = 2.0;
 
+#include "inc_ui_clip.inl"
+
 float processAxis(float coord, float border, float ratio) {
 	float len = border * ratio;
 	float lScale = 1.0 - len * 2.0;
@@ -145,5 +149,6 @@ void main(void) {
 		unWeightedColor = fragOutlineColor;
 		unWeightedColor.a *= outerAlpha * innerAlpha;
 	}
+	unWeightedColor.a *= clipAlpha(fragClipPos, fragClipData);
 #include "inc_fragment_oit_block.inl"
 }
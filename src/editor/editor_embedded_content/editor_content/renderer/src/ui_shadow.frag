@@ -0,0 +1,72 @@
+#version 450
+
+layout(location = 0) in vec4 fragColor;
+layout(location = 1) in vec4 fragSize2D;
+layout(location = 2) in vec4 fragBorderRadius;
+layout(location = 3) in vec4 fragShadow;
+layout(location = 4) in vec2 fragPixPos;
+
+layout(location = 0) out vec4 outColor;
+#ifdef OIT
+layout(location = 1) out float reveal;
+#endif
+
+const float edgeSoftness = 1.0;
+
+//https://www.shadertoy.com/view/tltXDl
+float roundedBoxSDF(vec2 centerPosition, vec2 size, vec4 radius) {
+	radius.xy = (centerPosition.x > 0.0) ? radius.xw : radius.yz;
+	radius.x  = (centerPosition.y < 0.0) ? radius.x  : radius.y;
+	vec2 q = abs(centerPosition) - size + radius.x;
+	return min(max(q.x,q.y),0.0) + length(max(q,0.0)) - radius.x;
+}
+
+// Abramowitz and Stegun approximation of the error function
+vec2 erf2(vec2 x) {
+	vec2 s = sign(x);
+	vec2 a = abs(x);
+	x = 1.0 + (0.278393 + (0.230389 + 0.078108 * (a * a)) * a) * a;
+	x *= x;
+	return s - s / (x * x);
+}
+
+// Coverage of a rounded box convolved with a gaussian, approximated by
+// integrating the gaussian across the signed distance to the box edge
+float shadowCoverage(float dist, float sigma) {
+	if (sigma < 0.001) {
+		return 1.0 - smoothstep(-edgeSoftness, edgeSoftness, dist);
+	}
+	float d = dist / (sigma * sqrt(2.0));
+	return 0.5 - 0.5 * erf2(vec2(d)).x;
+}
+
+void main(void) {
+	vec2 halfSize = fragSize2D.xy * 0.5;
+	vec2 offset = fragShadow.xy;
+	float sigma = fragShadow.z;
+	float spread = fragShadow.w;
+	bool inset = fragSize2D.w > 0.5;
+	// Flip to the same orientation the panel shader feeds into roundedBoxSDF
+	// where +x is the left side and +y is the top
+	vec2 boxPos = vec2(-fragPixPos.x, fragPixPos.y);
+	float boxDist = roundedBoxSDF(boxPos, halfSize, fragBorderRadius);
+	float alpha;
+	if (inset) {
+		// Everything inside the border box that isn't covered by the shrunken,
+		// offset box is in shadow
+		vec2 innerSize = max(halfSize - vec2(spread), vec2(0.0));
+		float innerDist = roundedBoxSDF(boxPos + offset * vec2(1.0, -1.0),
+			innerSize, fragBorderRadius);
+		alpha = 1.0 - shadowCoverage(innerDist, sigma);
+		alpha *= 1.0 - smoothstep(-edgeSoftness, edgeSoftness, boxDist);
+	} else {
+		vec2 outerSize = max(halfSize + vec2(spread), vec2(0.0));
+		float outerDist = roundedBoxSDF(boxPos + offset * vec2(1.0, -1.0),
+			outerSize, fragBorderRadius);
+		alpha = shadowCoverage(outerDist, sigma);
+		// Outer shadows are never drawn below the border box itself
+		alpha *= smoothstep(-edgeSoftness, edgeSoftness, boxDist);
+	}
+	vec4 unWeightedColor = vec4(fragColor.rgb, fragColor.a * alpha);
+#include "inc_fragment_oit_block.inl"
+}
@@ -0,0 +1,42 @@
+#version 460
+
+#include "inc_vertex.inl"
+
+layout(location = LOCATION_START) in vec4 shadowColor;
+layout(location = LOCATION_START+1) in vec4 scissor;
+layout(location = LOCATION_START+2) in vec4 size2D;
+layout(location = LOCATION_START+3) in vec4 borderRadius;
+layout(location = LOCATION_START+4) in vec4 shadow;
+
+layout(location = 0) out vec4 fragColor;
+layout(location = 1) out vec4 fragSize2D;
+layout(location = 2) out vec4 fragBorderRadius;
+layout(location = 3) out vec4 fragShadow;
+layout(location = 4) out vec2 fragPixPos;
+
+// Matches shadowDepthBias in engine/ui/shadow.go
+const float depthBias = 0.005;
+
+void main() {
+	vec4 vPos = model * vec4(Position, 1.0);
+	// size2D.z is how far the shadow paints past the border box, grow the quad
+	// by that much so the blur and spread have room to draw
+	vec2 outset = sign(Position.xy) * size2D.z;
+	vPos.xy += outset;
+	vPos.x = round(vPos.x);
+	vPos.y = round(vPos.y);
+	// Outer shadows sit behind the panel, inset shadows on top of its fill
+	vPos.z += size2D.w > 0.5 ? depthBias : -depthBias;
+	gl_Position = uiProjection * uiView * vPos;
+	fragColor = Color * shadowColor;
+	fragSize2D = size2D;
+	fragBorderRadius = borderRadius;
+	fragShadow = shadow;
+	// Pixel position relative to the center of the border box
+	fragPixPos = Position.xy * size2D.xy + outset;
+
+	gl_ClipDistance[0] = vPos.x - scissor.x;
+	gl_ClipDistance[1] = vPos.y - scissor.y;
+	gl_ClipDistance[2] = scissor.z - vPos.x;
+	gl_ClipDistance[3] = scissor.w - vPos.y;
+}
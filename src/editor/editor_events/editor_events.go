@@ -56,6 +56,10 @@ type EditorEvents struct {
 	// content id and switch itself active.
 	OnRequestViewHtmlUi events.EventWithArg[string]
 
+	// OnRequestViewComponentUi asks the UI workspace to preview the given UI
+	// component content id and switch itself active.
+	OnRequestViewComponentUi events.EventWithArg[string]
+
 	// OnRequestOpenParticleSystem asks the VFX workspace to open the given
 	// particle system content id and switch itself active.
 	OnRequestOpenParticleSystem events.EventWithArg[string]
@@ -29,6 +29,7 @@ type skinAnimationGizmo struct {
 	anim       framework.SkinAnimation
 	animations []kaiju_mesh.KaijuMeshAnimation
 	meshId     string
+	animMeshId string
 	animName   string
 }
 
@@ -78,10 +79,12 @@ func (c *SkinAnimationEntityDataRenderer) Hide(host *engine.Host, target *editor
 func (c *SkinAnimationEntityDataRenderer) Update(host *engine.Host, target *editor_stage_manager.StageEntity, data *entity_data_binding.EntityDataEntry) {
 	if g, ok := c.Skins[target]; ok {
 		meshId := string(data.FieldValueByName("MeshId").(content_id.Mesh))
+		animMeshId := string(data.FieldValueByName("AnimationMeshId").(content_id.Mesh))
 		name := data.FieldValueByName("AnimName").(string)
 		skin := target.StageData.ShaderData.SkinningHeader()
 		if skin != nil {
-			if g.meshId != meshId {
+			if g.meshId != meshId || g.animMeshId != animMeshId {
+				g.animations = nil
 				c.bindSkin(host, target, data)
 			}
 			if !strings.EqualFold(g.animName, name) {
@@ -94,6 +97,7 @@ func (c *SkinAnimationEntityDataRenderer) Update(host *engine.Host, target *edit
 			}
 		}
 		g.meshId = meshId
+		g.animMeshId = animMeshId
 		g.animName = name
 	}
 }
@@ -141,7 +145,7 @@ func (c *SkinAnimationEntityDataRenderer) bindSkin(host *engine.Host, target *ed
 				}
 			}
 		}
-		g.animations = km.Animations
+		g.animations = skinAnimationEntityData(data).LoadAnimations(km, host)
 	}
 	if len(g.animations) > 0 {
 		if g.animName == "" {
@@ -168,20 +172,14 @@ func (c *SkinAnimationEntityDataRenderer) update(deltaTime float64) {
 			continue
 		}
 		v.anim.Update(deltaTime)
-		frame := v.anim.CurrentFrame()
-		for i := range frame.Key.Bones {
-			frame.Bone = &frame.Key.Bones[i]
-			bone := skin.FindBone(int32(frame.Bone.NodeIndex))
+		samples := v.anim.Sample()
+		for i := range samples {
+			bone := skin.FindBone(samples[i].BoneId)
 			if bone == nil {
 				continue
 			}
-			nextFrame, ok := v.anim.FindNextFrameForBone(bone.Id, frame.Bone.PathType)
-			if !ok {
-				nextFrame = frame
-				nextFrame.Bone = frame.Bone
-			}
-			data := v.anim.Interpolate(frame, nextFrame)
-			switch frame.Bone.PathType {
+			data := samples[i].Data
+			switch samples[i].PathType {
 			case load_result.AnimPathTranslation:
 				bone.Transform.SetLocalPosition(matrix.Vec3FromSlice(data[:]))
 			case load_result.AnimPathRotation:
@@ -192,3 +190,11 @@ func (c *SkinAnimationEntityDataRenderer) update(deltaTime float64) {
 		}
 	}
 }
+
+func skinAnimationEntityData(data *entity_data_binding.EntityDataEntry) engine_entity_data_skin_animation.SkinAnimationEntityData {
+	return engine_entity_data_skin_animation.SkinAnimationEntityData{
+		MeshId:          data.FieldValueByName("MeshId").(content_id.Mesh),
+		AnimationMeshId: data.FieldValueByName("AnimationMeshId").(content_id.Mesh),
+		BoneMapId:       data.FieldValueByName("BoneMapId").(content_id.BoneMap),
+	}
+}
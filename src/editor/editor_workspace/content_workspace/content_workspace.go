@@ -943,7 +943,8 @@ func (w *ContentWorkspace) rightClickContent(e *document.Element) {
 	}
 	if cc, err := w.cache.Read(id); err == nil {
 		isEditableText := cc.Config.Type == (content_database.Html{}).TypeName() ||
-			cc.Config.Type == (content_database.Css{}).TypeName()
+			cc.Config.Type == (content_database.Css{}).TypeName() ||
+			cc.Config.Type == (content_database.Component{}).TypeName()
 		if cc.Config.Type == (content_database.TableOfContents{}).TypeName() {
 			options = append(options, context_menu.ContextMenuOption{
 				Label: "Add to table of contents",
@@ -961,6 +962,11 @@ func (w *ContentWorkspace) rightClickContent(e *document.Element) {
 				Label: "View in UI workspace",
 				Call:  func() { w.editor.Events().OnRequestViewHtmlUi.Execute(id) },
 			})
+		} else if cc.Config.Type == (content_database.Component{}).TypeName() {
+			options = append(options, context_menu.ContextMenuOption{
+				Label: "Preview in UI workspace",
+				Call:  func() { w.editor.Events().OnRequestViewComponentUi.Execute(id) },
+			})
 		}
 		if cc.Config.Type == (content_database.Terrain{}).TypeName() {
 			options = append(options, context_menu.ContextMenuOption{
@@ -1233,6 +1239,8 @@ func (w *ContentWorkspace) openInEditor(cc content_database.CachedContent) {
 	switch cc.Config.Type {
 	case content_database.Html{}.TypeName():
 		fallthrough
+	case content_database.Component{}.TypeName():
+		fallthrough
 	case content_database.Css{}.TypeName():
 		ed = w.editor.Settings().CodeEditor
 	case content_database.Mesh{}.TypeName():
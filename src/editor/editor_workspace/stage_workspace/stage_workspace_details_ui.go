@@ -1656,6 +1656,9 @@ func (dui *WorkspaceDetailsUI) selectAnimationNames(g *entity_data_binding.Entit
 	if id == "" || !ok {
 		return []string{}
 	}
+	if animId, ok := g.FieldValueByName("AnimationMeshId").(content_id.Mesh); ok && animId != "" {
+		id = animId
+	}
 	km, err := kaiju_mesh.ReadMesh(string(id), dui.workspace.Value().Host)
 	if err != nil {
 		return []string{}
@@ -52,6 +52,7 @@ type UIWorkspace struct {
 	ratioX        *document.Element
 	ratioY        *document.Element
 	html          string
+	component     string
 	data          string
 	styles        []string
 	bindingData   any
@@ -59,6 +60,7 @@ type UIWorkspace struct {
 	lastTime      float64
 	ratio         matrix.Vec2
 	openHtmlSubID events.Id
+	openCompSubID events.Id
 }
 
 func (w *UIWorkspace) ID() string          { return ID }
@@ -91,6 +93,10 @@ func (w *UIWorkspace) Initialize(ed editor_workspace.WorkspaceEditorInterface) e
 		ed.SelectWorkspace(ID)
 		w.OpenHtml(htmlID)
 	})
+	w.openCompSubID = ed.Events().OnRequestViewComponentUi.Add(func(componentID string) {
+		ed.SelectWorkspace(ID)
+		w.OpenComponent(componentID)
+	})
 	return nil
 }
 
@@ -98,6 +104,7 @@ func (w *UIWorkspace) Shutdown() {
 	defer tracing.NewRegion("UIWorkspace.Shutdown").End()
 	if w.ed != nil {
 		w.ed.Events().OnRequestViewHtmlUi.Remove(w.openHtmlSubID)
+		w.ed.Events().OnRequestViewComponentUi.Remove(w.openCompSubID)
 	}
 	w.CommonShutdown()
 }
@@ -106,7 +113,7 @@ func (w *UIWorkspace) Open() {
 	defer tracing.NewRegion("UIWorkspace.Open").End()
 	w.CommonOpen()
 	w.applyRatio()
-	if w.html != "" {
+	if w.html != "" || w.component != "" {
 		w.previewHelp.UI.Hide()
 	}
 }
@@ -125,12 +132,14 @@ func (w *UIWorkspace) clickFile(e *document.Element) {
 	file_browser.Show(w.Host, file_browser.Config{
 		Title:        "Load HTML file",
 		StartingPath: w.ed.ProjectFileSystem().FullPath(""),
-		ExtFilter:    []string{".html"},
+		ExtFilter:    []string{".html", ".component"},
 		OnlyFiles:    true,
 		OnCancel:     w.ed.FocusInterface,
 		OnConfirm: func(paths []string) {
 			w.ed.FocusInterface()
-			if paths[0] != "" {
+			if strings.HasSuffix(paths[0], ".component") {
+				w.OpenComponent(paths[0])
+			} else if paths[0] != "" {
 				w.OpenHtml(paths[0])
 			}
 		},
@@ -138,16 +147,16 @@ func (w *UIWorkspace) clickFile(e *document.Element) {
 }
 
 func (w *UIWorkspace) clickEdit(e *document.Element) {
-	if w.html == "" {
+	if w.html == "" && w.component == "" {
 		return
 	}
-	path := project_file_system.HtmlPath(w.html)
+	path := w.contentPath()
 	pfs := w.ed.ProjectFileSystem()
 	exec.Command("code", pfs.FullPath(""), pfs.FullPath(path.String())).Run()
 }
 
 func (w *UIWorkspace) clickLoadData(e *document.Element) {
-	if w.html == "" {
+	if w.html == "" && w.component == "" {
 		return
 	}
 	w.ed.BlurInterface()
@@ -161,7 +170,7 @@ func (w *UIWorkspace) clickLoadData(e *document.Element) {
 			w.ed.FocusInterface()
 			w.data = paths[0]
 			w.bindingData = loadBindingData(paths[0])
-			w.OpenHtml(w.html)
+			w.reopen()
 		},
 	})
 }
@@ -221,7 +230,7 @@ func (w *UIWorkspace) Update(deltaTime float64) {
 func (w *UIWorkspace) processFilesChanges() {
 	pfs := w.ed.ProjectFileSystem()
 	htmlChanged := false
-	if s, err := pfs.Stat(project_file_system.HtmlPath(w.html).String()); err == nil && s.ModTime().After(w.lastMod) {
+	if s, err := pfs.Stat(w.contentPath().String()); err == nil && s.ModTime().After(w.lastMod) {
 		htmlChanged = true
 	}
 	for f := 0; f < len(w.styles) && !htmlChanged; f++ {
@@ -234,7 +243,7 @@ func (w *UIWorkspace) processFilesChanges() {
 		htmlChanged = true
 	}
 	if htmlChanged {
-		w.OpenHtml(w.html)
+		w.reopen()
 		w.lastMod = time.Now()
 	}
 }
@@ -257,6 +266,7 @@ func (w *UIWorkspace) OpenHtml(html string) {
 	}
 	w.previewHelp.UI.Hide()
 	w.html = html
+	w.component = ""
 	if w.previewDoc != nil {
 		w.previewDoc.Destroy()
 		w.previewDoc = nil
@@ -273,6 +283,48 @@ func (w *UIWorkspace) OpenHtml(html string) {
 	w.lastMod = time.Now()
 }
 
+// OpenComponent previews the UI component with the given content id, it is
+// shown with the defaults of its properties and the fallbacks of its slots
+func (w *UIWorkspace) OpenComponent(id string) {
+	if id == "" {
+		return
+	}
+	w.previewHelp.UI.Hide()
+	w.html = ""
+	w.component = id
+	if w.previewDoc != nil {
+		w.previewDoc.Destroy()
+		w.previewDoc = nil
+	}
+	w.Host.RunOnMainThread(func() {
+		c, err := markup.LoadComponent(w.Host.AssetDatabase(), w.component)
+		if err != nil {
+			slog.Error("failed to load the component", "error", err)
+			return
+		}
+		html := "<html><body><" + c.Name + "></" + c.Name + "></body></html>"
+		w.previewDoc = markup.DocumentFromHTMLString(&w.previewMan,
+			html, "", w.bindingData, nil, w.previewArea)
+		w.styles = w.styles[:0]
+	})
+	w.lastMod = time.Now()
+}
+
+func (w *UIWorkspace) reopen() {
+	if w.component != "" {
+		w.OpenComponent(w.component)
+	} else {
+		w.OpenHtml(w.html)
+	}
+}
+
+func (w *UIWorkspace) contentPath() project_file_system.ContentPath {
+	if w.component != "" {
+		return project_file_system.ComponentPath(w.component)
+	}
+	return project_file_system.HtmlPath(w.html)
+}
+
 func loadBindingData(bindingFile string) any {
 	if _, err := os.Stat(bindingFile); err != nil {
 		slog.Error("failed to load the data file", "file", bindingFile, "error", err)
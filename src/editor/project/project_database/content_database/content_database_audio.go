@@ -0,0 +1,240 @@
+/******************************************************************************/
+/* content_database_audio.go                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package content_database
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"kaijuengine.com/editor/project/project_file_system"
+	"kaijuengine.com/platform/audio/audio_pcm"
+	"kaijuengine.com/platform/filesystem"
+	"kaijuengine.com/platform/profiler/tracing"
+)
+
+// audioToolName is the external tool used to decode compressed audio files
+// and to encode Ogg Vorbis. It must be installed and on the PATH to process
+// .mp3 and .ogg files or to compress audio, WAV files are processed without it
+const audioToolName = "ffmpeg"
+
+const (
+	defaultAudioQuality     = 4
+	audioNormalizePeakLimit = -1
+)
+
+// AudioImportSettings are the import settings that are shared by the [Sound]
+// and [Music] categories. The zero value keeps the audio as it is.
+//
+// Processing compressed source files (.mp3, .ogg) and compressing audio both
+// require ffmpeg to be installed and on the PATH, the import will fail with an
+// [AudioToolMissingError] when it is not.
+type AudioImportSettings struct {
+	// SampleRate converts the audio to the given sample rate, 0 will keep the
+	// sample rate of the source file
+	SampleRate int `json:",omitempty"`
+
+	// Mono will mix all of the channels down into a single channel
+	Mono bool `json:",omitempty"`
+
+	// Compress will encode the audio as Ogg Vorbis. Compressed source files
+	// (.mp3, .ogg) are always stored as Ogg Vorbis when they are processed.
+	Compress bool `json:",omitempty"`
+
+	// Quality is the Ogg Vorbis quality in the range [-1, 10], 0 will use the
+	// default quality
+	Quality float32 `json:",omitempty"`
+
+	// LoopStart is the time (in seconds) that looping playback jumps back to
+	// when it reaches the end of the audio
+	LoopStart float64 `json:",omitempty"`
+
+	// LoopEnd is the time (in seconds) where the audio is cut off, 0 will
+	// keep the full length of the audio
+	LoopEnd float64 `json:",omitempty"`
+
+	// Normalize will apply EBU R128 loudness normalization to the audio
+	Normalize bool `json:",omitempty"`
+
+	// TargetLoudness is the integrated loudness (in LUFS) that the audio is
+	// normalized to, 0 will use the EBU R128 target of -23 LUFS
+	TargetLoudness float64 `json:",omitempty"`
+}
+
+// AudioImportInfo is the information that is measured by the importer when
+// the audio is processed. It is written by the importer on every import and
+// is not an import setting, changes to it are overwritten.
+type AudioImportInfo struct {
+	// Loudness is the integrated loudness (in LUFS) of the source audio
+	Loudness float64 `json:",omitempty"`
+
+	// Gain is the gain (in dB) that was applied to normalize the audio
+	Gain float64 `json:",omitempty"`
+}
+
+func (s AudioImportSettings) targetLoudness() float64 {
+	if s.TargetLoudness == 0 {
+		return audio_pcm.EBUR128TargetLoudness
+	}
+	return s.TargetLoudness
+}
+
+func (s AudioImportSettings) quality() float32 {
+	if s.Quality == 0 {
+		return defaultAudioQuality
+	}
+	return min(max(s.Quality, -1), 10)
+}
+
+// needsProcessing returns false if the settings will not change the source
+func (s AudioImportSettings) needsProcessing() bool {
+	return s.Normalize || s.SampleRate > 0 || s.Mono || s.Compress ||
+		s.LoopStart > 0 || s.LoopEnd > 0
+}
+
+// audioImportPostProcData is the post process data of sound and music imports
+type audioImportPostProcData struct {
+	info      AudioImportInfo
+	processed bool
+}
+
+// importAudio reads the source audio file and processes it using the import
+// settings. Compressed source files can only be processed when the external
+// audio tool is installed, otherwise an [AudioToolMissingError] is returned.
+func importAudio(src string, settings AudioImportSettings) (ProcessedImport, error) {
+	defer tracing.NewRegion("content_database.importAudio").End()
+	data, err := filesystem.ReadFile(src)
+	if err != nil {
+		return ProcessedImport{}, err
+	}
+	out, info, err := processAudio(src, data, settings)
+	if err != nil {
+		return ProcessedImport{}, err
+	}
+	return ProcessedImport{
+		Variants:        []ImportVariant{{Name: fileNameNoExt(src), Data: out}},
+		postProcessData: info,
+	}, nil
+}
+
+func processAudio(src string, data []byte, settings AudioImportSettings) ([]byte, audioImportPostProcData, error) {
+	info := audioImportPostProcData{}
+	if !settings.needsProcessing() {
+		return data, info, nil
+	}
+	isWav := audio_pcm.IsWav(data)
+	if settings.Compress || !isWav {
+		if _, err := exec.LookPath(audioToolName); err != nil {
+			return data, info, AudioToolMissingError{Path: src, Tool: audioToolName}
+		}
+	}
+	var buf audio_pcm.Buffer
+	var err error
+	if isWav {
+		buf, err = audio_pcm.DecodeWav(data)
+	} else {
+		buf, err = decodeAudioWithTool(src)
+	}
+	if err != nil {
+		return data, info, AudioImportError{err, "decode"}
+	}
+	if settings.LoopEnd > 0 {
+		buf.Trim(int(math.Round(settings.LoopEnd * float64(buf.SampleRate))))
+	}
+	if settings.Mono {
+		buf = buf.Downmix()
+	}
+	if settings.SampleRate > 0 {
+		buf = buf.Resample(settings.SampleRate)
+	}
+	if loudness := buf.Loudness(); !math.IsInf(loudness, -1) {
+		info.info.Loudness = loudness
+		if settings.Normalize {
+			info.info.Gain = buf.NormalizationGain(settings.targetLoudness(), audioNormalizePeakLimit)
+			buf.ApplyGain(info.info.Gain)
+		}
+	}
+	loopStart := int(math.Round(settings.LoopStart * float64(buf.SampleRate)))
+	info.processed = true
+	if settings.Compress || !isWav {
+		out, err := encodeOggVorbisWithTool(buf, settings.quality(), loopStart)
+		if err != nil {
+			return data, info, AudioImportError{err, "encode"}
+		}
+		return out, info, nil
+	}
+	return audio_pcm.EncodeWav(buf, loopStart), info, nil
+}
+
+func decodeAudioWithTool(src string) (audio_pcm.Buffer, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(audioToolName, "-v", "error", "-i", src,
+		"-f", "wav", "-c:a", "pcm_f32le", "-")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return audio_pcm.Buffer{}, fmt.Errorf("%w: %s", err, stderr.String())
+	}
+	return audio_pcm.DecodeWav(stdout.Bytes())
+}
+
+func encodeOggVorbisWithTool(buf audio_pcm.Buffer, quality float32, loopStart int) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "kaiju-audio-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	in := filepath.Join(dir, "in.wav")
+	out := filepath.Join(dir, "out.ogg")
+	if err := os.WriteFile(in, audio_pcm.EncodeWav(buf, 0), os.ModePerm); err != nil {
+		return nil, err
+	}
+	args := []string{"-v", "error", "-y", "-i", in, "-c:a", "libvorbis",
+		"-q:a", strconv.FormatFloat(float64(quality), 'f', -1, 32)}
+	if loopStart > 0 {
+		args = append(args, "-metadata",
+			audio_pcm.VorbisLoopStartComment+"="+strconv.Itoa(loopStart))
+	}
+	args = append(args, out)
+	var stderr bytes.Buffer
+	cmd := exec.Command(audioToolName, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, stderr.String())
+	}
+	return os.ReadFile(out)
+}
+
+// updateAudioImportInfo stores the results of the audio import into the
+// import info of the content config, keeping it apart from the settings
+func updateAudioImportInfo(proc ProcessedImport, res *ImportResult, fs *project_file_system.FileSystem, cache *Cache, importInfo func(cfg *ContentConfig) **AudioImportInfo) error {
+	data, ok := proc.postProcessData.(audioImportPostProcData)
+	if !ok {
+		return nil
+	}
+	cc, err := cache.Read(res.Id)
+	if err != nil {
+		return err
+	}
+	info := importInfo(&cc.Config)
+	if data.processed {
+		*info = &data.info
+	} else if *info != nil {
+		*info = nil
+	} else {
+		return nil
+	}
+	if err := WriteConfig(cc.Path, cc.Config, fs); err != nil {
+		return err
+	}
+	cache.IndexCachedContent(cc)
+	return nil
+}
@@ -0,0 +1,106 @@
+/******************************************************************************/
+/* content_database_audio_test.go                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package content_database
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"kaijuengine.com/platform/audio/audio_pcm"
+)
+
+func testAudioWavFile(t *testing.T) string {
+	t.Helper()
+	const rate = 44100
+	buf := audio_pcm.Buffer{SampleRate: rate, Channels: 2, Samples: make([]float32, rate*2*2)}
+	amplitude := audio_pcm.DecibelsToLinear(-35)
+	for i := range buf.Frames() {
+		s := float32(amplitude * math.Sin(2*math.Pi*997*float64(i)/rate))
+		buf.Samples[i*2] = s
+		buf.Samples[i*2+1] = s
+	}
+	path := filepath.Join(t.TempDir(), "tone.wav")
+	if err := os.WriteFile(path, audio_pcm.EncodeWav(buf, 0), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportAudioNormalize(t *testing.T) {
+	proc, err := importAudio(testAudioWavFile(t), AudioImportSettings{Normalize: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := proc.postProcessData.(audioImportPostProcData).info
+	if math.Abs(info.Loudness+35) > 0.1 {
+		t.Errorf("expected the measured loudness to be -35 LUFS, got %f", info.Loudness)
+	}
+	buf, err := audio_pcm.DecodeWav(proc.Variants[0].Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := buf.Loudness(); math.Abs(l-audio_pcm.EBUR128TargetLoudness) > 0.1 {
+		t.Errorf("expected the imported loudness to be %d LUFS, got %f",
+			audio_pcm.EBUR128TargetLoudness, l)
+	}
+}
+
+func TestImportAudioSettings(t *testing.T) {
+	src := testAudioWavFile(t)
+	proc, err := importAudio(src, AudioImportSettings{
+		SampleRate: 22050,
+		Mono:       true,
+		LoopStart:  0.5,
+		LoopEnd:    1.5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := proc.Variants[0].Data
+	buf, err := audio_pcm.DecodeWav(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.Channels != 1 || buf.SampleRate != 22050 {
+		t.Errorf("expected mono 22050Hz audio, got %d channels at %dHz", buf.Channels, buf.SampleRate)
+	}
+	if math.Abs(buf.Duration()-1.5) > 0.001 {
+		t.Errorf("expected the audio to be cut at the loop end, got %f seconds", buf.Duration())
+	}
+	if loop, ok := audio_pcm.ReadLoopStart(data); !ok || loop != 0.5 {
+		t.Errorf("expected a loop start of 0.5 seconds, got %f (%t)", loop, ok)
+	}
+	if l := buf.Loudness(); math.Abs(l+38) > 0.2 {
+		t.Errorf("expected the downmixed audio not to be normalized, got %f LUFS", l)
+	}
+}
+
+func TestImportAudioUntouched(t *testing.T) {
+	src := testAudioWavFile(t)
+	proc, err := importAudio(src, AudioImportSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(src)
+	if string(proc.Variants[0].Data) != string(data) {
+		t.Error("expected the audio to be stored untouched when there is no processing")
+	}
+	if proc.postProcessData.(audioImportPostProcData).processed {
+		t.Error("expected the audio to not be marked as processed")
+	}
+}
+
+func TestImportAudioRequiresTool(t *testing.T) {
+	t.Setenv("PATH", "")
+	_, err := importAudio(testAudioWavFile(t), AudioImportSettings{Compress: true})
+	if !errors.As(err, &AudioToolMissingError{}) {
+		t.Errorf("expected the import to fail with a missing tool error, got %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+/******************************************************************************/
+/* content_database_bone_map.go                                               */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package content_database
+
+import (
+	"kaijuengine.com/editor/project/project_file_system"
+	"kaijuengine.com/platform/profiler/tracing"
+)
+
+func init() { addCategory(BoneMap{}) }
+
+// BoneMap is a [ContentCategory] represented by a file with a ".bonemap"
+// extension. It is a JSON file that maps the joint names of one skeleton to
+// the joint names of another so that animations can be retargeted between
+// meshes that don't share a skeleton.
+type BoneMap struct{}
+
+// See the documentation for the interface [ContentCategory] to learn more about
+// the following functions
+
+func (BoneMap) Path() string       { return project_file_system.ContentBoneMapFolder }
+func (BoneMap) TypeName() string   { return "BoneMap" }
+func (BoneMap) ExtNames() []string { return []string{".bonemap"} }
+
+func (BoneMap) Import(src string, _ *project_file_system.FileSystem) (ProcessedImport, error) {
+	defer tracing.NewRegion("BoneMap.Import").End()
+	return pathToTextData(src)
+}
+
+func (c BoneMap) Reimport(id string, cache *Cache, fs *project_file_system.FileSystem) (ProcessedImport, error) {
+	defer tracing.NewRegion("BoneMap.Reimport").End()
+	return reimportByNameMatching(c, id, cache, fs)
+}
+
+func (BoneMap) PostImportProcessing(proc ProcessedImport, res *ImportResult, fs *project_file_system.FileSystem, cache *Cache, linkedId string) error {
+	return nil
+}
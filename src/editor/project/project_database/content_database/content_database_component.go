@@ -0,0 +1,40 @@
+/******************************************************************************/
+/* content_database_component.go                                              */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package content_database
+
+import (
+	"kaijuengine.com/editor/project/project_file_system"
+	"kaijuengine.com/platform/profiler/tracing"
+)
+
+func init() { addCategory(Component{}) }
+
+// Component is a [ContentCategory] represented by a file with a ".component"
+// extension. It is a HTML file holding a single <kaiju-component> element
+// that defines a reusable custom element (its properties, scoped styles and
+// template with slots) which can be used by any HTML UI document.
+type Component struct{}
+
+// See the documentation for the interface [ContentCategory] to learn more about
+// the following functions
+
+func (Component) Path() string       { return project_file_system.ContentComponentFolder }
+func (Component) TypeName() string   { return "Component" }
+func (Component) ExtNames() []string { return []string{".component"} }
+
+func (Component) Import(src string, _ *project_file_system.FileSystem) (ProcessedImport, error) {
+	defer tracing.NewRegion("Component.Import").End()
+	return pathToTextData(src)
+}
+
+func (c Component) Reimport(id string, cache *Cache, fs *project_file_system.FileSystem) (ProcessedImport, error) {
+	defer tracing.NewRegion("Component.Reimport").End()
+	return reimportByNameMatching(c, id, cache, fs)
+}
+
+func (Component) PostImportProcessing(proc ProcessedImport, res *ImportResult, fs *project_file_system.FileSystem, cache *Cache, linkedId string) error {
+	return nil
+}
@@ -60,6 +60,24 @@ func (e ImageImportError) Error() string {
 	return fmt.Sprintf("image import failed on stage '%s' with error: %v", e.Stage, e.Err)
 }
 
+type AudioImportError struct {
+	Err   error
+	Stage string
+}
+
+func (e AudioImportError) Error() string {
+	return fmt.Sprintf("audio import failed on stage '%s' with error: %v", e.Stage, e.Err)
+}
+
+type AudioToolMissingError struct {
+	Path string
+	Tool string
+}
+
+func (e AudioToolMissingError) Error() string {
+	return fmt.Sprintf("the audio file '%s' can not be processed without %s, install it and make sure it is on the PATH", e.Path, e.Tool)
+}
+
 type ReimportSourceMissingError struct {
 	Id string
 }
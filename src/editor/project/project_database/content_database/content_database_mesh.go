@@ -35,6 +35,43 @@ func init() { addCategory(Mesh{}) }
 type Mesh struct{}
 type MeshConfig struct {
 	Submeshes []MeshSubmeshConfig `json:",omitempty"`
+	// AnimationCompression controls the key reduction and quantization of
+	// the animations in the mesh, nil will use the default compression
+	AnimationCompression *kaiju_mesh.AnimationCompression `json:",omitempty"`
+	// BakeVertexAnimation will bake each animation of the skinned meshes into
+	// a vertex animation texture which is imported alongside the mesh
+	BakeVertexAnimation      bool    `json:",omitempty"`
+	VertexAnimationFrameRate float32 `json:",omitempty"`
+}
+
+func (c *MeshConfig) animationCompression() kaiju_mesh.AnimationCompression {
+	if c == nil || c.AnimationCompression == nil {
+		return kaiju_mesh.DefaultAnimationCompression()
+	}
+	return *c.AnimationCompression
+}
+
+func (c *MeshConfig) vertexAnimationFrameRate() float32 {
+	if c == nil || c.VertexAnimationFrameRate <= 0 {
+		return kaiju_mesh.DefaultVertexAnimationFrameRate
+	}
+	return c.VertexAnimationFrameRate
+}
+
+// withSubmeshes creates a new config with the given submeshes while keeping
+// the import settings of this config
+func (c *MeshConfig) withSubmeshes(submeshes []MeshSubmeshConfig) *MeshConfig {
+	out := &MeshConfig{Submeshes: submeshes}
+	if c != nil {
+		out.AnimationCompression = c.AnimationCompression
+		out.BakeVertexAnimation = c.BakeVertexAnimation
+		out.VertexAnimationFrameRate = c.VertexAnimationFrameRate
+	}
+	if out.AnimationCompression == nil {
+		compression := kaiju_mesh.DefaultAnimationCompression()
+		out.AnimationCompression = &compression
+	}
+	return out
 }
 
 type MeshSubmeshConfig struct {
@@ -376,10 +413,14 @@ func (Mesh) PostImportProcessing(proc ProcessedImport, res *ImportResult, fs *pr
 		materials[data.set.Meshes[i].Key] = matId
 		data.set.Meshes[i].Material = matId
 	}
+	meshCompressSetAnimations(&data.set, cc.Config.Mesh)
+	if err := meshBakeVertexAnimations(&data.set, cc.Config.Mesh, res, fs, cache, linkedId); err != nil {
+		slog.Error("failed to bake the mesh vertex animations", "id", res.Id, "error", err)
+	}
 	if err := writeMeshSetTextureURIs(data.set, res.ContentPath().String(), fs, textureURIs); err != nil {
 		slog.Error("failed to write mesh GLB texture and material references", "id", res.Id, "error", err)
 	}
-	cc.Config.Mesh = &MeshConfig{Submeshes: meshConfigSubmeshes(data.set, materials, nil)}
+	cc.Config.Mesh = cc.Config.Mesh.withSubmeshes(meshConfigSubmeshes(data.set, materials, nil))
 	if err := WriteConfig(cc.Path, cc.Config, fs); err != nil {
 		return err
 	}
@@ -752,6 +793,10 @@ func (Mesh) PostReimportProcessing(proc ProcessedImport, res *ImportResult, fs *
 	for i := range data.set.Meshes {
 		data.set.Meshes[i].Material = materials[data.set.Meshes[i].Key]
 	}
+	meshCompressSetAnimations(&data.set, cc.Config.Mesh)
+	if err := meshBakeVertexAnimations(&data.set, cc.Config.Mesh, res, fs, cache, cc.Config.LinkedId); err != nil {
+		slog.Error("failed to bake the mesh vertex animations", "id", res.Id, "error", err)
+	}
 	serialized, err := data.set.SerializeWithOptions(kaiju_mesh.SerializeOptions{
 		MeshTextureURIs: textureURIs,
 	})
@@ -761,7 +806,7 @@ func (Mesh) PostReimportProcessing(proc ProcessedImport, res *ImportResult, fs *
 	if err := fs.WriteFile(res.ContentPath().String(), serialized, os.ModePerm); err != nil {
 		return err
 	}
-	cc.Config.Mesh = &MeshConfig{Submeshes: meshConfigSubmeshes(data.set, materials, cc.Config.Mesh)}
+	cc.Config.Mesh = cc.Config.Mesh.withSubmeshes(meshConfigSubmeshes(data.set, materials, cc.Config.Mesh))
 	if err := WriteConfig(cc.Path, cc.Config, fs); err != nil {
 		return err
 	}
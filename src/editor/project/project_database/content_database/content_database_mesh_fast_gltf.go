@@ -680,20 +680,6 @@ func meshFastGLTFProcessedData(
 	return meshFastEncodeGLB(data.doc, data.bin)
 }
 
-func meshFastGLTFWriteProcessed(
-	data meshFastGLTFPostProcData,
-	path string,
-	fs *project_file_system.FileSystem,
-	imageURIs map[int]string,
-	materials map[string]string,
-) error {
-	out, err := meshFastGLTFProcessedData(data, imageURIs, materials)
-	if err != nil {
-		return err
-	}
-	return fs.WriteFile(path, out, os.ModePerm)
-}
-
 func meshFastGLTFFinalizeImport(
 	data meshFastGLTFPostProcData,
 	res *ImportResult,
@@ -728,11 +714,17 @@ func meshFastGLTFFinalizeImport(
 		data.submeshes[i].Material = materials[data.submeshes[i].Key]
 	}
 	imageURIs := meshFastGLTFTextureImageURIs(data, textureURIs, res, fs, cache, cc.Config.SrcPath)
+	if data.bin, err = meshFastGLTFCompressAnimations(data.doc, data.bin, cc.Config.Mesh.animationCompression()); err != nil {
+		return nil, err
+	}
 	out, err := meshFastGLTFProcessedData(data, imageURIs, materials)
 	if err != nil {
 		return nil, err
 	}
-	cc.Config.Mesh = &MeshConfig{Submeshes: meshFastGLTFConfigSubmeshes(data.submeshes, materials, nil)}
+	if out, err = meshFastGLTFBakeVertexAnimations(data, out, cc.Config.Mesh, res, fs, cache, linkedId); err != nil {
+		return nil, err
+	}
+	cc.Config.Mesh = cc.Config.Mesh.withSubmeshes(meshFastGLTFConfigSubmeshes(data.submeshes, materials, nil))
 	if err := WriteConfig(cc.Path, cc.Config, fs); err != nil {
 		return nil, err
 	}
@@ -791,10 +783,20 @@ func meshFastGLTFPostReimportProcessing(
 		data.submeshes[i].Material = materials[data.submeshes[i].Key]
 	}
 	imageURIs := meshFastGLTFTextureImageURIs(data, textureURIs, res, fs, cache, cc.Config.SrcPath)
-	if err := meshFastGLTFWriteProcessed(data, res.ContentPath().String(), fs, imageURIs, materials); err != nil {
+	if data.bin, err = meshFastGLTFCompressAnimations(data.doc, data.bin, cc.Config.Mesh.animationCompression()); err != nil {
+		return err
+	}
+	out, err := meshFastGLTFProcessedData(data, imageURIs, materials)
+	if err != nil {
+		return err
+	}
+	if out, err = meshFastGLTFBakeVertexAnimations(data, out, cc.Config.Mesh, res, fs, cache, cc.Config.LinkedId); err != nil {
+		return err
+	}
+	if err := fs.WriteFile(res.ContentPath().String(), out, os.ModePerm); err != nil {
 		return err
 	}
-	cc.Config.Mesh = &MeshConfig{Submeshes: meshFastGLTFConfigSubmeshes(data.submeshes, materials, cc.Config.Mesh)}
+	cc.Config.Mesh = cc.Config.Mesh.withSubmeshes(meshFastGLTFConfigSubmeshes(data.submeshes, materials, cc.Config.Mesh))
 	if err := WriteConfig(cc.Path, cc.Config, fs); err != nil {
 		return err
 	}
@@ -0,0 +1,321 @@
+/******************************************************************************/
+/* content_database_mesh_fast_gltf_animation.go                               */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package content_database
+
+import (
+	"encoding/binary"
+	"math"
+	"slices"
+
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering/loaders/kaiju_mesh"
+)
+
+const (
+	fastGLTFComponentShort = 5122
+	fastGLTFComponentFloat = 5126
+)
+
+// meshFastGLTFCompressAnimations reduces the keys of each animation sampler in
+// the document using the compression settings. The compressed keys are written
+// to new buffer views and the buffer views that they replace are dropped when
+// the buffers are compacted, the compacted BIN chunk is returned.
+func meshFastGLTFCompressAnimations(doc map[string]any, bin []byte, c kaiju_mesh.AnimationCompression) ([]byte, error) {
+	animations := meshFastArrayField(doc, "animations")
+	if !c.IsEnabled() || len(animations) == 0 {
+		return bin, nil
+	}
+	type pendingTrack struct {
+		sampler map[string]any
+		track   kaiju_mesh.AnimTrack
+	}
+	buffers := [][]byte{bin, {}}
+	originalViews := meshFastGLTFAccessorViews(doc)
+	// All tracks are read before any are written as samplers may share their
+	// accessors, and the written accessors replace the ones being read
+	pending := []pendingTrack{}
+	for _, animValue := range animations {
+		anim, ok := meshFastMap(animValue)
+		if !ok {
+			continue
+		}
+		paths := map[int]kaiju_mesh.AnimationPathType{}
+		for _, channelValue := range meshFastArrayField(anim, "channels") {
+			channel, ok := meshFastMap(channelValue)
+			if !ok {
+				continue
+			}
+			sampler, ok := meshFastIntField(channel, "sampler")
+			target, _ := meshFastMap(channel["target"])
+			path, _ := meshFastStringField(target, "path")
+			if ok && meshFastGLTFAnimPath(path) != kaiju_mesh.AnimPathInvalid {
+				paths[sampler] = meshFastGLTFAnimPath(path)
+			}
+		}
+		for i, samplerValue := range meshFastArrayField(anim, "samplers") {
+			sampler, ok := meshFastMap(samplerValue)
+			path, animated := paths[i]
+			if !ok || !animated {
+				continue
+			}
+			if track, ok := meshFastGLTFReadTrack(doc, buffers, sampler, path); ok {
+				pending = append(pending, pendingTrack{sampler, track})
+			}
+		}
+	}
+	claimed := map[int]bool{}
+	for i := range pending {
+		p := &pending[i]
+		kaiju_mesh.CompressTrack(&p.track, c)
+		input, _ := meshFastIntField(p.sampler, "input")
+		output, _ := meshFastIntField(p.sampler, "output")
+		p.sampler["input"] = meshFastGLTFWriteTrackTimes(doc, buffers, p.track, input, claimed)
+		p.sampler["output"] = meshFastGLTFWriteTrackValues(doc, buffers, p.track,
+			output, claimed, c.QuantizeRotations)
+	}
+	if len(claimed) == 0 {
+		return bin, nil
+	}
+	skip := meshFastGLTFReplacedViews(doc, originalViews, claimed)
+	return meshFastGLTFCompactBuffers(doc, buffers, skip)
+}
+
+func meshFastGLTFAnimPath(path string) kaiju_mesh.AnimationPathType {
+	switch path {
+	case "translation":
+		return kaiju_mesh.AnimPathTranslation
+	case "rotation":
+		return kaiju_mesh.AnimPathRotation
+	case "scale":
+		return kaiju_mesh.AnimPathScale
+	default:
+		return kaiju_mesh.AnimPathInvalid
+	}
+}
+
+// meshFastGLTFAccessorViews maps each of the accessors to the buffer view that
+// it reads from, before any of the accessors are rewritten
+func meshFastGLTFAccessorViews(doc map[string]any) map[int]int {
+	out := map[int]int{}
+	for i, value := range meshFastArrayField(doc, "accessors") {
+		if accessor, ok := meshFastMap(value); ok {
+			if view, ok := meshFastIntField(accessor, "bufferView"); ok {
+				out[i] = view
+			}
+		}
+	}
+	return out
+}
+
+// meshFastGLTFReplacedViews returns the buffer views that are no longer read
+// by anything, these are the views of the accessors that were rewritten so
+// long as no other accessor, image, or blob still uses the view
+func meshFastGLTFReplacedViews(doc map[string]any, originalViews map[int]int, claimed map[int]bool) map[int]bool {
+	inUse := meshFastGLTFKaijuBVHBufferViews(doc)
+	if inUse == nil {
+		inUse = map[int]bool{}
+	}
+	for _, value := range meshFastArrayField(doc, "images") {
+		if image, ok := meshFastMap(value); ok {
+			if view, ok := meshFastIntField(image, "bufferView"); ok {
+				inUse[view] = true
+			}
+		}
+	}
+	for accessor, view := range originalViews {
+		if !claimed[accessor] {
+			inUse[view] = true
+		}
+	}
+	skip := map[int]bool{}
+	for accessor := range claimed {
+		if view := originalViews[accessor]; !inUse[view] {
+			skip[view] = true
+		}
+	}
+	return skip
+}
+
+func meshFastGLTFReadTrack(doc map[string]any, buffers [][]byte, sampler map[string]any, path kaiju_mesh.AnimationPathType) (kaiju_mesh.AnimTrack, bool) {
+	interpolation, _ := meshFastStringField(sampler, "interpolation")
+	track := kaiju_mesh.AnimTrack{PathType: path}
+	switch interpolation {
+	case "", "LINEAR":
+		track.Interpolation = kaiju_mesh.AnimInterpolateLinear
+	case "STEP":
+		track.Interpolation = kaiju_mesh.AnimInterpolateStep
+	default:
+		return track, false
+	}
+	input, ok := meshFastIntField(sampler, "input")
+	if !ok {
+		return track, false
+	}
+	output, ok := meshFastIntField(sampler, "output")
+	if !ok {
+		return track, false
+	}
+	times, ok := meshFastGLTFReadAccessor(doc, buffers, input, 1)
+	if !ok {
+		return track, false
+	}
+	components := 3
+	if path == kaiju_mesh.AnimPathRotation {
+		components = 4
+	}
+	values, ok := meshFastGLTFReadAccessor(doc, buffers, output, components)
+	if !ok || len(values) != len(times) {
+		return track, false
+	}
+	track.Times = make([]float32, len(times))
+	track.Values = make([][4]matrix.Float, len(values))
+	for i := range times {
+		track.Times[i] = float32(times[i][0])
+		if path == kaiju_mesh.AnimPathRotation {
+			track.Values[i] = matrix.QuaternionFromXYZW(values[i])
+		} else {
+			track.Values[i] = values[i]
+		}
+	}
+	return track, true
+}
+
+// meshFastGLTFReadAccessor reads the float (or normalized short) elements of a
+// non-sparse accessor
+func meshFastGLTFReadAccessor(doc map[string]any, buffers [][]byte, index, components int) ([][4]matrix.Float, bool) {
+	accessors := meshFastArrayField(doc, "accessors")
+	if index < 0 || index >= len(accessors) {
+		return nil, false
+	}
+	accessor, ok := meshFastMap(accessors[index])
+	if !ok || accessor["sparse"] != nil {
+		return nil, false
+	}
+	componentType, _ := meshFastIntField(accessor, "componentType")
+	normalized, _ := accessor["normalized"].(bool)
+	componentSize := 4
+	switch {
+	case componentType == fastGLTFComponentFloat:
+	case componentType == fastGLTFComponentShort && normalized:
+		componentSize = 2
+	default:
+		return nil, false
+	}
+	viewIndex, ok := meshFastIntField(accessor, "bufferView")
+	if !ok {
+		return nil, false
+	}
+	data, err := meshFastGLTFBufferViewBytes(doc, buffers, viewIndex)
+	if err != nil {
+		return nil, false
+	}
+	view, _ := meshFastMap(meshFastArrayField(doc, "bufferViews")[viewIndex])
+	stride, _ := meshFastIntField(view, "byteStride")
+	if stride == 0 {
+		stride = componentSize * components
+	}
+	offset, _ := meshFastIntField(accessor, "byteOffset")
+	count, _ := meshFastIntField(accessor, "count")
+	if count <= 0 || offset+(count-1)*stride+componentSize*components > len(data) {
+		return nil, false
+	}
+	out := make([][4]matrix.Float, count)
+	for i := range out {
+		at := offset + i*stride
+		for c := range components {
+			b := data[at+c*componentSize:]
+			if componentSize == 4 {
+				out[i][c] = matrix.Float(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+			} else {
+				v := matrix.Float(int16(binary.LittleEndian.Uint16(b))) / math.MaxInt16
+				out[i][c] = max(v, -1)
+			}
+		}
+	}
+	return out, true
+}
+
+func meshFastGLTFWriteTrackTimes(doc map[string]any, buffers [][]byte, track kaiju_mesh.AnimTrack, accessor int, claimed map[int]bool) int {
+	data := make([]byte, 0, len(track.Times)*4)
+	for _, t := range track.Times {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(t))
+	}
+	return meshFastGLTFWriteAccessor(doc, buffers, accessor, claimed, map[string]any{
+		"componentType": fastGLTFComponentFloat,
+		"count":         len(track.Times),
+		"type":          "SCALAR",
+		"min":           []any{slices.Min(track.Times)},
+		"max":           []any{slices.Max(track.Times)},
+	}, data)
+}
+
+func meshFastGLTFWriteTrackValues(doc map[string]any, buffers [][]byte, track kaiju_mesh.AnimTrack, accessor int, claimed map[int]bool, quantize bool) int {
+	fields := map[string]any{
+		"componentType": fastGLTFComponentFloat,
+		"count":         len(track.Values),
+		"type":          "VEC3",
+	}
+	data := make([]byte, 0, len(track.Values)*16)
+	for _, v := range track.Values {
+		if track.PathType != kaiju_mesh.AnimPathRotation {
+			for c := range 3 {
+				data = binary.LittleEndian.AppendUint32(data, math.Float32bits(float32(v[c])))
+			}
+			continue
+		}
+		q := matrix.Quaternion(v)
+		xyzw := [4]matrix.Float{q.X(), q.Y(), q.Z(), q.W()}
+		for c := range xyzw {
+			if quantize {
+				data = binary.LittleEndian.AppendUint16(data,
+					uint16(kaiju_mesh.QuantizeNormalizedInt16(xyzw[c])))
+			} else {
+				data = binary.LittleEndian.AppendUint32(data, math.Float32bits(float32(xyzw[c])))
+			}
+		}
+	}
+	if track.PathType == kaiju_mesh.AnimPathRotation {
+		fields["type"] = "VEC4"
+		if quantize {
+			fields["componentType"] = fastGLTFComponentShort
+			fields["normalized"] = true
+		}
+	}
+	return meshFastGLTFWriteAccessor(doc, buffers, accessor, claimed, fields, data)
+}
+
+// meshFastGLTFWriteAccessor appends the data to the extra buffer and points an
+// accessor at it. The original accessor is rewritten in place the first time
+// that it is written, this keeps the accessor indexes stable and avoids leaving
+// behind orphaned accessors. Accessors that are shared (such as a sampler input
+// used by multiple samplers) get a new accessor for each of the other users.
+func meshFastGLTFWriteAccessor(doc map[string]any, buffers [][]byte, index int, claimed map[int]bool, fields map[string]any, data []byte) int {
+	buffers[1] = append(buffers[1], make([]byte, meshFastAlign4(len(buffers[1]))-len(buffers[1]))...)
+	views := meshFastArrayField(doc, "bufferViews")
+	doc["bufferViews"] = append(views, map[string]any{
+		"buffer":     1,
+		"byteOffset": len(buffers[1]),
+		"byteLength": len(data),
+	})
+	buffers[1] = append(buffers[1], data...)
+	fields["bufferView"] = len(views)
+	accessors := meshFastArrayField(doc, "accessors")
+	if !claimed[index] && index >= 0 && index < len(accessors) {
+		if accessor, ok := meshFastMap(accessors[index]); ok {
+			claimed[index] = true
+			for _, key := range []string{"byteOffset", "normalized", "min", "max"} {
+				delete(accessor, key)
+			}
+			for k, v := range fields {
+				accessor[k] = v
+			}
+			return index
+		}
+	}
+	doc["accessors"] = append(accessors, fields)
+	return len(accessors)
+}
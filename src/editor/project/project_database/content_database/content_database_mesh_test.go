@@ -483,6 +483,73 @@ func TestMeshReimportFastGLTFPreservesSubmeshKeysAndMaterials(t *testing.T) {
 	}
 }
 
+func TestMeshFastGLTFCompressAnimationsReducesLinearKeys(t *testing.T) {
+	const keys = 11
+	positions := testF32Bytes(0, 0, 0)
+	times := make([]float32, keys)
+	values := make([]float32, 0, keys*3)
+	for i := range keys {
+		times[i] = float32(i) / (keys - 1)
+		values = append(values, times[i]*4, 1, 0)
+	}
+	bin := slices.Concat(positions, testF32Bytes(times...), testF32Bytes(values...))
+	doc, err := meshFastDecodeJSON([]byte(`{
+		"buffers": [{"byteLength": ` + strconv.Itoa(len(bin)) + `}],
+		"bufferViews": [
+			{"buffer": 0, "byteOffset": 0, "byteLength": 12},
+			{"buffer": 0, "byteOffset": 12, "byteLength": 44},
+			{"buffer": 0, "byteOffset": 56, "byteLength": 132}
+		],
+		"accessors": [
+			{"bufferView": 0, "componentType": 5126, "count": 1, "type": "VEC3"},
+			{"bufferView": 1, "componentType": 5126, "count": 11, "type": "SCALAR", "min": [0], "max": [1]},
+			{"bufferView": 2, "componentType": 5126, "count": 11, "type": "VEC3"}
+		],
+		"nodes": [{"name": "root"}],
+		"animations": [{
+			"name": "slide",
+			"samplers": [{"input": 1, "output": 2, "interpolation": "LINEAR"}],
+			"channels": [{"sampler": 0, "target": {"node": 0, "path": "translation"}}]
+		}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := meshFastGLTFCompressAnimations(doc, bin, kaiju_mesh.DefaultAnimationCompression())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) >= len(bin) {
+		t.Fatalf("compressed BIN = %d bytes, want less than %d", len(out), len(bin))
+	}
+	accessors := meshFastArrayField(doc, "accessors")
+	if len(accessors) != 3 {
+		t.Fatalf("accessors = %d, want the 3 accessors rewritten in place", len(accessors))
+	}
+	readVec := func(accessorIndex, components int) []float32 {
+		accessor, _ := meshFastMap(accessors[accessorIndex])
+		count, _ := meshFastIntField(accessor, "count")
+		viewIndex, _ := meshFastIntField(accessor, "bufferView")
+		view, _ := meshFastMap(meshFastArrayField(doc, "bufferViews")[viewIndex])
+		offset, _ := meshFastIntField(view, "byteOffset")
+		out := make([]float32, count*components)
+		for i := range out {
+			out[i] = math.Float32frombits(binary.LittleEndian.Uint32(bin[offset+i*4:]))
+		}
+		return out
+	}
+	bin = out
+	if got := readVec(0, 3); !slices.Equal(got, []float32{0, 0, 0}) {
+		t.Fatalf("untouched accessor = %v, want [0 0 0]", got)
+	}
+	if got := readVec(1, 1); !slices.Equal(got, []float32{0, 1}) {
+		t.Fatalf("times = %v, want [0 1]", got)
+	}
+	if got := readVec(2, 3); !slices.Equal(got, []float32{0, 1, 0, 4, 1, 0}) {
+		t.Fatalf("translations = %v, want [0 1 0 4 1 0]", got)
+	}
+}
+
 func newMockMeshImportFileSystem(t *testing.T) (*project_file_system.FileSystem, string) {
 	t.Helper()
 	pfs, err := project_file_system.New(t.TempDir())
@@ -0,0 +1,169 @@
+/******************************************************************************/
+/* content_database_mesh_vertex_animation.go                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package content_database
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"kaijuengine.com/editor/project/project_file_system"
+	"kaijuengine.com/rendering/loaders/kaiju_mesh"
+)
+
+func meshCompressSetAnimations(set *kaiju_mesh.KaijuMeshSet, cfg *MeshConfig) {
+	c := cfg.animationCompression()
+	if !c.IsEnabled() {
+		return
+	}
+	for i := range set.Meshes {
+		for j := range set.Meshes[i].Animations {
+			set.Meshes[i].Animations[j] = kaiju_mesh.CompressAnimation(
+				set.Meshes[i].Animations[j], c)
+		}
+	}
+}
+
+// meshBakeVertexAnimations bakes the animations of all the skinned meshes in
+// the set into vertex animation textures. When the mesh has been imported
+// before, the textures that were baked for it previously are overwritten so
+// that their ids remain stable across reimports.
+func meshBakeVertexAnimations(
+	set *kaiju_mesh.KaijuMeshSet,
+	cfg *MeshConfig,
+	res *ImportResult,
+	fs *project_file_system.FileSystem,
+	cache *Cache,
+	linkedId string,
+) error {
+	for i := range set.Meshes {
+		set.Meshes[i].VertexAnimations = nil
+	}
+	if cfg == nil || !cfg.BakeVertexAnimation {
+		return nil
+	}
+	previous := meshPreviousVertexAnimationTextures(res, fs)
+	dir, err := os.MkdirTemp("", "kaiju-vat-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	for i := range set.Meshes {
+		mesh := &set.Meshes[i]
+		if len(mesh.Joints) == 0 {
+			continue
+		}
+		for j := range mesh.Animations {
+			va, img, err := kaiju_mesh.BakeVertexAnimation(mesh,
+				&mesh.Animations[j], cfg.vertexAnimationFrameRate())
+			if err != nil {
+				return err
+			}
+			buff := bytes.Buffer{}
+			if err := png.Encode(&buff, img); err != nil {
+				return err
+			}
+			key := meshVertexAnimationKey(mesh.Key, va.Animation)
+			if id, ok := previous[key]; ok {
+				texRes := ImportResult{Id: id, Category: Texture{}}
+				if err := fs.WriteFile(texRes.ContentPath().String(), buff.Bytes(), os.ModePerm); err != nil {
+					return err
+				}
+				va.Texture = id
+			} else {
+				name := fmt.Sprintf("%s_%s_vat.png", mesh.Name, va.Animation)
+				path := filepath.Join(dir, name)
+				if err := os.WriteFile(path, buff.Bytes(), os.ModePerm); err != nil {
+					return err
+				}
+				texRes, err := Import(path, fs, cache, linkedId)
+				if err != nil {
+					return err
+				}
+				res.Dependencies = append(res.Dependencies, texRes[0])
+				va.Texture = texRes[0].Id
+			}
+			mesh.VertexAnimations = append(mesh.VertexAnimations, va)
+		}
+	}
+	return nil
+}
+
+// meshFastGLTFBakeVertexAnimations bakes the vertex animations for the meshes
+// in the processed GLB and writes their descriptions into the kaiju extras of
+// the document, the re-encoded GLB is returned.
+func meshFastGLTFBakeVertexAnimations(
+	data meshFastGLTFPostProcData,
+	processed []byte,
+	cfg *MeshConfig,
+	res *ImportResult,
+	fs *project_file_system.FileSystem,
+	cache *Cache,
+	linkedId string,
+) ([]byte, error) {
+	if cfg == nil || !cfg.BakeVertexAnimation {
+		return processed, nil
+	}
+	set, err := kaiju_mesh.DeserializeSet(processed)
+	if err != nil {
+		return nil, err
+	}
+	if err := meshBakeVertexAnimations(&set, cfg, res, fs, cache, linkedId); err != nil {
+		return nil, err
+	}
+	byKey := make(map[string][]kaiju_mesh.VertexAnimation, len(set.Meshes))
+	for i := range set.Meshes {
+		if len(set.Meshes[i].VertexAnimations) > 0 {
+			byKey[set.Meshes[i].Key] = set.Meshes[i].VertexAnimations
+		}
+	}
+	extras, _ := meshFastMap(data.doc["extras"])
+	kaiju, _ := meshFastMap(extras["kaiju"])
+	for _, value := range meshFastArrayField(kaiju, "meshes") {
+		extra, ok := meshFastMap(value)
+		if !ok {
+			continue
+		}
+		key, _ := meshFastStringField(extra, "key")
+		if anims, ok := byKey[key]; ok {
+			extra["vertexAnimations"] = anims
+		} else {
+			delete(extra, "vertexAnimations")
+		}
+	}
+	return meshFastEncodeGLB(data.doc, data.bin)
+}
+
+// meshPreviousVertexAnimationTextures reads the currently imported mesh (if
+// any) to find the textures that were baked for its vertex animations
+func meshPreviousVertexAnimationTextures(res *ImportResult, fs *project_file_system.FileSystem) map[string]string {
+	out := map[string]string{}
+	data, err := fs.ReadFile(res.ContentPath().String())
+	if err != nil {
+		return out
+	}
+	set, err := kaiju_mesh.DeserializeSet(data)
+	if err != nil {
+		slog.Warn("failed to read the previous mesh for vertex animations", "id", res.Id, "error", err)
+		return out
+	}
+	for i := range set.Meshes {
+		for _, va := range set.Meshes[i].VertexAnimations {
+			if va.Texture != "" {
+				out[meshVertexAnimationKey(set.Meshes[i].Key, va.Animation)] = va.Texture
+			}
+		}
+	}
+	return out
+}
+
+func meshVertexAnimationKey(meshKey, animation string) string {
+	return meshKey + "/" + animation
+}
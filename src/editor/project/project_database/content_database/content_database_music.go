@@ -14,9 +14,15 @@ import (
 func init() { addCategory(Music{}) }
 
 // Music is a [ContentCategory] represented by a file with a ".mp3" or ".ogg"
-// extension. Music is as it sounds.
+// extension. Music is as it sounds. See [AudioImportSettings] for how music
+// is processed when it is imported.
 type Music struct{}
-type MusicConfig struct{}
+type MusicConfig struct {
+	AudioImportSettings
+
+	// Imported is written by the importer, see [AudioImportInfo]
+	Imported *AudioImportInfo `json:",omitempty"`
+}
 
 // See the documentation for the interface [ContentCategory] to learn more about
 // the following functions
@@ -27,14 +33,42 @@ func (Music) ExtNames() []string { return []string{".mp3", ".ogg"} }
 
 func (Music) Import(src string, _ *project_file_system.FileSystem) (ProcessedImport, error) {
 	defer tracing.NewRegion("Music.Import").End()
-	return pathToBinaryData(src)
+	return importAudio(src, AudioImportSettings{})
 }
 
-func (c Music) Reimport(id string, cache *Cache, fs *project_file_system.FileSystem) (ProcessedImport, error) {
+func (Music) Reimport(id string, cache *Cache, fs *project_file_system.FileSystem) (ProcessedImport, error) {
 	defer tracing.NewRegion("Music.Reimport").End()
-	return reimportByNameMatching(c, id, cache, fs)
+	path, err := contentIdToSrcPath(id, cache, fs)
+	if err != nil {
+		return ProcessedImport{}, err
+	}
+	cc, err := cache.Read(id)
+	if err != nil {
+		return ProcessedImport{}, err
+	}
+	return importAudio(path, cc.Config.Music.importSettings())
 }
 
 func (Music) PostImportProcessing(proc ProcessedImport, res *ImportResult, fs *project_file_system.FileSystem, cache *Cache, linkedId string) error {
-	return nil
+	defer tracing.NewRegion("Music.PostImportProcessing").End()
+	return updateAudioImportInfo(proc, res, fs, cache, musicImportInfo)
+}
+
+func (Music) PostReimportProcessing(proc ProcessedImport, res *ImportResult, fs *project_file_system.FileSystem, cache *Cache) error {
+	defer tracing.NewRegion("Music.PostReimportProcessing").End()
+	return updateAudioImportInfo(proc, res, fs, cache, musicImportInfo)
+}
+
+func (c *MusicConfig) importSettings() AudioImportSettings {
+	if c == nil {
+		return AudioImportSettings{}
+	}
+	return c.AudioImportSettings
+}
+
+func musicImportInfo(cfg *ContentConfig) **AudioImportInfo {
+	if cfg.Music == nil {
+		cfg.Music = &MusicConfig{}
+	}
+	return &cfg.Music.Imported
 }
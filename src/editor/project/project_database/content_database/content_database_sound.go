@@ -14,9 +14,15 @@ import (
 func init() { addCategory(Sound{}) }
 
 // Sound is a [ContentCategory] represented by a file with a ".wav" extension.
-// Sound is as it sounds.
+// Sound is as it sounds. See [AudioImportSettings] for how sounds are
+// processed when they are imported.
 type Sound struct{}
-type SoundConfig struct{}
+type SoundConfig struct {
+	AudioImportSettings
+
+	// Imported is written by the importer, see [AudioImportInfo]
+	Imported *AudioImportInfo `json:",omitempty"`
+}
 
 // See the documentation for the interface [ContentCategory] to learn more about
 // the following functions
@@ -27,14 +33,42 @@ func (Sound) ExtNames() []string { return []string{".wav"} }
 
 func (Sound) Import(src string, _ *project_file_system.FileSystem) (ProcessedImport, error) {
 	defer tracing.NewRegion("Sound.Import").End()
-	return pathToBinaryData(src)
+	return importAudio(src, AudioImportSettings{})
 }
 
-func (c Sound) Reimport(id string, cache *Cache, fs *project_file_system.FileSystem) (ProcessedImport, error) {
+func (Sound) Reimport(id string, cache *Cache, fs *project_file_system.FileSystem) (ProcessedImport, error) {
 	defer tracing.NewRegion("Sound.Reimport").End()
-	return reimportByNameMatching(c, id, cache, fs)
+	path, err := contentIdToSrcPath(id, cache, fs)
+	if err != nil {
+		return ProcessedImport{}, err
+	}
+	cc, err := cache.Read(id)
+	if err != nil {
+		return ProcessedImport{}, err
+	}
+	return importAudio(path, cc.Config.Sound.importSettings())
 }
 
 func (Sound) PostImportProcessing(proc ProcessedImport, res *ImportResult, fs *project_file_system.FileSystem, cache *Cache, linkedId string) error {
-	return nil
+	defer tracing.NewRegion("Sound.PostImportProcessing").End()
+	return updateAudioImportInfo(proc, res, fs, cache, soundImportInfo)
+}
+
+func (Sound) PostReimportProcessing(proc ProcessedImport, res *ImportResult, fs *project_file_system.FileSystem, cache *Cache) error {
+	defer tracing.NewRegion("Sound.PostReimportProcessing").End()
+	return updateAudioImportInfo(proc, res, fs, cache, soundImportInfo)
+}
+
+func (c *SoundConfig) importSettings() AudioImportSettings {
+	if c == nil {
+		return AudioImportSettings{}
+	}
+	return c.AudioImportSettings
+}
+
+func soundImportInfo(cfg *ContentConfig) **AudioImportInfo {
+	if cfg.Sound == nil {
+		cfg.Sound = &SoundConfig{}
+	}
+	return &cfg.Sound.Imported
 }
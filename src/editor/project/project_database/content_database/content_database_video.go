@@ -0,0 +1,40 @@
+/******************************************************************************/
+/* content_database_video.go                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package content_database
+
+import (
+	"kaijuengine.com/editor/project/project_file_system"
+	"kaijuengine.com/platform/profiler/tracing"
+)
+
+func init() { addCategory(Video{}) }
+
+// Video is a [ContentCategory] represented by a file with a ".kvid"
+// extension. It is a video (JPEG frames and an optional WAV audio track)
+// that is played by the <video> UI element or streamed into a texture.
+type Video struct{}
+
+// See the documentation for the interface [ContentCategory] to learn more about
+// the following functions
+
+func (Video) Path() string       { return project_file_system.ContentVideoFolder }
+func (Video) TypeName() string   { return "Video" }
+func (Video) ExtNames() []string { return []string{".kvid"} }
+
+func (Video) Import(src string, _ *project_file_system.FileSystem) (ProcessedImport, error) {
+	defer tracing.NewRegion("Video.Import").End()
+	return pathToBinaryData(src)
+}
+
+func (v Video) Reimport(id string, cache *Cache, fs *project_file_system.FileSystem) (ProcessedImport, error) {
+	defer tracing.NewRegion("Video.Reimport").End()
+	return reimportByNameMatching(v, id, cache, fs)
+}
+
+func (Video) PostImportProcessing(proc ProcessedImport, res *ImportResult, fs *project_file_system.FileSystem, cache *Cache, linkedId string) error {
+	return nil
+}
@@ -44,9 +44,11 @@ var (
 		ContentSoundFolder,
 		ContentFontFolder,
 		ContentMeshFolder,
+		ContentBoneMapFolder,
 		ContentUiFolder,
 		ContentHtmlFolder,
 		ContentCssFolder,
+		ContentComponentFolder,
 		ContentTableFolder,
 		ContentTableOfContentsFolder,
 		ContentRenderFolder,
@@ -61,6 +63,7 @@ var (
 		ContentTemplateFolder,
 		ContentTerrainFolder,
 		ContentTextureFolder,
+		ContentVideoFolder,
 	}
 	coreRequiredFolders = []string{
 		DatabaseFolder,
@@ -43,6 +43,7 @@ const (
 	ContentSoundFolder           = ContentAudioFolder + "/sound"
 	ContentFontFolder            = "font"
 	ContentMeshFolder            = "mesh"
+	ContentBoneMapFolder         = ContentMeshFolder + "/bonemap"
 	ContentRenderFolder          = "render"
 	ContentRenderGraphFolder     = ContentRenderFolder + "/graph"
 	ContentMaterialFolder        = ContentRenderFolder + "/material"
@@ -60,6 +61,8 @@ const (
 	ContentUiFolder              = "ui"
 	ContentHtmlFolder            = ContentUiFolder + "/html"
 	ContentCssFolder             = ContentUiFolder + "/css"
+	ContentComponentFolder       = ContentUiFolder + "/component"
+	ContentVideoFolder           = "video"
 )
 
 const (
@@ -89,6 +92,10 @@ func HtmlPath(id string) ContentPath {
 	return AsContentPath(filepath.Join(ContentFolder, ContentHtmlFolder, id))
 }
 
+func ComponentPath(id string) ContentPath {
+	return AsContentPath(filepath.Join(ContentFolder, ContentComponentFolder, id))
+}
+
 func StagePath(id string) ContentPath {
 	return AsContentPath(filepath.Join(ContentFolder, ContentStageFolder, id))
 }
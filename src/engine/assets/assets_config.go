@@ -34,6 +34,7 @@ const (
 	MaterialDefinitionComposite           = "composite.material"
 	MaterialDefinitionUI                  = "ui.material"
 	MaterialDefinitionUITransparent       = "ui_transparent.material"
+	MaterialDefinitionUIShadow            = "ui_shadow.material"
 	MaterialDefinitionSprite              = "sprite.material"
 	MaterialDefinitionSpriteTransparent   = "sprite_transparent.material"
 	MaterialDefinitionLightDepth          = "light_depth.material"
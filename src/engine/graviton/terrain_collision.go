@@ -283,10 +283,13 @@ func (c *TerrainCollision) raycastLocal(ray Ray, length matrix.Float) (matrix.Ve
 	if lastPoint.Y() <= c.HeightAtLocal(lastPoint.XZ()) {
 		return lastPoint, true
 	}
-	for distance := entry + step; distance <= exit+matrix.Tiny; distance += step {
-		point := ray.Point(float32(matrix.Min(distance, exit)))
+	// Rays shorter than a step (such as ground probes) must still sample
+	// their end point, so the last step is clamped to the exit distance
+	for lastDistance < exit {
+		distance := matrix.Min(lastDistance+step, exit)
+		point := ray.Point(float32(distance))
 		if point.Y() <= c.HeightAtLocal(point.XZ()) {
-			return c.refineRayHit(ray, lastDistance, matrix.Min(distance, exit)), true
+			return c.refineRayHit(ray, lastDistance, distance), true
 		}
 		lastDistance = distance
 	}
@@ -130,6 +130,24 @@ func TestTerrainCollisionRaycastRespectsLength(t *testing.T) {
 	}
 }
 
+func TestTerrainCollisionRaycastShorterThanCellHits(t *testing.T) {
+	collision := testTerrainCollision(t, 2, matrix.NewVec2(20, 20), []matrix.Float{
+		0.2, 0.2,
+		0.2, 0.2,
+	}, -1, 1)
+	ray := Ray{
+		Origin:    matrix.NewVec3(0, 0.5, 0),
+		Direction: matrix.Vec3Down(),
+	}
+	hit, ok := collision.Raycast(ray, 1, nil)
+	if !ok {
+		t.Fatal("expected a ray shorter than a terrain cell to hit terrain")
+	}
+	if !matrix.ApproxTo(hit.Point.Y(), 0.2, 0.001) {
+		t.Fatalf("expected terrain hit at height 0.2, got %v", hit.Point)
+	}
+}
+
 func TestSystemRaycastHitsStaticTerrain(t *testing.T) {
 	system := System{}
 	system.Initialize()
@@ -257,6 +257,9 @@ func (host *Host) InitializeRenderer() error {
 
 func (host *Host) InitializeAudio() (err error) {
 	host.audio, err = audio.New()
+	if err == nil {
+		host.audio.FollowCameraFunc(host.PrimaryCamera)
+	}
 	return err
 }
 
@@ -436,6 +439,9 @@ func (host *Host) Update(deltaTime float64) {
 	}
 	host.LateUpdater.Update(deltaTime)
 	host.collisionManager.Update(deltaTime)
+	if host.audio != nil {
+		host.audio.Update3D(deltaTime)
+	}
 	if host.Window.IsClosed() || host.Window.IsCrashed() {
 		host.Closing = true
 	}
@@ -72,79 +72,86 @@ func DoTweenExt(val *float32, target float32, time float64, easing Easing,
 		scale:       (target - *val) / max(float32(time), 0.00001),
 		totalUpdate: 0,
 	}
+	tween.easing = EasingFunc(easing)
+	// Stop the tweener for the same value if one exists
+	Stop(val, true, false)
+	tweens = append(tweens, tween)
+}
+
+// EasingFunc returns the function for the easing, which maps a linear time in
+// the range [0, 1] to the eased time
+func EasingFunc(easing Easing) func(t float32) float32 {
 	switch easing {
 	case EasingLinear:
-		tween.easing = easeLinear
+		return easeLinear
 	case EasingIn:
-		tween.easing = easeInQuad
+		return easeInQuad
 	case EasingOut:
-		tween.easing = easeOutQuad
+		return easeOutQuad
 	case EasingInAndOut:
-		tween.easing = easeInOutQuad
+		return easeInOutQuad
 	case EasingInSine:
-		tween.easing = easeInSine
+		return easeInSine
 	case EasingOutSine:
-		tween.easing = easeOutSine
+		return easeOutSine
 	case EasingInAndOutSine:
-		tween.easing = easeInOutSine
+		return easeInOutSine
 	case EasingInQuad:
-		tween.easing = easeInQuad
+		return easeInQuad
 	case EasingOutQuad:
-		tween.easing = easeOutQuad
+		return easeOutQuad
 	case EasingInAndOutQuad:
-		tween.easing = easeInOutQuad
+		return easeInOutQuad
 	case EasingInCubic:
-		tween.easing = easeInCubic
+		return easeInCubic
 	case EasingOutCubic:
-		tween.easing = easeOutCubic
+		return easeOutCubic
 	case EasingInAndOutCubic:
-		tween.easing = easeInOutCubic
+		return easeInOutCubic
 	case EasingInQuart:
-		tween.easing = easeInQuart
+		return easeInQuart
 	case EasingOutQuart:
-		tween.easing = easeOutQuart
+		return easeOutQuart
 	case EasingInAndOutQuart:
-		tween.easing = easeInOutQuart
+		return easeInOutQuart
 	case EasingInQuint:
-		tween.easing = easeInQuint
+		return easeInQuint
 	case EasingOutQuint:
-		tween.easing = easeOutQuint
+		return easeOutQuint
 	case EasingInAndOutQuint:
-		tween.easing = easeInOutQuint
+		return easeInOutQuint
 	case EasingInExpo:
-		tween.easing = easeInExpo
+		return easeInExpo
 	case EasingOutExpo:
-		tween.easing = easeOutExpo
+		return easeOutExpo
 	case EasingInAndOutExpo:
-		tween.easing = easeInOutExpo
+		return easeInOutExpo
 	case EasingInCirc:
-		tween.easing = easeInCirc
+		return easeInCirc
 	case EasingOutCirc:
-		tween.easing = easeOutCirc
+		return easeOutCirc
 	case EasingInAndOutCirc:
-		tween.easing = easeInOutCirc
+		return easeInOutCirc
 	case EasingInBack:
-		tween.easing = easeInBack
+		return easeInBack
 	case EasingOutBack:
-		tween.easing = easeOutBack
+		return easeOutBack
 	case EasingInAndOutBack:
-		tween.easing = easeInOutBack
+		return easeInOutBack
 	case EasingInElastic:
-		tween.easing = easeInElastic
+		return easeInElastic
 	case EasingOutElastic:
-		tween.easing = easeOutElastic
+		return easeOutElastic
 	case EasingInAndOutElastic:
-		tween.easing = easeInOutElastic
+		return easeInOutElastic
 	case EasingInBounce:
-		tween.easing = easeInBounce
+		return easeInBounce
 	case EasingOutBounce:
-		tween.easing = easeOutBounce
+		return easeOutBounce
 	case EasingInAndOutBounce:
-		tween.easing = easeInOutBounce
+		return easeInOutBounce
 	}
-	// Stop the tweener for the same value if one exists
-	Stop(val, true, false)
-	tweens = append(tweens, tween)
+	return easeLinear
 }
 
 func Update(deltaTime float64) {
@@ -0,0 +1,151 @@
+/******************************************************************************/
+/* canvas.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"fmt"
+	"image"
+	"log/slog"
+
+	"kaijuengine.com/engine/ui/canvas"
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/platform/profiler/tracing"
+	"kaijuengine.com/rendering"
+)
+
+// Canvas is a panel showing a texture that is drawn to through the 2D API
+// of its Context. Drawing happens on the CPU and the parts of the texture
+// that changed are uploaded when the canvas updates
+type Canvas Panel
+
+type canvasData struct {
+	panelData
+	context    *canvas.Context
+	texture    *rendering.Texture
+	textureKey string
+}
+
+func (c *canvasData) innerPanelData() *panelData { return &c.panelData }
+
+func (u *UI) ToCanvas() *Canvas { return (*Canvas)(u) }
+func (c *Canvas) Base() *UI     { return (*UI)(c) }
+
+func (c *Canvas) CanvasData() *canvasData {
+	return c.elmData.(*canvasData)
+}
+
+// Init creates the canvas with a texture of width by height pixels, which is
+// also the size of the element unless it is styled otherwise
+func (c *Canvas) Init(width, height int) {
+	data := &canvasData{context: canvas.New(width, height)}
+	c.elmData = data
+	host := c.man.Value().Host
+	data.context.SetTextRenderer(canvasText{host.FontCache()})
+	p := c.Base().ToPanel()
+	p.Init(c.createTexture(), ElementTypeCanvas)
+	if p.shaderData != nil {
+		p.shaderData.BorderLen = matrix.Vec2Zero()
+	}
+	c.Base().AddEvent(EventTypeDestroy, func() {
+		if data.textureKey != "" {
+			host.TextureCache().ForceRemoveTexture(data.textureKey, rendering.TextureFilterLinear)
+		}
+	})
+}
+
+// Context is what is used to draw on the canvas
+func (c *Canvas) Context() *canvas.Context { return c.CanvasData().context }
+
+// Resize clears the canvas and changes the size of its texture, the same as
+// setting the width and height of an HTML canvas
+func (c *Canvas) Resize(width, height int) {
+	data := c.CanvasData()
+	if width == data.context.Width() && height == data.context.Height() {
+		return
+	}
+	data.context.Resize(width, height)
+	if tex := c.createTexture(); tex != nil {
+		(*Panel)(c).SetBackground(tex)
+	}
+}
+
+// LoadImage reads a PNG from the asset database to be drawn on the canvas
+func (c *Canvas) LoadImage(key string) (*canvas.Image, error) {
+	mem, err := c.man.Value().Host.AssetDatabase().Read(key)
+	if err != nil {
+		return nil, err
+	}
+	data := rendering.ReadRawTextureData(mem, rendering.TextureFileFormatPng)
+	if data.Width <= 0 || data.Height <= 0 || len(data.Mem) < data.Width*data.Height*4 {
+		return nil, fmt.Errorf("failed to read the image %s", key)
+	}
+	return &canvas.Image{Width: data.Width, Height: data.Height, Pixels: data.Mem}, nil
+}
+
+func (c *Canvas) createTexture() *rendering.Texture {
+	data := c.CanvasData()
+	cache := c.man.Value().Host.TextureCache()
+	if data.textureKey != "" {
+		cache.ForceRemoveTexture(data.textureKey, rendering.TextureFilterLinear)
+	}
+	w, h := max(data.context.Width(), 1), max(data.context.Height(), 1)
+	pixels := make([]byte, w*h*4)
+	copy(pixels, data.context.Pixels())
+	data.textureKey = fmt.Sprintf("canvas_%p_%dx%d", data, w, h)
+	tex, err := cache.InsertRawTexture(data.textureKey, pixels, w, h, rendering.TextureFilterLinear)
+	if err != nil {
+		slog.Error("failed to create the canvas texture", "error", err)
+		data.textureKey = ""
+		return nil
+	}
+	data.texture = tex
+	// The texture starts out with everything drawn so far
+	data.context.ClearDirty()
+	return tex
+}
+
+func (c *Canvas) update(deltaTime float64) {
+	defer tracing.NewRegion("Canvas.update").End()
+	(*Panel)(c).update(deltaTime)
+	c.upload()
+}
+
+// upload writes the part of the canvas that was drawn to since the last
+// upload into the texture, once the texture is on the GPU
+func (c *Canvas) upload() {
+	data := c.CanvasData()
+	tex := data.texture
+	if !data.context.IsDirty() || tex == nil || !tex.RenderId.IsValid() {
+		return
+	}
+	request := rendering.GPUImageWriteRequest{
+		Region: data.context.DirtyRegion(),
+		Pixels: data.context.DirtyPixels(),
+	}
+	data.context.ClearDirty()
+	c.man.Value().Host.RunOnRenderThread(func(device *rendering.GPUDevice) {
+		tex.WritePixels(device, []rendering.GPUImageWriteRequest{request})
+	})
+}
+
+// canvasText draws the text of canvases with the fonts of the host
+type canvasText struct{ fonts *rendering.FontCache }
+
+func (t canvasText) face(name string) rendering.FontFace {
+	if name == "" {
+		return rendering.FontRegular
+	}
+	return rendering.FontFace(name)
+}
+
+func (t canvasText) MeasureText(face string, size float32, text string) float32 {
+	return t.fonts.MeasureString(t.face(face), text, size)
+}
+
+func (t canvasText) RasterizeText(face string, size float32, text string) (*image.Alpha, matrix.Vec2, error) {
+	return t.fonts.RasterizeText(t.face(face), text, size)
+}
@@ -0,0 +1,357 @@
+/******************************************************************************/
+/* context.go                                                                 */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+// Package canvas is an immediate mode 2D drawing API in the style of the
+// HTML canvas. Everything is drawn on the CPU into an RGBA8 buffer, which
+// the canvas UI element uploads to its texture, so it can be used (and
+// tested) without a window or GPU.
+package canvas
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/image/vector"
+	"kaijuengine.com/matrix"
+)
+
+type LineCap = int
+
+const (
+	LineCapButt LineCap = iota
+	LineCapRound
+	LineCapSquare
+)
+
+type LineJoin = int
+
+const (
+	LineJoinMiter LineJoin = iota
+	LineJoinRound
+	LineJoinBevel
+)
+
+type state struct {
+	fill, stroke paint
+	lineWidth    matrix.Float
+	lineCap      LineCap
+	lineJoin     LineJoin
+	miterLimit   matrix.Float
+	globalAlpha  matrix.Float
+	transform    affine
+	font         string
+	fontSize     matrix.Float
+	textAlign    TextAlign
+	textBaseline TextBaseline
+	// clip is the coverage of the clip region over the whole canvas, it is
+	// replaced rather than changed so saved states can share it
+	clip *image.Alpha
+}
+
+// Context is the drawing surface of a canvas. Points given to it are in
+// pixels with the origin at the top left of the canvas and y going down.
+// The zero value has no size, call Resize before drawing on it.
+type Context struct {
+	width, height int
+	pixels        []byte
+	state         state
+	stack         []state
+	path          path
+	dirty         image.Rectangle
+	text          TextRenderer
+	raster        vector.Rasterizer
+	mask          image.Alpha
+}
+
+// New creates a context with a transparent canvas of the given size
+func New(width, height int) *Context {
+	c := &Context{}
+	c.Resize(width, height)
+	return c
+}
+
+// Resize clears the canvas to the new size and resets the drawing state, the
+// same as setting the size of an HTML canvas
+func (c *Context) Resize(width, height int) {
+	c.width, c.height = max(width, 0), max(height, 0)
+	c.pixels = make([]byte, c.width*c.height*4)
+	c.stack = c.stack[:0]
+	c.path.reset()
+	c.state = state{
+		fill:        paint{color: matrix.ColorBlack()},
+		stroke:      paint{color: matrix.ColorBlack()},
+		lineWidth:   1,
+		miterLimit:  10,
+		globalAlpha: 1,
+		transform:   identity(),
+		fontSize:    10,
+	}
+	c.dirty = image.Rect(0, 0, c.width, c.height)
+}
+
+func (c *Context) Width() int  { return c.width }
+func (c *Context) Height() int { return c.height }
+
+// Pixels is the RGBA8 (not premultiplied) content of the canvas, row by row
+// from the top
+func (c *Context) Pixels() []byte { return c.pixels }
+
+// Pixel reads the color of a single pixel of the canvas
+func (c *Context) Pixel(x, y int) matrix.Color {
+	if x < 0 || y < 0 || x >= c.width || y >= c.height {
+		return matrix.ColorTransparent()
+	}
+	i := (y*c.width + x) * 4
+	return matrix.Color8FromBytes(c.pixels[i : i+4]).AsColor()
+}
+
+// IsDirty will return true if anything was drawn since the last ClearDirty
+func (c *Context) IsDirty() bool { return !c.dirty.Empty() }
+
+// DirtyRegion is the x, y, width and height of the part of the canvas that
+// was drawn to since the last ClearDirty
+func (c *Context) DirtyRegion() matrix.Vec4i {
+	return matrix.Vec4i{int32(c.dirty.Min.X), int32(c.dirty.Min.Y),
+		int32(c.dirty.Dx()), int32(c.dirty.Dy())}
+}
+
+// DirtyPixels copies out the pixels of the dirty region, row by row
+func (c *Context) DirtyPixels() []byte {
+	w := c.dirty.Dx()
+	out := make([]byte, 0, w*c.dirty.Dy()*4)
+	for y := c.dirty.Min.Y; y < c.dirty.Max.Y; y++ {
+		start := (y*c.width + c.dirty.Min.X) * 4
+		out = append(out, c.pixels[start:start+w*4]...)
+	}
+	return out
+}
+
+func (c *Context) ClearDirty() { c.dirty = image.Rectangle{} }
+
+// SetTextRenderer sets what draws the text for FillText and MeasureText,
+// the canvas UI element sets it to the font cache of the host
+func (c *Context) SetTextRenderer(renderer TextRenderer) { c.text = renderer }
+
+// Save pushes the current drawing state (styles, transform and clip) to be
+// brought back with Restore. The path is not part of the state
+func (c *Context) Save() { c.stack = append(c.stack, c.state) }
+
+func (c *Context) Restore() {
+	if len(c.stack) == 0 {
+		return
+	}
+	c.state = c.stack[len(c.stack)-1]
+	c.stack = c.stack[:len(c.stack)-1]
+}
+
+func (c *Context) SetFillColor(color matrix.Color)   { c.state.fill = paint{color: color} }
+func (c *Context) SetStrokeColor(color matrix.Color) { c.state.stroke = paint{color: color} }
+
+func (c *Context) SetFillGradient(gradient *Gradient) {
+	c.state.fill = paint{color: matrix.ColorBlack(), gradient: gradient}
+}
+
+func (c *Context) SetStrokeGradient(gradient *Gradient) {
+	c.state.stroke = paint{color: matrix.ColorBlack(), gradient: gradient}
+}
+
+// SetGlobalAlpha sets the opacity of everything drawn after, values outside
+// of 0 to 1 are ignored
+func (c *Context) SetGlobalAlpha(alpha matrix.Float) {
+	if alpha >= 0 && alpha <= 1 {
+		c.state.globalAlpha = alpha
+	}
+}
+
+func (c *Context) SetLineWidth(width matrix.Float) {
+	if width > 0 && !matrix.IsInf(width, 0) {
+		c.state.lineWidth = width
+	}
+}
+
+func (c *Context) SetLineCap(lineCap LineCap)    { c.state.lineCap = lineCap }
+func (c *Context) SetLineJoin(lineJoin LineJoin) { c.state.lineJoin = lineJoin }
+
+func (c *Context) SetMiterLimit(limit matrix.Float) {
+	if limit > 0 && !matrix.IsInf(limit, 0) {
+		c.state.miterLimit = limit
+	}
+}
+
+func (c *Context) Translate(x, y matrix.Float) {
+	c.state.transform = c.state.transform.multiply(affine{1, 0, 0, 1, x, y})
+}
+
+// Rotate turns everything drawn after clockwise by angle radians
+func (c *Context) Rotate(angle matrix.Float) {
+	sin, cos := matrix.Sin(angle), matrix.Cos(angle)
+	c.state.transform = c.state.transform.multiply(affine{cos, sin, -sin, cos, 0, 0})
+}
+
+func (c *Context) Scale(x, y matrix.Float) {
+	c.state.transform = c.state.transform.multiply(affine{x, 0, 0, y, 0, 0})
+}
+
+// Transform multiplies the current transform by the matrix
+// [m11 m21 dx]
+// [m12 m22 dy]
+func (c *Context) Transform(m11, m12, m21, m22, dx, dy matrix.Float) {
+	c.state.transform = c.state.transform.multiply(affine{m11, m12, m21, m22, dx, dy})
+}
+
+func (c *Context) SetTransform(m11, m12, m21, m22, dx, dy matrix.Float) {
+	c.state.transform = affine{m11, m12, m21, m22, dx, dy}
+}
+
+func (c *Context) ResetTransform() { c.state.transform = identity() }
+
+// Fill fills the current path with the fill style, using the non-zero
+// winding rule
+func (c *Context) Fill() {
+	c.composite(c.path.polygons(), c.state.fill.source(c.state.transform), false)
+}
+
+func (c *Context) Stroke() {
+	c.composite(c.strokePolygons(c.path.subpaths), c.state.stroke.source(c.state.transform), false)
+}
+
+// Clip limits everything drawn after to the inside of the current path, and
+// of any clip that was already set
+func (c *Context) Clip() {
+	clip := image.NewAlpha(image.Rect(0, 0, c.width, c.height))
+	if mask, r := c.coverage(c.path.polygons()); mask != nil {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				a := mask.Pix[mask.PixOffset(x-r.Min.X, y-r.Min.Y)]
+				if old := c.state.clip; old != nil {
+					a = uint8(uint32(a) * uint32(old.Pix[old.PixOffset(x, y)]) / 255)
+				}
+				clip.Pix[clip.PixOffset(x, y)] = a
+			}
+		}
+	}
+	c.state.clip = clip
+}
+
+func (c *Context) FillRect(x, y, width, height matrix.Float) {
+	c.composite([][]point{c.rectPoints(x, y, width, height)},
+		c.state.fill.source(c.state.transform), false)
+}
+
+func (c *Context) StrokeRect(x, y, width, height matrix.Float) {
+	sp := subpath{points: c.rectPoints(x, y, width, height), closed: true}
+	c.composite(c.strokePolygons([]subpath{sp}), c.state.stroke.source(c.state.transform), false)
+}
+
+// ClearRect makes the pixels of the rectangle transparent, it is limited by
+// the clip but not by the global alpha
+func (c *Context) ClearRect(x, y, width, height matrix.Float) {
+	c.composite([][]point{c.rectPoints(x, y, width, height)}, nil, true)
+}
+
+func (c *Context) rectPoints(x, y, width, height matrix.Float) []point {
+	t := c.state.transform
+	return []point{t.apply(x, y), t.apply(x+width, y),
+		t.apply(x+width, y+height), t.apply(x, y+height)}
+}
+
+// coverage rasterizes the polygons (in canvas pixels) and returns how much
+// of each pixel they cover within the returned rectangle of the canvas. The
+// mask is reused by the next call
+func (c *Context) coverage(polygons [][]point) (*image.Alpha, image.Rectangle) {
+	minX, minY := matrix.Inf(1), matrix.Inf(1)
+	maxX, maxY := matrix.Inf(-1), matrix.Inf(-1)
+	for _, poly := range polygons {
+		for _, p := range poly {
+			minX, minY = min(minX, p.x), min(minY, p.y)
+			maxX, maxY = max(maxX, p.x), max(maxY, p.y)
+		}
+	}
+	if minX > maxX || matrix.IsNaN(minX) || matrix.IsNaN(minY) {
+		return nil, image.Rectangle{}
+	}
+	r := image.Rect(int(max(matrix.Floor(minX), -1)), int(max(matrix.Floor(minY), -1)),
+		int(min(matrix.Ceil(maxX), matrix.Float(c.width+1))),
+		int(min(matrix.Ceil(maxY), matrix.Float(c.height+1))))
+	r = r.Intersect(image.Rect(0, 0, c.width, c.height))
+	if r.Empty() {
+		return nil, image.Rectangle{}
+	}
+	w, h := r.Dx(), r.Dy()
+	c.raster.Reset(w, h)
+	c.raster.DrawOp = draw.Src
+	ox, oy := matrix.Float(r.Min.X), matrix.Float(r.Min.Y)
+	for _, poly := range polygons {
+		if len(poly) < 3 {
+			continue
+		}
+		c.raster.MoveTo(float32(poly[0].x-ox), float32(poly[0].y-oy))
+		for _, p := range poly[1:] {
+			c.raster.LineTo(float32(p.x-ox), float32(p.y-oy))
+		}
+		c.raster.ClosePath()
+	}
+	if cap(c.mask.Pix) < w*h {
+		c.mask.Pix = make([]uint8, w*h)
+	}
+	c.mask.Pix = c.mask.Pix[:w*h]
+	c.mask.Stride = w
+	c.mask.Rect = image.Rect(0, 0, w, h)
+	c.raster.Draw(&c.mask, c.mask.Rect, image.Opaque, image.Point{})
+	return &c.mask, r
+}
+
+// composite draws the source color through the coverage of the polygons,
+// or erases the pixels they cover when clearing
+func (c *Context) composite(polygons [][]point, src source, clear bool) {
+	mask, r := c.coverage(polygons)
+	if mask == nil {
+		return
+	}
+	c.blend(mask, r, src, clear)
+}
+
+// blend mixes the source over the canvas, mask being how much of each pixel
+// of the rectangle r is drawn to
+func (c *Context) blend(mask *image.Alpha, r image.Rectangle, src source, clear bool) {
+	clip := c.state.clip
+	drawn := image.Rectangle{Min: r.Max, Max: r.Min}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cov := matrix.Float(mask.Pix[mask.PixOffset(x-r.Min.X, y-r.Min.Y)]) / 255
+			if clip != nil {
+				cov *= matrix.Float(clip.Pix[clip.PixOffset(x, y)]) / 255
+			}
+			if cov <= 0 {
+				continue
+			}
+			i := (y*c.width + x) * 4
+			dst := c.pixels[i : i+4 : i+4]
+			if clear {
+				dst[3] = uint8(matrix.Float(dst[3])*(1-cov) + 0.5)
+			} else {
+				col := src(matrix.Float(x)+0.5, matrix.Float(y)+0.5)
+				sa := col.A() * cov * c.state.globalAlpha
+				if sa <= 0 {
+					continue
+				}
+				da := matrix.Float(dst[3]) / 255
+				outA := sa + da*(1-sa)
+				for ch := range 3 {
+					d := matrix.Float(dst[ch]) / 255
+					v := (col[ch]*sa + d*da*(1-sa)) / outA
+					dst[ch] = uint8(matrix.Clamp(v, 0, 1)*255 + 0.5)
+				}
+				dst[3] = uint8(matrix.Clamp(outA, 0, 1)*255 + 0.5)
+			}
+			drawn.Min.X, drawn.Min.Y = min(drawn.Min.X, x), min(drawn.Min.Y, y)
+			drawn.Max.X, drawn.Max.Y = max(drawn.Max.X, x+1), max(drawn.Max.Y, y+1)
+		}
+	}
+	if !drawn.Empty() {
+		c.dirty = c.dirty.Union(drawn)
+	}
+}
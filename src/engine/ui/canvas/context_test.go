@@ -0,0 +1,336 @@
+/******************************************************************************/
+/* context_test.go                                                            */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package canvas
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"kaijuengine.com/matrix"
+)
+
+func canvasColorNear(a, b matrix.Color) bool {
+	for i := range a {
+		if matrix.Abs(a[i]-b[i]) > 0.02 {
+			return false
+		}
+	}
+	return true
+}
+
+func countCanvasPixels(c *Context, test func(matrix.Color) bool) int {
+	count := 0
+	for y := range c.Height() {
+		for x := range c.Width() {
+			if test(c.Pixel(x, y)) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestCanvasNewIsTransparentAndDirty(t *testing.T) {
+	c := New(8, 4)
+	if len(c.Pixels()) != 8*4*4 {
+		t.Fatalf("expected %d bytes, got %d", 8*4*4, len(c.Pixels()))
+	}
+	if c.Pixel(3, 2).A() != 0 {
+		t.Fatal("expected a new canvas to be transparent")
+	}
+	if got := c.DirtyRegion(); got != (matrix.Vec4i{0, 0, 8, 4}) {
+		t.Fatalf("expected the whole canvas to be dirty, got %v", got)
+	}
+	c.ClearDirty()
+	if c.IsDirty() {
+		t.Fatal("expected the canvas to be clean")
+	}
+}
+
+func TestCanvasFillRectMarksDirtyRegion(t *testing.T) {
+	c := New(20, 20)
+	c.ClearDirty()
+	c.SetFillColor(matrix.ColorRed())
+	c.FillRect(4, 5, 6, 3)
+	if got := c.DirtyRegion(); got != (matrix.Vec4i{4, 5, 6, 3}) {
+		t.Fatalf("expected the dirty region to be the rect, got %v", got)
+	}
+	if !canvasColorNear(c.Pixel(6, 6), matrix.ColorRed()) {
+		t.Fatalf("expected red inside the rect, got %v", c.Pixel(6, 6))
+	}
+	if c.Pixel(3, 6).A() != 0 || c.Pixel(10, 6).A() != 0 {
+		t.Fatal("expected nothing outside of the rect")
+	}
+	pixels := c.DirtyPixels()
+	if len(pixels) != 6*3*4 || pixels[0] != 255 || pixels[3] != 255 {
+		t.Fatalf("expected the dirty pixels to be the red rect, got %d bytes", len(pixels))
+	}
+}
+
+func TestCanvasGlobalAlphaBlendsOver(t *testing.T) {
+	c := New(4, 4)
+	c.SetFillColor(matrix.ColorBlue())
+	c.FillRect(0, 0, 4, 4)
+	c.SetGlobalAlpha(0.5)
+	c.SetFillColor(matrix.ColorRed())
+	c.FillRect(0, 0, 4, 4)
+	if got := c.Pixel(1, 1); !canvasColorNear(got, matrix.Color{0.5, 0, 0.5, 1}) {
+		t.Fatalf("expected half red over blue, got %v", got)
+	}
+	c.SetGlobalAlpha(2)
+	c.FillRect(0, 0, 4, 4)
+	if got := c.Pixel(1, 1); !canvasColorNear(got, matrix.Color{0.75, 0, 0.25, 1}) {
+		t.Fatalf("expected an out of range alpha to be ignored, got %v", got)
+	}
+}
+
+func TestCanvasClearRect(t *testing.T) {
+	c := New(10, 10)
+	c.FillRect(0, 0, 10, 10)
+	c.ClearDirty()
+	c.SetGlobalAlpha(0.5)
+	c.ClearRect(2, 2, 3, 3)
+	if c.Pixel(3, 3).A() != 0 {
+		t.Fatalf("expected the cleared pixel to be transparent, got %v", c.Pixel(3, 3))
+	}
+	if c.Pixel(6, 6).A() != 1 {
+		t.Fatal("expected pixels outside of the clear to stay")
+	}
+	if got := c.DirtyRegion(); got != (matrix.Vec4i{2, 2, 3, 3}) {
+		t.Fatalf("expected the cleared rect to be dirty, got %v", got)
+	}
+}
+
+func TestCanvasTransformAndRestore(t *testing.T) {
+	c := New(20, 20)
+	c.Save()
+	c.Translate(10, 10)
+	c.Scale(2, 2)
+	c.FillRect(0, 0, 2, 2)
+	c.Restore()
+	c.FillRect(0, 0, 2, 2)
+	for _, p := range []image.Point{{11, 11}, {13, 13}, {1, 1}} {
+		if c.Pixel(p.X, p.Y).A() != 1 {
+			t.Fatalf("expected %v to be filled", p)
+		}
+	}
+	if c.Pixel(14, 14).A() != 0 || c.Pixel(3, 3).A() != 0 {
+		t.Fatal("expected the rects to end at their transformed size")
+	}
+}
+
+func TestCanvasRotate(t *testing.T) {
+	c := New(20, 20)
+	c.Translate(10, 10)
+	c.Rotate(math.Pi / 2)
+	// Turned clockwise, +x goes down the canvas
+	c.FillRect(2, -1, 6, 2)
+	if c.Pixel(10, 15).A() != 1 {
+		t.Fatal("expected the rect to be turned below the center")
+	}
+	if c.Pixel(15, 10).A() != 0 {
+		t.Fatal("expected nothing right of the center")
+	}
+}
+
+func TestCanvasFillArcArea(t *testing.T) {
+	c := New(40, 40)
+	c.BeginPath()
+	c.Arc(20, 20, 10, 0, 2*math.Pi, false)
+	c.Fill()
+	coverage := matrix.Float(0)
+	for y := range 40 {
+		for x := range 40 {
+			coverage += c.Pixel(x, y).A()
+		}
+	}
+	want := matrix.Float(math.Pi * 100)
+	if matrix.Abs(coverage-want) > want*0.02 {
+		t.Fatalf("expected the circle to cover about %f pixels, got %f", want, coverage)
+	}
+}
+
+func TestCanvasFillNonZeroWinding(t *testing.T) {
+	c := New(20, 20)
+	c.BeginPath()
+	c.Rect(0, 0, 20, 20)
+	c.Rect(5, 5, 10, 10)
+	c.Fill()
+	if c.Pixel(10, 10).A() != 1 {
+		t.Fatal("expected an inner rect wound the same way to stay filled")
+	}
+	c.ClearRect(0, 0, 20, 20)
+	c.BeginPath()
+	c.Rect(0, 0, 20, 20)
+	c.MoveTo(5, 5)
+	c.LineTo(5, 15)
+	c.LineTo(15, 15)
+	c.LineTo(15, 5)
+	c.ClosePath()
+	c.Fill()
+	if c.Pixel(10, 10).A() != 0 {
+		t.Fatal("expected an inner rect wound the other way to cut a hole")
+	}
+	if c.Pixel(2, 2).A() != 1 {
+		t.Fatal("expected the outer rect to be filled")
+	}
+}
+
+func TestCanvasClip(t *testing.T) {
+	c := New(20, 20)
+	c.Save()
+	c.BeginPath()
+	c.Rect(0, 0, 10, 20)
+	c.Clip()
+	c.FillRect(0, 0, 20, 20)
+	if c.Pixel(5, 5).A() != 1 || c.Pixel(15, 5).A() != 0 {
+		t.Fatal("expected only the clipped half to be filled")
+	}
+	c.BeginPath()
+	c.Rect(0, 0, 20, 10)
+	c.Clip()
+	c.ClearRect(0, 0, 20, 20)
+	if c.Pixel(5, 5).A() != 0 || c.Pixel(5, 15).A() != 1 {
+		t.Fatal("expected clips to intersect")
+	}
+	c.Restore()
+	c.FillRect(0, 0, 20, 20)
+	if c.Pixel(15, 15).A() != 1 {
+		t.Fatal("expected Restore to remove the clip")
+	}
+}
+
+func TestCanvasResizeResetsState(t *testing.T) {
+	c := New(4, 4)
+	c.Translate(2, 2)
+	c.SetFillColor(matrix.ColorRed())
+	c.FillRect(0, 0, 1, 1)
+	c.Resize(6, 6)
+	if c.Width() != 6 || c.Height() != 6 || c.Pixel(2, 2).A() != 0 {
+		t.Fatal("expected a clear canvas of the new size")
+	}
+	c.FillRect(0, 0, 1, 1)
+	if !canvasColorNear(c.Pixel(0, 0), matrix.ColorBlack()) {
+		t.Fatalf("expected the default black fill without transform, got %v", c.Pixel(0, 0))
+	}
+}
+
+func TestCanvasZeroValueIsSafe(t *testing.T) {
+	c := &Context{}
+	c.FillRect(0, 0, 10, 10)
+	c.BeginPath()
+	c.Arc(0, 0, 5, 0, 1, false)
+	c.Stroke()
+	if c.IsDirty() {
+		t.Fatal("expected nothing to be drawn without a size")
+	}
+}
+
+type testTextRenderer struct{}
+
+func (testTextRenderer) MeasureText(face string, size float32, text string) float32 {
+	return size * float32(len(text))
+}
+
+// RasterizeText draws every letter as a solid square sitting on the baseline
+func (testTextRenderer) RasterizeText(face string, size float32, text string) (*image.Alpha, matrix.Vec2, error) {
+	s := int(size)
+	mask := image.NewAlpha(image.Rect(0, 0, s*len(text), s))
+	for i := range mask.Pix {
+		mask.Pix[i] = 255
+	}
+	return mask, matrix.Vec2{0, size}, nil
+}
+
+func TestCanvasFillText(t *testing.T) {
+	c := New(40, 20)
+	c.FillText("ab", 2, 10)
+	if c.IsDirty() && c.DirtyRegion() != (matrix.Vec4i{0, 0, 40, 20}) {
+		t.Fatal("expected nothing to be drawn without a text renderer")
+	}
+	c.ClearDirty()
+	c.SetTextRenderer(testTextRenderer{})
+	c.SetFont("", 8)
+	c.SetFillColor(matrix.ColorRed())
+	c.FillText("ab", 2, 10)
+	if got := c.DirtyRegion(); got != (matrix.Vec4i{2, 2, 16, 8}) {
+		t.Fatalf("expected the text above the baseline, got %v", got)
+	}
+	if got := c.MeasureText("abc"); got != 24 {
+		t.Fatalf("expected the measure of the renderer, got %f", got)
+	}
+	c.ClearRect(0, 0, 40, 20)
+	c.ClearDirty()
+	c.SetTextAlign(TextAlignCenter)
+	c.SetTextBaseline(TextBaselineTop)
+	c.FillText("ab", 20, 0)
+	if got := c.DirtyRegion(); got.X() != 12 || got.Width() != 16 {
+		t.Fatalf("expected the text to be centered, got %v", got)
+	}
+	if c.Pixel(20, 2).A() != 1 {
+		t.Fatal("expected the text to hang below the top baseline")
+	}
+}
+
+func TestCanvasFillTextScaled(t *testing.T) {
+	c := New(40, 40)
+	c.ClearDirty()
+	c.SetTextRenderer(testTextRenderer{})
+	c.SetFont("", 5)
+	c.Scale(2, 2)
+	c.FillText("a", 2, 10)
+	if got := c.DirtyRegion(); got != (matrix.Vec4i{4, 10, 10, 10}) {
+		t.Fatalf("expected the text to be scaled, got %v", got)
+	}
+}
+
+func TestCanvasDrawImage(t *testing.T) {
+	img := &Image{Width: 2, Height: 1, Pixels: []byte{255, 0, 0, 255, 0, 0, 255, 255}}
+	c := New(20, 10)
+	c.ClearDirty()
+	c.DrawImage(img, 0, 0, 20, 10)
+	if !canvasColorNear(c.Pixel(1, 5), matrix.ColorRed()) ||
+		!canvasColorNear(c.Pixel(18, 5), matrix.ColorBlue()) {
+		t.Fatalf("expected the image to be stretched, got %v and %v", c.Pixel(1, 5), c.Pixel(18, 5))
+	}
+	c.ClearRect(0, 0, 20, 10)
+	c.DrawSubImage(img, 1, 0, 1, 1, 5, 5, 4, 4)
+	if !canvasColorNear(c.Pixel(5, 5), matrix.ColorBlue()) || c.Pixel(4, 5).A() != 0 {
+		t.Fatal("expected only the blue half of the image in the rect")
+	}
+	c.DrawImage(&Image{Width: 4, Height: 4}, 0, 0, 4, 4)
+	c.DrawImage(nil, 0, 0, 4, 4)
+}
+
+func TestCanvasGetAndPutImage(t *testing.T) {
+	c := New(4, 4)
+	c.SetFillColor(matrix.ColorRed())
+	c.FillRect(1, 1, 2, 2)
+	img := c.GetImage(-1, 0, 4, 4)
+	if img.Width != 4 || len(img.Pixels) != 4*4*4 {
+		t.Fatalf("expected a 4x4 image, got %dx%d", img.Width, img.Height)
+	}
+	// Canvas pixel 1, 1 is image pixel 2, 1
+	if img.Pixels[(1*4+2)*4] != 255 || img.Pixels[(1*4+0)*4+3] != 0 {
+		t.Fatal("expected the image to be offset and transparent outside the canvas")
+	}
+	other := New(4, 4)
+	other.SetFillColor(matrix.ColorBlue())
+	other.FillRect(0, 0, 4, 4)
+	other.ClearDirty()
+	other.PutImage(img, 1, 0)
+	if !canvasColorNear(other.Pixel(3, 1), matrix.ColorRed()) || other.Pixel(2, 1).A() != 0 {
+		t.Fatal("expected the image to replace the pixels without blending")
+	}
+	if !canvasColorNear(other.Pixel(0, 1), matrix.ColorBlue()) {
+		t.Fatal("expected the pixels left of the image to stay")
+	}
+	if got := other.DirtyRegion(); got != (matrix.Vec4i{1, 0, 3, 4}) {
+		t.Fatalf("expected the put pixels to be dirty, got %v", got)
+	}
+}
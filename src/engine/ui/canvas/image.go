@@ -0,0 +1,115 @@
+/******************************************************************************/
+/* image.go                                                                   */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package canvas
+
+import (
+	"image"
+
+	"kaijuengine.com/matrix"
+)
+
+// Image is an RGBA8 (not premultiplied) picture that can be drawn onto a
+// canvas, row by row from the top
+type Image struct {
+	Width, Height int
+	Pixels        []byte
+}
+
+func (img *Image) valid() bool {
+	return img != nil && img.Width > 0 && img.Height > 0 &&
+		len(img.Pixels) >= img.Width*img.Height*4
+}
+
+// sample bilinearly reads the image at a point measured in its pixels,
+// keeping within the rectangle so neighbouring parts of an atlas don't bleed
+func (img *Image) sample(x, y, minX, minY, maxX, maxY matrix.Float) matrix.Color {
+	x = matrix.Clamp(x, minX+0.5, maxX-0.5) - 0.5
+	y = matrix.Clamp(y, minY+0.5, maxY-0.5) - 0.5
+	x0, y0 := int(matrix.Floor(x)), int(matrix.Floor(y))
+	fx, fy := x-matrix.Float(x0), y-matrix.Float(y0)
+	x1, y1 := min(x0+1, int(maxX)-1, img.Width-1), min(y0+1, int(maxY)-1, img.Height-1)
+	read := func(px, py int) matrix.Color {
+		i := (max(py, 0)*img.Width + max(px, 0)) * 4
+		return matrix.Color8FromBytes(img.Pixels[i : i+4]).AsColor()
+	}
+	c00, c10, c01, c11 := read(x0, y0), read(x1, y0), read(x0, y1), read(x1, y1)
+	// Mixed premultiplied so the color of transparent pixels doesn't show
+	var out matrix.Color
+	out[3] = matrix.Lerp(matrix.Lerp(c00[3], c10[3], fx), matrix.Lerp(c01[3], c11[3], fx), fy)
+	if out[3] <= 0 {
+		return matrix.ColorTransparent()
+	}
+	for ch := range 3 {
+		top := matrix.Lerp(c00[ch]*c00[3], c10[ch]*c10[3], fx)
+		bottom := matrix.Lerp(c01[ch]*c01[3], c11[ch]*c11[3], fx)
+		out[ch] = matrix.Lerp(top, bottom, fy) / out[3]
+	}
+	return out
+}
+
+// DrawImage draws the whole image stretched into the rectangle
+func (c *Context) DrawImage(img *Image, x, y, width, height matrix.Float) {
+	if !img.valid() {
+		return
+	}
+	c.DrawSubImage(img, 0, 0, matrix.Float(img.Width), matrix.Float(img.Height), x, y, width, height)
+}
+
+// DrawSubImage draws the part sx, sy, sw, sh of the image (in its pixels)
+// stretched into the rectangle x, y, width, height
+func (c *Context) DrawSubImage(img *Image, sx, sy, sw, sh, x, y, width, height matrix.Float) {
+	if !img.valid() || sw == 0 || sh == 0 || width == 0 || height == 0 {
+		return
+	}
+	inv, ok := c.state.transform.inverse()
+	if !ok {
+		return
+	}
+	minX, minY := max(min(sx, sx+sw), 0), max(min(sy, sy+sh), 0)
+	maxX := min(max(sx, sx+sw), matrix.Float(img.Width))
+	maxY := min(max(sy, sy+sh), matrix.Float(img.Height))
+	if minX >= maxX || minY >= maxY {
+		return
+	}
+	toImage := affine{sw / width, 0, 0, sh / height, sx - x*sw/width, sy - y*sh/height}.multiply(inv)
+	c.composite([][]point{c.rectPoints(x, y, width, height)}, func(px, py matrix.Float) matrix.Color {
+		p := toImage.apply(px, py)
+		return img.sample(p.x, p.y, minX, minY, maxX, maxY)
+	}, false)
+}
+
+// GetImage copies out the pixels of the rectangle of the canvas, the parts
+// outside of the canvas are transparent
+func (c *Context) GetImage(x, y, width, height int) *Image {
+	img := &Image{Width: max(width, 0), Height: max(height, 0)}
+	img.Pixels = make([]byte, img.Width*img.Height*4)
+	r := image.Rect(x, y, x+img.Width, y+img.Height).Intersect(image.Rect(0, 0, c.width, c.height))
+	for row := r.Min.Y; row < r.Max.Y; row++ {
+		src := (row*c.width + r.Min.X) * 4
+		dst := ((row-y)*img.Width + r.Min.X - x) * 4
+		copy(img.Pixels[dst:dst+r.Dx()*4], c.pixels[src:])
+	}
+	return img
+}
+
+// PutImage replaces the pixels of the canvas with the image placed at x, y,
+// without blending, transforming or clipping it
+func (c *Context) PutImage(img *Image, x, y int) {
+	if !img.valid() {
+		return
+	}
+	r := image.Rect(x, y, x+img.Width, y+img.Height).Intersect(image.Rect(0, 0, c.width, c.height))
+	if r.Empty() {
+		return
+	}
+	for row := r.Min.Y; row < r.Max.Y; row++ {
+		src := ((row-y)*img.Width + r.Min.X - x) * 4
+		dst := (row*c.width + r.Min.X) * 4
+		copy(c.pixels[dst:dst+r.Dx()*4], img.Pixels[src:])
+	}
+	c.dirty = c.dirty.Union(r)
+}
@@ -0,0 +1,151 @@
+/******************************************************************************/
+/* paint.go                                                                   */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package canvas
+
+import (
+	"slices"
+
+	"kaijuengine.com/matrix"
+)
+
+// source gives the color to draw at a point of the canvas (in pixels)
+type source func(x, y matrix.Float) matrix.Color
+
+type paint struct {
+	color    matrix.Color
+	gradient *Gradient
+}
+
+// source is the paint as it is used with the transform, gradients are in the
+// coordinates of the transform at the time they are drawn with
+func (p paint) source(transform affine) source {
+	if p.gradient == nil {
+		color := p.color
+		return func(x, y matrix.Float) matrix.Color { return color }
+	}
+	inv, ok := transform.inverse()
+	if !ok {
+		return func(x, y matrix.Float) matrix.Color { return matrix.ColorTransparent() }
+	}
+	g := p.gradient
+	return func(x, y matrix.Float) matrix.Color {
+		return g.colorAt(inv.apply(x, y))
+	}
+}
+
+type gradientStop struct {
+	offset matrix.Float
+	color  matrix.Color
+}
+
+// Gradient is a linear or radial gradient to fill or stroke with, created by
+// CreateLinearGradient or CreateRadialGradient. Colors past the ends of the
+// gradient are the colors of the first and last stops
+type Gradient struct {
+	radial                 bool
+	x0, y0, r0, x1, y1, r1 matrix.Float
+	stops                  []gradientStop
+}
+
+// CreateLinearGradient creates a gradient along the line from x0, y0 to
+// x1, y1
+func (c *Context) CreateLinearGradient(x0, y0, x1, y1 matrix.Float) *Gradient {
+	return &Gradient{x0: x0, y0: y0, x1: x1, y1: y1}
+}
+
+// CreateRadialGradient creates a gradient between the circle x0, y0, r0 and
+// the circle x1, y1, r1
+func (c *Context) CreateRadialGradient(x0, y0, r0, x1, y1, r1 matrix.Float) *Gradient {
+	return &Gradient{radial: true, x0: x0, y0: y0, r0: max(r0, 0),
+		x1: x1, y1: y1, r1: max(r1, 0)}
+}
+
+// AddColorStop adds a color at the offset (0 to 1) of the gradient, stops at
+// the same offset make a hard edge between their colors
+func (g *Gradient) AddColorStop(offset matrix.Float, color matrix.Color) {
+	if offset < 0 || offset > 1 {
+		return
+	}
+	i, _ := slices.BinarySearchFunc(g.stops, offset, func(s gradientStop, o matrix.Float) int {
+		if s.offset <= o {
+			return -1
+		}
+		return 1
+	})
+	g.stops = slices.Insert(g.stops, i, gradientStop{offset, color})
+}
+
+func (g *Gradient) colorAt(p point) matrix.Color {
+	t, ok := g.offsetAt(p)
+	if !ok || len(g.stops) == 0 {
+		return matrix.ColorTransparent()
+	}
+	if t <= g.stops[0].offset {
+		return g.stops[0].color
+	}
+	for i := 1; i < len(g.stops); i++ {
+		a, b := g.stops[i-1], g.stops[i]
+		if t < b.offset {
+			// Mix the colors premultiplied so transparent stops don't
+			// bring in their color
+			f := (t - a.offset) / (b.offset - a.offset)
+			alpha := matrix.Lerp(a.color.A(), b.color.A(), f)
+			if alpha <= 0 {
+				return matrix.ColorTransparent()
+			}
+			var out matrix.Color
+			for ch := range 3 {
+				out[ch] = matrix.Lerp(a.color[ch]*a.color.A(), b.color[ch]*b.color.A(), f) / alpha
+			}
+			out[3] = alpha
+			return out
+		}
+	}
+	return g.stops[len(g.stops)-1].color
+}
+
+// offsetAt is how far along the gradient the point is, it is false where a
+// radial gradient doesn't reach
+func (g *Gradient) offsetAt(p point) (matrix.Float, bool) {
+	if !g.radial {
+		d := point{g.x1 - g.x0, g.y1 - g.y0}
+		l := d.dot(d)
+		if l == 0 {
+			return 0, false
+		}
+		return p.sub(point{g.x0, g.y0}).dot(d) / l, true
+	}
+	// Find the largest t where p is on the circle between the two circles
+	// at t, with a radius that isn't negative
+	cd := point{g.x1 - g.x0, g.y1 - g.y0}
+	pd := p.sub(point{g.x0, g.y0})
+	dr := g.r1 - g.r0
+	a := cd.dot(cd) - dr*dr
+	b := pd.dot(cd) + g.r0*dr
+	c := pd.dot(pd) - g.r0*g.r0
+	valid := func(t matrix.Float) bool { return g.r0+t*dr >= 0 }
+	if matrix.Abs(a) < matrix.Tiny {
+		if b == 0 {
+			return 0, false
+		}
+		t := c / (2 * b)
+		return t, valid(t)
+	}
+	disc := b*b - a*c
+	if disc < 0 {
+		return 0, false
+	}
+	sq := matrix.Sqrt(disc)
+	t0, t1 := (b+sq)/a, (b-sq)/a
+	if t0 < t1 {
+		t0, t1 = t1, t0
+	}
+	if valid(t0) {
+		return t0, true
+	}
+	return t1, valid(t1)
+}
@@ -0,0 +1,115 @@
+/******************************************************************************/
+/* paint_test.go                                                              */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package canvas
+
+import (
+	"testing"
+
+	"kaijuengine.com/matrix"
+)
+
+func TestGradientAddColorStopKeepsOrder(t *testing.T) {
+	g := &Gradient{}
+	g.AddColorStop(1, matrix.ColorBlue())
+	g.AddColorStop(0, matrix.ColorRed())
+	g.AddColorStop(0.5, matrix.ColorWhite())
+	g.AddColorStop(0.5, matrix.ColorBlack())
+	g.AddColorStop(2, matrix.ColorRed())
+	if len(g.stops) != 4 {
+		t.Fatalf("expected 4 stops, got %d", len(g.stops))
+	}
+	want := []matrix.Color{matrix.ColorRed(), matrix.ColorWhite(), matrix.ColorBlack(), matrix.ColorBlue()}
+	for i := range want {
+		if g.stops[i].color != want[i] {
+			t.Fatalf("expected stop %d to be %v, got %v", i, want[i], g.stops[i].color)
+		}
+	}
+	// Stops at the same offset make a hard edge
+	if got := g.colorAt(point{0.49, 0}); got == matrix.ColorBlack() {
+		t.Fatal("expected white before the edge")
+	}
+}
+
+func TestLinearGradientFill(t *testing.T) {
+	c := New(100, 4)
+	g := c.CreateLinearGradient(0, 0, 100, 0)
+	g.AddColorStop(0, matrix.ColorRed())
+	g.AddColorStop(1, matrix.ColorBlue())
+	c.SetFillGradient(g)
+	c.FillRect(0, 0, 100, 4)
+	if got := c.Pixel(0, 1); !canvasColorNear(got, matrix.ColorRed()) {
+		t.Fatalf("expected red at the start, got %v", got)
+	}
+	if got := c.Pixel(99, 1); !canvasColorNear(got, matrix.ColorBlue()) {
+		t.Fatalf("expected blue at the end, got %v", got)
+	}
+	if got := c.Pixel(50, 1); !canvasColorNear(got, matrix.Color{0.495, 0, 0.505, 1}) {
+		t.Fatalf("expected a mix in the middle, got %v", got)
+	}
+}
+
+func TestGradientUsesTransformOfTheFill(t *testing.T) {
+	c := New(100, 4)
+	g := c.CreateLinearGradient(0, 0, 50, 0)
+	g.AddColorStop(0, matrix.ColorRed())
+	g.AddColorStop(1, matrix.ColorBlue())
+	c.SetFillGradient(g)
+	c.Scale(2, 1)
+	c.FillRect(0, 0, 50, 4)
+	if got := c.Pixel(50, 1); !canvasColorNear(got, matrix.Color{0.495, 0, 0.505, 1}) {
+		t.Fatalf("expected the gradient to be scaled with the rect, got %v", got)
+	}
+}
+
+func TestGradientMixesPremultiplied(t *testing.T) {
+	g := &Gradient{x1: 1}
+	g.AddColorStop(0, matrix.ColorRed())
+	g.AddColorStop(1, matrix.Color{0, 0, 1, 0})
+	if got := g.colorAt(point{0.5, 0}); !canvasColorNear(got, matrix.Color{1, 0, 0, 0.5}) {
+		t.Fatalf("expected the transparent stop to only fade the red, got %v", got)
+	}
+}
+
+func TestRadialGradientOffsets(t *testing.T) {
+	g := &Gradient{radial: true, x0: 10, y0: 10, r0: 0, x1: 10, y1: 10, r1: 10}
+	for _, test := range []struct {
+		p    point
+		want matrix.Float
+	}{{point{10, 10}, 0}, {point{15, 10}, 0.5}, {point{10, 20}, 1}, {point{30, 10}, 2}} {
+		got, ok := g.offsetAt(test.p)
+		if !ok || matrix.Abs(got-test.want) > 0.001 {
+			t.Fatalf("expected %v to be at %f, got %f (%t)", test.p, test.want, got, ok)
+		}
+	}
+	// A cone from a small circle outside of the big one doesn't reach
+	// behind the small circle
+	cone := &Gradient{radial: true, x0: 0, y0: 0, r0: 1, x1: 10, y1: 0, r1: 5}
+	if _, ok := cone.offsetAt(point{-5, 8}); ok {
+		t.Fatal("expected the point behind the cone to have no offset")
+	}
+	if got, ok := cone.offsetAt(point{10, 0}); !ok || got < 1 {
+		t.Fatalf("expected the center of the end circle to be past the end, got %f", got)
+	}
+}
+
+func TestRadialGradientFill(t *testing.T) {
+	c := New(21, 21)
+	g := c.CreateRadialGradient(10.5, 10.5, 0, 10.5, 10.5, 10)
+	g.AddColorStop(0, matrix.ColorWhite())
+	g.AddColorStop(1, matrix.Color{1, 1, 1, 0})
+	c.SetFillGradient(g)
+	c.FillRect(0, 0, 21, 21)
+	if got := c.Pixel(10, 10).A(); got < 0.98 {
+		t.Fatalf("expected the center to be opaque, got %f", got)
+	}
+	if got := c.Pixel(15, 10).A(); matrix.Abs(got-0.5) > 0.02 {
+		t.Fatalf("expected halfway out to be half transparent, got %f", got)
+	}
+	if got := c.Pixel(0, 0).A(); got != 0 {
+		t.Fatalf("expected the corner to be past the gradient, got %f", got)
+	}
+}
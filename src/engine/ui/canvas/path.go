@@ -0,0 +1,251 @@
+/******************************************************************************/
+/* path.go                                                                    */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package canvas
+
+import (
+	"math"
+
+	"kaijuengine.com/matrix"
+)
+
+// point is a position on the canvas in pixels, paths are transformed as they
+// are built so later changes to the transform don't move them
+type point struct{ x, y matrix.Float }
+
+func (p point) sub(o point) point          { return point{p.x - o.x, p.y - o.y} }
+func (p point) add(o point) point          { return point{p.x + o.x, p.y + o.y} }
+func (p point) scale(s matrix.Float) point { return point{p.x * s, p.y * s} }
+func (p point) dot(o point) matrix.Float   { return p.x*o.x + p.y*o.y }
+func (p point) cross(o point) matrix.Float { return p.x*o.y - p.y*o.x }
+func (p point) length() matrix.Float       { return matrix.Sqrt(p.dot(p)) }
+func (p point) near(o point, d matrix.Float) bool {
+	return matrix.Abs(p.x-o.x) <= d && matrix.Abs(p.y-o.y) <= d
+}
+
+func (p point) normalized() point {
+	if l := p.length(); l > 0 {
+		return p.scale(1 / l)
+	}
+	return point{}
+}
+
+// affine is the 2D transform [a c e; b d f] stored as {a, b, c, d, e, f}
+type affine [6]matrix.Float
+
+func identity() affine { return affine{1, 0, 0, 1, 0, 0} }
+
+func (t affine) apply(x, y matrix.Float) point {
+	return point{t[0]*x + t[2]*y + t[4], t[1]*x + t[3]*y + t[5]}
+}
+
+// multiply returns the transform that applies m and then t
+func (t affine) multiply(m affine) affine {
+	return affine{
+		t[0]*m[0] + t[2]*m[1],
+		t[1]*m[0] + t[3]*m[1],
+		t[0]*m[2] + t[2]*m[3],
+		t[1]*m[2] + t[3]*m[3],
+		t[0]*m[4] + t[2]*m[5] + t[4],
+		t[1]*m[4] + t[3]*m[5] + t[5],
+	}
+}
+
+func (t affine) determinant() matrix.Float { return t[0]*t[3] - t[1]*t[2] }
+
+// scale is how much the transform grows lengths on average
+func (t affine) scale() matrix.Float { return matrix.Sqrt(matrix.Abs(t.determinant())) }
+
+func (t affine) inverse() (affine, bool) {
+	det := t.determinant()
+	if det == 0 || matrix.IsNaN(det) {
+		return affine{}, false
+	}
+	return affine{
+		t[3] / det, -t[1] / det, -t[2] / det, t[0] / det,
+		(t[2]*t[5] - t[3]*t[4]) / det, (t[1]*t[4] - t[0]*t[5]) / det,
+	}, true
+}
+
+type subpath struct {
+	points []point
+	closed bool
+}
+
+type path struct {
+	subpaths []subpath
+}
+
+func (p *path) reset() { p.subpaths = p.subpaths[:0] }
+
+func (p *path) current() *subpath {
+	if len(p.subpaths) == 0 {
+		return nil
+	}
+	return &p.subpaths[len(p.subpaths)-1]
+}
+
+func (p *path) moveTo(pt point) {
+	p.subpaths = append(p.subpaths, subpath{points: []point{pt}})
+}
+
+func (p *path) lineTo(pt point) {
+	if sp := p.current(); sp != nil {
+		sp.points = append(sp.points, pt)
+	} else {
+		p.moveTo(pt)
+	}
+}
+
+// polygons are the subpaths as closed shapes to be filled
+func (p *path) polygons() [][]point {
+	out := make([][]point, 0, len(p.subpaths))
+	for i := range p.subpaths {
+		if len(p.subpaths[i].points) > 2 {
+			out = append(out, p.subpaths[i].points)
+		}
+	}
+	return out
+}
+
+// curveSegments is how many lines a curve with a control polygon of the
+// given length (in pixels) is split into
+func curveSegments(length matrix.Float) int {
+	return int(matrix.Clamp(matrix.Ceil(length/3), 1, 256))
+}
+
+func (c *Context) BeginPath() { c.path.reset() }
+
+func (c *Context) MoveTo(x, y matrix.Float) { c.path.moveTo(c.state.transform.apply(x, y)) }
+
+func (c *Context) LineTo(x, y matrix.Float) { c.path.lineTo(c.state.transform.apply(x, y)) }
+
+// ClosePath joins the end of the current subpath to its start and begins a
+// new subpath there
+func (c *Context) ClosePath() {
+	sp := c.path.current()
+	if sp == nil || len(sp.points) == 0 {
+		return
+	}
+	sp.closed = true
+	c.path.moveTo(sp.points[0])
+}
+
+// ensure starts a subpath at the point if the path is empty and returns the
+// last point of the path
+func (c *Context) ensure(x, y matrix.Float) point {
+	sp := c.path.current()
+	if sp == nil || len(sp.points) == 0 {
+		c.MoveTo(x, y)
+		sp = c.path.current()
+	}
+	return sp.points[len(sp.points)-1]
+}
+
+func (c *Context) QuadraticCurveTo(cpx, cpy, x, y matrix.Float) {
+	p0 := c.ensure(cpx, cpy)
+	p1 := c.state.transform.apply(cpx, cpy)
+	p2 := c.state.transform.apply(x, y)
+	n := curveSegments(p1.sub(p0).length() + p2.sub(p1).length())
+	for i := 1; i <= n; i++ {
+		t := matrix.Float(i) / matrix.Float(n)
+		u := 1 - t
+		c.path.lineTo(p0.scale(u * u).add(p1.scale(2 * u * t)).add(p2.scale(t * t)))
+	}
+}
+
+func (c *Context) BezierCurveTo(cp1x, cp1y, cp2x, cp2y, x, y matrix.Float) {
+	p0 := c.ensure(cp1x, cp1y)
+	p1 := c.state.transform.apply(cp1x, cp1y)
+	p2 := c.state.transform.apply(cp2x, cp2y)
+	p3 := c.state.transform.apply(x, y)
+	n := curveSegments(p1.sub(p0).length() + p2.sub(p1).length() + p3.sub(p2).length())
+	for i := 1; i <= n; i++ {
+		t := matrix.Float(i) / matrix.Float(n)
+		u := 1 - t
+		c.path.lineTo(p0.scale(u * u * u).add(p1.scale(3 * u * u * t)).
+			add(p2.scale(3 * u * t * t)).add(p3.scale(t * t * t)))
+	}
+}
+
+// Arc adds a circular arc around x, y from the start to the end angle (in
+// radians, clockwise from the positive x axis), joined to the path by a
+// straight line
+func (c *Context) Arc(x, y, radius, startAngle, endAngle matrix.Float, counterclockwise bool) {
+	c.Ellipse(x, y, radius, radius, 0, startAngle, endAngle, counterclockwise)
+}
+
+func (c *Context) Ellipse(x, y, radiusX, radiusY, rotation, startAngle, endAngle matrix.Float, counterclockwise bool) {
+	if radiusX < 0 || radiusY < 0 {
+		return
+	}
+	const tau = 2 * math.Pi
+	var sweep matrix.Float
+	if !counterclockwise {
+		if endAngle-startAngle >= tau {
+			sweep = tau
+		} else if sweep = matrix.Mod(endAngle-startAngle, tau); sweep < 0 {
+			sweep += tau
+		}
+	} else {
+		if startAngle-endAngle >= tau {
+			sweep = -tau
+		} else if sweep = -matrix.Mod(startAngle-endAngle, tau); sweep > 0 {
+			sweep -= tau
+		}
+	}
+	rSin, rCos := matrix.Sin(rotation), matrix.Cos(rotation)
+	at := func(angle matrix.Float) point {
+		ex, ey := radiusX*matrix.Cos(angle), radiusY*matrix.Sin(angle)
+		return c.state.transform.apply(x+ex*rCos-ey*rSin, y+ex*rSin+ey*rCos)
+	}
+	c.path.lineTo(at(startAngle))
+	r := max(radiusX, radiusY) * c.state.transform.scale()
+	n := curveSegments(matrix.Abs(sweep) * r)
+	n = max(n, int(matrix.Ceil(matrix.Abs(sweep)*8/tau)))
+	for i := 1; i <= n; i++ {
+		c.path.lineTo(at(startAngle + sweep*matrix.Float(i)/matrix.Float(n)))
+	}
+}
+
+// ArcTo adds a line towards x1, y1 that turns towards x2, y2 along an arc of
+// the radius
+func (c *Context) ArcTo(x1, y1, x2, y2, radius matrix.Float) {
+	if radius < 0 {
+		return
+	}
+	last := c.ensure(x1, y1)
+	inv, ok := c.state.transform.inverse()
+	if !ok {
+		return
+	}
+	p0 := inv.apply(last.x, last.y)
+	p1, p2 := point{x1, y1}, point{x2, y2}
+	v1, v2 := p0.sub(p1).normalized(), p2.sub(p1).normalized()
+	if p0.near(p1, matrix.Tiny) || p1.near(p2, matrix.Tiny) || radius == 0 ||
+		matrix.Abs(v1.cross(v2)) < matrix.Tiny {
+		c.LineTo(x1, y1)
+		return
+	}
+	half := matrix.Acos(matrix.Clamp(v1.dot(v2), -1, 1)) / 2
+	dist := radius / matrix.Tan(half)
+	t1, t2 := p1.add(v1.scale(dist)), p1.add(v2.scale(dist))
+	center := p1.add(v1.add(v2).normalized().scale(radius / matrix.Sin(half)))
+	a0 := matrix.Atan2(t1.y-center.y, t1.x-center.x)
+	a1 := matrix.Atan2(t2.y-center.y, t2.x-center.x)
+	d := matrix.Mod(a1-a0+3*math.Pi, 2*math.Pi) - math.Pi
+	c.LineTo(t1.x, t1.y)
+	c.Arc(center.x, center.y, radius, a0, a0+d, d < 0)
+}
+
+// Rect adds a closed rectangle to the path and starts a new subpath at x, y
+func (c *Context) Rect(x, y, width, height matrix.Float) {
+	c.MoveTo(x, y)
+	c.LineTo(x+width, y)
+	c.LineTo(x+width, y+height)
+	c.LineTo(x, y+height)
+	c.ClosePath()
+}
@@ -0,0 +1,147 @@
+/******************************************************************************/
+/* stroke.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package canvas
+
+import (
+	"math"
+
+	"kaijuengine.com/matrix"
+)
+
+// strokePolygons outlines the subpaths as a set of polygons that are all
+// wound the same way, so they add up rather than cut holes when filled
+// together. Lines are widened in canvas pixels, so a transform that scales
+// unevenly is stroked with its average scale
+func (c *Context) strokePolygons(subpaths []subpath) [][]point {
+	hw := c.state.lineWidth * c.state.transform.scale() / 2
+	if hw <= 0 {
+		return nil
+	}
+	out := [][]point{}
+	add := func(poly ...point) {
+		if area(poly) < 0 {
+			for i, j := 0, len(poly)-1; i < j; i, j = i+1, j-1 {
+				poly[i], poly[j] = poly[j], poly[i]
+			}
+		}
+		out = append(out, poly)
+	}
+	for _, sp := range subpaths {
+		pts := make([]point, 0, len(sp.points))
+		for _, p := range sp.points {
+			if len(pts) == 0 || !p.near(pts[len(pts)-1], matrix.Tiny) {
+				pts = append(pts, p)
+			}
+		}
+		closed := sp.closed
+		if closed && len(pts) > 1 && pts[0].near(pts[len(pts)-1], matrix.Tiny) {
+			pts = pts[:len(pts)-1]
+		}
+		if len(pts) < 2 {
+			// A subpath that doesn't go anywhere only shows its caps
+			if len(pts) == 1 && !closed && len(sp.points) > 1 {
+				switch c.state.lineCap {
+				case LineCapRound:
+					add(circlePoints(pts[0], hw)...)
+				case LineCapSquare:
+					p := pts[0]
+					add(point{p.x - hw, p.y - hw}, point{p.x + hw, p.y - hw},
+						point{p.x + hw, p.y + hw}, point{p.x - hw, p.y + hw})
+				}
+			}
+			continue
+		}
+		segments := len(pts) - 1
+		if closed {
+			segments = len(pts)
+		}
+		for i := range segments {
+			a, b := pts[i], pts[(i+1)%len(pts)]
+			n := normal(a, b).scale(hw)
+			add(a.add(n), b.add(n), b.sub(n), a.sub(n))
+		}
+		for i := range pts {
+			if !closed && (i == 0 || i == len(pts)-1) {
+				continue
+			}
+			prev := pts[(i-1+len(pts))%len(pts)]
+			next := pts[(i+1)%len(pts)]
+			c.strokeJoin(prev, pts[i], next, hw, add)
+		}
+		if !closed {
+			c.strokeCap(pts[1], pts[0], hw, add)
+			c.strokeCap(pts[len(pts)-2], pts[len(pts)-1], hw, add)
+		}
+	}
+	return out
+}
+
+func (c *Context) strokeJoin(prev, p, next point, hw matrix.Float, add func(...point)) {
+	d0, d1 := p.sub(prev).normalized(), next.sub(p).normalized()
+	turn := d0.cross(d1)
+	if matrix.Abs(turn) < matrix.Tiny && d0.dot(d1) > 0 {
+		return
+	}
+	if c.state.lineJoin == LineJoinRound {
+		add(circlePoints(p, hw)...)
+		return
+	}
+	// The join fills the gap on the outside of the turn
+	side := matrix.Float(1)
+	if turn > 0 {
+		side = -1
+	}
+	n0 := point{-d0.y, d0.x}.scale(side)
+	n1 := point{-d1.y, d1.x}.scale(side)
+	a, b := p.add(n0.scale(hw)), p.add(n1.scale(hw))
+	if c.state.lineJoin == LineJoinMiter {
+		m := n0.add(n1)
+		if l := m.length(); l > matrix.Tiny && 2/l <= c.state.miterLimit {
+			add(p, a, p.add(m.scale(2*hw/(l*l))), b)
+			return
+		}
+	}
+	add(p, a, b)
+}
+
+// strokeCap adds the cap at the end of the line going from the point before
+// it
+func (c *Context) strokeCap(before, end point, hw matrix.Float, add func(...point)) {
+	switch c.state.lineCap {
+	case LineCapRound:
+		add(circlePoints(end, hw)...)
+	case LineCapSquare:
+		d := end.sub(before).normalized().scale(hw)
+		n := point{-d.y, d.x}
+		add(end.add(n), end.add(n).add(d), end.sub(n).add(d), end.sub(n))
+	}
+}
+
+func normal(a, b point) point {
+	d := b.sub(a).normalized()
+	return point{-d.y, d.x}
+}
+
+// area is the signed area of the polygon, the sign telling which way it is
+// wound
+func area(poly []point) matrix.Float {
+	sum := matrix.Float(0)
+	for i := range poly {
+		sum += poly[i].cross(poly[(i+1)%len(poly)])
+	}
+	return sum / 2
+}
+
+func circlePoints(center point, radius matrix.Float) []point {
+	n := max(8, curveSegments(2*math.Pi*radius))
+	pts := make([]point, n)
+	for i := range pts {
+		angle := 2 * math.Pi * matrix.Float(i) / matrix.Float(n)
+		pts[i] = point{center.x + radius*matrix.Cos(angle), center.y + radius*matrix.Sin(angle)}
+	}
+	return pts
+}
@@ -0,0 +1,173 @@
+/******************************************************************************/
+/* stroke_test.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package canvas
+
+import (
+	"math"
+	"testing"
+
+	"kaijuengine.com/matrix"
+)
+
+func TestStrokeLineWidthAndButtCaps(t *testing.T) {
+	c := New(20, 20)
+	c.SetLineWidth(4)
+	c.BeginPath()
+	c.MoveTo(5, 10)
+	c.LineTo(15, 10)
+	c.Stroke()
+	if c.Pixel(10, 8).A() != 1 || c.Pixel(10, 11).A() != 1 {
+		t.Fatal("expected the line to be 4 pixels wide")
+	}
+	if c.Pixel(10, 7).A() != 0 || c.Pixel(10, 12).A() != 0 {
+		t.Fatal("expected nothing past the width of the line")
+	}
+	if c.Pixel(4, 10).A() != 0 || c.Pixel(15, 10).A() != 0 {
+		t.Fatal("expected butt caps to end at the points")
+	}
+}
+
+func TestStrokeSquareAndRoundCaps(t *testing.T) {
+	for _, lineCap := range []LineCap{LineCapSquare, LineCapRound} {
+		c := New(20, 20)
+		c.SetLineWidth(4)
+		c.SetLineCap(lineCap)
+		c.BeginPath()
+		c.MoveTo(5, 10)
+		c.LineTo(15, 10)
+		c.Stroke()
+		if c.Pixel(3, 10).A() < 0.5 || c.Pixel(16, 10).A() < 0.5 {
+			t.Fatalf("expected cap %d to reach past the points", lineCap)
+		}
+		corner := c.Pixel(3, 8).A()
+		if lineCap == LineCapSquare && corner != 1 {
+			t.Fatal("expected the square cap to fill its corner")
+		} else if lineCap == LineCapRound && corner >= 0.5 {
+			t.Fatal("expected the round cap to leave its corner")
+		}
+	}
+}
+
+func TestStrokeJoins(t *testing.T) {
+	corner := func(join LineJoin) matrix.Float {
+		c := New(30, 30)
+		c.SetLineWidth(6)
+		c.SetLineJoin(join)
+		c.BeginPath()
+		c.MoveTo(5, 20)
+		c.LineTo(20, 20)
+		c.LineTo(20, 5)
+		c.Stroke()
+		// The outer corner of the turn
+		return c.Pixel(22, 22).A()
+	}
+	if got := corner(LineJoinMiter); got != 1 {
+		t.Fatalf("expected the miter join to fill the corner, got %f", got)
+	}
+	if got := corner(LineJoinBevel); got > 0.1 {
+		t.Fatalf("expected the bevel join to cut the corner, got %f", got)
+	}
+	if got := corner(LineJoinRound); got >= 1 || got <= 0 {
+		t.Fatalf("expected the round join to partly cover the corner, got %f", got)
+	}
+}
+
+func TestStrokeMiterLimit(t *testing.T) {
+	c := New(60, 40)
+	c.SetLineWidth(4)
+	c.SetMiterLimit(1.5)
+	c.BeginPath()
+	c.MoveTo(5, 35)
+	c.LineTo(30, 5)
+	c.LineTo(55, 35)
+	c.Stroke()
+	if c.Pixel(30, 1).A() != 0 {
+		t.Fatal("expected the sharp join to be beveled past the limit")
+	}
+	c.SetMiterLimit(10)
+	c.Stroke()
+	if c.Pixel(30, 1).A() == 0 {
+		t.Fatal("expected the miter to reach past the point within the limit")
+	}
+}
+
+func TestStrokeRectIsClosed(t *testing.T) {
+	c := New(20, 20)
+	c.SetLineWidth(2)
+	c.StrokeRect(5, 5, 10, 10)
+	for _, p := range [][2]int{{4, 4}, {15, 4}, {15, 15}, {4, 15}, {10, 5}} {
+		if c.Pixel(p[0], p[1]).A() != 1 {
+			t.Fatalf("expected %v on the outline to be drawn", p)
+		}
+	}
+	if c.Pixel(10, 10).A() != 0 {
+		t.Fatal("expected the inside of the rect to stay empty")
+	}
+}
+
+func TestStrokeOverlapDoesNotCutHoles(t *testing.T) {
+	c := New(30, 30)
+	c.SetLineWidth(4)
+	c.SetLineJoin(LineJoinRound)
+	c.BeginPath()
+	// A line that crosses back over itself
+	c.MoveTo(5, 5)
+	c.LineTo(25, 25)
+	c.LineTo(25, 5)
+	c.LineTo(5, 25)
+	c.Stroke()
+	if c.Pixel(15, 15).A() != 1 {
+		t.Fatal("expected the crossing to stay filled")
+	}
+}
+
+func TestStrokeArcAndScaledWidth(t *testing.T) {
+	c := New(40, 40)
+	c.Scale(2, 2)
+	c.SetLineWidth(1)
+	c.BeginPath()
+	c.Arc(10, 10, 8, 0, math.Pi, false)
+	c.Stroke()
+	// The lower half of a circle of radius 16 on the canvas, 2 pixels wide
+	if c.Pixel(20, 35).A() != 1 || c.Pixel(20, 37).A() != 0 {
+		t.Fatal("expected the arc to be stroked below the center")
+	}
+	if c.Pixel(20, 4).A() != 0 {
+		t.Fatal("expected nothing above the center")
+	}
+}
+
+func TestArcToRoundsCorner(t *testing.T) {
+	c := New(30, 30)
+	c.BeginPath()
+	c.MoveTo(0, 0)
+	c.ArcTo(20, 0, 20, 20, 10)
+	c.LineTo(20, 20)
+	c.LineTo(0, 20)
+	c.ClosePath()
+	c.Fill()
+	if c.Pixel(18, 1).A() != 0 {
+		t.Fatal("expected the corner to be rounded")
+	}
+	if c.Pixel(10, 1).A() != 1 || c.Pixel(18, 12).A() != 1 {
+		t.Fatal("expected the straight edges before and after the arc")
+	}
+}
+
+func TestBezierCurveEndsAtPoint(t *testing.T) {
+	c := New(10, 10)
+	c.MoveTo(0, 0)
+	c.BezierCurveTo(10, 0, 0, 10, 10, 10)
+	c.QuadraticCurveTo(0, 10, 0, 5)
+	sp := c.path.current()
+	if last := sp.points[len(sp.points)-1]; !last.near(point{0, 5}, matrix.Tiny) {
+		t.Fatalf("expected the path to end at the last point, got %v", last)
+	}
+	if len(sp.points) < 6 {
+		t.Fatalf("expected the curves to be split into lines, got %d points", len(sp.points))
+	}
+}
@@ -0,0 +1,133 @@
+/******************************************************************************/
+/* text.go                                                                    */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package canvas
+
+import (
+	"image"
+	"log/slog"
+
+	"kaijuengine.com/matrix"
+)
+
+type TextAlign = int
+
+const (
+	TextAlignStart TextAlign = iota
+	TextAlignEnd
+	TextAlignLeft
+	TextAlignRight
+	TextAlignCenter
+)
+
+type TextBaseline = int
+
+const (
+	TextBaselineAlphabetic TextBaseline = iota
+	TextBaselineTop
+	TextBaselineHanging
+	TextBaselineMiddle
+	TextBaselineIdeographic
+	TextBaselineBottom
+)
+
+// TextRenderer measures and draws single lines of text for a context. The
+// face is the name of the font given to SetFont and the size is in pixels
+type TextRenderer interface {
+	MeasureText(face string, size float32, text string) float32
+	// RasterizeText returns how much each pixel is covered by the text and
+	// where the left of its baseline is within that mask
+	RasterizeText(face string, size float32, text string) (*image.Alpha, matrix.Vec2, error)
+}
+
+// SetFont sets the face and size (in pixels) of the text drawn after, an
+// empty face uses the default font of the text renderer
+func (c *Context) SetFont(face string, size matrix.Float) {
+	c.state.font = face
+	if size > 0 {
+		c.state.fontSize = size
+	}
+}
+
+func (c *Context) SetTextAlign(align TextAlign)          { c.state.textAlign = align }
+func (c *Context) SetTextBaseline(baseline TextBaseline) { c.state.textBaseline = baseline }
+
+// MeasureText is the width of the text in the current font
+func (c *Context) MeasureText(text string) matrix.Float {
+	if c.text == nil {
+		return 0
+	}
+	return matrix.Float(c.text.MeasureText(c.state.font, float32(c.state.fontSize), text))
+}
+
+// FillText draws a line of text with the fill style, x and y being where
+// the text is placed by the text align and baseline
+func (c *Context) FillText(text string, x, y matrix.Float) {
+	if c.text == nil || text == "" {
+		return
+	}
+	inv, ok := c.state.transform.inverse()
+	if !ok {
+		return
+	}
+	// The text is drawn at the size it ends up on the canvas so that scaled
+	// text stays sharp
+	scale := c.state.transform.scale()
+	size := c.state.fontSize
+	mask, origin, err := c.text.RasterizeText(c.state.font, float32(size*scale), text)
+	if err != nil {
+		slog.Error("failed to draw the canvas text", "text", text, "error", err)
+		return
+	}
+	if mask == nil || mask.Rect.Empty() {
+		return
+	}
+	switch c.state.textAlign {
+	case TextAlignCenter:
+		x -= c.MeasureText(text) / 2
+	case TextAlignEnd, TextAlignRight:
+		x -= c.MeasureText(text)
+	}
+	// Without the metrics of the font, the baselines other than the
+	// alphabetic one are placed at the usual proportions of the em
+	switch c.state.textBaseline {
+	case TextBaselineTop, TextBaselineHanging:
+		y += size * 0.8
+	case TextBaselineMiddle:
+		y += size * 0.3
+	case TextBaselineIdeographic, TextBaselineBottom:
+		y -= size * 0.2
+	}
+	ox, oy := matrix.Float(origin.X()), matrix.Float(origin.Y())
+	w, h := matrix.Float(mask.Rect.Dx()), matrix.Float(mask.Rect.Dy())
+	// Where the pixels of the mask are on the canvas, and back again
+	toCanvas := c.state.transform.multiply(affine{1 / scale, 0, 0, 1 / scale, x - ox/scale, y - oy/scale})
+	toMask := affine{scale, 0, 0, scale, ox - x*scale, oy - y*scale}.multiply(inv)
+	fill := c.state.fill.source(c.state.transform)
+	c.composite([][]point{{toCanvas.apply(0, 0), toCanvas.apply(w, 0),
+		toCanvas.apply(w, h), toCanvas.apply(0, h)}}, func(x, y matrix.Float) matrix.Color {
+		col := fill(x, y)
+		p := toMask.apply(x, y)
+		col[3] *= sampleAlpha(mask, p.x, p.y)
+		return col
+	}, false)
+}
+
+// sampleAlpha bilinearly reads the mask at a point measured in its pixels
+func sampleAlpha(mask *image.Alpha, x, y matrix.Float) matrix.Float {
+	x, y = x-0.5, y-0.5
+	x0, y0 := int(matrix.Floor(x)), int(matrix.Floor(y))
+	fx, fy := x-matrix.Float(x0), y-matrix.Float(y0)
+	read := func(px, py int) matrix.Float {
+		if !(image.Point{px, py}).In(mask.Rect) {
+			return 0
+		}
+		return matrix.Float(mask.Pix[mask.PixOffset(px, py)]) / 255
+	}
+	top := matrix.Lerp(read(x0, y0), read(x0+1, y0), fx)
+	bottom := matrix.Lerp(read(x0, y0+1), read(x0+1, y0+1), fx)
+	return matrix.Lerp(top, bottom, fy)
+}
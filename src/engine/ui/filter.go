@@ -0,0 +1,198 @@
+/******************************************************************************/
+/* filter.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"kaijuengine.com/matrix"
+)
+
+type FilterFunction = int
+
+const (
+	FilterBrightness = FilterFunction(iota)
+	FilterContrast
+	FilterGrayscale
+	FilterHueRotate
+	FilterInvert
+	FilterOpacity
+	FilterSaturate
+	FilterSepia
+)
+
+// Filter is a single CSS filter function. The amount is in degrees for
+// hue-rotate and a ratio (1 = 100%) for everything else
+type Filter struct {
+	Function FilterFunction
+	Amount   float32
+}
+
+// panelColors holds the colors a panel was given while a filter is rewriting
+// the colors that are sent to the shader
+type panelColors struct {
+	fill    matrix.Color
+	border  [4]matrix.Color
+	outline matrix.Color
+}
+
+type colorMatrix [3][3]float32
+
+func (m colorMatrix) apply(c matrix.Color) matrix.Color {
+	r, g, b := c.R(), c.G(), c.B()
+	return matrix.Color{
+		m[0][0]*r + m[0][1]*g + m[0][2]*b,
+		m[1][0]*r + m[1][1]*g + m[1][2]*b,
+		m[2][0]*r + m[2][1]*g + m[2][2]*b,
+		c.A(),
+	}
+}
+
+// The matrices below are the ones given by the filter effects spec for the
+// shorthand filter functions
+func grayscaleMatrix(amount float32) colorMatrix {
+	a := 1 - matrix.Clamp(amount, 0, 1)
+	return colorMatrix{
+		{0.2126 + 0.7874*a, 0.7152 - 0.7152*a, 0.0722 - 0.0722*a},
+		{0.2126 - 0.2126*a, 0.7152 + 0.2848*a, 0.0722 - 0.0722*a},
+		{0.2126 - 0.2126*a, 0.7152 - 0.7152*a, 0.0722 + 0.9278*a},
+	}
+}
+
+func sepiaMatrix(amount float32) colorMatrix {
+	a := 1 - matrix.Clamp(amount, 0, 1)
+	return colorMatrix{
+		{0.393 + 0.607*a, 0.769 - 0.769*a, 0.189 - 0.189*a},
+		{0.349 - 0.349*a, 0.686 + 0.314*a, 0.168 - 0.168*a},
+		{0.272 - 0.272*a, 0.534 - 0.534*a, 0.131 + 0.869*a},
+	}
+}
+
+func saturateMatrix(amount float32) colorMatrix {
+	s := max(amount, 0)
+	return colorMatrix{
+		{0.213 + 0.787*s, 0.715 - 0.715*s, 0.072 - 0.072*s},
+		{0.213 - 0.213*s, 0.715 + 0.285*s, 0.072 - 0.072*s},
+		{0.213 - 0.213*s, 0.715 - 0.715*s, 0.072 + 0.928*s},
+	}
+}
+
+func hueRotateMatrix(degrees float32) colorMatrix {
+	rad := matrix.Deg2Rad(degrees)
+	c, s := matrix.Cos(rad), matrix.Sin(rad)
+	return colorMatrix{
+		{0.213 + c*0.787 - s*0.213, 0.715 - c*0.715 - s*0.715, 0.072 - c*0.072 + s*0.928},
+		{0.213 - c*0.213 + s*0.143, 0.715 + c*0.285 + s*0.140, 0.072 - c*0.072 - s*0.283},
+		{0.213 - c*0.213 - s*0.787, 0.715 - c*0.715 + s*0.715, 0.072 + c*0.928 + s*0.072},
+	}
+}
+
+func clampColor(c matrix.Color) matrix.Color {
+	for i := range c {
+		c[i] = matrix.Clamp(c[i], 0, 1)
+	}
+	return c
+}
+
+// FilterColor runs the color filter functions over the given color in order
+func FilterColor(filters []Filter, c matrix.Color) matrix.Color {
+	for _, f := range filters {
+		switch f.Function {
+		case FilterBrightness:
+			a := max(f.Amount, 0)
+			c = matrix.Color{c.R() * a, c.G() * a, c.B() * a, c.A()}
+		case FilterContrast:
+			a := max(f.Amount, 0)
+			o := 0.5 - 0.5*a
+			c = matrix.Color{c.R()*a + o, c.G()*a + o, c.B()*a + o, c.A()}
+		case FilterGrayscale:
+			c = grayscaleMatrix(f.Amount).apply(c)
+		case FilterHueRotate:
+			c = hueRotateMatrix(f.Amount).apply(c)
+		case FilterInvert:
+			a := matrix.Clamp(f.Amount, 0, 1)
+			s := 1 - 2*a
+			c = matrix.Color{a + c.R()*s, a + c.G()*s, a + c.B()*s, c.A()}
+		case FilterOpacity:
+			c.SetA(c.A() * matrix.Clamp(f.Amount, 0, 1))
+		case FilterSaturate:
+			c = saturateMatrix(f.Amount).apply(c)
+		case FilterSepia:
+			c = sepiaMatrix(f.Amount).apply(c)
+		default:
+			continue
+		}
+		c = clampColor(c)
+	}
+	return c
+}
+
+// effectiveFilters collects the filters of this element followed by those of
+// its ancestors, which is the order they are applied in when compositing
+func (ui *UI) effectiveFilters() []Filter {
+	var filters []Filter
+	for e := &ui.entity; e != nil; e = e.Parent {
+		u := FirstOnEntity(e)
+		if u == nil {
+			break
+		}
+		if !u.IsType(ElementTypeLabel) {
+			filters = append(filters, u.ToPanel().PanelData().filters...)
+		}
+	}
+	return filters
+}
+
+func (p *Panel) Filters() []Filter { return p.PanelData().filters }
+
+// SetFilters sets the filter functions applied to this panel and everything
+// inside of it. They are applied to the fill, border, outline and shadow
+// colors of the panels and to the text colors of labels
+func (p *Panel) SetFilters(filters []Filter) {
+	p.PanelData().filters = filters
+	p.Base().SetDirty(DirtyTypeColorChange)
+}
+
+// refreshFilteredColors re-applies the filters in effect to the colors that
+// are sent to the shader, keeping the original colors aside while filtered
+func (p *Panel) refreshFilteredColors() {
+	pd := p.PanelData()
+	filters := p.Base().effectiveFilters()
+	if len(filters) == 0 && pd.unfiltered == nil {
+		return
+	}
+	colors := panelColors{
+		fill:    p.shaderData.FgColor,
+		border:  p.shaderData.BorderColor,
+		outline: p.shaderData.OutlineColor,
+	}
+	if pd.unfiltered != nil {
+		colors = *pd.unfiltered
+	}
+	if len(filters) == 0 {
+		pd.unfiltered = nil
+	} else {
+		pd.unfiltered = &colors
+	}
+	fill := FilterColor(filters, colors.fill)
+	if !p.shaderData.FgColor.Equals(fill) {
+		if pd.drawing.IsValid() {
+			p.writeFillColor(fill)
+		} else {
+			p.shaderData.FgColor = fill
+		}
+	}
+	for i := range colors.border {
+		p.shaderData.BorderColor[i] = FilterColor(filters, colors.border[i])
+	}
+	p.shaderData.OutlineColor = FilterColor(filters, colors.outline)
+}
+
+func (p *Panel) filterColor(c matrix.Color) matrix.Color {
+	if p.PanelData().unfiltered == nil {
+		return c
+	}
+	return FilterColor(p.Base().effectiveFilters(), c)
+}
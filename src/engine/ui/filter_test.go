@@ -0,0 +1,65 @@
+/******************************************************************************/
+/* filter_test.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"testing"
+
+	"kaijuengine.com/matrix"
+)
+
+func colorApprox(a, b matrix.Color) bool {
+	for i := range a {
+		if !matrix.ApproxTo(a[i], b[i], 0.001) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFilterColor(t *testing.T) {
+	c := matrix.Color{0.2, 0.4, 0.6, 1}
+	tests := []struct {
+		name    string
+		filters []Filter
+		want    matrix.Color
+	}{
+		{"none", nil, c},
+		{"brightness", []Filter{{FilterBrightness, 2}}, matrix.Color{0.4, 0.8, 1, 1}},
+		{"contrast", []Filter{{FilterContrast, 0}}, matrix.Color{0.5, 0.5, 0.5, 1}},
+		{"invert", []Filter{{FilterInvert, 1}}, matrix.Color{0.8, 0.6, 0.4, 1}},
+		{"opacity", []Filter{{FilterOpacity, 0.25}}, matrix.Color{0.2, 0.4, 0.6, 0.25}},
+		{"grayscale none", []Filter{{FilterGrayscale, 0}}, c},
+		{"saturate identity", []Filter{{FilterSaturate, 1}}, c},
+		{"sepia none", []Filter{{FilterSepia, 0}}, c},
+		{"hue identity", []Filter{{FilterHueRotate, 360}}, c},
+	}
+	for _, test := range tests {
+		if got := FilterColor(test.filters, c); !colorApprox(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestFilterColorGrayscale(t *testing.T) {
+	got := FilterColor([]Filter{{FilterGrayscale, 1}}, matrix.Color{1, 0, 0, 1})
+	if !matrix.ApproxTo(got.R(), got.G(), 0.001) || !matrix.ApproxTo(got.G(), got.B(), 0.001) {
+		t.Fatalf("expected a gray color, got %v", got)
+	}
+	if !matrix.ApproxTo(got.R(), 0.2126, 0.001) {
+		t.Fatalf("expected red to map to its luminance, got %v", got)
+	}
+}
+
+func TestFilterColorOrder(t *testing.T) {
+	c := matrix.Color{0.5, 0.5, 0.5, 1}
+	a := FilterColor([]Filter{{FilterBrightness, 2}, {FilterInvert, 1}}, c)
+	b := FilterColor([]Filter{{FilterInvert, 1}, {FilterBrightness, 2}}, c)
+	if !colorApprox(a, matrix.Color{0, 0, 0, 1}) || !colorApprox(b, matrix.Color{1, 1, 1, 1}) {
+		t.Fatalf("expected filters to apply in order, got %v and %v", a, b)
+	}
+}
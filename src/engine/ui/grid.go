@@ -0,0 +1,461 @@
+/******************************************************************************/
+/* grid.go                                                                    */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import "slices"
+
+type GridAutoFlow = int
+
+const (
+	GridAutoFlowRow = GridAutoFlow(iota)
+	GridAutoFlowColumn
+)
+
+// GridLine describes one edge of a grid item. The zero value is auto. Line
+// is 1-based and negative values count back from the end of the explicit
+// grid. When Name is set, Line selects the nth line with that name (the
+// first if Line is 0). When Span is set, the edge spans that many tracks, or
+// that many lines called Name, from the opposite edge.
+type GridLine struct {
+	Line int
+	Name string
+	Span int
+}
+
+func (g GridLine) IsAuto() bool { return g.Line == 0 && g.Name == "" && g.Span == 0 }
+func (g GridLine) IsSpan() bool { return g.Span > 0 }
+
+type gridAxis struct {
+	tracks int
+	// Names for each of the tracks+1 lines, index 0 is line 1
+	names [][]string
+}
+
+type gridTrackPlacement struct {
+	pos      int
+	span     int
+	definite bool
+}
+
+type gridItemLines struct {
+	rowStart    GridLine
+	rowEnd      GridLine
+	columnStart GridLine
+	columnEnd   GridLine
+}
+
+type gridCellArea struct {
+	row     int
+	col     int
+	rowSpan int
+	colSpan int
+}
+
+type gridTrackItem struct {
+	pos  int
+	span int
+	size float32
+}
+
+type gridTemplate struct {
+	rows    gridAxis
+	columns gridAxis
+	flow    GridAutoFlow
+	dense   bool
+}
+
+// gridOccupancy is keyed by [major, minor] cell of the auto flow direction
+type gridOccupancy map[[2]int]bool
+
+func newGridAxis(tracks int, names [][]string) gridAxis {
+	a := gridAxis{tracks: tracks, names: make([][]string, tracks+1)}
+	for i := 0; i < len(names) && i < len(a.names); i++ {
+		a.names[i] = slices.Clone(names[i])
+	}
+	return a
+}
+
+// newGridTemplate builds the explicit grid, the template areas give names to
+// the lines around them ("area-start" and "area-end") like CSS
+func newGridTemplate(rows, columns int, rowNames, columnNames, areas [][]string) gridTemplate {
+	areaColumns := 0
+	for i := range areas {
+		areaColumns = max(areaColumns, len(areas[i]))
+	}
+	t := gridTemplate{
+		rows:    newGridAxis(max(rows, len(areas)), rowNames),
+		columns: newGridAxis(max(columns, areaColumns), columnNames),
+	}
+	bounds := map[string][4]int{}
+	order := []string{}
+	for r := range areas {
+		for c, name := range areas[r] {
+			if name == "" || name == "." {
+				continue
+			}
+			b, ok := bounds[name]
+			if !ok {
+				b = [4]int{r, c, r, c}
+				order = append(order, name)
+			}
+			b[0], b[1] = min(b[0], r), min(b[1], c)
+			b[2], b[3] = max(b[2], r), max(b[3], c)
+			bounds[name] = b
+		}
+	}
+	for _, name := range order {
+		b := bounds[name]
+		t.rows.addName(b[0], name+"-start")
+		t.rows.addName(b[2]+1, name+"-end")
+		t.columns.addName(b[1], name+"-start")
+		t.columns.addName(b[3]+1, name+"-end")
+	}
+	return t
+}
+
+func (a *gridAxis) addName(index int, name string) {
+	if !slices.Contains(a.names[index], name) {
+		a.names[index] = append(a.names[index], name)
+	}
+}
+
+func (a gridAxis) hasName(line int, name string) bool {
+	return line >= 1 && line <= len(a.names) && slices.Contains(a.names[line-1], name)
+}
+
+// namedLine finds the nth (negative counts from the end) line with the given
+// name. When there are not enough lines with the name, the implicit lines
+// after the explicit grid are all assumed to have it.
+func (a gridAxis) namedLine(name string, nth int) (int, bool) {
+	if nth == 0 {
+		nth = 1
+	}
+	lines := a.tracks + 1
+	if nth > 0 {
+		for l := 1; l <= lines; l++ {
+			if a.hasName(l, name) {
+				if nth--; nth == 0 {
+					return l, true
+				}
+			}
+		}
+		return lines + nth, false
+	}
+	for l := lines; l >= 1; l-- {
+		if a.hasName(l, name) {
+			if nth++; nth == 0 {
+				return l, true
+			}
+		}
+	}
+	return 1, false
+}
+
+func (a gridAxis) resolveLine(g GridLine, suffix string) (int, bool) {
+	switch {
+	case g.IsAuto() || g.IsSpan():
+		return 0, false
+	case g.Name != "":
+		if g.Line == 0 {
+			if l, ok := a.namedLine(g.Name+suffix, 1); ok {
+				return l, true
+			}
+		}
+		l, _ := a.namedLine(g.Name, g.Line)
+		return l, true
+	case g.Line > 0:
+		return g.Line, true
+	default:
+		return max(1, a.tracks+2+g.Line), true
+	}
+}
+
+// spanFrom counts the tracks covered by a span starting at the given line
+func (a gridAxis) spanFrom(g GridLine, from int, forward bool) int {
+	if g.Name == "" {
+		return g.Span
+	}
+	count := g.Span
+	lines := a.tracks + 1
+	if forward {
+		for l := from + 1; l <= lines; l++ {
+			if a.hasName(l, g.Name) {
+				if count--; count == 0 {
+					return l - from
+				}
+			}
+		}
+		return max(1, lines+count-from)
+	}
+	for l := from - 1; l >= 1; l-- {
+		if a.hasName(l, g.Name) {
+			if count--; count == 0 {
+				return from - l
+			}
+		}
+	}
+	return max(1, from-1)
+}
+
+// resolve turns a pair of lines into a 0-based track position and span,
+// definite is false when the position is left to the auto placement
+func (a gridAxis) resolve(start, end GridLine) gridTrackPlacement {
+	s, startOk := a.resolveLine(start, "-start")
+	e, endOk := a.resolveLine(end, "-end")
+	switch {
+	case startOk && endOk:
+		if e < s {
+			s, e = e, s
+		} else if e == s {
+			e = s + 1
+		}
+		return gridTrackPlacement{pos: s - 1, span: e - s, definite: true}
+	case startOk:
+		span := 1
+		if end.IsSpan() {
+			span = a.spanFrom(end, s, true)
+		}
+		return gridTrackPlacement{pos: s - 1, span: span, definite: true}
+	case endOk:
+		span := 1
+		if start.IsSpan() {
+			span = a.spanFrom(start, e, false)
+		}
+		s = max(1, e-span)
+		return gridTrackPlacement{pos: s - 1, span: max(1, e-s), definite: true}
+	}
+	// Named spans on an auto placed item count as a single track
+	span := 1
+	if start.IsSpan() && start.Name == "" {
+		span = start.Span
+	} else if end.IsSpan() && end.Name == "" && !start.IsSpan() {
+		span = end.Span
+	}
+	return gridTrackPlacement{span: span}
+}
+
+func (o gridOccupancy) free(major, minor, majorSpan, minorSpan int) bool {
+	for y := 0; y < majorSpan; y++ {
+		for x := 0; x < minorSpan; x++ {
+			if o[[2]int{major + y, minor + x}] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (o gridOccupancy) occupy(major, minor, majorSpan, minorSpan int) {
+	for y := 0; y < majorSpan; y++ {
+		for x := 0; x < minorSpan; x++ {
+			o[[2]int{major + y, minor + x}] = true
+		}
+	}
+}
+
+// place runs the CSS grid item placement algorithm. Items with a definite
+// position are placed first, then items locked to a row (or column for the
+// column flow), then the rest are auto placed creating implicit tracks as
+// needed. Items only locked to the cross axis do not move the sparse cursor,
+// so following items keep filling the earlier tracks.
+func (t gridTemplate) place(items []gridItemLines) (areas []gridCellArea, rows, columns int) {
+	majorAxis, minorAxis := t.rows, t.columns
+	if t.flow == GridAutoFlowColumn {
+		majorAxis, minorAxis = minorAxis, majorAxis
+	}
+	majors := make([]gridTrackPlacement, len(items))
+	minors := make([]gridTrackPlacement, len(items))
+	for i := range items {
+		r := t.rows.resolve(items[i].rowStart, items[i].rowEnd)
+		c := t.columns.resolve(items[i].columnStart, items[i].columnEnd)
+		if t.flow == GridAutoFlowColumn {
+			majors[i], minors[i] = c, r
+		} else {
+			majors[i], minors[i] = r, c
+		}
+	}
+	occupied := gridOccupancy{}
+	placed := make([]bool, len(items))
+	for i := range items {
+		if majors[i].definite && minors[i].definite {
+			occupied.occupy(majors[i].pos, minors[i].pos, majors[i].span, minors[i].span)
+			placed[i] = true
+		}
+	}
+	cursors := map[int]int{}
+	for i := range items {
+		if placed[i] || !majors[i].definite {
+			continue
+		}
+		minor := 0
+		if !t.dense {
+			minor = cursors[majors[i].pos]
+		}
+		for !occupied.free(majors[i].pos, minor, majors[i].span, minors[i].span) {
+			minor++
+		}
+		minors[i].pos = minor
+		occupied.occupy(majors[i].pos, minor, majors[i].span, minors[i].span)
+		cursors[majors[i].pos] = minor + minors[i].span
+		placed[i] = true
+	}
+	minorCount := max(minorAxis.tracks, 1)
+	for i := range items {
+		if placed[i] || minors[i].definite {
+			minorCount = max(minorCount, minors[i].pos+minors[i].span)
+		} else {
+			minorCount = max(minorCount, minors[i].span)
+		}
+	}
+	cursorMajor, cursorMinor := 0, 0
+	for i := range items {
+		if placed[i] {
+			continue
+		}
+		maj, mn := &majors[i], &minors[i]
+		if mn.definite {
+			major := cursorMajor
+			if t.dense {
+				major = 0
+			}
+			for !occupied.free(major, mn.pos, maj.span, mn.span) {
+				major++
+			}
+			maj.pos = major
+		} else {
+			if t.dense {
+				cursorMajor, cursorMinor = 0, 0
+			}
+			for {
+				if cursorMinor+mn.span > minorCount {
+					cursorMajor++
+					cursorMinor = 0
+				} else if occupied.free(cursorMajor, cursorMinor, maj.span, mn.span) {
+					break
+				} else {
+					cursorMinor++
+				}
+			}
+			maj.pos, mn.pos = cursorMajor, cursorMinor
+		}
+		occupied.occupy(maj.pos, mn.pos, maj.span, mn.span)
+	}
+	majorCount := majorAxis.tracks
+	areas = make([]gridCellArea, len(items))
+	for i := range items {
+		majorCount = max(majorCount, majors[i].pos+majors[i].span)
+		minorCount = max(minorCount, minors[i].pos+minors[i].span)
+		if t.flow == GridAutoFlowColumn {
+			areas[i] = gridCellArea{minors[i].pos, majors[i].pos, minors[i].span, majors[i].span}
+		} else {
+			areas[i] = gridCellArea{majors[i].pos, minors[i].pos, majors[i].span, minors[i].span}
+		}
+	}
+	if t.flow == GridAutoFlowColumn {
+		return areas, minorCount, majorCount
+	}
+	return areas, majorCount, minorCount
+}
+
+// sizeGridTracks resolves the size of count tracks. Template values follow
+// the panel convention where positive values are pixels, negative values are
+// fr units and zero sizes the track to its content. Tracks past the template
+// are at least autoSize and grow to their content. A negative available size
+// means the container is sized by its content, so fr tracks act like auto.
+func sizeGridTracks(template []float32, count int, autoSize float32, items []gridTrackItem, available, gap float32) []float32 {
+	sizes := make([]float32, count)
+	fr := make([]float32, count)
+	fitted := make([]bool, count)
+	for i := range count {
+		v := float32(0)
+		if i < len(template) {
+			v = template[i]
+		} else {
+			sizes[i] = max(autoSize, 0)
+		}
+		switch {
+		case v > 0:
+			sizes[i] = v
+		case v < 0 && available >= 0:
+			fr[i] = -v
+		default:
+			fitted[i] = true
+		}
+	}
+	for _, it := range items {
+		if it.span == 1 && it.pos < count && fitted[it.pos] {
+			sizes[it.pos] = max(sizes[it.pos], it.size)
+		}
+	}
+	// Spanning items grow the last content sized track they cover, unless
+	// they cross an fr track which already takes up the free space
+	for _, it := range items {
+		if it.span < 2 {
+			continue
+		}
+		total := float32(it.span-1) * gap
+		last := -1
+		for i := it.pos; i < it.pos+it.span && i < count; i++ {
+			total += sizes[i]
+			if fr[i] > 0 {
+				last = -1
+				break
+			} else if fitted[i] {
+				last = i
+			}
+		}
+		if last >= 0 && total < it.size {
+			sizes[last] += it.size - total
+		}
+	}
+	totalFr := float32(0)
+	used := float32(max(count-1, 0)) * gap
+	for i := range count {
+		totalFr += fr[i]
+		used += sizes[i]
+	}
+	if totalFr > 0 {
+		remaining := max(available-used, 0)
+		for i := range count {
+			if fr[i] > 0 {
+				sizes[i] = remaining * fr[i] / totalFr
+			}
+		}
+	}
+	return sizes
+}
+
+// gridTrackOffsets returns the start of each track relative to the first
+func gridTrackOffsets(sizes []float32, gap float32) []float32 {
+	offsets := make([]float32, len(sizes))
+	pos := float32(0)
+	for i := range sizes {
+		offsets[i] = pos
+		pos += sizes[i] + gap
+	}
+	return offsets
+}
+
+// gridSpanSize is the size of the tracks covered by a span, including gaps
+func gridSpanSize(sizes []float32, pos, span int, gap float32) float32 {
+	size := float32(0)
+	for i := pos; i < pos+span && i < len(sizes); i++ {
+		size += sizes[i]
+	}
+	return size + float32(max(span-1, 0))*gap
+}
+
+func gridAlignOffset(align FlexAlign, space, size float32) float32 {
+	switch align {
+	case FlexAlignCenter:
+		return (space - size) * 0.5
+	case FlexAlignEnd:
+		return space - size
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,182 @@
+/******************************************************************************/
+/* grid_test.go                                                               */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"testing"
+
+	"kaijuengine.com/matrix"
+)
+
+func autoGridItems(count int) []gridItemLines {
+	return make([]gridItemLines, count)
+}
+
+func TestGridPlaceAuto(t *testing.T) {
+	tmpl := newGridTemplate(0, 3, nil, nil, nil)
+	areas, rows, cols := tmpl.place(autoGridItems(5))
+	if rows != 2 || cols != 3 {
+		t.Fatalf("expected a 2x3 grid, got %dx%d", rows, cols)
+	}
+	want := []gridCellArea{{0, 0, 1, 1}, {0, 1, 1, 1}, {0, 2, 1, 1}, {1, 0, 1, 1}, {1, 1, 1, 1}}
+	for i := range want {
+		if areas[i] != want[i] {
+			t.Errorf("item %d: expected %v, got %v", i, want[i], areas[i])
+		}
+	}
+}
+
+func TestGridPlaceColumnFlow(t *testing.T) {
+	tmpl := newGridTemplate(2, 3, nil, nil, nil)
+	tmpl.flow = GridAutoFlowColumn
+	areas, rows, cols := tmpl.place(autoGridItems(3))
+	if rows != 2 || cols != 3 {
+		t.Fatalf("expected a 2x3 grid, got %dx%d", rows, cols)
+	}
+	want := []gridCellArea{{0, 0, 1, 1}, {1, 0, 1, 1}, {0, 1, 1, 1}}
+	for i := range want {
+		if areas[i] != want[i] {
+			t.Errorf("item %d: expected %v, got %v", i, want[i], areas[i])
+		}
+	}
+}
+
+func TestGridPlaceTemplateAreas(t *testing.T) {
+	tmpl := newGridTemplate(0, 0, nil, nil, [][]string{
+		{"head", "head", "head"},
+		{"side", "main", "main"},
+		{"foot", "foot", "foot"},
+	})
+	items := []gridItemLines{
+		{rowStart: GridLine{Name: "main"}, rowEnd: GridLine{Name: "main"},
+			columnStart: GridLine{Name: "main"}, columnEnd: GridLine{Name: "main"}},
+		{rowStart: GridLine{Name: "head"}, rowEnd: GridLine{Name: "head"},
+			columnStart: GridLine{Name: "head"}, columnEnd: GridLine{Name: "head"}},
+		{rowStart: GridLine{Name: "foot"}, rowEnd: GridLine{Name: "foot"},
+			columnStart: GridLine{Name: "foot"}, columnEnd: GridLine{Name: "foot"}},
+		{},
+	}
+	areas, rows, cols := tmpl.place(items)
+	if rows != 3 || cols != 3 {
+		t.Fatalf("expected a 3x3 grid, got %dx%d", rows, cols)
+	}
+	want := []gridCellArea{{1, 1, 1, 2}, {0, 0, 1, 3}, {2, 0, 1, 3}, {1, 0, 1, 1}}
+	for i := range want {
+		if areas[i] != want[i] {
+			t.Errorf("item %d: expected %v, got %v", i, want[i], areas[i])
+		}
+	}
+}
+
+func TestGridResolveLines(t *testing.T) {
+	axis := newGridAxis(4, [][]string{{"full-start"}, {"main"}, nil, {"main"}, {"full-end"}})
+	tests := []struct {
+		name       string
+		start, end GridLine
+		want       gridTrackPlacement
+	}{
+		{"auto", GridLine{}, GridLine{}, gridTrackPlacement{span: 1}},
+		{"line", GridLine{Line: 2}, GridLine{}, gridTrackPlacement{1, 1, true}},
+		{"negative", GridLine{Line: 1}, GridLine{Line: -1}, gridTrackPlacement{0, 4, true}},
+		{"reversed", GridLine{Line: 4}, GridLine{Line: 2}, gridTrackPlacement{1, 2, true}},
+		{"span end", GridLine{Line: 2}, GridLine{Span: 2}, gridTrackPlacement{1, 2, true}},
+		{"span start", GridLine{Span: 2}, GridLine{Line: 5}, gridTrackPlacement{2, 2, true}},
+		{"auto span", GridLine{Span: 3}, GridLine{}, gridTrackPlacement{span: 3}},
+		{"named", GridLine{Name: "full"}, GridLine{Name: "full"}, gridTrackPlacement{0, 4, true}},
+		{"nth named", GridLine{Name: "main", Line: 2}, GridLine{}, gridTrackPlacement{3, 1, true}},
+		{"named span", GridLine{Line: 1}, GridLine{Name: "main", Span: 2}, gridTrackPlacement{0, 3, true}},
+		{"missing name", GridLine{Name: "nope"}, GridLine{}, gridTrackPlacement{5, 1, true}},
+	}
+	for _, test := range tests {
+		if got := axis.resolve(test.start, test.end); got != test.want {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestGridPlaceImplicitTracks(t *testing.T) {
+	tmpl := newGridTemplate(1, 2, nil, nil, nil)
+	items := []gridItemLines{
+		{columnStart: GridLine{Line: 4}},
+		{rowStart: GridLine{Line: 3}, columnStart: GridLine{Span: 3}},
+	}
+	areas, rows, cols := tmpl.place(items)
+	if rows != 3 || cols != 4 {
+		t.Fatalf("expected an implicit 3x4 grid, got %dx%d", rows, cols)
+	}
+	if areas[0] != (gridCellArea{0, 3, 1, 1}) {
+		t.Errorf("expected the first item in the implicit column, got %v", areas[0])
+	}
+	if areas[1] != (gridCellArea{2, 0, 1, 3}) {
+		t.Errorf("expected the second item on the implicit row, got %v", areas[1])
+	}
+}
+
+func TestGridPlaceDense(t *testing.T) {
+	items := []gridItemLines{
+		{},
+		{columnStart: GridLine{Span: 2}},
+		{},
+		{},
+	}
+	sparse := newGridTemplate(0, 2, nil, nil, nil)
+	areas, _, _ := sparse.place(items)
+	// The wide item can't fit beside the first so leaves a hole behind
+	if areas[2] != (gridCellArea{2, 0, 1, 1}) {
+		t.Errorf("expected sparse packing to leave a hole, got %v", areas[2])
+	}
+	dense := sparse
+	dense.dense = true
+	areas, _, _ = dense.place(items)
+	if areas[2] != (gridCellArea{0, 1, 1, 1}) {
+		t.Errorf("expected dense packing to fill the hole, got %v", areas[2])
+	}
+	if areas[3] != (gridCellArea{2, 0, 1, 1}) {
+		t.Errorf("expected dense packing to continue after the wide item, got %v", areas[3])
+	}
+}
+
+func TestGridPlaceColumnLockedKeepsCursor(t *testing.T) {
+	tmpl := newGridTemplate(0, 3, nil, nil, nil)
+	items := []gridItemLines{{columnStart: GridLine{Line: 3}}, {}, {}, {}}
+	areas, _, _ := tmpl.place(items)
+	want := []gridCellArea{{0, 2, 1, 1}, {0, 0, 1, 1}, {0, 1, 1, 1}, {1, 0, 1, 1}}
+	for i := range want {
+		if areas[i] != want[i] {
+			t.Errorf("item %d: expected %v, got %v", i, want[i], areas[i])
+		}
+	}
+}
+
+func TestSizeGridTracks(t *testing.T) {
+	items := []gridTrackItem{{0, 1, 30}, {1, 1, 50}, {0, 3, 200}, {0, 2, 100}}
+	sizes := sizeGridTracks([]float32{0, 40, -1}, 4, 20, items, 300, 10)
+	// The auto track fits its items, the fr track takes what's left, and the
+	// implicit track uses the auto size
+	want := []float32{50, 40, 160, 20}
+	for i := range want {
+		if !matrix.Approx(sizes[i], want[i]) {
+			t.Fatalf("expected %v, got %v", want, sizes)
+		}
+	}
+	sizes = sizeGridTracks([]float32{0, -1}, 2, 0, items[:2], -1, 0)
+	if !matrix.Approx(sizes[1], 50) {
+		t.Fatalf("expected an fr track of a fitted container to fit its content, got %v", sizes)
+	}
+}
+
+func TestGridAlignOffset(t *testing.T) {
+	if got := gridAlignOffset(FlexAlignCenter, 100, 40); !matrix.Approx(got, 30) {
+		t.Errorf("expected center offset 30, got %f", got)
+	}
+	if got := gridAlignOffset(FlexAlignEnd, 100, 40); !matrix.Approx(got, 60) {
+		t.Errorf("expected end offset 60, got %f", got)
+	}
+	if got := gridAlignOffset(FlexAlignStretch, 100, 40); got != 0 {
+		t.Errorf("expected stretch to start aligned, got %f", got)
+	}
+}
@@ -285,15 +285,21 @@ func (input *Input) change() { (*UI)(input).requestEvent(EventTypeChange) }
 func (input *Input) charX(index int) float32 {
 	data := input.InputData()
 	left := horizontalPadding
-	strWidth := float32(0)
-	tmp := editableTextSlice(data.label.LabelData().text, 0, index)
-	if len(tmp) == 0 {
-		strWidth = 0
-	} else {
-		host := input.man.Value().Host
-		strWidth = host.FontCache().MeasureString(data.label.LabelData().fontFace, tmp, data.label.LabelData().fontSize)
+	if data.label.LabelData().textLength == 0 {
+		return left
+	}
+	pos := rendering.CaretPositions(input.textLine(), data.label.LabelData().textLength)
+	return left + pos[editableTextClamp(index, 0, len(pos)-1)]
+}
+
+// textLine is the displayed text laid out on a single line, it is in visual
+// order so the caret can follow the bidirectional runs of the text
+func (input *Input) textLine() rendering.TextLine {
+	lines := input.InputData().label.layoutLines(0)
+	if len(lines) == 0 {
+		return rendering.TextLine{}
 	}
-	return left + strWidth
+	return lines[0]
 }
 
 func (input *Input) setBgColors() {
@@ -395,17 +401,30 @@ func (input *Input) findNextBreak(start, dir int) int {
 func (input *Input) arrowMoveCursor(kb *hid.Keyboard, dir int) {
 	data := input.InputData()
 	currentPos := data.cursorOffset
-	newPos := data.cursorOffset + dir
+	// The arrow keys move visually, jumps to the ends or between words go
+	// the logical way the arrow points in for the direction of the text
+	logicalDir := dir
+	if input.textLine().Direction == rendering.TextDirectionRTL {
+		logicalDir = -dir
+	}
+	newPos := rendering.CaretOffsetBeside(input.textLine(),
+		data.label.LabelData().textLength, currentPos, dir)
 	if kb.HasMeta() {
-		if dir < 0 {
+		if logicalDir < 0 {
 			newPos = 0
 		} else {
 			newPos = editableTextRuneCount(data.text)
 		}
 	} else if kb.HasCtrl() || kb.HasAlt() {
-		newPos = input.findNextBreak(newPos, dir)
+		newPos = input.findNextBreak(currentPos+logicalDir, logicalDir)
 	}
 	input.moveCursor(newPos)
+	if data.cursorOffset != currentPos {
+		dir = 1
+		if data.cursorOffset < currentPos {
+			dir = -1
+		}
+	}
 	if kb.HasShift() {
 		if currentPos != data.cursorOffset {
 			start := data.selectStart
@@ -482,8 +501,7 @@ func (input *Input) pointerPosWithin() int {
 		ws := input.entity.Transform.WorldScale()
 		pos.SetX(pos.X() - (wp.X() - ws.X()*0.5) - horizontalPadding)
 		pos.SetY(pos.Y() - (wp.Y() - ws.Y()*0.5))
-		return host.FontCache().PointOffsetWithin(
-			ld.fontFace, ld.text, pos, ld.fontSize, data.label.MaxWidth())
+		return rendering.CaretOffsetAt(input.textLine(), ld.textLength, pos.X())
 	}
 }
 
@@ -850,6 +868,12 @@ func (input *Input) SetFGColor(newColor matrix.Color) {
 	data.placeholder.SetColor(phColor)
 }
 
+// SetPlaceholderColor overrides the placeholder text color, which otherwise
+// follows the foreground color set with SetFGColor
+func (input *Input) SetPlaceholderColor(newColor matrix.Color) {
+	input.InputData().placeholder.SetColor(newColor)
+}
+
 func (input *Input) SetBGColor(newColor matrix.Color) {
 	data := input.InputData()
 	(*Panel)(input).SetColor(newColor)
@@ -39,6 +39,11 @@ type labelData struct {
 	diffScore         int
 	runeShaderData    []*rendering.TextShaderData
 	runeDrawings      []rendering.Drawing
+	runeSources       []int
+	textLayout        rendering.TextLayout
+	textShadows       []TextShadow
+	shadowLayers      []*textShadowLayer
+	pxRange           matrix.Vec2
 	fontFace          rendering.FontFace
 	lastRenderWidth   float32
 	unEnforcedFGColor matrix.Color
@@ -118,6 +123,11 @@ func (label *Label) activateDrawings() {
 	for i := range ld.runeDrawings {
 		ld.runeDrawings[i].ShaderData.Activate()
 	}
+	for _, layer := range ld.shadowLayers {
+		for _, sd := range layer.shaderData {
+			sd.Activate()
+		}
+	}
 }
 
 func (label *Label) deactivateDrawings() {
@@ -125,17 +135,26 @@ func (label *Label) deactivateDrawings() {
 	for i := range ld.runeDrawings {
 		ld.runeDrawings[i].ShaderData.Deactivate()
 	}
+	for _, layer := range ld.shadowLayers {
+		for _, sd := range layer.shaderData {
+			sd.Deactivate()
+		}
+	}
 }
 
 func (label *Label) FontFace() rendering.FontFace { return label.LabelData().fontFace }
 
 func (label *Label) colorRange(section colorRange) {
 	ld := label.LabelData()
-	end := len(ld.runeShaderData)
+	if len(ld.runeShaderData) <= section.end {
+		return
+	}
 	fg, bg := label.resolveFontColors(section.hue, section.bgHue)
-	for i := section.start; i < section.end && end > section.end; i++ {
-		ld.runeShaderData[i].FgColor = fg
-		ld.runeShaderData[i].BgColor = bg
+	for i, src := range ld.runeSources {
+		if src >= section.start && src < section.end {
+			ld.runeShaderData[i].FgColor = fg
+			ld.runeShaderData[i].BgColor = bg
+		}
 	}
 }
 
@@ -146,6 +165,8 @@ func (label *Label) clearDrawings() {
 	}
 	ld.runeShaderData = ld.runeShaderData[:0]
 	ld.runeDrawings = ld.runeDrawings[:0]
+	ld.runeSources = ld.runeSources[:0]
+	label.clearTextShadows()
 }
 
 func (label *Label) labelPostLayoutUpdate() {
@@ -170,9 +191,74 @@ func (label *Label) updateHeight(maxWidth float32) {
 }
 
 func (label *Label) measure(maxWidth float32) matrix.Vec2 {
+	return label.measureLines(label.layoutLines(maxWidth))
+}
+
+func (label *Label) measureLines(lines []rendering.TextLine) matrix.Vec2 {
 	ld := label.LabelData()
-	return label.man.Value().Host.FontCache().MeasureStringWithinWithLetterSpacing(ld.fontFace,
-		ld.text, ld.fontSize, maxWidth, ld.lineHeight, ld.letterSpacing)
+	fc := label.man.Value().Host.FontCache()
+	if IsVerticalWritingMode(label.writingMode()) {
+		return fc.MeasureVerticalTextLines(ld.fontFace, lines, ld.fontSize, ld.lineHeight)
+	}
+	return fc.MeasureTextLines(ld.fontFace, lines, ld.fontSize, ld.lineHeight)
+}
+
+// parentPanel is the panel the label is laid out in, the direction and
+// writing mode of the text come from it
+func (label *Label) parentPanel() *Panel {
+	if label.entity.Parent == nil {
+		return nil
+	}
+	p := FirstOnEntity(label.entity.Parent)
+	if p == nil || p.IsType(ElementTypeLabel) {
+		return nil
+	}
+	return p.ToPanel()
+}
+
+func (label *Label) writingMode() WritingMode {
+	if p := label.parentPanel(); p != nil {
+		return p.ResolvedWritingMode()
+	}
+	return WritingModeHorizontalTB
+}
+
+func (label *Label) layoutLines(maxWidth float32) []rendering.TextLine {
+	ld := label.LabelData()
+	layout := ld.textLayout
+	layout.MaxWidth = maxWidth
+	layout.LetterSpacing = ld.letterSpacing
+	fc := label.man.Value().Host.FontCache()
+	if p := label.parentPanel(); p != nil {
+		if IsVerticalWritingMode(p.ResolvedWritingMode()) {
+			// Columns don't wrap, they are as tall as the text in them
+			layout.MaxWidth = 0
+			return fc.LayoutVerticalText(ld.fontFace, ld.text, ld.fontSize,
+				ld.lineHeight, layout)
+		}
+		if d := p.ResolvedDirection(); d != DirectionInherit {
+			layout.Direction = textDirection(d)
+		}
+	}
+	return fc.LayoutText(ld.fontFace, ld.text, ld.fontSize, layout)
+}
+
+// renderLines creates the drawings for the lines from layoutLines in the
+// given colors, vertical writing modes draw them as columns
+func (label *Label) renderLines(lines []rendering.TextLine, maxWidth float32,
+	fg, bg matrix.Color) ([]rendering.Drawing, []int) {
+	ld := label.LabelData()
+	host := label.man.Value().Host
+	if mode := label.writingMode(); IsVerticalWritingMode(mode) {
+		return host.FontCache().RenderVerticalTextLines(host, lines, 0, 0, 0,
+			ld.fontSize, fg, bg, mode == WritingModeVerticalRL,
+			label.entity.Transform.WorldScale(), true, false, ld.fontFace,
+			ld.lineHeight, &host.Cameras.UI)
+	}
+	return host.FontCache().RenderTextLines(host, lines, 0, 0, 0, ld.fontSize,
+		maxWidth, fg, bg, ld.justify, ld.baseline,
+		label.entity.Transform.WorldScale(), true, false, ld.fontFace,
+		ld.lineHeight, &host.Cameras.UI)
 }
 
 func (label *Label) renderText() {
@@ -204,25 +290,25 @@ func (label *Label) renderText() {
 				}
 			}
 		}
+		host := label.man.Value().Host
+		lines := label.layoutLines(maxWidth)
 		if !label.layout.stylizerControlsHeight() {
-			label.layout.ScaleHeight(label.measure(maxWidth).Height())
+			label.layout.ScaleHeight(label.measureLines(lines).Height())
 		}
-		host := label.man.Value().Host
+		// Shadows are added first so that they are drawn below the text
+		label.renderTextShadows(lines, maxWidth)
 		// Resolve against the calculated surface so the font cache picks the
 		// crisp non-OIT material (both colors opaque) instead of fringing the
 		// edges over solid backgrounds.
 		fg, bg := label.resolveFontColors(ld.fgColor, ld.bgColor)
-		ld.runeDrawings = host.FontCache().RenderMeshesWithLetterSpacing(
-			host, ld.text, 0, 0, 0, ld.fontSize,
-			maxWidth, fg, bg, ld.justify,
-			ld.baseline, label.entity.Transform.WorldScale(),
-			true, false, ld.fontFace, ld.lineHeight, ld.letterSpacing, &host.Cameras.UI)
+		ld.runeDrawings, ld.runeSources = label.renderLines(lines, maxWidth, fg, bg)
 		ld.runeShaderData = make([]*rendering.TextShaderData, len(ld.runeDrawings))
 		for i := range ld.runeDrawings {
 			rd := &ld.runeDrawings[i]
 			rd.Transform = &label.entity.Transform
 			rd.Layer = rendering.RenderLayerUI
 			ld.runeShaderData[i] = rd.ShaderData.(*rendering.TextShaderData)
+			ld.pxRange = ld.runeShaderData[i].PxRange
 			if bg.A() < 1.0 {
 				transparent := ld.runeDrawings[i]
 				transparent.Material = host.FontCache().TransparentMaterial(
@@ -251,6 +337,7 @@ func (label *Label) labelRender() {
 	if ld.renderRequired {
 		label.renderText()
 	}
+	label.placeTextShadows()
 	label.setLabelScissors()
 	if !label.Base().IsActive() {
 		label.deactivateDrawings()
@@ -294,6 +381,10 @@ func (label *Label) calculatedSurface() matrix.Color {
 // that fill is invisible and the anti-aliased edges blend toward the correct
 // color with no halo.
 func (label *Label) resolveFontColors(fg, bg matrix.Color) (matrix.Color, matrix.Color) {
+	if filters := label.Base().effectiveFilters(); len(filters) > 0 {
+		fg = FilterColor(filters, fg)
+		bg = FilterColor(filters, bg)
+	}
 	if bg.A() >= 1.0 {
 		return fg, bg
 	}
@@ -308,6 +399,7 @@ func (label *Label) updateColors() {
 		ld.runeShaderData[i].FgColor = fg
 		ld.runeShaderData[i].BgColor = bg
 	}
+	label.updateTextShadows(label.Base().effectiveFilters())
 }
 
 func (label *Label) FontSize() float32 { return label.LabelData().fontSize }
@@ -344,6 +436,20 @@ func (label *Label) SetLetterSpacing(spacing float32) {
 
 func (label *Label) LetterSpacing() float32 { return label.LabelData().letterSpacing }
 
+// TextLayout returns the line breaking rules of the label, the max width and
+// letter spacing are filled in from the label when the text is laid out
+func (label *Label) TextLayout() rendering.TextLayout { return label.LabelData().textLayout }
+
+func (label *Label) SetTextLayout(layout rendering.TextLayout) {
+	ld := label.LabelData()
+	if ld.textLayout == layout {
+		return
+	}
+	ld.textLayout = layout
+	ld.renderRequired = true
+	label.Base().SetDirty(DirtyTypeGenerated)
+}
+
 func (label *Label) Text() string { return label.LabelData().text }
 
 func (label *Label) SetText(text string) {
@@ -483,10 +589,7 @@ func (label *Label) MaxWidth() float32 {
 
 func (label *Label) SetWidthAutoHeight(width float32) {
 	defer tracing.NewRegion("Label.SetWidthAutoHeight").End()
-	ld := label.LabelData()
-	textSize := label.Base().man.Value().Host.FontCache().MeasureStringWithinWithLetterSpacing(
-		ld.fontFace, ld.text, ld.fontSize, width, ld.lineHeight, ld.letterSpacing)
-	label.layout.Scale(width, textSize.Y())
+	label.layout.Scale(width, label.measure(width).Y())
 }
 
 func (label *Label) findColorRange(start, end int) *colorRange {
@@ -631,6 +734,7 @@ func (label *Label) Clone(to *Label) {
 	to.SetFontSize(ld.fontSize)
 	to.SetLineHeight(ld.lineHeight)
 	to.SetLetterSpacing(ld.letterSpacing)
+	to.SetTextLayout(ld.textLayout)
 	to.SetMaxWidth(ld.overrideMaxWidth)
 	to.SetColor(ld.fgColor)
 	to.SetBGColor(ld.bgColor)
@@ -638,4 +742,5 @@ func (label *Label) Clone(to *Label) {
 	to.SetBaseline(ld.baseline)
 	// TODO:  Set font face?
 	to.SetWrap(ld.wordWrap)
+	to.SetTextShadows(slices.Clone(ld.textShadows))
 }
@@ -43,10 +43,10 @@ type Layout struct {
 	border           matrix.Vec4
 	padding          matrix.Vec4
 	margin           matrix.Vec4
-	gridRowStart     int
-	gridRowEnd       int
-	gridColumnStart  int
-	gridColumnEnd    int
+	gridRowStart     GridLine
+	gridRowEnd       GridLine
+	gridColumnStart  GridLine
+	gridColumnEnd    GridLine
 	flexGrow         float32
 	flexShrink       float32
 	flexBasis        float32
@@ -54,6 +54,7 @@ type Layout struct {
 	flexBasisPercent bool
 	flexOrder        int
 	alignSelf        FlexAlign
+	justifySelf      FlexAlign
 	positioning      Positioning
 	Stylizer         LayoutStylizer
 	runningStylizer  bool
@@ -78,10 +79,10 @@ func (l *Layout) ClearStyles() {
 	l.border = matrix.Vec4{}
 	l.padding = matrix.Vec4{}
 	l.margin = matrix.Vec4{}
-	l.gridRowStart = 0
-	l.gridRowEnd = 0
-	l.gridColumnStart = 0
-	l.gridColumnEnd = 0
+	l.gridRowStart = GridLine{}
+	l.gridRowEnd = GridLine{}
+	l.gridColumnStart = GridLine{}
+	l.gridColumnEnd = GridLine{}
 	l.flexGrow = 0
 	l.flexShrink = 1
 	l.flexBasis = 0
@@ -89,6 +90,7 @@ func (l *Layout) ClearStyles() {
 	l.flexBasisPercent = false
 	l.flexOrder = 0
 	l.alignSelf = FlexAlignAuto
+	l.justifySelf = FlexAlignAuto
 	l.positioning = PositioningStatic
 }
 
@@ -259,10 +261,10 @@ func (l *Layout) Border() matrix.Vec4      { return l.border }
 func (l *Layout) Padding() matrix.Vec4     { return l.padding }
 func (l *Layout) Margin() matrix.Vec4      { return l.margin }
 func (l *Layout) Offset() matrix.Vec2      { return matrix.Vec2{l.offset.X(), l.offset.Y()} }
-func (l *Layout) GridRowStart() int        { return l.gridRowStart }
-func (l *Layout) GridRowEnd() int          { return l.gridRowEnd }
-func (l *Layout) GridColumnStart() int     { return l.gridColumnStart }
-func (l *Layout) GridColumnEnd() int       { return l.gridColumnEnd }
+func (l *Layout) GridRowStart() int        { return l.gridRowStart.Line }
+func (l *Layout) GridRowEnd() int          { return l.gridRowEnd.Line }
+func (l *Layout) GridColumnStart() int     { return l.gridColumnStart.Line }
+func (l *Layout) GridColumnEnd() int       { return l.gridColumnEnd.Line }
 func (l *Layout) FlexGrow() float32        { return l.flexGrow }
 func (l *Layout) FlexShrink() float32      { return l.flexShrink }
 func (l *Layout) FlexBasis() float32       { return l.flexBasis }
@@ -270,6 +272,15 @@ func (l *Layout) FlexBasisAuto() bool      { return l.flexBasisAuto }
 func (l *Layout) FlexBasisPercent() bool   { return l.flexBasisPercent }
 func (l *Layout) FlexOrder() int           { return l.flexOrder }
 func (l *Layout) AlignSelf() FlexAlign     { return l.alignSelf }
+func (l *Layout) JustifySelf() FlexAlign   { return l.justifySelf }
+
+func (l *Layout) GridRowLines() (start, end GridLine) {
+	return l.gridRowStart, l.gridRowEnd
+}
+
+func (l *Layout) GridColumnLines() (start, end GridLine) {
+	return l.gridColumnStart, l.gridColumnEnd
+}
 
 func (l *Layout) SetFlexGrow(grow float32) {
 	if grow < 0 {
@@ -332,13 +343,25 @@ func (l *Layout) SetAlignSelf(align FlexAlign) {
 	l.ui.layoutChanged(DirtyTypeLayout)
 }
 
-func (l *Layout) SetGridRow(start, end int) {
-	if start < 0 {
-		start = 0
-	}
-	if end < 0 {
-		end = 0
+func (l *Layout) SetJustifySelf(align FlexAlign) {
+	if l.justifySelf == align {
+		return
 	}
+	l.justifySelf = align
+	l.ui.layoutChanged(DirtyTypeLayout)
+}
+
+func (l *Layout) SetGridRow(start, end int) {
+	l.SetGridRowLines(GridLine{Line: max(start, 0)}, GridLine{Line: max(end, 0)})
+}
+
+func (l *Layout) SetGridColumn(start, end int) {
+	l.SetGridColumnLines(GridLine{Line: max(start, 0)}, GridLine{Line: max(end, 0)})
+}
+
+// SetGridRowLines places the element between the given row lines, see
+// GridLine for how numbered, named and spanning lines are described
+func (l *Layout) SetGridRowLines(start, end GridLine) {
 	if l.gridRowStart == start && l.gridRowEnd == end {
 		return
 	}
@@ -347,13 +370,9 @@ func (l *Layout) SetGridRow(start, end int) {
 	l.ui.layoutChanged(DirtyTypeLayout)
 }
 
-func (l *Layout) SetGridColumn(start, end int) {
-	if start < 0 {
-		start = 0
-	}
-	if end < 0 {
-		end = 0
-	}
+// SetGridColumnLines places the element between the given column lines, see
+// GridLine for how numbered, named and spanning lines are described
+func (l *Layout) SetGridColumnLines(start, end GridLine) {
 	if l.gridColumnStart == start && l.gridColumnEnd == end {
 		return
 	}
@@ -475,6 +494,11 @@ func layoutFloating(self *Layout) {
 	s := self.PixelSize()
 	bounds := self.bounds()
 	pos := anchorTopLeft(self, bounds.X(), bounds.Y(), s)
+	if !self.ui.IsType(ElementTypeLabel) {
+		offset := self.ui.ToPanel().PanelData().transform.layoutOffset(s)
+		pos.SetX(pos.X() + offset.X())
+		pos.SetY(pos.Y() + offset.Y())
+	}
 	pos.SetZ(self.z + 0.01)
 	t.SetPosition(pos.AsVec3())
 }
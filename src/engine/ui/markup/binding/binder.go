@@ -0,0 +1,608 @@
+/******************************************************************************/
+/* binder.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package binding
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+const (
+	bindPrefix      = "bind-"
+	bindEach        = "bind-each"
+	bindText        = "bind-text"
+	bindValue       = "bind-value"
+	bindClass       = "bind-class"
+	bindClassPrefix = "bind-class-"
+	bindStylePrefix = "bind-style-"
+	bindKeyPath     = "$key"
+	bindIndexPath   = "$index"
+)
+
+// Binder keeps a document up to date with a model. Only the elements that
+// read a changed value are patched, and the rows of a bind-each list are
+// matched by key so they are moved rather than recreated. The binder must be
+// unbound before the document is destroyed if the model outlives it
+//
+// The markup supports:
+//   - ${path} within text, replaced with the value at the path
+//   - bind-text="path" to set the text of the element
+//   - bind-value="path" two-way binding for inputs, checkboxes, selects,
+//     sliders and text areas
+//   - bind-class="path" for the class list and bind-class-name="path" to
+//     toggle a class by the truthiness of the value
+//   - bind-style-property="path" for a style property
+//   - bind-attribute="path" for any other attribute
+//   - bind-each="path" to repeat the element for each item of a *List,
+//     within it paths are read from the item first, $key and $index are the
+//     key and position of the item. The element itself is hidden and kept as
+//     the template of the rows
+type Binder struct {
+	doc        *document.Document
+	root       *scope
+	styleDirty bool
+	updating   int
+	unbound    bool
+	listeners  []boundListener
+}
+
+type boundListener struct {
+	elm *document.Element
+	id  document.ListenerId
+}
+
+type scope struct {
+	binder   *Binder
+	model    *Model
+	parent   *scope
+	key      string
+	index    int
+	isRow    bool
+	bindings []*valueBinding
+	lists    []*listBinding
+	children []*scope
+	observer ObserverId
+}
+
+type valueBinding struct {
+	elm     *document.Element
+	path    string
+	last    any
+	applied bool
+	apply   func(b *valueBinding, value any)
+	// read returns the value of the element, it is only set for two-way
+	// bindings
+	read func() (any, bool)
+	// parts are the literal and path parts of interpolated text
+	parts []textPart
+}
+
+type textPart struct {
+	text   string
+	isPath bool
+}
+
+type listBinding struct {
+	scope    *scope
+	template *document.Element
+	style    string
+	hasStyle bool
+	path     string
+	rows     map[string]*listRow
+}
+
+type listRow struct {
+	elm   *document.Element
+	scope *scope
+}
+
+// Bind connects the elements of the document to the model and fills in
+// their values. Elements added to the document afterwards are not bound
+func Bind(doc *document.Document, model *Model) *Binder {
+	b := &Binder{doc: doc}
+	b.root = b.newScope(model, nil)
+	b.begin()
+	for _, elm := range doc.TopElements {
+		b.scan(elm, b.root)
+	}
+	b.root.refresh()
+	b.end()
+	return b
+}
+
+// Unbind stops the document from following the model
+func (b *Binder) Unbind() {
+	if b.unbound {
+		return
+	}
+	b.unbound = true
+	b.root.release()
+	for _, l := range b.listeners {
+		l.elm.RemoveEventListener(l.id)
+	}
+	b.listeners = nil
+}
+
+func (b *Binder) begin() { b.updating++ }
+
+// end applies the styles of the document once the outermost change is done
+// if any of the patches changed what the styles match
+func (b *Binder) end() {
+	b.updating--
+	if b.updating == 0 && b.styleDirty {
+		b.styleDirty = false
+		b.doc.ApplyStyles()
+	}
+}
+
+func (b *Binder) newScope(model *Model, parent *scope) *scope {
+	s := &scope{binder: b, parent: parent}
+	s.setModel(model)
+	if parent != nil {
+		parent.children = append(parent.children, s)
+	}
+	return s
+}
+
+// setModel changes the model the scope reads from, changes to lists nested
+// in the model are forwarded by it so they don't need observers of their own
+func (s *scope) setModel(model *Model) {
+	if s.model != nil {
+		s.model.Unobserve(s.observer)
+	}
+	s.model = model
+	s.observer = model.Observe(func(string) {
+		if s.binder.unbound {
+			return
+		}
+		s.binder.begin()
+		s.refresh()
+		s.binder.end()
+	})
+}
+
+func (s *scope) release() {
+	s.model.Unobserve(s.observer)
+	for _, c := range s.children {
+		c.release()
+	}
+	s.children = nil
+}
+
+func (s *scope) removeChild(child *scope) {
+	for i := range s.children {
+		if s.children[i] == child {
+			s.children = append(s.children[:i], s.children[i+1:]...)
+			return
+		}
+	}
+}
+
+// lookup reads the path from the closest scope that has it
+func (s *scope) lookup(path string) (any, bool) {
+	switch path {
+	case bindKeyPath, bindIndexPath:
+		for r := s; r != nil; r = r.parent {
+			if r.isRow {
+				if path == bindKeyPath {
+					return r.key, true
+				}
+				return r.index, true
+			}
+		}
+		return nil, false
+	}
+	for c := s; c != nil; c = c.parent {
+		if c.model.Has(path) {
+			return c.model.Get(path)
+		}
+	}
+	return nil, false
+}
+
+// owner is the model a two-way binding writes the path into
+func (s *scope) owner(path string) *Model {
+	for c := s; c != nil; c = c.parent {
+		if c.model.Has(path) {
+			return c.model
+		}
+	}
+	return s.model
+}
+
+// refresh re-reads the values of the scope and its child scopes (as they
+// fall back to the values of this one) and patches what changed
+func (s *scope) refresh() {
+	for _, v := range s.bindings {
+		v.refresh(s)
+	}
+	for _, l := range s.lists {
+		l.refresh()
+	}
+	for _, c := range s.children {
+		c.refresh()
+	}
+}
+
+func (v *valueBinding) refresh(s *scope) {
+	var value any
+	if v.parts != nil {
+		sb := strings.Builder{}
+		for _, p := range v.parts {
+			if !p.isPath {
+				sb.WriteString(p.text)
+			} else if val, ok := s.lookup(p.text); ok {
+				sb.WriteString(formatValue(val))
+			}
+		}
+		value = sb.String()
+	} else {
+		value, _ = s.lookup(v.path)
+	}
+	if v.applied && sameValue(v.last, value) {
+		return
+	}
+	v.last, v.applied = value, true
+	v.apply(v, value)
+}
+
+func (b *Binder) scan(elm *document.Element, s *scope) {
+	if elm.IsText() {
+		if parts := parseInterpolation(elm.Data); parts != nil {
+			s.bindings = append(s.bindings, &valueBinding{
+				elm:   elm,
+				parts: parts,
+				apply: applyText,
+			})
+		}
+		return
+	}
+	if path := elm.Attribute(bindEach); path != "" {
+		b.bindList(elm, s, path)
+		return
+	}
+	b.bindAttributes(elm, s)
+	for _, c := range elm.Children {
+		b.scan(c, s)
+	}
+}
+
+func (b *Binder) bindList(elm *document.Element, s *scope, path string) {
+	l := &listBinding{
+		scope:    s,
+		template: elm,
+		style:    elm.Attribute("style"),
+		hasStyle: elm.HasAttribute("style"),
+		path:     strings.TrimSpace(path),
+		rows:     map[string]*listRow{},
+	}
+	b.doc.SetElementStylePropertyWithoutApply(elm, "display", "none")
+	b.styleDirty = true
+	s.lists = append(s.lists, l)
+}
+
+// refresh patches the rows to match the items of the list. Rows of removed
+// keys are removed, new keys get a copy of the template and the remaining
+// rows are only moved when they are out of order
+func (l *listBinding) refresh() {
+	b := l.scope.binder
+	var items []ListItem
+	if v, ok := l.scope.lookup(l.path); ok {
+		if list, isList := v.(*List); isList && list != nil {
+			items = list.items
+		}
+	}
+	keep := make(map[string]struct{}, len(items))
+	for i := range items {
+		keep[items[i].Key] = struct{}{}
+	}
+	for key, row := range l.rows {
+		if _, ok := keep[key]; !ok {
+			l.scope.removeChild(row.scope)
+			row.scope.release()
+			b.doc.RemoveElementWithoutApplyStyles(row.elm)
+			delete(l.rows, key)
+			b.styleDirty = true
+		}
+	}
+	prev := l.template
+	for i, item := range items {
+		row, ok := l.rows[item.Key]
+		if !ok {
+			row = l.createRow(item, prev)
+			l.rows[item.Key] = row
+		} else {
+			if row.scope.model != item.Model {
+				row.scope.setModel(item.Model)
+			}
+			if parent := prev.Parent.Value(); parent != nil &&
+				parent.IndexOfChild(row.elm) != parent.IndexOfChild(prev)+1 {
+				b.doc.InsertElementAfterWithoutApplyStyles(row.elm, prev)
+				b.styleDirty = true
+			}
+		}
+		row.scope.index = i
+		prev = row.elm
+	}
+}
+
+func (l *listBinding) createRow(item ListItem, after *document.Element) *listRow {
+	b := l.scope.binder
+	elm := b.doc.DuplicateElementWithoutApplyStyles(l.template)
+	elm.RemoveAttribute(bindEach)
+	if l.hasStyle {
+		elm.SetAttribute("style", l.style)
+	} else {
+		elm.RemoveAttribute("style")
+	}
+	if elm.UI != nil {
+		elm.UI.Show()
+	}
+	b.doc.InsertElementAfterWithoutApplyStyles(elm, after)
+	b.styleDirty = true
+	s := b.newScope(item.Model, l.scope)
+	s.isRow = true
+	s.key = item.Key
+	b.scan(elm, s)
+	return &listRow{elm: elm, scope: s}
+}
+
+func (b *Binder) bindAttributes(elm *document.Element, s *scope) {
+	for _, attr := range elm.AttributeKeys() {
+		if !strings.HasPrefix(attr, bindPrefix) {
+			continue
+		}
+		path := strings.TrimSpace(elm.Attribute(attr))
+		v := &valueBinding{elm: elm, path: path}
+		switch {
+		case attr == bindText:
+			v.apply = applyInnerText
+		case attr == bindValue:
+			v.apply = applyValue
+			v.read = readValue(elm)
+			b.listenForValue(elm, s, path, v)
+		case attr == bindClass:
+			v.apply = func(v *valueBinding, value any) {
+				b.doc.SetElementClassesWithoutApply(v.elm, strings.Fields(formatValue(value))...)
+				b.styleDirty = true
+			}
+		case strings.HasPrefix(attr, bindClassPrefix):
+			class := strings.TrimPrefix(attr, bindClassPrefix)
+			v.apply = func(v *valueBinding, value any) {
+				b.toggleClass(v.elm, class, truthy(value))
+			}
+		case strings.HasPrefix(attr, bindStylePrefix):
+			property := strings.TrimPrefix(attr, bindStylePrefix)
+			v.apply = func(v *valueBinding, value any) {
+				b.doc.SetElementStylePropertyWithoutApply(v.elm, property, formatValue(value))
+				b.styleDirty = true
+			}
+		case attr == bindPrefix+"disabled":
+			v.apply = func(v *valueBinding, value any) {
+				b.doc.SetElementDisabled(v.elm, truthy(value))
+			}
+		default:
+			name := strings.TrimPrefix(attr, bindPrefix)
+			v.apply = func(v *valueBinding, value any) {
+				if value == nil || value == false {
+					v.elm.RemoveAttribute(name)
+				} else {
+					v.elm.SetAttribute(name, formatValue(value))
+				}
+				b.styleDirty = true
+			}
+		}
+		s.bindings = append(s.bindings, v)
+	}
+}
+
+func (b *Binder) toggleClass(elm *document.Element, class string, on bool) {
+	classes := strings.Fields(elm.Attribute("class"))
+	has := false
+	for i := range classes {
+		if classes[i] == class {
+			has = true
+			if !on {
+				classes = append(classes[:i], classes[i+1:]...)
+			}
+			break
+		}
+	}
+	if on == has {
+		return
+	}
+	if on {
+		classes = append(classes, class)
+	}
+	b.doc.SetElementClassesWithoutApply(elm, classes...)
+	b.styleDirty = true
+}
+
+// listenForValue writes the value of the element into the model when the
+// element changes it
+func (b *Binder) listenForValue(elm *document.Element, s *scope, path string, v *valueBinding) {
+	if v.read == nil {
+		return
+	}
+	id := elm.AddEventListener("change", func(*document.DOMEvent) {
+		if b.unbound {
+			return
+		}
+		value, ok := v.read()
+		if !ok {
+			return
+		}
+		// The element already shows the value, so applying it is skipped
+		v.last, v.applied = value, true
+		s.owner(path).SetPath(path, value)
+	}, false)
+	b.listeners = append(b.listeners, boundListener{elm: elm, id: id})
+}
+
+func applyText(v *valueBinding, value any) {
+	text := formatValue(value)
+	v.elm.Data = text
+	if v.elm.UI != nil && v.elm.UI.IsType(ui.ElementTypeLabel) {
+		v.elm.UI.ToLabel().SetText(text)
+	}
+}
+
+func applyInnerText(v *valueBinding, value any) {
+	text := formatValue(value)
+	if lbl := v.elm.InnerLabel(); lbl != nil {
+		lbl.SetText(text)
+		v.elm.Children[0].Data = text
+	}
+}
+
+func applyValue(v *valueBinding, value any) {
+	u := v.elm.UI
+	if u == nil {
+		return
+	}
+	switch u.Type() {
+	case ui.ElementTypeInput:
+		if input := u.ToInput(); input.Text() != formatValue(value) {
+			input.SetTextWithoutEvent(formatValue(value))
+		}
+	case ui.ElementTypeTextArea:
+		if area := u.ToTextArea(); area.Text() != formatValue(value) {
+			area.SetTextWithoutEvent(formatValue(value))
+		}
+	case ui.ElementTypeCheckbox:
+		if cb := u.ToCheckbox(); cb.IsChecked() != truthy(value) {
+			cb.SetCheckedWithoutEvent(truthy(value))
+		}
+	case ui.ElementTypeSelect:
+		if sel := u.ToSelect(); sel.Value() != formatValue(value) {
+			sel.PickOptionByLabelWithoutEvent(formatValue(value))
+		}
+	case ui.ElementTypeSlider:
+		if f, ok := toFloat(value); ok {
+			u.ToSlider().SetValueWithoutEvent(float32(f))
+		}
+	}
+}
+
+func readValue(elm *document.Element) func() (any, bool) {
+	u := elm.UI
+	if u == nil {
+		return nil
+	}
+	switch u.Type() {
+	case ui.ElementTypeInput:
+		return func() (any, bool) { return u.ToInput().Text(), true }
+	case ui.ElementTypeTextArea:
+		return func() (any, bool) { return u.ToTextArea().Text(), true }
+	case ui.ElementTypeCheckbox:
+		return func() (any, bool) { return u.ToCheckbox().IsChecked(), true }
+	case ui.ElementTypeSelect:
+		return func() (any, bool) { return u.ToSelect().Value(), true }
+	case ui.ElementTypeSlider:
+		return func() (any, bool) { return u.ToSlider().Value(), true }
+	}
+	return nil
+}
+
+// parseInterpolation splits text on its ${path} parts, it returns nil if
+// the text has none
+func parseInterpolation(text string) []textPart {
+	if !strings.Contains(text, "${") {
+		return nil
+	}
+	parts := []textPart{}
+	for {
+		start := strings.Index(text, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(text[start:], "}")
+		if end < 0 {
+			break
+		}
+		if start > 0 {
+			parts = append(parts, textPart{text: text[:start]})
+		}
+		path := strings.TrimSpace(text[start+2 : start+end])
+		parts = append(parts, textPart{text: path, isPath: true})
+		text = text[start+end+1:]
+	}
+	if text != "" {
+		parts = append(parts, textPart{text: text})
+	}
+	return parts
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != "" && v != "false"
+	case *List:
+		return v != nil && v.Len() > 0
+	}
+	if f, ok := toFloat(value); ok {
+		return f != 0
+	}
+	return true
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,82 @@
+/******************************************************************************/
+/* binder_test.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package binding
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseInterpolation(t *testing.T) {
+	if parseInterpolation("plain text") != nil {
+		t.Fatal("expected text without paths to not be interpolated")
+	}
+	parts := parseInterpolation("Hello ${ user.name }, you have ${count}${unclosed")
+	want := []textPart{
+		{text: "Hello "},
+		{text: "user.name", isPath: true},
+		{text: ", you have "},
+		{text: "count", isPath: true},
+		{text: "${unclosed"},
+	}
+	if !slices.Equal(parts, want) {
+		t.Fatalf("expected %v but got %v", want, parts)
+	}
+}
+
+func TestTruthy(t *testing.T) {
+	for _, v := range []any{nil, false, "", "false", 0, 0.0, uint8(0), NewList()} {
+		if truthy(v) {
+			t.Errorf("expected %#v to be false", v)
+		}
+	}
+	for _, v := range []any{true, "yes", 1, -2.5, NewModel()} {
+		if !truthy(v) {
+			t.Errorf("expected %#v to be true", v)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := map[string]any{
+		"":     nil,
+		"text": "text",
+		"3":    3,
+		"0.5":  float32(0.5),
+		"1.25": 1.25,
+		"true": true,
+	}
+	for want, v := range tests {
+		if got := formatValue(v); got != want {
+			t.Errorf("expected %q for %#v but got %q", want, v, got)
+		}
+	}
+}
+
+func TestScopeLookup(t *testing.T) {
+	b := &Binder{}
+	root := b.newScope(NewModelFrom(map[string]any{"title": "root", "name": "root"}), nil)
+	row := b.newScope(NewModelFrom(map[string]any{"name": "row"}), root)
+	row.isRow, row.key, row.index = true, "k", 3
+	if v, _ := row.lookup("name"); v != "row" {
+		t.Errorf("expected the row value, got %v", v)
+	}
+	if v, _ := row.lookup("title"); v != "root" {
+		t.Errorf("expected the parent value, got %v", v)
+	}
+	if v, _ := row.lookup(bindKeyPath); v != "k" {
+		t.Errorf("expected the row key, got %v", v)
+	}
+	if v, _ := row.lookup(bindIndexPath); v != 3 {
+		t.Errorf("expected the row index, got %v", v)
+	}
+	if _, ok := root.lookup(bindKeyPath); ok {
+		t.Error("expected no key outside of a row")
+	}
+	if row.owner("title") != root.model || row.owner("new") != row.model {
+		t.Error("expected values to be written to the model that has them")
+	}
+}
@@ -0,0 +1,295 @@
+/******************************************************************************/
+/* model.go                                                                   */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package binding
+
+import (
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type ObserverId = int
+
+type modelObserver struct {
+	id   ObserverId
+	call func(key string)
+}
+
+// Model is an observable set of named values that a document is bound to.
+// Values can be anything, a *Model value is a nested model that is read
+// with a dotted path ("user.name") and a *List value is rendered with
+// bind-each
+type Model struct {
+	values    map[string]any
+	observers []modelObserver
+	lastId    ObserverId
+	// forwards are the observers on nested models and lists that pass their
+	// changes on to the observers of this model
+	forwards map[string]func()
+}
+
+func NewModel() *Model {
+	return &Model{
+		values:   map[string]any{},
+		forwards: map[string]func(){},
+	}
+}
+
+// NewModelFrom creates a model holding a copy of the values
+func NewModelFrom(values map[string]any) *Model {
+	m := NewModel()
+	for k, v := range values {
+		m.Set(k, v)
+	}
+	return m
+}
+
+// Get reads the value at the dotted path, the second return is false if the
+// path doesn't lead to a value
+func (m *Model) Get(path string) (any, bool) {
+	key, rest, nested := strings.Cut(path, ".")
+	v, ok := m.values[key]
+	if !ok || !nested {
+		return v, ok
+	}
+	child, isModel := v.(*Model)
+	if !isModel || child == nil {
+		return nil, false
+	}
+	return child.Get(rest)
+}
+
+// Has returns true if the first key of the path is a value of this model
+func (m *Model) Has(path string) bool {
+	key, _, _ := strings.Cut(path, ".")
+	_, ok := m.values[key]
+	return ok
+}
+
+// Set changes the value of the key and notifies the observers if it is
+// different from what it was
+func (m *Model) Set(key string, value any) {
+	if old, ok := m.values[key]; ok && sameValue(old, value) {
+		return
+	}
+	if stop, ok := m.forwards[key]; ok {
+		stop()
+		delete(m.forwards, key)
+	}
+	m.values[key] = value
+	switch v := value.(type) {
+	case *Model:
+		if v != nil {
+			id := v.Observe(func(child string) { m.notify(key + "." + child) })
+			m.forwards[key] = func() { v.Unobserve(id) }
+		}
+	case *List:
+		if v != nil {
+			id := v.Observe(func() { m.notify(key) })
+			m.forwards[key] = func() { v.Unobserve(id) }
+		}
+	}
+	m.notify(key)
+}
+
+// SetPath changes the value at the dotted path, the models along the path
+// must already exist
+func (m *Model) SetPath(path string, value any) {
+	idx := strings.LastIndex(path, ".")
+	if idx < 0 {
+		m.Set(path, value)
+		return
+	}
+	v, ok := m.Get(path[:idx])
+	child, isModel := v.(*Model)
+	if !ok || !isModel || child == nil {
+		slog.Warn("failed to find the model to set the value in", "path", path)
+		return
+	}
+	child.Set(path[idx+1:], value)
+}
+
+// Delete removes the key from the model
+func (m *Model) Delete(key string) {
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+	if stop, ok := m.forwards[key]; ok {
+		stop()
+		delete(m.forwards, key)
+	}
+	delete(m.values, key)
+	m.notify(key)
+}
+
+// List returns the list at the key, creating it if there isn't one yet
+func (m *Model) List(key string) *List {
+	if l, ok := m.values[key].(*List); ok && l != nil {
+		return l
+	}
+	l := NewList()
+	m.Set(key, l)
+	return l
+}
+
+// Observe registers a function that is called with the (dotted) key of any
+// value that changes in the model or the models and lists nested in it
+func (m *Model) Observe(call func(key string)) ObserverId {
+	m.lastId++
+	m.observers = append(m.observers, modelObserver{id: m.lastId, call: call})
+	return m.lastId
+}
+
+func (m *Model) Unobserve(id ObserverId) {
+	for i := range m.observers {
+		if m.observers[i].id == id {
+			m.observers = slices.Delete(m.observers, i, i+1)
+			return
+		}
+	}
+}
+
+func (m *Model) notify(key string) {
+	// Observers may unobserve while they are being called
+	observers := slices.Clone(m.observers)
+	for i := range observers {
+		observers[i].call(key)
+	}
+}
+
+func sameValue(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	return ta == tb && ta.Comparable() && a == b
+}
+
+type listObserver struct {
+	id   ObserverId
+	call func()
+}
+
+// ListItem is an entry of a list, the key identifies the item across
+// changes so its elements are kept rather than recreated
+type ListItem struct {
+	Key   string
+	Model *Model
+}
+
+// List is an observable, ordered set of keyed models
+type List struct {
+	items     []ListItem
+	observers []listObserver
+	lastId    ObserverId
+}
+
+func NewList() *List { return &List{} }
+
+func (l *List) Len() int              { return len(l.items) }
+func (l *List) At(index int) ListItem { return l.items[index] }
+func (l *List) Items() []ListItem     { return slices.Clone(l.items) }
+
+// Index returns the position of the item with the key or -1
+func (l *List) Index(key string) int {
+	return slices.IndexFunc(l.items, func(item ListItem) bool { return item.Key == key })
+}
+
+// Get returns the model of the item with the key
+func (l *List) Get(key string) (*Model, bool) {
+	if i := l.Index(key); i >= 0 {
+		return l.items[i].Model, true
+	}
+	return nil, false
+}
+
+func (l *List) Append(key string, item *Model) {
+	l.Insert(len(l.items), key, item)
+}
+
+// Insert adds the item at the index, keys must be unique within the list
+func (l *List) Insert(index int, key string, item *Model) {
+	if l.Index(key) >= 0 {
+		slog.Warn("the key is already in the list", "key", key)
+		return
+	}
+	index = max(0, min(index, len(l.items)))
+	l.items = slices.Insert(l.items, index, ListItem{Key: key, Model: item})
+	l.notify()
+}
+
+func (l *List) Remove(key string) bool {
+	i := l.Index(key)
+	if i < 0 {
+		return false
+	}
+	l.items = slices.Delete(l.items, i, i+1)
+	l.notify()
+	return true
+}
+
+// Move changes the position of the item with the key
+func (l *List) Move(key string, index int) {
+	i := l.Index(key)
+	if i < 0 {
+		return
+	}
+	item := l.items[i]
+	l.items = slices.Delete(l.items, i, i+1)
+	index = max(0, min(index, len(l.items)))
+	l.items = slices.Insert(l.items, index, item)
+	l.notify()
+}
+
+// Replace sets all of the items of the list at once, items that keep their
+// key keep their elements
+func (l *List) Replace(items []ListItem) {
+	seen := make(map[string]struct{}, len(items))
+	l.items = l.items[:0]
+	for _, item := range items {
+		if _, ok := seen[item.Key]; ok {
+			slog.Warn("the key is already in the list", "key", item.Key)
+			continue
+		}
+		seen[item.Key] = struct{}{}
+		l.items = append(l.items, item)
+	}
+	l.notify()
+}
+
+func (l *List) Clear() {
+	if len(l.items) == 0 {
+		return
+	}
+	l.items = l.items[:0]
+	l.notify()
+}
+
+// Observe registers a function that is called when items are added,
+// removed or moved. Changes within the models of the items are observed on
+// the models themselves
+func (l *List) Observe(call func()) ObserverId {
+	l.lastId++
+	l.observers = append(l.observers, listObserver{id: l.lastId, call: call})
+	return l.lastId
+}
+
+func (l *List) Unobserve(id ObserverId) {
+	for i := range l.observers {
+		if l.observers[i].id == id {
+			l.observers = slices.Delete(l.observers, i, i+1)
+			return
+		}
+	}
+}
+
+func (l *List) notify() {
+	observers := slices.Clone(l.observers)
+	for i := range observers {
+		observers[i].call()
+	}
+}
@@ -0,0 +1,120 @@
+/******************************************************************************/
+/* model_test.go                                                              */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package binding
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestModelSetNotifiesOnChange(t *testing.T) {
+	m := NewModel()
+	keys := []string{}
+	m.Observe(func(key string) { keys = append(keys, key) })
+	m.Set("name", "kaiju")
+	m.Set("name", "kaiju")
+	m.Set("name", "engine")
+	m.Set("count", 1)
+	m.Set("count", 1.0)
+	want := []string{"name", "name", "count", "count"}
+	if !slices.Equal(keys, want) {
+		t.Fatalf("expected %v but got %v", want, keys)
+	}
+}
+
+func TestModelNestedPaths(t *testing.T) {
+	user := NewModelFrom(map[string]any{"name": "brent"})
+	m := NewModelFrom(map[string]any{"user": user})
+	if v, ok := m.Get("user.name"); !ok || v != "brent" {
+		t.Fatalf("expected brent but got %v", v)
+	}
+	if _, ok := m.Get("user.missing"); ok {
+		t.Fatal("expected a missing nested value to not be found")
+	}
+	if _, ok := m.Get("name.length"); ok {
+		t.Fatal("expected a path through a non-model to not be found")
+	}
+	keys := []string{}
+	m.Observe(func(key string) { keys = append(keys, key) })
+	m.SetPath("user.name", "farris")
+	if v, _ := user.Get("name"); v != "farris" {
+		t.Fatalf("expected SetPath to change the nested model, got %v", v)
+	}
+	other := NewModel()
+	m.Set("user", other)
+	user.Set("name", "ignored")
+	other.Set("name", "other")
+	want := []string{"user.name", "user", "user.name"}
+	if !slices.Equal(keys, want) {
+		t.Fatalf("expected %v but got %v", want, keys)
+	}
+}
+
+func TestModelDelete(t *testing.T) {
+	m := NewModelFrom(map[string]any{"a": 1})
+	calls := 0
+	m.Observe(func(string) { calls++ })
+	m.Delete("missing")
+	m.Delete("a")
+	if m.Has("a") || calls != 1 {
+		t.Fatalf("expected a single notification for the delete, got %d", calls)
+	}
+}
+
+func TestModelUnobserve(t *testing.T) {
+	m := NewModel()
+	calls := 0
+	id := m.Observe(func(string) { calls++ })
+	m.Set("a", 1)
+	m.Unobserve(id)
+	m.Set("a", 2)
+	if calls != 1 {
+		t.Fatalf("expected 1 call but got %d", calls)
+	}
+}
+
+func listKeys(l *List) []string {
+	keys := []string{}
+	for _, item := range l.Items() {
+		keys = append(keys, item.Key)
+	}
+	return keys
+}
+
+func TestListKeyedChanges(t *testing.T) {
+	m := NewModel()
+	l := m.List("items")
+	if m.List("items") != l {
+		t.Fatal("expected List to return the existing list")
+	}
+	calls := 0
+	m.Observe(func(key string) {
+		if key != "items" {
+			t.Errorf("expected the list key but got %s", key)
+		}
+		calls++
+	})
+	l.Append("a", NewModel())
+	l.Append("b", NewModel())
+	l.Append("a", NewModel())
+	l.Insert(0, "c", NewModel())
+	l.Move("c", 2)
+	if want := []string{"a", "b", "c"}; !slices.Equal(listKeys(l), want) {
+		t.Fatalf("expected %v but got %v", want, listKeys(l))
+	}
+	if !l.Remove("b") || l.Remove("b") {
+		t.Fatal("expected the key to be removed only once")
+	}
+	l.Replace([]ListItem{{Key: "c"}, {Key: "d"}, {Key: "c"}})
+	if want := []string{"c", "d"}; !slices.Equal(listKeys(l), want) {
+		t.Fatalf("expected %v but got %v", want, listKeys(l))
+	}
+	l.Clear()
+	l.Clear()
+	if calls != 7 || l.Len() != 0 {
+		t.Fatalf("expected 7 notifications and an empty list, got %d and %d", calls, l.Len())
+	}
+}
@@ -0,0 +1,327 @@
+/******************************************************************************/
+/* animator.go                                                                */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package animation
+
+import (
+	"math"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+// Animator holds the running animations and transitions of a single element.
+// It lives outside of the element's style rules so that it survives the rules
+// being cleared and re-applied when classes or states change
+type Animator struct {
+	animations  []runningAnimation
+	transitions map[string]*runningTransition
+	targets     map[string][]rules.PropertyValue
+	shown       map[string][]rules.PropertyValue
+	animated    []string
+}
+
+type runningAnimation struct {
+	Animation
+	start      float64
+	pausedAt   float64
+	pausedTime float64
+}
+
+type runningTransition struct {
+	Transition
+	from  []rules.PropertyValue
+	to    []rules.PropertyValue
+	start float64
+}
+
+// Apply evaluates the animations and transitions of the element at the time
+// now (in seconds) and returns the styled rules with the animated values
+// replacing the styled values. Active is true while any value is still
+// changing, in which case the element should be styled again next frame
+func (a *Animator) Apply(styled []rules.Rule, keyframes map[string]rules.Keyframes, now float64) ([]rules.Rule, bool, error) {
+	anims, err := ParseAnimations(styled)
+	if err != nil {
+		return styled, false, err
+	}
+	trans, err := ParseTransitions(styled)
+	if err != nil {
+		return styled, false, err
+	}
+	base := map[string][]rules.PropertyValue{}
+	for i := range styled {
+		base[styled[i].Property] = styled[i].Values
+	}
+	a.syncAnimations(anims, now)
+	a.syncTransitions(base, trans, now)
+	active := false
+	animated := map[string][]rules.PropertyValue{}
+	order := []string{}
+	set := func(property string, values []rules.PropertyValue) {
+		if _, ok := animated[property]; !ok {
+			order = append(order, property)
+		}
+		animated[property] = values
+	}
+	for i := range a.animations {
+		ra := &a.animations[i]
+		p, apply, running := ra.progress(now)
+		if running && ra.PlayState == PlayStateRunning {
+			active = true
+		}
+		if !apply {
+			continue
+		}
+		k, ok := keyframes[ra.Name]
+		if !ok {
+			continue
+		}
+		for _, prop := range k.Properties() {
+			if v, ok := sampleKeyframes(&k, prop, base[prop], float32(p), ra.Timing); ok {
+				set(prop, v)
+			}
+		}
+	}
+	// Transitions are applied after animations so they win, they are sampled
+	// in the order of the styled rules to keep the output stable
+	for i := range styled {
+		prop := styled[i].Property
+		tr, ok := a.transitions[prop]
+		if !ok {
+			continue
+		}
+		elapsed := now - tr.start - tr.Delay
+		if elapsed >= tr.Duration {
+			delete(a.transitions, prop)
+			continue
+		}
+		p := float32(max(0, elapsed) / tr.Duration)
+		set(prop, Interpolate(tr.from, tr.to, tr.Timing(p)))
+		active = true
+	}
+	out := make([]rules.Rule, 0, len(styled)+len(order))
+	for i := range styled {
+		r := styled[i]
+		if v, ok := animated[r.Property]; ok {
+			r.Values = v
+		}
+		out = append(out, r)
+	}
+	for _, prop := range order {
+		if _, ok := base[prop]; !ok {
+			out = append(out, rules.Rule{Property: prop, Values: animated[prop]})
+		}
+	}
+	a.animated = order
+	clear(a.shown)
+	if a.shown == nil {
+		a.shown = map[string][]rules.PropertyValue{}
+	}
+	for i := range out {
+		a.shown[out[i].Property] = out[i].Values
+	}
+	return out, active, nil
+}
+
+// Active returns true if any animation or transition is being tracked
+func (a *Animator) Active() bool {
+	return len(a.animations) > 0 || len(a.transitions) > 0
+}
+
+// Animated returns the properties whose values were replaced by the last
+// Apply, in the order they were animated
+func (a *Animator) Animated() []string { return a.animated }
+
+// syncAnimations keeps the start time of the animations that are still listed
+// (matched by name) and starts the ones that are new
+func (a *Animator) syncAnimations(anims []Animation, now float64) {
+	used := make([]bool, len(a.animations))
+	next := make([]runningAnimation, 0, len(anims))
+	for _, spec := range anims {
+		found := -1
+		for i := range a.animations {
+			if !used[i] && a.animations[i].Name == spec.Name {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			ra := runningAnimation{Animation: spec, start: now}
+			if spec.PlayState == PlayStatePaused {
+				ra.pausedAt = now
+			}
+			next = append(next, ra)
+			continue
+		}
+		used[found] = true
+		ra := a.animations[found]
+		if ra.PlayState != spec.PlayState {
+			if spec.PlayState == PlayStatePaused {
+				ra.pausedAt = now
+			} else {
+				ra.pausedTime += now - ra.pausedAt
+			}
+		}
+		ra.Animation = spec
+		next = append(next, ra)
+	}
+	a.animations = next
+}
+
+// syncTransitions starts a transition for each property whose styled value
+// changed since the last time the element was styled, the transition starts
+// from the value that was on screen so interrupted transitions don't jump.
+// The transitions of the new style are used, so a transition that is only
+// declared for :hover will run when the element becomes hovered
+func (a *Animator) syncTransitions(base map[string][]rules.PropertyValue, trans []Transition, now float64) {
+	if a.targets == nil {
+		a.targets = map[string][]rules.PropertyValue{}
+		a.transitions = map[string]*runningTransition{}
+	}
+	for prop := range a.targets {
+		if _, ok := base[prop]; !ok {
+			delete(a.targets, prop)
+			delete(a.transitions, prop)
+		}
+	}
+	for prop := range a.transitions {
+		if _, ok := transitionFor(trans, prop); !ok {
+			delete(a.transitions, prop)
+		}
+	}
+	for prop, values := range base {
+		prev, seen := a.targets[prop]
+		if seen && valuesEqual(prev, values) {
+			continue
+		}
+		a.targets[prop] = cloneValues(values)
+		if !seen {
+			continue
+		}
+		t, ok := transitionFor(trans, prop)
+		if !ok {
+			continue
+		}
+		from := prev
+		if shown, ok := a.shown[prop]; ok {
+			from = shown
+		}
+		if !CanInterpolate(from, values) {
+			delete(a.transitions, prop)
+			continue
+		}
+		a.transitions[prop] = &runningTransition{
+			Transition: t,
+			from:       cloneValues(from),
+			to:         cloneValues(values),
+			start:      now,
+		}
+	}
+}
+
+func transitionFor(trans []Transition, property string) (Transition, bool) {
+	// The last matching transition wins, like the last declared CSS value
+	for i := len(trans) - 1; i >= 0; i-- {
+		if trans[i].Applies(property) {
+			return trans[i], true
+		}
+	}
+	return Transition{}, false
+}
+
+// progress returns the directed progress through the keyframes, if the
+// animation should be applied to the style and if it is still running
+func (r *runningAnimation) progress(now float64) (float64, bool, bool) {
+	t := now
+	if r.PlayState == PlayStatePaused {
+		t = r.pausedAt
+	}
+	elapsed := t - r.start - r.pausedTime - r.Delay
+	fillBackwards := r.FillMode == FillModeBackwards || r.FillMode == FillModeBoth
+	fillForwards := r.FillMode == FillModeForwards || r.FillMode == FillModeBoth
+	if elapsed < 0 {
+		return r.directed(0, 0), fillBackwards, true
+	}
+	total := 0.0
+	if r.Duration > 0 && r.IterationCount > 0 {
+		total = r.Duration * r.IterationCount
+	}
+	if elapsed >= total {
+		if r.IterationCount == 0 {
+			return r.directed(0, 0), fillForwards, false
+		}
+		iteration := math.Ceil(r.IterationCount) - 1
+		p := r.IterationCount - math.Floor(r.IterationCount)
+		if p == 0 {
+			p = 1
+		}
+		return r.directed(iteration, p), fillForwards, false
+	}
+	overall := elapsed / r.Duration
+	iteration := math.Floor(overall)
+	return r.directed(iteration, overall-iteration), true, true
+}
+
+func (r *runningAnimation) directed(iteration, p float64) float64 {
+	reverse := false
+	switch r.Direction {
+	case DirectionReverse:
+		reverse = true
+	case DirectionAlternate:
+		reverse = int64(iteration)%2 == 1
+	case DirectionAlternateReverse:
+		reverse = int64(iteration)%2 == 0
+	}
+	if reverse {
+		return 1 - p
+	}
+	return p
+}
+
+// sampleKeyframes finds the value of a property at the progress p, frames
+// that don't list the property are skipped and the styled value is used for
+// the missing 0% and 100% frames
+func sampleKeyframes(k *rules.Keyframes, property string, styled []rules.PropertyValue, p float32, timing TimingFunction) ([]rules.PropertyValue, bool) {
+	type stop struct {
+		offset float32
+		values []rules.PropertyValue
+	}
+	stops := make([]stop, 0, len(k.Frames)+2)
+	for i := range k.Frames {
+		if r, ok := k.Frames[i].Rule(property); ok {
+			stops = append(stops, stop{k.Frames[i].Offset, r.Values})
+		}
+	}
+	if len(stops) == 0 {
+		return nil, false
+	}
+	if stops[0].offset > 0 {
+		first := styled
+		if first == nil {
+			first = stops[0].values
+		}
+		stops = append([]stop{{0, first}}, stops...)
+	}
+	if last := stops[len(stops)-1]; last.offset < 1 {
+		end := styled
+		if end == nil {
+			end = last.values
+		}
+		stops = append(stops, stop{1, end})
+	}
+	for i := 0; i < len(stops)-1; i++ {
+		a, b := stops[i], stops[i+1]
+		if p > b.offset && i < len(stops)-2 {
+			continue
+		}
+		local := float32(1)
+		if b.offset > a.offset {
+			local = (p - a.offset) / (b.offset - a.offset)
+		}
+		local = min(max(local, 0), 1)
+		return Interpolate(a.values, b.values, timing(local)), true
+	}
+	return cloneValues(stops[0].values), true
+}
@@ -0,0 +1,148 @@
+/******************************************************************************/
+/* animator_test.go                                                           */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package animation
+
+import (
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+func ruleValue(t *testing.T, rs []rules.Rule, property string) string {
+	t.Helper()
+	for i := len(rs) - 1; i >= 0; i-- {
+		if rs[i].Property == property {
+			return rs[i].Values[0].Str
+		}
+	}
+	t.Fatalf("missing the %s rule", property)
+	return ""
+}
+
+func applyAt(t *testing.T, a *Animator, rs []rules.Rule, keyframes map[string]rules.Keyframes, now float64) ([]rules.Rule, bool) {
+	t.Helper()
+	out, active, err := a.Apply(rs, keyframes, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out, active
+}
+
+const testKeyframes = `@keyframes fade { from { opacity: 0; } to { opacity: 1; } }
+@keyframes pulse { 50% { width: 20px; } }
+.a { width: 10px; }`
+
+func TestAnimatorKeyframes(t *testing.T) {
+	s := parseSheet(t, testKeyframes)
+	rs := append(s.Groups[0].Rules, parseRules(t, "animation: fade 2s linear;")...)
+	a := Animator{}
+	out, active := applyAt(t, &a, rs, s.Keyframes, 10)
+	if v := ruleValue(t, out, "opacity"); v != "0" || !active {
+		t.Fatalf("expected opacity 0 at the start, got %s (active %v)", v, active)
+	}
+	out, _ = applyAt(t, &a, rs, s.Keyframes, 11)
+	if v := ruleValue(t, out, "opacity"); v != "0.5" {
+		t.Fatalf("expected opacity 0.5 half way, got %s", v)
+	}
+	if got := a.Animated(); len(got) != 1 || got[0] != "opacity" {
+		t.Fatalf("expected only the opacity to be animated, got %v", got)
+	}
+	// Without a fill mode the animation stops affecting the style when done
+	out, active = applyAt(t, &a, rs, s.Keyframes, 12.5)
+	if active {
+		t.Fatal("expected the animation to be finished")
+	}
+	if len(a.Animated()) != 0 {
+		t.Fatal("expected nothing to be animated once the animation is done")
+	}
+	for i := range out {
+		if out[i].Property == "opacity" {
+			t.Fatal("expected the opacity to no longer be animated")
+		}
+	}
+}
+
+func TestAnimatorImplicitFrames(t *testing.T) {
+	s := parseSheet(t, testKeyframes)
+	rs := append(s.Groups[0].Rules, parseRules(t, "animation: pulse 1s linear;")...)
+	a := Animator{}
+	applyAt(t, &a, rs, s.Keyframes, 0)
+	out, _ := applyAt(t, &a, rs, s.Keyframes, 0.25)
+	if v := ruleValue(t, out, "width"); v != "15px" {
+		t.Fatalf("expected the styled width to be the 0%% frame, got %s", v)
+	}
+	out, _ = applyAt(t, &a, rs, s.Keyframes, 0.75)
+	if v := ruleValue(t, out, "width"); v != "15px" {
+		t.Fatalf("expected the styled width to be the 100%% frame, got %s", v)
+	}
+}
+
+func TestAnimatorDirectionAndFill(t *testing.T) {
+	s := parseSheet(t, testKeyframes)
+	rs := parseRules(t, "animation: fade 1s linear 1s 2 alternate both;")
+	a := Animator{}
+	out, _ := applyAt(t, &a, rs, s.Keyframes, 0)
+	if v := ruleValue(t, out, "opacity"); v != "0" {
+		t.Fatalf("expected backwards fill during the delay, got %s", v)
+	}
+	out, _ = applyAt(t, &a, rs, s.Keyframes, 2.25)
+	if v := ruleValue(t, out, "opacity"); v != "0.75" {
+		t.Fatalf("expected the second iteration to play in reverse, got %s", v)
+	}
+	out, active := applyAt(t, &a, rs, s.Keyframes, 5)
+	if v := ruleValue(t, out, "opacity"); v != "0" || active {
+		t.Fatalf("expected forwards fill of the reversed end, got %s (active %v)", v, active)
+	}
+}
+
+func TestAnimatorPlayState(t *testing.T) {
+	s := parseSheet(t, testKeyframes)
+	running := parseRules(t, "animation: fade 4s linear;")
+	paused := parseRules(t, "animation: fade 4s linear paused;")
+	a := Animator{}
+	applyAt(t, &a, running, s.Keyframes, 0)
+	out, active := applyAt(t, &a, paused, s.Keyframes, 1)
+	if v := ruleValue(t, out, "opacity"); v != "0.25" || active {
+		t.Fatalf("expected the paused value 0.25, got %s (active %v)", v, active)
+	}
+	applyAt(t, &a, paused, s.Keyframes, 10)
+	applyAt(t, &a, running, s.Keyframes, 11)
+	out, _ = applyAt(t, &a, running, s.Keyframes, 12)
+	if v := ruleValue(t, out, "opacity"); v != "0.5" {
+		t.Fatalf("expected the time while paused to be skipped, got %s", v)
+	}
+}
+
+func TestAnimatorTransition(t *testing.T) {
+	normal := parseRules(t, "color: #000000; opacity: 1; transition: color 1s linear;")
+	hover := parseRules(t, "color: #ffffff; opacity: 0; transition: color 1s linear;")
+	a := Animator{}
+	out, active := applyAt(t, &a, normal, nil, 0)
+	if v := ruleValue(t, out, "color"); v != "#000000" || active {
+		t.Fatalf("expected no transition for the initial style, got %s", v)
+	}
+	out, active = applyAt(t, &a, hover, nil, 1)
+	if v := ruleValue(t, out, "color"); v != "#000000ff" || !active {
+		t.Fatalf("expected the transition to start from the old color, got %s", v)
+	}
+	if v := ruleValue(t, out, "opacity"); v != "0" {
+		t.Fatalf("expected opacity to change without a transition, got %s", v)
+	}
+	out, _ = applyAt(t, &a, hover, nil, 1.5)
+	if v := ruleValue(t, out, "color"); v != "#808080ff" {
+		t.Fatalf("expected the half way color, got %s", v)
+	}
+	// Reversing part way starts from the color that was on screen
+	out, _ = applyAt(t, &a, normal, nil, 1.5)
+	if v := ruleValue(t, out, "color"); v != "#808080ff" {
+		t.Fatalf("expected the reversed transition to start on screen, got %s", v)
+	}
+	out, active = applyAt(t, &a, normal, nil, 3)
+	if v := ruleValue(t, out, "color"); v != "#000000" || active {
+		t.Fatalf("expected the transition to be finished, got %s", v)
+	}
+}
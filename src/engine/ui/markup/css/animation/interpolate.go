@@ -0,0 +1,229 @@
+/******************************************************************************/
+/* interpolate.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package animation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"kaijuengine.com/engine/ui/markup/css/helpers"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/matrix"
+)
+
+var dimensionPattern = regexp.MustCompile(`^([+-]?(?:\d+\.?\d*|\.\d+)(?:[eE][+-]?\d+)?)([a-zA-Z%]*)$`)
+
+// Interpolate blends the values of a property between from and to where t is
+// typically in the range of [0, 1]. Colors, lengths, numbers and functions
+// with matching arguments (such as transforms) are blended, anything else
+// switches from one to the other at the half way point
+func Interpolate(from, to []rules.PropertyValue, t float32) []rules.PropertyValue {
+	if out, ok := interpolateValues(from, to, t); ok {
+		return out
+	}
+	if t < 0.5 {
+		return cloneValues(from)
+	}
+	return cloneValues(to)
+}
+
+// CanInterpolate returns true if the values can be smoothly blended rather
+// than switching from one to the other
+func CanInterpolate(from, to []rules.PropertyValue) bool {
+	_, ok := interpolateValues(from, to, 0)
+	return ok
+}
+
+func interpolateValues(from, to []rules.PropertyValue, t float32) ([]rules.PropertyValue, bool) {
+	if len(from) != len(to) || len(from) == 0 {
+		return nil, false
+	}
+	out := make([]rules.PropertyValue, len(from))
+	for i := range from {
+		v, ok := interpolateValue(from[i], to[i], t)
+		if !ok {
+			return nil, false
+		}
+		out[i] = v
+	}
+	return out, true
+}
+
+func interpolateValue(a, b rules.PropertyValue, t float32) (rules.PropertyValue, bool) {
+	if ca, ok := parseColor(a); ok {
+		if cb, ok := parseColor(b); ok {
+			return rules.PropertyValue{
+				Str:       colorHex(lerpColor(ca, cb, t)),
+				Separated: a.Separated,
+			}, true
+		}
+	}
+	if a.IsFunction() || b.IsFunction() {
+		if a.Str != b.Str || len(a.Args) != len(b.Args) {
+			return rules.PropertyValue{}, false
+		}
+		out := rules.PropertyValue{
+			Str:       a.Str,
+			Args:      make([]string, len(a.Args)),
+			ArgNums:   make([]float32, len(a.Args)),
+			Separated: a.Separated,
+		}
+		for i := range a.Args {
+			if a.Args[i] == b.Args[i] {
+				out.Args[i] = a.Args[i]
+			} else if s, ok := lerpDimension(a.Args[i], b.Args[i], t); ok {
+				out.Args[i] = s
+			} else {
+				return rules.PropertyValue{}, false
+			}
+			if i < len(a.ArgNums) && i < len(b.ArgNums) {
+				out.ArgNums[i] = lerp(a.ArgNums[i], b.ArgNums[i], t)
+			}
+		}
+		return out, true
+	}
+	if a.Str == b.Str {
+		return a.Clone(), true
+	}
+	s, ok := lerpDimension(a.Str, b.Str, t)
+	if !ok {
+		return rules.PropertyValue{}, false
+	}
+	return rules.PropertyValue{
+		Str:       s,
+		Num:       lerp(a.Num, b.Num, t),
+		Separated: a.Separated,
+	}, true
+}
+
+func splitDimension(str string) (float64, string, bool) {
+	m := dimensionPattern.FindStringSubmatch(str)
+	if m == nil {
+		return 0, "", false
+	}
+	f, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return f, strings.ToLower(m[2]), true
+}
+
+// lerpDimension blends two numbers that share the same unit, a unit-less zero
+// is allowed to blend with any unit (such as "0" to "10px")
+func lerpDimension(a, b string, t float32) (string, bool) {
+	na, ua, ok := splitDimension(a)
+	if !ok {
+		return "", false
+	}
+	nb, ub, ok := splitDimension(b)
+	if !ok {
+		return "", false
+	}
+	if ua != ub {
+		if ua == "" && na == 0 {
+			ua = ub
+		} else if ub == "" && nb == 0 {
+			ub = ua
+		} else {
+			return "", false
+		}
+	}
+	v := na + (nb-na)*float64(t)
+	return strconv.FormatFloat(v, 'f', -1, 32) + ua, true
+}
+
+func parseColor(v rules.PropertyValue) (matrix.Color, bool) {
+	switch v.Str {
+	case "rgb", "rgba":
+		if len(v.Args) < 3 {
+			return matrix.Color{}, false
+		}
+		var c [4]float64
+		c[3] = 1
+		for i := 0; i < len(v.Args) && i < 4; i++ {
+			n, unit, ok := splitDimension(v.Args[i])
+			if !ok || (unit != "" && unit != "%") {
+				return matrix.Color{}, false
+			}
+			if unit == "%" {
+				n /= 100
+			} else if i < 3 {
+				n /= 255
+			}
+			c[i] = n
+		}
+		return matrix.NewColor(matrix.Float(c[0]), matrix.Float(c[1]),
+			matrix.Float(c[2]), matrix.Float(c[3])), true
+	case "transparent":
+		return matrix.ColorTransparent(), true
+	}
+	if v.IsFunction() {
+		return matrix.Color{}, false
+	}
+	hex := v.Str
+	if named, ok := helpers.ColorMap[strings.ToLower(hex)]; ok {
+		hex = named
+	}
+	if !strings.HasPrefix(hex, "#") {
+		return matrix.Color{}, false
+	}
+	c, err := matrix.ColorFromHexString(hex)
+	return c, err == nil
+}
+
+// lerpColor blends the colors with premultiplied alpha so that fading from
+// transparent doesn't pass through black
+func lerpColor(a, b matrix.Color, t float32) matrix.Color {
+	out := matrix.Color{}
+	alpha := lerp(float32(a.A()), float32(b.A()), t)
+	for i := range 3 {
+		pa := float32(a[i] * a.A())
+		pb := float32(b[i] * b.A())
+		if alpha > 0 {
+			out[i] = matrix.Float(lerp(pa, pb, t) / alpha)
+		}
+	}
+	out[3] = matrix.Float(alpha)
+	return out
+}
+
+func colorHex(c matrix.Color) string {
+	channel := func(v matrix.Float) uint8 {
+		return uint8(min(max(float32(v), 0), 1)*255 + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", channel(c.R()), channel(c.G()),
+		channel(c.B()), channel(c.A()))
+}
+
+func lerp(a, b, t float32) float32 { return a + (b-a)*t }
+
+func cloneValues(values []rules.PropertyValue) []rules.PropertyValue {
+	out := make([]rules.PropertyValue, len(values))
+	for i := range values {
+		out[i] = values[i].Clone()
+	}
+	return out
+}
+
+func valuesEqual(a, b []rules.PropertyValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Str != b[i].Str || len(a[i].Args) != len(b[i].Args) {
+			return false
+		}
+		for j := range a[i].Args {
+			if a[i].Args[j] != b[i].Args[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+/******************************************************************************/
+/* interpolate_test.go                                                        */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package animation
+
+import (
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+func TestInterpolateLength(t *testing.T) {
+	from := []rules.PropertyValue{{Str: "10px", Num: 10}}
+	to := []rules.PropertyValue{{Str: "20px", Num: 20}}
+	out := Interpolate(from, to, 0.25)
+	if out[0].Str != "12.5px" || out[0].Num != 12.5 {
+		t.Fatalf("expected 12.5px, got %#v", out[0])
+	}
+	out = Interpolate([]rules.PropertyValue{{Str: "0"}}, to, 0.5)
+	if out[0].Str != "10px" {
+		t.Fatalf("expected a unit-less zero to take the other unit, got %s", out[0].Str)
+	}
+}
+
+func TestInterpolateOpacity(t *testing.T) {
+	out := Interpolate([]rules.PropertyValue{{Str: "0"}}, []rules.PropertyValue{{Str: "1"}}, 0.75)
+	if out[0].Str != "0.75" {
+		t.Fatalf("expected 0.75, got %s", out[0].Str)
+	}
+}
+
+func TestInterpolateColor(t *testing.T) {
+	from := []rules.PropertyValue{{Str: "black"}}
+	to := []rules.PropertyValue{{Str: "rgb", Args: []string{"255", "255", "255"}}}
+	out := Interpolate(from, to, 0.5)
+	if out[0].Str != "#808080ff" {
+		t.Fatalf("expected #808080ff, got %s", out[0].Str)
+	}
+	// Fading in from transparent should not darken the color
+	out = Interpolate([]rules.PropertyValue{{Str: "transparent"}},
+		[]rules.PropertyValue{{Str: "#ff0000"}}, 0.5)
+	if out[0].Str != "#ff000080" {
+		t.Fatalf("expected #ff000080, got %s", out[0].Str)
+	}
+}
+
+func TestInterpolateTransform(t *testing.T) {
+	from := []rules.PropertyValue{{Str: "translate", Args: []string{"0px", "10%"}, ArgNums: []float32{0, 0.1}}}
+	to := []rules.PropertyValue{{Str: "translate", Args: []string{"100px", "10%"}, ArgNums: []float32{100, 0.1}}}
+	out := Interpolate(from, to, 0.5)
+	if out[0].Str != "translate" || out[0].Args[0] != "50px" || out[0].Args[1] != "10%" {
+		t.Fatalf("unexpected transform %#v", out[0])
+	}
+	if out[0].ArgNums[0] != 50 {
+		t.Fatalf("expected the numeric argument to be blended, got %f", out[0].ArgNums[0])
+	}
+}
+
+func TestInterpolateDiscrete(t *testing.T) {
+	from := []rules.PropertyValue{{Str: "none"}}
+	to := []rules.PropertyValue{{Str: "block"}}
+	if CanInterpolate(from, to) {
+		t.Fatal("keywords should not be interpolated")
+	}
+	if Interpolate(from, to, 0.49)[0].Str != "none" || Interpolate(from, to, 0.5)[0].Str != "block" {
+		t.Fatal("expected the value to switch at the half way point")
+	}
+	mismatch := []rules.PropertyValue{{Str: "translateX", Args: []string{"10px"}}}
+	other := []rules.PropertyValue{{Str: "translateY", Args: []string{"10px"}}}
+	if CanInterpolate(mismatch, other) {
+		t.Fatal("different transform functions should not be interpolated")
+	}
+}
@@ -0,0 +1,427 @@
+/******************************************************************************/
+/* spec.go                                                                    */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package animation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+type Direction uint8
+type FillMode uint8
+type PlayState uint8
+
+const (
+	DirectionNormal = Direction(iota)
+	DirectionReverse
+	DirectionAlternate
+	DirectionAlternateReverse
+)
+
+const (
+	FillModeNone = FillMode(iota)
+	FillModeForwards
+	FillModeBackwards
+	FillModeBoth
+)
+
+const (
+	PlayStateRunning = PlayState(iota)
+	PlayStatePaused
+)
+
+// Infinite is the iteration count used for "animation-iteration-count: infinite"
+var Infinite = math.Inf(1)
+
+// Animation is the computed value of one item of the animation-* properties
+type Animation struct {
+	Name           string
+	Duration       float64
+	Delay          float64
+	IterationCount float64
+	Direction      Direction
+	FillMode       FillMode
+	PlayState      PlayState
+	Timing         TimingFunction
+}
+
+// Transition is the computed value of one item of the transition-* properties
+type Transition struct {
+	Property string
+	Duration float64
+	Delay    float64
+	Timing   TimingFunction
+}
+
+func defaultAnimation() Animation {
+	return Animation{
+		Name:           "none",
+		IterationCount: 1,
+		Timing:         Ease,
+	}
+}
+
+func defaultTransition() Transition {
+	return Transition{
+		Property: "all",
+		Timing:   Ease,
+	}
+}
+
+// Applies returns true if the transition should be used for the property
+func (t Transition) Applies(property string) bool {
+	return t.Property == "all" || t.Property == property
+}
+
+// ParseTime reads a CSS time value ("1s", "250ms" or "0") into seconds
+func ParseTime(str string) (float64, bool) {
+	str = strings.ToLower(str)
+	scale := 1.0
+	switch {
+	case strings.HasSuffix(str, "ms"):
+		str = strings.TrimSuffix(str, "ms")
+		scale = 0.001
+	case strings.HasSuffix(str, "s"):
+		str = strings.TrimSuffix(str, "s")
+	case str != "0":
+		return 0, false
+	}
+	t, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, false
+	}
+	return t * scale, true
+}
+
+func parseIterationCount(str string) (float64, bool) {
+	if str == "infinite" {
+		return Infinite, true
+	}
+	if strings.HasSuffix(str, "s") {
+		return 0, false
+	}
+	count, err := strconv.ParseFloat(str, 64)
+	if err != nil || count < 0 {
+		return 0, false
+	}
+	return count, true
+}
+
+func parseDirection(str string) (Direction, bool) {
+	switch str {
+	case "normal":
+		return DirectionNormal, true
+	case "reverse":
+		return DirectionReverse, true
+	case "alternate":
+		return DirectionAlternate, true
+	case "alternate-reverse":
+		return DirectionAlternateReverse, true
+	}
+	return DirectionNormal, false
+}
+
+func parseFillMode(str string) (FillMode, bool) {
+	switch str {
+	case "none":
+		return FillModeNone, true
+	case "forwards":
+		return FillModeForwards, true
+	case "backwards":
+		return FillModeBackwards, true
+	case "both":
+		return FillModeBoth, true
+	}
+	return FillModeNone, false
+}
+
+func parsePlayState(str string) (PlayState, bool) {
+	switch str {
+	case "running":
+		return PlayStateRunning, true
+	case "paused":
+		return PlayStatePaused, true
+	}
+	return PlayStateRunning, false
+}
+
+// parseAnimationShorthand reads a single item of the animation shorthand, the
+// first time is the duration and the second is the delay, any value that is
+// not a known keyword is the name of the animation
+func parseAnimationShorthand(values []rules.PropertyValue) (Animation, error) {
+	a := defaultAnimation()
+	timeCount := 0
+	hasName := false
+	for _, v := range values {
+		if t, ok := ParseTime(v.Str); ok && !v.IsFunction() {
+			if timeCount == 0 {
+				a.Duration = t
+			} else {
+				a.Delay = t
+			}
+			timeCount++
+		} else if f, ok := ParseTimingFunction(v); ok {
+			a.Timing = f
+		} else if c, ok := parseIterationCount(v.Str); ok {
+			a.IterationCount = c
+		} else if d, ok := parseDirection(v.Str); ok && v.Str != "normal" {
+			a.Direction = d
+		} else if m, ok := parseFillMode(v.Str); ok && (v.Str != "none" || hasName) {
+			a.FillMode = m
+		} else if p, ok := parsePlayState(v.Str); ok {
+			a.PlayState = p
+		} else if v.Str == "normal" {
+			// Ambiguous with the direction, already the default
+		} else if !hasName && !v.IsFunction() {
+			a.Name = strings.Trim(v.Str, `"'`)
+			hasName = true
+		} else {
+			return a, fmt.Errorf("unexpected animation value '%s'", v.Str)
+		}
+	}
+	return a, nil
+}
+
+// parseTransitionShorthand reads a single item of the transition shorthand
+func parseTransitionShorthand(values []rules.PropertyValue) (Transition, error) {
+	t := defaultTransition()
+	timeCount := 0
+	hasProperty := false
+	for _, v := range values {
+		if s, ok := ParseTime(v.Str); ok && !v.IsFunction() {
+			if timeCount == 0 {
+				t.Duration = s
+			} else {
+				t.Delay = s
+			}
+			timeCount++
+		} else if f, ok := ParseTimingFunction(v); ok {
+			t.Timing = f
+		} else if !hasProperty && !v.IsFunction() {
+			t.Property = v.Str
+			hasProperty = true
+		} else {
+			return t, fmt.Errorf("unexpected transition value '%s'", v.Str)
+		}
+	}
+	return t, nil
+}
+
+// applyLonghand sets the field of each item from a comma separated longhand
+// list, the list is repeated when it is shorter than the number of items
+func applyLonghand[T any](items []T, values []rules.PropertyValue, set func(item *T, v rules.PropertyValue) error) error {
+	list := rules.SplitValueList(values)
+	if len(list) == 0 {
+		return nil
+	}
+	for i := range items {
+		entry := list[i%len(list)]
+		if len(entry) != 1 {
+			return fmt.Errorf("expected 1 value per list item but got %d", len(entry))
+		}
+		if err := set(&items[i], entry[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func timeSetter(str string, out *float64) error {
+	t, ok := ParseTime(str)
+	if !ok {
+		return fmt.Errorf("invalid time '%s'", str)
+	}
+	*out = t
+	return nil
+}
+
+func timingSetter(v rules.PropertyValue, out *TimingFunction) error {
+	f, ok := ParseTimingFunction(v)
+	if !ok {
+		return fmt.Errorf("invalid timing function '%s'", v.Str)
+	}
+	*out = f
+	return nil
+}
+
+// ParseAnimations computes the list of animations declared by the animation
+// shorthand and the animation-* longhand rules, later rules win. Animations
+// named "none" are not included in the result
+func ParseAnimations(rs []rules.Rule) ([]Animation, error) {
+	var out []Animation
+	for i := range rs {
+		r := &rs[i]
+		var err error
+		switch r.Property {
+		case "animation":
+			out = out[:0]
+			for _, item := range rules.SplitValueList(r.Values) {
+				a, e := parseAnimationShorthand(item)
+				if e != nil {
+					return nil, e
+				}
+				out = append(out, a)
+			}
+		case "animation-name":
+			list := rules.SplitValueList(r.Values)
+			names := make([]Animation, len(list))
+			for j := range list {
+				names[j] = defaultAnimation()
+				if j < len(out) {
+					names[j] = out[j]
+				}
+				names[j].Name = strings.Trim(list[j][0].Str, `"'`)
+			}
+			out = names
+		case "animation-duration":
+			err = applyLonghand(out, r.Values, func(a *Animation, v rules.PropertyValue) error {
+				return timeSetter(v.Str, &a.Duration)
+			})
+		case "animation-delay":
+			err = applyLonghand(out, r.Values, func(a *Animation, v rules.PropertyValue) error {
+				return timeSetter(v.Str, &a.Delay)
+			})
+		case "animation-timing-function":
+			err = applyLonghand(out, r.Values, func(a *Animation, v rules.PropertyValue) error {
+				return timingSetter(v, &a.Timing)
+			})
+		case "animation-iteration-count":
+			err = applyLonghand(out, r.Values, func(a *Animation, v rules.PropertyValue) error {
+				c, ok := parseIterationCount(v.Str)
+				if !ok {
+					return fmt.Errorf("invalid iteration count '%s'", v.Str)
+				}
+				a.IterationCount = c
+				return nil
+			})
+		case "animation-direction":
+			err = applyLonghand(out, r.Values, func(a *Animation, v rules.PropertyValue) error {
+				d, ok := parseDirection(v.Str)
+				if !ok {
+					return fmt.Errorf("invalid animation direction '%s'", v.Str)
+				}
+				a.Direction = d
+				return nil
+			})
+		case "animation-fill-mode":
+			err = applyLonghand(out, r.Values, func(a *Animation, v rules.PropertyValue) error {
+				m, ok := parseFillMode(v.Str)
+				if !ok {
+					return fmt.Errorf("invalid animation fill mode '%s'", v.Str)
+				}
+				a.FillMode = m
+				return nil
+			})
+		case "animation-play-state":
+			err = applyLonghand(out, r.Values, func(a *Animation, v rules.PropertyValue) error {
+				p, ok := parsePlayState(v.Str)
+				if !ok {
+					return fmt.Errorf("invalid animation play state '%s'", v.Str)
+				}
+				a.PlayState = p
+				return nil
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	valid := out[:0]
+	for i := range out {
+		if out[i].Name != "none" && out[i].Name != "" {
+			valid = append(valid, out[i])
+		}
+	}
+	return valid, nil
+}
+
+// ParseTransitions computes the list of transitions declared by the
+// transition shorthand and the transition-* longhand rules, later rules win
+func ParseTransitions(rs []rules.Rule) ([]Transition, error) {
+	var out []Transition
+	for i := range rs {
+		r := &rs[i]
+		var err error
+		switch r.Property {
+		case "transition":
+			out = out[:0]
+			for _, item := range rules.SplitValueList(r.Values) {
+				t, e := parseTransitionShorthand(item)
+				if e != nil {
+					return nil, e
+				}
+				out = append(out, t)
+			}
+		case "transition-property":
+			list := rules.SplitValueList(r.Values)
+			props := make([]Transition, len(list))
+			for j := range list {
+				props[j] = defaultTransition()
+				if j < len(out) {
+					props[j] = out[j]
+				}
+				props[j].Property = list[j][0].Str
+			}
+			out = props
+		case "transition-duration":
+			if len(out) == 0 {
+				out = append(out, defaultTransition())
+			}
+			err = applyLonghand(out, r.Values, func(t *Transition, v rules.PropertyValue) error {
+				return timeSetter(v.Str, &t.Duration)
+			})
+		case "transition-delay":
+			if len(out) == 0 {
+				out = append(out, defaultTransition())
+			}
+			err = applyLonghand(out, r.Values, func(t *Transition, v rules.PropertyValue) error {
+				return timeSetter(v.Str, &t.Delay)
+			})
+		case "transition-timing-function":
+			if len(out) == 0 {
+				out = append(out, defaultTransition())
+			}
+			err = applyLonghand(out, r.Values, func(t *Transition, v rules.PropertyValue) error {
+				return timingSetter(v, &t.Timing)
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	valid := out[:0]
+	for i := range out {
+		if out[i].Property != "none" && out[i].Duration > 0 {
+			valid = append(valid, out[i])
+		}
+	}
+	return valid, nil
+}
+
+// Validate checks the values of the animation, transition or one of their
+// longhand properties. The values are applied by the element's Animator
+func Validate(property string, values []rules.PropertyValue) error {
+	rs := []rules.Rule{{Property: property, Values: values}}
+	if strings.HasPrefix(property, "transition") {
+		_, err := ParseTransitions(rs)
+		return err
+	}
+	if property != "animation" && property != "animation-name" {
+		// Longhands only apply to named animations, so name one for each item
+		names := make([]rules.PropertyValue, len(rules.SplitValueList(values)))
+		for i := range names {
+			names[i] = rules.PropertyValue{Str: "validate", Separated: i > 0}
+		}
+		rs = append([]rules.Rule{{Property: "animation-name", Values: names}}, rs...)
+	}
+	_, err := ParseAnimations(rs)
+	return err
+}
@@ -0,0 +1,155 @@
+/******************************************************************************/
+/* spec_test.go                                                               */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package animation
+
+import (
+	"math"
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+type testWindow struct{}
+
+func (testWindow) DotsPerMillimeter() float64 { return 1 }
+func (testWindow) Width() int                 { return 100 }
+func (testWindow) Height() int                { return 100 }
+
+func parseSheet(t *testing.T, css string) rules.StyleSheet {
+	t.Helper()
+	s := rules.NewStyleSheet()
+	s.Parse(css, testWindow{})
+	if len(s.Groups) == 0 {
+		t.Fatal("expected the style sheet to have a group")
+	}
+	return s
+}
+
+func parseRules(t *testing.T, css string) []rules.Rule {
+	t.Helper()
+	return parseSheet(t, ".a { "+css+" }").Groups[0].Rules
+}
+
+func TestParseTime(t *testing.T) {
+	tests := map[string]float64{"1s": 1, "250ms": 0.25, "0": 0, "-.5s": -0.5}
+	for in, expected := range tests {
+		if got, ok := ParseTime(in); !ok || got != expected {
+			t.Fatalf("%s expected %f, got %f (%v)", in, expected, got, ok)
+		}
+	}
+	for _, in := range []string{"1", "fades", "ease"} {
+		if _, ok := ParseTime(in); ok {
+			t.Fatalf("%s should not be a time", in)
+		}
+	}
+}
+
+func TestParseAnimationShorthand(t *testing.T) {
+	anims, err := ParseAnimations(parseRules(t,
+		"animation: fade 2s ease-in 500ms infinite alternate both paused, slide 1s;"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anims) != 2 {
+		t.Fatalf("expected 2 animations, got %d", len(anims))
+	}
+	a := anims[0]
+	if a.Name != "fade" || a.Duration != 2 || a.Delay != 0.5 {
+		t.Fatalf("unexpected name/duration/delay %#v", a)
+	}
+	if !math.IsInf(a.IterationCount, 1) || a.Direction != DirectionAlternate ||
+		a.FillMode != FillModeBoth || a.PlayState != PlayStatePaused {
+		t.Fatalf("unexpected keywords %#v", a)
+	}
+	if got := a.Timing(0.5); math.Abs(float64(got-EaseIn(0.5))) > 1e-6 {
+		t.Fatalf("expected the ease-in timing function, got %f", got)
+	}
+	b := anims[1]
+	if b.Name != "slide" || b.Duration != 1 || b.IterationCount != 1 || b.FillMode != FillModeNone {
+		t.Fatalf("unexpected defaults %#v", b)
+	}
+}
+
+func TestParseAnimationLonghands(t *testing.T) {
+	anims, err := ParseAnimations(parseRules(t, `animation-name: a, b, c;
+		animation-duration: 1s, 2s;
+		animation-iteration-count: 3;
+		animation-timing-function: steps(4, end);`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anims) != 3 {
+		t.Fatalf("expected 3 animations, got %d", len(anims))
+	}
+	durations := []float64{1, 2, 1}
+	for i := range anims {
+		if anims[i].Duration != durations[i] || anims[i].IterationCount != 3 {
+			t.Fatalf("animation %d has unexpected values %#v", i, anims[i])
+		}
+		if anims[i].Timing(0.3) != 0.25 {
+			t.Fatalf("expected the steps timing function, got %f", anims[i].Timing(0.3))
+		}
+	}
+}
+
+func TestParseAnimationNone(t *testing.T) {
+	anims, err := ParseAnimations(parseRules(t, "animation: none;"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anims) != 0 {
+		t.Fatalf("expected no animations, got %#v", anims)
+	}
+}
+
+func TestParseTransitions(t *testing.T) {
+	trans, err := ParseTransitions(parseRules(t,
+		"transition: opacity 1s, color 200ms cubic-bezier(0.1, 0.7, 1.0, 0.1) 1s, width 0s;"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The zero duration width transition does nothing so it is dropped
+	if len(trans) != 2 {
+		t.Fatalf("expected 2 transitions, got %d", len(trans))
+	}
+	if trans[0].Property != "opacity" || trans[0].Duration != 1 {
+		t.Fatalf("unexpected first transition %#v", trans[0])
+	}
+	if trans[1].Property != "color" || trans[1].Duration != 0.2 || trans[1].Delay != 1 {
+		t.Fatalf("unexpected second transition %#v", trans[1])
+	}
+	if !trans[1].Applies("color") || trans[1].Applies("opacity") {
+		t.Fatal("transition applies to the wrong property")
+	}
+}
+
+func TestParseTransitionErrors(t *testing.T) {
+	if _, err := ParseTransitions(parseRules(t, "transition-duration: fast;")); err == nil {
+		t.Fatal("expected an error for an invalid duration")
+	}
+}
+
+func TestTimingKeywords(t *testing.T) {
+	for _, name := range []string{"linear", "ease", "step-start", "ease-out-bounce"} {
+		f, ok := ParseTimingFunction(rules.PropertyValue{Str: name})
+		if !ok {
+			t.Fatalf("expected %s to be a timing function", name)
+		}
+		if f(0) != 0 && name != "step-start" {
+			t.Fatalf("%s expected to start at 0, got %f", name, f(0))
+		}
+		if f(1) != 1 {
+			t.Fatalf("%s expected to end at 1, got %f", name, f(1))
+		}
+	}
+	if f, _ := ParseTimingFunction(rules.PropertyValue{Str: "step-start"}); f(0.1) != 1 {
+		t.Fatalf("step-start expected to jump immediately, got %f", f(0.1))
+	}
+	if _, ok := ParseTimingFunction(rules.PropertyValue{Str: "fade"}); ok {
+		t.Fatal("fade should not be a timing function")
+	}
+}
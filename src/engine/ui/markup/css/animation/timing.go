@@ -0,0 +1,118 @@
+/******************************************************************************/
+/* timing.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package animation
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"kaijuengine.com/engine/systems/tweening"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+// TimingFunction maps the linear progress of an animation in the range of
+// [0, 1] to the eased progress
+type TimingFunction func(t float32) float32
+
+var (
+	Linear    TimingFunction = tweening.EasingFunc(tweening.EasingLinear)
+	Ease                     = TimingFunction(helpers.CubicBezier(0.25, 0.1, 0.25, 1))
+	EaseIn                   = TimingFunction(helpers.CubicBezier(0.42, 0, 1, 1))
+	EaseOut                  = TimingFunction(helpers.CubicBezier(0, 0, 0.58, 1))
+	EaseInOut                = TimingFunction(helpers.CubicBezier(0.42, 0, 0.58, 1))
+)
+
+// timingKeywords holds the standard CSS keywords along with the tweening
+// system easings so that markup can use the same curves as hand written tweens
+var timingKeywords = map[string]TimingFunction{
+	"linear":              Linear,
+	"ease":                Ease,
+	"ease-in":             EaseIn,
+	"ease-out":            EaseOut,
+	"ease-in-out":         EaseInOut,
+	"step-start":          Steps(1, true),
+	"step-end":            Steps(1, false),
+	"ease-in-sine":        tweening.EasingFunc(tweening.EasingInSine),
+	"ease-out-sine":       tweening.EasingFunc(tweening.EasingOutSine),
+	"ease-in-out-sine":    tweening.EasingFunc(tweening.EasingInAndOutSine),
+	"ease-in-quad":        tweening.EasingFunc(tweening.EasingInQuad),
+	"ease-out-quad":       tweening.EasingFunc(tweening.EasingOutQuad),
+	"ease-in-out-quad":    tweening.EasingFunc(tweening.EasingInAndOutQuad),
+	"ease-in-cubic":       tweening.EasingFunc(tweening.EasingInCubic),
+	"ease-out-cubic":      tweening.EasingFunc(tweening.EasingOutCubic),
+	"ease-in-out-cubic":   tweening.EasingFunc(tweening.EasingInAndOutCubic),
+	"ease-in-quart":       tweening.EasingFunc(tweening.EasingInQuart),
+	"ease-out-quart":      tweening.EasingFunc(tweening.EasingOutQuart),
+	"ease-in-out-quart":   tweening.EasingFunc(tweening.EasingInAndOutQuart),
+	"ease-in-quint":       tweening.EasingFunc(tweening.EasingInQuint),
+	"ease-out-quint":      tweening.EasingFunc(tweening.EasingOutQuint),
+	"ease-in-out-quint":   tweening.EasingFunc(tweening.EasingInAndOutQuint),
+	"ease-in-expo":        tweening.EasingFunc(tweening.EasingInExpo),
+	"ease-out-expo":       tweening.EasingFunc(tweening.EasingOutExpo),
+	"ease-in-out-expo":    tweening.EasingFunc(tweening.EasingInAndOutExpo),
+	"ease-in-circ":        tweening.EasingFunc(tweening.EasingInCirc),
+	"ease-out-circ":       tweening.EasingFunc(tweening.EasingOutCirc),
+	"ease-in-out-circ":    tweening.EasingFunc(tweening.EasingInAndOutCirc),
+	"ease-in-back":        tweening.EasingFunc(tweening.EasingInBack),
+	"ease-out-back":       tweening.EasingFunc(tweening.EasingOutBack),
+	"ease-in-out-back":    tweening.EasingFunc(tweening.EasingInAndOutBack),
+	"ease-in-elastic":     tweening.EasingFunc(tweening.EasingInElastic),
+	"ease-out-elastic":    tweening.EasingFunc(tweening.EasingOutElastic),
+	"ease-in-out-elastic": tweening.EasingFunc(tweening.EasingInAndOutElastic),
+	"ease-in-bounce":      tweening.EasingFunc(tweening.EasingInBounce),
+	"ease-out-bounce":     tweening.EasingFunc(tweening.EasingOutBounce),
+	"ease-in-out-bounce":  tweening.EasingFunc(tweening.EasingInAndOutBounce),
+}
+
+// Steps creates the CSS steps() timing function, jumpStart matches the
+// "jump-start" (or "start") position while false matches "jump-end"
+func Steps(count int, jumpStart bool) TimingFunction {
+	count = max(1, count)
+	return func(t float32) float32 {
+		if t >= 1 {
+			return 1
+		} else if t <= 0 && !jumpStart {
+			return 0
+		}
+		step := float32(math.Floor(float64(t * float32(count))))
+		if jumpStart {
+			step++
+		}
+		return min(step/float32(count), 1)
+	}
+}
+
+// ParseTimingFunction reads a timing function keyword, cubic-bezier() or
+// steps() value. The boolean is false if the value is not a timing function
+func ParseTimingFunction(value rules.PropertyValue) (TimingFunction, bool) {
+	switch value.Str {
+	case "cubic-bezier":
+		p, err := helpers.ParseCubicBezierArgs(value.Args)
+		if err != nil {
+			return nil, false
+		}
+		return helpers.CubicBezier(p[0], p[1], p[2], p[3]), true
+	case "steps":
+		if len(value.Args) == 0 {
+			return nil, false
+		}
+		count, err := strconv.Atoi(value.Args[0])
+		if err != nil || count < 1 {
+			return nil, false
+		}
+		jumpStart := len(value.Args) > 1 &&
+			(value.Args[1] == "start" || value.Args[1] == "jump-start")
+		return Steps(count, jumpStart), true
+	}
+	if value.IsFunction() {
+		return nil, false
+	}
+	f, ok := timingKeywords[strings.ToLower(value.Str)]
+	return f, ok
+}
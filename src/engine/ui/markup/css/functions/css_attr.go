@@ -7,13 +7,23 @@
 package functions
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// attr(name), generated boxes (such as ::before) read the attribute from the
+// element that generated them
 func (f Attr) Process(panel *ui.Panel, elm *document.Element, value rules.PropertyValue) (string, error) {
-	return "", errors.New("not implemented")
+	if len(value.Args) != 1 {
+		return "", fmt.Errorf("attr expects 1 argument but got %d", len(value.Args))
+	}
+	if elm.PseudoElement() != "" {
+		if origin := elm.Parent.Value(); origin != nil {
+			elm = origin
+		}
+	}
+	return elm.Attribute(value.Args[0]), nil
 }
@@ -8,12 +8,37 @@ package functions
 
 import (
 	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// counter(name) or counter(name, style)
 func (f Counter) Process(panel *ui.Panel, elm *document.Element, value rules.PropertyValue) (string, error) {
-	return "", errors.New("not implemented")
+	if len(value.Args) == 0 || len(value.Args) > 2 {
+		return "", fmt.Errorf("counter expects 1 or 2 arguments but got %d", len(value.Args))
+	}
+	style, err := counterStyleArg(value.Args, 1)
+	if err != nil {
+		return "", err
+	}
+	values := elm.CounterValues(value.Args[0])
+	if len(values) == 0 {
+		// A counter that is not in scope is treated as being 0
+		return helpers.FormatCounter(0, style), nil
+	}
+	return helpers.FormatCounter(values[len(values)-1], style), nil
+}
+
+func counterStyleArg(args []string, idx int) (string, error) {
+	if len(args) <= idx {
+		return "decimal", nil
+	}
+	if !helpers.IsCounterStyle(args[idx]) {
+		return "", errors.New("unsupported counter style: " + args[idx])
+	}
+	return args[idx], nil
 }
@@ -0,0 +1,37 @@
+/******************************************************************************/
+/* css_counters.go                                                            */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package functions
+
+import (
+	"fmt"
+	"strings"
+
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+// counters(name, separator) or counters(name, separator, style)
+func (f Counters) Process(panel *ui.Panel, elm *document.Element, value rules.PropertyValue) (string, error) {
+	if len(value.Args) < 2 || len(value.Args) > 3 {
+		return "", fmt.Errorf("counters expects 2 or 3 arguments but got %d", len(value.Args))
+	}
+	style, err := counterStyleArg(value.Args, 2)
+	if err != nil {
+		return "", err
+	}
+	values := elm.CounterValues(value.Args[0])
+	if len(values) == 0 {
+		values = []int{0}
+	}
+	parts := make([]string, len(values))
+	for i := range values {
+		parts[i] = helpers.FormatCounter(values[i], style)
+	}
+	return strings.Join(parts, helpers.UnquoteString(value.Args[1])), nil
+}
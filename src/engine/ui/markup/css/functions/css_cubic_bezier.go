@@ -7,13 +7,18 @@
 package functions
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
 func (f CubicBezier) Process(panel *ui.Panel, elm *document.Element, value rules.PropertyValue) (string, error) {
-	return "", errors.New("not implemented")
+	p, err := helpers.ParseCubicBezierArgs(value.Args)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("cubic-bezier(%g, %g, %g, %g)", p[0], p[1], p[2], p[3]), nil
 }
@@ -22,6 +22,7 @@ var FunctionMap = map[string]Function{
 	"calc":                      Calc{},
 	"conic-gradient":            ConicGradient{},
 	"counter":                   Counter{},
+	"counters":                  Counters{},
 	"cubic-bezier":              CubicBezier{},
 	"hsl":                       Hsl{},
 	"hsla":                      Hsla{},
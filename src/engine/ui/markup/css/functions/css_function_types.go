@@ -26,6 +26,11 @@ type Counter struct{}
 
 func (f Counter) Key() string { return "counter" }
 
+// Returns the values of the named counter and all of its outer counters
+type Counters struct{}
+
+func (f Counters) Key() string { return "counters" }
+
 // Defines a Cubic Bezier curve
 type CubicBezier struct{}
 
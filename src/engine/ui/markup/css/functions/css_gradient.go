@@ -0,0 +1,148 @@
+/******************************************************************************/
+/* css_gradient.go                                                            */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/matrix"
+)
+
+// IsGradient reports if the value is one of the gradient functions
+func IsGradient(value rules.PropertyValue) bool {
+	switch value.Str {
+	case "linear-gradient", "repeating-linear-gradient",
+		"radial-gradient", "repeating-radial-gradient":
+		return true
+	}
+	return false
+}
+
+// GradientValue joins a gradient with the rgb() and rgba() colors inside of
+// it, which the parser splits off into values of their own along with the
+// rest of the arguments of the gradient. It returns the joined gradient and
+// how many of the values it used
+func GradientValue(values []rules.PropertyValue) (rules.PropertyValue, int) {
+	out := values[0].Clone()
+	used := 1
+	for ; used < len(values); used++ {
+		v := values[used]
+		count := map[string]int{"rgb": 3, "rgba": 4}[v.Str]
+		if v.Separated || count == 0 || len(v.Args) < count {
+			break
+		}
+		color := v.Clone()
+		color.Args = color.Args[:count]
+		var hex string
+		if count == 3 {
+			hex, _ = Rgb{}.Process(nil, nil, color)
+		} else {
+			hex, _ = Rgba{}.Process(nil, nil, color)
+		}
+		out.Args = append(out.Args, hex)
+		out.Args = append(out.Args, v.Args[count:]...)
+	}
+	return out, used
+}
+
+// ParseGradient reads a linear or radial gradient (or their repeating
+// versions) that has been joined with GradientValue
+func ParseGradient(value rules.PropertyValue, window helpers.WindowDimensions) (ui.Gradient, error) {
+	var g ui.Gradient
+	var stops []string
+	var err error
+	switch value.Str {
+	case "linear-gradient", "repeating-linear-gradient":
+		g, stops, err = parseLinearGradient(value.Args)
+	case "radial-gradient", "repeating-radial-gradient":
+		g, stops, err = parseRadialGradient(value.Args, window)
+	default:
+		return g, fmt.Errorf("unsupported gradient %s", value.Str)
+	}
+	if err != nil {
+		return g, err
+	}
+	g.Repeating = strings.HasPrefix(value.Str, "repeating-")
+	g.Stops, err = parseGradientStops(stops, window)
+	return g, err
+}
+
+// gradientColor reads a color of a color stop, rgb() and rgba() colors
+// have already been turned into hex by GradientValue
+func gradientColor(str string) (matrix.Color, bool) {
+	if str == "transparent" {
+		return matrix.ColorTransparent(), true
+	}
+	if hex, ok := helpers.ColorMap[str]; ok {
+		str = hex
+	} else if !strings.HasPrefix(str, "#") {
+		return matrix.Color{}, false
+	}
+	c, err := matrix.ColorFromHexString(str)
+	return c, err == nil
+}
+
+// gradientArgsUntilColor splits the arguments at the first color stop
+func gradientArgsUntilColor(args []string) ([]string, []string) {
+	for i := range args {
+		if _, ok := gradientColor(args[i]); ok {
+			return args[:i], args[i:]
+		}
+	}
+	return args, nil
+}
+
+func gradientLength(str string, window helpers.WindowDimensions) ui.ClipLength {
+	return ui.ClipLength{
+		Value:   helpers.NumFromLength(str, window),
+		Percent: strings.HasSuffix(str, "%"),
+	}
+}
+
+func isGradientLength(str string) bool {
+	return str != "" && (str[0] == '-' || str[0] == '.' || (str[0] >= '0' && str[0] <= '9'))
+}
+
+// parseGradientStops reads "color [position [position]]" stops, a second
+// position is the same as writing the color again at that position
+func parseGradientStops(args []string, window helpers.WindowDimensions) ([]ui.GradientStop, error) {
+	stops := []ui.GradientStop{}
+	positions := 0
+	for _, arg := range args {
+		if c, ok := gradientColor(arg); ok {
+			stops = append(stops, ui.GradientStop{Color: c})
+			positions = 0
+			continue
+		}
+		if !isGradientLength(arg) || len(stops) == 0 {
+			return nil, fmt.Errorf("invalid gradient color stop %s", arg)
+		}
+		switch positions {
+		case 0:
+			last := &stops[len(stops)-1]
+			last.Position, last.HasPosition = gradientLength(arg, window), true
+		case 1:
+			stops = append(stops, ui.GradientStop{
+				Color:       stops[len(stops)-1].Color,
+				Position:    gradientLength(arg, window),
+				HasPosition: true,
+			})
+		default:
+			return nil, errors.New("color hints of gradients are not supported")
+		}
+		positions++
+	}
+	if len(stops) < 2 {
+		return nil, errors.New("a gradient needs at least 2 color stops")
+	}
+	return stops, nil
+}
@@ -7,13 +7,60 @@
 package functions
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
 )
 
+// Gradients are images, they are read with ParseGradient by the properties
+// that take an image rather than being turned into a string
 func (f LinearGradient) Process(panel *ui.Panel, elm *document.Element, value rules.PropertyValue) (string, error) {
-	return "", errors.New("not implemented")
+	return "", fmt.Errorf("%s can only be used as an image", value.Str)
+}
+
+// [angle|to side-or-corner,] color-stop-list
+func parseLinearGradient(args []string) (ui.Gradient, []string, error) {
+	g := ui.Gradient{Kind: ui.GradientLinear, Angle: 180}
+	prelude, stops := gradientArgsUntilColor(args)
+	if len(prelude) == 0 {
+		return g, stops, nil
+	}
+	if prelude[0] != "to" {
+		if len(prelude) != 1 {
+			return g, nil, fmt.Errorf("unexpected gradient values %v", prelude)
+		}
+		a, err := helpers.AngleFromStr(prelude[0])
+		g.Angle = a
+		return g, stops, err
+	}
+	var corner matrix.Vec2
+	for _, side := range prelude[1:] {
+		switch side {
+		case "left":
+			corner.SetX(-1)
+		case "right":
+			corner.SetX(1)
+		case "top":
+			corner.SetY(-1)
+		case "bottom":
+			corner.SetY(1)
+		default:
+			return g, nil, fmt.Errorf("invalid gradient direction %s", side)
+		}
+	}
+	if len(prelude) < 2 || len(prelude) > 3 || corner == (matrix.Vec2{}) {
+		return g, nil, fmt.Errorf("invalid gradient direction %v", prelude)
+	}
+	if corner.X() != 0 && corner.Y() != 0 {
+		g.Corner = corner
+	} else {
+		g.Angle = map[matrix.Vec2]float32{
+			{0, -1}: 0, {1, 0}: 90, {0, 1}: 180, {-1, 0}: 270,
+		}[corner]
+	}
+	return g, stops, nil
 }
@@ -7,13 +7,97 @@
 package functions
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+var radialGradientExtents = map[string]ui.GradientExtent{
+	"closest-side":    ui.GradientExtentClosestSide,
+	"closest-corner":  ui.GradientExtentClosestCorner,
+	"farthest-side":   ui.GradientExtentFarthestSide,
+	"farthest-corner": ui.GradientExtentFarthestCorner,
+}
+
 func (f RadialGradient) Process(panel *ui.Panel, elm *document.Element, value rules.PropertyValue) (string, error) {
-	return "", errors.New("not implemented")
+	return "", fmt.Errorf("%s can only be used as an image", value.Str)
+}
+
+// [shape || size] [at position,] color-stop-list
+func parseRadialGradient(args []string, window helpers.WindowDimensions) (ui.Gradient, []string, error) {
+	center := ui.ClipLength{Value: 0.5, Percent: true}
+	g := ui.Gradient{Kind: ui.GradientRadial, Center: [2]ui.ClipLength{center, center}}
+	prelude, stops := gradientArgsUntilColor(args)
+	sizes := 0
+	ellipse := false
+	for i := 0; i < len(prelude); i++ {
+		arg := prelude[i]
+		if extent, ok := radialGradientExtents[arg]; ok {
+			g.Extent = extent
+			continue
+		}
+		switch {
+		case arg == "circle":
+			g.Circle = true
+		case arg == "ellipse":
+			ellipse = true
+		case arg == "at":
+			pos, err := ParsePosition(prelude[i+1:], window)
+			if err != nil {
+				return g, nil, err
+			}
+			g.Center = pos
+			i = len(prelude)
+		case isGradientLength(arg) && sizes < 2:
+			g.Size[sizes] = gradientLength(arg, window)
+			g.Extent = ui.GradientExtentSize
+			sizes++
+		default:
+			return g, nil, fmt.Errorf("unexpected gradient value %s", arg)
+		}
+	}
+	if sizes == 1 && !ellipse {
+		g.Circle = true
+	}
+	if g.Extent == ui.GradientExtentSize && (g.Circle && sizes != 1 || !g.Circle && sizes != 2) {
+		return g, nil, fmt.Errorf("invalid size for a radial gradient %v", prelude)
+	}
+	return g, stops, nil
+}
+
+// ParsePosition reads a position of one or two keywords or lengths, the
+// same as the position of a radial gradient or a clip path shape
+func ParsePosition(args []string, window helpers.WindowDimensions) ([2]ui.ClipLength, error) {
+	center := ui.ClipLength{Value: 0.5, Percent: true}
+	pos := [2]ui.ClipLength{center, center}
+	if len(args) == 0 || len(args) > 2 {
+		return pos, fmt.Errorf("expected 1 or 2 values for a position but got %d", len(args))
+	}
+	first, second := args[0], ""
+	if len(args) == 2 {
+		second = args[1]
+	}
+	if first == "top" || first == "bottom" || second == "left" || second == "right" {
+		first, second = second, first
+	}
+	keywords := [2]map[string]float32{
+		{"left": 0, "center": 0.5, "right": 1},
+		{"top": 0, "center": 0.5, "bottom": 1},
+	}
+	for i, str := range [2]string{first, second} {
+		if str == "" {
+			continue
+		}
+		if k, ok := keywords[i][str]; ok {
+			pos[i] = ui.ClipLength{Value: k, Percent: true}
+		} else if isGradientLength(str) {
+			pos[i] = gradientLength(str, window)
+		} else {
+			return pos, fmt.Errorf("invalid position %s", str)
+		}
+	}
+	return pos, nil
 }
@@ -7,7 +7,7 @@
 package functions
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
@@ -15,5 +15,5 @@ import (
 )
 
 func (f RepeatingLinearGradient) Process(panel *ui.Panel, elm *document.Element, value rules.PropertyValue) (string, error) {
-	return "", errors.New("not implemented")
+	return "", fmt.Errorf("%s can only be used as an image", value.Str)
 }
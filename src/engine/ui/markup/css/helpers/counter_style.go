@@ -0,0 +1,104 @@
+/******************************************************************************/
+/* counter_style.go                                                           */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package helpers
+
+import (
+	"strconv"
+	"strings"
+)
+
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, "m"}, {900, "cm"}, {500, "d"}, {400, "cd"},
+	{100, "c"}, {90, "xc"}, {50, "l"}, {40, "xl"},
+	{10, "x"}, {9, "ix"}, {5, "v"}, {4, "iv"}, {1, "i"},
+}
+
+var counterStyleSymbols = map[string]string{
+	"disc":   "•",
+	"circle": "◦",
+	"square": "▪",
+}
+
+// IsCounterStyle returns true if the style is one of the supported
+// list-style-type or counter() styles
+func IsCounterStyle(style string) bool {
+	switch style {
+	case "none", "decimal", "decimal-leading-zero", "lower-roman", "upper-roman",
+		"lower-alpha", "lower-latin", "upper-alpha", "upper-latin", "lower-greek":
+		return true
+	}
+	_, ok := counterStyleSymbols[style]
+	return ok
+}
+
+// FormatCounter writes the counter value in the given counter style, styles
+// that can not represent the value (such as roman numerals for 0) fall back
+// to decimal. Unknown styles are treated as decimal
+func FormatCounter(value int, style string) string {
+	if symbol, ok := counterStyleSymbols[style]; ok {
+		return symbol
+	}
+	switch style {
+	case "none":
+		return ""
+	case "decimal-leading-zero":
+		if value >= 0 && value < 10 {
+			return "0" + strconv.Itoa(value)
+		}
+	case "lower-roman", "upper-roman":
+		if value > 0 && value < 4000 {
+			sb := strings.Builder{}
+			for _, r := range romanNumerals {
+				for ; value >= r.value; value -= r.value {
+					sb.WriteString(r.symbol)
+				}
+			}
+			if style == "upper-roman" {
+				return strings.ToUpper(sb.String())
+			}
+			return sb.String()
+		}
+	case "lower-alpha", "lower-latin", "upper-alpha", "upper-latin":
+		if value > 0 {
+			return alphabeticCounter(value, 'a', 26, style[0] == 'u')
+		}
+	case "lower-greek":
+		if value > 0 {
+			return alphabeticCounter(value, 'α', 24, false)
+		}
+	}
+	return strconv.Itoa(value)
+}
+
+// CounterSuffix returns the text that follows the counter in a list marker
+func CounterSuffix(style string) string {
+	if _, ok := counterStyleSymbols[style]; ok || style == "none" {
+		return " "
+	}
+	return ". "
+}
+
+func alphabeticCounter(value int, first rune, count int, upper bool) string {
+	out := []rune{}
+	for value > 0 {
+		value--
+		r := first + rune(value%count)
+		// Skip the final sigma which is not used as a counter symbol
+		if first == 'α' && r >= 'ς' {
+			r++
+		}
+		out = append([]rune{r}, out...)
+		value /= count
+	}
+	if upper {
+		return strings.ToUpper(string(out))
+	}
+	return string(out)
+}
@@ -0,0 +1,45 @@
+/******************************************************************************/
+/* counter_style_test.go                                                      */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package helpers
+
+import "testing"
+
+func TestFormatCounter(t *testing.T) {
+	tests := []struct {
+		value int
+		style string
+		want  string
+	}{
+		{3, "decimal", "3"},
+		{7, "decimal-leading-zero", "07"},
+		{12, "decimal-leading-zero", "12"},
+		{1994, "upper-roman", "MCMXCIV"},
+		{4, "lower-roman", "iv"},
+		{0, "lower-roman", "0"},
+		{1, "lower-alpha", "a"},
+		{27, "upper-latin", "AA"},
+		{18, "lower-greek", "σ"},
+		{5, "disc", "•"},
+		{5, "square", "▪"},
+		{5, "none", ""},
+		{5, "unknown", "5"},
+	}
+	for _, test := range tests {
+		if got := FormatCounter(test.value, test.style); got != test.want {
+			t.Errorf("FormatCounter(%d, %q) expected %q, got %q", test.value, test.style, test.want, got)
+		}
+	}
+}
+
+func TestCounterSuffix(t *testing.T) {
+	if CounterSuffix("decimal") != ". " || CounterSuffix("disc") != " " {
+		t.Fatal("unexpected list marker suffix")
+	}
+	if !IsCounterStyle("upper-roman") || IsCounterStyle("roman") {
+		t.Fatal("unexpected counter style validation")
+	}
+}
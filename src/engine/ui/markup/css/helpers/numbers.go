@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"kaijuengine.com/matrix"
 	"kaijuengine.com/rendering"
 )
 
@@ -155,3 +156,29 @@ func NumFromLengthWithFont(str string, window WindowDimensions, fontSize float32
 func NumFromLength(str string, window WindowDimensions) float32 {
 	return NumFromLengthWithFont(str, window, rendering.DefaultFontEMSize)
 }
+
+// AngleFromStr converts a CSS angle into degrees
+func AngleFromStr(str string) (float32, error) {
+	units := []struct {
+		suffix string
+		scale  float64
+	}{
+		{"deg", 1},
+		{"grad", 0.9},
+		{"rad", float64(matrix.Rad2Deg(1))},
+		{"turn", 360},
+	}
+	for _, u := range units {
+		if strings.HasSuffix(str, u.suffix) {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(str, u.suffix), 64)
+			if err != nil {
+				return 0, err
+			}
+			return float32(v * u.scale), nil
+		}
+	}
+	if str == "0" {
+		return 0, nil
+	}
+	return 0, fmt.Errorf("invalid angle %s", str)
+}
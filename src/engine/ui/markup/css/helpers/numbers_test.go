@@ -10,6 +10,7 @@ import (
 	"math"
 	"testing"
 
+	"kaijuengine.com/matrix"
 	"kaijuengine.com/rendering"
 )
 
@@ -153,3 +154,13 @@ func TestNumFromLengthWithFont_NonFiniteInput(t *testing.T) {
 		})
 	}
 }
+
+func TestAngleFromStr(t *testing.T) {
+	tests := map[string]float32{"90deg": 90, "100grad": 90, "0.25turn": 90, "0": 0}
+	for str, want := range tests {
+		got, err := AngleFromStr(str)
+		if err != nil || !matrix.ApproxTo(got, want, 0.001) {
+			t.Errorf("%s: expected %f, got %f (%v)", str, want, got, err)
+		}
+	}
+}
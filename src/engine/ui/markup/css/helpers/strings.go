@@ -0,0 +1,58 @@
+/******************************************************************************/
+/* strings.go                                                                 */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package helpers
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IsQuotedString returns true if the value is a CSS string token
+func IsQuotedString(str string) bool {
+	return len(str) >= 2 && (str[0] == '"' || str[0] == '\'') && str[len(str)-1] == str[0]
+}
+
+// UnquoteString removes the quotes from a CSS string and resolves its escapes,
+// such as "\201C" for a left double quotation mark. Strings that are not
+// quoted are returned as they are
+func UnquoteString(str string) string {
+	if !IsQuotedString(str) {
+		return str
+	}
+	str = str[1 : len(str)-1]
+	if !strings.ContainsRune(str, '\\') {
+		return str
+	}
+	sb := strings.Builder{}
+	for i := 0; i < len(str); i++ {
+		if str[i] != '\\' || i+1 >= len(str) {
+			sb.WriteByte(str[i])
+			continue
+		}
+		end := i + 1
+		for end < len(str) && end-i <= 6 && isHexDigit(str[end]) {
+			end++
+		}
+		if end == i+1 {
+			sb.WriteByte(str[end])
+			i = end
+			continue
+		}
+		code, _ := strconv.ParseUint(str[i+1:end], 16, 32)
+		sb.WriteRune(rune(code))
+		// A single white space after a hex escape ends the escape
+		if end < len(str) && str[end] == ' ' {
+			end++
+		}
+		i = end - 1
+	}
+	return sb.String()
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,27 @@
+/******************************************************************************/
+/* strings_test.go                                                            */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package helpers
+
+import "testing"
+
+func TestUnquoteString(t *testing.T) {
+	tests := map[string]string{
+		`"abc"`:       "abc",
+		`'a b'`:       "a b",
+		`"\201C"`:     "“",
+		`"\2014 x"`:   "—x",
+		`"a\"b"`:      `a"b`,
+		`unquoted`:    "unquoted",
+		`"\A"`:        "\n",
+		`"\00A0text"`: " text",
+	}
+	for in, want := range tests {
+		if got := UnquoteString(in); got != want {
+			t.Errorf("UnquoteString(%s) expected %q, got %q", in, want, got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+/******************************************************************************/
+/* timing.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package helpers
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// ParseCubicBezierArgs reads the 4 control point arguments of a CSS
+// cubic-bezier() function, the x values must be within [0, 1]
+func ParseCubicBezierArgs(args []string) ([4]float32, error) {
+	out := [4]float32{}
+	if len(args) != 4 {
+		return out, errors.New("cubic-bezier expects 4 arguments")
+	}
+	for i := range args {
+		f, err := strconv.ParseFloat(args[i], 32)
+		if err != nil {
+			return out, err
+		}
+		out[i] = float32(f)
+	}
+	if out[0] < 0 || out[0] > 1 || out[2] < 0 || out[2] > 1 {
+		return out, errors.New("cubic-bezier x values must be within [0, 1]")
+	}
+	return out, nil
+}
+
+// CubicBezier returns an easing function for the curve going from (0, 0) to
+// (1, 1) through the control points (x1, y1) and (x2, y2), just like the CSS
+// cubic-bezier() timing function
+func CubicBezier(x1, y1, x2, y2 float32) func(t float32) float32 {
+	if x1 == y1 && x2 == y2 {
+		return func(t float32) float32 { return t }
+	}
+	cx := 3 * x1
+	bx := 3*(x2-x1) - cx
+	ax := 1 - cx - bx
+	cy := 3 * y1
+	by := 3*(y2-y1) - cy
+	ay := 1 - cy - by
+	sampleX := func(s float32) float32 { return ((ax*s+bx)*s + cx) * s }
+	sampleY := func(s float32) float32 { return ((ay*s+by)*s + cy) * s }
+	slopeX := func(s float32) float32 { return (3*ax*s+2*bx)*s + cx }
+	const epsilon = 1e-6
+	solveX := func(x float32) float32 {
+		// Newton-Raphson is fast for most curves, fall back to bisection for
+		// the ones with a flat slope
+		s := x
+		for range 8 {
+			diff := sampleX(s) - x
+			if abs32(diff) < epsilon {
+				return s
+			}
+			d := slopeX(s)
+			if abs32(d) < epsilon {
+				break
+			}
+			s -= diff / d
+		}
+		lo, hi := float32(0), float32(1)
+		s = x
+		for range 32 {
+			diff := sampleX(s) - x
+			if abs32(diff) < epsilon {
+				break
+			}
+			if diff > 0 {
+				hi = s
+			} else {
+				lo = s
+			}
+			s = (lo + hi) * 0.5
+		}
+		return s
+	}
+	return func(t float32) float32 {
+		if t <= 0 {
+			return 0
+		} else if t >= 1 {
+			return 1
+		}
+		return sampleY(solveX(t))
+	}
+}
+
+func abs32(x float32) float32 { return float32(math.Abs(float64(x))) }
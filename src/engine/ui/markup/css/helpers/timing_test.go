@@ -0,0 +1,64 @@
+/******************************************************************************/
+/* timing_test.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package helpers
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCubicBezierEndpoints(t *testing.T) {
+	ease := CubicBezier(0.25, 0.1, 0.25, 1)
+	if ease(0) != 0 || ease(1) != 1 {
+		t.Fatalf("expected the curve to start at 0 and end at 1, got %f and %f", ease(0), ease(1))
+	}
+}
+
+func TestCubicBezierLinear(t *testing.T) {
+	linear := CubicBezier(0.5, 0.5, 0.5, 0.5)
+	for _, v := range []float32{0.1, 0.33, 0.5, 0.9} {
+		if linear(v) != v {
+			t.Fatalf("expected %f, got %f", v, linear(v))
+		}
+	}
+}
+
+func TestCubicBezierKnownValues(t *testing.T) {
+	tests := []struct {
+		curve    [4]float32
+		x        float32
+		expected float32
+	}{
+		// Reference values from the browser implementations of the keywords
+		{[4]float32{0.25, 0.1, 0.25, 1}, 0.5, 0.8024033},
+		{[4]float32{0.42, 0, 1, 1}, 0.5, 0.3153568},
+		{[4]float32{0, 0, 0.58, 1}, 0.5, 0.6846432},
+		{[4]float32{0.42, 0, 0.58, 1}, 0.5, 0.5},
+	}
+	for _, tt := range tests {
+		f := CubicBezier(tt.curve[0], tt.curve[1], tt.curve[2], tt.curve[3])
+		if got := f(tt.x); math.Abs(float64(got-tt.expected)) > 1e-3 {
+			t.Fatalf("cubic-bezier(%v) at %f expected %f, got %f", tt.curve, tt.x, tt.expected, got)
+		}
+	}
+}
+
+func TestParseCubicBezierArgs(t *testing.T) {
+	p, err := ParseCubicBezierArgs([]string{"0.1", "-0.5", "0.9", "1.5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != [4]float32{0.1, -0.5, 0.9, 1.5} {
+		t.Fatalf("unexpected control points %v", p)
+	}
+	if _, err := ParseCubicBezierArgs([]string{"1.1", "0", "0", "1"}); err == nil {
+		t.Fatal("expected an error for an x value outside of [0, 1]")
+	}
+	if _, err := ParseCubicBezierArgs([]string{"0", "0", "1"}); err == nil {
+		t.Fatal("expected an error for a missing argument")
+	}
+}
@@ -0,0 +1,164 @@
+/******************************************************************************/
+/* media.go                                                                   */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package css
+
+import (
+	"slices"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+// Preferences are the user settings tested by the prefers-* media features.
+// Games set these from their own settings, open documents need ApplyStyles
+// to be called for a change to take effect
+type Preferences struct {
+	ReducedMotion   bool
+	DarkColorScheme bool
+}
+
+var UserPreferences Preferences
+
+type containerWatch struct {
+	container *document.Element
+	query     *rules.MediaQuery
+}
+
+type containerInfo struct {
+	names      []string
+	inlineOnly bool
+}
+
+func (z Stylizer) viewportFeatures() rules.MediaFeatures {
+	f := rules.MediaFeatures{
+		ReducedMotion:   UserPreferences.ReducedMotion,
+		DarkColorScheme: UserPreferences.DarkColorScheme,
+	}
+	if z.Window != nil {
+		f.Window = z.Window
+		f.Width = float32(z.Window.Width())
+		f.Height = float32(z.Window.Height())
+	}
+	return f
+}
+
+// findContainers returns the elements that are size containers, which is
+// found by matching every group that isn't itself a container query
+func findContainers(s rules.StyleSheet, doc *document.Document, viewport rules.MediaFeatures) map[*document.Element]containerInfo {
+	cssMap := CSSMap{}
+	for _, group := range s.Groups {
+		if group.MediaQuery.Container || !group.MediaQuery.MatchesViewport(viewport) {
+			continue
+		}
+		for _, sel := range group.Selectors {
+			applyIndirect(&group, sel.Parts, weightedRules(group, sel), doc, cssMap)
+		}
+	}
+	cleanMapDuplicates(cssMap)
+	containers := map[*document.Element]containerInfo{}
+	for _, elm := range doc.Elements {
+		info, kind := containerInfo{}, ""
+		for _, r := range cssMap[elm.UI] {
+			if r.Invocation != rules.RuleInvokeImmediate || r.PseudoElement != "" {
+				continue
+			}
+			switch r.Property {
+			case "container-type":
+				kind = firstValue(r.Values)
+			case "container-name":
+				info.names = valueStrings(r.Values)
+			case "container":
+				// <name> [/ <type>]
+				info.names, kind = nil, "normal"
+				for i, v := range r.Values {
+					if v.Str == "/" {
+						kind = firstValue(r.Values[i+1:])
+						break
+					}
+					info.names = append(info.names, v.Str)
+				}
+			}
+		}
+		if kind == "size" || kind == "inline-size" {
+			info.inlineOnly = kind == "inline-size"
+			containers[elm] = info
+		}
+	}
+	return containers
+}
+
+func firstValue(values []rules.PropertyValue) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0].Str
+}
+
+func valueStrings(values []rules.PropertyValue) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v.Str != "none" {
+			out = append(out, v.Str)
+		}
+	}
+	return out
+}
+
+// queryContainer returns the closest ancestor container of the element that
+// the query can be tested against
+func queryContainer(elm *document.Element, query *rules.MediaQuery,
+	containers map[*document.Element]containerInfo) (*document.Element, containerInfo, bool) {
+	for p := elm.Parent.Value(); p != nil; p = p.Parent.Value() {
+		if info, ok := containers[p]; ok && (query.Name == "" || slices.Contains(info.names, query.Name)) {
+			return p, info, true
+		}
+	}
+	return nil, containerInfo{}, false
+}
+
+func containerFeatures(container *document.Element, info containerInfo, viewport rules.MediaFeatures) rules.MediaFeatures {
+	f := viewport
+	size := container.UI.Layout().ContentSize()
+	f.Width, f.Height = size.X(), size.Y()
+	f.InlineOnly = info.inlineOnly
+	return f
+}
+
+// applyContainerRules adds the rules of a container query group to the
+// elements that match the selector and whose container matches the query.
+// Every tested container is reported so the document can restyle when the
+// container is resized across the query
+func applyContainerRules(group *rules.SelectorGroup, parts []rules.SelectorPart, applyRules []rules.Rule,
+	doc *document.Document, cssMap CSSMap, containers map[*document.Element]containerInfo,
+	watched map[containerWatch]bool, checks *[]document.ContainerQuery, viewport rules.MediaFeatures) {
+	query := &group.MediaQuery
+	for _, elm := range doc.Elements {
+		if !groupReaches(group, elm) {
+			continue
+		}
+		container, info, ok := queryContainer(elm, query, containers)
+		if !ok {
+			continue
+		}
+		matched := query.MatchesContainer(containerFeatures(container, info, viewport))
+		if key := (containerWatch{container, query}); !watched[key] {
+			watched[key] = true
+			*checks = append(*checks, document.ContainerQuery{
+				Matched: matched,
+				Evaluate: func() bool {
+					return query.MatchesContainer(containerFeatures(container, info, viewport))
+				},
+			})
+		}
+		if !matched {
+			continue
+		}
+		if ok, selectorRules := selectorMatches(elm, parts, applyRules); ok {
+			cssMap.add(elm.UI, selectorRules)
+		}
+	}
+}
@@ -73,3 +73,42 @@ func TestCleanMapDuplicatesUnderstandsShorthandOverrides(t *testing.T) {
 		t.Fatalf("expected padding to remain, got %q", got[0].Property)
 	}
 }
+
+func TestCSSMapAddKeepsMoreSpecificEarlierRule(t *testing.T) {
+	elm := &ui.UI{}
+	cssMap := CSSMap{}
+
+	cssMap.add(elm, []rules.Rule{{Property: "color", Specificity: rules.Specificity{1, 0, 0}}})
+	cssMap.add(elm, []rules.Rule{{Property: "color", Specificity: rules.Specificity{0, 1, 0}}})
+
+	got := cssMap[elm]
+	if len(got) != 1 || got[0].Specificity != (rules.Specificity{1, 0, 0}) {
+		t.Fatalf("expected the id rule to win, got %#v", got)
+	}
+}
+
+func TestCSSMapAddLosingShorthandBeforeSpecificLonghand(t *testing.T) {
+	elm := &ui.UI{}
+	cssMap := CSSMap{}
+
+	cssMap.add(elm, []rules.Rule{{Property: "margin-top", Specificity: rules.Specificity{0, 1, 0}}})
+	cssMap.add(elm, []rules.Rule{{Property: "margin", Specificity: rules.Specificity{0, 0, 1}}})
+
+	got := cssMap[elm]
+	if len(got) != 2 || got[0].Property != "margin" || got[1].Property != "margin-top" {
+		t.Fatalf("expected margin then margin-top, got %#v", got)
+	}
+}
+
+func TestCSSMapAddAuthorBeatsUserAgent(t *testing.T) {
+	elm := &ui.UI{}
+	cssMap := CSSMap{}
+
+	cssMap.add(elm, []rules.Rule{{Property: "display", UserAgent: true, Specificity: rules.Specificity{1, 0, 0}}})
+	cssMap.add(elm, []rules.Rule{{Property: "display", Specificity: rules.Specificity{0, 0, 1}}})
+
+	got := cssMap[elm]
+	if len(got) != 1 || got[0].UserAgent {
+		t.Fatalf("expected the author rule to win, got %#v", got)
+	}
+}
@@ -15,8 +15,13 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// none|<filter-function>+|initial|inherit
+//
+// The UI passes don't read back what is drawn behind a panel, so only none
+// is accepted
 func (p BackdropFilter) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BackdropFilter not implemented")}
-
-	return problems[0]
+	if len(values) == 1 && (values[0].Str == "none" || values[0].Str == "initial") {
+		return nil
+	}
+	return errors.New("BackdropFilter not implemented")
 }
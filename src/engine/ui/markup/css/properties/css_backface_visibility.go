@@ -15,8 +15,18 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// visible|hidden|initial|inherit
 func (p BackfaceVisibility) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BackfaceVisibility not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return errors.New("backface-visibility expects 1 value")
+	}
+	switch values[0].Str {
+	case "visible", "initial", "inherit", "unset":
+		panel.SetBackfaceHidden(false)
+	case "hidden":
+		panel.SetBackfaceHidden(true)
+	default:
+		return errors.New("backface-visibility has unexpected value")
+	}
+	return nil
 }
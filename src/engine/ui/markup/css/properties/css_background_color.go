@@ -69,6 +69,15 @@ func (p BackgroundColor) Process(panel *ui.Panel, elm *document.Element, values
 		return err
 	}
 
+	if elm.PseudoElement() == document.PseudoSelection {
+		if panel.Base().IsType(ui.ElementTypeInput) {
+			panel.Base().ToInput().SetSelectColor(color)
+		} else if panel.Base().IsType(ui.ElementTypeTextArea) {
+			panel.Base().ToTextArea().SetSelectColor(color)
+		}
+		return nil
+	}
+
 	if isLabel {
 		elm.UI.ToLabel().SetBGColor(color)
 		return nil
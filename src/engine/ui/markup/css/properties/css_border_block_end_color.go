@@ -7,16 +7,14 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// color|transparent|initial|inherit
 func (p BorderBlockEndColor) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BorderBlockEndColor not implemented")}
-
-	return problems[0]
+	_, end := blockSides(panel)
+	return borderSideColorProperties[end].Process(panel, elm, values, host)
 }
@@ -7,16 +7,14 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// color|transparent|initial|inherit
 func (p BorderBlockStartColor) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BorderBlockStartColor not implemented")}
-
-	return problems[0]
+	start, _ := blockSides(panel)
+	return borderSideColorProperties[start].Process(panel, elm, values, host)
 }
@@ -7,16 +7,14 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// medium|thin|thick|length|initial|inherit
 func (p BorderBlockWidth) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BorderBlockWidth not implemented")}
-
-	return problems[0]
+	start, end := blockSides(panel)
+	return processLogicalSides(borderSideWidthProperties, start, end, panel, elm, values, host)
 }
@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,18 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// separate|collapse|initial|inherit
 func (p BorderCollapse) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BorderCollapse not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return fmt.Errorf("%s expects exactly one value", p.Key())
+	}
+	switch values[0].Str {
+	case "collapse":
+		panel.SetTableBorderCollapse(true)
+	case "separate", "initial", "inherit":
+		panel.SetTableBorderCollapse(false)
+	default:
+		return fmt.Errorf("%s expected separate or collapse, but got: %s", p.Key(), values[0].Str)
+	}
+	return nil
 }
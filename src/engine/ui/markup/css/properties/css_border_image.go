@@ -8,15 +8,191 @@ package properties
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p BorderImage) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BorderImage not implemented")}
+var borderImageURL = regexp.MustCompile(`^url\s*\(\s*["']?(.*?)["']?\s*\)$`)
+
+var borderImageRepeatModes = map[string]ui.BorderImageRepeatMode{
+	"stretch": ui.BorderImageStretch,
+	"repeat":  ui.BorderImageRepeat,
+	"round":   ui.BorderImageRound,
+	"space":   ui.BorderImageSpace,
+}
+
+func borderImageTexture(str string, host *engine.Host) (*rendering.Texture, error) {
+	if str == "none" {
+		return nil, nil
+	}
+	parts := borderImageURL.FindStringSubmatch(str)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("expected none or a url for the border image but got %s", str)
+	}
+	return host.TextureCache().Texture(strings.TrimSpace(parts[1]), rendering.TextureFilterLinear)
+}
+
+// borderImageLength reads a length, a percentage or a plain number of one
+// side of a border image
+func borderImageLength(str string, window helpers.WindowDimensions, allowAuto, allowLength bool) (ui.BorderImageLength, error) {
+	if str == "auto" && allowAuto {
+		return ui.BorderImageLength{Unit: ui.BorderImageAuto}, nil
+	}
+	if strings.HasSuffix(str, "%") {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(str, "%"), 32)
+		if err != nil || v < 0 {
+			return ui.BorderImageLength{}, fmt.Errorf("invalid percentage %s", str)
+		}
+		return ui.BorderImageLength{Value: float32(v) / 100, Unit: ui.BorderImagePercent}, nil
+	}
+	if v, err := strconv.ParseFloat(str, 32); err == nil {
+		if v < 0 {
+			return ui.BorderImageLength{}, fmt.Errorf("negative border image value %s", str)
+		}
+		return ui.BorderImageLength{Value: float32(v), Unit: ui.BorderImageNumber}, nil
+	}
+	if !allowLength || str == "" || (str[0] < '0' || str[0] > '9') && str[0] != '.' {
+		return ui.BorderImageLength{}, fmt.Errorf("invalid border image value %s", str)
+	}
+	return ui.BorderImageLength{Value: helpers.NumFromLength(str, window), Unit: ui.BorderImagePixels}, nil
+}
+
+// borderImageFourSides reads 1 to 4 values into the top, right, bottom and
+// left sides the same way as margin
+func borderImageFourSides(strs []string, window helpers.WindowDimensions, allowAuto, allowLength bool) ([4]ui.BorderImageLength, error) {
+	var sides [4]ui.BorderImageLength
+	if len(strs) == 0 || len(strs) > 4 {
+		return sides, fmt.Errorf("expected 1 to 4 values but got %d", len(strs))
+	}
+	for i := range strs {
+		l, err := borderImageLength(strs[i], window, allowAuto, allowLength)
+		if err != nil {
+			return sides, err
+		}
+		sides[i] = l
+	}
+	switch len(strs) {
+	case 1:
+		sides[1], sides[2], sides[3] = sides[0], sides[0], sides[0]
+	case 2:
+		sides[2], sides[3] = sides[0], sides[1]
+	case 3:
+		sides[3] = sides[1]
+	}
+	return sides, nil
+}
 
-	return problems[0]
+// borderImageSlice reads the slice numbers and percentages, fill can come
+// before or after them
+func borderImageSlice(strs []string, window helpers.WindowDimensions) ([4]ui.BorderImageLength, bool, error) {
+	fill := false
+	sides := make([]string, 0, len(strs))
+	for _, s := range strs {
+		if s == "fill" {
+			fill = true
+		} else {
+			sides = append(sides, s)
+		}
+	}
+	slice, err := borderImageFourSides(sides, window, false, false)
+	return slice, fill, err
+}
+
+func isBorderImageRepeat(str string) bool {
+	_, ok := borderImageRepeatModes[str]
+	return ok
+}
+
+func borderImageRepeat(strs []string) ([2]ui.BorderImageRepeatMode, error) {
+	var modes [2]ui.BorderImageRepeatMode
+	if len(strs) == 0 || len(strs) > 2 {
+		return modes, fmt.Errorf("expected 1 or 2 repeat values but got %d", len(strs))
+	}
+	for i, s := range strs {
+		m, ok := borderImageRepeatModes[s]
+		if !ok {
+			return modes, fmt.Errorf("invalid border image repeat %s", s)
+		}
+		modes[i] = m
+	}
+	if len(strs) == 1 {
+		modes[1] = modes[0]
+	}
+	return modes, nil
+}
+
+func valueStrings(values []rules.PropertyValue) []string {
+	out := make([]string, len(values))
+	for i := range values {
+		out[i] = values[i].Str
+	}
+	return out
+}
+
+// source || slice [/ width | / width? / outset]? || repeat
+func (p BorderImage) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) == 0 {
+		return errors.New("BorderImage requires at least 1 value")
+	}
+	img := ui.DefaultBorderImage()
+	if len(values) == 1 && (values[0].Str == "none" || values[0].Str == "initial") {
+		panel.SetBorderImage(img)
+		return nil
+	}
+	var slice, repeat []string
+	var sizes [2][]string
+	section := 0
+	for _, v := range values {
+		str := v.Str
+		switch {
+		case str == "/":
+			if section++; section > 2 {
+				return errors.New("too many / in the border image")
+			}
+		case section > 0:
+			sizes[section-1] = append(sizes[section-1], str)
+		case str == "none" || strings.HasPrefix(str, "url"):
+			tex, err := borderImageTexture(str, host)
+			if err != nil {
+				return err
+			}
+			img.Texture = tex
+		case isBorderImageRepeat(str):
+			repeat = append(repeat, str)
+		default:
+			slice = append(slice, str)
+		}
+	}
+	var err error
+	if len(slice) > 0 {
+		if img.Slice, img.Fill, err = borderImageSlice(slice, host.Window); err != nil {
+			return err
+		}
+	}
+	if len(sizes[0]) > 0 {
+		if img.Width, err = borderImageFourSides(sizes[0], host.Window, true, true); err != nil {
+			return err
+		}
+	}
+	if len(sizes[1]) > 0 {
+		if img.Outset, err = borderImageFourSides(sizes[1], host.Window, false, true); err != nil {
+			return err
+		}
+	}
+	if len(repeat) > 0 {
+		if img.Repeat, err = borderImageRepeat(repeat); err != nil {
+			return err
+		}
+	}
+	panel.SetBorderImage(img)
+	return nil
 }
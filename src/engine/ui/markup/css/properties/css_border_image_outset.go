@@ -7,16 +7,24 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// length|number{1,4}|initial
 func (p BorderImageOutset) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BorderImageOutset not implemented")}
-
-	return problems[0]
+	img := panel.BorderImage()
+	if len(values) == 1 && values[0].Str == "initial" {
+		img.Outset = ui.DefaultBorderImage().Outset
+	} else {
+		outset, err := borderImageFourSides(valueStrings(values), host.Window, false, true)
+		if err != nil {
+			return err
+		}
+		img.Outset = outset
+	}
+	panel.SetBorderImage(img)
+	return nil
 }
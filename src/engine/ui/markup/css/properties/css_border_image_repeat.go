@@ -7,16 +7,24 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// stretch|repeat|round|space{1,2}|initial
 func (p BorderImageRepeat) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BorderImageRepeat not implemented")}
-
-	return problems[0]
+	img := panel.BorderImage()
+	if len(values) == 1 && values[0].Str == "initial" {
+		img.Repeat = ui.DefaultBorderImage().Repeat
+	} else {
+		repeat, err := borderImageRepeat(valueStrings(values))
+		if err != nil {
+			return err
+		}
+		img.Repeat = repeat
+	}
+	panel.SetBorderImage(img)
+	return nil
 }
@@ -7,22 +7,30 @@
 package properties
 
 import (
-	"fmt"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
-	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 	"kaijuengine.com/matrix"
 )
 
+// number|%{1,4} && fill?|initial
 func (p BorderImageSlice) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	if len(values) != 1 {
-		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	img := panel.BorderImage()
+	if len(values) == 1 && values[0].Str == "initial" {
+		img.Slice, img.Fill = ui.DefaultBorderImage().Slice, false
+		panel.SetBorderImage(img)
+		return nil
+	}
+	slice, fill, err := borderImageSlice(valueStrings(values), host.Window)
+	if err != nil {
+		return err
+	}
+	img.Slice, img.Fill = slice, fill
+	panel.SetBorderImage(img)
+	// A single size also nine-slices the background image of the panel
+	if len(values) == 1 && slice[0].Unit == ui.BorderImageNumber {
+		panel.Base().ShaderData().BorderLen = matrix.NewVec2(slice[0].Value, slice[0].Value)
 	}
-
-	width := helpers.NumFromLength(values[0].Str, host.Window)
-	panel.Base().ShaderData().BorderLen = matrix.NewVec2(width, width)
 	return nil
 }
@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,26 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// none|url|initial|inherit
 func (p BorderImageSource) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BorderImageSource not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	img := panel.BorderImage()
+	switch values[0].Str {
+	case "initial":
+		img.Texture = nil
+	case "inherit":
+		if elm.Parent.Value() != nil {
+			img.Texture = elm.Parent.Value().UI.ToPanel().BorderImage().Texture
+		}
+	default:
+		tex, err := borderImageTexture(values[0].Str, host)
+		if err != nil {
+			return err
+		}
+		img.Texture = tex
+	}
+	panel.SetBorderImage(img)
+	return nil
 }
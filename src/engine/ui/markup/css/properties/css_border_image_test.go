@@ -0,0 +1,64 @@
+/******************************************************************************/
+/* css_border_image_test.go                                                   */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package properties
+
+import (
+	"testing"
+
+	"kaijuengine.com/engine/ui"
+)
+
+func TestBorderImageFourSides(t *testing.T) {
+	sides, err := borderImageFourSides([]string{"10px", "auto", "2"}, testWindow{}, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [4]ui.BorderImageLength{
+		{Value: 10, Unit: ui.BorderImagePixels},
+		{Unit: ui.BorderImageAuto},
+		{Value: 2, Unit: ui.BorderImageNumber},
+		{Unit: ui.BorderImageAuto},
+	}
+	if sides != want {
+		t.Fatalf("expected %+v, got %+v", want, sides)
+	}
+	if _, err := borderImageFourSides([]string{"auto"}, testWindow{}, false, true); err == nil {
+		t.Fatal("expected auto to be invalid for an outset")
+	}
+	if _, err := borderImageFourSides([]string{"-1"}, testWindow{}, false, true); err == nil {
+		t.Fatal("expected a negative value to be invalid")
+	}
+}
+
+func TestBorderImageSlice(t *testing.T) {
+	slice, fill, err := borderImageSlice([]string{"fill", "30", "25%"}, testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	thirty := ui.BorderImageLength{Value: 30, Unit: ui.BorderImageNumber}
+	quarter := ui.BorderImageLength{Value: 0.25, Unit: ui.BorderImagePercent}
+	if !fill || slice != [4]ui.BorderImageLength{thirty, quarter, thirty, quarter} {
+		t.Fatalf("unexpected slice %+v, fill %v", slice, fill)
+	}
+	if _, _, err := borderImageSlice([]string{"10px"}, testWindow{}); err == nil {
+		t.Fatal("expected a slice with units to be invalid")
+	}
+}
+
+func TestBorderImageRepeat(t *testing.T) {
+	modes, err := borderImageRepeat([]string{"round"})
+	if err != nil || modes != [2]ui.BorderImageRepeatMode{ui.BorderImageRound, ui.BorderImageRound} {
+		t.Fatalf("unexpected repeat %v, %v", modes, err)
+	}
+	modes, err = borderImageRepeat([]string{"stretch", "space"})
+	if err != nil || modes != [2]ui.BorderImageRepeatMode{ui.BorderImageStretch, ui.BorderImageSpace} {
+		t.Fatalf("unexpected repeat %v, %v", modes, err)
+	}
+	if _, err := borderImageRepeat([]string{"tile"}); err == nil {
+		t.Fatal("expected an unknown repeat to be invalid")
+	}
+}
@@ -7,16 +7,24 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// length|%|number|auto{1,4}|initial
 func (p BorderImageWidth) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BorderImageWidth not implemented")}
-
-	return problems[0]
+	img := panel.BorderImage()
+	if len(values) == 1 && values[0].Str == "initial" {
+		img.Width = ui.DefaultBorderImage().Width
+	} else {
+		width, err := borderImageFourSides(valueStrings(values), host.Window, true, true)
+		if err != nil {
+			return err
+		}
+		img.Width = width
+	}
+	panel.SetBorderImage(img)
+	return nil
 }
@@ -15,8 +15,10 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// border-width border-style border-color|initial|inherit
 func (p BorderInline) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BorderInline not implemented")}
-
-	return problems[0]
+	start, end := inlineSides(panel)
+	return errors.Join(
+		borderSideProperties[start].Process(panel, elm, values, host),
+		borderSideProperties[end].Process(panel, elm, values, host))
 }
@@ -7,16 +7,14 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// none|hidden|dotted|dashed|solid|double|groove|ridge|inset|outset|initial|inherit
 func (p BorderInlineEndStyle) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BorderInlineEndStyle not implemented")}
-
-	return problems[0]
+	_, end := inlineSides(panel)
+	return borderSideStyleProperties[end].Process(panel, elm, values, host)
 }
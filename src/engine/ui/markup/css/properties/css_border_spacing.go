@@ -7,16 +7,27 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// <length> <length>?|initial|inherit
 func (p BorderSpacing) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BorderSpacing not implemented")}
-
-	return problems[0]
+	if len(values) == 0 || len(values) > 2 {
+		return fmt.Errorf("%s expects 1 or 2 values but got %d", p.Key(), len(values))
+	}
+	switch values[0].Str {
+	case "initial", "inherit":
+		panel.SetTableBorderSpacing(2, 2)
+		return nil
+	}
+	h := helpers.NumFromLength(values[0].Str, host.Window)
+	v := helpers.NumFromLength(values[len(values)-1].Str, host.Window)
+	panel.SetTableBorderSpacing(h, v)
+	return nil
 }
@@ -8,15 +8,117 @@ package properties
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
 )
 
+// none|[inset? && <length>{2,4} && <color>?]#|initial|inherit
 func (p BoxShadow) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("BoxShadow not implemented")}
+	shadows, err := parseBoxShadows(values, host.Window)
+	if err != nil {
+		return err
+	}
+	panel.SetBoxShadows(shadows)
+	return nil
+}
+
+// splitCommaValues breaks the values up on the top-level commas, such as
+// the ones that separate each shadow
+func splitCommaValues(values []rules.PropertyValue) [][]rules.PropertyValue {
+	layers := [][]rules.PropertyValue{}
+	for i := range values {
+		if i == 0 || values[i].Separated {
+			layers = append(layers, []rules.PropertyValue{})
+		}
+		layers[len(layers)-1] = append(layers[len(layers)-1], values[i])
+	}
+	return layers
+}
+
+func isShadowNone(values []rules.PropertyValue) bool {
+	return len(values) == 1 && (values[0].Str == "none" || values[0].Str == "initial")
+}
+
+func shadowLength(str string, window helpers.WindowDimensions) (float32, bool) {
+	if str == "0" {
+		return 0, true
+	}
+	if len(str) == 0 || !strings.ContainsAny(str[:1], "0123456789-+.") {
+		return 0, false
+	}
+	if strings.HasSuffix(str, "%") {
+		return 0, false
+	}
+	return helpers.NumFromLength(str, window), true
+}
+
+// parseShadowLayer reads the lengths, color and inset keyword of one shadow
+// in any order, the lengths must be next to each other
+func parseShadowLayer(values []rules.PropertyValue, window helpers.WindowDimensions, maxLengths int, allowInset bool) ([]float32, matrix.Color, bool, error) {
+	lengths := make([]float32, 0, maxLengths)
+	color := matrix.ColorBlack()
+	hasColor, inset, lengthsDone := false, false, false
+	for i := range values {
+		str := values[i].Str
+		if l, ok := shadowLength(str, window); ok {
+			if lengthsDone || len(lengths) == maxLengths {
+				return nil, color, false, fmt.Errorf("unexpected shadow length %s", str)
+			}
+			lengths = append(lengths, l)
+			continue
+		}
+		lengthsDone = len(lengths) > 0
+		if str == "inset" && allowInset && !inset {
+			inset = true
+		} else if c, ok := outlineColorFromStr(str); ok && !hasColor {
+			color = c
+			hasColor = true
+		} else {
+			return nil, color, false, fmt.Errorf("invalid shadow value %s", str)
+		}
+	}
+	if len(lengths) < 2 {
+		return nil, color, false, errors.New("a shadow requires at least an x and y offset")
+	}
+	return lengths, color, inset, nil
+}
 
-	return problems[0]
+func parseBoxShadows(values []rules.PropertyValue, window helpers.WindowDimensions) ([]ui.BoxShadow, error) {
+	if len(values) == 0 {
+		return nil, errors.New("BoxShadow requires a value")
+	}
+	if isShadowNone(values) {
+		return nil, nil
+	}
+	layers := splitCommaValues(values)
+	shadows := make([]ui.BoxShadow, 0, len(layers))
+	for _, layer := range layers {
+		lengths, color, inset, err := parseShadowLayer(layer, window, 4, true)
+		if err != nil {
+			return nil, err
+		}
+		s := ui.BoxShadow{
+			Offset: matrix.Vec2{lengths[0], lengths[1]},
+			Color:  color,
+			Inset:  inset,
+		}
+		if len(lengths) > 2 {
+			if lengths[2] < 0 {
+				return nil, errors.New("box-shadow blur radius can not be negative")
+			}
+			s.Blur = lengths[2]
+		}
+		if len(lengths) > 3 {
+			s.Spread = lengths[3]
+		}
+		shadows = append(shadows, s)
+	}
+	return shadows, nil
 }
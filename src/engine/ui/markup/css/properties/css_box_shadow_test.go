@@ -0,0 +1,104 @@
+/******************************************************************************/
+/* css_box_shadow_test.go                                                     */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package properties
+
+import (
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/matrix"
+)
+
+type testWindow struct{}
+
+func (testWindow) DotsPerMillimeter() float64 { return 1 }
+func (testWindow) Width() int                 { return 1000 }
+func (testWindow) Height() int                { return 500 }
+
+func shadowValues(strs ...string) []rules.PropertyValue {
+	values := []rules.PropertyValue{}
+	separated := false
+	for _, s := range strs {
+		if s == "," {
+			separated = true
+			continue
+		}
+		values = append(values, rules.PropertyValue{Str: s, Separated: separated})
+		separated = false
+	}
+	return values
+}
+
+func TestParseBoxShadows(t *testing.T) {
+	shadows, err := parseBoxShadows(shadowValues(
+		"2px", "4px", "6px", "-1px", "#ff000080", ",", "inset", "0", "1px", "blue"), testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shadows) != 2 {
+		t.Fatalf("expected 2 shadows, got %d", len(shadows))
+	}
+	s := shadows[0]
+	if !s.Offset.Equals(matrix.Vec2{2, 4}) || s.Blur != 6 || s.Spread != -1 || s.Inset {
+		t.Fatalf("unexpected first shadow %+v", s)
+	}
+	if !matrix.ApproxTo(s.Color.A(), 0.5, 0.01) {
+		t.Fatalf("expected a half transparent color, got %v", s.Color)
+	}
+	s = shadows[1]
+	if !s.Inset || !s.Offset.Equals(matrix.Vec2{0, 1}) || s.Blur != 0 {
+		t.Fatalf("unexpected second shadow %+v", s)
+	}
+	if !s.Color.Equals(matrix.ColorBlue()) {
+		t.Fatalf("expected a blue shadow, got %v", s.Color)
+	}
+}
+
+func TestParseBoxShadowsDefaultsAndNone(t *testing.T) {
+	shadows, err := parseBoxShadows(shadowValues("red", "3px", "3px"), testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shadows) != 1 || !shadows[0].Color.Equals(matrix.ColorRed()) {
+		t.Fatalf("expected the color to be allowed first, got %+v", shadows)
+	}
+	if shadows, err = parseBoxShadows(shadowValues("none"), testWindow{}); err != nil || shadows != nil {
+		t.Fatalf("expected none to clear the shadows, got %+v, %v", shadows, err)
+	}
+}
+
+func TestParseBoxShadowsInvalid(t *testing.T) {
+	invalid := [][]string{
+		{"2px"},
+		{"1px", "2px", "3px", "4px", "5px"},
+		{"1px", "red", "2px"},
+		{"1px", "2px", "-3px"},
+		{"1px", "2px", "banana"},
+		{"inset", "inset", "1px", "2px"},
+	}
+	for _, v := range invalid {
+		if _, err := parseBoxShadows(shadowValues(v...), testWindow{}); err == nil {
+			t.Errorf("expected %v to be invalid", v)
+		}
+	}
+}
+
+func TestParseTextShadows(t *testing.T) {
+	shadows, err := parseTextShadows(shadowValues("1px", "2px", "3px", "black", ",", "0", "0", "#fff"), testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shadows) != 2 || shadows[0].Blur != 3 || !shadows[1].Color.Equals(matrix.ColorWhite()) {
+		t.Fatalf("unexpected text shadows %+v", shadows)
+	}
+	if _, err = parseTextShadows(shadowValues("inset", "1px", "1px"), testWindow{}); err == nil {
+		t.Fatal("expected inset to be invalid for text-shadow")
+	}
+	if _, err = parseTextShadows(shadowValues("1px", "1px", "1px", "1px"), testWindow{}); err == nil {
+		t.Fatal("expected text-shadow to reject a spread")
+	}
+}
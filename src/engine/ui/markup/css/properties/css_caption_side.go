@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,18 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// top|bottom|block-start|block-end|initial|inherit
 func (p CaptionSide) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("CaptionSide not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return fmt.Errorf("%s expects exactly one value", p.Key())
+	}
+	switch values[0].Str {
+	case "bottom", "block-end":
+		panel.SetTableCaptionBottom(true)
+	case "top", "block-start", "initial", "inherit":
+		panel.SetTableCaptionBottom(false)
+	default:
+		return fmt.Errorf("%s expected top or bottom, but got: %s", p.Key(), values[0].Str)
+	}
+	return nil
 }
@@ -8,6 +8,7 @@ package properties
 
 import (
 	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +16,42 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// auto|rect(top, right, bottom, left)|initial
+//
+// The rect edges are all measured from the top left of the border box, so
+// the rect is drawn as a polygon of its four corners
 func (p Clip) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Clip not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	switch values[0].Str {
+	case "auto", "initial":
+		panel.SetClipPath(ui.ClipShape{})
+		return nil
+	case "rect":
+	default:
+		return fmt.Errorf("unsupported clip value %s", values[0].Str)
+	}
+	if len(values[0].Args) != 4 {
+		return errors.New("rect expects 4 values")
+	}
+	var edges [4]ui.ClipLength
+	for i, arg := range values[0].Args {
+		if arg == "auto" {
+			// The top and left default to the start of the box, the right
+			// and bottom to the end of it
+			edges[i] = ui.ClipLength{Percent: true}
+			if i == 1 || i == 2 {
+				edges[i].Value = 1
+			}
+		} else {
+			edges[i] = transformLength(arg, host.Window)
+		}
+	}
+	top, right, bottom, left := edges[0], edges[1], edges[2], edges[3]
+	panel.SetClipPath(ui.ClipShape{
+		Kind:   ui.ClipShapePolygon,
+		Points: [][2]ui.ClipLength{{left, top}, {right, top}, {right, bottom}, {left, bottom}},
+	})
+	return nil
 }
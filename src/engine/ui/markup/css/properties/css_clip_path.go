@@ -0,0 +1,169 @@
+/******************************************************************************/
+/* css_clip_path.go                                                           */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package properties
+
+import (
+	"errors"
+	"fmt"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/functions"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+var clipPathBoxes = map[string]ui.ClipBox{
+	"border-box":  ui.ClipBoxBorder,
+	"padding-box": ui.ClipBoxPadding,
+	"content-box": ui.ClipBoxContent,
+	"margin-box":  ui.ClipBoxMargin,
+	// There are no SVG boxes, these are the same as the border box
+	"fill-box":   ui.ClipBoxBorder,
+	"stroke-box": ui.ClipBoxBorder,
+	"view-box":   ui.ClipBoxBorder,
+}
+
+// none|basic-shape || geometry-box|initial
+func (p ClipPath) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	shape, err := parseClipPath(values, host.Window)
+	if err != nil {
+		return err
+	}
+	panel.SetClipPath(shape)
+	return nil
+}
+
+func parseClipPath(values []rules.PropertyValue, window helpers.WindowDimensions) (ui.ClipShape, error) {
+	if len(values) == 0 || len(values) > 2 {
+		return ui.ClipShape{}, fmt.Errorf("expected 1 or 2 values but got %d", len(values))
+	}
+	if len(values) == 1 && (values[0].Str == "none" || values[0].Str == "initial") {
+		return ui.ClipShape{}, nil
+	}
+	var shape ui.ClipShape
+	hasBox := false
+	for _, v := range values {
+		if box, ok := clipPathBoxes[v.Str]; ok && !hasBox {
+			shape.Box, hasBox = box, true
+			continue
+		}
+		if shape.Kind != ui.ClipShapeNone {
+			return shape, fmt.Errorf("unexpected clip path value %s", v.Str)
+		}
+		box := shape.Box
+		s, err := parseClipShape(v, window)
+		if err != nil {
+			return shape, err
+		}
+		shape, shape.Box = s, box
+	}
+	if shape.Kind == ui.ClipShapeNone {
+		// A box alone clips to the edges of that box
+		shape.Kind = ui.ClipShapeInset
+	}
+	return shape, nil
+}
+
+func clipShapeRadius(str string, window helpers.WindowDimensions) ui.ClipLength {
+	switch str {
+	case "closest-side":
+		return ui.ClipLength{Value: ui.ClipRadiusClosestSide}
+	case "farthest-side":
+		return ui.ClipLength{Value: ui.ClipRadiusFarthestSide}
+	}
+	return transformLength(str, window)
+}
+
+// clipShapeRadiiAndCenter reads "[radius [radius]] [at position]" of a
+// circle or an ellipse
+func clipShapeRadiiAndCenter(shape *ui.ClipShape, args []string, radii int, window helpers.WindowDimensions) error {
+	closest := ui.ClipLength{Value: ui.ClipRadiusClosestSide}
+	shape.Radii = [2]ui.ClipLength{closest, closest}
+	center := ui.ClipLength{Value: 0.5, Percent: true}
+	shape.Center = [2]ui.ClipLength{center, center}
+	i := 0
+	for ; i < len(args) && args[i] != "at"; i++ {
+		if i >= radii {
+			return fmt.Errorf("too many radii for %s", map[int]string{1: "circle", 2: "ellipse"}[radii])
+		}
+		shape.Radii[i] = clipShapeRadius(args[i], window)
+	}
+	if radii == 2 && i == 1 {
+		return errors.New("an ellipse needs both radii")
+	}
+	if i < len(args) {
+		pos, err := functions.ParsePosition(args[i+1:], window)
+		if err != nil {
+			return err
+		}
+		shape.Center = pos
+	}
+	return nil
+}
+
+func parseClipShape(fn rules.PropertyValue, window helpers.WindowDimensions) (ui.ClipShape, error) {
+	shape := ui.ClipShape{}
+	args := fn.Args
+	switch fn.Str {
+	case "circle":
+		shape.Kind = ui.ClipShapeCircle
+		return shape, clipShapeRadiiAndCenter(&shape, args, 1, window)
+	case "ellipse":
+		shape.Kind = ui.ClipShapeEllipse
+		return shape, clipShapeRadiiAndCenter(&shape, args, 2, window)
+	case "inset":
+		shape.Kind = ui.ClipShapeInset
+		insets := args
+		for i := range args {
+			if args[i] == "round" {
+				if i+1 >= len(args) {
+					return shape, errors.New("inset is missing the round radius")
+				}
+				// Only a single radius for all of the corners is supported
+				shape.Round = transformLength(args[i+1], window)
+				insets = args[:i]
+				break
+			}
+		}
+		if len(insets) == 0 || len(insets) > 4 {
+			return shape, fmt.Errorf("inset expects 1 to 4 values but got %d", len(insets))
+		}
+		for i := range insets {
+			shape.Insets[i] = transformLength(insets[i], window)
+		}
+		switch len(insets) {
+		case 1:
+			shape.Insets[1], shape.Insets[2], shape.Insets[3] = shape.Insets[0], shape.Insets[0], shape.Insets[0]
+		case 2:
+			shape.Insets[2], shape.Insets[3] = shape.Insets[0], shape.Insets[1]
+		case 3:
+			shape.Insets[3] = shape.Insets[1]
+		}
+		return shape, nil
+	case "polygon":
+		shape.Kind = ui.ClipShapePolygon
+		if len(args) > 0 && (args[0] == "nonzero" || args[0] == "evenodd") {
+			args = args[1:]
+		}
+		if len(args) < 6 || len(args)%2 != 0 {
+			return shape, errors.New("polygon expects at least 3 points")
+		}
+		if len(args)/2 > ui.MaxClipPolygonPoints {
+			return shape, fmt.Errorf("polygon can have at most %d points but got %d",
+				ui.MaxClipPolygonPoints, len(args)/2)
+		}
+		for i := 0; i < len(args); i += 2 {
+			shape.Points = append(shape.Points, [2]ui.ClipLength{
+				transformLength(args[i], window), transformLength(args[i+1], window),
+			})
+		}
+		return shape, nil
+	}
+	return shape, fmt.Errorf("unsupported clip path shape %s", fn.Str)
+}
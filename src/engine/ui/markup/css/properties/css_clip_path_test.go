@@ -0,0 +1,94 @@
+/******************************************************************************/
+/* css_clip_path_test.go                                                      */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package properties
+
+import (
+	"testing"
+
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+func TestParseClipPathCircle(t *testing.T) {
+	shape, err := parseClipPath([]rules.PropertyValue{
+		{Str: "circle", Args: []string{"40%", "at", "top", "20px"}},
+		{Str: "padding-box"},
+	}, testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shape.Kind != ui.ClipShapeCircle || shape.Box != ui.ClipBoxPadding {
+		t.Fatalf("unexpected shape %+v", shape)
+	}
+	want := [2]ui.ClipLength{{Value: 20}, {Value: 0, Percent: true}}
+	if shape.Radii[0] != (ui.ClipLength{Value: 0.4, Percent: true}) || shape.Center != want {
+		t.Fatalf("unexpected circle %+v", shape)
+	}
+}
+
+func TestParseClipPathEllipseDefaults(t *testing.T) {
+	shape, err := parseClipPath([]rules.PropertyValue{{Str: "ellipse"}}, testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	closest := ui.ClipLength{Value: ui.ClipRadiusClosestSide}
+	center := ui.ClipLength{Value: 0.5, Percent: true}
+	if shape.Radii != [2]ui.ClipLength{closest, closest} || shape.Center != [2]ui.ClipLength{center, center} {
+		t.Fatalf("unexpected ellipse %+v", shape)
+	}
+	if _, err := parseClipPath([]rules.PropertyValue{
+		{Str: "ellipse", Args: []string{"10px", "at", "center"}},
+	}, testWindow{}); err == nil {
+		t.Fatal("expected an ellipse with one radius to be invalid")
+	}
+}
+
+func TestParseClipPathInset(t *testing.T) {
+	shape, err := parseClipPath([]rules.PropertyValue{
+		{Str: "inset", Args: []string{"10px", "20%", "round", "5px"}},
+	}, testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ten, twenty := ui.ClipLength{Value: 10}, ui.ClipLength{Value: 0.2, Percent: true}
+	if shape.Insets != [4]ui.ClipLength{ten, twenty, ten, twenty} || shape.Round.Value != 5 {
+		t.Fatalf("unexpected inset %+v", shape)
+	}
+}
+
+func TestParseClipPathPolygon(t *testing.T) {
+	shape, err := parseClipPath([]rules.PropertyValue{
+		{Str: "polygon", Args: []string{"evenodd", "50%", "0", "100%", "50%", "50%", "100%", "0", "50%"}},
+	}, testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shape.Kind != ui.ClipShapePolygon || len(shape.Points) != 4 || shape.Points[1][0].Value != 1 {
+		t.Fatalf("unexpected polygon %+v", shape)
+	}
+	args := []string{}
+	for range ui.MaxClipPolygonPoints + 1 {
+		args = append(args, "0", "0")
+	}
+	if _, err := parseClipPath([]rules.PropertyValue{{Str: "polygon", Args: args}}, testWindow{}); err == nil {
+		t.Fatal("expected a polygon with too many points to be invalid")
+	}
+}
+
+func TestParseClipPathBoxAndNone(t *testing.T) {
+	shape, err := parseClipPath([]rules.PropertyValue{{Str: "content-box"}}, testWindow{})
+	if err != nil || shape.Kind != ui.ClipShapeInset || shape.Box != ui.ClipBoxContent {
+		t.Fatalf("expected the box alone to clip to it, got %+v, %v", shape, err)
+	}
+	shape, err = parseClipPath([]rules.PropertyValue{{Str: "none"}}, testWindow{})
+	if err != nil || shape.Kind != ui.ClipShapeNone {
+		t.Fatalf("expected none to remove the clip, got %+v, %v", shape, err)
+	}
+	if _, err := parseClipPath([]rules.PropertyValue{{Str: "path", Args: []string{"M 0 0"}}}, testWindow{}); err == nil {
+		t.Fatal("expected path to be unsupported")
+	}
+}
@@ -47,6 +47,14 @@ func (p Color) Process(panel *ui.Panel, elm *document.Element, values []rules.Pr
 		return err
 	}
 
+	if elm.PseudoElement() == document.PseudoPlaceholder {
+		if panel.Base().IsType(ui.ElementTypeInput) {
+			panel.Base().ToInput().SetPlaceholderColor(color)
+		} else if panel.Base().IsType(ui.ElementTypeTextArea) {
+			panel.Base().ToTextArea().SetPlaceholderColor(color)
+		}
+		return nil
+	}
 	if panel.Base().IsType(ui.ElementTypeInput) {
 		panel.Base().ToInput().SetFGColor(color)
 		return nil
@@ -0,0 +1,34 @@
+/******************************************************************************/
+/* css_container.go                                                           */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package properties
+
+import (
+	"fmt"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+// container-name [/ container-type]?
+//
+// The stylizer reads this property when matching @container rules
+func (p Container) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	for i := range values {
+		if values[i].Str != "/" {
+			continue
+		}
+		if i != len(values)-2 {
+			return fmt.Errorf("expected a single container type after / in %s", p.Key())
+		}
+		if err := validateContainerNames(p.Key(), values[:i]); err != nil {
+			return err
+		}
+		return ContainerType{}.Process(panel, elm, values[i+1:], host)
+	}
+	return validateContainerNames(p.Key(), values)
+}
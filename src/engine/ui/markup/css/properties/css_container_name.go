@@ -0,0 +1,39 @@
+/******************************************************************************/
+/* css_container_name.go                                                      */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package properties
+
+import (
+	"fmt"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+// none|name+
+//
+// The stylizer reads this property when matching @container rules
+func (p ContainerName) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	return validateContainerNames(p.Key(), values)
+}
+
+func validateContainerNames(key string, values []rules.PropertyValue) error {
+	if len(values) == 0 {
+		return fmt.Errorf("expected at least 1 value for %s", key)
+	}
+	for i := range values {
+		switch values[i].Str {
+		case "", "and", "or", "not":
+			return fmt.Errorf("invalid container name for %s: %q", key, values[i].Str)
+		case "none":
+			if len(values) > 1 {
+				return fmt.Errorf("none can not be combined with other names in %s", key)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+/******************************************************************************/
+/* css_container_type.go                                                      */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package properties
+
+import (
+	"fmt"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+// normal|size|inline-size
+//
+// The stylizer reads this property when matching @container rules, there is
+// nothing to apply to the panel itself
+func (p ContainerType) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value for %s but got %d", p.Key(), len(values))
+	}
+	switch values[0].Str {
+	case "normal", "size", "inline-size", "initial", "inherit":
+		return nil
+	default:
+		return fmt.Errorf("invalid value for %s: %s", p.Key(), values[0].Str)
+	}
+}
@@ -8,15 +8,66 @@ package properties
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/functions"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// normal|none|string|counter()|counters()|attr()|open-quote|close-quote|no-open-quote|no-close-quote
 func (p Content) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Content not implemented")}
+	if len(values) == 0 {
+		return errors.New("no values for content")
+	}
+	switch elm.PseudoElement() {
+	case document.PseudoBefore, document.PseudoAfter, document.PseudoMarker:
+	default:
+		return errors.New("content is only supported on ::before, ::after and ::marker")
+	}
+	text, err := generatedContent(panel, elm, values)
+	if err != nil {
+		return err
+	}
+	if lbl := elm.InnerLabel(); lbl != nil {
+		lbl.SetText(text)
+	}
+	return nil
+}
 
-	return problems[0]
+func generatedContent(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue) (string, error) {
+	sb := strings.Builder{}
+	depth := elm.QuoteDepth()
+	for _, v := range values {
+		switch v.Str {
+		case "none", "normal":
+			return "", nil
+		case "open-quote":
+			sb.WriteString(quoteAtDepth(elm, depth, true))
+			depth++
+		case "close-quote":
+			depth = max(0, depth-1)
+			sb.WriteString(quoteAtDepth(elm, depth, false))
+		case "no-open-quote":
+			depth++
+		case "no-close-quote":
+			depth = max(0, depth-1)
+		case "counter", "counters", "attr":
+			str, err := functions.FunctionMap[v.Str].Process(panel, elm, v)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(str)
+		default:
+			if !helpers.IsQuotedString(v.Str) {
+				return "", fmt.Errorf("unsupported content value: %s", v.Str)
+			}
+			sb.WriteString(helpers.UnquoteString(v.Str))
+		}
+	}
+	return sb.String(), nil
 }
@@ -8,6 +8,8 @@ package properties
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +17,30 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// none|[name integer?]+
 func (p CounterIncrement) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("CounterIncrement not implemented")}
+	return validateCounterList(p.Key(), values)
+}
 
-	return problems[0]
+func validateCounterList(key string, values []rules.PropertyValue) error {
+	if len(values) == 0 {
+		return fmt.Errorf("no values for %s", key)
+	}
+	if len(values) == 1 && values[0].Str == "none" {
+		return nil
+	}
+	afterName := false
+	for i := range values {
+		if _, err := strconv.Atoi(values[i].Str); err == nil {
+			if !afterName {
+				return errors.New(key + " expects a counter name before " + values[i].Str)
+			}
+			afterName = false
+		} else if values[i].Str == "none" {
+			return errors.New(key + " can not mix none with counter names")
+		} else {
+			afterName = true
+		}
+	}
+	return nil
 }
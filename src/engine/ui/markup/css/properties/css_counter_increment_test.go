@@ -0,0 +1,46 @@
+/******************************************************************************/
+/* css_counter_increment_test.go                                              */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package properties
+
+import (
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+func testCounterValues(values ...string) []rules.PropertyValue {
+	out := make([]rules.PropertyValue, len(values))
+	for i := range values {
+		out[i] = rules.PropertyValue{Str: values[i]}
+	}
+	return out
+}
+
+func TestValidateCounterList(t *testing.T) {
+	valid := [][]string{
+		{"none"},
+		{"item"},
+		{"item", "2"},
+		{"chapter", "section", "-1"},
+	}
+	for _, v := range valid {
+		if err := validateCounterList("counter-increment", testCounterValues(v...)); err != nil {
+			t.Errorf("%v should be valid: %v", v, err)
+		}
+	}
+	invalid := [][]string{
+		{},
+		{"2"},
+		{"item", "2", "3"},
+		{"item", "none"},
+	}
+	for _, v := range invalid {
+		if err := validateCounterList("counter-increment", testCounterValues(v...)); err == nil {
+			t.Errorf("%v should be invalid", v)
+		}
+	}
+}
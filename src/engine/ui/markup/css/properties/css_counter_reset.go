@@ -7,16 +7,16 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// none|[name integer?]+
+//
+// The counters are resolved for the whole document when the styles are
+// applied (see document.Document.UpdateCounters), so this only validates
 func (p CounterReset) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("CounterReset not implemented")}
-
-	return problems[0]
+	return validateCounterList(p.Key(), values)
 }
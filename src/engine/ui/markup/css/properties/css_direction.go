@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,26 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
-func (p Direction) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Direction not implemented")}
+var directionKeywords = map[string]ui.Direction{
+	"ltr":     ui.DirectionLTR,
+	"rtl":     ui.DirectionRTL,
+	"initial": ui.DirectionLTR,
+	"unset":   ui.DirectionInherit,
+	"inherit": ui.DirectionInherit,
+}
 
-	return problems[0]
+// The logical properties read the direction, so it is set before them
+func (p Direction) Sort() int { return -1 }
+
+// ltr|rtl|initial|inherit
+func (p Direction) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	dir, ok := directionKeywords[values[0].Str]
+	if !ok {
+		return fmt.Errorf("unsupported value '%s'", values[0].Str)
+	}
+	panel.SetDirection(dir)
+	return nil
 }
@@ -15,23 +15,44 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
-// block|inline|inline-block|flex|inline-flex|grid|inline-grid|flow-root|none|contents|block flex|block flow|block flow-root|block grid|inline flex|inline flow|inline flow-root|inline grid|table|table-row|list-item|inherit|initial|revert|revert-layer|unset
+var displayTableRoles = map[string]ui.TableRole{
+	"table":              ui.TableRoleTable,
+	"inline-table":       ui.TableRoleTable,
+	"table-caption":      ui.TableRoleCaption,
+	"table-header-group": ui.TableRoleHeaderGroup,
+	"table-row-group":    ui.TableRoleRowGroup,
+	"table-footer-group": ui.TableRoleFooterGroup,
+	"table-row":          ui.TableRoleRow,
+	"table-cell":         ui.TableRoleCell,
+	"table-column-group": ui.TableRoleColumnGroup,
+	"table-column":       ui.TableRoleColumn,
+}
+
+// block|inline|inline-block|flex|inline-flex|grid|inline-grid|flow-root|none|contents|block flex|block flow|block flow-root|block grid|inline flex|inline flow|inline flow-root|inline grid|table|inline-table|table-caption|table-header-group|table-row-group|table-footer-group|table-row|table-cell|table-column-group|table-column|list-item|inherit|initial|revert|revert-layer|unset
 func (p Display) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
 	if len(values) == 0 {
 		return errors.New("no values for display")
 	}
+	if role, ok := displayTableRoles[values[0].Str]; ok {
+		panel.SetFlowLayout()
+		panel.SetTableRole(role)
+		return nil
+	}
 	switch values[0].Str {
 	case "none":
 		panel.Base().Hide()
 		return nil
 	case "flex", "inline-flex", "block flex", "inline flex":
 		panel.SetFlex()
+		panel.SetTableRole(ui.TableRoleNone)
 		return nil
 	case "grid", "inline-grid", "block grid", "inline grid":
 		panel.SetGrid(0)
+		panel.SetTableRole(ui.TableRoleNone)
 		return nil
 	case "block", "inline", "inline-block", "flow-root", "block flow", "inline flow":
 		panel.SetFlowLayout()
+		panel.SetTableRole(ui.TableRoleNone)
 		return nil
 	default:
 		return nil
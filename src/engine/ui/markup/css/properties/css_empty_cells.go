@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,18 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// show|hide|initial|inherit
 func (p EmptyCells) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("EmptyCells not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return fmt.Errorf("%s expects exactly one value", p.Key())
+	}
+	switch values[0].Str {
+	case "hide":
+		panel.SetTableEmptyCellsHidden(true)
+	case "show", "initial", "inherit":
+		panel.SetTableEmptyCellsHidden(false)
+	default:
+		return fmt.Errorf("%s expected show or hide, but got: %s", p.Key(), values[0].Str)
+	}
+	return nil
 }
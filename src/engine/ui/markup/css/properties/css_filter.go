@@ -8,15 +8,92 @@ package properties
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+var filterFunctions = map[string]ui.FilterFunction{
+	"brightness": ui.FilterBrightness,
+	"contrast":   ui.FilterContrast,
+	"grayscale":  ui.FilterGrayscale,
+	"hue-rotate": ui.FilterHueRotate,
+	"invert":     ui.FilterInvert,
+	"opacity":    ui.FilterOpacity,
+	"saturate":   ui.FilterSaturate,
+	"sepia":      ui.FilterSepia,
+}
+
+// none|<filter-function>+|initial|inherit
 func (p Filter) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Filter not implemented")}
+	filters, err := parseFilters(values)
+	if err != nil {
+		return err
+	}
+	panel.SetFilters(filters)
+	return nil
+}
+
+func parseFilters(values []rules.PropertyValue) ([]ui.Filter, error) {
+	if len(values) == 0 {
+		return nil, errors.New("Filter requires a value")
+	}
+	if len(values) == 1 && (values[0].Str == "none" || values[0].Str == "initial") {
+		return nil, nil
+	}
+	filters := make([]ui.Filter, 0, len(values))
+	for i := range values {
+		if values[i].Str == "blur" {
+			// Panels are drawn straight to the UI passes, there is no
+			// intermediate target that could be blurred
+			return nil, errors.New("Filter blur not implemented")
+		}
+		fn, ok := filterFunctions[values[i].Str]
+		if !ok {
+			return nil, fmt.Errorf("unsupported filter function %s", values[i].Str)
+		}
+		if len(values[i].Args) > 1 {
+			return nil, fmt.Errorf("%s expects a single argument", values[i].Str)
+		}
+		arg := ""
+		if len(values[i].Args) == 1 {
+			arg = strings.TrimSpace(values[i].Args[0])
+		}
+		amount, err := filterAmount(fn, arg)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, ui.Filter{Function: fn, Amount: amount})
+	}
+	return filters, nil
+}
 
-	return problems[0]
+// filterAmount reads the argument of a filter function, an empty argument
+// gives the default value from the spec
+func filterAmount(fn ui.FilterFunction, arg string) (float32, error) {
+	if fn == ui.FilterHueRotate {
+		if arg == "" {
+			return 0, nil
+		}
+		return helpers.AngleFromStr(arg)
+	}
+	if arg == "" {
+		return 1, nil
+	}
+	scale := 1.0
+	if strings.HasSuffix(arg, "%") {
+		arg = strings.TrimSuffix(arg, "%")
+		scale = 0.01
+	}
+	v, err := strconv.ParseFloat(arg, 32)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid filter amount %s", arg)
+	}
+	return float32(v * scale), nil
 }
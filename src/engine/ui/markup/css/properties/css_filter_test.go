@@ -0,0 +1,76 @@
+/******************************************************************************/
+/* css_filter_test.go                                                         */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package properties
+
+import (
+	"testing"
+
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/matrix"
+)
+
+func TestParseFilters(t *testing.T) {
+	values := []rules.PropertyValue{
+		{Str: "brightness", Args: []string{"150%"}},
+		{Str: "grayscale", Args: []string{"0.5"}},
+		{Str: "hue-rotate", Args: []string{"0.5turn"}},
+		{Str: "invert"},
+	}
+	filters, err := parseFilters(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ui.Filter{
+		{Function: ui.FilterBrightness, Amount: 1.5},
+		{Function: ui.FilterGrayscale, Amount: 0.5},
+		{Function: ui.FilterHueRotate, Amount: 180},
+		{Function: ui.FilterInvert, Amount: 1},
+	}
+	if len(filters) != len(want) {
+		t.Fatalf("expected %d filters, got %d", len(want), len(filters))
+	}
+	for i := range want {
+		if filters[i].Function != want[i].Function || !matrix.ApproxTo(filters[i].Amount, want[i].Amount, 0.001) {
+			t.Errorf("filter %d: expected %+v, got %+v", i, want[i], filters[i])
+		}
+	}
+}
+
+func TestParseFiltersInvalid(t *testing.T) {
+	invalid := [][]rules.PropertyValue{
+		{{Str: "drop-shadow", Args: []string{"1px"}}},
+		{{Str: "blur", Args: []string{"4px"}}},
+		{{Str: "brightness", Args: []string{"-50%"}}},
+		{{Str: "hue-rotate", Args: []string{"90"}}},
+		{{Str: "opacity", Args: []string{"1", "2"}}},
+	}
+	for _, v := range invalid {
+		if _, err := parseFilters(v); err == nil {
+			t.Errorf("expected %+v to be invalid", v)
+		}
+	}
+	if filters, err := parseFilters([]rules.PropertyValue{{Str: "none"}}); err != nil || filters != nil {
+		t.Fatalf("expected none to clear the filters, got %+v, %v", filters, err)
+	}
+}
+
+func TestBackdropFilterAndBlendModeOnlyAcceptWhatIsDrawn(t *testing.T) {
+	if err := (BackdropFilter{}).Process(nil, nil, []rules.PropertyValue{{Str: "none"}}, nil); err != nil {
+		t.Fatalf("expected none to be accepted, got %v", err)
+	}
+	blur := []rules.PropertyValue{{Str: "blur", Args: []string{"4px"}}}
+	if err := (BackdropFilter{}).Process(nil, nil, blur, nil); err == nil {
+		t.Fatal("expected a backdrop blur to be rejected")
+	}
+	if err := (MixBlendMode{}).Process(nil, nil, []rules.PropertyValue{{Str: "normal"}}, nil); err != nil {
+		t.Fatalf("expected normal blending to be accepted, got %v", err)
+	}
+	if err := (MixBlendMode{}).Process(nil, nil, []rules.PropertyValue{{Str: "multiply"}}, nil); err == nil {
+		t.Fatal("expected multiply blending to be rejected")
+	}
+}
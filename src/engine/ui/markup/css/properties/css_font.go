@@ -75,6 +75,7 @@ var (
 
 type fontShorthand struct {
 	style     string
+	variant   string
 	weight    string
 	size      rules.PropertyValue
 	line      rules.PropertyValue
@@ -166,6 +167,7 @@ func parseFontShorthand(values []rules.PropertyValue) (fontShorthand, error) {
 			continue
 		}
 		if _, ok := fontVariantValues[str]; ok {
+			out.variant = str
 			continue
 		}
 		if _, ok := fontWeightValues[str]; ok {
@@ -202,6 +204,13 @@ func (p Font) Process(panel *ui.Panel, elm *document.Element, values []rules.Pro
 	if err := (FontWeight{}).Process(panel, elm, []rules.PropertyValue{{Str: font.weight}}, host); err != nil {
 		return err
 	}
+	variant := font.variant
+	if variant == "" {
+		variant = "normal"
+	}
+	if err := (FontVariantCaps{}).Process(panel, elm, []rules.PropertyValue{{Str: variant}}, host); err != nil {
+		return err
+	}
 	if err := (FontSize{}).Process(panel, elm, []rules.PropertyValue{font.size}, host); err != nil {
 		return err
 	}
@@ -7,16 +7,60 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p FontFeatureSettings) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("FontFeatureSettings not implemented")}
+// parseFontFeatureSettings converts the comma separated feature list into
+// the "tag=value" form of [rendering.FontFeatures.Settings]
+func parseFontFeatureSettings(values []rules.PropertyValue) (string, error) {
+	if len(values) == 1 && (values[0].Str == "normal" || values[0].Str == "initial" || values[0].Str == "unset") {
+		return "", nil
+	}
+	settings := []string{}
+	for _, feature := range splitCommaValues(values) {
+		if len(feature) == 0 || len(feature) > 2 {
+			return "", fmt.Errorf("expected a feature tag and an optional value")
+		}
+		tag := feature[0].Str
+		if len(tag) != 6 || (tag[0] != '"' && tag[0] != '\'') || tag[5] != tag[0] {
+			return "", fmt.Errorf("expected a quoted 4 letter feature tag but got '%s'", tag)
+		}
+		value := 1
+		if len(feature) == 2 {
+			switch str := feature[1].Str; str {
+			case "on":
+			case "off":
+				value = 0
+			default:
+				v, err := strconv.Atoi(str)
+				if err != nil || v < 0 {
+					return "", fmt.Errorf("invalid feature value '%s'", str)
+				}
+				value = v
+			}
+		}
+		settings = append(settings, fmt.Sprintf("%s=%d", tag[1:5], value))
+	}
+	return strings.Join(settings, ","), nil
+}
 
-	return problems[0]
+// normal|<feature-tag-value>#|initial|inherit
+func (p FontFeatureSettings) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	var settings string
+	var err error
+	if len(values) == 1 && values[0].Str == "inherit" {
+		settings = parentTextLayout(elm).Features.Settings
+	} else if settings, err = parseFontFeatureSettings(values); err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.Features.Settings = settings })
+	return nil
 }
@@ -7,16 +7,28 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p FontKerning) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("FontKerning not implemented")}
+var fontKerningKeywords = map[string]rendering.FontKerning{
+	"auto":    rendering.FontKerningAuto,
+	"initial": rendering.FontKerningAuto,
+	"unset":   rendering.FontKerningAuto,
+	"normal":  rendering.FontKerningNormal,
+	"none":    rendering.FontKerningNone,
+}
 
-	return problems[0]
+// auto|normal|none|initial|inherit
+func (p FontKerning) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	kerning, err := textLayoutKeyword(elm, values, fontKerningKeywords,
+		func(l rendering.TextLayout) rendering.FontKerning { return l.Features.Kerning })
+	if err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.Features.Kerning = kerning })
+	return nil
 }
@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
 func TestParseFontShorthandFull(t *testing.T) {
@@ -71,3 +73,57 @@ func TestParseFontShorthandRequiresSizeAndFamily(t *testing.T) {
 		}
 	}
 }
+
+func TestParseFontFeatureSettings(t *testing.T) {
+	settings, err := parseFontFeatureSettings(shadowValues(`"liga"`, "0", ",", "'smcp'", ",", `"ss01"`, "on", ",", `"salt"`, "3"))
+	if err != nil || settings != "liga=0,smcp=1,ss01=1,salt=3" {
+		t.Errorf("unexpected settings %q %v", settings, err)
+	}
+	if settings, err = parseFontFeatureSettings(shadowValues("normal")); err != nil || settings != "" {
+		t.Errorf("expected normal to clear the settings, got %q %v", settings, err)
+	}
+	for _, bad := range [][]string{{"liga"}, {`"lig"`}, {`"liga"`, "maybe"}, {`"liga"`, "-1"}} {
+		if _, err = parseFontFeatureSettings(shadowValues(bad...)); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestParseFontVariant(t *testing.T) {
+	start := rendering.FontFeatures{Caps: rendering.FontCapsTitling, Kerning: rendering.FontKerningNone}
+	features, err := parseFontVariant(shadowValues("small-caps", "no-common-ligatures", "tabular-nums", "slashed-zero", "super"), start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := rendering.FontFeatures{
+		Kerning:   rendering.FontKerningNone,
+		Ligatures: rendering.FontLigaturesNoCommon,
+		Caps:      rendering.FontCapsSmall,
+		Numeric:   rendering.FontNumericTabular | rendering.FontNumericSlashedZero,
+		Position:  rendering.FontPositionSuper,
+	}
+	if features != want {
+		t.Errorf("expected %+v but got %+v", want, features)
+	}
+	if features, _ = parseFontVariant(shadowValues("none"), want); features.Ligatures != rendering.FontLigaturesNone || features.Caps != rendering.FontCapsNormal {
+		t.Errorf("expected none to disable ligatures and reset the rest, got %+v", features)
+	}
+	if _, err = parseFontVariant(shadowValues("fancy"), start); err == nil {
+		t.Error("expected an error for an unknown keyword")
+	}
+}
+
+func TestFontVariantFlags(t *testing.T) {
+	elm := &document.Element{}
+	get := func(f rendering.FontFeatures) rendering.FontEastAsian { return f.EastAsian }
+	flags, err := fontVariantFlags(elm, shadowValues("jis04", "full-width"), fontVariantEastAsianKeywords, get)
+	if err != nil || flags != rendering.FontEastAsianJIS04|rendering.FontEastAsianFullWidth {
+		t.Errorf("unexpected flags %v %v", flags, err)
+	}
+	if flags, err = fontVariantFlags(elm, shadowValues("normal"), fontVariantEastAsianKeywords, get); err != nil || flags != 0 {
+		t.Errorf("expected normal to be the zero value, got %v %v", flags, err)
+	}
+	if _, err = fontVariantFlags(elm, shadowValues("jis04", "sideways"), fontVariantEastAsianKeywords, get); err == nil {
+		t.Error("expected an error for an unknown keyword")
+	}
+}
@@ -7,16 +7,99 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p FontVariant) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("FontVariant not implemented")}
+// fontVariantFlags combines the flags of each keyword in the values, normal
+// and initial are the zero value and inherit reads the parent text
+func fontVariantFlags[T ~int](elm *document.Element, values []rules.PropertyValue,
+	keywords map[string]T, inherited func(rendering.FontFeatures) T) (T, error) {
+	var out T
+	if len(values) == 0 {
+		return out, fmt.Errorf("expected at least 1 value")
+	}
+	if len(values) == 1 {
+		switch values[0].Str {
+		case "normal", "initial", "unset":
+			return out, nil
+		case "inherit":
+			return inherited(parentTextLayout(elm).Features), nil
+		}
+	}
+	for i := range values {
+		flag, ok := keywords[values[i].Str]
+		if !ok {
+			return out, fmt.Errorf("unsupported value '%s'", values[i].Str)
+		}
+		out |= flag
+	}
+	return out, nil
+}
 
-	return problems[0]
+// parseFontVariant reads the font-variant shorthand into the features,
+// every font-variant-* property is reset before the values are applied
+func parseFontVariant(values []rules.PropertyValue, features rendering.FontFeatures) (rendering.FontFeatures, error) {
+	features.Ligatures = 0
+	features.Caps = rendering.FontCapsNormal
+	features.Numeric = 0
+	features.EastAsian = 0
+	features.Position = rendering.FontPositionNormal
+	features.Alternates = 0
+	if len(values) == 1 {
+		switch values[0].Str {
+		case "normal", "initial", "unset":
+			return features, nil
+		case "none":
+			features.Ligatures = rendering.FontLigaturesNone
+			return features, nil
+		}
+	}
+	for i := range values {
+		str := values[i].Str
+		if v, ok := fontVariantLigaturesKeywords[str]; ok {
+			features.Ligatures |= v
+		} else if v, ok := fontVariantCapsKeywords[str]; ok && str != "normal" {
+			features.Caps = v
+		} else if v, ok := fontVariantNumericKeywords[str]; ok {
+			features.Numeric |= v
+		} else if v, ok := fontVariantEastAsianKeywords[str]; ok {
+			features.EastAsian |= v
+		} else if v, ok := fontVariantPositionKeywords[str]; ok && str != "normal" {
+			features.Position = v
+		} else if v, ok := fontVariantAlternatesKeywords[str]; ok {
+			features.Alternates |= v
+		} else {
+			return features, fmt.Errorf("unsupported value '%s'", str)
+		}
+	}
+	return features, nil
+}
+
+// normal|none|<font-variant-ligatures>||<font-variant-caps>||<font-variant-numeric>||<font-variant-east-asian>||<font-variant-position>||<font-variant-alternates>|initial|inherit
+func (p FontVariant) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) == 1 && values[0].Str == "inherit" {
+		parent := parentTextLayout(elm).Features
+		updateChildTextLayouts(elm, func(l *rendering.TextLayout) {
+			l.Features.Ligatures = parent.Ligatures
+			l.Features.Caps = parent.Caps
+			l.Features.Numeric = parent.Numeric
+			l.Features.EastAsian = parent.EastAsian
+			l.Features.Position = parent.Position
+			l.Features.Alternates = parent.Alternates
+		})
+		return nil
+	}
+	if _, err := parseFontVariant(values, rendering.FontFeatures{}); err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) {
+		l.Features, _ = parseFontVariant(values, l.Features)
+	})
+	return nil
 }
@@ -7,16 +7,34 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p FontVariantAlternates) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("FontVariantAlternates not implemented")}
+var fontVariantAlternatesKeywords = map[string]rendering.FontAlternates{
+	"historical-forms": rendering.FontAlternatesHistoricalForms,
+}
 
-	return problems[0]
+// normal|historical-forms|initial|inherit
+//
+// The functional values (stylistic(), swash(), ornaments() and the like)
+// name values from @font-feature-values, which is not supported
+func (p FontVariantAlternates) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	for i := range values {
+		if values[i].IsFunction() {
+			return fmt.Errorf("unsupported value '%s()', @font-feature-values is not supported", values[i].Str)
+		}
+	}
+	alternates, err := fontVariantFlags(elm, values, fontVariantAlternatesKeywords,
+		func(f rendering.FontFeatures) rendering.FontAlternates { return f.Alternates })
+	if err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.Features.Alternates = alternates })
+	return nil
 }
@@ -7,16 +7,32 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p FontVariantCaps) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("FontVariantCaps not implemented")}
+var fontVariantCapsKeywords = map[string]rendering.FontCaps{
+	"normal":          rendering.FontCapsNormal,
+	"initial":         rendering.FontCapsNormal,
+	"unset":           rendering.FontCapsNormal,
+	"small-caps":      rendering.FontCapsSmall,
+	"all-small-caps":  rendering.FontCapsAllSmall,
+	"petite-caps":     rendering.FontCapsPetite,
+	"all-petite-caps": rendering.FontCapsAllPetite,
+	"unicase":         rendering.FontCapsUnicase,
+	"titling-caps":    rendering.FontCapsTitling,
+}
 
-	return problems[0]
+// normal|small-caps|all-small-caps|petite-caps|all-petite-caps|unicase|titling-caps|initial|inherit
+func (p FontVariantCaps) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	caps, err := textLayoutKeyword(elm, values, fontVariantCapsKeywords,
+		func(l rendering.TextLayout) rendering.FontCaps { return l.Features.Caps })
+	if err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.Features.Caps = caps })
+	return nil
 }
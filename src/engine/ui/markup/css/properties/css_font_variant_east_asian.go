@@ -7,16 +7,32 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p FontVariantEastAsian) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("FontVariantEastAsian not implemented")}
+var fontVariantEastAsianKeywords = map[string]rendering.FontEastAsian{
+	"jis78":              rendering.FontEastAsianJIS78,
+	"jis83":              rendering.FontEastAsianJIS83,
+	"jis90":              rendering.FontEastAsianJIS90,
+	"jis04":              rendering.FontEastAsianJIS04,
+	"simplified":         rendering.FontEastAsianSimplified,
+	"traditional":        rendering.FontEastAsianTraditional,
+	"full-width":         rendering.FontEastAsianFullWidth,
+	"proportional-width": rendering.FontEastAsianProportionalWidth,
+	"ruby":               rendering.FontEastAsianRuby,
+}
 
-	return problems[0]
+// normal|jis78|jis83|jis90|jis04|simplified|traditional|full-width|proportional-width|ruby|initial|inherit
+func (p FontVariantEastAsian) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	eastAsian, err := fontVariantFlags(elm, values, fontVariantEastAsianKeywords,
+		func(f rendering.FontFeatures) rendering.FontEastAsian { return f.EastAsian })
+	if err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.Features.EastAsian = eastAsian })
+	return nil
 }
@@ -7,16 +7,37 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p FontVariantLigatures) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("FontVariantLigatures not implemented")}
+var fontVariantLigaturesKeywords = map[string]rendering.FontLigatures{
+	"common-ligatures":           0,
+	"no-common-ligatures":        rendering.FontLigaturesNoCommon,
+	"discretionary-ligatures":    rendering.FontLigaturesDiscretionary,
+	"no-discretionary-ligatures": 0,
+	"historical-ligatures":       rendering.FontLigaturesHistorical,
+	"no-historical-ligatures":    0,
+	"contextual":                 0,
+	"no-contextual":              rendering.FontLigaturesNoContextual,
+}
 
-	return problems[0]
+// normal|none|common-ligatures|no-common-ligatures|discretionary-ligatures|no-discretionary-ligatures|historical-ligatures|no-historical-ligatures|contextual|no-contextual|initial|inherit
+func (p FontVariantLigatures) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	var ligatures rendering.FontLigatures
+	var err error
+	if len(values) == 1 && values[0].Str == "none" {
+		ligatures = rendering.FontLigaturesNone
+	} else {
+		ligatures, err = fontVariantFlags(elm, values, fontVariantLigaturesKeywords,
+			func(f rendering.FontFeatures) rendering.FontLigatures { return f.Ligatures })
+	}
+	if err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.Features.Ligatures = ligatures })
+	return nil
 }
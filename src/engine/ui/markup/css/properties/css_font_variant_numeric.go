@@ -7,16 +7,31 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p FontVariantNumeric) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("FontVariantNumeric not implemented")}
+var fontVariantNumericKeywords = map[string]rendering.FontNumeric{
+	"lining-nums":        rendering.FontNumericLining,
+	"oldstyle-nums":      rendering.FontNumericOldstyle,
+	"proportional-nums":  rendering.FontNumericProportional,
+	"tabular-nums":       rendering.FontNumericTabular,
+	"diagonal-fractions": rendering.FontNumericDiagonalFractions,
+	"stacked-fractions":  rendering.FontNumericStackedFractions,
+	"ordinal":            rendering.FontNumericOrdinal,
+	"slashed-zero":       rendering.FontNumericSlashedZero,
+}
 
-	return problems[0]
+// normal|lining-nums|oldstyle-nums|proportional-nums|tabular-nums|diagonal-fractions|stacked-fractions|ordinal|slashed-zero|initial|inherit
+func (p FontVariantNumeric) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	numeric, err := fontVariantFlags(elm, values, fontVariantNumericKeywords,
+		func(f rendering.FontFeatures) rendering.FontNumeric { return f.Numeric })
+	if err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.Features.Numeric = numeric })
+	return nil
 }
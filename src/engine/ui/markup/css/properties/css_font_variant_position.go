@@ -7,16 +7,28 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p FontVariantPosition) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("FontVariantPosition not implemented")}
+var fontVariantPositionKeywords = map[string]rendering.FontPosition{
+	"normal":  rendering.FontPositionNormal,
+	"initial": rendering.FontPositionNormal,
+	"unset":   rendering.FontPositionNormal,
+	"sub":     rendering.FontPositionSub,
+	"super":   rendering.FontPositionSuper,
+}
 
-	return problems[0]
+// normal|sub|super|initial|inherit
+func (p FontVariantPosition) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	position, err := textLayoutKeyword(elm, values, fontVariantPositionKeywords,
+		func(l rendering.TextLayout) rendering.FontPosition { return l.Features.Position })
+	if err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.Features.Position = position })
+	return nil
 }
@@ -8,7 +8,6 @@ package properties
 
 import (
 	"strconv"
-	"strings"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -23,27 +22,79 @@ func (p Grid) Process(panel *ui.Panel, elm *document.Element, values []rules.Pro
 	if values[0].Str == "initial" || values[0].Str == "none" {
 		return nil
 	}
-	// Shorthand can specify template columns/rows; for now enable grid with parsed or default columns
-	cols := 3
-	if len(values) > 0 {
-		str := values[0].Str
-		if n, err := strconv.Atoi(str); err == nil && n > 0 {
-			cols = n
-		} else if strings.Contains(str, "repeat(") {
-			// crude parse for repeat(N, ...)
-			if idx := strings.Index(str, "("); idx > 0 {
-				part := strings.TrimSpace(str[idx+1:])
-				if comma := strings.Index(part, ","); comma > 0 {
-					if n, err := strconv.Atoi(strings.TrimSpace(part[:comma])); err == nil && n > 0 {
-						cols = n
-					}
-				}
-			}
-		} else if len(values) > 1 {
-			// multiple values may indicate columns
-			cols = len(values)
+	// A lone column count, e.g. "grid: 3"
+	if len(values) == 1 {
+		if n, err := strconv.Atoi(values[0].Str); err == nil && n > 0 {
+			panel.SetGrid(n)
+			return nil
+		}
+	}
+	parts := splitGridSlashValues(values)
+	if len(parts) == 2 {
+		if dense, auto, ok := gridAutoFlowValues(parts[0]); ok {
+			return p.processAutoFlow(panel, ui.GridAutoFlowRow, dense, auto, parts[1], host)
+		}
+		if dense, auto, ok := gridAutoFlowValues(parts[1]); ok {
+			return p.processAutoFlow(panel, ui.GridAutoFlowColumn, dense, auto, parts[0], host)
 		}
 	}
-	panel.SetGrid(cols)
+	template, err := parseGridTemplate(values, p.Key(), host.Window)
+	if err != nil {
+		return err
+	}
+	setGridTemplate(panel, template)
+	panel.SetGridAutoFlow(ui.GridAutoFlowRow, false)
+	panel.SetGridAutoRows(0)
+	panel.SetGridAutoColumns(0)
+	return nil
+}
+
+// processAutoFlow handles "auto-flow [dense] [<auto-rows>] / <columns>" and
+// "<rows> / auto-flow [dense] [<auto-columns>]"
+func (p Grid) processAutoFlow(panel *ui.Panel, flow ui.GridAutoFlow, dense bool, auto, tracks []rules.PropertyValue, host *engine.Host) error {
+	autoSize, err := gridAutoTrackSize(p.Key(), auto, host)
+	if err != nil {
+		return err
+	}
+	sizes, names, err := parseGridTrackList(tracks, p.Key(), host.Window)
+	if err != nil {
+		return err
+	}
+	panel.SetGridTemplateAreas(nil)
+	if flow == ui.GridAutoFlowRow {
+		panel.SetGridTemplateRows(nil)
+		panel.SetGridRowNames(nil)
+		panel.SetGridTemplateColumns(sizes)
+		panel.SetGridColumnNames(names)
+		panel.SetGridAutoRows(autoSize)
+		panel.SetGridAutoColumns(0)
+	} else {
+		panel.SetGridTemplateRows(sizes)
+		panel.SetGridRowNames(names)
+		panel.SetGridTemplateColumns(nil)
+		panel.SetGridColumnNames(nil)
+		panel.SetGridAutoColumns(autoSize)
+		panel.SetGridAutoRows(0)
+	}
+	panel.SetGridAutoFlow(flow, dense)
 	return nil
 }
+
+// gridAutoFlowValues splits "auto-flow [dense] <size>" into its parts, ok is
+// false when the values don't start with the auto-flow keyword
+func gridAutoFlowValues(values []rules.PropertyValue) (dense bool, size []rules.PropertyValue, ok bool) {
+	for i := range values {
+		switch values[i].Str {
+		case "auto-flow":
+			ok = true
+		case "dense":
+			dense = true
+		default:
+			if !ok {
+				return false, nil, false
+			}
+			size = append(size, values[i])
+		}
+	}
+	return dense, size, ok
+}
@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -16,7 +16,32 @@ import (
 )
 
 func (p GridArea) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("GridArea not implemented")}
-
-	return problems[0]
+	if len(values) == 0 {
+		return nil
+	}
+	parts := splitGridSlashValues(values)
+	if len(parts) > 4 {
+		return fmt.Errorf("%s expects at most four lines", p.Key())
+	}
+	// row-start / column-start / row-end / column-end, omitted lines copy
+	// the opposite named line or are auto
+	lines := [4]ui.GridLine{}
+	for i := range parts {
+		line, err := parseGridLineValue(parts[i], p.Key())
+		if err != nil {
+			return err
+		}
+		lines[i] = line
+	}
+	for i := len(parts); i < len(lines); i++ {
+		if i == 1 {
+			lines[i] = gridLineNameOnly(lines[0])
+		} else {
+			lines[i] = gridLineNameOnly(lines[i-2])
+		}
+	}
+	layout := panel.Base().Layout()
+	layout.SetGridRowLines(lines[0], lines[2])
+	layout.SetGridColumnLines(lines[1], lines[3])
+	return nil
 }
@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -16,7 +16,20 @@ import (
 )
 
 func (p GridAutoFlow) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("GridAutoFlow not implemented")}
-
-	return problems[0]
+	flow := ui.GridAutoFlowRow
+	dense := false
+	for i := range values {
+		switch values[i].Str {
+		case "row", "initial", "inherit", "unset":
+			flow = ui.GridAutoFlowRow
+		case "column":
+			flow = ui.GridAutoFlowColumn
+		case "dense":
+			dense = true
+		default:
+			return fmt.Errorf("invalid %s value %q", p.Key(), values[i].Str)
+		}
+	}
+	panel.SetGridAutoFlow(flow, dense)
+	return nil
 }
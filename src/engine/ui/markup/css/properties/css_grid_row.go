@@ -17,62 +17,61 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
-type gridLineValue struct {
-	line   int
-	span   int
-	isSpan bool
-}
-
 func (p GridRow) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
 	if len(values) == 0 {
 		return nil
 	}
-	startValues, endValues := splitGridLineValues(values)
-	start, err := parseGridLineValue(startValues, p.Key())
+	start, end, err := parseGridLinePair(values, p.Key())
 	if err != nil {
 		return err
 	}
-	end, err := parseGridLineValue(endValues, p.Key())
-	if err != nil {
-		return err
+	panel.Base().Layout().SetGridRowLines(start, end)
+	return nil
+}
+
+// parseGridLinePair reads the "start / end" form of grid-row and
+// grid-column. A lone named start line is also used as the end line
+func parseGridLinePair(values []rules.PropertyValue, property string) (ui.GridLine, ui.GridLine, error) {
+	parts := splitGridSlashValues(values)
+	if len(parts) > 2 {
+		return ui.GridLine{}, ui.GridLine{}, fmt.Errorf("%s expects at most two lines", property)
 	}
-	startLine := start.line
-	endLine := end.line
-	if start.isSpan {
-		if endLine == 0 {
-			return fmt.Errorf("grid-row start span requires an explicit end line")
-		}
-		startLine = endLine - start.span
-		if startLine < 1 {
-			startLine = 1
-		}
+	start, err := parseGridLineValue(parts[0], property)
+	if err != nil {
+		return start, ui.GridLine{}, err
 	}
-	if end.isSpan {
-		if startLine == 0 {
-			return fmt.Errorf("grid-row end span requires an explicit start line")
-		}
-		endLine = startLine + end.span
+	if len(parts) == 1 {
+		return start, gridLineNameOnly(start), nil
 	}
-	if startLine > 0 && endLine > 0 && endLine <= startLine {
-		return fmt.Errorf("grid-row end line must be greater than start line")
+	end, err := parseGridLineValue(parts[1], property)
+	return start, end, err
+}
+
+// gridLineNameOnly returns the line when it is only a name, which the grid
+// shorthands copy into the omitted lines, otherwise auto
+func gridLineNameOnly(line ui.GridLine) ui.GridLine {
+	if line.Name != "" && line.Line == 0 && line.Span == 0 {
+		return line
 	}
-	panel.Base().Layout().SetGridRow(startLine, endLine)
-	return nil
+	return ui.GridLine{}
 }
 
-func splitGridLineValues(values []rules.PropertyValue) ([]rules.PropertyValue, []rules.PropertyValue) {
+func splitGridSlashValues(values []rules.PropertyValue) [][]rules.PropertyValue {
+	parts := [][]rules.PropertyValue{}
+	last := 0
 	for i := range values {
 		if values[i].Str == "/" {
-			return values[:i], values[i+1:]
+			parts = append(parts, values[last:i])
+			last = i + 1
 		}
 	}
-	return values, nil
+	return append(parts, values[last:])
 }
 
-func parseGridLineValue(values []rules.PropertyValue, property string) (gridLineValue, error) {
-	if len(values) == 0 {
-		return gridLineValue{}, nil
-	}
+// parseGridLineValue reads a single grid line: auto, a line number, a line
+// name with an optional number picking the nth match, or span followed by a
+// count and/or a line name
+func parseGridLineValue(values []rules.PropertyValue, property string) (ui.GridLine, error) {
 	parts := make([]string, 0, len(values))
 	for i := range values {
 		part := strings.TrimSpace(values[i].Str)
@@ -81,27 +80,44 @@ func parseGridLineValue(values []rules.PropertyValue, property string) (gridLine
 		}
 	}
 	if len(parts) == 0 {
-		return gridLineValue{}, nil
+		return ui.GridLine{}, nil
 	}
 	if len(parts) == 1 {
 		switch parts[0] {
 		case "auto", "initial", "inherit", "unset":
-			return gridLineValue{}, nil
-		case "span":
-			return gridLineValue{}, fmt.Errorf("%s span requires a positive integer", property)
+			return ui.GridLine{}, nil
+		}
+	}
+	line := ui.GridLine{}
+	isSpan := false
+	for _, part := range parts {
+		if part == "span" {
+			if isSpan {
+				return ui.GridLine{}, fmt.Errorf("%s has more than one span", property)
+			}
+			isSpan = true
+			continue
 		}
-		line, err := strconv.Atoi(parts[0])
-		if err != nil || line <= 0 {
-			return gridLineValue{}, fmt.Errorf("%s line must be a positive integer", property)
+		if n, err := strconv.Atoi(part); err == nil {
+			if n == 0 || line.Line != 0 {
+				return ui.GridLine{}, fmt.Errorf("%s line must be a single non-zero integer", property)
+			}
+			line.Line = n
+			continue
 		}
-		return gridLineValue{line: line}, nil
+		if line.Name != "" || part == "auto" {
+			return ui.GridLine{}, fmt.Errorf("unsupported %s line value %q", property, strings.Join(parts, " "))
+		}
+		line.Name = part
 	}
-	if len(parts) == 2 && parts[0] == "span" {
-		span, err := strconv.Atoi(parts[1])
-		if err != nil || span <= 0 {
-			return gridLineValue{}, fmt.Errorf("%s span must be a positive integer", property)
+	if !isSpan {
+		if line.Line == 0 && line.Name == "" {
+			return ui.GridLine{}, fmt.Errorf("unsupported %s line value %q", property, strings.Join(parts, " "))
 		}
-		return gridLineValue{span: span, isSpan: true}, nil
+		return line, nil
+	}
+	if line.Line < 0 || (line.Line == 0 && line.Name == "") {
+		return ui.GridLine{}, fmt.Errorf("%s span requires a positive integer or a line name", property)
 	}
-	return gridLineValue{}, fmt.Errorf("unsupported %s line value %q", property, strings.Join(parts, " "))
+	return ui.GridLine{Name: line.Name, Span: max(line.Line, 1)}, nil
 }
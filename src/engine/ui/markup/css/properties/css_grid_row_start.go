@@ -7,8 +7,6 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
@@ -16,7 +14,12 @@ import (
 )
 
 func (p GridRowStart) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("GridRowStart not implemented")}
-
-	return problems[0]
+	start, err := parseGridLineValue(values, p.Key())
+	if err != nil {
+		return err
+	}
+	layout := panel.Base().Layout()
+	_, end := layout.GridRowLines()
+	layout.SetGridRowLines(start, end)
+	return nil
 }
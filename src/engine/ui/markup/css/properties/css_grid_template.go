@@ -7,16 +7,120 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
+	"strings"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+type gridTemplateValue struct {
+	rows        []float32
+	rowNames    [][]string
+	columns     []float32
+	columnNames [][]string
+	areas       [][]string
+}
+
 func (p GridTemplate) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("GridTemplate not implemented")}
+	if len(values) == 0 {
+		return nil
+	}
+	switch values[0].Str {
+	case "none", "initial", "inherit", "unset":
+		setGridTemplate(panel, gridTemplateValue{})
+		return nil
+	}
+	template, err := parseGridTemplate(values, p.Key(), host.Window)
+	if err != nil {
+		return err
+	}
+	setGridTemplate(panel, template)
+	return nil
+}
+
+func setGridTemplate(panel *ui.Panel, template gridTemplateValue) {
+	panel.SetGridTemplateRows(template.rows)
+	panel.SetGridRowNames(template.rowNames)
+	panel.SetGridTemplateAreas(template.areas)
+	if len(template.columns) > 0 {
+		panel.SetGridTemplateColumns(template.columns)
+		panel.SetGridColumnNames(template.columnNames)
+	} else {
+		panel.SetGridTemplateColumns(nil)
+		panel.SetGridColumnNames(nil)
+	}
+}
+
+func isGridAreaString(str string) bool {
+	return strings.HasPrefix(str, `"`) || strings.HasPrefix(str, "'")
+}
+
+// parseGridTemplate reads "<rows> / <columns>" or the template areas form
+// where each quoted row can be followed by its size and wrapped in line
+// names, e.g. "[top] 'a a' 40px [middle] 'b c' 1fr / 100px 1fr"
+func parseGridTemplate(values []rules.PropertyValue, property string, window helpers.WindowDimensions) (gridTemplateValue, error) {
+	t := gridTemplateValue{}
+	parts := splitGridSlashValues(values)
+	if len(parts) > 2 {
+		return t, fmt.Errorf("%s expects rows and columns separated by a single /", property)
+	}
+	rowValues := parts[0]
+	hasAreas := false
+	for i := range rowValues {
+		hasAreas = hasAreas || isGridAreaString(rowValues[i].Str)
+	}
+	var err error
+	if hasAreas {
+		err = t.parseAreaRows(rowValues, property, window)
+	} else if len(rowValues) > 0 && rowValues[0].Str != "none" {
+		t.rows, t.rowNames, err = parseGridTrackList(rowValues, property, window)
+	}
+	if err != nil {
+		return t, err
+	}
+	if len(parts) == 2 && len(parts[1]) > 0 && parts[1][0].Str != "none" {
+		t.columns, t.columnNames, err = parseGridTrackList(parts[1], property, window)
+	}
+	return t, err
+}
 
-	return problems[0]
+func (t *gridTemplateValue) parseAreaRows(values []rules.PropertyValue, property string, window helpers.WindowDimensions) error {
+	areaRows := []string{}
+	t.rowNames = [][]string{nil}
+	sized := false
+	for i := 0; i < len(values); i++ {
+		str := values[i].Str
+		switch {
+		case str == "[":
+			last := len(t.rowNames) - 1
+			for i++; i < len(values) && values[i].Str != "]"; i++ {
+				t.rowNames[last] = append(t.rowNames[last], values[i].Str)
+			}
+			if i == len(values) {
+				return fmt.Errorf("%s has an unclosed line name list", property)
+			}
+		case isGridAreaString(str):
+			areaRows = append(areaRows, str)
+			t.rows = append(t.rows, 0)
+			t.rowNames = append(t.rowNames, nil)
+			sized = false
+		default:
+			if len(areaRows) == 0 || sized {
+				return fmt.Errorf("%s row size %q must follow an area string", property, str)
+			}
+			size, err := gridTrackSize(values[i], property, window)
+			if err != nil {
+				return err
+			}
+			t.rows[len(t.rows)-1] = size
+			sized = true
+		}
+	}
+	areas, err := parseGridTemplateAreas(areaRows)
+	t.areas = areas
+	return err
 }
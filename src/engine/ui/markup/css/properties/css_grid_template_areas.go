@@ -7,7 +7,8 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
+	"strings"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -16,7 +17,67 @@ import (
 )
 
 func (p GridTemplateAreas) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("GridTemplateAreas not implemented")}
+	if len(values) == 0 {
+		return nil
+	}
+	switch values[0].Str {
+	case "none", "initial", "inherit", "unset":
+		panel.SetGridTemplateAreas(nil)
+		return nil
+	}
+	rows := make([]string, len(values))
+	for i := range values {
+		rows[i] = values[i].Str
+	}
+	areas, err := parseGridTemplateAreas(rows)
+	if err != nil {
+		return err
+	}
+	panel.SetGridTemplateAreas(areas)
+	return nil
+}
 
-	return problems[0]
+// parseGridTemplateAreas splits the quoted rows into cell names. Every row
+// needs the same number of cells and each named area must be a rectangle
+func parseGridTemplateAreas(rows []string) ([][]string, error) {
+	areas := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		row = strings.Trim(strings.TrimSpace(row), `"'`)
+		cells := strings.Fields(row)
+		if len(cells) == 0 {
+			return nil, fmt.Errorf("grid-template-areas row %q has no cells", row)
+		}
+		for i := range cells {
+			if strings.Trim(cells[i], ".") == "" {
+				cells[i] = "."
+			}
+		}
+		if len(areas) > 0 && len(cells) != len(areas[0]) {
+			return nil, fmt.Errorf("grid-template-areas rows must have the same number of cells")
+		}
+		areas = append(areas, cells)
+	}
+	bounds := map[string][4]int{}
+	for r := range areas {
+		for c, name := range areas[r] {
+			if name == "." {
+				continue
+			}
+			b, ok := bounds[name]
+			if !ok {
+				b = [4]int{r, c, r, c}
+			}
+			bounds[name] = [4]int{min(b[0], r), min(b[1], c), max(b[2], r), max(b[3], c)}
+		}
+	}
+	for name, b := range bounds {
+		for r := b[0]; r <= b[2]; r++ {
+			for c := b[1]; c <= b[3]; c++ {
+				if areas[r][c] != name {
+					return nil, fmt.Errorf("grid-template-areas area %q is not a rectangle", name)
+				}
+			}
+		}
+	}
+	return areas, nil
 }
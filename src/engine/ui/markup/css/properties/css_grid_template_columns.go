@@ -7,6 +7,7 @@
 package properties
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -25,51 +26,105 @@ func (p GridTemplateColumns) Process(panel *ui.Panel, elm *document.Element, val
 	if values[0].Str == "initial" || values[0].Str == "none" {
 		panel.SetFlowLayout()
 		panel.SetGridTemplateColumns(nil)
+		panel.SetGridColumnNames(nil)
 		return nil
 	}
 
-	// Explicit template, e.g. "8rem 1fr"
-	if len(values) > 1 {
-		cols := make([]float32, 0, len(values))
-		for i := range values {
-			s := strings.TrimSpace(values[i].Str)
-			if strings.HasSuffix(s, "fr") {
-				n := strings.TrimSpace(strings.TrimSuffix(s, "fr"))
-				if n == "" {
-					n = "1"
-				}
-				if f, err := strconv.ParseFloat(n, 32); err == nil && f > 0 {
-					cols = append(cols, -float32(f))
-					continue
-				}
-				cols = cols[:0]
-				break
-			}
-			cols = append(cols, helpers.NumFromLength(s, host.Window))
-		}
-		if len(cols) == len(values) {
-			panel.SetGridTemplateColumns(cols)
+	// A lone column count, e.g. "3"
+	if len(values) == 1 {
+		if n, err := strconv.Atoi(values[0].Str); err == nil && n > 0 {
+			panel.SetGrid(n)
+			panel.SetGridTemplateColumns(nil)
+			panel.SetGridColumnNames(nil)
 			return nil
 		}
 	}
 
-	cols := 3
-	str := values[0].Str
-	if n, err := strconv.Atoi(str); err == nil && n > 0 {
-		cols = n
-	} else if strings.Contains(str, "repeat(") {
-		if idx := strings.Index(str, "("); idx > 0 {
-			part := strings.TrimSpace(str[idx+1:])
-			if comma := strings.Index(part, ","); comma > 0 {
-				if n, err := strconv.Atoi(strings.TrimSpace(part[:comma])); err == nil && n > 0 {
-					cols = n
-				}
+	cols, names, err := parseGridTrackList(values, p.Key(), host.Window)
+	if err != nil {
+		return err
+	}
+	panel.SetGridTemplateColumns(cols)
+	panel.SetGridColumnNames(names)
+	return nil
+}
+
+// parseGridTrackList reads a track list such as "[full-start] 8rem
+// repeat(2, 1fr) [full-end]". Sizes use the panel convention of positive
+// pixels, negative fr units and zero for content sized tracks. The names
+// hold the bracketed line names for each line, starting from the first.
+func parseGridTrackList(values []rules.PropertyValue, property string, window helpers.WindowDimensions) ([]float32, [][]string, error) {
+	tracks := []float32{}
+	names := [][]string{nil}
+	tokens := make([]rules.PropertyValue, 0, len(values))
+	for i := range values {
+		if values[i].Str != "repeat" || !values[i].IsFunction() {
+			tokens = append(tokens, values[i])
+			continue
+		}
+		args := values[i].Args
+		count, err := strconv.Atoi(strings.TrimSpace(args[0]))
+		if err != nil || count <= 0 || len(args) < 2 {
+			return nil, nil, fmt.Errorf("%s only supports repeat with a positive count", property)
+		}
+		for range count {
+			for _, arg := range args[1:] {
+				tokens = append(tokens, rules.PropertyValue{Str: arg})
 			}
 		}
-	} else if len(values) > 1 {
-		cols = len(values)
 	}
-	panel.SetGrid(cols)
-	panel.SetGridTemplateColumns(nil)
-	return nil
+	for i := 0; i < len(tokens); i++ {
+		str := strings.TrimSpace(tokens[i].Str)
+		if str == "[" {
+			for i++; i < len(tokens) && tokens[i].Str != "]"; i++ {
+				names[len(names)-1] = append(names[len(names)-1], tokens[i].Str)
+			}
+			if i == len(tokens) {
+				return nil, nil, fmt.Errorf("%s has an unclosed line name list", property)
+			}
+			continue
+		}
+		size, err := gridTrackSize(tokens[i], property, window)
+		if err != nil {
+			return nil, nil, err
+		}
+		tracks = append(tracks, size)
+		names = append(names, nil)
+	}
+	if len(tracks) == 0 {
+		return nil, nil, fmt.Errorf("%s has no tracks", property)
+	}
+	return tracks, names, nil
+}
+
+func gridTrackSize(value rules.PropertyValue, property string, window helpers.WindowDimensions) (float32, error) {
+	str := strings.TrimSpace(value.Str)
+	switch {
+	case value.IsFunction() && str == "minmax" && len(value.Args) == 2:
+		// The maximum decides the size, unless it is content based
+		size, err := gridTrackSize(rules.PropertyValue{Str: value.Args[1]}, property, window)
+		if err != nil || size != 0 {
+			return size, err
+		}
+		return gridTrackSize(rules.PropertyValue{Str: value.Args[0]}, property, window)
+	case value.IsFunction() && str == "fit-content":
+		return 0, nil
+	case str == "auto" || str == "min-content" || str == "max-content":
+		return 0, nil
+	case strings.HasSuffix(str, "fr"):
+		n := strings.TrimSpace(strings.TrimSuffix(str, "fr"))
+		if n == "" {
+			n = "1"
+		}
+		f, err := strconv.ParseFloat(n, 32)
+		if err != nil || f < 0 {
+			return 0, fmt.Errorf("invalid %s fr value %q", property, str)
+		}
+		return -float32(f), nil
+	}
+	size := helpers.NumFromLength(str, window)
+	if size < 0 {
+		return 0, fmt.Errorf("invalid %s track size %q", property, str)
+	}
+	return size, nil
 }
@@ -7,8 +7,6 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
@@ -16,7 +14,20 @@ import (
 )
 
 func (p GridTemplateRows) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("GridTemplateRows not implemented")}
-
-	return problems[0]
+	if len(values) == 0 {
+		return nil
+	}
+	switch values[0].Str {
+	case "none", "initial", "inherit", "unset":
+		panel.SetGridTemplateRows(nil)
+		panel.SetGridRowNames(nil)
+		return nil
+	}
+	rows, names, err := parseGridTrackList(values, p.Key(), host.Window)
+	if err != nil {
+		return err
+	}
+	panel.SetGridTemplateRows(rows)
+	panel.SetGridRowNames(names)
+	return nil
 }
@@ -0,0 +1,155 @@
+/******************************************************************************/
+/* css_grid_test.go                                                           */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package properties
+
+import (
+	"slices"
+	"testing"
+
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+func gridValues(strs ...string) []rules.PropertyValue {
+	values := make([]rules.PropertyValue, len(strs))
+	for i := range strs {
+		values[i] = rules.PropertyValue{Str: strs[i]}
+	}
+	return values
+}
+
+func TestParseGridLineValue(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   ui.GridLine
+	}{
+		{[]string{"auto"}, ui.GridLine{}},
+		{[]string{"3"}, ui.GridLine{Line: 3}},
+		{[]string{"-1"}, ui.GridLine{Line: -1}},
+		{[]string{"main"}, ui.GridLine{Name: "main"}},
+		{[]string{"2", "main"}, ui.GridLine{Line: 2, Name: "main"}},
+		{[]string{"span", "2"}, ui.GridLine{Span: 2}},
+		{[]string{"span", "main"}, ui.GridLine{Span: 1, Name: "main"}},
+		{[]string{"main", "span", "3"}, ui.GridLine{Span: 3, Name: "main"}},
+	}
+	for _, test := range tests {
+		got, err := parseGridLineValue(gridValues(test.values...), "grid-row")
+		if err != nil {
+			t.Errorf("%v: %v", test.values, err)
+		} else if got != test.want {
+			t.Errorf("%v: expected %+v, got %+v", test.values, test.want, got)
+		}
+	}
+	for _, bad := range [][]string{{"0"}, {"span"}, {"span", "-2"}, {"a", "b"}, {"span", "1", "span"}} {
+		if _, err := parseGridLineValue(gridValues(bad...), "grid-row"); err == nil {
+			t.Errorf("%v: expected an error", bad)
+		}
+	}
+}
+
+func TestParseGridLinePair(t *testing.T) {
+	start, end, err := parseGridLinePair(gridValues("head"), "grid-row")
+	if err != nil || start.Name != "head" || end.Name != "head" {
+		t.Fatalf("expected a lone name to be used for both lines, got %+v %+v %v", start, end, err)
+	}
+	start, end, err = parseGridLinePair(gridValues("2", "/", "span", "3"), "grid-row")
+	if err != nil || start != (ui.GridLine{Line: 2}) || end != (ui.GridLine{Span: 3}) {
+		t.Fatalf("unexpected lines %+v %+v %v", start, end, err)
+	}
+	start, end, err = parseGridLinePair(gridValues("2"), "grid-row")
+	if err != nil || !end.IsAuto() {
+		t.Fatalf("expected a lone number to leave the end auto, got %+v %+v %v", start, end, err)
+	}
+}
+
+func TestParseGridTrackList(t *testing.T) {
+	values := []rules.PropertyValue{
+		{Str: "["}, {Str: "full-start"}, {Str: "]"},
+		{Str: "100px"},
+		{Str: "repeat", Args: []string{"2", "[", "col", "]", "1fr"}},
+		{Str: "minmax", Args: []string{"20px", "2fr"}},
+		{Str: "auto"},
+		{Str: "["}, {Str: "full-end"}, {Str: "]"},
+	}
+	tracks, names, err := parseGridTrackList(values, "grid-template-columns", testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tracks, []float32{100, -1, -1, -2, 0}) {
+		t.Fatalf("unexpected tracks %v", tracks)
+	}
+	if len(names) != len(tracks)+1 {
+		t.Fatalf("expected a name list per line, got %d", len(names))
+	}
+	if names[0][0] != "full-start" || names[1][0] != "col" || names[2][0] != "col" || names[5][0] != "full-end" {
+		t.Fatalf("unexpected line names %v", names)
+	}
+	if _, _, err := parseGridTrackList(gridValues("[", "a"), "grid-template-columns", testWindow{}); err == nil {
+		t.Fatal("expected an unclosed name list to fail")
+	}
+}
+
+func TestParseGridTemplateAreas(t *testing.T) {
+	areas, err := parseGridTemplateAreas([]string{`"head head"`, `"side ..."`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(areas[0], []string{"head", "head"}) || !slices.Equal(areas[1], []string{"side", "."}) {
+		t.Fatalf("unexpected areas %v", areas)
+	}
+	if _, err := parseGridTemplateAreas([]string{`"a b"`, `"a"`}); err == nil {
+		t.Fatal("expected uneven rows to fail")
+	}
+	if _, err := parseGridTemplateAreas([]string{`"a b"`, `"b a"`}); err == nil {
+		t.Fatal("expected a non rectangular area to fail")
+	}
+}
+
+func TestParseGridTemplate(t *testing.T) {
+	values := gridValues("[", "top", "]", `"a a"`, "40px", `"b c"`, "/", "100px", "1fr")
+	template, err := parseGridTemplate(values, "grid-template", testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(template.rows, []float32{40, 0}) {
+		t.Fatalf("unexpected rows %v", template.rows)
+	}
+	if !slices.Equal(template.columns, []float32{100, -1}) {
+		t.Fatalf("unexpected columns %v", template.columns)
+	}
+	if len(template.areas) != 2 || template.areas[1][1] != "c" {
+		t.Fatalf("unexpected areas %v", template.areas)
+	}
+	if len(template.rowNames) != 3 || template.rowNames[0][0] != "top" {
+		t.Fatalf("unexpected row names %v", template.rowNames)
+	}
+	template, err = parseGridTemplate(gridValues("1fr", "2fr", "/", "50px"), "grid-template", testWindow{})
+	if err != nil || !slices.Equal(template.rows, []float32{-1, -2}) || template.areas != nil {
+		t.Fatalf("unexpected rows/columns template %+v %v", template, err)
+	}
+}
+
+func TestParseJustifyAlign(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   ui.FlexAlign
+	}{
+		{[]string{"center"}, ui.FlexAlignCenter},
+		{[]string{"safe", "end"}, ui.FlexAlignEnd},
+		{[]string{"left"}, ui.FlexAlignStart},
+		{[]string{"right"}, ui.FlexAlignEnd},
+		{[]string{"normal"}, ui.FlexAlignStretch},
+	}
+	for _, test := range tests {
+		if got, ok := parseJustifyAlign(gridValues(test.values...)); !ok || got != test.want {
+			t.Errorf("%v: expected %d, got %d (%v)", test.values, test.want, got, ok)
+		}
+	}
+	if _, ok := parseJustifyAlign(gridValues("center", "end")); ok {
+		t.Error("expected two alignments to fail")
+	}
+}
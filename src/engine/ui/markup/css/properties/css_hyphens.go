@@ -7,16 +7,43 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p Hyphens) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Hyphens not implemented")}
+var hyphensKeywords = map[string]rendering.TextHyphens{
+	"manual":  rendering.TextHyphensManual,
+	"initial": rendering.TextHyphensManual,
+	"unset":   rendering.TextHyphensManual,
+	"none":    rendering.TextHyphensNone,
+	"auto":    rendering.TextHyphensAuto,
+}
 
-	return problems[0]
+// elementLanguage finds the lang attribute of the element or the closest
+// ancestor that has one
+func elementLanguage(elm *document.Element) string {
+	for e := elm; e != nil; e = e.Parent.Value() {
+		if lang := e.Attribute("lang"); lang != "" {
+			return lang
+		}
+	}
+	return ""
+}
+
+// none|manual|auto|initial|inherit
+func (p Hyphens) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	mode, err := textLayoutKeyword(elm, values, hyphensKeywords,
+		func(l rendering.TextLayout) rendering.TextHyphens { return l.Hyphens })
+	if err != nil {
+		return err
+	}
+	lang := elementLanguage(elm)
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) {
+		l.Hyphens = mode
+		l.Language = lang
+	})
+	return nil
 }
@@ -16,7 +16,5 @@ import (
 )
 
 func (p Import) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Import not implemented")}
-
-	return problems[0]
+	return errors.New("import must be declared with the @import at-rule")
 }
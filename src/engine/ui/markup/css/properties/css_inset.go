@@ -8,6 +8,7 @@ package properties
 
 import (
 	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +16,15 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// auto|length{1,4}|initial|inherit
 func (p Inset) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Inset not implemented")}
-
-	return problems[0]
+	if len(values) == 0 || len(values) > 4 {
+		return fmt.Errorf("expected 1 to 4 values but got %d", len(values))
+	}
+	values = expandFourSideValues(values)
+	return errors.Join(
+		Top{}.Process(panel, elm, values[:1], host),
+		Right{}.Process(panel, elm, values[1:2], host),
+		Bottom{}.Process(panel, elm, values[2:3], host),
+		Left{}.Process(panel, elm, values[3:], host))
 }
@@ -7,16 +7,14 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// auto|length|initial|inherit
 func (p InsetBlockEnd) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("InsetBlockEnd not implemented")}
-
-	return problems[0]
+	_, end := blockSides(panel)
+	return insetSideProperties[end].Process(panel, elm, values, host)
 }
@@ -7,7 +7,8 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
+	"strings"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -16,7 +17,40 @@ import (
 )
 
 func (p JustifyItems) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("JustifyItems not implemented")}
+	if len(values) == 0 {
+		return nil
+	}
+	align, ok := parseJustifyAlign(values)
+	if !ok || align == ui.FlexAlignAuto {
+		return fmt.Errorf("invalid justify-items value %q", joinValueStrings(values))
+	}
+	panel.SetGridJustifyItems(align)
+	return nil
+}
+
+// parseJustifyAlign reads the inline axis alignment of grid items, the
+// overflow (safe/unsafe) and legacy keywords are accepted but ignored
+func parseJustifyAlign(values []rules.PropertyValue) (ui.FlexAlign, bool) {
+	align, found := ui.FlexAlignAuto, false
+	for i := range values {
+		switch values[i].Str {
+		case "safe", "unsafe", "legacy":
+			continue
+		case "left":
+			align, found = ui.FlexAlignStart, true
+		case "right":
+			align, found = ui.FlexAlignEnd, true
+		default:
+			a, ok := parseFlexAlign(values[i].Str)
+			if !ok || found {
+				return ui.FlexAlignAuto, false
+			}
+			align, found = a, true
+		}
+	}
+	return align, found
+}
 
-	return problems[0]
+func joinValueStrings(values []rules.PropertyValue) string {
+	return strings.Join(valuesToStrings(values), " ")
 }
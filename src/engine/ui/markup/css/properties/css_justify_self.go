@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -16,7 +16,13 @@ import (
 )
 
 func (p JustifySelf) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("JustifySelf not implemented")}
-
-	return problems[0]
+	if len(values) == 0 {
+		return nil
+	}
+	align, ok := parseJustifyAlign(values)
+	if !ok {
+		return fmt.Errorf("invalid justify-self value %q", joinValueStrings(values))
+	}
+	panel.Base().Layout().SetJustifySelf(align)
+	return nil
 }
@@ -16,7 +16,5 @@ import (
 )
 
 func (p Keyframes) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Keyframes not implemented")}
-
-	return problems[0]
+	return errors.New("keyframes must be declared with the @keyframes at-rule")
 }
@@ -7,16 +7,30 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p LineBreak) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("LineBreak not implemented")}
+var lineBreakKeywords = map[string]rendering.TextLineBreak{
+	"auto":     rendering.TextLineBreakAuto,
+	"initial":  rendering.TextLineBreakAuto,
+	"unset":    rendering.TextLineBreakAuto,
+	"loose":    rendering.TextLineBreakLoose,
+	"normal":   rendering.TextLineBreakNormal,
+	"strict":   rendering.TextLineBreakStrict,
+	"anywhere": rendering.TextLineBreakAnywhere,
+}
 
-	return problems[0]
+// auto|loose|normal|strict|anywhere|initial|inherit
+func (p LineBreak) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	mode, err := textLayoutKeyword(elm, values, lineBreakKeywords,
+		func(l rendering.TextLayout) rendering.TextLineBreak { return l.LineBreak })
+	if err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.LineBreak = mode })
+	return nil
 }
@@ -0,0 +1,44 @@
+/******************************************************************************/
+/* css_line_clamp.go                                                          */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package properties
+
+import (
+	"fmt"
+	"strconv"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
+)
+
+// none|integer|initial|inherit
+func (p LineClamp) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	lines := 0
+	overflow := rendering.TextOverflowEllipsis
+	switch values[0].Str {
+	case "none", "initial", "unset":
+		overflow = rendering.TextOverflowClip
+	case "inherit":
+		inherited := parentTextLayout(elm)
+		lines, overflow = inherited.MaxLines, inherited.Overflow
+	default:
+		var err error
+		if lines, err = strconv.Atoi(values[0].Str); err != nil || lines <= 0 {
+			return fmt.Errorf("line-clamp expects a positive integer but got '%s'", values[0].Str)
+		}
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) {
+		l.MaxLines = lines
+		l.Overflow = overflow
+	})
+	return nil
+}
@@ -8,15 +8,28 @@ package properties
 
 import (
 	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// list-style-type list-style-position list-style-image
 func (p ListStyle) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("ListStyle not implemented")}
-
-	return problems[0]
+	if len(values) == 0 || len(values) > 3 {
+		return errors.New("list-style expects 1 to 3 values")
+	}
+	for i := range values {
+		switch values[i].Str {
+		case "inside", "outside", "url":
+		default:
+			if !helpers.IsCounterStyle(values[i].Str) && !helpers.IsQuotedString(values[i].Str) {
+				return fmt.Errorf("unsupported list-style value: %s", values[i].Str)
+			}
+		}
+	}
+	return nil
 }
@@ -7,16 +7,25 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// disc|circle|square|decimal|decimal-leading-zero|lower-roman|upper-roman|lower-greek|lower-latin|upper-latin|lower-alpha|upper-alpha|none|string
+//
+// The markers are generated by the list items themselves, which inherit the
+// type from the closest ancestor that sets it
 func (p ListStyleType) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("ListStyleType not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	if !helpers.IsCounterStyle(values[0].Str) && !helpers.IsQuotedString(values[0].Str) {
+		return fmt.Errorf("unsupported list-style-type: %s", values[0].Str)
+	}
+	return nil
 }
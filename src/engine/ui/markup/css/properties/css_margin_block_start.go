@@ -7,16 +7,14 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// length|auto|initial|inherit
 func (p MarginBlockStart) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("MarginBlockStart not implemented")}
-
-	return problems[0]
+	start, _ := blockSides(panel)
+	return marginSideProperties[start].Process(panel, elm, values, host)
 }
@@ -7,16 +7,14 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// length|auto|initial|inherit
 func (p MarginInline) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("MarginInline not implemented")}
-
-	return problems[0]
+	start, end := inlineSides(panel)
+	return processLogicalSides(marginSideProperties, start, end, panel, elm, values, host)
 }
@@ -7,16 +7,14 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// length|auto|initial|inherit
 func (p MarginInlineEnd) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("MarginInlineEnd not implemented")}
-
-	return problems[0]
+	_, end := inlineSides(panel)
+	return marginSideProperties[end].Process(panel, elm, values, host)
 }
@@ -8,15 +8,49 @@ package properties
 
 import (
 	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/functions"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// mask-image || mask-mode
+//
+// The position, size, repeat, origin, clip and composite of a mask layer
+// are not supported and give an error after the image and mode are set
 func (p Mask) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Mask not implemented")}
-
-	return problems[0]
+	if len(values) == 0 {
+		return errors.New("Mask requires a value")
+	}
+	var mask *ui.Gradient
+	mode := ui.MaskModeAlpha
+	var unsupported []string
+	for i := 0; i < len(values); i++ {
+		if values[i].Separated {
+			unsupported = append(unsupported, "additional mask layers")
+			break
+		}
+		if m, ok := maskModes[values[i].Str]; ok && values[i].Str != "initial" {
+			mode = m
+			continue
+		}
+		m, used, err := maskImage(values[i:], host.Window)
+		if err == nil {
+			mask = m
+			i += used - 1
+		} else if functions.IsGradient(values[i]) {
+			return err
+		} else {
+			unsupported = append(unsupported, values[i].Str)
+		}
+	}
+	panel.SetMaskGradient(mask)
+	panel.SetMaskMode(mode)
+	if len(unsupported) > 0 {
+		return fmt.Errorf("unsupported mask values %v", unsupported)
+	}
+	return nil
 }
@@ -8,15 +8,48 @@ package properties
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/functions"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
-func (p MaskImage) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("MaskImage not implemented")}
+// maskImage reads the first value(s) as a mask image, returning the mask
+// (nil for none) and how many values it used
+func maskImage(values []rules.PropertyValue, window helpers.WindowDimensions) (*ui.Gradient, int, error) {
+	switch {
+	case values[0].Str == "none" || values[0].Str == "initial":
+		return nil, 1, nil
+	case functions.IsGradient(values[0]):
+		value, used := functions.GradientValue(values)
+		g, err := functions.ParseGradient(value, window)
+		if err != nil {
+			return nil, used, err
+		}
+		return &g, used, nil
+	case strings.HasPrefix(values[0].Str, "url"):
+		return nil, 1, errors.New("only gradients are supported as mask images")
+	}
+	return nil, 1, fmt.Errorf("invalid mask image %s", values[0].Str)
+}
 
-	return problems[0]
+// none|gradient|initial
+func (p MaskImage) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) == 0 {
+		return errors.New("MaskImage requires a value")
+	}
+	mask, used, err := maskImage(values, host.Window)
+	if err != nil {
+		return err
+	}
+	panel.SetMaskGradient(mask)
+	if used < len(values) {
+		return errors.New("only a single mask layer is supported")
+	}
+	return nil
 }
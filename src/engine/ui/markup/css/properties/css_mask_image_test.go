@@ -0,0 +1,74 @@
+/******************************************************************************/
+/* css_mask_image_test.go                                                     */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package properties
+
+import (
+	"testing"
+
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/matrix"
+)
+
+func TestMaskImageLinearGradient(t *testing.T) {
+	// linear-gradient(to bottom right, rgba(0, 0, 0, 1) 20%, transparent) as
+	// it comes from the parser
+	mask, used, err := maskImage([]rules.PropertyValue{
+		{Str: "linear-gradient", Args: []string{"to", "bottom", "right"}},
+		{Str: "rgba", Args: []string{"0", "0", "0", "1", "20%", "transparent"}},
+		{Str: "luminance"},
+	}, testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used != 2 {
+		t.Fatalf("expected the gradient to use 2 values, used %d", used)
+	}
+	if mask.Kind != ui.GradientLinear || mask.Corner != (matrix.Vec2{1, 1}) || len(mask.Stops) != 2 {
+		t.Fatalf("unexpected gradient %+v", mask)
+	}
+	if mask.Stops[0].Color.A() != 1 || !mask.Stops[0].HasPosition || mask.Stops[0].Position.Value != 0.2 {
+		t.Fatalf("unexpected first stop %+v", mask.Stops[0])
+	}
+	if mask.Stops[1].Color.A() != 0 || mask.Stops[1].HasPosition {
+		t.Fatalf("unexpected last stop %+v", mask.Stops[1])
+	}
+}
+
+func TestMaskImageRepeatingRadialGradient(t *testing.T) {
+	mask, _, err := maskImage([]rules.PropertyValue{{
+		Str:  "repeating-radial-gradient",
+		Args: []string{"circle", "closest-side", "at", "left", "10px", "black", "black", "10%", "transparent", "20%"},
+	}}, testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mask.Repeating || mask.Kind != ui.GradientRadial || !mask.Circle ||
+		mask.Extent != ui.GradientExtentClosestSide {
+		t.Fatalf("unexpected gradient %+v", mask)
+	}
+	if mask.Center != [2]ui.ClipLength{{Value: 0, Percent: true}, {Value: 10}} || len(mask.Stops) != 3 {
+		t.Fatalf("unexpected gradient %+v", mask)
+	}
+}
+
+func TestMaskImageGradientErrors(t *testing.T) {
+	invalid := [][]rules.PropertyValue{
+		{{Str: "linear-gradient", Args: []string{"black"}}},
+		{{Str: "linear-gradient", Args: []string{"to", "middle", "black", "white"}}},
+		{{Str: "radial-gradient", Args: []string{"ellipse", "10px", "black", "white"}}},
+		{{Str: "url(\"mask.png\")"}},
+	}
+	for _, v := range invalid {
+		if _, _, err := maskImage(v, testWindow{}); err == nil {
+			t.Errorf("expected %+v to be invalid", v)
+		}
+	}
+	if mask, _, err := maskImage([]rules.PropertyValue{{Str: "none"}}, testWindow{}); err != nil || mask != nil {
+		t.Fatalf("expected none to remove the mask, got %+v, %v", mask, err)
+	}
+}
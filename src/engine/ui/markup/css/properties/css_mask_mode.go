@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,24 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
-func (p MaskMode) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("MaskMode not implemented")}
+// Gradients are images without a luminance mask of their own, so
+// match-source is the same as alpha
+var maskModes = map[string]ui.MaskMode{
+	"alpha":        ui.MaskModeAlpha,
+	"luminance":    ui.MaskModeLuminance,
+	"match-source": ui.MaskModeAlpha,
+	"initial":      ui.MaskModeAlpha,
+}
 
-	return problems[0]
+// alpha|luminance|match-source|initial
+func (p MaskMode) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	mode, ok := maskModes[values[0].Str]
+	if !ok {
+		return fmt.Errorf("invalid mask mode %s", values[0].Str)
+	}
+	panel.SetMaskMode(mode)
+	return nil
 }
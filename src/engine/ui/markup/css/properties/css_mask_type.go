@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,17 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// luminance|alpha|initial
+//
+// This only changes SVG mask elements, which the UI doesn't have, so the
+// value is only checked
 func (p MaskType) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("MaskType not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	switch values[0].Str {
+	case "luminance", "alpha", "initial":
+		return nil
+	}
+	return fmt.Errorf("invalid mask type %s", values[0].Str)
 }
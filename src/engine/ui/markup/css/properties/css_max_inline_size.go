@@ -7,16 +7,13 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// none|length|initial|inherit
 func (p MaxInlineSize) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("MaxInlineSize not implemented")}
-
-	return problems[0]
+	return processLogicalSize(true, MaxWidth{}, MaxHeight{}, panel, elm, values, host)
 }
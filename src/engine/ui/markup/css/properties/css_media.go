@@ -16,7 +16,5 @@ import (
 )
 
 func (p Media) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Media not implemented")}
-
-	return problems[0]
+	return errors.New("media must be declared with the @media at-rule")
 }
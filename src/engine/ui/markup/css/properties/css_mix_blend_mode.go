@@ -8,6 +8,7 @@ package properties
 
 import (
 	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +16,15 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// normal|multiply|screen|overlay|darken|lighten|color-dodge|color-burn|hard-light|soft-light|difference|exclusion|hue|saturation|color|luminosity|plus-lighter
+//
+// The UI passes only do normal blending, so every other mode is rejected
 func (p MixBlendMode) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("MixBlendMode not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	if values[0].Str == "normal" || values[0].Str == "initial" {
+		return nil
+	}
+	return errors.New("MixBlendMode not implemented")
 }
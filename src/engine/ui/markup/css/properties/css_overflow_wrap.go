@@ -7,16 +7,28 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p OverflowWrap) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("OverflowWrap not implemented")}
+var overflowWrapKeywords = map[string]rendering.TextOverflowWrap{
+	"normal":     rendering.TextOverflowWrapNormal,
+	"initial":    rendering.TextOverflowWrapNormal,
+	"unset":      rendering.TextOverflowWrapNormal,
+	"break-word": rendering.TextOverflowWrapBreakWord,
+	"anywhere":   rendering.TextOverflowWrapAnywhere,
+}
 
-	return problems[0]
+// normal|break-word|anywhere|initial|inherit
+func (p OverflowWrap) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	mode, err := textLayoutKeyword(elm, values, overflowWrapKeywords,
+		func(l rendering.TextLayout) rendering.TextOverflowWrap { return l.OverflowWrap })
+	if err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.OverflowWrap = mode })
+	return nil
 }
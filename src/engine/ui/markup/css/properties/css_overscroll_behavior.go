@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,50 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
-func (p OverscrollBehavior) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("OverscrollBehavior not implemented")}
+func overscrollBehaviorValue(key string, values []rules.PropertyValue, index int) (ui.OverscrollBehavior, error) {
+	switch values[index].Str {
+	case "auto", "initial", "inherit":
+		return ui.OverscrollBehaviorAuto, nil
+	case "contain":
+		return ui.OverscrollBehaviorContain, nil
+	case "none":
+		return ui.OverscrollBehaviorNone, nil
+	}
+	return ui.OverscrollBehaviorAuto, fmt.Errorf("%s expected auto, contain or none, but got: %s", key, values[index].Str)
+}
 
-	return problems[0]
+// setOverscrollAxis sets the behavior of one axis, the x axis if horizontal
+func setOverscrollAxis(panel *ui.Panel, key string, values []rules.PropertyValue, horizontal bool) error {
+	if len(values) != 1 {
+		return fmt.Errorf("%s expects exactly one value", key)
+	}
+	b, err := overscrollBehaviorValue(key, values, 0)
+	if err != nil {
+		return err
+	}
+	x, y := panel.OverscrollBehavior()
+	if horizontal {
+		x = b
+	} else {
+		y = b
+	}
+	panel.SetOverscrollBehavior(x, y)
+	return nil
+}
+
+// [auto|contain|none]{1,2}|initial|inherit
+func (p OverscrollBehavior) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) == 0 || len(values) > 2 {
+		return fmt.Errorf("%s expects 1 or 2 values but got %d", p.Key(), len(values))
+	}
+	x, err := overscrollBehaviorValue(p.Key(), values, 0)
+	if err != nil {
+		return err
+	}
+	y, err := overscrollBehaviorValue(p.Key(), values, len(values)-1)
+	if err != nil {
+		return err
+	}
+	panel.SetOverscrollBehavior(x, y)
+	return nil
 }
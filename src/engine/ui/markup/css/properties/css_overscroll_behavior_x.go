@@ -7,16 +7,13 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// auto|contain|none|initial|inherit
 func (p OverscrollBehaviorX) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("OverscrollBehaviorX not implemented")}
-
-	return problems[0]
+	return setOverscrollAxis(panel, p.Key(), values, true)
 }
@@ -7,16 +7,14 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// length|initial|inherit
 func (p PaddingBlock) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("PaddingBlock not implemented")}
-
-	return problems[0]
+	start, end := blockSides(panel)
+	return processLogicalSides(paddingSideProperties, start, end, panel, elm, values, host)
 }
@@ -11,12 +11,21 @@ import (
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// length|none|initial|inherit
 func (p Perspective) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Perspective not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return errors.New("perspective expects 1 value")
+	}
+	switch values[0].Str {
+	case "none", "initial", "inherit", "unset":
+		panel.SetPerspective(0)
+	default:
+		panel.SetPerspective(helpers.NumFromLength(values[0].Str, host.Window))
+	}
+	return nil
 }
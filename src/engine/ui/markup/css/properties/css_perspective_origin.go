@@ -15,8 +15,18 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// x-position [y-position]|initial|inherit
 func (p PerspectiveOrigin) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("PerspectiveOrigin not implemented")}
-
-	return problems[0]
+	if len(values) > 2 {
+		return errors.New("perspective-origin expects 1 or 2 values")
+	}
+	if len(values) == 1 && (values[0].Str == "initial" || values[0].Str == "inherit") {
+		values = nil
+	}
+	x, y, _, err := transformOriginValues(values, host.Window)
+	if values != nil && err != nil {
+		return err
+	}
+	panel.SetPerspectiveOrigin(x, y)
+	return nil
 }
@@ -7,8 +7,6 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
@@ -16,7 +14,20 @@ import (
 )
 
 func (p PlaceItems) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("PlaceItems not implemented")}
+	if len(values) == 0 {
+		return nil
+	}
+	if err := (AlignItems{}).Process(panel, elm, values[:1], host); err != nil {
+		return err
+	}
+	return JustifyItems{}.Process(panel, elm, placeSecondValue(values), host)
+}
 
-	return problems[0]
+// placeSecondValue returns the justify half of a place-* shorthand, which
+// copies the align value when omitted
+func placeSecondValue(values []rules.PropertyValue) []rules.PropertyValue {
+	if len(values) > 1 {
+		return values[1:]
+	}
+	return values[:1]
 }
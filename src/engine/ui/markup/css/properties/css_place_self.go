@@ -7,8 +7,6 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
@@ -16,7 +14,11 @@ import (
 )
 
 func (p PlaceSelf) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("PlaceSelf not implemented")}
-
-	return problems[0]
+	if len(values) == 0 {
+		return nil
+	}
+	if err := (AlignSelf{}).Process(panel, elm, values[:1], host); err != nil {
+		return err
+	}
+	return JustifySelf{}.Process(panel, elm, placeSecondValue(values), host)
 }
@@ -111,6 +111,7 @@ var PropertyMap = map[string]document.CSSProperty{
 	"charset":                     Charset{},
 	"clear":                       Clear{},
 	"clip":                        Clip{},
+	"clip-path":                   ClipPath{},
 	"color":                       Color{},
 	"column-count":                ColumnCount{},
 	"column-fill":                 ColumnFill{},
@@ -122,9 +123,13 @@ var PropertyMap = map[string]document.CSSProperty{
 	"column-span":                 ColumnSpan{},
 	"column-width":                ColumnWidth{},
 	"columns":                     Columns{},
+	"container":                   Container{},
+	"container-name":              ContainerName{},
+	"container-type":              ContainerType{},
 	"content":                     Content{},
 	"counter-increment":           CounterIncrement{},
 	"counter-reset":               CounterReset{},
+	"counter-set":                 CounterSet{},
 	"cursor":                      Cursor{},
 	"direction":                   Direction{},
 	"display":                     Display{},
@@ -198,6 +203,7 @@ var PropertyMap = map[string]document.CSSProperty{
 	"left":                        Left{},
 	"letter-spacing":              LetterSpacing{},
 	"line-break":                  LineBreak{},
+	"line-clamp":                  LineClamp{},
 	"line-height":                 LineHeight{},
 	"list-style":                  ListStyle{},
 	"list-style-image":            ListStyleImage{},
@@ -7,9 +7,14 @@
 package properties
 
 import (
+	"errors"
+	"fmt"
+
+	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
 func childLabels(elm *document.Element) []*ui.Label {
@@ -24,6 +29,41 @@ func childLabels(elm *document.Element) []*ui.Label {
 	return labels
 }
 
+func parentTextLayout(elm *document.Element) rendering.TextLayout {
+	if parent := elm.Parent.Value(); parent != nil {
+		if parentLabels := childLabels(parent); len(parentLabels) > 0 {
+			return parentLabels[0].TextLayout()
+		}
+	}
+	return rendering.TextLayout{}
+}
+
+func updateChildTextLayouts(elm *document.Element, update func(layout *rendering.TextLayout)) {
+	for _, label := range childLabels(elm) {
+		layout := label.TextLayout()
+		update(&layout)
+		label.SetTextLayout(layout)
+	}
+}
+
+// textLayoutKeyword resolves the single keyword value of a text layout
+// property, inherit reads the value from the text of the parent element
+func textLayoutKeyword[T any](elm *document.Element, values []rules.PropertyValue,
+	keywords map[string]T, inherited func(rendering.TextLayout) T) (T, error) {
+	var out T
+	if len(values) != 1 {
+		return out, fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	if values[0].Str == "inherit" {
+		return inherited(parentTextLayout(elm)), nil
+	}
+	out, ok := keywords[values[0].Str]
+	if !ok {
+		return out, fmt.Errorf("unsupported value '%s'", values[0].Str)
+	}
+	return out, nil
+}
+
 func expandFourSideValues(values []rules.PropertyValue) []rules.PropertyValue {
 	values = clonePropertyValues(values)
 	switch len(values) {
@@ -47,3 +87,47 @@ func clonePropertyValues(values []rules.PropertyValue) []rules.PropertyValue {
 	}
 	return out
 }
+
+// The physical properties of each ui.Side, logical properties are processed
+// as the property of the side they map to for the writing mode and direction
+var (
+	marginSideProperties        = [4]document.CSSProperty{MarginLeft{}, MarginTop{}, MarginRight{}, MarginBottom{}}
+	paddingSideProperties       = [4]document.CSSProperty{PaddingLeft{}, PaddingTop{}, PaddingRight{}, PaddingBottom{}}
+	insetSideProperties         = [4]document.CSSProperty{Left{}, Top{}, Right{}, Bottom{}}
+	borderSideProperties        = [4]document.CSSProperty{BorderLeft{}, BorderTop{}, BorderRight{}, BorderBottom{}}
+	borderSideColorProperties   = [4]document.CSSProperty{BorderLeftColor{}, BorderTopColor{}, BorderRightColor{}, BorderBottomColor{}}
+	borderSideStyleProperties   = [4]document.CSSProperty{BorderLeftStyle{}, BorderTopStyle{}, BorderRightStyle{}, BorderBottomStyle{}}
+	borderSideWidthProperties   = [4]document.CSSProperty{BorderLeftWidth{}, BorderTopWidth{}, BorderRightWidth{}, BorderBottomWidth{}}
+	scrollMarginSideProperties  = [4]document.CSSProperty{ScrollMarginLeft{}, ScrollMarginTop{}, ScrollMarginRight{}, ScrollMarginBottom{}}
+	scrollPaddingSideProperties = [4]document.CSSProperty{ScrollPaddingLeft{}, ScrollPaddingTop{}, ScrollPaddingRight{}, ScrollPaddingBottom{}}
+)
+
+func inlineSides(panel *ui.Panel) (start, end ui.Side) {
+	return ui.InlineSides(panel.ResolvedWritingMode(), panel.ResolvedDirection())
+}
+
+func blockSides(panel *ui.Panel) (start, end ui.Side) {
+	return ui.BlockSides(panel.ResolvedWritingMode())
+}
+
+// processLogicalSides processes the values of a start and end shorthand, such
+// as margin-inline, on the physical sides. A single value is used for both
+func processLogicalSides(sideProperties [4]document.CSSProperty, start, end ui.Side,
+	panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) == 0 || len(values) > 2 {
+		return fmt.Errorf("expected 1 or 2 values but got %d", len(values))
+	}
+	return errors.Join(
+		sideProperties[start].Process(panel, elm, values[:1], host),
+		sideProperties[end].Process(panel, elm, values[len(values)-1:], host))
+}
+
+// processLogicalSize processes an inline or block size as the width or the
+// height depending on if the writing mode is vertical
+func processLogicalSize(inline bool, width, height document.CSSProperty,
+	panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if inline == ui.IsVerticalWritingMode(panel.ResolvedWritingMode()) {
+		return height.Process(panel, elm, values, host)
+	}
+	return width.Process(panel, elm, values, host)
+}
@@ -555,6 +555,11 @@ type Clip struct{ PropertyBase }
 
 func (p Clip) Key() string { return "clip" }
 
+// Clips an element to a basic shape, the parts outside of the shape are hidden
+type ClipPath struct{ PropertyBase }
+
+func (p ClipPath) Key() string { return "clip-path" }
+
 // Sets the color of text
 type Color struct{ PropertyBase }
 
@@ -610,6 +615,21 @@ type Columns struct{ PropertyBase }
 
 func (p Columns) Key() string { return "columns" }
 
+// A shorthand property for the container-name and container-type properties
+type Container struct{ PropertyBase }
+
+func (p Container) Key() string { return "container" }
+
+// Specifies the names an element can be queried by in @container rules
+type ContainerName struct{ PropertyBase }
+
+func (p ContainerName) Key() string { return "container-name" }
+
+// Specifies whether an element is a size container for @container rules
+type ContainerType struct{ PropertyBase }
+
+func (p ContainerType) Key() string { return "container-type" }
+
 // Used with the :before and :after pseudo-elements, to insert generated content
 type Content struct{ PropertyBase }
 
@@ -625,6 +645,11 @@ type CounterReset struct{ PropertyBase }
 
 func (p CounterReset) Key() string { return "counter-reset" }
 
+// Sets one or more CSS counters to a value
+type CounterSet struct{ PropertyBase }
+
+func (p CounterSet) Key() string { return "counter-set" }
+
 // Specifies the mouse cursor to be displayed when pointing over an element
 type Cursor struct{ PropertyBase }
 
@@ -990,6 +1015,11 @@ type LineBreak struct{ PropertyBase }
 
 func (p LineBreak) Key() string { return "line-break" }
 
+// Limits the text to a number of lines, ending the last with an ellipsis
+type LineClamp struct{ PropertyBase }
+
+func (p LineClamp) Key() string { return "line-clamp" }
+
 // Sets the line height
 type LineHeight struct{ PropertyBase }
 
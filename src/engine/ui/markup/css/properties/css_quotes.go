@@ -8,15 +8,58 @@ package properties
 
 import (
 	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+var defaultQuotes = []string{"“", "”", "‘", "’"}
+
+// none|auto|[string string]+
 func (p Quotes) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Quotes not implemented")}
+	if len(values) == 0 {
+		return errors.New("no values for quotes")
+	}
+	if len(values) == 1 && (values[0].Str == "none" || values[0].Str == "auto") {
+		return nil
+	}
+	if len(values)%2 != 0 {
+		return fmt.Errorf("quotes expects pairs of strings but got %d values", len(values))
+	}
+	for i := range values {
+		if !helpers.IsQuotedString(values[i].Str) {
+			return fmt.Errorf("invalid quotes value: %s", values[i].Str)
+		}
+	}
+	return nil
+}
 
-	return problems[0]
+// quoteAtDepth returns the open or close quote for the nesting depth using the
+// inherited quotes property, the innermost pair is repeated for deeper quotes
+func quoteAtDepth(elm *document.Element, depth int, open bool) string {
+	quotes := defaultQuotes
+	if values := elm.InheritedValues("quotes"); len(values) > 0 {
+		switch values[0].Str {
+		case "none":
+			return ""
+		case "auto":
+		default:
+			quotes = make([]string, 0, len(values))
+			for i := range values {
+				quotes = append(quotes, helpers.UnquoteString(values[i].Str))
+			}
+		}
+	}
+	pair := min(depth, len(quotes)/2-1)
+	if pair < 0 {
+		return ""
+	}
+	if open {
+		return quotes[pair*2]
+	}
+	return quotes[pair*2+1]
 }
@@ -11,12 +11,63 @@ import (
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
 )
 
-func (p Rotate) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Rotate not implemented")}
+// rotateValue reads the axis and the angle of the rotate property, the
+// angle can be written before or after the axis
+func rotateValue(values []rules.PropertyValue) (matrix.Vec3, float32, error) {
+	axis := matrix.Vec3{0, 0, 1}
+	if len(values) == 1 {
+		switch values[0].Str {
+		case "none", "initial", "inherit", "unset":
+			return axis, 0, nil
+		}
+	}
+	if len(values) != 1 && len(values) != 2 && len(values) != 4 {
+		return axis, 0, errors.New("rotate expects an angle and an optional axis")
+	}
+	angleAt := len(values) - 1
+	angle, err := helpers.AngleFromStr(values[angleAt].Str)
+	if err != nil {
+		angleAt = 0
+		if angle, err = helpers.AngleFromStr(values[0].Str); err != nil {
+			return axis, 0, err
+		}
+	}
+	rest := make([]string, 0, 3)
+	for i := range values {
+		if i != angleAt {
+			rest = append(rest, values[i].Str)
+		}
+	}
+	switch len(rest) {
+	case 1:
+		var ok bool
+		if axis, ok = map[string]matrix.Vec3{
+			"x": {1, 0, 0}, "y": {0, 1, 0}, "z": {0, 0, 1},
+		}[rest[0]]; !ok {
+			return axis, 0, errors.New("rotate has an invalid axis")
+		}
+	case 3:
+		v, err := transformNumbers(rest)
+		if err != nil {
+			return axis, 0, err
+		}
+		axis = matrix.Vec3{v[0], v[1], v[2]}
+	}
+	return axis, angle, nil
+}
 
-	return problems[0]
+// none|angle|[x|y|z|number{3}] angle|initial|inherit
+func (p Rotate) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	axis, angle, err := rotateValue(values)
+	if err != nil {
+		return err
+	}
+	panel.SetRotate(axis, angle)
+	return nil
 }
@@ -15,8 +15,32 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// none|x [y [z]]|initial|inherit
 func (p Scale) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Scale not implemented")}
-
-	return problems[0]
+	if len(values) == 0 || len(values) > 3 {
+		return errors.New("scale expects 1 to 3 values")
+	}
+	switch values[0].Str {
+	case "none", "initial", "inherit", "unset":
+		panel.SetScale(1, 1, 1)
+		return nil
+	}
+	args := make([]string, len(values))
+	for i := range values {
+		args[i] = values[i].Str
+	}
+	v, err := transformNumbers(args)
+	if err != nil {
+		return err
+	}
+	// A single value scales x and y, z is only scaled when it's given
+	switch len(v) {
+	case 1:
+		panel.SetScale(v[0], v[0], 1)
+	case 2:
+		panel.SetScale(v[0], v[1], 1)
+	default:
+		panel.SetScale(v[0], v[1], v[2])
+	}
+	return nil
 }
@@ -7,16 +7,38 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
 )
 
-func (p ScrollMargin) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("ScrollMargin not implemented")}
+func setScrollMarginSide(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, side ui.Side, host *engine.Host) error {
+	inherited := matrix.Vec4Zero()
+	if p := elm.Parent.Value(); p != nil && p.UIPanel != nil {
+		inherited = p.UIPanel.ScrollMargin()
+	}
+	size, err := scrollSideLength("scroll-margin", values, inherited, side, host.Window)
+	if err != nil {
+		return err
+	}
+	margin := panel.ScrollMargin()
+	margin[side] = size
+	panel.SetScrollMargin(margin)
+	return nil
+}
 
-	return problems[0]
+// length{1,4}|initial|inherit
+func (p ScrollMargin) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	sides, err := scrollShorthandSides(p.Key(), values)
+	if err != nil {
+		return err
+	}
+	for side := range sides {
+		if err := setScrollMarginSide(panel, elm, sides[side:side+1], side, host); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -7,16 +7,14 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// length{1,2}|initial|inherit
 func (p ScrollMarginBlock) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("ScrollMarginBlock not implemented")}
-
-	return problems[0]
+	start, end := blockSides(panel)
+	return processLogicalSides(scrollMarginSideProperties, start, end, panel, elm, values, host)
 }
@@ -7,16 +7,77 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
 )
 
-func (p ScrollPadding) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("ScrollPadding not implemented")}
+// scrollSideLength reads a scroll-margin or scroll-padding length, auto and
+// initial are both 0 for the scroll offsets
+func scrollSideLength(key string, values []rules.PropertyValue, inherited matrix.Vec4, side ui.Side, window helpers.WindowDimensions) (float32, error) {
+	if len(values) != 1 {
+		return 0, fmt.Errorf("%s expects exactly one value", key)
+	}
+	switch values[0].Str {
+	case "auto", "initial":
+		return 0, nil
+	case "inherit":
+		return inherited[side], nil
+	}
+	return helpers.NumFromLength(values[0].Str, window), nil
+}
+
+// scrollShorthandSides maps the 1 to 4 values of a scroll-margin or
+// scroll-padding shorthand (top, right, bottom, left) to the sides
+func scrollShorthandSides(key string, values []rules.PropertyValue) ([4]rules.PropertyValue, error) {
+	var sides [4]rules.PropertyValue
+	var top, right, bottom, left rules.PropertyValue
+	switch len(values) {
+	case 1:
+		top, right, bottom, left = values[0], values[0], values[0], values[0]
+	case 2:
+		top, right, bottom, left = values[0], values[1], values[0], values[1]
+	case 3:
+		top, right, bottom, left = values[0], values[1], values[2], values[1]
+	case 4:
+		top, right, bottom, left = values[0], values[1], values[2], values[3]
+	default:
+		return sides, fmt.Errorf("%s expects 1 to 4 values but got %d", key, len(values))
+	}
+	sides[ui.SideLeft], sides[ui.SideTop], sides[ui.SideRight], sides[ui.SideBottom] = left, top, right, bottom
+	return sides, nil
+}
 
-	return problems[0]
+func setScrollPaddingSide(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, side ui.Side, host *engine.Host) error {
+	inherited := matrix.Vec4Zero()
+	if p := elm.Parent.Value(); p != nil && p.UIPanel != nil {
+		inherited = p.UIPanel.ScrollPadding()
+	}
+	size, err := scrollSideLength("scroll-padding", values, inherited, side, host.Window)
+	if err != nil {
+		return err
+	}
+	padding := panel.ScrollPadding()
+	padding[side] = size
+	panel.SetScrollPadding(padding)
+	return nil
+}
+
+// [auto|length]{1,4}|initial|inherit
+func (p ScrollPadding) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	sides, err := scrollShorthandSides(p.Key(), values)
+	if err != nil {
+		return err
+	}
+	for side := range sides {
+		if err := setScrollPaddingSide(panel, elm, sides[side:side+1], side, host); err != nil {
+			return err
+		}
+	}
+	return nil
 }
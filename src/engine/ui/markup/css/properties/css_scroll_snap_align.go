@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,37 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
-func (p ScrollSnapAlign) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("ScrollSnapAlign not implemented")}
+func scrollSnapAlignValue(key, str string) (ui.ScrollSnapAlign, error) {
+	switch str {
+	case "none", "initial", "inherit":
+		return ui.ScrollSnapAlignNone, nil
+	case "start":
+		return ui.ScrollSnapAlignStart, nil
+	case "center":
+		return ui.ScrollSnapAlignCenter, nil
+	case "end":
+		return ui.ScrollSnapAlignEnd, nil
+	}
+	return ui.ScrollSnapAlignNone, fmt.Errorf("%s expected none, start, center or end, but got: %s", key, str)
+}
 
-	return problems[0]
+// [none|start|end|center]{1,2}|initial|inherit
+func (p ScrollSnapAlign) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) == 0 || len(values) > 2 {
+		return fmt.Errorf("%s expects 1 or 2 values but got %d", p.Key(), len(values))
+	}
+	block, err := scrollSnapAlignValue(p.Key(), values[0].Str)
+	if err != nil {
+		return err
+	}
+	inline, err := scrollSnapAlignValue(p.Key(), values[len(values)-1].Str)
+	if err != nil {
+		return err
+	}
+	if ui.IsVerticalWritingMode(panel.ResolvedWritingMode()) {
+		panel.SetScrollSnapAlign(block, inline)
+	} else {
+		panel.SetScrollSnapAlign(inline, block)
+	}
+	return nil
 }
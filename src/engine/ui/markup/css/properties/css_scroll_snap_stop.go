@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,18 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// normal|always|initial|inherit
 func (p ScrollSnapStop) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("ScrollSnapStop not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return fmt.Errorf("%s expects exactly one value", p.Key())
+	}
+	switch values[0].Str {
+	case "always":
+		panel.SetScrollSnapStop(true)
+	case "normal", "initial", "inherit":
+		panel.SetScrollSnapStop(false)
+	default:
+		return fmt.Errorf("%s expected normal or always, but got: %s", p.Key(), values[0].Str)
+	}
+	return nil
 }
@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,44 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// none|[x|y|block|inline|both] [mandatory|proximity]?|initial|inherit
 func (p ScrollSnapType) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("ScrollSnapType not implemented")}
-
-	return problems[0]
+	if len(values) == 0 || len(values) > 2 {
+		return fmt.Errorf("%s expects 1 or 2 values but got %d", p.Key(), len(values))
+	}
+	vertical := ui.IsVerticalWritingMode(panel.ResolvedWritingMode())
+	axis := ui.ScrollSnapAxisNone
+	switch values[0].Str {
+	case "none", "initial", "inherit":
+	case "x":
+		axis = ui.ScrollSnapAxisX
+	case "y":
+		axis = ui.ScrollSnapAxisY
+	case "both":
+		axis = ui.ScrollSnapAxisBoth
+	case "block":
+		axis = ui.ScrollSnapAxisY
+		if vertical {
+			axis = ui.ScrollSnapAxisX
+		}
+	case "inline":
+		axis = ui.ScrollSnapAxisX
+		if vertical {
+			axis = ui.ScrollSnapAxisY
+		}
+	default:
+		return fmt.Errorf("%s expected a snap axis, but got: %s", p.Key(), values[0].Str)
+	}
+	strictness := ui.ScrollSnapProximity
+	if len(values) == 2 {
+		switch values[1].Str {
+		case "mandatory":
+			strictness = ui.ScrollSnapMandatory
+		case "proximity":
+		default:
+			return fmt.Errorf("%s expected mandatory or proximity, but got: %s", p.Key(), values[1].Str)
+		}
+	}
+	panel.SetScrollSnapType(axis, strictness)
+	return nil
 }
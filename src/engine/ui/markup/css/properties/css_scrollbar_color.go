@@ -7,16 +7,52 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/functions"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
 )
 
-func (p ScrollbarColor) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("ScrollbarColor not implemented")}
+func scrollbarColorValue(panel *ui.Panel, elm *document.Element, value rules.PropertyValue) (matrix.Color, error) {
+	hex := value.Str
+	switch hex {
+	case "rgb":
+		hex, _ = functions.Rgb{}.Process(panel, elm, value)
+	case "rgba":
+		hex, _ = functions.Rgba{}.Process(panel, elm, value)
+	}
+	if newHex, ok := helpers.ColorMap[hex]; ok {
+		hex = newHex
+	}
+	return matrix.ColorFromHexString(hex)
+}
 
-	return problems[0]
+// auto|<thumb color> <track color>|initial|inherit
+func (p ScrollbarColor) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	switch len(values) {
+	case 1:
+		switch values[0].Str {
+		case "auto", "initial", "inherit":
+			panel.ResetScrollbarColor()
+			return nil
+		}
+		return fmt.Errorf("%s expected auto or 2 colors, but got: %s", p.Key(), values[0].Str)
+	case 2:
+		thumb, err := scrollbarColorValue(panel, elm, values[0])
+		if err != nil {
+			return err
+		}
+		track, err := scrollbarColorValue(panel, elm, values[1])
+		if err != nil {
+			return err
+		}
+		panel.SetScrollbarColor(thumb, track)
+		return nil
+	}
+	return fmt.Errorf("%s expects 1 or 2 values but got %d", p.Key(), len(values))
 }
@@ -7,16 +7,53 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
+	"strconv"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
+// number|length|initial|inherit
 func (p TabSize) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("TabSize not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	str := values[0].Str
+	switch str {
+	case "initial", "unset":
+		updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.TabSize = 0 })
+		return nil
+	case "inherit":
+		size := parentTextLayout(elm).TabSize
+		updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.TabSize = size })
+		return nil
+	}
+	if spaces, err := strconv.ParseFloat(str, 32); err == nil {
+		if spaces < 0 {
+			return fmt.Errorf("tab-size can not be negative, got %s", str)
+		}
+		updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.TabSize = float32(spaces) })
+		return nil
+	}
+	// The layout measures tabs in spaces, so lengths are converted using the
+	// width of a space in each label's font
+	for _, label := range childLabels(elm) {
+		emSize := host.FontCache().EMSize(label.FontFace())
+		width := helpers.NumFromLengthWithFont(str, host.Window, emSize)
+		if width < 0 {
+			return fmt.Errorf("tab-size can not be negative, got %s", str)
+		}
+		space := host.FontCache().MeasureString(label.FontFace(), " ", label.FontSize())
+		layout := label.TextLayout()
+		if space > 0 {
+			layout.TabSize = width / space
+		}
+		label.SetTextLayout(layout)
+	}
+	return nil
 }
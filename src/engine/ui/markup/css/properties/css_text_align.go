@@ -16,7 +16,7 @@ import (
 	"kaijuengine.com/rendering"
 )
 
-// left|right|center|justify|initial|inherit
+// left|right|center|justify|start|end|initial|inherit
 func (p TextAlign) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
 	if len(values) != 1 {
 		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
@@ -40,12 +40,16 @@ func (p TextAlign) Process(panel *ui.Panel, elm *document.Element, values []rule
 		for _, l := range labels {
 			l.SetJustify(rendering.FontJustifyJustify)
 		}
-	case "initial":
+	case "start", "initial":
 		for _, l := range labels {
-			l.SetJustify(rendering.FontJustifyLeft)
+			l.SetJustify(rendering.FontJustifyStart)
+		}
+	case "end":
+		for _, l := range labels {
+			l.SetJustify(rendering.FontJustifyEnd)
 		}
 	case "inherit":
-		inherited := rendering.FontJustifyLeft
+		inherited := rendering.FontJustifyStart
 		if parent := elm.Parent.Value(); parent != nil {
 			if parentLabels := childLabels(parent); len(parentLabels) > 0 {
 				inherited = parentLabels[0].Justify()
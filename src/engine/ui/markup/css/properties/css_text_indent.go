@@ -7,16 +7,47 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
+	"strings"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
+// length|percentage|initial|inherit
 func (p TextIndent) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("TextIndent not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	str := values[0].Str
+	indent := float32(0)
+	switch str {
+	case "initial", "unset":
+	case "inherit":
+		indent = parentTextLayout(elm).Indent
+	case "each-line", "hanging":
+		return fmt.Errorf("text-indent does not currently support %s", str)
+	default:
+		labels := childLabels(elm)
+		emSize := float32(16)
+		if len(labels) > 0 {
+			emSize = host.FontCache().EMSize(labels[0].FontFace())
+		}
+		indent = helpers.NumFromLengthWithFont(str, host.Window, emSize)
+		if strings.HasSuffix(str, "%") {
+			// Percentages are of the width of the containing block
+			width := float32(0)
+			if panel != nil {
+				l := panel.Base().Layout()
+				width = l.PixelSize().X() - l.Padding().Horizontal() - l.Border().Horizontal()
+			}
+			indent *= width
+		}
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.Indent = indent })
+	return nil
 }
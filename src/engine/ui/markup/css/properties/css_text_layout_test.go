@@ -0,0 +1,61 @@
+/******************************************************************************/
+/* css_text_layout_test.go                                                    */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package properties
+
+import (
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
+)
+
+func TestParseTextOverflow(t *testing.T) {
+	overflow, ellipsis, err := parseTextOverflow([]rules.PropertyValue{{Str: "ellipsis"}})
+	if err != nil || overflow != rendering.TextOverflowEllipsis || ellipsis != "" {
+		t.Errorf("unexpected ellipsis result %v %q %v", overflow, ellipsis, err)
+	}
+	overflow, ellipsis, err = parseTextOverflow([]rules.PropertyValue{{Str: "clip"}, {Str: `"~"`}})
+	if err != nil || overflow != rendering.TextOverflowEllipsis || ellipsis != "~" {
+		t.Errorf("expected the end value to be the custom string, got %v %q %v", overflow, ellipsis, err)
+	}
+	if _, _, err = parseTextOverflow([]rules.PropertyValue{{Str: "fade"}}); err == nil {
+		t.Error("expected an error for an unsupported value")
+	}
+}
+
+func TestTextLayoutKeyword(t *testing.T) {
+	elm := &document.Element{}
+	get := func(l rendering.TextLayout) rendering.TextWhiteSpace { return l.WhiteSpace }
+	mode, err := textLayoutKeyword(elm, []rules.PropertyValue{{Str: "pre-line"}}, whiteSpaceKeywords, get)
+	if err != nil || mode != rendering.TextWhiteSpacePreLine {
+		t.Errorf("expected pre-line but got %v %v", mode, err)
+	}
+	mode, err = textLayoutKeyword(elm, []rules.PropertyValue{{Str: "inherit"}}, whiteSpaceKeywords, get)
+	if err != nil || mode != rendering.TextWhiteSpacePreWrap {
+		t.Errorf("expected inherit without a parent to use the default but got %v %v", mode, err)
+	}
+	if _, err = textLayoutKeyword(elm, []rules.PropertyValue{{Str: "wrap"}}, whiteSpaceKeywords, get); err == nil {
+		t.Error("expected an error for an unknown keyword")
+	}
+	if _, err = textLayoutKeyword(elm, nil, whiteSpaceKeywords, get); err == nil {
+		t.Error("expected an error when no value is given")
+	}
+}
+
+func TestUnicodeBidiKeyword(t *testing.T) {
+	elm := &document.Element{}
+	get := func(l rendering.TextLayout) rendering.TextBidi { return l.Bidi }
+	mode, err := textLayoutKeyword(elm, []rules.PropertyValue{{Str: "isolate-override"}}, unicodeBidiKeywords, get)
+	if err != nil || mode != rendering.TextBidiOverride {
+		t.Errorf("expected isolate-override to override but got %v %v", mode, err)
+	}
+	mode, err = textLayoutKeyword(elm, []rules.PropertyValue{{Str: "plaintext"}}, unicodeBidiKeywords, get)
+	if err != nil || mode != rendering.TextBidiPlaintext {
+		t.Errorf("expected plaintext but got %v %v", mode, err)
+	}
+}
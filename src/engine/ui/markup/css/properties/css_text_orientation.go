@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,24 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
-func (p TextOrientation) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("TextOrientation not implemented")}
+var textOrientationKeywords = map[string]ui.TextOrientation{
+	"mixed":    ui.TextOrientationMixed,
+	"upright":  ui.TextOrientationUpright,
+	"sideways": ui.TextOrientationSideways,
+	"initial":  ui.TextOrientationMixed,
+	"unset":    ui.TextOrientationInherit,
+	"inherit":  ui.TextOrientationInherit,
+}
 
-	return problems[0]
+// mixed|upright|sideways|initial|inherit
+func (p TextOrientation) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	orientation, ok := textOrientationKeywords[values[0].Str]
+	if !ok {
+		return fmt.Errorf("unsupported value '%s'", values[0].Str)
+	}
+	panel.SetTextOrientation(orientation)
+	return nil
 }
@@ -7,16 +7,51 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p TextOverflow) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("TextOverflow not implemented")}
+// parseTextOverflow reads the overflow of the end of the line, when two
+// values are given the first is for the start of the line which is always
+// clipped as the text is laid out from the start
+func parseTextOverflow(values []rules.PropertyValue) (rendering.TextOverflow, string, error) {
+	if len(values) != 1 && len(values) != 2 {
+		return rendering.TextOverflowClip, "", fmt.Errorf("expected 1 or 2 values but got %d", len(values))
+	}
+	str := values[len(values)-1].Str
+	switch str {
+	case "clip", "initial", "unset":
+		return rendering.TextOverflowClip, "", nil
+	case "ellipsis":
+		return rendering.TextOverflowEllipsis, "", nil
+	}
+	if len(str) >= 2 && (str[0] == '"' || str[0] == '\'') && str[len(str)-1] == str[0] {
+		return rendering.TextOverflowEllipsis, str[1 : len(str)-1], nil
+	}
+	return rendering.TextOverflowClip, "", fmt.Errorf("unsupported value '%s'", str)
+}
 
-	return problems[0]
+// clip|ellipsis|string|initial|inherit
+func (p TextOverflow) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	var overflow rendering.TextOverflow
+	var ellipsis string
+	if len(values) == 1 && values[0].Str == "inherit" {
+		inherited := parentTextLayout(elm)
+		overflow, ellipsis = inherited.Overflow, inherited.Ellipsis
+	} else {
+		var err error
+		if overflow, ellipsis, err = parseTextOverflow(values); err != nil {
+			return err
+		}
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) {
+		l.Overflow = overflow
+		l.Ellipsis = ellipsis
+	})
+	return nil
 }
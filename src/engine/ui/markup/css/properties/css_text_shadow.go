@@ -11,12 +11,59 @@ import (
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
 )
 
+func setChildTextShadows(elm *document.Element, shadows []ui.TextShadow) {
+	for _, c := range elm.Children {
+		if c.IsText() {
+			c.UI.ToLabel().SetTextShadows(shadows)
+		}
+		setChildTextShadows(c, shadows)
+	}
+}
+
+// none|[<length>{2,3} && <color>?]#|initial|inherit
 func (p TextShadow) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("TextShadow not implemented")}
+	if len(values) == 1 && values[0].Str == "inherit" {
+		return nil
+	}
+	shadows, err := parseTextShadows(values, host.Window)
+	if err != nil {
+		return err
+	}
+	setChildTextShadows(elm, shadows)
+	return nil
+}
 
-	return problems[0]
+func parseTextShadows(values []rules.PropertyValue, window helpers.WindowDimensions) ([]ui.TextShadow, error) {
+	if len(values) == 0 {
+		return nil, errors.New("TextShadow requires a value")
+	}
+	if isShadowNone(values) {
+		return nil, nil
+	}
+	layers := splitCommaValues(values)
+	shadows := make([]ui.TextShadow, 0, len(layers))
+	for _, layer := range layers {
+		lengths, color, _, err := parseShadowLayer(layer, window, 3, false)
+		if err != nil {
+			return nil, err
+		}
+		s := ui.TextShadow{
+			Offset: matrix.Vec2{lengths[0], lengths[1]},
+			Color:  color,
+		}
+		if len(lengths) > 2 {
+			if lengths[2] < 0 {
+				return nil, errors.New("text-shadow blur radius can not be negative")
+			}
+			s.Blur = lengths[2]
+		}
+		shadows = append(shadows, s)
+	}
+	return shadows, nil
 }
@@ -8,6 +8,8 @@ package properties
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"kaijuengine.com/engine"
@@ -18,79 +20,237 @@ import (
 	"kaijuengine.com/matrix"
 )
 
-func translateXYZ(str string, panel *ui.Panel, host *engine.Host, vc matrix.VectorComponent) {
-	if vc == matrix.Vz {
-		p := panel.Base().Entity().Transform.Position()
-		p[vc] += helpers.NumFromLength(str, host.Window)
-		panel.Base().Entity().Transform.SetPosition(p)
-	} else {
-		offset := panel.Base().Layout().InnerOffset()
-		p := helpers.NumFromLength(str, host.Window)
-		if vc == matrix.Vy {
-			p *= -1.0
-		}
-		if strings.HasSuffix(str, "%") {
-			l := panel.Base().Layout()
-			localInnerOffset := l.LocalInnerOffset()
-			localInnerOffset[vc] = l.PixelSize()[vc] * p
-			l.SetLocalInnerOffset(localInnerOffset.X(), localInnerOffset.Y(), localInnerOffset.Z(), localInnerOffset.W())
-		} else {
-			offset[vc] += p
-			panel.Base().Layout().SetInnerOffset(offset.X(), offset.Y(), offset.Z(), offset.W())
+// transformLength reads a length of a transform, percentages are kept as a
+// fraction of the size of the panel as it isn't known until the layout
+func transformLength(str string, window helpers.WindowDimensions) ui.TransformLength {
+	return ui.TransformLength{
+		Value:   helpers.NumFromLength(str, window),
+		Percent: strings.HasSuffix(str, "%"),
+	}
+}
+
+// transformNumber reads a plain number, or a percentage as a fraction
+func transformNumber(str string) (float32, error) {
+	scale := 1.0
+	if strings.HasSuffix(str, "%") {
+		str, scale = strings.TrimSuffix(str, "%"), 0.01
+	}
+	v, err := strconv.ParseFloat(str, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %s", str)
+	}
+	return float32(v * scale), nil
+}
+
+func transformNumbers(args []string) ([]float32, error) {
+	out := make([]float32, len(args))
+	for i := range args {
+		v, err := transformNumber(args[i])
+		if err != nil {
+			return nil, err
 		}
+		out[i] = v
 	}
+	return out, nil
 }
 
-// none|transform-functions|initial|inherit
-func (p Transform) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	if len(values) != 1 {
-		return errors.New("transform expects 1 value")
+func expectArgs(fn rules.PropertyValue, counts ...int) error {
+	for _, c := range counts {
+		if len(fn.Args) == c {
+			return nil
+		}
 	}
+	return fmt.Errorf("%s expects %v values but got %d", fn.Str, counts, len(fn.Args))
+}
 
-	switch values[0].Str {
-	case "none":
-	case "initial":
-	case "inherit":
-	case "matrix":
-	case "matrix3d":
-	case "translate":
-		if len(values[0].Args) == 2 {
-			translateXYZ(values[0].Args[0], panel, host, matrix.Vx)
-			translateXYZ(values[0].Args[1], panel, host, matrix.Vy)
+// parseTransformFunction converts one function of the transform property
+func parseTransformFunction(fn rules.PropertyValue, window helpers.WindowDimensions) (ui.TransformOp, error) {
+	length := func(i int) ui.TransformLength { return transformLength(fn.Args[i], window) }
+	none := ui.TransformLength{}
+	switch fn.Str {
+	case "matrix", "matrix3d":
+		if err := expectArgs(fn, map[string]int{"matrix": 6, "matrix3d": 16}[fn.Str]); err != nil {
+			return ui.TransformOp{}, err
+		}
+		v, err := transformNumbers(fn.Args)
+		if err != nil {
+			return ui.TransformOp{}, err
+		}
+		m := matrix.Mat4Identity()
+		if fn.Str == "matrix3d" {
+			copy(m[:], v)
 		} else {
-			return errors.New("translate expects 2 values")
+			m[0], m[1], m[4], m[5], m[12], m[13] = v[0], v[1], v[2], v[3], v[4], v[5]
 		}
+		return ui.MatrixOp(m), nil
+	case "translate":
+		if err := expectArgs(fn, 1, 2); err != nil {
+			return ui.TransformOp{}, err
+		}
+		y := none
+		if len(fn.Args) == 2 {
+			y = length(1)
+		}
+		return ui.TranslateOp(length(0), y, 0), nil
 	case "translate3d":
-		if len(values[0].Args) == 3 {
-			translateXYZ(values[0].Args[0], panel, host, matrix.Vx)
-			translateXYZ(values[0].Args[1], panel, host, matrix.Vy)
-			translateXYZ(values[0].Args[2], panel, host, matrix.Vz)
-		} else {
-			return errors.New("translate3d expects 3 values")
-		}
-	case "translateX":
-		translateXYZ(values[0].Args[0], panel, host, matrix.Vx)
-	case "translateY":
-		translateXYZ(values[0].Args[0], panel, host, matrix.Vy)
-	case "translateZ":
-		translateXYZ(values[0].Args[0], panel, host, matrix.Vz)
-	case "scale":
-	case "scale3d":
-	case "scaleX":
-	case "scaleY":
-	case "scaleZ":
-	case "rotate":
+		if err := expectArgs(fn, 3); err != nil {
+			return ui.TransformOp{}, err
+		}
+		return ui.TranslateOp(length(0), length(1), helpers.NumFromLength(fn.Args[2], window)), nil
+	case "translateX", "translateY", "translateZ":
+		if err := expectArgs(fn, 1); err != nil {
+			return ui.TransformOp{}, err
+		}
+		switch fn.Str {
+		case "translateX":
+			return ui.TranslateOp(length(0), none, 0), nil
+		case "translateY":
+			return ui.TranslateOp(none, length(0), 0), nil
+		default:
+			return ui.TranslateOp(none, none, helpers.NumFromLength(fn.Args[0], window)), nil
+		}
+	case "scale", "scale3d", "scaleX", "scaleY", "scaleZ":
+		v, err := transformNumbers(fn.Args)
+		if err != nil {
+			return ui.TransformOp{}, err
+		}
+		switch {
+		case fn.Str == "scale" && len(v) == 1:
+			return ui.ScaleOp(v[0], v[0], 1), nil
+		case fn.Str == "scale" && len(v) == 2:
+			return ui.ScaleOp(v[0], v[1], 1), nil
+		case fn.Str == "scale3d" && len(v) == 3:
+			return ui.ScaleOp(v[0], v[1], v[2]), nil
+		case fn.Str == "scaleX" && len(v) == 1:
+			return ui.ScaleOp(v[0], 1, 1), nil
+		case fn.Str == "scaleY" && len(v) == 1:
+			return ui.ScaleOp(1, v[0], 1), nil
+		case fn.Str == "scaleZ" && len(v) == 1:
+			return ui.ScaleOp(1, 1, v[0]), nil
+		}
+		return ui.TransformOp{}, fmt.Errorf("%s has the wrong number of values", fn.Str)
+	case "rotate", "rotateX", "rotateY", "rotateZ":
+		if err := expectArgs(fn, 1); err != nil {
+			return ui.TransformOp{}, err
+		}
+		a, err := helpers.AngleFromStr(fn.Args[0])
+		if err != nil {
+			return ui.TransformOp{}, err
+		}
+		axis := map[string]matrix.Vec3{
+			"rotateX": {1, 0, 0},
+			"rotateY": {0, 1, 0},
+		}[fn.Str]
+		if axis == (matrix.Vec3{}) {
+			axis = matrix.Vec3{0, 0, 1}
+		}
+		return ui.RotateOp(axis, a), nil
 	case "rotate3d":
-	case "rotateX":
-	case "rotateY":
-	case "rotateZ":
-	case "skew":
-	case "skewX":
-	case "skewY":
+		if err := expectArgs(fn, 4); err != nil {
+			return ui.TransformOp{}, err
+		}
+		v, err := transformNumbers(fn.Args[:3])
+		if err != nil {
+			return ui.TransformOp{}, err
+		}
+		a, err := helpers.AngleFromStr(fn.Args[3])
+		if err != nil {
+			return ui.TransformOp{}, err
+		}
+		return ui.RotateOp(matrix.Vec3{v[0], v[1], v[2]}, a), nil
+	case "skew", "skewX", "skewY":
+		if err := expectArgs(fn, 1, 2); err != nil || (fn.Str != "skew" && len(fn.Args) != 1) {
+			return ui.TransformOp{}, fmt.Errorf("%s has the wrong number of values", fn.Str)
+		}
+		a := make([]float32, 2)
+		for i := range fn.Args {
+			v, err := helpers.AngleFromStr(fn.Args[i])
+			if err != nil {
+				return ui.TransformOp{}, err
+			}
+			a[i] = v
+		}
+		if fn.Str == "skewY" {
+			a[0], a[1] = 0, a[0]
+		}
+		return ui.SkewOp(a[0], a[1]), nil
 	case "perspective":
-	default:
-		return errors.New("transform has unexpected value")
+		if err := expectArgs(fn, 1); err != nil {
+			return ui.TransformOp{}, err
+		}
+		if fn.Args[0] == "none" {
+			return ui.PerspectiveOp(0), nil
+		}
+		return ui.PerspectiveOp(helpers.NumFromLength(fn.Args[0], window)), nil
 	}
+	return ui.TransformOp{}, fmt.Errorf("transform has unexpected value %s", fn.Str)
+}
 
+func parseTransformFunctions(values []rules.PropertyValue, window helpers.WindowDimensions) ([]ui.TransformOp, error) {
+	if len(values) == 1 {
+		switch values[0].Str {
+		case "none", "initial", "inherit", "unset":
+			return nil, nil
+		}
+	}
+	ops := make([]ui.TransformOp, 0, len(values))
+	for i := range values {
+		op, err := parseTransformFunction(values[i], window)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// none|transform-functions|initial|inherit
+func (p Transform) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) == 0 {
+		return errors.New("transform expects at least 1 value")
+	}
+	ops, err := parseTransformFunctions(values, host.Window)
+	if err != nil {
+		return err
+	}
+	panel.SetTransformFunctions(ops)
 	return nil
 }
+
+// transformOriginValues reads the x, y and z of transform-origin and
+// perspective-origin, keywords can be given in either order
+func transformOriginValues(values []rules.PropertyValue, window helpers.WindowDimensions) (x, y ui.TransformLength, z float32, err error) {
+	x, y = ui.TransformLength{Value: 0.5, Percent: true}, ui.TransformLength{Value: 0.5, Percent: true}
+	if len(values) == 0 || len(values) > 3 {
+		return x, y, 0, errors.New("expected 1 to 3 values for the origin")
+	}
+	keyword := func(str string) (ui.TransformLength, bool) {
+		l, ok := map[string]float32{"left": 0, "top": 0, "center": 0.5, "right": 1, "bottom": 1}[str]
+		return ui.TransformLength{Value: l, Percent: true}, ok
+	}
+	first, second := values[0].Str, ""
+	if len(values) > 1 {
+		second = values[1].Str
+	}
+	if first == "top" || first == "bottom" || second == "left" || second == "right" {
+		first, second = second, first
+	}
+	if first != "" {
+		if l, ok := keyword(first); ok {
+			x = l
+		} else {
+			x = transformLength(first, window)
+		}
+	}
+	if second != "" {
+		if l, ok := keyword(second); ok {
+			y = l
+		} else {
+			y = transformLength(second, window)
+		}
+	}
+	if len(values) == 3 {
+		z = helpers.NumFromLength(values[2].Str, window)
+	}
+	return x, y, z, nil
+}
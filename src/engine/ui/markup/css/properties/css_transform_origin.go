@@ -7,16 +7,21 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// x-offset|offset-keyword [y-offset [z-offset]]|initial|inherit
 func (p TransformOrigin) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("TransformOrigin not implemented")}
-
-	return problems[0]
+	if len(values) == 1 && (values[0].Str == "initial" || values[0].Str == "inherit") {
+		values = nil
+	}
+	x, y, z, err := transformOriginValues(values, host.Window)
+	if values != nil && err != nil {
+		return err
+	}
+	panel.SetTransformOrigin(x, y, z)
+	return nil
 }
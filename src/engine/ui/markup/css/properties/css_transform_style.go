@@ -15,8 +15,18 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// flat|preserve-3d|initial|inherit
 func (p TransformStyle) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("TransformStyle not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return errors.New("transform-style expects 1 value")
+	}
+	switch values[0].Str {
+	case "flat", "initial", "inherit", "unset":
+		panel.SetTransformPreserve3D(false)
+	case "preserve-3d":
+		panel.SetTransformPreserve3D(true)
+	default:
+		return errors.New("transform-style has unexpected value")
+	}
+	return nil
 }
@@ -0,0 +1,120 @@
+/******************************************************************************/
+/* css_transform_test.go                                                      */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package properties
+
+import (
+	"testing"
+
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/matrix"
+)
+
+func TestParseTransformFunctions(t *testing.T) {
+	values := []rules.PropertyValue{
+		{Str: "translate", Args: []string{"-50%", "10px"}},
+		{Str: "rotateY", Args: []string{"0.25turn"}},
+		{Str: "scale", Args: []string{"2"}},
+		{Str: "skewX", Args: []string{"10deg"}},
+		{Str: "perspective", Args: []string{"500px"}},
+	}
+	ops, err := parseTransformFunctions(values, testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ui.TransformOp{
+		{Kind: ui.TransformOpTranslate, Values: matrix.Vec4{-0.5, 10, 0, 0}, Percent: [2]bool{true, false}},
+		ui.RotateOp(matrix.Vec3{0, 1, 0}, 90),
+		ui.ScaleOp(2, 2, 1),
+		ui.SkewOp(10, 0),
+		ui.PerspectiveOp(500),
+	}
+	if len(ops) != len(want) {
+		t.Fatalf("expected %d functions, got %d", len(want), len(ops))
+	}
+	for i := range want {
+		if ops[i].Kind != want[i].Kind || ops[i].Percent != want[i].Percent ||
+			!matrix.Vec4ApproxTo(ops[i].Values, want[i].Values, 0.001) {
+			t.Errorf("function %d: expected %+v, got %+v", i, want[i], ops[i])
+		}
+	}
+}
+
+func TestParseTransformMatrix(t *testing.T) {
+	ops, err := parseTransformFunctions([]rules.PropertyValue{
+		{Str: "matrix", Args: []string{"1", "2", "3", "4", "5", "6"}},
+	}, testWindow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := ops[0].Matrix
+	if m[0] != 1 || m[1] != 2 || m[4] != 3 || m[5] != 4 || m[12] != 5 || m[13] != 6 || m[10] != 1 {
+		t.Fatalf("unexpected matrix %v", m)
+	}
+	if _, err := parseTransformFunctions([]rules.PropertyValue{
+		{Str: "matrix", Args: []string{"1", "2"}},
+	}, testWindow{}); err == nil {
+		t.Fatal("expected a matrix with 2 values to be invalid")
+	}
+}
+
+func TestRotateValue(t *testing.T) {
+	tests := []struct {
+		values []string
+		axis   matrix.Vec3
+		angle  float32
+	}{
+		{[]string{"45deg"}, matrix.Vec3{0, 0, 1}, 45},
+		{[]string{"x", "90deg"}, matrix.Vec3{1, 0, 0}, 90},
+		{[]string{"90deg", "y"}, matrix.Vec3{0, 1, 0}, 90},
+		{[]string{"1", "1", "0", "0.5turn"}, matrix.Vec3{1, 1, 0}, 180},
+		{[]string{"none"}, matrix.Vec3{0, 0, 1}, 0},
+	}
+	for _, test := range tests {
+		values := make([]rules.PropertyValue, len(test.values))
+		for i := range test.values {
+			values[i].Str = test.values[i]
+		}
+		axis, angle, err := rotateValue(values)
+		if err != nil {
+			t.Fatalf("%v: %v", test.values, err)
+		}
+		if axis != test.axis || !matrix.ApproxTo(angle, test.angle, 0.001) {
+			t.Errorf("%v: expected %v %v, got %v %v", test.values, test.axis, test.angle, axis, angle)
+		}
+	}
+	if _, _, err := rotateValue([]rules.PropertyValue{{Str: "w"}, {Str: "10deg"}}); err == nil {
+		t.Error("expected an unknown axis to be invalid")
+	}
+}
+
+func TestTransformOriginValues(t *testing.T) {
+	pct := func(v float32) ui.TransformLength { return ui.TransformLength{Value: v, Percent: true} }
+	tests := []struct {
+		values []string
+		x, y   ui.TransformLength
+		z      float32
+	}{
+		{[]string{"left"}, pct(0), pct(0.5), 0},
+		{[]string{"top"}, pct(0.5), pct(0), 0},
+		{[]string{"bottom", "right"}, pct(1), pct(1), 0},
+		{[]string{"10px", "25%", "4px"}, ui.TransformLength{Value: 10}, pct(0.25), 4},
+	}
+	for _, test := range tests {
+		values := make([]rules.PropertyValue, len(test.values))
+		for i := range test.values {
+			values[i].Str = test.values[i]
+		}
+		x, y, z, err := transformOriginValues(values, testWindow{})
+		if err != nil {
+			t.Fatalf("%v: %v", test.values, err)
+		}
+		if x != test.x || y != test.y || z != test.z {
+			t.Errorf("%v: expected %v %v %v, got %v %v %v", test.values, test.x, test.y, test.z, x, y, z)
+		}
+	}
+}
@@ -11,12 +11,31 @@ import (
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// none|x [y [z]]|initial|inherit
 func (p Translate) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("Translate not implemented")}
-
-	return problems[0]
+	var x, y ui.TransformLength
+	var z float32
+	switch len(values) {
+	case 1:
+		switch values[0].Str {
+		case "none", "initial", "inherit", "unset":
+			panel.SetTranslate(x, y, z)
+			return nil
+		}
+	case 2, 3:
+		y = transformLength(values[1].Str, host.Window)
+		if len(values) == 3 {
+			z = helpers.NumFromLength(values[2].Str, host.Window)
+		}
+	default:
+		return errors.New("translate expects 1 to 3 values")
+	}
+	x = transformLength(values[0].Str, host.Window)
+	panel.SetTranslate(x, y, z)
+	return nil
 }
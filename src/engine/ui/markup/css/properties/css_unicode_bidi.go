@@ -7,16 +7,33 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p UnicodeBidi) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("UnicodeBidi not implemented")}
+// Each text element is laid out as its own paragraph, so embed and isolate
+// have nothing to separate it from and are the same as normal
+var unicodeBidiKeywords = map[string]rendering.TextBidi{
+	"normal":           rendering.TextBidiNormal,
+	"embed":            rendering.TextBidiNormal,
+	"isolate":          rendering.TextBidiNormal,
+	"bidi-override":    rendering.TextBidiOverride,
+	"isolate-override": rendering.TextBidiOverride,
+	"plaintext":        rendering.TextBidiPlaintext,
+	"initial":          rendering.TextBidiNormal,
+	"unset":            rendering.TextBidiNormal,
+}
 
-	return problems[0]
+// normal|embed|isolate|bidi-override|isolate-override|plaintext|initial|inherit
+func (p UnicodeBidi) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	mode, err := textLayoutKeyword(elm, values, unicodeBidiKeywords,
+		func(l rendering.TextLayout) rendering.TextBidi { return l.Bidi })
+	if err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.Bidi = mode })
+	return nil
 }
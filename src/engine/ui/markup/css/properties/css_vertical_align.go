@@ -98,6 +98,11 @@ func (p VerticalAlign) Process(panel *ui.Panel, elm *document.Element, values []
 	if err != nil {
 		return err
 	}
+	if panel.TableRole() == ui.TableRoleCell {
+		// Cells place all of their content within the height of the row
+		panel.SetTableCellVerticalAlign(align)
+		return nil
+	}
 	labels := directChildLabels(elm)
 	for _, l := range labels {
 		setLabelVerticalAlign(l, align, shifted, values[0].Str)
@@ -7,16 +7,53 @@
 package properties
 
 import (
-	"errors"
+	"strings"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p WhiteSpace) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("WhiteSpace not implemented")}
+var whiteSpaceKeywords = map[string]rendering.TextWhiteSpace{
+	"normal":       rendering.TextWhiteSpaceNormal,
+	"initial":      rendering.TextWhiteSpaceNormal,
+	"unset":        rendering.TextWhiteSpaceNormal,
+	"nowrap":       rendering.TextWhiteSpaceNoWrap,
+	"pre":          rendering.TextWhiteSpacePre,
+	"pre-wrap":     rendering.TextWhiteSpacePreWrap,
+	"pre-line":     rendering.TextWhiteSpacePreLine,
+	"break-spaces": rendering.TextWhiteSpaceBreakSpaces,
+}
+
+func whiteSpacePreserves(mode rendering.TextWhiteSpace) bool {
+	return mode != rendering.TextWhiteSpaceNormal && mode != rendering.TextWhiteSpaceNoWrap
+}
 
-	return problems[0]
+// setChildTextSource restores the text as written in the document, the
+// parser collapses it when creating the labels, so modes that preserve the
+// white space need the original back
+func setChildTextSource(elm *document.Element) {
+	for _, c := range elm.Children {
+		if c.IsText() {
+			c.UI.ToLabel().SetText(strings.ReplaceAll(c.Data, "\r", ""))
+		} else {
+			setChildTextSource(c)
+		}
+	}
+}
+
+// normal|nowrap|pre|pre-wrap|pre-line|break-spaces|initial|inherit
+func (p WhiteSpace) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	mode, err := textLayoutKeyword(elm, values, whiteSpaceKeywords,
+		func(l rendering.TextLayout) rendering.TextWhiteSpace { return l.WhiteSpace })
+	if err != nil {
+		return err
+	}
+	if whiteSpacePreserves(mode) {
+		setChildTextSource(elm)
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.WhiteSpace = mode })
+	return nil
 }
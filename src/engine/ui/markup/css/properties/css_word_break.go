@@ -7,16 +7,35 @@
 package properties
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
-func (p WordBreak) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("WordBreak not implemented")}
+var wordBreakKeywords = map[string]rendering.TextWordBreak{
+	"normal":    rendering.TextWordBreakNormal,
+	"initial":   rendering.TextWordBreakNormal,
+	"unset":     rendering.TextWordBreakNormal,
+	"break-all": rendering.TextWordBreakBreakAll,
+	"keep-all":  rendering.TextWordBreakKeepAll,
+	// Deprecated, this is the same as normal with overflow-wrap: anywhere
+	"break-word": rendering.TextWordBreakNormal,
+}
 
-	return problems[0]
+// normal|break-all|keep-all|break-word|initial|inherit
+func (p WordBreak) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	mode, err := textLayoutKeyword(elm, values, wordBreakKeywords,
+		func(l rendering.TextLayout) rendering.TextWordBreak { return l.WordBreak })
+	if err != nil {
+		return err
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) {
+		l.WordBreak = mode
+		if values[0].Str == "break-word" {
+			l.OverflowWrap = rendering.TextOverflowWrapAnywhere
+		}
+	})
+	return nil
 }
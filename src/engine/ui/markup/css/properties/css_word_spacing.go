@@ -7,16 +7,34 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
+// normal|length|initial|inherit
 func (p WordSpacing) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("WordSpacing not implemented")}
-
-	return problems[0]
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	spacing := float32(0)
+	switch values[0].Str {
+	case "normal", "initial", "unset":
+	case "inherit":
+		spacing = parentTextLayout(elm).WordSpacing
+	default:
+		labels := childLabels(elm)
+		emSize := float32(16)
+		if len(labels) > 0 {
+			emSize = host.FontCache().EMSize(labels[0].FontFace())
+		}
+		spacing = helpers.NumFromLengthWithFont(values[0].Str, host.Window, emSize)
+	}
+	updateChildTextLayouts(elm, func(l *rendering.TextLayout) { l.WordSpacing = spacing })
+	return nil
 }
@@ -13,6 +13,7 @@ import (
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/rendering"
 )
 
 func setChildTextWordWrap(elm *document.Element, wrap bool) {
@@ -46,8 +47,10 @@ func (p WordWrap) Process(panel *ui.Panel, elm *document.Element, values []rules
 	case "inherit":
 	case "initial":
 	case "break-word":
-		// TODO:  Implement word breaking in labels
-		fallthrough
+		setChildTextWordWrap(elm, true)
+		updateChildTextLayouts(elm, func(l *rendering.TextLayout) {
+			l.OverflowWrap = rendering.TextOverflowWrapBreakWord
+		})
 	default:
 		return errors.New("WordWrap does not currently support " + values[0].Str)
 	}
@@ -7,7 +7,7 @@
 package properties
 
 import (
-	"errors"
+	"fmt"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/ui"
@@ -15,8 +15,31 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
-func (p WritingMode) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
-	problems := []error{errors.New("WritingMode not implemented")}
+// The sideways modes are drawn the same as their vertical counterparts as
+// only upright glyphs are supported
+var writingModeKeywords = map[string]ui.WritingMode{
+	"horizontal-tb": ui.WritingModeHorizontalTB,
+	"vertical-rl":   ui.WritingModeVerticalRL,
+	"vertical-lr":   ui.WritingModeVerticalLR,
+	"sideways-rl":   ui.WritingModeVerticalRL,
+	"sideways-lr":   ui.WritingModeVerticalLR,
+	"initial":       ui.WritingModeHorizontalTB,
+	"unset":         ui.WritingModeInherit,
+	"inherit":       ui.WritingModeInherit,
+}
 
-	return problems[0]
+// The logical properties read the writing mode, so it is set before them
+func (p WritingMode) Sort() int { return -1 }
+
+// horizontal-tb|vertical-rl|vertical-lr|sideways-rl|sideways-lr|initial|inherit
+func (p WritingMode) Process(panel *ui.Panel, elm *document.Element, values []rules.PropertyValue, host *engine.Host) error {
+	if len(values) != 1 {
+		return fmt.Errorf("expected exactly 1 value but got %d", len(values))
+	}
+	mode, ok := writingModeKeywords[values[0].Str]
+	if !ok {
+		return fmt.Errorf("unsupported value '%s'", values[0].Str)
+	}
+	panel.SetWritingMode(mode)
+	return nil
 }
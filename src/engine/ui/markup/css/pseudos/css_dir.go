@@ -8,11 +8,30 @@ package pseudos
 
 import (
 	"errors"
+	"strings"
 
+	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// Dir matches elements whose direction from the dir attribute, on them or
+// the closest parent with one, is the given ltr or rtl
 func (p Dir) Process(elm *document.Element, value rules.SelectorPart) ([]*document.Element, error) {
-	return []*document.Element{elm}, errors.New("not implemented")
+	if len(value.Args) != 1 {
+		return []*document.Element{}, errors.New(":dir expects ltr or rtl")
+	}
+	var want ui.Direction
+	switch strings.ToLower(strings.TrimSpace(value.Args[0])) {
+	case "ltr":
+		want = ui.DirectionLTR
+	case "rtl":
+		want = ui.DirectionRTL
+	default:
+		return []*document.Element{}, nil
+	}
+	if elm.Direction() == want {
+		return []*document.Element{elm}, nil
+	}
+	return []*document.Element{}, nil
 }
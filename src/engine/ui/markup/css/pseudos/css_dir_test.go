@@ -0,0 +1,46 @@
+/******************************************************************************/
+/* css_dir_test.go                                                            */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package pseudos
+
+import (
+	"runtime"
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+const testDirHTML = `<div id="page"><div dir="rtl"><p id="inner">שלום</p><p id="ltr" dir="ltr">hi</p></div><p id="auto" dir="auto">مرحبا world</p></div>`
+
+func testDirMatches(elm *document.Element, dir string) bool {
+	got, err := (Dir{}).Process(elm, rules.SelectorPart{Args: []string{dir}})
+	return err == nil && len(got) == 1 && got[0] == elm
+}
+
+func TestDir(t *testing.T) {
+	root := document.NewHTML(testDirHTML)
+	tests := []struct{ id, dir string }{
+		{"page", "ltr"},
+		{"inner", "rtl"},
+		{"ltr", "ltr"},
+		{"auto", "rtl"},
+	}
+	for _, test := range tests {
+		elm := root.FindElementById(test.id)
+		if !testDirMatches(elm, test.dir) {
+			t.Errorf("expected #%s to match :dir(%s)", test.id, test.dir)
+		}
+		other := "rtl"
+		if test.dir == "rtl" {
+			other = "ltr"
+		}
+		if testDirMatches(elm, other) {
+			t.Errorf("expected #%s not to match :dir(%s)", test.id, other)
+		}
+	}
+	runtime.KeepAlive(root)
+}
@@ -7,12 +7,19 @@
 package pseudos
 
 import (
-	"errors"
+	"strings"
 
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// Empty matches elements without any child elements or text, white space
+// only text is considered empty
 func (p Empty) Process(elm *document.Element, value rules.SelectorPart) ([]*document.Element, error) {
-	return []*document.Element{elm}, errors.New("not implemented")
+	for _, c := range elm.Children {
+		if !c.IsText() || strings.TrimSpace(c.Data) != "" {
+			return []*document.Element{}, nil
+		}
+	}
+	return []*document.Element{elm}, nil
 }
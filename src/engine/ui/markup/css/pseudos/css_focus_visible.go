@@ -7,12 +7,21 @@
 package pseudos
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
 func (p FocusVisible) Process(elm *document.Element, value rules.SelectorPart) ([]*document.Element, error) {
-	return []*document.Element{elm}, errors.New("not implemented")
+	return []*document.Element{elm}, nil
+}
+
+// AlterRules makes the rules wait for focus that the document shows, which is
+// focus given by keyboard or controller navigation and the focus of text
+// fields however it was given
+func (p FocusVisible) AlterRules(inRules []rules.Rule) []rules.Rule {
+	for i := range inRules {
+		inRules[i].Invocation = inRules[i].Invocation.With(
+			rules.RuleInvokeFocus | rules.RuleInvokeFocusVisible)
+	}
+	return inRules
 }
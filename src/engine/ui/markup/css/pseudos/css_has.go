@@ -14,5 +14,13 @@ import (
 )
 
 func (p Has) Process(elm *document.Element, value rules.SelectorPart) ([]*document.Element, error) {
-	return []*document.Element{elm}, errors.New("not implemented")
+	if len(value.Args) == 0 {
+		return []*document.Element{}, errors.New(":has requires a selector argument")
+	}
+	for _, sel := range selectorList(value) {
+		if matchRelativeSelector(elm, sel) {
+			return []*document.Element{elm}, nil
+		}
+	}
+	return []*document.Element{}, nil
 }
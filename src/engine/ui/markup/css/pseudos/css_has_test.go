@@ -0,0 +1,62 @@
+/******************************************************************************/
+/* css_has_test.go                                                            */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package pseudos
+
+import (
+	"runtime"
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+const testHasHTML = `<div id="card"><figure><img src="a.png"></figure><p class="caption">A</p></div><div id="empty"></div>`
+
+func testHasMatches(elm *document.Element, args ...string) bool {
+	got, err := (Has{}).Process(elm, rules.SelectorPart{Args: args})
+	return err == nil && len(got) == 1 && got[0] == elm
+}
+
+func TestHasDescendant(t *testing.T) {
+	root := document.NewHTML(testHasHTML)
+	card := root.FindElementById("card")
+	if !testHasMatches(card, "img") {
+		t.Fatal(":has(img) should match an element with an img descendant")
+	}
+	if testHasMatches(root.FindElementById("empty"), "img") {
+		t.Fatal(":has(img) should not match an element without an img")
+	}
+	if !testHasMatches(card, "video", ",", " ", ".", "caption") {
+		t.Fatal(":has(video, .caption) should match when any selector matches")
+	}
+	runtime.KeepAlive(root)
+}
+
+func TestHasRelativeCombinators(t *testing.T) {
+	root := document.NewHTML(testHasHTML)
+	card := root.FindElementById("card")
+	if testHasMatches(card, ">", "img") {
+		t.Fatal(":has(> img) should not match a grandchild img")
+	}
+	if !testHasMatches(card, ">", "figure", ">", "img") {
+		t.Fatal(":has(> figure > img) should match")
+	}
+	if !testHasMatches(root.FindElementByTag("figure"), "+", ".", "caption") {
+		t.Fatal(":has(+ .caption) should match the adjacent sibling")
+	}
+	if !testHasMatches(card, "~", "#empty") {
+		t.Fatal(":has(~ #empty) should match a following sibling")
+	}
+	runtime.KeepAlive(root)
+}
+
+func TestHasRequiresArgument(t *testing.T) {
+	root := document.NewHTML(testHasHTML)
+	if _, err := (Has{}).Process(root.FindElementById("card"), rules.SelectorPart{}); err == nil {
+		t.Fatal(":has() without a selector should return an error")
+	}
+}
@@ -7,12 +7,23 @@
 package pseudos
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// Host matches the element a component was instantiated for, :host() also
+// requires the element to match one of the selectors of the argument
 func (p Host) Process(elm *document.Element, value rules.SelectorPart) ([]*document.Element, error) {
-	return []*document.Element{elm}, errors.New("not implemented")
+	if !elm.HasAttribute(rules.ComponentHostAttribute) {
+		return []*document.Element{}, nil
+	}
+	if value.SelectType != rules.ReadingPseudoFunction {
+		return []*document.Element{elm}, nil
+	}
+	for _, sel := range selectorList(value) {
+		if MatchSelector(elm, sel.Parts, nil) {
+			return []*document.Element{elm}, nil
+		}
+	}
+	return []*document.Element{}, nil
 }
@@ -13,6 +13,22 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// HostContext matches the element a component was instantiated for when it
+// or any of its ancestors match one of the selectors of the argument
 func (p HostContext) Process(elm *document.Element, value rules.SelectorPart) ([]*document.Element, error) {
-	return []*document.Element{elm}, errors.New("not implemented")
+	if len(value.Args) == 0 {
+		return []*document.Element{}, errors.New(":host-context requires a selector argument")
+	}
+	if !elm.HasAttribute(rules.ComponentHostAttribute) {
+		return []*document.Element{}, nil
+	}
+	selectors := selectorList(value)
+	for e := elm; e != nil; e = e.Parent.Value() {
+		for _, sel := range selectors {
+			if MatchSelector(e, sel.Parts, nil) {
+				return []*document.Element{elm}, nil
+			}
+		}
+	}
+	return []*document.Element{}, nil
 }
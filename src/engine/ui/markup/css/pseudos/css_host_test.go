@@ -0,0 +1,69 @@
+/******************************************************************************/
+/* css_host_test.go                                                           */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package pseudos
+
+import (
+	"runtime"
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+const testHostHTML = `<div id="theme" class="dark">
+<item-card id="card" class="wide" kaiju-host="item-card"><div id="inner" kaiju-scope="item-card"></div></item-card>
+</div>`
+
+func testHostMatches(p Pseudo, elm *document.Element, part rules.SelectorPart) bool {
+	got, err := p.Process(elm, part)
+	return err == nil && len(got) == 1 && got[0] == elm
+}
+
+func TestHost(t *testing.T) {
+	root := document.NewHTML(testHostHTML)
+	card := root.FindElementById("card")
+	plain := rules.SelectorPart{Name: "host", SelectType: rules.ReadingPseudo}
+	if !testHostMatches(Host{}, card, plain) {
+		t.Fatal(":host should match the host of a component")
+	}
+	if testHostMatches(Host{}, root.FindElementById("inner"), plain) {
+		t.Fatal(":host should not match the elements within the component")
+	}
+	fn := func(args ...string) rules.SelectorPart {
+		return rules.SelectorPart{Name: "host", SelectType: rules.ReadingPseudoFunction, Args: args}
+	}
+	if !testHostMatches(Host{}, card, fn(".", "wide")) {
+		t.Fatal(":host(.wide) should match a host with the class")
+	}
+	if testHostMatches(Host{}, card, fn(".", "narrow")) {
+		t.Fatal(":host(.narrow) should not match a host without the class")
+	}
+	runtime.KeepAlive(root)
+}
+
+func TestHostContext(t *testing.T) {
+	root := document.NewHTML(testHostHTML)
+	card := root.FindElementById("card")
+	part := func(args ...string) rules.SelectorPart {
+		return rules.SelectorPart{Name: "host-context", SelectType: rules.ReadingPseudoFunction, Args: args}
+	}
+	if !testHostMatches(HostContext{}, card, part(".", "dark")) {
+		t.Fatal(":host-context(.dark) should match a host within a .dark element")
+	}
+	if !testHostMatches(HostContext{}, card, part(".", "wide")) {
+		t.Fatal(":host-context() should also test the host itself")
+	}
+	if testHostMatches(HostContext{}, card, part(".", "light")) {
+		t.Fatal(":host-context(.light) should not match without a .light ancestor")
+	}
+	if testHostMatches(HostContext{}, root.FindElementById("theme"), part(".", "dark")) {
+		t.Fatal(":host-context() should only match the hosts of components")
+	}
+	if _, err := (HostContext{}).Process(card, rules.SelectorPart{}); err == nil {
+		t.Fatal(":host-context without a selector should return an error")
+	}
+	runtime.KeepAlive(root)
+}
@@ -14,5 +14,20 @@ import (
 )
 
 func (p Is) Process(elm *document.Element, value rules.SelectorPart) ([]*document.Element, error) {
-	return []*document.Element{elm}, errors.New("not implemented")
+	if len(value.Args) == 0 {
+		return []*document.Element{}, errors.New(":is requires a selector argument")
+	}
+	if matchesAnySelector(elm, selectorList(value)) {
+		return []*document.Element{elm}, nil
+	}
+	return []*document.Element{}, nil
+}
+
+func matchesAnySelector(elm *document.Element, selectors []rules.Selector) bool {
+	for i := range selectors {
+		if MatchSelector(elm, selectors[i].Parts, nil) {
+			return true
+		}
+	}
+	return false
 }
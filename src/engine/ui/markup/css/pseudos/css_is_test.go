@@ -0,0 +1,43 @@
+/******************************************************************************/
+/* css_is_test.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package pseudos
+
+import (
+	"runtime"
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+func TestIsAndWhereMatchSelectorList(t *testing.T) {
+	root := document.NewHTML(`<nav><a id="home" class="link active">Home</a></nav>`)
+	elm := root.FindElementById("home")
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{".", "missing", ",", " ", ".", "active"}, true},
+		{[]string{"nav", " ", "a"}, true},
+		{[]string{"nav", ">", "a", ".", "link"}, true},
+		{[]string{"section", " ", "a"}, false},
+		{[]string{"#other", ",", " ", "span"}, false},
+	}
+	for _, test := range tests {
+		value := rules.SelectorPart{Args: test.args}
+		for name, p := range map[string]Pseudo{"is": Is{}, "where": Where{}} {
+			got, err := p.Process(elm, value)
+			if err != nil {
+				t.Fatalf(":%s(%v) returned an error: %v", name, test.args, err)
+			}
+			if matched := len(got) == 1 && got[0] == elm; matched != test.want {
+				t.Errorf(":%s(%v) expected match %v", name, test.args, test.want)
+			}
+		}
+	}
+	runtime.KeepAlive(root)
+}
@@ -0,0 +1,45 @@
+/******************************************************************************/
+/* css_media_test.go                                                          */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package pseudos
+
+import (
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+func TestMediaPseudosOnlyMatchVideos(t *testing.T) {
+	root := document.NewHTML(`<div><video src="intro.kvid"></video></div>`)
+	video := root.FindElementByTag("video")
+	div := root.FindElementByTag("div")
+	for _, p := range []Pseudo{Playing{}, Paused{}} {
+		got, err := p.Process(video, rules.SelectorPart{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0] != video {
+			t.Errorf(":%s should match video elements", p.Key())
+		}
+		if got, _ = p.Process(div, rules.SelectorPart{}); len(got) != 0 {
+			t.Errorf(":%s should not match other elements", p.Key())
+		}
+	}
+}
+
+func TestMediaPseudosWaitForPlaybackState(t *testing.T) {
+	in := []rules.Rule{{Property: "opacity"}}
+	if got := (Playing{}).AlterRules(in); got[0].Invocation != rules.RuleInvokePlaying {
+		t.Errorf(":playing rules should wait for the playing state, got %d", got[0].Invocation)
+	}
+	in = []rules.Rule{{Property: "opacity", Invocation: rules.RuleInvokeHover}}
+	got := (Paused{}).AlterRules(in)
+	if !got[0].Invocation.Matches(rules.RuleInvokeHover|rules.RuleInvokePaused) ||
+		got[0].Invocation.Matches(rules.RuleInvokePaused) {
+		t.Errorf(":hover:paused rules should wait for both states, got %d", got[0].Invocation)
+	}
+}
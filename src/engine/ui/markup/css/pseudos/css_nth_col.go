@@ -7,12 +7,33 @@
 package pseudos
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// processNthCol matches the table cells that cover a column at An+B, a cell
+// spanning several columns matches if any of them are
+func processNthCol(elm *document.Element, value rules.SelectorPart, fromEnd bool) ([]*document.Element, error) {
+	a, b, err := parseNthFormula(value.Args)
+	if err != nil {
+		return []*document.Element{}, err
+	}
+	first, span, count, ok := elm.TableColumn()
+	if !ok {
+		return []*document.Element{}, nil
+	}
+	for col := first; col < first+span; col++ {
+		position := col
+		if fromEnd {
+			position = count - col + 1
+		}
+		if nthMatches(a, b, position) {
+			return []*document.Element{elm}, nil
+		}
+	}
+	return []*document.Element{}, nil
+}
+
 func (p NthCol) Process(elm *document.Element, value rules.SelectorPart) ([]*document.Element, error) {
-	return []*document.Element{elm}, errors.New("not implemented")
+	return processNthCol(elm, value, false)
 }
@@ -0,0 +1,39 @@
+/******************************************************************************/
+/* css_nth_col_test.go                                                        */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package pseudos
+
+import (
+	"runtime"
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+const testNthColHTML = `<table><tbody>
+<tr><td id="a" rowspan="2">a</td><td id="b" colspan="2">b</td></tr>
+<tr><td id="c">c</td><td id="d">d</td></tr>
+</tbody></table>`
+
+func TestNthCol(t *testing.T) {
+	root := document.NewHTML(testNthColHTML)
+	a, b, c, d := root.FindElementById("a"), root.FindElementById("b"),
+		root.FindElementById("c"), root.FindElementById("d")
+	// c is pushed into the second column by the rowspan of a
+	if !testPseudoMatches(t, NthCol{}, c, "2") || testPseudoMatches(t, NthCol{}, c, "1") {
+		t.Error("c should be in the 2nd column")
+	}
+	if !testPseudoMatches(t, NthCol{}, b, "2") || !testPseudoMatches(t, NthCol{}, b, "3") {
+		t.Error("b should span the 2nd and 3rd columns")
+	}
+	if !testPseudoMatches(t, NthLastCol{}, d, "1") || !testPseudoMatches(t, NthLastCol{}, a, "3") {
+		t.Error("d should be the last column and a the 3rd last")
+	}
+	if !testPseudoMatches(t, NthCol{}, a, "odd") || testPseudoMatches(t, NthCol{}, c, "odd") {
+		t.Error("odd should match the 1st and 3rd columns")
+	}
+	runtime.KeepAlive(root)
+}
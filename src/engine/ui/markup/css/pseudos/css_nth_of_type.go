@@ -8,11 +8,92 @@ package pseudos
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// parseNthFormula reads the An+B argument of the :nth-* pseudo-classes
+func parseNthFormula(args []string) (int, int, error) {
+	formula := strings.ToLower(strings.ReplaceAll(strings.Join(args, ""), " ", ""))
+	switch formula {
+	case "":
+		return 0, 0, errors.New("no arguments supplied")
+	case "odd":
+		return 2, 1, nil
+	case "even":
+		return 2, 0, nil
+	}
+	n := strings.IndexByte(formula, 'n')
+	if n < 0 {
+		b, err := strconv.Atoi(formula)
+		return 0, b, err
+	}
+	a := 0
+	switch formula[:n] {
+	case "", "+":
+		a = 1
+	case "-":
+		a = -1
+	default:
+		var err error
+		if a, err = strconv.Atoi(formula[:n]); err != nil {
+			return 0, 0, fmt.Errorf("invalid nth formula: %s", formula)
+		}
+	}
+	b := 0
+	if rest := formula[n+1:]; rest != "" {
+		var err error
+		if b, err = strconv.Atoi(rest); err != nil {
+			return 0, 0, fmt.Errorf("invalid nth formula: %s", formula)
+		}
+	}
+	return a, b, nil
+}
+
+// nthMatches returns true if the 1 based position is An+B for some n >= 0
+func nthMatches(a, b, position int) bool {
+	if a == 0 {
+		return position == b
+	}
+	diff := position - b
+	return diff%a == 0 && diff/a >= 0
+}
+
+// typePosition returns the 1 based position of the element among the sibling
+// elements that have the same tag along with the number of those siblings
+func typePosition(elm *document.Element, fromEnd bool) (int, int) {
+	siblings := elementSiblings(elm)
+	position, count := 0, 0
+	for _, s := range siblings {
+		if s.Data != elm.Data {
+			continue
+		}
+		count++
+		if s == elm {
+			position = count
+		}
+	}
+	if fromEnd {
+		position = count - position + 1
+	}
+	return position, count
+}
+
+func processNthOfType(elm *document.Element, value rules.SelectorPart, fromEnd bool) ([]*document.Element, error) {
+	a, b, err := parseNthFormula(value.Args)
+	if err != nil {
+		return []*document.Element{}, err
+	}
+	if position, _ := typePosition(elm, fromEnd); nthMatches(a, b, position) {
+		return []*document.Element{elm}, nil
+	}
+	return []*document.Element{}, nil
+}
+
 func (p NthOfType) Process(elm *document.Element, value rules.SelectorPart) ([]*document.Element, error) {
-	return []*document.Element{elm}, errors.New("not implemented")
+	return processNthOfType(elm, value, false)
 }
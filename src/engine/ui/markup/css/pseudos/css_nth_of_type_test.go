@@ -0,0 +1,98 @@
+/******************************************************************************/
+/* css_nth_of_type_test.go                                                    */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package pseudos
+
+import (
+	"runtime"
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+const testOfTypeHTML = `<ul><li id="l1"></li><p id="p1"> </p><li id="l2">x</li><li id="l3"></li><li id="l4"></li></ul>`
+
+func testPseudoMatches(t *testing.T, p Pseudo, elm *document.Element, args ...string) bool {
+	t.Helper()
+	got, err := p.Process(elm, rules.SelectorPart{Args: args})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return len(got) == 1 && got[0] == elm
+}
+
+func TestParseNthFormula(t *testing.T) {
+	tests := []struct {
+		args []string
+		a, b int
+	}{
+		{[]string{"odd"}, 2, 1},
+		{[]string{"even"}, 2, 0},
+		{[]string{"3"}, 0, 3},
+		{[]string{"2n", "+", "1"}, 2, 1},
+		{[]string{"2n", "+1"}, 2, 1},
+		{[]string{"-n", "+", "3"}, -1, 3},
+		{[]string{"n"}, 1, 0},
+		{[]string{"3n", "-", "2"}, 3, -2},
+	}
+	for _, test := range tests {
+		a, b, err := parseNthFormula(test.args)
+		if err != nil || a != test.a || b != test.b {
+			t.Errorf("%v expected %dn+%d, got %dn+%d (%v)", test.args, test.a, test.b, a, b, err)
+		}
+	}
+	if _, _, err := parseNthFormula([]string{"2x"}); err == nil {
+		t.Error("expected an error for an invalid formula")
+	}
+}
+
+func TestNthOfType(t *testing.T) {
+	root := document.NewHTML(testOfTypeHTML)
+	l1, l2, l3, l4 := root.FindElementById("l1"), root.FindElementById("l2"),
+		root.FindElementById("l3"), root.FindElementById("l4")
+	// The <p> is skipped, so l2 is the second li and not the third child
+	if !testPseudoMatches(t, NthOfType{}, l2, "2") {
+		t.Error("l2 should be the 2nd li")
+	}
+	if !testPseudoMatches(t, NthOfType{}, l3, "odd") || testPseudoMatches(t, NthOfType{}, l2, "odd") {
+		t.Error("odd should match l1 and l3 only")
+	}
+	if !testPseudoMatches(t, NthOfType{}, l2, "-n", "+", "2") || testPseudoMatches(t, NthOfType{}, l3, "-n", "+", "2") {
+		t.Error("-n+2 should match the first two li")
+	}
+	if !testPseudoMatches(t, NthLastOfType{}, l3, "2") || testPseudoMatches(t, NthLastOfType{}, l1, "2") {
+		t.Error("l3 should be the 2nd last li")
+	}
+	if !testPseudoMatches(t, FirstOfType{}, l1) || testPseudoMatches(t, FirstOfType{}, l2) {
+		t.Error("only l1 should be the first of type")
+	}
+	if !testPseudoMatches(t, LastOfType{}, l4) || testPseudoMatches(t, LastOfType{}, l3) {
+		t.Error("only l4 should be the last of type")
+	}
+	runtime.KeepAlive(root)
+}
+
+func TestOnlyAndEmpty(t *testing.T) {
+	root := document.NewHTML(testOfTypeHTML)
+	p1, l1, l2 := root.FindElementById("p1"), root.FindElementById("l1"), root.FindElementById("l2")
+	if !testPseudoMatches(t, OnlyOfType{}, p1) || testPseudoMatches(t, OnlyOfType{}, l1) {
+		t.Error("only the p should be the only of its type")
+	}
+	if testPseudoMatches(t, OnlyChild{}, l1) {
+		t.Error("l1 has siblings so it is not an only child")
+	}
+	if !testPseudoMatches(t, OnlyChild{}, root.FindElementByTag("ul")) {
+		t.Error("the ul is the only child of the body")
+	}
+	if !testPseudoMatches(t, Empty{}, l1) || !testPseudoMatches(t, Empty{}, p1) {
+		t.Error("elements with no or white space only content should be empty")
+	}
+	if testPseudoMatches(t, Empty{}, l2) {
+		t.Error("an element with text should not be empty")
+	}
+	runtime.KeepAlive(root)
+}
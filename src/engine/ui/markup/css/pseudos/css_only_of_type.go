@@ -7,12 +7,13 @@
 package pseudos
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
 func (p OnlyOfType) Process(elm *document.Element, value rules.SelectorPart) ([]*document.Element, error) {
-	return []*document.Element{elm}, errors.New("not implemented")
+	if _, count := typePosition(elm, false); count == 1 {
+		return []*document.Element{elm}, nil
+	}
+	return []*document.Element{}, nil
 }
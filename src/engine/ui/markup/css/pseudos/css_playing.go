@@ -7,12 +7,20 @@
 package pseudos
 
 import (
-	"errors"
-
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
 func (p Playing) Process(elm *document.Element, value rules.SelectorPart) ([]*document.Element, error) {
-	return []*document.Element{elm}, errors.New("not implemented")
+	if elm.IsVideo() {
+		return []*document.Element{elm}, nil
+	}
+	return []*document.Element{}, nil
+}
+
+func (p Playing) AlterRules(inRules []rules.Rule) []rules.Rule {
+	for i := range inRules {
+		inRules[i].Invocation = inRules[i].Invocation.With(rules.RuleInvokePlaying)
+	}
+	return inRules
 }
@@ -135,9 +135,8 @@ func (p Focus) IsFunction() bool { return false }
 // https://developer.mozilla.org/en-US/docs/Web/CSS/:focus-visible
 type FocusVisible struct{}
 
-func (p FocusVisible) Key() string                                { return "focus-visible" }
-func (p FocusVisible) IsFunction() bool                           { return false }
-func (p FocusVisible) AlterRules(rules []rules.Rule) []rules.Rule { return rules }
+func (p FocusVisible) Key() string      { return "focus-visible" }
+func (p FocusVisible) IsFunction() bool { return false }
 
 // https://developer.mozilla.org/en-US/docs/Web/CSS/:focus-within
 type FocusWithin struct{}
@@ -350,16 +349,14 @@ func (p PlaceholderShown) AlterRules(rules []rules.Rule) []rules.Rule { return r
 // https://developer.mozilla.org/en-US/docs/Web/CSS/:paused
 type Paused struct{}
 
-func (p Paused) Key() string                                { return "paused" }
-func (p Paused) IsFunction() bool                           { return false }
-func (p Paused) AlterRules(rules []rules.Rule) []rules.Rule { return rules }
+func (p Paused) Key() string      { return "paused" }
+func (p Paused) IsFunction() bool { return false }
 
 // https://developer.mozilla.org/en-US/docs/Web/CSS/:playing
 type Playing struct{}
 
-func (p Playing) Key() string                                { return "playing" }
-func (p Playing) IsFunction() bool                           { return false }
-func (p Playing) AlterRules(rules []rules.Rule) []rules.Rule { return rules }
+func (p Playing) Key() string      { return "playing" }
+func (p Playing) IsFunction() bool { return false }
 
 // https://developer.mozilla.org/en-US/docs/Web/CSS/:read-only
 type ReadOnly struct{}
@@ -13,6 +13,14 @@ import (
 	"kaijuengine.com/engine/ui/markup/document"
 )
 
+// Where matches the same as :is, the difference is that it adds nothing to
+// the specificity of the selector (see rules.Selector.Specificity)
 func (p Where) Process(elm *document.Element, value rules.SelectorPart) ([]*document.Element, error) {
-	return []*document.Element{elm}, errors.New("not implemented")
+	if len(value.Args) == 0 {
+		return []*document.Element{}, errors.New(":where requires a selector argument")
+	}
+	if matchesAnySelector(elm, selectorList(value)) {
+		return []*document.Element{elm}, nil
+	}
+	return []*document.Element{}, nil
 }
@@ -0,0 +1,218 @@
+/******************************************************************************/
+/* selector_matcher.go                                                        */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package pseudos
+
+import (
+	"slices"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+type selectorStep struct {
+	parts      []rules.SelectorPart
+	combinator rules.RuleState
+	// scope is set for the implied first step of a relative selector (such
+	// as the argument of :has), it only matches this one element
+	scope *document.Element
+}
+
+func isCombinator(part rules.SelectorPart) bool {
+	switch part.SelectType {
+	case rules.ReadingDescendant, rules.ReadingChild, rules.ReadingSibling, rules.ReadingAdjacent:
+		return true
+	default:
+		return false
+	}
+}
+
+func selectorSteps(parts []rules.SelectorPart) []selectorStep {
+	steps := make([]selectorStep, 0, len(parts))
+	current := selectorStep{parts: make([]rules.SelectorPart, 0), combinator: rules.ReadingTag}
+	for i := range parts {
+		part := parts[i]
+		if isCombinator(part) {
+			if len(current.parts) > 0 {
+				steps = append(steps, current)
+				current = selectorStep{parts: make([]rules.SelectorPart, 0), combinator: part.SelectType}
+			} else if len(steps) > 0 {
+				current.combinator = part.SelectType
+			}
+			continue
+		}
+		current.parts = append(current.parts, part)
+	}
+	if len(current.parts) > 0 {
+		steps = append(steps, current)
+	}
+	return steps
+}
+
+// MatchSelector returns true if the element matches the parts of a selector.
+// Pseudo-classes that change how the rules are invoked (such as :hover)
+// update selectorRules, which can be nil when only the match is needed
+func MatchSelector(elm *document.Element, parts []rules.SelectorPart, selectorRules *[]rules.Rule) bool {
+	steps := selectorSteps(parts)
+	if len(steps) == 0 {
+		return false
+	}
+	return selectorStepMatches(elm, steps, len(steps)-1, selectorRules)
+}
+
+// matchRelativeSelector matches a selector that is relative to the scope
+// element, such as the "> img" in :has(> img)
+func matchRelativeSelector(scope *document.Element, sel rules.Selector) bool {
+	parts := sel.Parts
+	combinator := rules.ReadingDescendant
+	if len(parts) > 0 && isCombinator(parts[0]) {
+		combinator = parts[0].SelectType
+		parts = parts[1:]
+	}
+	steps := selectorSteps(parts)
+	if len(steps) == 0 {
+		return false
+	}
+	steps[0].combinator = combinator
+	steps = append([]selectorStep{{scope: scope}}, steps...)
+	root := scope
+	if combinator == rules.ReadingSibling || combinator == rules.ReadingAdjacent {
+		if root = scope.Parent.Value(); root == nil {
+			return false
+		}
+	}
+	var search func(e *document.Element) bool
+	search = func(e *document.Element) bool {
+		for _, c := range e.Children {
+			if c.IsText() {
+				continue
+			}
+			if selectorStepMatches(c, steps, len(steps)-1, nil) || search(c) {
+				return true
+			}
+		}
+		return false
+	}
+	return search(root)
+}
+
+func selectorStepMatches(elm *document.Element, steps []selectorStep, idx int, selectorRules *[]rules.Rule) bool {
+	if elm == nil {
+		return false
+	}
+	if steps[idx].scope != nil {
+		return elm == steps[idx].scope
+	}
+	if !selectorPartListMatches(elm, steps[idx].parts, selectorRules) {
+		return false
+	}
+	if idx == 0 {
+		return true
+	}
+	switch steps[idx].combinator {
+	case rules.ReadingChild:
+		return selectorStepMatches(elm.Parent.Value(), steps, idx-1, selectorRules)
+	case rules.ReadingDescendant:
+		for parent := elm.Parent.Value(); parent != nil; parent = parent.Parent.Value() {
+			if selectorStepMatches(parent, steps, idx-1, selectorRules) {
+				return true
+			}
+		}
+	case rules.ReadingAdjacent:
+		siblings := elementSiblings(elm)
+		if i := slices.Index(siblings, elm); i > 0 {
+			return selectorStepMatches(siblings[i-1], steps, idx-1, selectorRules)
+		}
+	case rules.ReadingSibling:
+		siblings := elementSiblings(elm)
+		for i := slices.Index(siblings, elm) - 1; i >= 0; i-- {
+			if selectorStepMatches(siblings[i], steps, idx-1, selectorRules) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func selectorPartListMatches(elm *document.Element, parts []rules.SelectorPart, selectorRules *[]rules.Rule) bool {
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+		switch part.SelectType {
+		case rules.ReadingId:
+			if elm.Attribute("id") != part.Name {
+				return false
+			}
+		case rules.ReadingClass:
+			if !elm.HasClass(part.Name) {
+				return false
+			}
+		case rules.ReadingTag:
+			if elm.IsText() || (part.Name != "*" && elm.Data != part.Name) {
+				return false
+			}
+		case rules.ReadingCondition:
+			want := ""
+			hasAssignment := i+1 < len(parts) && parts[i+1].SelectType == rules.ReadingConditionAssignment
+			if hasAssignment {
+				want = parts[i+1].Name
+				i++
+			}
+			if !selectorAttributeMatches(elm, part.Name, want, hasAssignment) {
+				return false
+			}
+		case rules.ReadingConditionAssignment:
+			return false
+		case rules.ReadingPseudoElement:
+			// Pseudo-elements style a box that is generated by the element,
+			// so it is the element itself that is matched here
+		case rules.ReadingPseudo, rules.ReadingPseudoFunction:
+			p, ok := PseudoMap[part.Name]
+			if !ok {
+				return false
+			}
+			selects, err := p.Process(elm, part)
+			if err != nil || !slices.Contains(selects, elm) {
+				return false
+			}
+			if selectorRules != nil {
+				*selectorRules = p.AlterRules(*selectorRules)
+			}
+		}
+	}
+	return true
+}
+
+func selectorAttributeMatches(elm *document.Element, key, value string, hasAssignment bool) bool {
+	if !hasAssignment {
+		return elm.HasAttribute(key)
+	}
+	return elm.Attribute(key) == value
+}
+
+// elementSiblings returns the element children of the element's parent
+// (including the element itself), text nodes are not included
+func elementSiblings(elm *document.Element) []*document.Element {
+	parent := elm.Parent.Value()
+	if parent == nil {
+		return []*document.Element{elm}
+	}
+	out := make([]*document.Element, 0, len(parent.Children))
+	for _, c := range parent.Children {
+		if !c.IsText() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// selectorList returns the parsed selector list argument of the pseudo-class
+// function, parsing it if the part was not created by the style sheet parser
+func selectorList(value rules.SelectorPart) []rules.Selector {
+	if value.Selectors != nil {
+		return value.Selectors
+	}
+	return rules.ParseSelectorList(value.Args)
+}
@@ -10,7 +10,6 @@ import (
 	"slices"
 
 	"kaijuengine.com/engine/ui"
-	"kaijuengine.com/engine/ui/markup/css/helpers"
 	"kaijuengine.com/engine/ui/markup/css/pseudos"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/document"
@@ -90,17 +89,49 @@ func cssPropertyOverrides(later, earlier string) bool {
 	return false
 }
 
+// cssRulesShareTarget returns true if the rules style the same box in the
+// same state, only then can one of them override the other
+func cssRulesShareTarget(a, b *rules.Rule) bool {
+	return a.Invocation == b.Invocation && a.PseudoElement == b.PseudoElement
+}
+
+// cssRuleOutranks returns true if the later rule wins the cascade against the
+// earlier rule. Author rules always win against user-agent rules, otherwise
+// the more specific rule wins and ties go to the rule that came later
+func cssRuleOutranks(later, earlier *rules.Rule) bool {
+	if later.UserAgent != earlier.UserAgent {
+		return earlier.UserAgent
+	}
+	return later.Specificity.Compare(earlier.Specificity) >= 0
+}
+
 func (m CSSMap) add(elm *ui.UI, inRules []rules.Rule) {
 	addRules := rules.CloneRules(inRules)
 	if c, ok := m[elm]; !ok {
 		m[elm] = addRules
 	} else {
 		for i := len(c) - 1; i >= 0; i-- {
-			for j := range addRules {
-				if c[i].Invocation == addRules[j].Invocation &&
-					cssPropertyOverrides(addRules[j].Property, c[i].Property) {
-					c = slices.Delete(c, i, i+1)
+			for j := 0; j < len(addRules); j++ {
+				later := &addRules[j]
+				if !cssRulesShareTarget(&c[i], later) {
+					continue
+				}
+				if cssPropertyOverrides(later.Property, c[i].Property) {
+					if cssRuleOutranks(later, &c[i]) {
+						c = slices.Delete(c, i, i+1)
+						break
+					}
+					if later.Property != c[i].Property {
+						// The losing shorthand has to be applied before the
+						// longhand that beat it so the longhand is kept
+						c = slices.Insert(c, i, *later)
+					}
+					addRules = slices.Delete(addRules, j, j+1)
 					break
+				} else if cssPropertyOverrides(c[i].Property, later.Property) &&
+					!cssRuleOutranks(later, &c[i]) {
+					addRules = slices.Delete(addRules, j, j+1)
+					j--
 				}
 			}
 		}
@@ -127,143 +158,46 @@ func applyMappings(doc *document.Document, cssMap map[*ui.UI][]rules.Rule) {
 	}
 }
 
-func applyDirect(part rules.SelectorPart, applyRules []rules.Rule, doc *document.Document, cssMap CSSMap) {
+// groupReaches returns false for the elements of a component's template
+// when the group is a style of the document, the styles of the engine and
+// of the component itself (which are scoped by their selectors) reach them
+func groupReaches(group *rules.SelectorGroup, elm *document.Element) bool {
+	return group.UserAgent || group.Scope != "" ||
+		!elm.HasAttribute(rules.ComponentScopeAttribute)
+}
+
+func applyDirect(group *rules.SelectorGroup, part rules.SelectorPart, applyRules []rules.Rule, doc *document.Document, cssMap CSSMap) {
+	var elms []*document.Element
 	switch part.SelectType {
 	case rules.ReadingId:
 		if elm, ok := doc.GetElementById(part.Name); ok {
-			cssMap.add(elm.UI, applyRules)
+			elms = []*document.Element{elm}
 		}
 	case rules.ReadingClass:
-		for _, elm := range doc.GetElementsByClass(part.Name) {
-			cssMap.add(elm.UI, applyRules)
-		}
+		elms = doc.GetElementsByClass(part.Name)
 	case rules.ReadingTag:
-		for _, elm := range doc.GetElementsByTagName(part.Name) {
-			cssMap.add(elm.UI, applyRules)
-		}
+		elms = doc.GetElementsByTagName(part.Name)
 	}
-}
-
-type selectorStep struct {
-	parts      []rules.SelectorPart
-	combinator rules.RuleState
-}
-
-func isCombinator(part rules.SelectorPart) bool {
-	switch part.SelectType {
-	case rules.ReadingDescendant, rules.ReadingChild, rules.ReadingSibling, rules.ReadingAdjacent:
-		return true
-	default:
-		return false
-	}
-}
-
-func selectorSteps(parts []rules.SelectorPart) []selectorStep {
-	steps := make([]selectorStep, 0, len(parts))
-	current := selectorStep{parts: make([]rules.SelectorPart, 0), combinator: rules.ReadingTag}
-	for i := range parts {
-		part := parts[i]
-		if isCombinator(part) {
-			if len(current.parts) > 0 {
-				steps = append(steps, current)
-				current = selectorStep{parts: make([]rules.SelectorPart, 0), combinator: part.SelectType}
-			} else if len(steps) > 0 {
-				current.combinator = part.SelectType
-			}
-			continue
+	for _, elm := range elms {
+		if groupReaches(group, elm) {
+			cssMap.add(elm.UI, applyRules)
 		}
-		current.parts = append(current.parts, part)
-	}
-	if len(current.parts) > 0 {
-		steps = append(steps, current)
 	}
-	return steps
 }
 
 func selectorMatches(elm *document.Element, parts []rules.SelectorPart, applyRules []rules.Rule) (bool, []rules.Rule) {
-	steps := selectorSteps(parts)
-	if len(steps) == 0 {
-		return false, nil
-	}
 	selectorRules := rules.CloneRules(applyRules)
-	if selectorStepMatches(elm, steps, len(steps)-1, &selectorRules) {
+	if pseudos.MatchSelector(elm, parts, &selectorRules) {
 		return true, selectorRules
 	}
 	return false, nil
 }
 
-func selectorStepMatches(elm *document.Element, steps []selectorStep, idx int, selectorRules *[]rules.Rule) bool {
-	if elm == nil || !selectorPartListMatches(elm, steps[idx].parts, selectorRules) {
-		return false
-	}
-	if idx == 0 {
-		return true
-	}
-	switch steps[idx].combinator {
-	case rules.ReadingChild:
-		return selectorStepMatches(elm.Parent.Value(), steps, idx-1, selectorRules)
-	case rules.ReadingDescendant:
-		for parent := elm.Parent.Value(); parent != nil; parent = parent.Parent.Value() {
-			if selectorStepMatches(parent, steps, idx-1, selectorRules) {
-				return true
-			}
-		}
-	}
-	return false
-}
-
-func selectorPartListMatches(elm *document.Element, parts []rules.SelectorPart, selectorRules *[]rules.Rule) bool {
-	for i := 0; i < len(parts); i++ {
-		part := parts[i]
-		switch part.SelectType {
-		case rules.ReadingId:
-			if elm.Attribute("id") != part.Name {
-				return false
-			}
-		case rules.ReadingClass:
-			if !elm.HasClass(part.Name) {
-				return false
-			}
-		case rules.ReadingTag:
-			if elm.IsText() || elm.Data != part.Name {
-				return false
-			}
-		case rules.ReadingCondition:
-			want := ""
-			hasAssignment := i+1 < len(parts) && parts[i+1].SelectType == rules.ReadingConditionAssignment
-			if hasAssignment {
-				want = parts[i+1].Name
-				i++
-			}
-			if !selectorAttributeMatches(elm, part.Name, want, hasAssignment) {
-				return false
-			}
-		case rules.ReadingConditionAssignment:
-			return false
-		case rules.ReadingPseudo, rules.ReadingPseudoFunction:
-			p, ok := pseudos.PseudoMap[part.Name]
-			if !ok {
-				return false
-			}
-			selects, err := p.Process(elm, part)
-			if err != nil || !slices.Contains(selects, elm) {
-				return false
-			}
-			*selectorRules = p.AlterRules(*selectorRules)
-		}
-	}
-	return true
-}
-
-func selectorAttributeMatches(elm *document.Element, key, value string, hasAssignment bool) bool {
-	if !hasAssignment {
-		return elm.HasAttribute(key)
-	}
-	return elm.Attribute(key) == value
-}
-
-func applyIndirect(parts []rules.SelectorPart, applyRules []rules.Rule, doc *document.Document, cssMap CSSMap) {
+func applyIndirect(group *rules.SelectorGroup, parts []rules.SelectorPart, applyRules []rules.Rule, doc *document.Document, cssMap CSSMap) {
 	for _, elm := range doc.Elements {
+		if !groupReaches(group, elm) {
+			continue
+		}
 		if ok, selectorRules := selectorMatches(elm, parts, applyRules); ok {
 			cssMap.add(elm.UI, selectorRules)
 		}
@@ -279,8 +213,9 @@ func cleanMapDuplicates(cssMap CSSMap) {
 				continue
 			}
 			for j := i + 1; j < len(v); j++ {
-				if v[i].Invocation == v[j].Invocation &&
-					cssPropertyOverrides(v[j].Property, v[i].Property) {
+				if cssRulesShareTarget(&v[i], &v[j]) &&
+					cssPropertyOverrides(v[j].Property, v[i].Property) &&
+					cssRuleOutranks(&v[j], &v[i]) {
 					v = slices.Delete(v, i, i+1)
 					i--
 					break
@@ -295,10 +230,33 @@ type Stylizer struct {
 	Window *windowing.Window
 }
 
+// weightedRules returns a copy of the group rules that carries the cascade
+// weight of the selector that matched them
+func weightedRules(group rules.SelectorGroup, sel rules.Selector) []rules.Rule {
+	out := make([]rules.Rule, len(group.Rules))
+	specificity := sel.Specificity()
+	pseudoElement := sel.PseudoElement()
+	for i := range group.Rules {
+		out[i] = group.Rules[i]
+		out[i].Specificity = specificity
+		out[i].UserAgent = group.UserAgent
+		out[i].PseudoElement = pseudoElement
+	}
+	return out
+}
+
 func (z Stylizer) ApplyStyles(s rules.StyleSheet, doc *document.Document) {
+	// Elements that no longer match any rule after this pass (for example an
+	// ancestor that stopped matching :has() because a descendant changed) are
+	// not touched by applyMappings, so they are tracked to be restyled
+	hadRules := make([]*document.Element, 0, len(doc.Elements))
 	for i := range doc.Elements {
 		e := doc.Elements[i]
+		if e.Stylizer.HasRules() {
+			hadRules = append(hadRules, e)
+		}
 		e.Stylizer.ClearRules()
+		e.Stylizer.SetKeyframes(s.Keyframes)
 		for j := range e.UIEventIds {
 			for k := range e.UIEventIds[j] {
 				e.UI.RemoveEvent(j, e.UIEventIds[j][k])
@@ -307,37 +265,50 @@ func (z Stylizer) ApplyStyles(s rules.StyleSheet, doc *document.Document) {
 		}
 	}
 	cssMap := CSSMap(make(map[*ui.UI][]rules.Rule))
-	for _, group := range s.Groups {
-		if group.MediaQuery.IsValid() {
-			switch group.MediaQuery.Key {
-			case "screen":
-			case "max-width":
-				v := helpers.NumFromLength(group.MediaQuery.Value, z.Window)
-				if int(v) <= z.Window.Width() {
-					continue
-				}
-			default:
-				continue
-			}
+	viewport := z.viewportFeatures()
+	var containers map[*document.Element]containerInfo
+	relational := false
+	watched := map[containerWatch]bool{}
+	checks := []document.ContainerQuery{}
+	for i := range s.Groups {
+		group := &s.Groups[i]
+		if !group.MediaQuery.MatchesViewport(viewport) {
+			continue
+		}
+		if group.MediaQuery.Container && containers == nil {
+			containers = findContainers(s, doc, viewport)
 		}
 		for _, sel := range group.Selectors {
-			if len(sel.Parts) == 1 && (sel.Parts[0].SelectType == rules.ReadingId ||
+			relational = relational || sel.IsRelational()
+			applyRules := weightedRules(*group, sel)
+			if group.MediaQuery.Container {
+				applyContainerRules(group, sel.Parts, applyRules, doc,
+					cssMap, containers, watched, &checks, viewport)
+			} else if len(sel.Parts) == 1 && (sel.Parts[0].SelectType == rules.ReadingId ||
 				sel.Parts[0].SelectType == rules.ReadingClass ||
-				sel.Parts[0].SelectType == rules.ReadingTag) {
-				applyDirect(sel.Parts[0], group.Rules, doc, cssMap)
+				(sel.Parts[0].SelectType == rules.ReadingTag && sel.Parts[0].Name != "*")) {
+				applyDirect(group, sel.Parts[0], applyRules, doc, cssMap)
 			} else if len(sel.Parts) > 1 {
-				applyIndirect(sel.Parts, group.Rules, doc, cssMap)
+				applyIndirect(group, sel.Parts, applyRules, doc, cssMap)
 			} else if len(sel.Parts) == 1 {
-				applyIndirect(sel.Parts, group.Rules, doc, cssMap)
+				applyIndirect(group, sel.Parts, applyRules, doc, cssMap)
 			}
 		}
 	}
+	doc.WatchContainerQueries(checks)
+	doc.WatchRelationalSelectors(relational)
 	cleanMapDuplicates(cssMap)
 	applyMappings(doc, cssMap)
+	for _, e := range hadRules {
+		if _, ok := cssMap[e.UI]; !ok {
+			e.UI.SetDirty(ui.DirtyTypeGenerated)
+		}
+	}
 	for _, elm := range doc.Elements {
 		if inlineStyle := elm.Attribute("style"); inlineStyle != "" {
 			group := s.ParseInline(inlineStyle, z.Window)
 			applyToElement(group.Rules, elm)
 		}
 	}
+	doc.UpdateCounters()
 }
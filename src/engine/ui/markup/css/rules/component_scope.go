@@ -0,0 +1,67 @@
+/******************************************************************************/
+/* component_scope.go                                                         */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package rules
+
+import "kaijuengine.com/engine/ui/markup/css/helpers"
+
+const (
+	// ComponentScopeAttribute is set to the name of the component on each of
+	// the elements that were created from the template of the component
+	ComponentScopeAttribute = "kaiju-scope"
+	// ComponentHostAttribute is set to the name of the component on the
+	// element the component was instantiated for
+	ComponentHostAttribute = "kaiju-host"
+)
+
+// ParseScoped parses the styles of a component, the selectors only match the
+// elements of the component's template while :host and :host-context() match
+// the element the component was instantiated for
+func (s *StyleSheet) ParseScoped(cssStr, scope string, window helpers.WindowDimensions) {
+	start := len(s.Groups)
+	s.Parse(cssStr, window)
+	for i := start; i < len(s.Groups); i++ {
+		s.Groups[i].Scope = scope
+		for j := range s.Groups[i].Selectors {
+			s.Groups[i].Selectors[j] = scopeSelector(s.Groups[i].Selectors[j], scope)
+		}
+	}
+}
+
+// scopeSelector limits the selector to the component with the given name.
+// The compound selectors with :host are limited to the hosts of the
+// component and if the selector doesn't target the host, the element it
+// targets has to be within the template of the component
+func scopeSelector(sel Selector, scope string) Selector {
+	parts := make([]SelectorPart, 0, len(sel.Parts)+2)
+	targetsHost := false
+	for i := range sel.Parts {
+		switch sel.Parts[i].SelectType {
+		case ReadingDescendant, ReadingChild, ReadingSibling, ReadingAdjacent:
+			targetsHost = false
+		case ReadingPseudo, ReadingPseudoFunction:
+			if isHostPseudo(sel.Parts[i].Name) {
+				parts = append(parts, scopeCondition(ComponentHostAttribute, scope)...)
+				targetsHost = true
+			}
+		}
+		parts = append(parts, sel.Parts[i])
+	}
+	if !targetsHost {
+		parts = append(parts, scopeCondition(ComponentScopeAttribute, scope)...)
+	}
+	sel.Parts = parts
+	return sel
+}
+
+func isHostPseudo(name string) bool { return name == "host" || name == "host-context" }
+
+func scopeCondition(attribute, scope string) []SelectorPart {
+	return []SelectorPart{
+		{Name: attribute, SelectType: ReadingCondition},
+		{Name: scope, SelectType: ReadingConditionAssignment},
+	}
+}
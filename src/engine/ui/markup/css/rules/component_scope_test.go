@@ -0,0 +1,62 @@
+/******************************************************************************/
+/* component_scope_test.go                                                    */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package rules
+
+import "testing"
+
+const testCSSScoped = `.title span { color: red; }
+:host { display: block; }
+:host(.wide) .title { width: 100%; }
+:host-context(.dark) > .title { color: white; }`
+
+func testHasCondition(parts []SelectorPart, at int, attribute, scope string) bool {
+	return at+1 < len(parts) &&
+		parts[at].SelectType == ReadingCondition && parts[at].Name == attribute &&
+		parts[at+1].SelectType == ReadingConditionAssignment && parts[at+1].Name == scope
+}
+
+func TestParseScoped(t *testing.T) {
+	s := NewStyleSheet()
+	s.Parse(".outside { color: blue; }", dummyWindow{})
+	s.ParseScoped(testCSSScoped, "item-card", dummyWindow{})
+	if len(s.Groups) != 5 {
+		t.Fatalf("expected 5 groups but got %d", len(s.Groups))
+	}
+	if parts := s.Groups[0].Selectors[0].Parts; len(parts) != 1 {
+		t.Fatalf("expected the unscoped selector to be unchanged, got %v", parts)
+	}
+	descendant := s.Groups[1].Selectors[0].Parts
+	if n := len(descendant); n != 5 || !testHasCondition(descendant, n-2, ComponentScopeAttribute, "item-card") {
+		t.Fatalf("expected the target of the selector to be scoped, got %v", descendant)
+	}
+	host := s.Groups[2].Selectors[0].Parts
+	if len(host) != 3 || !testHasCondition(host, 0, ComponentHostAttribute, "item-card") || host[2].Name != "host" {
+		t.Fatalf("expected :host to be limited to the hosts of the component, got %v", host)
+	}
+	hostArg := s.Groups[3].Selectors[0].Parts
+	if !testHasCondition(hostArg, 0, ComponentHostAttribute, "item-card") ||
+		!testHasCondition(hostArg, len(hostArg)-2, ComponentScopeAttribute, "item-card") {
+		t.Fatalf("expected both the host and the target to be scoped, got %v", hostArg)
+	}
+	if len(hostArg[2].Selectors) != 1 {
+		t.Fatal("expected the argument of :host() to be parsed as a selector list")
+	}
+	context := s.Groups[4].Selectors[0].Parts
+	if !testHasCondition(context, 0, ComponentHostAttribute, "item-card") {
+		t.Fatalf("expected :host-context() to be limited to the hosts of the component, got %v", context)
+	}
+}
+
+func TestParseScopedSpecificity(t *testing.T) {
+	s := NewStyleSheet()
+	s.ParseScoped(testCSSScoped, "item-card", dummyWindow{})
+	tests := []Specificity{{0, 1, 1}, {0, 1, 0}, {0, 3, 0}, {0, 3, 0}}
+	for i, want := range tests {
+		if got := s.Groups[i].Selectors[0].Specificity(); got != want {
+			t.Errorf("group %d: expected specificity %v but got %v", i, want, got)
+		}
+	}
+}
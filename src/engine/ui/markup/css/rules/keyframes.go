@@ -0,0 +1,98 @@
+/******************************************************************************/
+/* keyframes.go                                                               */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package rules
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Keyframe is a single step of a @keyframes block, the offset is in the range
+// of [0, 1] where "from" is 0 and "to" is 1
+type Keyframe struct {
+	Offset float32
+	Rules  []Rule
+}
+
+type Keyframes struct {
+	Name   string
+	Frames []Keyframe
+}
+
+// Rule returns the rule for the given property in this frame, the last
+// declaration of the property wins
+func (k *Keyframe) Rule(property string) (Rule, bool) {
+	for i := len(k.Rules) - 1; i >= 0; i-- {
+		if k.Rules[i].Property == property {
+			return k.Rules[i], true
+		}
+	}
+	return Rule{}, false
+}
+
+// Properties returns the unique names of all the properties that are animated
+// by any of the frames
+func (k *Keyframes) Properties() []string {
+	out := []string{}
+	for i := range k.Frames {
+		for j := range k.Frames[i].Rules {
+			if !slices.Contains(out, k.Frames[i].Rules[j].Property) {
+				out = append(out, k.Frames[i].Rules[j].Property)
+			}
+		}
+	}
+	return out
+}
+
+func (k *Keyframes) addFrame(offset float32, rules []Rule) {
+	for i := range k.Frames {
+		if k.Frames[i].Offset == offset {
+			k.Frames[i].Rules = append(k.Frames[i].Rules, CloneRules(rules)...)
+			return
+		}
+	}
+	k.Frames = append(k.Frames, Keyframe{
+		Offset: offset,
+		Rules:  CloneRules(rules),
+	})
+}
+
+func (k *Keyframes) sort() {
+	slices.SortStableFunc(k.Frames, func(a, b Keyframe) int {
+		if a.Offset < b.Offset {
+			return -1
+		} else if a.Offset > b.Offset {
+			return 1
+		}
+		return 0
+	})
+}
+
+func isKeyframesAtRule(name string) bool {
+	name = strings.ToLower(name)
+	return name == "@keyframes" || name == "@-webkit-keyframes"
+}
+
+// keyframeOffset converts a keyframe selector ("from", "to" or a percentage)
+// into an offset in the range [0, 1]
+func keyframeOffset(selector string) (float32, bool) {
+	switch strings.ToLower(selector) {
+	case "from":
+		return 0, true
+	case "to":
+		return 1, true
+	}
+	if !strings.HasSuffix(selector, "%") {
+		return 0, false
+	}
+	pct, err := strconv.ParseFloat(strings.TrimSuffix(selector, "%"), 32)
+	if err != nil || pct < 0 || pct > 100 {
+		return 0, false
+	}
+	return float32(pct / 100), true
+}
@@ -0,0 +1,464 @@
+/******************************************************************************/
+/* media_query.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package rules
+
+import (
+	"strconv"
+	"strings"
+
+	"kaijuengine.com/engine/ui/markup/css/helpers"
+)
+
+// MediaQuery is the condition of an @media or @container block, a group
+// only applies while its query matches
+type MediaQuery struct {
+	// Text is the query as written, such as "screen and (min-width: 600px)"
+	Text string
+	// Container is set for @container queries, which match against the size
+	// of the closest ancestor container named Name (or any if Name is empty)
+	Container bool
+	Name      string
+	list      []mediaQuery
+	// parent is the query of the @import the group was loaded through
+	parent *MediaQuery
+}
+
+// MediaFeatures are the values media features are tested against, for a
+// container query Width and Height are the size of the container
+type MediaFeatures struct {
+	Window        helpers.WindowDimensions
+	Width, Height float32
+	// InlineOnly is set for inline-size containers, their height can't be
+	// queried
+	InlineOnly      bool
+	ReducedMotion   bool
+	DarkColorScheme bool
+}
+
+type mediaQuery struct {
+	not       bool
+	mediaType string
+	cond      *mediaCondition
+}
+
+type mediaCondition struct {
+	// op is "and", "or", "not" or empty for a single feature test
+	op       string
+	children []*mediaCondition
+	feature  mediaFeature
+}
+
+type mediaFeature struct {
+	name string
+	// ops and values are comparisons that read "name op value", a feature
+	// without any is a boolean test
+	ops    []string
+	values []string
+}
+
+// ParseMediaQuery reads the prelude of an @media rule or the media list of
+// an @import
+func ParseMediaQuery(text string) MediaQuery {
+	m := MediaQuery{Text: strings.TrimSpace(text)}
+	tokens := tokenizeMediaQuery(m.Text)
+	for _, part := range splitMediaTokens(tokens) {
+		m.list = append(m.list, parseMediaQueryTokens(part))
+	}
+	return m
+}
+
+// ParseContainerQuery reads the prelude of an @container rule, an optional
+// container name followed by the size condition
+func ParseContainerQuery(text string) MediaQuery {
+	m := MediaQuery{Text: strings.TrimSpace(text), Container: true}
+	tokens := tokenizeMediaQuery(m.Text)
+	if len(tokens) > 0 && tokens[0] != "(" && tokens[0] != "not" {
+		m.Name, tokens = tokens[0], tokens[1:]
+	}
+	p := mediaParser{tokens: tokens}
+	m.list = []mediaQuery{{cond: p.condition()}}
+	return m
+}
+
+func (m *MediaQuery) IsValid() bool { return m.Text != "" }
+
+func (m *MediaQuery) Clear() { *m = MediaQuery{} }
+
+// Within returns the query limited to also match the parent query
+func (m MediaQuery) Within(parent MediaQuery) MediaQuery {
+	if !parent.IsValid() {
+		return m
+	}
+	if !m.IsValid() {
+		return parent
+	}
+	if m.parent != nil {
+		p := m.parent.Within(parent)
+		m.parent = &p
+	} else {
+		m.parent = &parent
+	}
+	return m
+}
+
+// MatchesViewport reports if the query matches the window, container
+// queries only check the media of the @import they were loaded through
+func (m *MediaQuery) MatchesViewport(features MediaFeatures) bool {
+	if m.parent != nil && !m.parent.MatchesViewport(features) {
+		return false
+	}
+	return m.Container || m.matches(features)
+}
+
+// MatchesContainer reports if a container query matches the size of the
+// container given in the features, it is always true for other queries
+func (m *MediaQuery) MatchesContainer(features MediaFeatures) bool {
+	return !m.Container || m.matches(features)
+}
+
+func (m *MediaQuery) matches(features MediaFeatures) bool {
+	if !m.IsValid() {
+		return true
+	}
+	for i := range m.list {
+		q := &m.list[i]
+		match := true
+		switch q.mediaType {
+		case "", "all", "screen":
+		default:
+			match = false
+		}
+		if match && q.cond != nil {
+			match = q.cond.matches(features)
+		}
+		if match != q.not {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *mediaCondition) matches(f MediaFeatures) bool {
+	switch c.op {
+	case "not":
+		return !c.children[0].matches(f)
+	case "and":
+		for _, child := range c.children {
+			if !child.matches(f) {
+				return false
+			}
+		}
+		return true
+	case "or":
+		for _, child := range c.children {
+			if child.matches(f) {
+				return true
+			}
+		}
+		return false
+	}
+	return c.feature.matches(f)
+}
+
+func (m mediaFeature) matches(f MediaFeatures) bool {
+	name, ops, values := m.name, m.ops, m.values
+	if strings.HasPrefix(name, "min-") && len(ops) == 1 && ops[0] == ":" {
+		name, ops = name[4:], []string{">="}
+	} else if strings.HasPrefix(name, "max-") && len(ops) == 1 && ops[0] == ":" {
+		name, ops = name[4:], []string{"<="}
+	}
+	var discrete string
+	switch name {
+	case "orientation":
+		discrete = "landscape"
+		if f.Height > f.Width {
+			discrete = "portrait"
+		}
+	case "prefers-reduced-motion":
+		discrete = "no-preference"
+		if f.ReducedMotion {
+			discrete = "reduce"
+		}
+	case "prefers-color-scheme":
+		discrete = "light"
+		if f.DarkColorScheme {
+			discrete = "dark"
+		}
+	case "hover", "any-hover":
+		discrete = "hover"
+	case "pointer", "any-pointer":
+		discrete = "fine"
+	}
+	if discrete != "" {
+		if len(ops) == 0 {
+			return discrete != "none" && discrete != "no-preference"
+		}
+		return len(ops) == 1 && ops[0] == ":" && values[0] == discrete
+	}
+	var actual float32
+	switch name {
+	case "width", "inline-size":
+		actual = f.Width
+	case "height", "block-size":
+		if f.InlineOnly {
+			return false
+		}
+		actual = f.Height
+	case "aspect-ratio":
+		if f.InlineOnly || f.Height <= 0 {
+			return false
+		}
+		actual = f.Width / f.Height
+	case "resolution":
+		if f.Window == nil {
+			return false
+		}
+		actual = float32(f.Window.DotsPerMillimeter() * 25.4)
+	default:
+		return false
+	}
+	if len(ops) == 0 {
+		return actual != 0
+	}
+	for i, op := range ops {
+		want, ok := mediaValue(name, values[i], f.Window)
+		if !ok {
+			return false
+		}
+		var pass bool
+		switch op {
+		case ":", "=":
+			pass = actual == want
+		case "<":
+			pass = actual < want
+		case "<=":
+			pass = actual <= want
+		case ">":
+			pass = actual > want
+		case ">=":
+			pass = actual >= want
+		}
+		if !pass {
+			return false
+		}
+	}
+	return true
+}
+
+// mediaValue converts the value of a feature into pixels, a ratio or dots
+// per inch depending on the feature
+func mediaValue(name, value string, window helpers.WindowDimensions) (float32, bool) {
+	switch name {
+	case "aspect-ratio":
+		num, den, found := strings.Cut(value, "/")
+		n, err := strconv.ParseFloat(strings.TrimSpace(num), 32)
+		if err != nil {
+			return 0, false
+		}
+		d := 1.0
+		if found {
+			if d, err = strconv.ParseFloat(strings.TrimSpace(den), 32); err != nil || d == 0 {
+				return 0, false
+			}
+		}
+		return float32(n / d), true
+	case "resolution":
+		// dppx must be checked before its x alias
+		units := []struct {
+			suffix string
+			scale  float64
+		}{{"dpi", 1}, {"dpcm", 2.54}, {"dppx", 96}, {"x", 96}}
+		for _, u := range units {
+			if strings.HasSuffix(value, u.suffix) {
+				v, err := strconv.ParseFloat(strings.TrimSuffix(value, u.suffix), 32)
+				return float32(v * u.scale), err == nil
+			}
+		}
+		return 0, false
+	}
+	if value == "0" {
+		return 0, true
+	}
+	if window == nil {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(value, "px"), 32)
+		return float32(v), err == nil
+	}
+	return helpers.NumFromLength(value, window), true
+}
+
+func tokenizeMediaQuery(text string) []string {
+	var tokens []string
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '(' || c == ')' || c == ',' || c == ':' || c == '/' || c == '=':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '<' || c == '>':
+			if i+1 < len(text) && text[i+1] == '=' {
+				tokens = append(tokens, text[i:i+2])
+				i += 2
+			} else {
+				tokens = append(tokens, string(c))
+				i++
+			}
+		default:
+			end := i
+			for end < len(text) && !strings.ContainsRune(" \t\r\n(),:/=<>", rune(text[end])) {
+				end++
+			}
+			tokens = append(tokens, strings.ToLower(text[i:end]))
+			i = end
+		}
+	}
+	return tokens
+}
+
+// splitMediaTokens splits the tokens on the top level commas of a media
+// query list
+func splitMediaTokens(tokens []string) [][]string {
+	out := [][]string{}
+	depth, start := 0, 0
+	for i, t := range tokens {
+		switch t {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				out = append(out, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(out, tokens[start:])
+}
+
+func parseMediaQueryTokens(tokens []string) mediaQuery {
+	q := mediaQuery{}
+	if len(tokens) > 0 && tokens[0] != "(" {
+		if tokens[0] == "not" || tokens[0] == "only" {
+			q.not = tokens[0] == "not"
+			tokens = tokens[1:]
+		}
+		if len(tokens) > 0 && tokens[0] != "(" {
+			q.mediaType, tokens = tokens[0], tokens[1:]
+			if len(tokens) > 0 && tokens[0] == "and" {
+				tokens = tokens[1:]
+			}
+		}
+	}
+	if len(tokens) > 0 {
+		p := mediaParser{tokens: tokens}
+		q.cond = p.condition()
+	}
+	return q
+}
+
+type mediaParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *mediaParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *mediaParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *mediaParser) condition() *mediaCondition {
+	if p.peek() == "not" {
+		p.next()
+		return &mediaCondition{op: "not", children: []*mediaCondition{p.inParens()}}
+	}
+	first := p.inParens()
+	op := p.peek()
+	if op != "and" && op != "or" {
+		return first
+	}
+	c := &mediaCondition{op: op, children: []*mediaCondition{first}}
+	for p.peek() == op {
+		p.next()
+		c.children = append(c.children, p.inParens())
+	}
+	return c
+}
+
+// inParens reads a parenthesized condition or feature test, anything it
+// can't read becomes a feature that never matches
+func (p *mediaParser) inParens() *mediaCondition {
+	if p.next() != "(" {
+		return &mediaCondition{}
+	}
+	if t := p.peek(); t == "(" || t == "not" {
+		c := p.condition()
+		p.next()
+		return c
+	}
+	var inner []string
+	for depth := 0; p.pos < len(p.tokens); {
+		t := p.next()
+		if t == "(" {
+			depth++
+		} else if t == ")" {
+			if depth == 0 {
+				break
+			}
+			depth--
+		}
+		inner = append(inner, t)
+	}
+	return &mediaCondition{feature: parseMediaFeature(inner)}
+}
+
+var flippedMediaOps = map[string]string{"<": ">", "<=": ">=", ">": "<", ">=": "<=", "=": "="}
+
+func parseMediaFeature(tokens []string) mediaFeature {
+	// Values such as 16/9 are split by the tokenizer, they are joined back
+	// together between the operators
+	var parts []string
+	var ops []string
+	current := ""
+	for _, t := range tokens {
+		if _, isOp := flippedMediaOps[t]; isOp || t == ":" {
+			parts, ops, current = append(parts, current), append(ops, t), ""
+		} else {
+			current += t
+		}
+	}
+	parts = append(parts, current)
+	switch {
+	case len(ops) == 0 && len(parts) == 1:
+		return mediaFeature{name: parts[0]}
+	case len(ops) == 1 && ops[0] == ":":
+		return mediaFeature{name: parts[0], ops: ops, values: parts[1:]}
+	case len(ops) == 1 && isMediaFeatureName(parts[0]):
+		return mediaFeature{name: parts[0], ops: ops, values: parts[1:]}
+	case len(ops) == 1:
+		return mediaFeature{name: parts[1], ops: []string{flippedMediaOps[ops[0]]}, values: parts[:1]}
+	case len(ops) == 2:
+		// value < name < value
+		return mediaFeature{name: parts[1],
+			ops:    []string{flippedMediaOps[ops[0]], ops[1]},
+			values: []string{parts[0], parts[2]}}
+	}
+	return mediaFeature{name: "invalid", ops: []string{"="}, values: []string{""}}
+}
+
+func isMediaFeatureName(s string) bool {
+	return s != "" && (s[0] < '0' || s[0] > '9') && s[0] != '.' && s[0] != '-'
+}
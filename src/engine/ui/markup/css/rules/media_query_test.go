@@ -0,0 +1,112 @@
+/******************************************************************************/
+/* media_query_test.go                                                        */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package rules
+
+import "testing"
+
+type testMediaWindow struct{ w, h int }
+
+func (t testMediaWindow) DotsPerMillimeter() float64 { return 96 / 25.4 }
+func (t testMediaWindow) Width() int                 { return t.w }
+func (t testMediaWindow) Height() int                { return t.h }
+
+func testViewport(w, h int) MediaFeatures {
+	return MediaFeatures{
+		Window: testMediaWindow{w, h},
+		Width:  float32(w),
+		Height: float32(h),
+	}
+}
+
+func TestMediaQueryMatchesViewport(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"screen", true},
+		{"all and (min-width: 600px)", true},
+		{"print", false},
+		{"not print", true},
+		{"only screen and (max-width: 600px)", false},
+		{"(width >= 800px)", true},
+		{"(400px < width < 800px)", false},
+		{"(400px < width <= 800px)", true},
+		{"(orientation: landscape)", true},
+		{"(orientation: portrait)", false},
+		{"(aspect-ratio: 4/3)", true},
+		{"(min-aspect-ratio: 16/9)", false},
+		{"(resolution: 1dppx)", true},
+		{"(min-resolution: 2x)", false},
+		{"(resolution: 96dpi)", true},
+		{"(prefers-reduced-motion: reduce)", false},
+		{"(prefers-color-scheme: light)", true},
+		{"(hover: hover) and (pointer: fine)", true},
+		{"(max-width: 100px), (min-height: 500px)", true},
+		{"(max-width: 100px) or (min-height: 700px)", false},
+		{"not (max-width: 100px)", true},
+		{"screen and (not (min-width: 600px))", false},
+		{"(unknown-feature: 1)", false},
+	}
+	f := testViewport(800, 600)
+	for _, test := range tests {
+		q := ParseMediaQuery(test.query)
+		if got := q.MatchesViewport(f); got != test.want {
+			t.Errorf("%q: expected %v but got %v", test.query, test.want, got)
+		}
+	}
+}
+
+func TestMediaQueryPreferences(t *testing.T) {
+	f := testViewport(800, 600)
+	f.ReducedMotion = true
+	f.DarkColorScheme = true
+	for _, query := range []string{"(prefers-reduced-motion)",
+		"(prefers-reduced-motion: reduce)", "(prefers-color-scheme: dark)"} {
+		q := ParseMediaQuery(query)
+		if !q.MatchesViewport(f) {
+			t.Errorf("%q: expected to match", query)
+		}
+	}
+}
+
+func TestContainerQuery(t *testing.T) {
+	q := ParseContainerQuery("sidebar (min-width: 300px)")
+	if !q.Container || q.Name != "sidebar" {
+		t.Fatalf("expected a container query named sidebar, got %+v", q)
+	}
+	if !q.MatchesViewport(testViewport(100, 100)) {
+		t.Error("container queries should not be filtered by the viewport")
+	}
+	if !q.MatchesContainer(MediaFeatures{Width: 320, Height: 50}) {
+		t.Error("expected a 320px container to match")
+	}
+	if q.MatchesContainer(MediaFeatures{Width: 200, Height: 50}) {
+		t.Error("expected a 200px container to not match")
+	}
+	anon := ParseContainerQuery("(height > 100px)")
+	if anon.Name != "" {
+		t.Errorf("expected no container name, got %q", anon.Name)
+	}
+	if anon.MatchesContainer(MediaFeatures{Width: 500, Height: 500, InlineOnly: true}) {
+		t.Error("the height of an inline-size container can't be queried")
+	}
+}
+
+func TestMediaQueryWithin(t *testing.T) {
+	inner := ParseMediaQuery("(min-width: 500px)")
+	q := inner.Within(ParseMediaQuery("(max-width: 700px)"))
+	if q.MatchesViewport(testViewport(800, 600)) {
+		t.Error("expected the parent query to exclude the viewport")
+	}
+	if !q.MatchesViewport(testViewport(600, 600)) {
+		t.Error("expected both queries to match")
+	}
+	empty := MediaQuery{}
+	if w := empty.Within(inner); w.Text != inner.Text {
+		t.Errorf("expected an empty query to become its parent, got %q", w.Text)
+	}
+}
@@ -8,6 +8,7 @@ package rules
 
 import (
 	"bytes"
+	"log/slog"
 	"slices"
 	"strings"
 
@@ -18,10 +19,27 @@ import (
 )
 
 type StyleSheet struct {
-	Groups         []SelectorGroup
-	CustomVars     map[string][]string
+	Groups     []SelectorGroup
+	CustomVars map[string][]string
+	Keyframes  map[string]Keyframes
+	// ImportResolver reads the CSS of an @import, imports are skipped when
+	// it is not set
+	ImportResolver func(path string) (string, error)
 	state          RuleState
 	stateFuncDepth int
+	keyframes      *Keyframes
+	frameOffsets   []float32
+	pending        *Selector
+	pendingDepth   int
+	container      *containerBlock
+}
+
+// containerBlock collects the body of an @container rule, the CSS parser
+// only reads nested rules for the at-rules it knows so the body is read as
+// a style sheet of its own once the block ends
+type containerBlock struct {
+	query MediaQuery
+	body  strings.Builder
 }
 
 // varRefSentinel prefixes a deferred custom-property reference that is stored
@@ -61,6 +79,102 @@ func (s *StyleSheet) clearGroupMediaQuery() {
 	s.Groups[len(s.Groups)-1].MediaQuery.Clear()
 }
 
+func preludeText(values []css.Token) string {
+	sb := strings.Builder{}
+	for _, val := range values {
+		sb.Write(val.Data)
+	}
+	return sb.String()
+}
+
+// atRuleQuery creates the query for the groups within an at-rule block,
+// blocks of unsupported at-rules get a query that never matches
+func atRuleQuery(name string, values []css.Token) MediaQuery {
+	text := preludeText(values)
+	switch name {
+	case "@media":
+		return ParseMediaQuery(text)
+	case "@container":
+		return ParseContainerQuery(text)
+	}
+	return MediaQuery{Text: name + " " + text, list: []mediaQuery{{mediaType: name}}}
+}
+
+func (s *StyleSheet) endContainer(window helpers.WindowDimensions) {
+	block := s.container
+	s.container = nil
+	nested := StyleSheet{
+		Groups:         make([]SelectorGroup, 0),
+		state:          ReadingTag,
+		CustomVars:     s.CustomVars,
+		Keyframes:      s.Keyframes,
+		ImportResolver: s.ImportResolver,
+	}
+	nested.read(block.body.String(), window)
+	nested.removeLastGroup()
+	for i := range nested.Groups {
+		// Media queries nested in the container still filter by the viewport
+		nested.Groups[i].MediaQuery = block.query.Within(nested.Groups[i].MediaQuery)
+	}
+	s.insertGroups(nested.Groups)
+}
+
+// insertGroups adds the groups before the group currently being read
+func (s *StyleSheet) insertGroups(groups []SelectorGroup) {
+	current := s.Groups[len(s.Groups)-1]
+	s.Groups = append(append(s.Groups[:len(s.Groups)-1], groups...), current)
+}
+
+// readImport parses the imported style sheet in place, its groups are added
+// before the group being read so they lose to the rules of this sheet
+func (s *StyleSheet) readImport(values []css.Token, window helpers.WindowDimensions) {
+	if s.ImportResolver == nil {
+		return
+	}
+	path := ""
+	rest := values
+	for i, val := range values {
+		switch val.TokenType {
+		case css.WhitespaceToken:
+			continue
+		case css.StringToken:
+			path = strings.Trim(string(val.Data), `"'`)
+		case css.URLToken:
+			path = strings.TrimSuffix(strings.TrimPrefix(string(val.Data), "url("), ")")
+			path = strings.Trim(strings.TrimSpace(path), `"'`)
+		case css.FunctionToken:
+			// url("path") is a function token followed by a string token
+			continue
+		}
+		if path != "" {
+			rest = values[i+1:]
+			break
+		}
+	}
+	if path == "" {
+		return
+	}
+	src, err := s.ImportResolver(path)
+	if err != nil {
+		slog.Error("failed to read the imported style sheet", "path", path, "error", err)
+		return
+	}
+	imported := NewStyleSheet()
+	imported.ImportResolver = s.ImportResolver
+	imported.Parse(src, window)
+	media := ParseMediaQuery(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(preludeText(rest)), ")")))
+	for i := range imported.Groups {
+		imported.Groups[i].MediaQuery = imported.Groups[i].MediaQuery.Within(media)
+	}
+	s.insertGroups(imported.Groups)
+	for k, v := range imported.CustomVars {
+		s.CustomVars[k] = v
+	}
+	for k, v := range imported.Keyframes {
+		s.Keyframes[k] = v
+	}
+}
+
 func (s *StyleSheet) removeLastGroup() {
 	s.Groups = s.Groups[:len(s.Groups)-1]
 }
@@ -69,7 +183,11 @@ func (s *StyleSheet) currentGroup() *SelectorGroup {
 	return &s.Groups[len(s.Groups)-1]
 }
 
-func (s *StyleSheet) readSelector(cssParser *css.Parser) {
+// readSelector reads the selector and returns true if it was completed. The
+// tokenizer ends a selector on any comma, including one within a pseudo-class
+// function such as :is(.a, .b), in that case the selector is held onto so the
+// next call continues reading it
+func (s *StyleSheet) readSelector(cssParser *css.Parser) bool {
 	sel := Selector{
 		Parts: make([]SelectorPart, 0),
 	}
@@ -92,6 +210,11 @@ func (s *StyleSheet) readSelector(cssParser *css.Parser) {
 		}
 	}
 	pseudoFunctionDepth := 0
+	if s.pending != nil {
+		sel = *s.pending
+		pseudoFunctionDepth = s.pendingDepth
+		s.pending = nil
+	}
 	appendPseudoArg := func(data string) bool {
 		if pseudoFunctionDepth == 0 {
 			return false
@@ -100,8 +223,16 @@ func (s *StyleSheet) readSelector(cssParser *css.Parser) {
 		sel.Parts[idx].Args = append(sel.Parts[idx].Args, data)
 		return true
 	}
+	// colons counts the colons directly before the current token so that
+	// pseudo-elements (::before) can be told apart from pseudo-classes
+	colons := 0
 	for _, val := range cssParser.Values() {
+		lastColon := colons
+		colons = 0
 		switch val.TokenType {
+		case css.DimensionToken, css.PercentageToken:
+			// Only valid as arguments, such as the 2n of :nth-of-type(2n+1)
+			appendPseudoArg(string(val.Data))
 		case css.IdentToken:
 			fallthrough
 		case css.StringToken:
@@ -110,12 +241,17 @@ func (s *StyleSheet) readSelector(cssParser *css.Parser) {
 			if appendPseudoArg(string(val.Data)) {
 			} else {
 				d := string(val.Data)
+				selectType := s.state
 				if s.state == ReadingConditionAssignment {
 					d = strings.Trim(d, `"`)
+				} else if s.state == ReadingPseudo && lastColon > 1 {
+					selectType = ReadingPseudoElement
+				} else if _, ok := legacyPseudoElements[d]; ok && s.state == ReadingPseudo {
+					selectType = ReadingPseudoElement
 				}
 				sel.Parts = append(sel.Parts, SelectorPart{
 					Name:       d,
-					SelectType: s.state,
+					SelectType: selectType,
 				})
 			}
 		case css.HashToken:
@@ -131,6 +267,7 @@ func (s *StyleSheet) readSelector(cssParser *css.Parser) {
 			if appendPseudoArg(":") {
 			} else {
 				s.state = ReadingPseudo
+				colons = lastColon + 1
 			}
 		case css.FunctionToken:
 			name := strings.TrimSuffix(string(val.Data), "(")
@@ -183,11 +320,19 @@ func (s *StyleSheet) readSelector(cssParser *css.Parser) {
 					appendCombinator(ReadingChild, ">")
 					s.state = ReadingTag
 				case "~":
-					s.state = ReadingSibling
+					appendCombinator(ReadingSibling, "~")
+					s.state = ReadingTag
 				case "+":
-					s.state = ReadingAdjacent
+					appendCombinator(ReadingAdjacent, "+")
+					s.state = ReadingTag
+				case "*":
+					sel.Parts = append(sel.Parts, SelectorPart{
+						Name:       "*",
+						SelectType: ReadingTag,
+					})
 				case ":":
 					s.state = ReadingPseudo
+					colons = lastColon + 1
 				case "=":
 					if s.state == ReadingCondition {
 						s.state = ReadingConditionAssignment
@@ -196,8 +341,23 @@ func (s *StyleSheet) readSelector(cssParser *css.Parser) {
 			}
 		}
 	}
+	if pseudoFunctionDepth > 0 {
+		appendPseudoArg(",")
+		s.pending = &sel
+		s.pendingDepth = pseudoFunctionDepth
+		return false
+	}
+	for i := range sel.Parts {
+		if sel.Parts[i].SelectType != ReadingPseudoFunction {
+			continue
+		}
+		if _, ok := selectorListPseudos[sel.Parts[i].Name]; ok {
+			sel.Parts[i].Selectors = ParseSelectorList(sel.Parts[i].Args)
+		}
+	}
 	idx := len(s.Groups) - 1
 	s.Groups[idx].Selectors = append(s.Groups[idx].Selectors, sel)
+	return true
 }
 
 func (s *StyleSheet) readProperty(prop string, cssParser *css.Parser, _ helpers.WindowDimensions) {
@@ -205,15 +365,19 @@ func (s *StyleSheet) readProperty(prop string, cssParser *css.Parser, _ helpers.
 		Property: prop,
 		Values:   make([]PropertyValue, 0),
 	}
+	separated := false
 	for _, val := range cssParser.Values() {
 		switch val.TokenType {
 		case css.FunctionToken:
 			s.stateFuncDepth++
 			s.state = ReadingPropertyFunction
 			r.Values = append(r.Values, PropertyValue{
-				Str: strings.TrimSuffix(string(val.Data), "("),
+				Str:       strings.TrimSuffix(string(val.Data), "("),
+				Separated: separated,
 			})
+			separated = false
 		case css.CommaToken:
+			separated = s.stateFuncDepth == 0
 		case css.CommentToken:
 		case css.WhitespaceToken:
 		case css.RightParenthesisToken:
@@ -242,7 +406,8 @@ func (s *StyleSheet) readProperty(prop string, cssParser *css.Parser, _ helpers.
 						// Top-level var(): record a deferred placeholder value
 						// that will expand into zero or more values later.
 						r.Values = append(r.Values, PropertyValue{
-							Str: makeVarRef(str),
+							Str:       makeVarRef(str),
+							Separated: last.Separated,
 						})
 					}
 				} else {
@@ -250,8 +415,10 @@ func (s *StyleSheet) readProperty(prop string, cssParser *css.Parser, _ helpers.
 				}
 			} else {
 				r.Values = append(r.Values, PropertyValue{
-					Str: string(val.Data),
+					Str:       string(val.Data),
+					Separated: separated,
 				})
+				separated = false
 			}
 		}
 	}
@@ -271,6 +438,14 @@ func (s *StyleSheet) resolveVars(window helpers.WindowDimensions) {
 			s.resolveRuleVars(&g.Rules[ri], window)
 		}
 	}
+	for name, k := range s.Keyframes {
+		for fi := range k.Frames {
+			for ri := range k.Frames[fi].Rules {
+				s.resolveRuleVars(&k.Frames[fi].Rules[ri], window)
+			}
+		}
+		s.Keyframes[name] = k
+	}
 }
 
 // resolveRuleVars substitutes deferred var references in a single rule and then
@@ -283,8 +458,11 @@ func (s *StyleSheet) resolveRuleVars(r *Rule, window helpers.WindowDimensions) {
 	for i := range r.Values {
 		v := r.Values[i]
 		if name, ok := parseVarRef(v.Str); ok {
-			for _, sub := range s.CustomVars[name] {
-				resolved = append(resolved, PropertyValue{Str: sub})
+			for j, sub := range s.CustomVars[name] {
+				resolved = append(resolved, PropertyValue{
+					Str:       sub,
+					Separated: j == 0 && v.Separated,
+				})
 			}
 			continue
 		}
@@ -318,15 +496,64 @@ func (s *StyleSheet) resolveRuleVars(r *Rule, window helpers.WindowDimensions) {
 	}
 }
 
+func (s *StyleSheet) beginKeyframes(cssParser *css.Parser) {
+	s.keyframes = &Keyframes{Frames: make([]Keyframe, 0)}
+	for _, val := range cssParser.Values() {
+		switch val.TokenType {
+		case css.IdentToken, css.StringToken:
+			s.keyframes.Name = strings.Trim(string(val.Data), `"'`)
+		}
+	}
+}
+
+func (s *StyleSheet) readKeyframeOffsets(cssParser *css.Parser) {
+	for _, val := range cssParser.Values() {
+		if offset, ok := keyframeOffset(string(val.Data)); ok {
+			s.frameOffsets = append(s.frameOffsets, offset)
+		}
+	}
+}
+
+// endKeyframe moves the declarations that were read into the current group
+// over to each of the offsets that the keyframe selector listed
+func (s *StyleSheet) endKeyframe() {
+	g := s.currentGroup()
+	for _, offset := range s.frameOffsets {
+		s.keyframes.addFrame(offset, g.Rules)
+	}
+	g.Rules = g.Rules[:0]
+	s.frameOffsets = s.frameOffsets[:0]
+}
+
+func (s *StyleSheet) endKeyframes() {
+	if s.keyframes.Name != "" {
+		if s.Keyframes == nil {
+			s.Keyframes = make(map[string]Keyframes)
+		}
+		s.keyframes.sort()
+		s.Keyframes[s.keyframes.Name] = *s.keyframes
+	}
+	s.keyframes = nil
+}
+
 func NewStyleSheet() StyleSheet {
 	return StyleSheet{
 		Groups:     make([]SelectorGroup, 0),
 		state:      ReadingTag,
 		CustomVars: make(map[string][]string),
+		Keyframes:  make(map[string]Keyframes),
 	}
 }
 
 func (s *StyleSheet) Parse(cssStr string, window helpers.WindowDimensions) {
+	s.read(cssStr, window)
+	// All custom properties are now known with their final (last :root wins)
+	// values; substitute deferred var references and compute numeric forms.
+	s.resolveVars(window)
+	s.removeLastGroup()
+}
+
+func (s *StyleSheet) read(cssStr string, window helpers.WindowDimensions) {
 	cssParser := css.NewParser(parse.NewInput(bytes.NewBufferString(cssStr)), false)
 	exit := false
 	s.addGroup()
@@ -339,41 +566,58 @@ func (s *StyleSheet) Parse(cssStr string, window helpers.WindowDimensions) {
 		case css.CommentGrammar:
 			// Do nothing
 		case css.BeginAtRuleGrammar:
-			q := MediaQuery{}
-			for _, val := range cssParser.Values() {
-				if val.TokenType == css.WhitespaceToken {
-					continue
-				}
-				v := string(val.Data)
-				switch v {
-				case "(", ":", ")":
-				default:
-					if q.Key == "" {
-						q.Key = v
-					} else {
-						q.Value = v
-					}
+			if isKeyframesAtRule(string(propData)) {
+				s.beginKeyframes(cssParser)
+				continue
+			}
+			if string(propData) == "@container" {
+				s.container = &containerBlock{
+					query: atRuleQuery(string(propData), cssParser.Values()),
 				}
+				continue
 			}
-			s.setGroupMediaQuery(q)
+			s.setGroupMediaQuery(atRuleQuery(string(propData), cssParser.Values()))
 		case css.AtRuleGrammar:
+			if string(propData) == "@import" {
+				s.readImport(cssParser.Values(), window)
+			}
 		case css.QualifiedRuleGrammar:
+			if s.keyframes != nil {
+				s.readKeyframeOffsets(cssParser)
+				continue
+			}
 			if qualifiedGroupStart < 0 {
 				qualifiedGroupStart = len(s.Groups) - 1
 			}
-			if s.state < ReadingProperty {
-				s.readSelector(cssParser)
+			if s.state < ReadingProperty && !s.readSelector(cssParser) {
+				continue
 			}
 			s.addGroup()
 		case css.BeginRulesetGrammar:
-			s.readSelector(cssParser)
+			if s.keyframes != nil {
+				s.readKeyframeOffsets(cssParser)
+			} else {
+				s.readSelector(cssParser)
+			}
 			s.state = ReadingProperty
 		case css.EndAtRuleGrammar:
+			if s.container != nil {
+				s.endContainer(window)
+				continue
+			}
 			s.state = ReadingTag
+			if s.keyframes != nil {
+				s.endKeyframes()
+				continue
+			}
 			s.addGroup()
 			s.clearGroupMediaQuery()
 		case css.EndRulesetGrammar:
 			s.state = ReadingTag
+			if s.keyframes != nil {
+				s.endKeyframe()
+				continue
+			}
 			if qualifiedGroupStart >= 0 {
 				last := &s.Groups[len(s.Groups)-1]
 				for i := len(s.Groups) - 2; i >= qualifiedGroupStart; i-- {
@@ -388,6 +632,9 @@ func (s *StyleSheet) Parse(cssStr string, window helpers.WindowDimensions) {
 		case css.DeclarationGrammar:
 			s.readProperty(string(propData), cssParser, window)
 		case css.TokenGrammar:
+			if s.container != nil {
+				s.container.body.Write(propData)
+			}
 		case css.CustomPropertyGrammar:
 			name := string(propData)
 			vals := make([]string, 0)
@@ -397,10 +644,14 @@ func (s *StyleSheet) Parse(cssStr string, window helpers.WindowDimensions) {
 			s.CustomVars[name] = vals
 		}
 	}
-	// All custom properties are now known with their final (last :root wins)
-	// values; substitute deferred var references and compute numeric forms.
-	s.resolveVars(window)
-	s.removeLastGroup()
+}
+
+// MarkUserAgent flags all of the groups that have been parsed so far as the
+// default styles of the engine
+func (s *StyleSheet) MarkUserAgent() {
+	for i := range s.Groups {
+		s.Groups[i].UserAgent = true
+	}
 }
 
 func (s *StyleSheet) ParseInline(cssStr string, window helpers.WindowDimensions) *SelectorGroup {
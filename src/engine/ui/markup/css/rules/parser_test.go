@@ -39,6 +39,15 @@ const testCSSVarMultiToken = `:root { --pad: 1px 2px 3px 4px; }
 const testCSSVarInCalcLaterRoot = `.test { height: calc(100% - var(--h)); }
 :root { --h: 24px; }`
 
+const testCSSKeyframes = `@keyframes fade {
+	from { opacity: 0; }
+	50%, 75% { opacity: 0.5; color: red; }
+	to { opacity: 1; }
+}
+.test { animation: fade 1s ease-in, slide 2s; }`
+
+const testCSSIsList = `:is(.a, .b) > span, p ~ :where(#c, .d) { display: none; }`
+
 type dummyWindow struct{}
 
 func (dummyWindow) DotsPerMillimeter() float64 { return 1 }
@@ -398,3 +407,212 @@ func TestParseVariableInCalcLaterRoot(t *testing.T) {
 		}
 	}
 }
+
+func TestParseKeyframes(t *testing.T) {
+	s := NewStyleSheet()
+	s.Parse(testCSSKeyframes, dummyWindow{})
+	k, ok := s.Keyframes["fade"]
+	if !ok {
+		t.Fatal("expected the fade keyframes to be parsed")
+	}
+	expectedOffsets := []float32{0, 0.5, 0.75, 1}
+	if len(k.Frames) != len(expectedOffsets) {
+		t.Fatalf("expected %d frames, got %d", len(expectedOffsets), len(k.Frames))
+	}
+	for i := range expectedOffsets {
+		if k.Frames[i].Offset != expectedOffsets[i] {
+			t.Fatalf("frame %d expected offset %f, got %f", i, expectedOffsets[i], k.Frames[i].Offset)
+		}
+	}
+	if r, ok := k.Frames[2].Rule("opacity"); !ok || r.Values[0].Str != "0.5" {
+		t.Fatalf("expected opacity 0.5 at 75%%, got %#v", r)
+	}
+	if _, ok := k.Frames[2].Rule("color"); !ok {
+		t.Fatal("expected color at 75%")
+	}
+	if props := k.Properties(); len(props) != 2 {
+		t.Fatalf("expected 2 animated properties, got %v", props)
+	}
+	// The keyframe declarations must not leak into the selector groups
+	if len(s.Groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(s.Groups))
+	}
+	g := s.Groups[0]
+	if len(g.Rules) != 1 || g.Rules[0].Property != "animation" {
+		t.Fatalf("expected only the animation rule, got %#v", g.Rules)
+	}
+	if len(g.Selectors) != 1 || g.Selectors[0].Parts[0].Name != "test" {
+		t.Fatalf("unexpected selectors %#v", g.Selectors)
+	}
+}
+
+func TestParseCommaSeparatedValues(t *testing.T) {
+	s := NewStyleSheet()
+	s.Parse(testCSSKeyframes, dummyWindow{})
+	list := SplitValueList(s.Groups[0].Rules[0].Values)
+	if len(list) != 2 {
+		t.Fatalf("expected 2 list items, got %d", len(list))
+	}
+	if len(list[0]) != 3 || list[0][0].Str != "fade" {
+		t.Fatalf("unexpected first item %#v", list[0])
+	}
+	if len(list[1]) != 2 || list[1][0].Str != "slide" {
+		t.Fatalf("unexpected second item %#v", list[1])
+	}
+}
+
+func TestParseSelectorListPseudo(t *testing.T) {
+	s := NewStyleSheet()
+	s.Parse(testCSSIsList, dummyWindow{})
+	// Each selector of the comma separated list gets its own group, the
+	// commas inside of :is and :where must not split the selector
+	if len(s.Groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(s.Groups))
+	}
+	sels := []Selector{s.Groups[0].Selectors[0], s.Groups[1].Selectors[0]}
+	is := sels[0].Parts[0]
+	if is.Name != "is" || len(is.Selectors) != 2 {
+		t.Fatalf("expected :is with 2 selectors, got %#v", is)
+	}
+	if is.Selectors[0].Parts[0].Name != "a" || is.Selectors[1].Parts[0].Name != "b" {
+		t.Fatalf("unexpected :is selectors %#v", is.Selectors)
+	}
+	if sels[0].Parts[1].SelectType != ReadingChild || sels[0].Parts[2].Name != "span" {
+		t.Fatalf("unexpected parts after :is %#v", sels[0].Parts)
+	}
+	if sels[1].Parts[0].Name != "p" || sels[1].Parts[1].SelectType != ReadingSibling {
+		t.Fatalf("expected a sibling combinator, got %#v", sels[1].Parts)
+	}
+	if where := sels[1].Parts[2]; where.Name != "where" || len(where.Selectors) != 2 {
+		t.Fatalf("expected :where with 2 selectors, got %#v", where)
+	}
+}
+
+func TestParseSelectorListRelative(t *testing.T) {
+	list := ParseSelectorList([]string{">", "img", ",", " ", ".", "caption"})
+	if len(list) != 2 {
+		t.Fatalf("expected 2 selectors, got %d", len(list))
+	}
+	if list[0].Parts[0].SelectType != ReadingChild || list[0].Parts[1].Name != "img" {
+		t.Fatalf("unexpected relative selector %#v", list[0].Parts)
+	}
+	if list[1].Parts[0].SelectType != ReadingClass || list[1].Parts[0].Name != "caption" {
+		t.Fatalf("unexpected selector %#v", list[1].Parts)
+	}
+}
+
+func TestSelectorSpecificity(t *testing.T) {
+	tests := []struct {
+		css  string
+		want Specificity
+	}{
+		{`* { display: none; }`, Specificity{0, 0, 0}},
+		{`div span { display: none; }`, Specificity{0, 0, 2}},
+		{`#a .b:hover { display: none; }`, Specificity{1, 2, 0}},
+		{`input[type="text"] { display: none; }`, Specificity{0, 1, 1}},
+		{`:is(#a, .b) span { display: none; }`, Specificity{1, 0, 1}},
+		{`:where(#a, .b) span { display: none; }`, Specificity{0, 0, 1}},
+		{`div:not(.a, .b.c) { display: none; }`, Specificity{0, 2, 1}},
+		{`li:nth-of-type(2n+1) { display: none; }`, Specificity{0, 1, 1}},
+	}
+	for _, test := range tests {
+		s := NewStyleSheet()
+		s.Parse(test.css, dummyWindow{})
+		if got := s.Groups[0].Selectors[0].Specificity(); got != test.want {
+			t.Errorf("%s expected specificity %v, got %v", test.css, test.want, got)
+		}
+	}
+}
+
+func TestParsePseudoElements(t *testing.T) {
+	tests := []struct {
+		css    string
+		pseudo string
+		want   Specificity
+	}{
+		{`li::before { content: "-"; }`, "before", Specificity{0, 0, 2}},
+		{`a:after { content: "-"; }`, "after", Specificity{0, 0, 2}},
+		{`.tip:hover::after { content: "-"; }`, "after", Specificity{0, 2, 1}},
+		{`input::placeholder { color: red; }`, "placeholder", Specificity{0, 0, 2}},
+		{`a:hover { color: red; }`, "", Specificity{0, 1, 1}},
+	}
+	for _, test := range tests {
+		s := NewStyleSheet()
+		s.Parse(test.css, dummyWindow{})
+		sel := s.Groups[0].Selectors[0]
+		if got := sel.PseudoElement(); got != test.pseudo {
+			t.Errorf("%s expected pseudo-element %q, got %q", test.css, test.pseudo, got)
+		}
+		if got := sel.Specificity(); got != test.want {
+			t.Errorf("%s expected specificity %v, got %v", test.css, test.want, got)
+		}
+	}
+}
+
+func TestParseMediaAndImport(t *testing.T) {
+	sheet := NewStyleSheet()
+	sheet.ImportResolver = func(path string) (string, error) {
+		if path != "theme.css" {
+			t.Errorf("unexpected import path %q", path)
+		}
+		return `.imported { display: none; }`, nil
+	}
+	sheet.Parse(`@import "theme.css" (min-width: 500px);
+.a { display: none; }
+@media (max-width: 300px) { .b { display: none; } }
+@container card (min-width: 200px) { .c { display: none; } }
+@container (min-width: 100px) { @media (max-width: 300px) { .e { display: none; } } }
+.d { display: none; }`, dummyWindow{})
+	queries := map[string]MediaQuery{}
+	for _, g := range sheet.Groups {
+		for _, sel := range g.Selectors {
+			if len(sel.Parts) > 0 {
+				queries[sel.Parts[0].Name] = g.MediaQuery
+			}
+		}
+	}
+	for _, name := range []string{"imported", "a", "b", "c", "d", "e"} {
+		if _, ok := queries[name]; !ok {
+			t.Fatalf("missing group for .%s", name)
+		}
+	}
+	narrow := MediaFeatures{Width: 200, Height: 200}
+	wide := MediaFeatures{Width: 600, Height: 200}
+	if q := queries["imported"]; q.MatchesViewport(narrow) || !q.MatchesViewport(wide) {
+		t.Error("expected the import media to limit the imported rules")
+	}
+	if q := queries["b"]; !q.MatchesViewport(narrow) || q.MatchesViewport(wide) {
+		t.Error("expected .b to only match narrow viewports")
+	}
+	if q := queries["c"]; !q.Container || q.Name != "card" {
+		t.Errorf("expected .c to be in the card container query, got %+v", q)
+	}
+	if q := queries["e"]; !q.Container || !q.MatchesViewport(narrow) || q.MatchesViewport(wide) {
+		t.Error("expected .e to be a container query limited to narrow viewports")
+	}
+	if q := queries["a"]; q.IsValid() {
+		t.Errorf("expected .a to have no query, got %q", q.Text)
+	}
+	if q := queries["d"]; q.IsValid() {
+		t.Errorf("expected .d to have no query, got %q", q.Text)
+	}
+}
+
+func TestSelectorIsRelational(t *testing.T) {
+	tests := []struct {
+		css  string
+		want bool
+	}{
+		{`div span { display: none; }`, false},
+		{`div:not(.a) { display: none; }`, false},
+		{`figure:has(> img) { display: none; }`, true},
+		{`:is(.a, li:has(.b)) span { display: none; }`, true},
+	}
+	for _, test := range tests {
+		s := NewStyleSheet()
+		s.Parse(test.css, dummyWindow{})
+		if got := s.Groups[0].Selectors[0].IsRelational(); got != test.want {
+			t.Errorf("%s expected relational to be %t, got %t", test.css, test.want, got)
+		}
+	}
+}
@@ -18,6 +18,9 @@ const (
 	RuleInvokeVisited
 	RuleInvokeInvalid
 	RuleInvokeValid
+	RuleInvokeFocusVisible
+	RuleInvokePlaying
+	RuleInvokePaused
 )
 
 func (r RuleInvoke) Matches(state RuleInvoke) bool {
@@ -33,14 +36,18 @@ type PropertyValue struct {
 	Num     float32
 	Args    []string
 	ArgNums []float32
+	// Separated is true when a top-level comma came before this value, such
+	// as the start of the second item in "opacity 1s, color 2s"
+	Separated bool
 }
 
 func (p *PropertyValue) Clone() PropertyValue {
 	return PropertyValue{
-		Str:     p.Str,
-		Num:     p.Num,
-		Args:    slices.Clone(p.Args),
-		ArgNums: slices.Clone(p.ArgNums),
+		Str:       p.Str,
+		Num:       p.Num,
+		Args:      slices.Clone(p.Args),
+		ArgNums:   slices.Clone(p.ArgNums),
+		Separated: p.Separated,
 	}
 }
 
@@ -54,15 +61,25 @@ type Rule struct {
 	Invocation   RuleInvoke
 	Sort         int
 	SelfDestruct bool
+	// Specificity and UserAgent are the cascade weight of the selector that
+	// the rule was matched with
+	Specificity Specificity
+	UserAgent   bool
+	// PseudoElement is the name of the pseudo-element the rule styles (such
+	// as "before"), it is empty for rules of the element itself
+	PseudoElement string
 }
 
 func (r *Rule) Clone() Rule {
 	out := Rule{
-		Property:     r.Property,
-		Invocation:   r.Invocation,
-		Sort:         r.Sort,
-		SelfDestruct: r.SelfDestruct,
-		Values:       make([]PropertyValue, len(r.Values)),
+		Property:      r.Property,
+		Invocation:    r.Invocation,
+		Sort:          r.Sort,
+		SelfDestruct:  r.SelfDestruct,
+		Specificity:   r.Specificity,
+		UserAgent:     r.UserAgent,
+		PseudoElement: r.PseudoElement,
+		Values:        make([]PropertyValue, len(r.Values)),
 	}
 	for i := range r.Values {
 		out.Values[i] = r.Values[i].Clone()
@@ -70,6 +87,23 @@ func (r *Rule) Clone() Rule {
 	return out
 }
 
+// SplitValueList splits the values of a comma separated list property (such as
+// transition or animation) into each of the items of the list
+func SplitValueList(values []PropertyValue) [][]PropertyValue {
+	out := [][]PropertyValue{}
+	start := 0
+	for i := range values {
+		if values[i].Separated && i > start {
+			out = append(out, values[start:i])
+			start = i
+		}
+	}
+	if start < len(values) {
+		out = append(out, values[start:])
+	}
+	return out
+}
+
 func CloneRules(in []Rule) []Rule {
 	out := make([]Rule, len(in))
 	for i := range in {
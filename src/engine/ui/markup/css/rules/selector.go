@@ -6,6 +6,8 @@
 
 package rules
 
+import "strings"
+
 type RuleState = int
 
 const (
@@ -20,6 +22,7 @@ const (
 	ReadingConditionAssignment
 	ReadingPseudo
 	ReadingPseudoFunction
+	ReadingPseudoElement
 	ReadingProperty
 	ReadingPropertyValue
 	ReadingPropertyFunction
@@ -29,28 +32,201 @@ type SelectorPart struct {
 	Name       string
 	Args       []string
 	SelectType RuleState
+	// Selectors is the parsed selector list argument of pseudo-class
+	// functions that take one, such as :is(), :where(), :not() and :has()
+	Selectors []Selector
 }
 
 type Selector struct {
 	Parts []SelectorPart
 }
 
-type MediaQuery struct {
-	Key   string
-	Value string
+// Specificity is the (id, class, type) weight of a selector, used to decide
+// which of two conflicting rules wins the cascade
+type Specificity [3]int
+
+// selectorListPseudos are the pseudo-class functions whose arguments are a
+// selector list rather than plain values
+var selectorListPseudos = map[string]struct{}{
+	"is":           {},
+	"where":        {},
+	"not":          {},
+	"has":          {},
+	"host":         {},
+	"host-context": {},
+}
+
+// legacyPseudoElements are the pseudo-elements that may also be written with
+// a single colon, as they were in CSS 2
+var legacyPseudoElements = map[string]struct{}{
+	"before":       {},
+	"after":        {},
+	"first-line":   {},
+	"first-letter": {},
+}
+
+func (s Specificity) Add(other Specificity) Specificity {
+	return Specificity{s[0] + other[0], s[1] + other[1], s[2] + other[2]}
+}
+
+// Compare returns -1 if s is less specific than other, 1 if it is more
+// specific and 0 if they are equal
+func (s Specificity) Compare(other Specificity) int {
+	for i := range s {
+		if s[i] < other[i] {
+			return -1
+		} else if s[i] > other[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// IsRelational reports if the selector uses :has(), directly or within the
+// selector list of another pseudo-class. Whether a relational selector matches
+// depends on the elements around the subject, not only the subject itself.
+func (s Selector) IsRelational() bool {
+	for _, p := range s.Parts {
+		if p.SelectType == ReadingPseudoFunction && p.Name == "has" {
+			return true
+		}
+		for _, sel := range p.Selectors {
+			if sel.IsRelational() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Specificity computes the weight of the selector. :is(), :not() and :has()
+// take the weight of their most specific argument while :where() is always
+// zero, which makes it useful for easily overridden defaults
+func (s Selector) Specificity() Specificity {
+	out := Specificity{}
+	for _, p := range s.Parts {
+		switch p.SelectType {
+		case ReadingId:
+			out[0]++
+		case ReadingCondition:
+			// The conditions that scope the styles of a component don't add
+			// to the weight the author wrote
+			if p.Name != ComponentScopeAttribute && p.Name != ComponentHostAttribute {
+				out[1]++
+			}
+		case ReadingClass, ReadingPseudo:
+			out[1]++
+		case ReadingTag:
+			if p.Name != "*" {
+				out[2]++
+			}
+		case ReadingPseudoElement:
+			out[2]++
+		case ReadingPseudoFunction:
+			switch p.Name {
+			case "where":
+			case "is", "not", "has":
+				out = out.Add(maxSpecificity(p.Selectors))
+			case "host", "host-context":
+				out[1]++
+				out = out.Add(maxSpecificity(p.Selectors))
+			default:
+				out[1]++
+			}
+		}
+	}
+	return out
+}
+
+// PseudoElement returns the name of the pseudo-element the selector targets,
+// such as "before" for "li::before", or an empty string if there is none
+func (s Selector) PseudoElement() string {
+	for i := len(s.Parts) - 1; i >= 0; i-- {
+		if s.Parts[i].SelectType == ReadingPseudoElement {
+			return s.Parts[i].Name
+		}
+	}
+	return ""
+}
+
+func maxSpecificity(selectors []Selector) Specificity {
+	out := Specificity{}
+	for i := range selectors {
+		if s := selectors[i].Specificity(); s.Compare(out) > 0 {
+			out = s
+		}
+	}
+	return out
+}
+
+// ParseSelectorList parses the arguments of a pseudo-class function such as
+// :is(.a, #b > span) into the selectors of the list. Selectors may start with
+// a combinator (as is used by :has(> img)), in which case the first part of
+// the selector is that combinator
+func ParseSelectorList(args []string) []Selector {
+	out := make([]Selector, 0)
+	for _, item := range splitSelectorList(args) {
+		text := strings.TrimSpace(strings.Join(item, ""))
+		lead := SelectorPart{}
+		switch {
+		case strings.HasPrefix(text, ">"):
+			lead = SelectorPart{Name: ">", SelectType: ReadingChild}
+		case strings.HasPrefix(text, "+"):
+			lead = SelectorPart{Name: "+", SelectType: ReadingAdjacent}
+		case strings.HasPrefix(text, "~"):
+			lead = SelectorPart{Name: "~", SelectType: ReadingSibling}
+		}
+		if lead.Name != "" {
+			text = strings.TrimSpace(text[1:])
+		}
+		if text == "" {
+			continue
+		}
+		sheet := NewStyleSheet()
+		sheet.Parse(text+" {}", nil)
+		if len(sheet.Groups) == 0 || len(sheet.Groups[0].Selectors) == 0 {
+			continue
+		}
+		sel := sheet.Groups[0].Selectors[0]
+		if lead.Name != "" {
+			sel.Parts = append([]SelectorPart{lead}, sel.Parts...)
+		}
+		out = append(out, sel)
+	}
+	return out
+}
+
+func splitSelectorList(args []string) [][]string {
+	out := make([][]string, 0, 1)
+	current := make([]string, 0, len(args))
+	depth := 0
+	for i := range args {
+		token := strings.TrimSpace(args[i])
+		if token == "[" || strings.HasSuffix(token, "(") {
+			depth++
+		} else if (token == "]" || token == ")") && depth > 0 {
+			depth--
+		}
+		if token == "," && depth == 0 {
+			out = append(out, current)
+			current = make([]string, 0, len(args)-i-1)
+		} else {
+			current = append(current, args[i])
+		}
+	}
+	return append(out, current)
 }
 
 type SelectorGroup struct {
 	Selectors  []Selector
 	Rules      []Rule
 	MediaQuery MediaQuery
-}
-
-func (m *MediaQuery) IsValid() bool { return m.Key != "" }
-
-func (m *MediaQuery) Clear() {
-	m.Key = ""
-	m.Value = ""
+	// UserAgent groups are the default styles of the engine, they lose to
+	// any other style regardless of specificity
+	UserAgent bool
+	// Scope is the name of the component the group styles, it is empty for
+	// the styles of the document
+	Scope string
 }
 
 func (s *SelectorGroup) AddRule(r Rule) {
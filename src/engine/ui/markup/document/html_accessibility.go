@@ -0,0 +1,620 @@
+/******************************************************************************/
+/* html_accessibility.go                                                      */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"kaijuengine.com/engine/ui"
+)
+
+// AccessibilityRole is what an element is to assistive tech, it follows the
+// roles of ARIA and comes from the role attribute or else the tag
+type AccessibilityRole string
+
+const (
+	RoleNone         AccessibilityRole = "none"
+	RoleGeneric      AccessibilityRole = "generic"
+	RoleDocument     AccessibilityRole = "document"
+	RoleText         AccessibilityRole = "text"
+	RoleAlert        AccessibilityRole = "alert"
+	RoleButton       AccessibilityRole = "button"
+	RoleCell         AccessibilityRole = "cell"
+	RoleCheckbox     AccessibilityRole = "checkbox"
+	RoleColumnHeader AccessibilityRole = "columnheader"
+	RoleComboBox     AccessibilityRole = "combobox"
+	RoleDialog       AccessibilityRole = "dialog"
+	RoleForm         AccessibilityRole = "form"
+	RoleGroup        AccessibilityRole = "group"
+	RoleHeading      AccessibilityRole = "heading"
+	RoleImage        AccessibilityRole = "img"
+	RoleLink         AccessibilityRole = "link"
+	RoleList         AccessibilityRole = "list"
+	RoleListItem     AccessibilityRole = "listitem"
+	RoleMain         AccessibilityRole = "main"
+	RoleMenu         AccessibilityRole = "menu"
+	RoleMenuItem     AccessibilityRole = "menuitem"
+	RoleNavigation   AccessibilityRole = "navigation"
+	RoleOption       AccessibilityRole = "option"
+	RoleParagraph    AccessibilityRole = "paragraph"
+	RoleProgressBar  AccessibilityRole = "progressbar"
+	RoleRadio        AccessibilityRole = "radio"
+	RoleRow          AccessibilityRole = "row"
+	RoleSeparator    AccessibilityRole = "separator"
+	RoleSlider       AccessibilityRole = "slider"
+	RoleSpinButton   AccessibilityRole = "spinbutton"
+	RoleStatus       AccessibilityRole = "status"
+	RoleSwitch       AccessibilityRole = "switch"
+	RoleTab          AccessibilityRole = "tab"
+	RoleTabList      AccessibilityRole = "tablist"
+	RoleTabPanel     AccessibilityRole = "tabpanel"
+	RoleTable        AccessibilityRole = "table"
+	RoleTextBox      AccessibilityRole = "textbox"
+)
+
+// AccessibilityState is a set of the states of an accessibility node
+type AccessibilityState uint16
+
+const (
+	StateFocusable AccessibilityState = 1 << iota
+	StateFocused
+	StateDisabled
+	StateChecked
+	StateMixed
+	StateExpanded
+	StateCollapsed
+	StateSelected
+	StatePressed
+	StateRequired
+	StateInvalid
+	StateReadOnly
+	StateBusy
+)
+
+var accessibilityStateNames = [...]string{
+	"focusable", "focused", "disabled", "checked", "mixed", "expanded",
+	"collapsed", "selected", "pressed", "required", "invalid", "readonly", "busy",
+}
+
+// Politeness is how a change of a live region (aria-live) or an announcement
+// is spoken, polite waits for what is being spoken and assertive interrupts it
+type Politeness int
+
+const (
+	PolitenessOff Politeness = iota
+	PolitenessPolite
+	PolitenessAssertive
+)
+
+// AccessibilityNode is an element as it is seen by assistive tech. Elements
+// that don't mean anything on their own, like a div for layout, are left out
+// of the tree and their children take their place
+type AccessibilityNode struct {
+	Role        AccessibilityRole
+	Name        string
+	Description string
+	Value       string
+	// Level is the level of a heading, 0 for other nodes
+	Level    int
+	States   AccessibilityState
+	Live     Politeness
+	Element  *Element
+	Children []*AccessibilityNode
+	// liveText is the text of a live region when the tree was built
+	liveText string
+}
+
+// nameFromContentRoles are the roles that are named by the text inside of
+// them, that text is not repeated as children of the node
+var nameFromContentRoles = []AccessibilityRole{
+	RoleButton, RoleCell, RoleCheckbox, RoleColumnHeader, RoleHeading, RoleLink,
+	RoleMenuItem, RoleOption, RoleRadio, RoleSwitch, RoleTab,
+}
+
+func (n *AccessibilityNode) Has(state AccessibilityState) bool {
+	return n.States&state == state
+}
+
+// Find returns the node of the element, or nil if the element is not in the
+// tree under this node
+func (n *AccessibilityNode) Find(elm *Element) *AccessibilityNode {
+	if n.Element == elm {
+		return n
+	}
+	for _, c := range n.Children {
+		if found := c.Find(elm); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Focused returns the node that has the focus, or nil if none do
+func (n *AccessibilityNode) Focused() *AccessibilityNode {
+	if n.Has(StateFocused) {
+		return n
+	}
+	for _, c := range n.Children {
+		if found := c.Focused(); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Dump writes the tree under the node as text, one node per line indented
+// by its depth, which is what tests compare against
+func (n *AccessibilityNode) Dump() string {
+	sb := strings.Builder{}
+	n.dump(&sb, 0)
+	return sb.String()
+}
+
+func (n *AccessibilityNode) dump(sb *strings.Builder, depth int) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(string(n.Role))
+	if n.Name != "" {
+		sb.WriteString(" " + strconv.Quote(n.Name))
+	}
+	if n.Level > 0 {
+		fmt.Fprintf(sb, " level=%d", n.Level)
+	}
+	if n.Value != "" {
+		sb.WriteString(" value=" + strconv.Quote(n.Value))
+	}
+	if n.Description != "" {
+		sb.WriteString(" description=" + strconv.Quote(n.Description))
+	}
+	switch n.Live {
+	case PolitenessPolite:
+		sb.WriteString(" live=polite")
+	case PolitenessAssertive:
+		sb.WriteString(" live=assertive")
+	}
+	if n.States != 0 {
+		states := make([]string, 0, len(accessibilityStateNames))
+		for i, name := range accessibilityStateNames {
+			if n.States&(1<<i) != 0 {
+				states = append(states, name)
+			}
+		}
+		sb.WriteString(" [" + strings.Join(states, ", ") + "]")
+	}
+	sb.WriteByte('\n')
+	for _, c := range n.Children {
+		c.dump(sb, depth+1)
+	}
+}
+
+// AccessibilityTree builds the accessibility tree of the body of the
+// document as it is right now
+func (d *Document) AccessibilityTree() *AccessibilityNode {
+	if len(d.Elements) == 0 {
+		return &AccessibilityNode{Role: RoleDocument}
+	}
+	body := d.Elements[0].Root().Body()
+	if body == nil {
+		body = d.Elements[0].Root()
+	}
+	return BuildAccessibilityTree(body, d.accessibleFocus())
+}
+
+// BuildAccessibilityTree builds the accessibility tree of the element and
+// everything in it, focused is the element that has the focus (or nil). The
+// element doesn't need to have UI, in which case only its markup is used
+func BuildAccessibilityTree(root *Element, focused *Element) *AccessibilityNode {
+	b := accessibilityBuilder{root: root.Root(), focused: focused}
+	out := &AccessibilityNode{Role: RoleDocument, Element: root}
+	if root.Type == html.ElementNode && root.Data != "body" {
+		out.Children = b.build(root)
+	} else {
+		for _, c := range root.Children {
+			out.Children = append(out.Children, b.build(c)...)
+		}
+	}
+	return out
+}
+
+type accessibilityBuilder struct {
+	root    *Element
+	focused *Element
+}
+
+func (b *accessibilityBuilder) build(e *Element) []*AccessibilityNode {
+	if e.pseudo != "" || isAccessibilityHidden(e) {
+		return nil
+	}
+	if e.Type == html.TextNode {
+		text := collapseWhitespace(accessibleText(e))
+		if text == "" {
+			return nil
+		}
+		return []*AccessibilityNode{{Role: RoleText, Name: text, Element: e}}
+	}
+	if e.Type != html.ElementNode {
+		return nil
+	}
+	role := accessibilityRole(e)
+	children := make([]*AccessibilityNode, 0, len(e.Children))
+	for _, c := range e.Children {
+		children = append(children, b.build(c)...)
+	}
+	if slices.Contains(nameFromContentRoles, role) {
+		children = slices.DeleteFunc(children, func(n *AccessibilityNode) bool {
+			return n.Role == RoleText
+		})
+	}
+	node := &AccessibilityNode{
+		Role:        role,
+		Name:        b.name(e, role),
+		Description: b.description(e),
+		Value:       accessibleValue(e, role),
+		Level:       headingLevel(e, role),
+		States:      b.states(e, role),
+		Live:        livePoliteness(e, role),
+		Element:     e,
+		Children:    children,
+	}
+	if node.Live != PolitenessOff {
+		node.liveText = collapseWhitespace(accessibleText(e))
+	}
+	if role == RoleNone || (role == RoleGeneric && node.Name == "" &&
+		node.Description == "" && node.Live == PolitenessOff && !node.Has(StateFocusable)) {
+		return children
+	}
+	return []*AccessibilityNode{node}
+}
+
+func isAccessibilityHidden(e *Element) bool {
+	if e.Type != html.ElementNode {
+		return false
+	}
+	switch e.Data {
+	case "head", "script", "style", "template", "title":
+		return true
+	}
+	if e.HasAttribute("hidden") || strings.EqualFold(e.Attribute("aria-hidden"), "true") ||
+		(e.IsInput() && strings.EqualFold(e.Attribute("type"), "hidden")) {
+		return true
+	}
+	return e.UI != nil && !e.UI.IsActive()
+}
+
+func accessibilityRole(e *Element) AccessibilityRole {
+	if roles := strings.Fields(e.Attribute("role")); len(roles) > 0 {
+		if roles[0] == "presentation" {
+			return RoleNone
+		}
+		return AccessibilityRole(strings.ToLower(roles[0]))
+	}
+	switch e.Data {
+	case "body":
+		return RoleDocument
+	case "button":
+		return RoleButton
+	case "input":
+		switch strings.ToLower(strings.TrimSpace(e.Attribute("type"))) {
+		case "button", "submit", "reset", "image":
+			return RoleButton
+		case "radio":
+			return RoleRadio
+		}
+		switch classifyHTMLInputType(e.Attribute("type")) {
+		case htmlInputTypeCheckbox:
+			return RoleCheckbox
+		case htmlInputTypeSlider:
+			return RoleSlider
+		case htmlInputTypeNumber:
+			return RoleSpinButton
+		}
+		return RoleTextBox
+	case "textarea":
+		return RoleTextBox
+	case "select":
+		return RoleComboBox
+	case "option":
+		return RoleOption
+	case "a":
+		if e.HasAttribute("href") {
+			return RoleLink
+		}
+	case "img":
+		if e.HasAttribute("alt") && e.Attribute("alt") == "" {
+			return RoleNone
+		}
+		return RoleImage
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		return RoleHeading
+	case "ul", "ol":
+		return RoleList
+	case "li":
+		return RoleListItem
+	case "table":
+		return RoleTable
+	case "tr":
+		return RoleRow
+	case "td":
+		return RoleCell
+	case "th":
+		return RoleColumnHeader
+	case "dialog":
+		return RoleDialog
+	case "progress":
+		return RoleProgressBar
+	case "nav":
+		return RoleNavigation
+	case "main":
+		return RoleMain
+	case "p":
+		return RoleParagraph
+	case "hr":
+		return RoleSeparator
+	case "form":
+		return RoleForm
+	case "fieldset":
+		return RoleGroup
+	}
+	return RoleGeneric
+}
+
+func (b *accessibilityBuilder) name(e *Element, role AccessibilityRole) string {
+	if name := b.textOfIds(e.Attribute("aria-labelledby")); name != "" {
+		return name
+	}
+	if name := collapseWhitespace(e.Attribute("aria-label")); name != "" {
+		return name
+	}
+	switch {
+	case e.IsInput() && role == RoleButton:
+		if v := collapseWhitespace(e.Attribute("value")); v != "" {
+			return v
+		}
+		if strings.EqualFold(e.Attribute("type"), "reset") {
+			return "Reset"
+		}
+		return "Submit"
+	case e.IsInput(), e.IsTextArea(), e.IsSelect():
+		if name := b.labelText(e); name != "" {
+			return name
+		}
+	case e.IsImage():
+		if alt := collapseWhitespace(e.Attribute("alt")); alt != "" {
+			return alt
+		}
+	}
+	if slices.Contains(nameFromContentRoles, role) {
+		if name := collapseWhitespace(accessibleText(e)); name != "" {
+			return name
+		}
+	}
+	if title := collapseWhitespace(e.Attribute("title")); title != "" {
+		return title
+	}
+	return collapseWhitespace(e.Attribute("placeholder"))
+}
+
+// labelText is the text of the label elements of a form control, either
+// pointed at it with for or wrapped around it
+func (b *accessibilityBuilder) labelText(e *Element) string {
+	parts := make([]string, 0, 1)
+	if id := e.Attribute("id"); id != "" {
+		for _, label := range b.root.FindElementsByTag("label") {
+			if label.Attribute("for") == id {
+				parts = append(parts, collapseWhitespace(accessibleText(label)))
+			}
+		}
+	}
+	for p := e.Parent.Value(); p != nil; p = p.Parent.Value() {
+		if p.Data == "label" && p.Type == html.ElementNode {
+			parts = append(parts, collapseWhitespace(accessibleText(p)))
+			break
+		}
+	}
+	return strings.Join(slices.DeleteFunc(parts, func(s string) bool { return s == "" }), " ")
+}
+
+func (b *accessibilityBuilder) description(e *Element) string {
+	if desc := b.textOfIds(e.Attribute("aria-describedby")); desc != "" {
+		return desc
+	}
+	return collapseWhitespace(e.Attribute("aria-description"))
+}
+
+func (b *accessibilityBuilder) textOfIds(ids string) string {
+	parts := make([]string, 0, 1)
+	for _, id := range strings.Fields(ids) {
+		if elm := b.root.FindElementById(id); elm != nil {
+			if text := collapseWhitespace(accessibleText(elm)); text != "" {
+				parts = append(parts, text)
+			} else if label := collapseWhitespace(elm.Attribute("aria-label")); label != "" {
+				parts = append(parts, label)
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func accessibleValue(e *Element, role AccessibilityRole) string {
+	if text := collapseWhitespace(e.Attribute("aria-valuetext")); text != "" {
+		return text
+	}
+	switch role {
+	case RoleTextBox, RoleSpinButton:
+		if strings.EqualFold(e.Attribute("type"), "password") {
+			return ""
+		}
+		if e.UI != nil {
+			switch e.UI.Type() {
+			case ui.ElementTypeInput:
+				return e.UI.ToInput().Text()
+			case ui.ElementTypeTextArea:
+				return e.UI.ToTextArea().Text()
+			}
+		}
+		if e.IsTextArea() && !e.HasAttribute("value") {
+			return strings.TrimSpace(accessibleText(e))
+		}
+		return e.Attribute("value")
+	case RoleSlider:
+		if e.UI != nil && e.UI.IsType(ui.ElementTypeSlider) {
+			return strconv.Itoa(int(e.UI.ToSlider().Value()*100+0.5)) + "%"
+		}
+	case RoleComboBox:
+		if e.UI != nil && e.UI.IsType(ui.ElementTypeSelect) {
+			return e.UI.ToSelect().Name()
+		}
+		options := e.FindElementsByTag("option")
+		for _, o := range options {
+			if o.HasAttribute("selected") {
+				return collapseWhitespace(accessibleText(o))
+			}
+		}
+		if len(options) > 0 {
+			return collapseWhitespace(accessibleText(options[0]))
+		}
+		return ""
+	}
+	if now := e.Attribute("aria-valuenow"); now != "" {
+		return now
+	}
+	if role == RoleSlider || role == RoleProgressBar {
+		return e.Attribute("value")
+	}
+	return ""
+}
+
+func headingLevel(e *Element, role AccessibilityRole) int {
+	if role != RoleHeading {
+		return 0
+	}
+	if level, err := strconv.Atoi(e.Attribute("aria-level")); err == nil && level > 0 {
+		return level
+	}
+	if len(e.Data) == 2 && e.Data[0] == 'h' && e.Data[1] >= '1' && e.Data[1] <= '6' {
+		return int(e.Data[1] - '0')
+	}
+	return 2
+}
+
+func (b *accessibilityBuilder) states(e *Element, role AccessibilityRole) AccessibilityState {
+	var s AccessibilityState
+	ariaTrue := func(key string) bool { return strings.EqualFold(e.Attribute(key), "true") }
+	if e.UI != nil {
+		if e.IsFocusable() {
+			s |= StateFocusable
+		}
+		if e.UI.IsDisabled() {
+			s |= StateDisabled
+		}
+	} else if e.isFocusableByMarkup() && !e.HasAttribute("disabled") {
+		s |= StateFocusable
+	}
+	if e == b.focused {
+		s |= StateFocused
+	}
+	if (e.HasAttribute("disabled") && elementSupportsDisabled(e)) || ariaTrue("aria-disabled") {
+		s |= StateDisabled
+	}
+	switch checked := strings.ToLower(e.Attribute("aria-checked")); {
+	case checked == "mixed":
+		s |= StateMixed
+	case checked == "true":
+		s |= StateChecked
+	case checked == "false":
+	case e.UI != nil && e.UI.IsType(ui.ElementTypeCheckbox):
+		if e.UI.ToCheckbox().IsChecked() {
+			s |= StateChecked
+		}
+	case e.IsInput() && e.HasAttribute("checked"):
+		s |= StateChecked
+	}
+	switch strings.ToLower(e.Attribute("aria-expanded")) {
+	case "true":
+		s |= StateExpanded
+	case "false":
+		s |= StateCollapsed
+	default:
+		if role == RoleComboBox && e.UI != nil && e.UI.IsType(ui.ElementTypeSelect) {
+			if e.UI.ToSelect().IsExpanded() {
+				s |= StateExpanded
+			} else {
+				s |= StateCollapsed
+			}
+		}
+	}
+	if ariaTrue("aria-selected") || (role == RoleOption && e.HasAttribute("selected")) {
+		s |= StateSelected
+	}
+	if ariaTrue("aria-pressed") {
+		s |= StatePressed
+	}
+	if e.HasAttribute("required") || ariaTrue("aria-required") {
+		s |= StateRequired
+	}
+	if ariaTrue("aria-invalid") {
+		s |= StateInvalid
+	}
+	if e.HasAttribute("readonly") || ariaTrue("aria-readonly") {
+		s |= StateReadOnly
+	}
+	if ariaTrue("aria-busy") {
+		s |= StateBusy
+	}
+	return s
+}
+
+func livePoliteness(e *Element, role AccessibilityRole) Politeness {
+	switch strings.ToLower(e.Attribute("aria-live")) {
+	case "polite":
+		return PolitenessPolite
+	case "assertive":
+		return PolitenessAssertive
+	case "off":
+		return PolitenessOff
+	}
+	switch role {
+	case RoleAlert:
+		return PolitenessAssertive
+	case RoleStatus:
+		return PolitenessPolite
+	}
+	return PolitenessOff
+}
+
+// accessibleText is the text inside of the element that isn't hidden, it is
+// read from the labels of the text when they exist as their text can be
+// changed after the document is parsed
+func accessibleText(e *Element) string {
+	sb := strings.Builder{}
+	var collect func(target *Element)
+	collect = func(target *Element) {
+		if target.Type == html.TextNode {
+			if target.UI != nil && target.UI.IsType(ui.ElementTypeLabel) {
+				sb.WriteString(target.UI.ToLabel().Text())
+			} else {
+				sb.WriteString(target.Data)
+			}
+			return
+		}
+		if target != e && (target.pseudo != "" || isAccessibilityHidden(target)) {
+			return
+		}
+		for i := range target.Children {
+			collect(target.Children[i])
+		}
+	}
+	collect(e)
+	return sb.String()
+}
+
+func collapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,218 @@
+/******************************************************************************/
+/* html_accessibility_test.go                                                 */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import (
+	"strings"
+	"testing"
+)
+
+func accessibilityTestDocument(html string) (*Document, *Element) {
+	root := NewHTML(html)
+	d := &Document{}
+	var index func(e *Element)
+	index = func(e *Element) {
+		d.Elements = append(d.Elements, e)
+		for _, c := range e.Children {
+			index(c)
+		}
+	}
+	index(root.Body())
+	return d, root
+}
+
+type spoken struct {
+	text      string
+	interrupt bool
+}
+
+func recordSpeech(d *Document) *[]spoken {
+	out := &[]spoken{}
+	d.screenReader.speaker = SpeakerFunc(func(text string, interrupt bool) {
+		*out = append(*out, spoken{text, interrupt})
+	})
+	return out
+}
+
+func TestAccessibilityTreeDump(t *testing.T) {
+	d, _ := accessibilityTestDocument(`
+		<div class="menu">
+			<h1>Settings</h1>
+			<label><input type="checkbox" checked> Music</label>
+			<label for="name">Name</label>
+			<input id="name" value="Kai" required>
+			<button aria-describedby="hint">Play <span>now</span></button>
+			<p id="hint" hidden>Starts the game</p>
+			<img src="logo.png" alt="">
+			<div aria-label="Spacer" aria-hidden="true"></div>
+			<div role="status">Saved</div>
+		</div>`)
+	want := strings.Join([]string{
+		`document`,
+		`  heading "Settings" level=1`,
+		`  checkbox "Music" [focusable, checked]`,
+		`  text "Music"`,
+		`  text "Name"`,
+		`  textbox "Name" value="Kai" [focusable, required]`,
+		`  button "Play now" description="Starts the game" [focusable]`,
+		`  status live=polite`,
+		`    text "Saved"`,
+		``,
+	}, "\n")
+	if got := d.AccessibilityTree().Dump(); got != want {
+		t.Errorf("expected the tree\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestAccessibilityRolesAndStates(t *testing.T) {
+	root := NewHTML(`
+		<div role="switch" aria-checked="mixed" tabindex="0">Sound</div>
+		<button aria-expanded="false" aria-pressed="true" disabled>Menu</button>
+		<input type="range" aria-valuetext="Loud" aria-label="Volume">
+		<select aria-label="Mode"><option>Easy</option><option selected>Hard</option></select>
+		<a>Not a link</a>
+		<h3 aria-level="5">Deep</h3>`)
+	tree := BuildAccessibilityTree(root.Body(), nil)
+	tests := []struct {
+		tag    string
+		role   AccessibilityRole
+		states AccessibilityState
+		value  string
+	}{
+		{"div", RoleSwitch, StateFocusable | StateMixed, ""},
+		{"button", RoleButton, StateDisabled | StateCollapsed | StatePressed, ""},
+		{"input", RoleSlider, StateFocusable, "Loud"},
+		{"select", RoleComboBox, StateFocusable, "Hard"},
+	}
+	for _, test := range tests {
+		node := tree.Find(root.FindElementByTag(test.tag))
+		if node == nil {
+			t.Errorf("expected a node for the %s", test.tag)
+			continue
+		}
+		if node.Role != test.role || node.States != test.states || node.Value != test.value {
+			t.Errorf("%s: expected %s %d %q, got %s %d %q", test.tag, test.role,
+				test.states, test.value, node.Role, node.States, node.Value)
+		}
+	}
+	if node := tree.Find(root.FindElementByTag("a")); node != nil {
+		t.Error("expected an anchor without href to be left out of the tree")
+	}
+	if node := tree.Find(root.FindElementByTag("h3")); node == nil || node.Level != 5 {
+		t.Error("expected aria-level to set the level of the heading")
+	}
+}
+
+func TestAccessibilityNodeSpeech(t *testing.T) {
+	tests := []struct {
+		node AccessibilityNode
+		want string
+	}{
+		{AccessibilityNode{Role: RoleCheckbox, Name: "Music"}, "Music, checkbox, not checked"},
+		{AccessibilityNode{Role: RoleButton, Name: "Play", Description: "Starts the game",
+			States: StateDisabled}, "Play, button, disabled, Starts the game"},
+		{AccessibilityNode{Role: RoleHeading, Name: "Settings", Level: 2}, "Settings, heading level 2"},
+		{AccessibilityNode{Role: RoleTextBox, Name: "Name", Value: "Kai",
+			States: StateRequired | StateInvalid}, "Name, edit text, Kai, required, invalid entry"},
+		{AccessibilityNode{Role: RoleComboBox, Name: "Mode", Value: "Hard",
+			States: StateCollapsed}, "Mode, combo box, Hard, collapsed"},
+	}
+	for _, test := range tests {
+		if got := test.node.Speech(); got != test.want {
+			t.Errorf("expected %q, got %q", test.want, got)
+		}
+	}
+}
+
+func TestScreenReaderSpeaksFocusAndChanges(t *testing.T) {
+	d, root := accessibilityTestDocument(`
+		<input id="music" type="checkbox" aria-label="Music">
+		<button>Play</button>`)
+	speech := recordSpeech(d)
+	d.RefreshScreenReader()
+	if len(*speech) != 0 {
+		t.Fatalf("expected nothing to be said without focus, got %v", *speech)
+	}
+	checkbox := root.FindElementById("music")
+	d.nav.focused = checkbox
+	d.RefreshScreenReader()
+	checkbox.SetAttribute("checked", "")
+	d.RefreshScreenReader()
+	d.RefreshScreenReader()
+	d.nav.focused = root.FindElementByTag("button")
+	d.RefreshScreenReader()
+	want := []spoken{
+		{"Music, checkbox, not checked", true},
+		{"checked", true},
+		{"Play, button", true},
+	}
+	if len(*speech) != len(want) {
+		t.Fatalf("expected %v, got %v", want, *speech)
+	}
+	for i := range want {
+		if (*speech)[i] != want[i] {
+			t.Errorf("expected %v, got %v", want[i], (*speech)[i])
+		}
+	}
+}
+
+func TestScreenReaderSpeaksLiveRegions(t *testing.T) {
+	d, root := accessibilityTestDocument(`
+		<div id="score" aria-live="polite">Score 10</div>
+		<div id="quiet" aria-live="off">Nothing</div>
+		<div id="warn" hidden role="alert">Low health</div>`)
+	speech := recordSpeech(d)
+	d.RefreshScreenReader()
+	root.FindElementById("score").Children[0].Data = "Score 20"
+	root.FindElementById("quiet").Children[0].Data = "Still nothing"
+	root.FindElementById("warn").RemoveAttribute("hidden")
+	d.RefreshScreenReader()
+	d.Announce("Game saved", PolitenessAssertive)
+	d.Announce("Ignored", PolitenessOff)
+	want := []spoken{
+		{"Score 20", false},
+		{"Low health", true},
+		{"Game saved", true},
+	}
+	if len(*speech) != len(want) {
+		t.Fatalf("expected %v, got %v", want, *speech)
+	}
+	for i := range want {
+		if (*speech)[i] != want[i] {
+			t.Errorf("expected %v, got %v", want[i], (*speech)[i])
+		}
+	}
+}
+
+func TestScreenReaderFollowsEventsWithoutHost(t *testing.T) {
+	d, root := accessibilityTestDocument(`
+		<input id="music" type="checkbox" aria-label="Music">`)
+	var speech []spoken
+	d.EnableScreenReader(SpeakerFunc(func(text string, interrupt bool) {
+		speech = append(speech, spoken{text, interrupt})
+	}))
+	checkbox := root.FindElementById("music")
+	d.nav.focused = checkbox
+	checkbox.DispatchEvent(NewEvent("focus", false, false))
+	checkbox.SetAttribute("checked", "")
+	checkbox.DispatchEvent(NewEvent("change", true, false))
+	d.DisableScreenReader()
+	checkbox.RemoveAttribute("checked")
+	checkbox.DispatchEvent(NewEvent("change", true, false))
+	want := []spoken{
+		{"Music, checkbox, not checked", true},
+		{"checked", true},
+	}
+	if len(speech) != len(want) {
+		t.Fatalf("expected %v, got %v", want, speech)
+	}
+	for i := range want {
+		if speech[i] != want[i] {
+			t.Errorf("expected %v, got %v", want[i], speech[i])
+		}
+	}
+}
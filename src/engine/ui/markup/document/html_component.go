@@ -0,0 +1,305 @@
+/******************************************************************************/
+/* html_component.go                                                          */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package document
+
+import (
+	"fmt"
+	"html/template"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"weak"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+const (
+	componentTag         = "kaiju-component"
+	componentPropertyTag = "property"
+	componentSlotTag     = "slot"
+	// maxComponentDepth is how deep components may be nested within the
+	// templates of other components
+	maxComponentDepth = 32
+)
+
+// ComponentProperty is an attribute of a component's host element that is
+// given to the template of the component, the default is used when the
+// host doesn't have the attribute
+type ComponentProperty struct {
+	Name    string
+	Default string
+}
+
+// Component is a custom element defined by an HTML asset. Its template is
+// rendered (as a Go template with the attributes of the host element as the
+// data) in place of the content of each element with the name of the
+// component. The content of the host element is placed into the <slot>
+// elements of the template and the styles only apply within the template,
+// with :host being the element the component was used for
+//
+//	<kaiju-component name="item-card">
+//		<property name="title" default="Untitled"></property>
+//		<style>
+//			:host { display: block; }
+//			.title { font-size: 20px; }
+//		</style>
+//		<template>
+//			<div class="title">{{.title}}</div>
+//			<slot name="icon"></slot>
+//			<div class="body"><slot>Nothing to show</slot></div>
+//		</template>
+//	</kaiju-component>
+type Component struct {
+	Name       string
+	Properties []ComponentProperty
+	Style      string
+	template   *template.Template
+}
+
+var (
+	components      = map[string]*Component{}
+	componentsMutex sync.RWMutex
+)
+
+// ParseComponent reads the definition of a component from its HTML, the
+// name of the component must contain a hyphen so that it can't be confused
+// with the standard HTML elements
+func ParseComponent(src string) (*Component, error) {
+	root, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	def := findHTMLNode(root, componentTag)
+	if def == nil {
+		return nil, fmt.Errorf("the component is missing the <%s> element", componentTag)
+	}
+	c := &Component{Name: strings.ToLower(htmlNodeAttribute(def, "name"))}
+	if !strings.Contains(c.Name, "-") {
+		return nil, fmt.Errorf("the component name %q must contain a hyphen", c.Name)
+	}
+	style := strings.Builder{}
+	walkHTMLNodes(def, func(n *html.Node) bool {
+		switch n.Data {
+		case "template":
+			return false
+		case componentPropertyTag:
+			c.Properties = append(c.Properties, ComponentProperty{
+				Name:    htmlNodeAttribute(n, "name"),
+				Default: htmlNodeAttribute(n, "default"),
+			})
+		case "style":
+			for t := n.FirstChild; t != nil; t = t.NextSibling {
+				style.WriteString(t.Data)
+			}
+		}
+		return true
+	})
+	c.Style = style.String()
+	body, err := componentTemplateSource(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the template of the component %s: %w", c.Name, err)
+	}
+	if c.template, err = template.New(c.Name).Funcs(funcMap).Parse(body); err != nil {
+		return nil, fmt.Errorf("failed to parse the template of the component %s: %w", c.Name, err)
+	}
+	return c, nil
+}
+
+// componentTemplateSource returns the markup within the <template> element
+// as it was written, parsing it as HTML would change the template actions
+func componentTemplateSource(src string) (string, error) {
+	lower := strings.ToLower(src)
+	start := strings.Index(lower, "<template")
+	if start < 0 {
+		return "", fmt.Errorf("missing the <template> element")
+	}
+	open := strings.Index(lower[start:], ">")
+	end := strings.LastIndex(lower, "</template>")
+	if open < 0 || end < start+open {
+		return "", fmt.Errorf("the <template> element is not closed")
+	}
+	return src[start+open+1 : end], nil
+}
+
+func findHTMLNode(root *html.Node, tag string) *html.Node {
+	var found *html.Node
+	walkHTMLNodes(root, func(n *html.Node) bool {
+		if found == nil && n.Type == html.ElementNode && n.Data == tag {
+			found = n
+		}
+		return found == nil
+	})
+	return found
+}
+
+// walkHTMLNodes calls visit for each of the nodes under the root, the
+// children of a node are skipped when visit returns false
+func walkHTMLNodes(root *html.Node, visit func(n *html.Node) bool) {
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode && c.Type != html.DocumentNode {
+			continue
+		}
+		if visit(c) {
+			walkHTMLNodes(c, visit)
+		}
+	}
+}
+
+func htmlNodeAttribute(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// RegisterComponent makes the component available to the documents that are
+// created after it, a component with the same name is replaced
+func RegisterComponent(c *Component) {
+	componentsMutex.Lock()
+	defer componentsMutex.Unlock()
+	components[c.Name] = c
+}
+
+func UnregisterComponent(name string) {
+	componentsMutex.Lock()
+	defer componentsMutex.Unlock()
+	delete(components, strings.ToLower(name))
+}
+
+func FindComponent(name string) (*Component, bool) {
+	componentsMutex.RLock()
+	defer componentsMutex.RUnlock()
+	c, ok := components[strings.ToLower(name)]
+	return c, ok
+}
+
+// Components returns the components that are used in the document, their
+// styles are to be parsed with rules.StyleSheet.ParseScoped
+func (d *Document) Components() []*Component { return d.components }
+
+// render creates the elements of the template for the given properties
+func (c *Component) render(properties map[string]string) ([]*Element, error) {
+	sb := strings.Builder{}
+	if err := c.template.Execute(&sb, properties); err != nil {
+		return nil, err
+	}
+	context := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(sb.String()), context)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Element, 0, len(nodes))
+	for _, n := range nodes {
+		if n.Type == html.TextNode && strings.TrimSpace(n.Data) == "" {
+			continue
+		}
+		out = append(out, toElement(n))
+	}
+	return out, nil
+}
+
+// expandComponents instantiates the components used within the element,
+// stack holds the components whose templates are being expanded
+func (d *Document) expandComponents(elm *Element, stack []string) {
+	for _, c := range elm.Children {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if comp, ok := FindComponent(c.Data); ok && !c.HasAttribute(rules.ComponentHostAttribute) {
+			d.instantiateComponent(c, comp, stack)
+		} else {
+			d.expandComponents(c, stack)
+		}
+	}
+}
+
+func (d *Document) instantiateComponent(host *Element, c *Component, stack []string) {
+	if slices.Contains(stack, c.Name) || len(stack) >= maxComponentDepth {
+		slog.Error("the component is used within its own template", "component", c.Name)
+		return
+	}
+	// The content of the host belongs to the template it was written in
+	d.expandComponents(host, stack)
+	content := host.Children
+	properties := make(map[string]string, len(c.Properties)+len(host.attr))
+	for _, p := range c.Properties {
+		properties[p.Name] = p.Default
+	}
+	for _, a := range host.attr {
+		properties[a.Key] = a.Val
+	}
+	shadow, err := c.render(properties)
+	if err != nil {
+		slog.Error("failed to render the component", "component", c.Name, "error", err)
+		return
+	}
+	host.SetAttribute(rules.ComponentHostAttribute, c.Name)
+	host.Children = shadow
+	for _, e := range shadow {
+		e.setParents(host)
+		markComponentScope(e, c.Name)
+	}
+	d.expandComponents(host, append(slices.Clone(stack), c.Name))
+	distributeSlots(host, c.Name, content)
+	if !slices.Contains(d.components, c) {
+		d.components = append(d.components, c)
+	}
+}
+
+func markComponentScope(elm *Element, name string) {
+	if elm.Type != html.ElementNode {
+		return
+	}
+	elm.SetAttribute(rules.ComponentScopeAttribute, name)
+	for _, c := range elm.Children {
+		markComponentScope(c, name)
+	}
+}
+
+// distributeSlots replaces the <slot> elements of the component's template
+// with the content of the host that names them in its slot attribute, the
+// unnamed slot takes the rest of the content. A slot that gets no content
+// keeps its own children as the fallback
+func distributeSlots(host *Element, name string, content []*Element) {
+	assigned := map[string][]*Element{}
+	for _, e := range content {
+		slot := ""
+		if e.Type == html.ElementNode {
+			slot = e.Attribute("slot")
+		}
+		assigned[slot] = append(assigned[slot], e)
+	}
+	var fill func(parent *Element)
+	fill = func(parent *Element) {
+		for i := 0; i < len(parent.Children); i++ {
+			c := parent.Children[i]
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if c.Data != componentSlotTag || c.Attribute(rules.ComponentScopeAttribute) != name {
+				fill(c)
+				continue
+			}
+			slotName := c.Attribute("name")
+			elms := assigned[slotName]
+			delete(assigned, slotName)
+			if len(elms) == 0 {
+				elms = c.Children
+			}
+			parent.Children = slices.Replace(parent.Children, i, i+1, elms...)
+			for _, e := range elms {
+				e.Parent = weak.Make(parent)
+			}
+			i += len(elms) - 1
+		}
+	}
+	fill(host)
+}
@@ -0,0 +1,143 @@
+/******************************************************************************/
+/* html_component_test.go                                                     */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package document
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+const testComponentHTML = `<kaiju-component name="test-card">
+	<property name="title" default="Untitled"></property>
+	<style>:host { display: block; }</style>
+	<style>.title { color: red; }</style>
+	<template>
+		<div class="title">{{.title}}</div>
+		<div class="icon"><slot name="icon"><span class="noIcon">none</span></slot></div>
+		<div class="body"><slot></slot></div>
+	</template>
+</kaiju-component>`
+
+func testRegisterComponent(t *testing.T, src string) *Component {
+	t.Helper()
+	c, err := ParseComponent(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterComponent(c)
+	t.Cleanup(func() { UnregisterComponent(c.Name) })
+	return c
+}
+
+func TestParseComponent(t *testing.T) {
+	c := testRegisterComponent(t, testComponentHTML)
+	if c.Name != "test-card" {
+		t.Fatalf("expected the name test-card but got %s", c.Name)
+	}
+	want := []ComponentProperty{{Name: "title", Default: "Untitled"}}
+	if !slices.Equal(c.Properties, want) {
+		t.Fatalf("expected the properties %v but got %v", want, c.Properties)
+	}
+	if c.Style != ":host { display: block; }.title { color: red; }" {
+		t.Fatalf("unexpected style %q", c.Style)
+	}
+	if found, ok := FindComponent("TEST-CARD"); !ok || found != c {
+		t.Fatal("expected the component to be registered")
+	}
+}
+
+func TestParseComponentErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing definition": `<div></div>`,
+		"name without dash":  `<kaiju-component name="card"><template></template></kaiju-component>`,
+		"missing template":   `<kaiju-component name="a-card"></kaiju-component>`,
+		"bad template":       `<kaiju-component name="a-card"><template>{{.title</template></kaiju-component>`,
+	}
+	for name, src := range tests {
+		if _, err := ParseComponent(src); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestExpandComponents(t *testing.T) {
+	c := testRegisterComponent(t, testComponentHTML)
+	h := NewHTML(`<body>
+		<test-card id="a" title="Hello"><img slot="icon" id="img"><p id="text">Content</p></test-card>
+		<test-card id="b"></test-card>
+	</body>`)
+	d := &Document{}
+	d.expandComponents(h, nil)
+	a := h.FindElementById("a")
+	if a.Attribute(rules.ComponentHostAttribute) != "test-card" {
+		t.Fatal("expected the host to be marked")
+	}
+	title := a.Children[0]
+	if !title.HasClass("title") || title.Attribute(rules.ComponentScopeAttribute) != "test-card" {
+		t.Fatal("expected the template elements to be scoped to the component")
+	}
+	if title.Children[0].Data != "Hello" {
+		t.Fatalf("expected the title property, got %q", title.Children[0].Data)
+	}
+	icon := a.Children[1]
+	if len(icon.Children) != 1 || icon.Children[0] != h.FindElementById("img") {
+		t.Fatal("expected the named slot to hold the content with the slot attribute")
+	}
+	body := a.Children[2]
+	text := h.FindElementById("text")
+	if len(body.Children) != 1 || body.Children[0] != text || text.Parent.Value() != body {
+		t.Fatal("expected the default slot to hold the rest of the content")
+	}
+	if text.HasAttribute(rules.ComponentScopeAttribute) {
+		t.Fatal("expected the slotted content to not be scoped to the component")
+	}
+	b := h.FindElementById("b")
+	if b.Children[0].Children[0].Data != "Untitled" {
+		t.Fatal("expected the default of the property to be used")
+	}
+	if fallback := b.Children[1].Children[0]; !fallback.HasClass("noIcon") {
+		t.Fatal("expected an empty slot to keep its fallback content")
+	}
+	if !slices.Equal(d.Components(), []*Component{c}) {
+		t.Fatal("expected the document to list the component once")
+	}
+	runtime.KeepAlive(h)
+}
+
+func TestExpandNestedComponents(t *testing.T) {
+	testRegisterComponent(t, `<kaiju-component name="test-outer"><template>
+		<test-inner class="wrap"><slot></slot></test-inner>
+	</template></kaiju-component>`)
+	testRegisterComponent(t, `<kaiju-component name="test-inner"><template>
+		<section><slot></slot></section>
+	</template></kaiju-component>`)
+	testRegisterComponent(t, `<kaiju-component name="test-loop"><template>
+		<test-loop></test-loop>
+	</template></kaiju-component>`)
+	h := NewHTML(`<body><test-outer id="outer"><b id="bold">x</b></test-outer><test-loop id="loop"></test-loop></body>`)
+	d := &Document{}
+	d.expandComponents(h, nil)
+	inner := h.FindElementById("outer").Children[0]
+	if inner.Attribute(rules.ComponentHostAttribute) != "test-inner" ||
+		inner.Attribute(rules.ComponentScopeAttribute) != "test-outer" {
+		t.Fatal("expected the inner host to belong to the template of the outer component")
+	}
+	section := inner.Children[0]
+	if section.Attribute(rules.ComponentScopeAttribute) != "test-inner" {
+		t.Fatal("expected the inner template to be scoped to the inner component")
+	}
+	if len(section.Children) != 1 || section.Children[0] != h.FindElementById("bold") {
+		t.Fatal("expected the content to be passed through both slots")
+	}
+	loop := h.FindElementById("loop").Children[0]
+	if loop.HasAttribute(rules.ComponentHostAttribute) {
+		t.Fatal("expected a component used within itself to not be expanded")
+	}
+	runtime.KeepAlive(h)
+}
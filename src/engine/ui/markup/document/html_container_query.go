@@ -0,0 +1,63 @@
+/******************************************************************************/
+/* html_container_query.go                                                    */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import (
+	"runtime"
+	"weak"
+
+	"kaijuengine.com/engine"
+)
+
+// ContainerQuery is an @container query as it was tested against the size of
+// a container when the styles were last applied
+type ContainerQuery struct {
+	Matched  bool
+	Evaluate func() bool
+}
+
+// WatchContainerQueries replaces the container queries that are tested after
+// each layout, the styles are applied again when a container is resized so
+// that one of the queries changes its result
+func (d *Document) WatchContainerQueries(queries []ContainerQuery) {
+	d.containerQueries = queries
+	host := d.host.Value()
+	if len(queries) == 0 || host == nil || d.containerUpdateId.IsValid() {
+		return
+	}
+	wd := weak.Make(d)
+	d.containerUpdateId = host.UILateUpdater.AddUpdate(func(float64) {
+		if doc := wd.Value(); doc != nil {
+			doc.checkContainerQueries()
+		}
+	})
+	type containerCleanup struct {
+		host weak.Pointer[engine.Host]
+		id   engine.UpdateId
+	}
+	runtime.AddCleanup(d, func(c containerCleanup) {
+		if h := c.host.Value(); h != nil {
+			h.UILateUpdater.RemoveUpdate(&c.id)
+		}
+	}, containerCleanup{d.host, d.containerUpdateId})
+}
+
+func (d *Document) checkContainerQueries() {
+	for i := range d.containerQueries {
+		if d.containerQueries[i].Evaluate() != d.containerQueries[i].Matched {
+			d.ApplyStyles()
+			return
+		}
+	}
+}
+
+func (d *Document) stopWatchingContainerQueries() {
+	if host := d.host.Value(); host != nil {
+		host.UILateUpdater.RemoveUpdate(&d.containerUpdateId)
+	}
+	d.containerQueries = nil
+}
@@ -0,0 +1,229 @@
+/******************************************************************************/
+/* html_counters.go                                                           */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import (
+	"strconv"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+type counterSnapshot struct {
+	values     map[string][]int
+	quoteDepth int
+}
+
+type counterScope struct {
+	name  string
+	value int
+	// owner is the parent of the element that created the counter, the
+	// counter is in scope until the walk leaves the owner
+	owner *Element
+}
+
+type counterOp struct {
+	name  string
+	value int
+}
+
+type counterWalker struct {
+	scopes     []counterScope
+	quoteDepth int
+}
+
+// UpdateCounters walks the document in order resolving the counter-reset,
+// counter-set and counter-increment rules of the elements and their ::before
+// and ::after boxes. The values are then read by counter(), counters() and
+// the list item markers
+func (d *Document) UpdateCounters() {
+	w := counterWalker{}
+	for _, e := range d.TopElements {
+		w.visit(e)
+	}
+}
+
+// CounterValues returns the values of the named counter that are in scope for
+// the element, outermost first. Generated boxes (such as ::before) read the
+// values that were resolved for them on the element that generated them
+func (e *Element) CounterValues(name string) []int {
+	if snapshot, ok := e.counterSnapshot(); ok {
+		return snapshot.values[name]
+	}
+	return nil
+}
+
+// QuoteDepth returns how many quotes have been opened and not yet closed by
+// the generated content that comes before the element
+func (e *Element) QuoteDepth() int {
+	snapshot, _ := e.counterSnapshot()
+	return snapshot.quoteDepth
+}
+
+func (e *Element) counterSnapshot() (counterSnapshot, bool) {
+	origin, key := e, ""
+	if e.pseudo != "" {
+		if origin = e.Parent.Value(); origin == nil {
+			return counterSnapshot{}, false
+		}
+		if e.pseudo != PseudoMarker {
+			key = e.pseudo
+		}
+	}
+	snapshot, ok := origin.Stylizer.counters[key]
+	return snapshot, ok
+}
+
+func (w *counterWalker) visit(e *Element) {
+	if e.IsText() {
+		return
+	}
+	s := &e.Stylizer
+	s.counters = nil
+	parent := e.Parent.Value()
+	resets := counterOps(s.immediateValues("counter-reset", ""), 0)
+	sets := counterOps(s.immediateValues("counter-set", ""), 0)
+	increments := counterOps(s.immediateValues("counter-increment", ""), 1)
+	if !hasCounterOp(resets, listItemCounter) {
+		switch e.Data {
+		case "ol", "ul", "menu":
+			start := 1
+			if v, err := strconv.Atoi(e.Attribute("start")); err == nil {
+				start = v
+			}
+			resets = append(resets, counterOp{listItemCounter, start - 1})
+		}
+	}
+	if e.Data == "li" && !hasCounterOp(sets, listItemCounter) &&
+		!hasCounterOp(increments, listItemCounter) {
+		if v, err := strconv.Atoi(e.Attribute("value")); err == nil {
+			sets = append(sets, counterOp{listItemCounter, v})
+		} else {
+			increments = append(increments, counterOp{listItemCounter, 1})
+		}
+	}
+	w.apply(parent, resets, sets, increments)
+	w.snapshot(s, "")
+	if s.hasGeneratedContent(PseudoBefore) {
+		w.visitBox(e, PseudoBefore)
+	}
+	for _, c := range e.Children {
+		w.visit(c)
+	}
+	if s.hasGeneratedContent(PseudoAfter) {
+		w.visitBox(e, PseudoAfter)
+	}
+	w.popScopes(e)
+}
+
+// visitBox resolves the counters of a ::before or ::after box, these boxes
+// act as children of the element that generates them
+func (w *counterWalker) visitBox(e *Element, pseudo string) {
+	s := &e.Stylizer
+	w.apply(e, counterOps(s.immediateValues("counter-reset", pseudo), 0),
+		counterOps(s.immediateValues("counter-set", pseudo), 0),
+		counterOps(s.immediateValues("counter-increment", pseudo), 1))
+	w.snapshot(s, pseudo)
+	for _, v := range s.immediateValues("content", pseudo) {
+		switch v.Str {
+		case "open-quote", "no-open-quote":
+			w.quoteDepth++
+		case "close-quote", "no-close-quote":
+			w.quoteDepth = max(0, w.quoteDepth-1)
+		}
+	}
+}
+
+func (w *counterWalker) apply(owner *Element, resets, sets, increments []counterOp) {
+	for _, op := range resets {
+		if idx := w.find(op.name); idx >= 0 && w.scopes[idx].owner == owner {
+			w.scopes[idx].value = op.value
+		} else {
+			w.scopes = append(w.scopes, counterScope{op.name, op.value, owner})
+		}
+	}
+	for _, op := range sets {
+		w.instantiate(op.name, owner).value = op.value
+	}
+	for _, op := range increments {
+		w.instantiate(op.name, owner).value += op.value
+	}
+}
+
+// instantiate returns the innermost counter with the name, creating one that
+// starts at 0 if the counter is not in scope
+func (w *counterWalker) instantiate(name string, owner *Element) *counterScope {
+	idx := w.find(name)
+	if idx < 0 {
+		w.scopes = append(w.scopes, counterScope{name, 0, owner})
+		idx = len(w.scopes) - 1
+	}
+	return &w.scopes[idx]
+}
+
+func (w *counterWalker) find(name string) int {
+	for i := len(w.scopes) - 1; i >= 0; i-- {
+		if w.scopes[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (w *counterWalker) popScopes(owner *Element) {
+	end := len(w.scopes)
+	for end > 0 && w.scopes[end-1].owner == owner {
+		end--
+	}
+	w.scopes = w.scopes[:end]
+}
+
+func (w *counterWalker) snapshot(s *ElementLayoutStylizer, key string) {
+	if len(w.scopes) == 0 && w.quoteDepth == 0 {
+		return
+	}
+	snapshot := counterSnapshot{
+		values:     make(map[string][]int, len(w.scopes)),
+		quoteDepth: w.quoteDepth,
+	}
+	for _, scope := range w.scopes {
+		snapshot.values[scope.name] = append(snapshot.values[scope.name], scope.value)
+	}
+	if s.counters == nil {
+		s.counters = make(map[string]counterSnapshot)
+	}
+	s.counters[key] = snapshot
+}
+
+// counterOps reads the "name [integer]" pairs of counter-reset, counter-set
+// and counter-increment, names without an integer use the fallback value
+func counterOps(values []rules.PropertyValue, fallback int) []counterOp {
+	ops := []counterOp{}
+	for i := 0; i < len(values); i++ {
+		name := values[i].Str
+		if name == "none" {
+			return nil
+		}
+		op := counterOp{name, fallback}
+		if i+1 < len(values) {
+			if v, err := strconv.Atoi(values[i+1].Str); err == nil {
+				op.value = v
+				i++
+			}
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+func hasCounterOp(ops []counterOp, name string) bool {
+	for i := range ops {
+		if ops[i].name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+/******************************************************************************/
+/* html_counters_test.go                                                      */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+	"weak"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+func testCounterRule(elm *Element, property, pseudo string, values ...string) {
+	r := rules.Rule{Property: property, PseudoElement: pseudo}
+	for _, v := range values {
+		r.Values = append(r.Values, rules.PropertyValue{Str: v})
+	}
+	elm.Stylizer.styleRules = append(elm.Stylizer.styleRules, r)
+}
+
+func TestUpdateCountersListItems(t *testing.T) {
+	root := NewHTML(`<ol id="list" start="3"><li id="a">A</li><li id="b" value="10">B</li><li id="c">C<ul><li id="d">D</li></ul></li></ol>`)
+	doc := Document{TopElements: []*Element{root}}
+	doc.UpdateCounters()
+	tests := map[string][]int{
+		"a": {3},
+		"b": {10},
+		"c": {11},
+		"d": {11, 1},
+	}
+	for id, want := range tests {
+		if got := root.FindElementById(id).CounterValues(listItemCounter); !slices.Equal(got, want) {
+			t.Errorf("%s expected list-item %v, got %v", id, want, got)
+		}
+	}
+	runtime.KeepAlive(root)
+}
+
+func TestUpdateCountersScopes(t *testing.T) {
+	root := NewHTML(`<div id="doc"><h1 id="h1a">A</h1><h2 id="h2a">A.1</h2><h2 id="h2b">A.2</h2><h1 id="h1b">B</h1><h2 id="h2c">B.1</h2></div>`)
+	testCounterRule(root.FindElementById("doc"), "counter-reset", "", "chapter")
+	for _, h := range root.FindElementsByTag("h1") {
+		testCounterRule(h, "counter-increment", "", "chapter")
+		testCounterRule(h, "counter-reset", "", "section")
+	}
+	for _, h := range root.FindElementsByTag("h2") {
+		testCounterRule(h, "counter-increment", "", "section", "2")
+	}
+	doc := Document{TopElements: []*Element{root}}
+	doc.UpdateCounters()
+	tests := []struct {
+		id      string
+		chapter int
+		section int
+	}{
+		{"h2a", 1, 2},
+		{"h2b", 1, 4},
+		{"h1b", 2, 0},
+		{"h2c", 2, 2},
+	}
+	for _, test := range tests {
+		elm := root.FindElementById(test.id)
+		if got := elm.CounterValues("chapter"); !slices.Equal(got, []int{test.chapter}) {
+			t.Errorf("%s expected chapter %d, got %v", test.id, test.chapter, got)
+		}
+		if got := elm.CounterValues("section"); !slices.Equal(got, []int{test.section}) {
+			t.Errorf("%s expected section %d, got %v", test.id, test.section, got)
+		}
+	}
+	runtime.KeepAlive(root)
+}
+
+func TestUpdateCountersGeneratedBoxes(t *testing.T) {
+	root := NewHTML(`<div id="doc"><q id="q1">A<q id="q2">B</q></q><p id="p">C</p></div>`)
+	for _, q := range root.FindElementsByTag("q") {
+		testCounterRule(q, "content", PseudoBefore, "open-quote")
+		testCounterRule(q, "content", PseudoAfter, "close-quote")
+	}
+	p := root.FindElementById("p")
+	testCounterRule(p, "content", PseudoBefore, "counter")
+	testCounterRule(p, "counter-increment", PseudoBefore, "note")
+	doc := Document{TopElements: []*Element{root}}
+	doc.UpdateCounters()
+	q2 := root.FindElementById("q2")
+	if depth := (&Element{Parent: weak.Make(q2), pseudo: PseudoBefore}).QuoteDepth(); depth != 1 {
+		t.Errorf("the inner quote should open at depth 1, got %d", depth)
+	}
+	if depth := p.QuoteDepth(); depth != 0 {
+		t.Errorf("all quotes should be closed before the paragraph, got %d", depth)
+	}
+	if got := (&Element{Parent: weak.Make(p), pseudo: PseudoBefore}).CounterValues("note"); !slices.Equal(got, []int{1}) {
+		t.Errorf("the ::before box should see its own increment, got %v", got)
+	}
+	if got := p.CounterValues("note"); got != nil {
+		t.Errorf("the paragraph comes before its ::before box, got %v", got)
+	}
+	runtime.KeepAlive(root)
+}
@@ -14,7 +14,9 @@ import (
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/systems/events"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/canvas"
 	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering"
 
 	"golang.org/x/net/html"
 )
@@ -30,6 +32,14 @@ type Element struct {
 	Children   []*Element
 	Stylizer   ElementLayoutStylizer
 	UIEventIds [ui.EventTypeEnd][]events.Id
+	// pseudo is the name of the pseudo-element (such as "before") for boxes
+	// that are generated by the element that is their Parent
+	pseudo         string
+	listeners      []eventListener
+	lastListenerId ListenerId
+	// eventBridges are the UI events that dispatch to the listeners of the
+	// element and its ancestors
+	eventBridges [ui.EventTypeEnd]events.Id
 }
 
 func (e *Element) ClassList() []string {
@@ -49,7 +59,8 @@ func (e *Element) HasClass(class string) bool {
 // SetAttribute sets an attribute on the HTML element. If the attribute key is
 // "class", it parses the value as space-separated class names and sets them
 // using SetClasses. Otherwise, it updates the existing attribute or adds a new
-// one to the element's attribute list.
+// one to the element's attribute list. Styles are not applied again, so call
+// [Document.ApplyStyles] afterwards if a selector depends on the attribute.
 func (e *Element) SetAttribute(key, value string) {
 	if key == "class" {
 		e.SetClasses(strings.Split(value, " ")...)
@@ -152,6 +163,23 @@ func (e *Element) IsImage() bool {
 	return e.Data == "img"
 }
 
+func (e *Element) IsCanvas() bool {
+	return e.Data == "canvas"
+}
+
+// CanvasContext is the drawing context of a <canvas> element, it is nil for
+// any other element or before the canvas UI has been created
+func (e *Element) CanvasContext() *canvas.Context {
+	if !e.IsCanvas() || e.UI == nil || !e.UI.IsType(ui.ElementTypeCanvas) {
+		return nil
+	}
+	return e.UI.ToCanvas().Context()
+}
+
+func (e *Element) IsVideo() bool {
+	return e.Data == "video"
+}
+
 func (e *Element) IsSelect() bool {
 	return e.Data == "select"
 }
@@ -243,6 +271,16 @@ func (e *Element) Attribute(key string) string {
 	return ""
 }
 
+// AttributeKeys returns the keys of the attributes of the element in the
+// order they were declared
+func (e *Element) AttributeKeys() []string {
+	keys := make([]string, len(e.attr))
+	for i := range e.attr {
+		keys[i] = e.attr[i].Key
+	}
+	return keys
+}
+
 func (e *Element) HasAttribute(key string) bool {
 	for i := range e.attr {
 		if e.attr[i].Key == key {
@@ -252,6 +290,27 @@ func (e *Element) HasAttribute(key string) bool {
 	return false
 }
 
+// Direction resolves the dir attribute of the element, it comes from the
+// closest element that sets it and is left-to-right when none do. The auto
+// value uses the first strong character in the text of the element
+func (e *Element) Direction() ui.Direction {
+	for elm := e; elm != nil; elm = elm.Parent.Value() {
+		switch strings.ToLower(elm.Attribute("dir")) {
+		case "ltr":
+			return ui.DirectionLTR
+		case "rtl":
+			return ui.DirectionRTL
+		case "auto":
+			dir, ok := rendering.FirstStrongDirection([]rune(elm.textContent()))
+			if ok && dir == rendering.TextDirectionRTL {
+				return ui.DirectionRTL
+			}
+			return ui.DirectionLTR
+		}
+	}
+	return ui.DirectionLTR
+}
+
 func (e *Element) FindElementById(id string) *Element {
 	if e.Attribute("id") == id {
 		return e
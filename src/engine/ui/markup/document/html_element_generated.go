@@ -0,0 +1,349 @@
+/******************************************************************************/
+/* html_element_generated.go                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import (
+	"fmt"
+	"strings"
+	"weak"
+
+	"golang.org/x/net/html"
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/helpers"
+	"kaijuengine.com/engine/ui/markup/css/rules"
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering"
+)
+
+const (
+	PseudoBefore      = "before"
+	PseudoAfter       = "after"
+	PseudoMarker      = "marker"
+	PseudoPlaceholder = "placeholder"
+	PseudoSelection   = "selection"
+
+	listItemCounter = "list-item"
+)
+
+// controlPseudoProperties are the properties that can be used to style the
+// parts of the text controls, any other property would style the control
+var controlPseudoProperties = map[string]map[string]struct{}{
+	PseudoPlaceholder: {"color": {}},
+	PseudoSelection:   {"background-color": {}},
+}
+
+type generatedBox struct {
+	elm *Element
+	key string
+}
+
+// PseudoElement returns the name of the pseudo-element this element is a
+// generated box for, such as "before", or an empty string for elements that
+// come from the document
+func (e *Element) PseudoElement() string { return e.pseudo }
+
+// GeneratedBox returns the ::before, ::after or ::marker box the element
+// generated for its content, if there is one
+func (e *Element) GeneratedBox(pseudo string) (*Element, bool) {
+	box, ok := e.Stylizer.generated[pseudo]
+	if !ok {
+		return nil, false
+	}
+	return box.elm, true
+}
+
+// immediateValues returns the values of the last rule for the property that
+// is not tied to a state (such as :hover)
+func (s *ElementLayoutStylizer) immediateValues(property, pseudo string) []rules.PropertyValue {
+	for i := len(s.styleRules) - 1; i >= 0; i-- {
+		r := &s.styleRules[i]
+		if r.Property == property && r.PseudoElement == pseudo &&
+			r.Invocation == rules.RuleInvokeImmediate {
+			return r.Values
+		}
+	}
+	return nil
+}
+
+func (s *ElementLayoutStylizer) hasGeneratedContent(pseudo string) bool {
+	return contentGeneratesBox(s.immediateValues("content", pseudo))
+}
+
+func contentGeneratesBox(values []rules.PropertyValue) bool {
+	return len(values) > 0 && values[0].Str != "none" && values[0].Str != "normal"
+}
+
+// stateRules filters the rules down to those that apply in the current state,
+// state rules (such as :hover) replace the immediate rules they overlap
+func (s *ElementLayoutStylizer) stateRules(in []rules.Rule) []rules.Rule {
+	a := make([]rules.Rule, 0, len(in))
+	b := make([]rules.Rule, 0, len(in))
+	for i := range in {
+		if s.currentState != rules.RuleInvokeImmediate && in[i].Invocation == rules.RuleInvokeImmediate {
+			a = append(a, in[i])
+		} else if in[i].Invocation.Matches(s.currentState) {
+			b = append(b, in[i])
+		}
+	}
+	out := make([]rules.Rule, 0, len(a)+len(b))
+	for i := range a {
+		overridden := false
+		for j := 0; j < len(b) && !overridden; j++ {
+			overridden = a[i].Property == b[j].Property && a[i].PseudoElement == b[j].PseudoElement
+		}
+		if !overridden {
+			out = append(out, a[i])
+		}
+	}
+	return append(out, b...)
+}
+
+func (s *ElementLayoutStylizer) processPseudoElements(elm *Element, pseudoRules []rules.Rule, host *engine.Host) []error {
+	problems := []error{}
+	byName := map[string][]rules.Rule{}
+	for _, r := range s.stateRules(pseudoRules) {
+		byName[r.PseudoElement] = append(byName[r.PseudoElement], r)
+	}
+	for name := range controlPseudoProperties {
+		if len(byName[name]) > 0 {
+			problems = append(problems, processControlPseudo(elm, name, byName[name], host)...)
+		}
+	}
+	// Replaced elements, such as inputs and images, have no content that
+	// boxes could be generated into
+	if elm.UIPanel != nil && elm.UI.IsType(ui.ElementTypePanel) {
+		if markerRules, ok := s.markerRules(elm, byName[PseudoMarker]); ok {
+			s.updateGeneratedBox(elm, PseudoMarker, markerRules)
+		} else {
+			s.removeGeneratedBox(elm, PseudoMarker)
+		}
+		for _, name := range []string{PseudoBefore, PseudoAfter} {
+			if contentGeneratesBox(lastRuleValues(byName[name], "content")) {
+				s.updateGeneratedBox(elm, name, byName[name])
+			} else {
+				s.removeGeneratedBox(elm, name)
+			}
+		}
+	}
+	s.restyleGenerated = false
+	return problems
+}
+
+// processControlPseudo styles the ::placeholder and ::selection of inputs
+// and text areas. The properties are given an element that stands in for the
+// pseudo-element so they know to style that part of the control
+func processControlPseudo(elm *Element, name string, pseudoRules []rules.Rule, host *engine.Host) []error {
+	if !elm.UI.IsType(ui.ElementTypeInput) && !elm.UI.IsType(ui.ElementTypeTextArea) {
+		return nil
+	}
+	part := &Element{
+		Type:    html.ElementNode,
+		Data:    elm.Data,
+		attr:    elm.attr,
+		UI:      elm.UI,
+		UIPanel: elm.UIPanel,
+		Parent:  weak.Make(elm),
+		pseudo:  name,
+	}
+	problems := []error{}
+	allowed := controlPseudoProperties[name]
+	for i := range pseudoRules {
+		if _, ok := allowed[pseudoRules[i].Property]; !ok {
+			problems = append(problems, fmt.Errorf("the %s property is not supported on ::%s",
+				pseudoRules[i].Property, name))
+			continue
+		}
+		if p, ok := LinkedPropertyMap[pseudoRules[i].Property]; ok {
+			if err := p.Process(elm.UIPanel, part, pseudoRules[i].Values, host); err != nil {
+				problems = append(problems, err)
+			}
+		}
+	}
+	return problems
+}
+
+// markerRules returns the rules for the ::marker box of a list item. Unless
+// the ::marker sets its own content, the content is the list-item counter in
+// the (inherited) list-style-type
+func (s *ElementLayoutStylizer) markerRules(elm *Element, markerRules []rules.Rule) ([]rules.Rule, bool) {
+	if elm.IsText() || elm.Data != "li" {
+		return nil, false
+	}
+	if content := lastRuleValues(markerRules, "content"); content != nil {
+		return markerRules, contentGeneratesBox(content)
+	}
+	style := elm.listStyleType()
+	if style == "none" {
+		return nil, false
+	}
+	content := rules.Rule{Property: "content"}
+	if helpers.IsQuotedString(style) {
+		content.Values = []rules.PropertyValue{{Str: style}}
+	} else {
+		content.Values = []rules.PropertyValue{
+			{Str: "counter", Args: []string{listItemCounter, style}},
+			{Str: `"` + helpers.CounterSuffix(style) + `"`},
+		}
+	}
+	return append([]rules.Rule{content}, markerRules...), true
+}
+
+// listStyleType finds the list-style-type of the element, which is inherited
+// from the closest ancestor that sets it
+func (e *Element) listStyleType() string {
+	for p := e; p != nil; p = p.Parent.Value() {
+		if v := p.Stylizer.immediateValues("list-style-type", ""); len(v) > 0 {
+			return v[0].Str
+		}
+		for _, v := range p.Stylizer.immediateValues("list-style", "") {
+			if helpers.IsCounterStyle(v.Str) || helpers.IsQuotedString(v.Str) {
+				return v.Str
+			}
+		}
+	}
+	return "disc"
+}
+
+// InheritedValues returns the values of the property from the element or the
+// closest ancestor that sets it, which is how inherited properties resolve
+func (e *Element) InheritedValues(property string) []rules.PropertyValue {
+	for p := e; p != nil; p = p.Parent.Value() {
+		if v := p.Stylizer.immediateValues(property, ""); len(v) > 0 {
+			return v
+		}
+	}
+	return nil
+}
+
+func lastRuleValues(in []rules.Rule, property string) []rules.PropertyValue {
+	for i := len(in) - 1; i >= 0; i-- {
+		if in[i].Property == property {
+			return in[i].Values
+		}
+	}
+	return nil
+}
+
+func (s *ElementLayoutStylizer) updateGeneratedBox(elm *Element, name string, boxRules []rules.Rule) {
+	box, ok := s.generated[name]
+	if !ok {
+		if box = newGeneratedBox(elm, name); box == nil {
+			return
+		}
+		if s.generated == nil {
+			s.generated = make(map[string]*generatedBox)
+		}
+		s.generated[name] = box
+	}
+	s.placeGeneratedBox(elm, name, box)
+	key := generatedRulesKey(boxRules)
+	if ok && box.key == key && !s.restyleGenerated {
+		return
+	}
+	box.key = key
+	box.elm.Stylizer.ClearRules()
+	for i := range boxRules {
+		r := boxRules[i].Clone()
+		r.PseudoElement = ""
+		r.Invocation = rules.RuleInvokeImmediate
+		box.elm.Stylizer.AddRule(r)
+	}
+	box.elm.UI.SetDirty(ui.DirtyTypeGenerated)
+}
+
+func (s *ElementLayoutStylizer) removeGeneratedBox(elm *Element, name string) {
+	box, ok := s.generated[name]
+	if !ok {
+		return
+	}
+	delete(s.generated, name)
+	elm.UIPanel.RemoveChild(box.elm.UI)
+	if host := elm.UI.Host(); host != nil {
+		host.DestroyEntity(box.elm.UI.Entity())
+	}
+}
+
+// newGeneratedBox creates the panel of a ::before, ::after or ::marker box
+// along with the label its content is written to
+func newGeneratedBox(elm *Element, name string) *generatedBox {
+	man := elm.UI.Manager()
+	if man == nil {
+		return nil
+	}
+	panel := man.Add().ToPanel()
+	panel.Init(nil, ui.ElementTypePanel)
+	panel.SetOverflow(ui.OverflowVisible)
+	label := man.Add().ToLabel()
+	label.Init("")
+	label.SetJustify(rendering.FontJustifyStart)
+	label.SetBaseline(rendering.FontBaselineTop)
+	label.SetBGColor(matrix.ColorTransparent())
+	panel.AddChild(label.Base())
+	boxElm := &Element{
+		Type:    html.ElementNode,
+		Data:    "::" + name,
+		UI:      panel.Base(),
+		UIPanel: panel,
+		Parent:  weak.Make(elm),
+		pseudo:  name,
+	}
+	text := &Element{
+		Type:   html.TextNode,
+		UI:     label.Base(),
+		Parent: weak.Make(boxElm),
+	}
+	boxElm.Children = []*Element{text}
+	boxElm.Stylizer = ElementLayoutStylizer{element: weak.Make(boxElm)}
+	text.Stylizer = ElementLayoutStylizer{element: weak.Make(text)}
+	panel.Base().Layout().Stylizer = &boxElm.Stylizer
+	return &generatedBox{elm: boxElm}
+}
+
+// placeGeneratedBox keeps the boxes in the order marker, before, the content
+// of the element and then after
+func (s *ElementLayoutStylizer) placeGeneratedBox(elm *Element, name string, box *generatedBox) {
+	parent := elm.UIPanel
+	children := parent.Base().Entity().Children
+	boxEntity := box.elm.UI.Entity()
+	switch name {
+	case PseudoMarker, PseudoBefore:
+		idx := 0
+		if _, ok := s.generated[PseudoMarker]; ok && name == PseudoBefore {
+			idx = 1
+		}
+		if idx >= len(children) || children[idx] != boxEntity {
+			if boxEntity.Parent == parent.Base().Entity() {
+				parent.RemoveChild(box.elm.UI)
+			}
+			parent.InsertChild(box.elm.UI, idx)
+		}
+	case PseudoAfter:
+		if len(children) == 0 || children[len(children)-1] != boxEntity {
+			if boxEntity.Parent == parent.Base().Entity() {
+				parent.RemoveChild(box.elm.UI)
+			}
+			parent.AddChild(box.elm.UI)
+		}
+	}
+}
+
+func generatedRulesKey(in []rules.Rule) string {
+	sb := strings.Builder{}
+	for i := range in {
+		sb.WriteString(in[i].Property)
+		sb.WriteByte(':')
+		for _, v := range in[i].Values {
+			sb.WriteString(v.Str)
+			sb.WriteByte('(')
+			sb.WriteString(strings.Join(v.Args, ","))
+			sb.WriteString(") ")
+		}
+		sb.WriteByte(';')
+	}
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+/******************************************************************************/
+/* html_element_generated_test.go                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import (
+	"runtime"
+	"testing"
+
+	"kaijuengine.com/engine/ui/markup/css/rules"
+)
+
+func TestMarkerRulesUseInheritedListStyleType(t *testing.T) {
+	root := NewHTML(`<ol id="list"><li id="a">A</li></ol><ul id="plain"><li id="b">B</li></ul>`)
+	testCounterRule(root.FindElementById("list"), "list-style-type", "", "upper-roman")
+	testCounterRule(root.FindElementById("plain"), "list-style-type", "", "none")
+	a := root.FindElementById("a")
+	got, ok := a.Stylizer.markerRules(a, nil)
+	if !ok || len(got) != 1 {
+		t.Fatalf("expected a marker content rule, got %#v", got)
+	}
+	values := got[0].Values
+	if values[0].Str != "counter" || values[0].Args[1] != "upper-roman" || values[1].Str != `". "` {
+		t.Fatalf("unexpected marker content %#v", values)
+	}
+	b := root.FindElementById("b")
+	if _, ok := b.Stylizer.markerRules(b, nil); ok {
+		t.Fatal("list-style-type none should not generate a marker")
+	}
+	custom := []rules.Rule{{Property: "content", Values: []rules.PropertyValue{{Str: `"-"`}}}}
+	if got, ok := b.Stylizer.markerRules(b, custom); !ok || len(got) != 1 {
+		t.Fatal("::marker content should generate a marker even without a list style")
+	}
+	runtime.KeepAlive(root)
+}
+
+func TestStateRulesReplaceImmediateRules(t *testing.T) {
+	s := ElementLayoutStylizer{currentState: rules.RuleInvokeHover}
+	in := []rules.Rule{
+		{Property: "color", PseudoElement: PseudoBefore},
+		{Property: "content", PseudoElement: PseudoBefore},
+		{Property: "color", PseudoElement: PseudoBefore, Invocation: rules.RuleInvokeHover},
+		{Property: "color", PseudoElement: PseudoBefore, Invocation: rules.RuleInvokeFocus},
+	}
+	got := s.stateRules(in)
+	if len(got) != 2 || got[0].Property != "content" || got[1].Invocation != rules.RuleInvokeHover {
+		t.Fatalf("unexpected state rules %#v", got)
+	}
+}
@@ -9,11 +9,13 @@ package document
 import (
 	"errors"
 	"slices"
+	"strings"
 	"weak"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/systems/events"
 	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup/css/animation"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/klib"
 )
@@ -24,6 +26,26 @@ var (
 		"visibility": {},
 		"display":    {},
 	}
+	// paintProperties only change how the element is drawn, animating them
+	// doesn't move or resize anything so the layout is left alone
+	paintProperties = map[string]struct{}{
+		"color":               {},
+		"background-color":    {},
+		"opacity":             {},
+		"border-color":        {},
+		"border-top-color":    {},
+		"border-right-color":  {},
+		"border-bottom-color": {},
+		"border-left-color":   {},
+		"outline-color":       {},
+		"box-shadow":          {},
+		"text-shadow":         {},
+		"transform":           {},
+		"translate":           {},
+		"rotate":              {},
+		"scale":               {},
+		"filter":              {},
+	}
 	panelOnlyProperties = map[string]struct{}{
 		"width":        {},
 		"height":       {},
@@ -41,6 +63,11 @@ func isPanelOnlyProperty(property string) bool {
 	return ok
 }
 
+func isPaintProperty(property string) bool {
+	_, ok := paintProperties[property]
+	return ok
+}
+
 type CSSProperty interface {
 	Key() string
 	Process(panel *ui.Panel, elm *Element, values []rules.PropertyValue, host *engine.Host) error
@@ -60,6 +87,10 @@ type ElementLayoutStylizer struct {
 		missId  events.Id
 		focusId events.Id
 		blurId  events.Id
+		// visibleFocusId and visibleBlurId are for text fields, which show
+		// their focus however it was given
+		visibleFocusId events.Id
+		visibleBlurId  events.Id
 	}
 	activeEvt struct {
 		enterId events.Id
@@ -69,6 +100,22 @@ type ElementLayoutStylizer struct {
 	}
 	currentState     rules.RuleInvoke
 	interestedStates rules.RuleInvoke
+	animator         animation.Animator
+	keyframes        map[string]rules.Keyframes
+	// styled are the rules of the last time the element was styled, the
+	// animations are stepped from them between styling
+	styled           []rules.Rule
+	animated         bool
+	frameQueued      bool
+	generated        map[string]*generatedBox
+	counters         map[string]counterSnapshot
+	restyleGenerated bool
+}
+
+// SetKeyframes sets the @keyframes of the style sheet that the element's
+// animations are looked up in
+func (s *ElementLayoutStylizer) SetKeyframes(keyframes map[string]rules.Keyframes) {
+	s.keyframes = keyframes
 }
 
 func (s *ElementLayoutStylizer) HasRule(rule string) bool {
@@ -80,6 +127,8 @@ func (s *ElementLayoutStylizer) HasRule(rule string) bool {
 	return false
 }
 
+func (s *ElementLayoutStylizer) HasRules() bool { return len(s.styleRules) > 0 }
+
 func (s *ElementLayoutStylizer) ClearRules() {
 	s.styleRules = s.styleRules[:0]
 	e := s.element.Value()
@@ -92,6 +141,8 @@ func (s *ElementLayoutStylizer) ClearRules() {
 	e.UI.RemoveEvent(ui.EventTypeMiss, s.focusEvt.missId)
 	e.UI.RemoveEvent(ui.EventTypeFocus, s.focusEvt.focusId)
 	e.UI.RemoveEvent(ui.EventTypeBlur, s.focusEvt.blurId)
+	e.UI.RemoveEvent(ui.EventTypeFocus, s.focusEvt.visibleFocusId)
+	e.UI.RemoveEvent(ui.EventTypeBlur, s.focusEvt.visibleBlurId)
 	e.UI.RemoveEvent(ui.EventTypeEnter, s.activeEvt.enterId)
 	e.UI.RemoveEvent(ui.EventTypeExit, s.activeEvt.exitId)
 	e.UI.RemoveEvent(ui.EventTypeDown, s.activeEvt.downId)
@@ -108,6 +159,8 @@ func (s *ElementLayoutStylizer) ClearRules() {
 	s.focusEvt.missId = 0
 	s.focusEvt.focusId = 0
 	s.focusEvt.blurId = 0
+	s.focusEvt.visibleFocusId = 0
+	s.focusEvt.visibleBlurId = 0
 	s.activeEvt.enterId = 0
 	s.activeEvt.exitId = 0
 	s.activeEvt.downId = 0
@@ -115,6 +168,8 @@ func (s *ElementLayoutStylizer) ClearRules() {
 	s.activateEvtId = 0
 	s.deactivateEvtId = 0
 	s.interestedStates = rules.RuleInvokeImmediate
+	s.animated = false
+	s.restyleGenerated = true
 }
 
 func (s *ElementLayoutStylizer) AddRule(rule rules.Rule) {
@@ -129,6 +184,9 @@ func (s *ElementLayoutStylizer) AddRule(rule rules.Rule) {
 	}
 	rule.Sort = p.Sort()
 	s.styleRules = append(s.styleRules, rule)
+	if strings.HasPrefix(rule.Property, "animation") || strings.HasPrefix(rule.Property, "transition") {
+		s.animated = true
+	}
 	s.interestedStates = s.interestedStates.With(rule.Invocation)
 	if rule.Invocation&rules.RuleInvokeHover != 0 {
 		if s.hoverEvtId == 0 {
@@ -156,6 +214,15 @@ func (s *ElementLayoutStylizer) AddRule(rule rules.Rule) {
 			})
 		}
 	}
+	if rule.Invocation&rules.RuleInvokeFocusVisible != 0 && s.focusEvt.visibleFocusId == 0 &&
+		(elm.UI.IsType(ui.ElementTypeInput) || elm.UI.IsType(ui.ElementTypeTextArea)) {
+		s.focusEvt.visibleFocusId = elm.UI.AddEvent(ui.EventTypeFocus, func() {
+			s.setState(rules.RuleInvokeFocusVisible, true)
+		})
+		s.focusEvt.visibleBlurId = elm.UI.AddEvent(ui.EventTypeBlur, func() {
+			s.setState(rules.RuleInvokeFocusVisible, false)
+		})
+	}
 	if rule.Invocation&rules.RuleInvokeActive != 0 {
 		if s.activeEvt.enterId == 0 {
 			s.activeEvt.enterId = elm.UI.AddEvent(ui.EventTypeEnter, func() {
@@ -179,6 +246,7 @@ func (s *ElementLayoutStylizer) AddRule(rule rules.Rule) {
 		}
 	}
 	s.syncValidationState()
+	s.syncMediaState()
 }
 
 func (s *ElementLayoutStylizer) setState(state rules.RuleInvoke, enabled bool) {
@@ -201,6 +269,12 @@ func (s *ElementLayoutStylizer) setState(state rules.RuleInvoke, enabled bool) {
 	}
 }
 
+// SetFocusVisible sets if the element shows that it has the focus, which
+// :focus-visible rules wait for
+func (s *ElementLayoutStylizer) SetFocusVisible(visible bool) {
+	s.setState(rules.RuleInvokeFocusVisible, visible)
+}
+
 func (s *ElementLayoutStylizer) syncValidationState() {
 	if s.interestedStates&(rules.RuleInvokeInvalid|rules.RuleInvokeValid) == 0 {
 		return
@@ -227,6 +301,23 @@ func (s *ElementLayoutStylizer) syncValidationState() {
 	}
 }
 
+func (s *ElementLayoutStylizer) syncMediaState() {
+	if s.interestedStates&(rules.RuleInvokePlaying|rules.RuleInvokePaused) == 0 {
+		return
+	}
+	elm := s.element.Value()
+	if elm == nil || !elm.UI.IsType(ui.ElementTypeVideo) {
+		return
+	}
+	if elm.UI.ToVideo().IsPlaying() {
+		s.currentState &^= rules.RuleInvokePaused
+		s.currentState = s.currentState.With(rules.RuleInvokePlaying)
+	} else {
+		s.currentState &^= rules.RuleInvokePlaying
+		s.currentState = s.currentState.With(rules.RuleInvokePaused)
+	}
+}
+
 func (s *ElementLayoutStylizer) ProcessStyle(layout *ui.Layout) []error {
 	return s.processRules(layout)
 }
@@ -238,10 +329,16 @@ func (s *ElementLayoutStylizer) processRules(layout *ui.Layout) []error {
 		return []error{errors.New("missing element when processing rules")}
 	}
 	s.syncValidationState()
+	s.syncMediaState()
 	host := elm.UI.Host()
 	a := make([]rules.Rule, 0, len(s.styleRules))
 	b := make([]rules.Rule, 0, len(s.styleRules))
+	pseudoRules := make([]rules.Rule, 0)
 	for i := 0; i < len(s.styleRules); i++ {
+		if s.styleRules[i].PseudoElement != "" {
+			pseudoRules = append(pseudoRules, s.styleRules[i])
+			continue
+		}
 		if s.currentState != rules.RuleInvokeImmediate && s.styleRules[i].Invocation == rules.RuleInvokeImmediate {
 			a = append(a, s.styleRules[i])
 		} else if s.styleRules[i].Invocation.Matches(s.currentState) {
@@ -262,6 +359,25 @@ func (s *ElementLayoutStylizer) processRules(layout *ui.Layout) []error {
 		}
 	}
 	all := append(a, b...)
+	if s.animated || s.animator.Active() {
+		s.styled = append(s.styled[:0], all...)
+	}
+	if animated, err := s.animate(all, host); err != nil {
+		problems = append(problems, err)
+	} else {
+		all = animated
+	}
+	problems = append(problems, s.applyRules(layout, elm, all, host)...)
+	if len(pseudoRules) > 0 || len(s.generated) > 0 || (!elm.IsText() && elm.Data == "li") {
+		problems = append(problems, s.processPseudoElements(elm, pseudoRules, host)...)
+	}
+	return problems
+}
+
+// applyRules processes the rules on the element in the order of their
+// properties, linked properties are merged first
+func (s *ElementLayoutStylizer) applyRules(layout *ui.Layout, elm *Element, all []rules.Rule, host *engine.Host) []error {
+	problems := make([]error, 0)
 	// Look ahead to see if any upcoming properties can be merged
 	for i := 0; i < len(all); i++ {
 		if p, ok := LinkedPropertyMap[all[i].Property]; ok {
@@ -288,9 +404,79 @@ func (s *ElementLayoutStylizer) processRules(layout *ui.Layout) []error {
 	return problems
 }
 
+// animate replaces the values of the rules that are being animated or
+// transitioned. The state of the animations is kept across ClearRules so that
+// class or state changes can start transitions from the current values
+func (s *ElementLayoutStylizer) animate(all []rules.Rule, host *engine.Host) ([]rules.Rule, error) {
+	if !s.animated && !s.animator.Active() {
+		s.animator = animation.Animator{}
+		return all, nil
+	}
+	if host == nil {
+		return all, nil
+	}
+	out, active, err := s.animator.Apply(all, s.keyframes, host.Runtime())
+	if err != nil {
+		return all, err
+	}
+	for i := range out {
+		if p, ok := LinkedPropertyMap[out[i].Property]; ok {
+			out[i].Sort = p.Sort()
+		}
+	}
+	if active && !s.frameQueued {
+		s.frameQueued = true
+		we := s.element
+		host.RunNextFrame(func() {
+			if e := we.Value(); e != nil {
+				e.Stylizer.frameQueued = false
+				e.Stylizer.stepAnimations(host)
+			}
+		})
+	}
+	return out, nil
+}
+
+// stepAnimations applies the animated values of the next frame straight to
+// the element without styling it again. The layout is only updated when a
+// property that changes it is animated, and the element is only styled again
+// once an animation stops so its properties go back to the styled values
+func (s *ElementLayoutStylizer) stepAnimations(host *engine.Host) {
+	elm := s.element.Value()
+	if elm == nil || !elm.UI.IsActive() {
+		return
+	}
+	prev := slices.Clone(s.animator.Animated())
+	out, err := s.animate(s.styled, host)
+	if err != nil {
+		elm.UI.SetDirty(ui.DirtyTypeGenerated)
+		return
+	}
+	animated := s.animator.Animated()
+	for i := range prev {
+		if !slices.Contains(animated, prev[i]) {
+			elm.UI.SetDirty(ui.DirtyTypeGenerated)
+			return
+		}
+	}
+	stepped := make([]rules.Rule, 0, len(animated))
+	affectsLayout := false
+	for i := range out {
+		if slices.Contains(animated, out[i].Property) {
+			stepped = append(stepped, out[i])
+			affectsLayout = affectsLayout || !isPaintProperty(out[i].Property)
+		}
+	}
+	s.applyRules(elm.UI.Layout(), elm, stepped, host)
+	if affectsLayout {
+		elm.UI.SetDirty(ui.DirtyTypeLayout)
+	}
+}
+
 func (s *ElementLayoutStylizer) clone(newElm *Element) ElementLayoutStylizer {
 	out := ElementLayoutStylizer{
-		element: weak.Make(newElm),
+		element:   weak.Make(newElm),
+		keyframes: s.keyframes,
 	}
 	for i := range s.styleRules {
 		out.AddRule(s.styleRules[i].Clone())
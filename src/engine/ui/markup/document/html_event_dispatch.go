@@ -0,0 +1,323 @@
+/******************************************************************************/
+/* html_event_dispatch.go                                                     */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package document
+
+import (
+	"slices"
+
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/matrix"
+)
+
+type EventPhase = int
+
+const (
+	EventPhaseNone = EventPhase(iota)
+	EventPhaseCapturing
+	EventPhaseAtTarget
+	EventPhaseBubbling
+)
+
+type ListenerId = int
+
+type EventListener = func(evt *DOMEvent)
+
+type eventListener struct {
+	id        ListenerId
+	eventType string
+	capture   bool
+	call      EventListener
+}
+
+// DOMEvent is the event given to the listeners of an element. Its fields are
+// read through methods so the event is usable the same way from Lua
+type DOMEvent struct {
+	eventType          string
+	target             *Element
+	currentTarget      *Element
+	phase              EventPhase
+	bubbles            bool
+	cancelable         bool
+	detail             any
+	shift, ctrl        bool
+	alt, meta          bool
+	position           matrix.Vec2
+	propagationStopped bool
+	immediateStopped   bool
+	defaultPrevented   bool
+}
+
+// uiEventNames are the DOM names of the UI events that are dispatched
+// through the element tree
+var uiEventNames = [ui.EventTypeEnd]string{
+	ui.EventTypeEnter:       "mouseenter",
+	ui.EventTypeMove:        "mousemove",
+	ui.EventTypeExit:        "mouseleave",
+	ui.EventTypeClick:       "click",
+	ui.EventTypeRightClick:  "contextmenu",
+	ui.EventTypeDoubleClick: "dblclick",
+	ui.EventTypeDown:        "mousedown",
+	ui.EventTypeUp:          "mouseup",
+	ui.EventTypeMiss:        "miss",
+	ui.EventTypeDragStart:   "dragstart",
+	ui.EventTypeDrop:        "drop",
+	ui.EventTypeDropEnter:   "dragenter",
+	ui.EventTypeDropExit:    "dragleave",
+	ui.EventTypeDragEnd:     "dragend",
+	ui.EventTypeScroll:      "wheel",
+	ui.EventTypeFocus:       "focus",
+	ui.EventTypeBlur:        "blur",
+	ui.EventTypeSubmit:      "submit",
+	ui.EventTypeChange:      "change",
+	ui.EventTypeKeyDown:     "keydown",
+	ui.EventTypeKeyUp:       "keyup",
+}
+
+// eventNameAliases are the other names the UI events are known by, the
+// hover events of the UI are enter/exit so mouseover and mouseout don't
+// bubble like they would in a browser
+var eventNameAliases = map[string]string{
+	"mouseover":  "mouseenter",
+	"mouseout":   "mouseleave",
+	"mouseexit":  "mouseleave",
+	"rightclick": "contextmenu",
+	"mousewheel": "wheel",
+}
+
+var nonBubblingEvents = map[string]struct{}{
+	"mouseenter": {},
+	"mouseleave": {},
+	"miss":       {},
+	"dragenter":  {},
+	"dragleave":  {},
+	"focus":      {},
+	"blur":       {},
+}
+
+func normalizeEventName(eventType string) string {
+	if alias, ok := eventNameAliases[eventType]; ok {
+		return alias
+	}
+	return eventType
+}
+
+func eventBubbles(eventType string) bool {
+	_, ok := nonBubblingEvents[eventType]
+	return !ok
+}
+
+func uiEventType(eventType string) (ui.EventType, bool) {
+	for i := range uiEventNames {
+		if uiEventNames[i] != "" && uiEventNames[i] == eventType {
+			return ui.EventType(i), true
+		}
+	}
+	return ui.EventTypeInvalid, false
+}
+
+// NewEvent creates an event that can be raised on an element with
+// DispatchEvent
+func NewEvent(eventType string, bubbles, cancelable bool) *DOMEvent {
+	return &DOMEvent{
+		eventType:  normalizeEventName(eventType),
+		bubbles:    bubbles,
+		cancelable: cancelable,
+	}
+}
+
+// NewCustomEvent creates a bubbling, cancelable event that carries detail to
+// its listeners
+func NewCustomEvent(eventType string, detail any) *DOMEvent {
+	evt := NewEvent(eventType, true, true)
+	evt.detail = detail
+	return evt
+}
+
+func (e *DOMEvent) Type() string              { return e.eventType }
+func (e *DOMEvent) Target() *Element          { return e.target }
+func (e *DOMEvent) CurrentTarget() *Element   { return e.currentTarget }
+func (e *DOMEvent) Phase() EventPhase         { return e.phase }
+func (e *DOMEvent) Bubbles() bool             { return e.bubbles }
+func (e *DOMEvent) Cancelable() bool          { return e.cancelable }
+func (e *DOMEvent) Detail() any               { return e.detail }
+func (e *DOMEvent) ShiftKey() bool            { return e.shift }
+func (e *DOMEvent) CtrlKey() bool             { return e.ctrl }
+func (e *DOMEvent) AltKey() bool              { return e.alt }
+func (e *DOMEvent) MetaKey() bool             { return e.meta }
+func (e *DOMEvent) Position() matrix.Vec2     { return e.position }
+func (e *DOMEvent) DefaultPrevented() bool    { return e.defaultPrevented }
+func (e *DOMEvent) PropagationStopped() bool  { return e.propagationStopped }
+func (e *DOMEvent) StopPropagation()          { e.propagationStopped = true }
+func (e *DOMEvent) StopImmediatePropagation() { e.immediateStopped = true; e.propagationStopped = true }
+
+// PreventDefault marks the event as canceled, for events raised by the UI the
+// built in behavior has already happened by the time listeners are called,
+// the result is only reported back to the code that dispatched the event
+func (e *DOMEvent) PreventDefault() {
+	if e.cancelable {
+		e.defaultPrevented = true
+	}
+}
+
+// AddEventListener registers a listener to be called when an event of the
+// given type reaches the element. Capture listeners are called on the way
+// down to the target, others at the target and while the event bubbles up
+func (e *Element) AddEventListener(eventType string, listener EventListener, capture bool) ListenerId {
+	eventType = normalizeEventName(eventType)
+	e.lastListenerId++
+	e.listeners = append(e.listeners, eventListener{
+		id:        e.lastListenerId,
+		eventType: eventType,
+		capture:   capture,
+		call:      listener,
+	})
+	if t, ok := uiEventType(eventType); ok {
+		e.refreshEventBridge(t)
+	}
+	return e.lastListenerId
+}
+
+// RemoveEventListener removes a listener that was returned by
+// AddEventListener
+func (e *Element) RemoveEventListener(id ListenerId) {
+	for i := range e.listeners {
+		if e.listeners[i].id == id {
+			eventType := e.listeners[i].eventType
+			e.listeners = slices.Delete(e.listeners, i, i+1)
+			if t, ok := uiEventType(eventType); ok {
+				e.refreshEventBridge(t)
+			}
+			return
+		}
+	}
+}
+
+// DispatchEvent raises the event on the element. The listeners of its
+// ancestors are called in the capture phase, then the listeners of the
+// element (capture listeners first) and, if the event bubbles, the ancestors
+// again in reverse. The
+// return is false if a listener canceled the event with PreventDefault
+func (e *Element) DispatchEvent(evt *DOMEvent) bool {
+	evt.target = e
+	evt.propagationStopped = false
+	evt.immediateStopped = false
+	path := []*Element{}
+	for p := e.Parent.Value(); p != nil; p = p.Parent.Value() {
+		path = append(path, p)
+	}
+	evt.phase = EventPhaseCapturing
+	for i := len(path) - 1; i >= 0 && !evt.propagationStopped; i-- {
+		path[i].invokeListeners(evt, true)
+	}
+	if !evt.propagationStopped {
+		evt.phase = EventPhaseAtTarget
+		e.invokeListeners(evt, true)
+		e.invokeListeners(evt, false)
+	}
+	if evt.bubbles {
+		evt.phase = EventPhaseBubbling
+		for i := 0; i < len(path) && !evt.propagationStopped; i++ {
+			path[i].invokeListeners(evt, false)
+		}
+	}
+	evt.phase = EventPhaseNone
+	evt.currentTarget = nil
+	return !evt.defaultPrevented
+}
+
+func (e *Element) invokeListeners(evt *DOMEvent, capture bool) {
+	evt.currentTarget = e
+	// Listeners may be added or removed by the listeners being called
+	listeners := slices.Clone(e.listeners)
+	for i := range listeners {
+		l := &listeners[i]
+		if evt.immediateStopped {
+			return
+		}
+		if l.eventType != evt.eventType || l.capture != capture {
+			continue
+		}
+		l.call(evt)
+	}
+}
+
+func (e *Element) hasListener(eventType string) bool {
+	for i := range e.listeners {
+		if e.listeners[i].eventType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// observesDescendants returns true if the element has a listener that
+// would be called for the event when it is raised on one of its descendants
+func (e *Element) observesDescendants(eventType string) bool {
+	bubbles := eventBubbles(eventType)
+	for i := range e.listeners {
+		if e.listeners[i].eventType == eventType && (bubbles || e.listeners[i].capture) {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshEventBridge makes sure the element, and its descendants, only
+// handle the UI event if a listener can observe it. Elements without one
+// are left alone so the event keeps going through them to what is behind
+func (e *Element) refreshEventBridge(evtType ui.EventType) {
+	name := uiEventNames[evtType]
+	observed := false
+	for p := e.Parent.Value(); p != nil && !observed; p = p.Parent.Value() {
+		observed = p.observesDescendants(name)
+	}
+	e.bridgeEvent(evtType, name, observed)
+}
+
+func (e *Element) refreshEventBridges() {
+	for i := range uiEventNames {
+		if uiEventNames[i] != "" {
+			e.refreshEventBridge(ui.EventType(i))
+		}
+	}
+}
+
+func (e *Element) bridgeEvent(evtType ui.EventType, name string, observed bool) {
+	if e.UI != nil {
+		needed := observed || e.hasListener(name)
+		if needed && e.eventBridges[evtType] == 0 {
+			e.eventBridges[evtType] = e.UI.AddEvent(evtType, func() {
+				e.dispatchUIEvent(evtType)
+			})
+		} else if !needed && e.eventBridges[evtType] != 0 {
+			e.UI.RemoveEvent(evtType, e.eventBridges[evtType])
+			e.eventBridges[evtType] = 0
+		}
+	}
+	observed = observed || e.observesDescendants(name)
+	for i := range e.Children {
+		e.Children[i].bridgeEvent(evtType, name, observed)
+	}
+}
+
+func (e *Element) dispatchUIEvent(evtType ui.EventType) {
+	name := uiEventNames[evtType]
+	evt := NewEvent(name, eventBubbles(name), true)
+	if host := e.UI.Host(); host != nil && host.Window != nil {
+		kb := &host.Window.Keyboard
+		evt.shift = kb.HasShift()
+		evt.ctrl = kb.HasCtrl()
+		evt.alt = kb.HasAlt()
+		evt.meta = kb.HasMeta()
+		evt.position = host.Window.Mouse.Position()
+	}
+	target := e
+	// Text is not a target of its own, it's raised on the element it's in
+	if target.IsText() && target.Parent.Value() != nil {
+		target = target.Parent.Value()
+	}
+	target.DispatchEvent(evt)
+}
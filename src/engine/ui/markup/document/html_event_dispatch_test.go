@@ -0,0 +1,148 @@
+/******************************************************************************/
+/* html_event_dispatch_test.go                                                */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package document
+
+import (
+	"slices"
+	"testing"
+)
+
+const testEventHTML = `<html><body>
+<div id="list"><div id="row"><span id="cell">text</span></div></div>
+</body></html>`
+
+func testEventElements(t *testing.T) (list, row, cell *Element) {
+	t.Helper()
+	body := NewHTML(testEventHTML).Body()
+	list = body.FindElementById("list")
+	row = body.FindElementById("row")
+	cell = body.FindElementById("cell")
+	if list == nil || row == nil || cell == nil {
+		t.Fatal("failed to find the test elements")
+	}
+	return list, row, cell
+}
+
+func TestDispatchEventPhaseOrder(t *testing.T) {
+	list, row, cell := testEventElements(t)
+	order := []string{}
+	record := func(name string) EventListener {
+		return func(evt *DOMEvent) {
+			order = append(order, name)
+			if evt.Target() != cell {
+				t.Errorf("%s: expected the cell to be the target", name)
+			}
+		}
+	}
+	list.AddEventListener("click", record("list bubble"), false)
+	list.AddEventListener("click", record("list capture"), true)
+	row.AddEventListener("click", record("row bubble"), false)
+	row.AddEventListener("click", record("row capture"), true)
+	cell.AddEventListener("click", record("cell"), false)
+	cell.DispatchEvent(NewEvent("click", true, true))
+	want := []string{"list capture", "row capture", "cell", "row bubble", "list bubble"}
+	if !slices.Equal(order, want) {
+		t.Fatalf("expected %v but got %v", want, order)
+	}
+}
+
+func TestDispatchEventDelegatedCurrentTarget(t *testing.T) {
+	list, _, cell := testEventElements(t)
+	var current *Element
+	list.AddEventListener("click", func(evt *DOMEvent) {
+		current = evt.CurrentTarget()
+		if evt.Phase() != EventPhaseBubbling {
+			t.Errorf("expected the bubbling phase, got %d", evt.Phase())
+		}
+	}, false)
+	cell.DispatchEvent(NewEvent("click", true, true))
+	if current != list {
+		t.Fatal("expected the delegated listener to see the list as the current target")
+	}
+}
+
+func TestDispatchEventNonBubbling(t *testing.T) {
+	list, _, cell := testEventElements(t)
+	bubbled, captured := false, false
+	list.AddEventListener("focus", func(*DOMEvent) { bubbled = true }, false)
+	list.AddEventListener("focus", func(*DOMEvent) { captured = true }, true)
+	cell.DispatchEvent(NewEvent("focus", eventBubbles("focus"), false))
+	if bubbled {
+		t.Error("focus should not bubble")
+	}
+	if !captured {
+		t.Error("capture listeners should see events that don't bubble")
+	}
+}
+
+func TestDispatchEventStopPropagation(t *testing.T) {
+	list, row, cell := testEventElements(t)
+	listCalled, secondCalled := false, false
+	list.AddEventListener("click", func(*DOMEvent) { listCalled = true }, false)
+	row.AddEventListener("click", func(evt *DOMEvent) { evt.StopPropagation() }, false)
+	row.AddEventListener("click", func(*DOMEvent) { secondCalled = true }, false)
+	cell.DispatchEvent(NewEvent("click", true, true))
+	if listCalled {
+		t.Error("stopPropagation should keep the event from reaching the list")
+	}
+	if !secondCalled {
+		t.Error("stopPropagation should still call the other listeners of the row")
+	}
+	secondCalled = false
+	row.AddEventListener("click", func(evt *DOMEvent) { evt.StopImmediatePropagation() }, true)
+	row.DispatchEvent(NewEvent("click", true, true))
+	if secondCalled {
+		t.Error("stopImmediatePropagation should skip the remaining listeners")
+	}
+}
+
+func TestDispatchCustomEventPreventDefault(t *testing.T) {
+	list, _, cell := testEventElements(t)
+	var detail any
+	list.AddEventListener("select-row", func(evt *DOMEvent) {
+		detail = evt.Detail()
+		evt.PreventDefault()
+	}, false)
+	if cell.DispatchEvent(NewCustomEvent("select-row", 7)) {
+		t.Error("expected the canceled event to report false")
+	}
+	if detail != 7 {
+		t.Errorf("expected the detail to be 7, got %v", detail)
+	}
+	if !cell.DispatchEvent(NewEvent("select-row", true, false)) {
+		t.Error("events that aren't cancelable can't be canceled")
+	}
+}
+
+func TestRemoveEventListenerAndAliases(t *testing.T) {
+	_, row, _ := testEventElements(t)
+	calls := 0
+	id := row.AddEventListener("mouseover", func(*DOMEvent) { calls++ }, false)
+	row.DispatchEvent(NewEvent("mouseenter", false, false))
+	row.RemoveEventListener(id)
+	row.DispatchEvent(NewEvent("mouseenter", false, false))
+	if calls != 1 {
+		t.Fatalf("expected 1 call but got %d", calls)
+	}
+}
+
+func TestAttributeHandlerConsumesEvent(t *testing.T) {
+	body := NewHTML(`<html><body><div id="outer" onclick="outer">` +
+		`<div id="inner" onclick="inner"></div></div></body></html>`).Body()
+	calls := []string{}
+	funcMap := map[string]func(*Element){
+		"outer": func(*Element) { calls = append(calls, "outer") },
+		"inner": func(*Element) { calls = append(calls, "inner") },
+	}
+	outer := body.FindElementById("outer")
+	inner := body.FindElementById("inner")
+	setupEvents(outer, funcMap)
+	setupEvents(inner, funcMap)
+	inner.DispatchEvent(NewEvent("click", true, true))
+	if !slices.Equal(calls, []string{"inner"}) {
+		t.Fatalf("expected only the inner handler to be called, got %v", calls)
+	}
+}
@@ -0,0 +1,512 @@
+/******************************************************************************/
+/* html_navigation.go                                                         */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import (
+	"math"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"weak"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/platform/hid"
+)
+
+type NavDirection int
+
+const (
+	NavDirectionUp NavDirection = iota
+	NavDirectionDown
+	NavDirectionLeft
+	NavDirectionRight
+)
+
+const (
+	// navRepeatDelay is how long a direction is held before it repeats and
+	// navRepeatRate is the time between the repeats after that
+	navRepeatDelay = 0.4
+	navRepeatRate  = 0.12
+	// navStickThreshold is how far a stick is pushed to count as a direction
+	navStickThreshold = 0.5
+	// navOrthogonalWeight makes elements that are off to the side of the
+	// direction further away than elements in line with it
+	navOrthogonalWeight = 2
+)
+
+var navAttributes = [...]string{
+	NavDirectionUp:    "nav-up",
+	NavDirectionDown:  "nav-down",
+	NavDirectionLeft:  "nav-left",
+	NavDirectionRight: "nav-right",
+}
+
+type documentNavigation struct {
+	focused  *Element
+	visible  bool
+	groups   map[*Element]*Element
+	updateId engine.UpdateId
+	repeat   navRepeat
+}
+
+// navRect is the area of an element with the y axis going down the screen
+type navRect struct {
+	left, top, right, bottom float32
+}
+
+func (r navRect) centerX() float32 { return (r.left + r.right) * 0.5 }
+
+// toDown rotates the rect so that moving in the direction is moving down
+func (r navRect) toDown(dir NavDirection) navRect {
+	switch dir {
+	case NavDirectionUp:
+		return navRect{r.left, -r.bottom, r.right, -r.top}
+	case NavDirectionRight:
+		return navRect{r.top, r.left, r.bottom, r.right}
+	case NavDirectionLeft:
+		return navRect{r.top, -r.right, r.bottom, -r.left}
+	default:
+		return r
+	}
+}
+
+// navScore is how far the move from one rect to the other is in the
+// direction, the second return is false if the rect isn't in that direction
+func navScore(from, to navRect, dir NavDirection) (float32, bool) {
+	from, to = from.toDown(dir), to.toDown(dir)
+	if to.top+to.bottom <= from.top+from.bottom || to.bottom <= from.bottom {
+		return 0, false
+	}
+	primary := max(0, to.top-from.bottom)
+	gap := max(0, to.left-from.right, from.left-to.right)
+	offset := float32(math.Abs(float64(to.centerX() - from.centerX())))
+	return primary + gap*navOrthogonalWeight + offset*0.1, true
+}
+
+// nearestInDirection returns the index of the rect that is the closest move
+// in the direction or -1
+func nearestInDirection(from navRect, rects []navRect, dir NavDirection) int {
+	best, bestScore := -1, float32(math.MaxFloat32)
+	for i := range rects {
+		if score, ok := navScore(from, rects[i], dir); ok && score < bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// navRepeat turns a held direction into moves, one when it is pressed and
+// then repeated while it stays held
+type navRepeat struct {
+	dir   NavDirection
+	held  bool
+	timer float64
+}
+
+func (r *navRepeat) step(dir NavDirection, held bool, deltaTime float64) bool {
+	if !held {
+		r.held = false
+		return false
+	}
+	if !r.held || r.dir != dir {
+		r.dir, r.held, r.timer = dir, true, navRepeatDelay
+		return true
+	}
+	r.timer -= deltaTime
+	if r.timer > 0 {
+		return false
+	}
+	r.timer = navRepeatRate
+	return true
+}
+
+func elementNavRect(elm *Element) navRect {
+	p, _, s := elm.UI.Entity().Transform.WorldTransform()
+	hw, hh := s.X()*0.5, s.Y()*0.5
+	return navRect{p.X() - hw, -(p.Y() + hh), p.X() + hw, -(p.Y() - hh)}
+}
+
+// IsFocusable returns true if navigation can put the focus on the element.
+// Controls and links are focusable, a tabindex of 0 or more makes any
+// element focusable and a negative tabindex takes it out of navigation
+func (e *Element) IsFocusable() bool {
+	if e.UI == nil || e.IsText() || e.pseudo != "" || !e.UI.IsActive() || e.UI.IsDisabled() {
+		return false
+	}
+	return e.isFocusableByMarkup()
+}
+
+// isFocusableByMarkup is the part of IsFocusable that only depends on the
+// tag and attributes of the element
+func (e *Element) isFocusableByMarkup() bool {
+	if e.HasAttribute("tabindex") {
+		idx, err := strconv.Atoi(strings.TrimSpace(e.Attribute("tabindex")))
+		return err == nil && idx >= 0
+	}
+	switch {
+	case e.IsInput():
+		return e.Attribute("type") != "hidden"
+	case e.IsButton(), e.IsTextArea(), e.IsSelect():
+		return true
+	case e.Data == "a":
+		return e.HasAttribute("href")
+	}
+	return false
+}
+
+func (e *Element) isWithin(ancestor *Element) bool {
+	for p := e; p != nil; p = p.Parent.Value() {
+		if p == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Element) isEditingText() bool {
+	switch e.UI.Type() {
+	case ui.ElementTypeInput:
+		return e.UI.ToInput().IsFocused()
+	case ui.ElementTypeTextArea:
+		return e.UI.ToTextArea().IsFocused()
+	}
+	return false
+}
+
+// focusTrap returns the element that navigation is kept within, which is the
+// last shown element with the focus-trap attribute, such as a modal
+func (d *Document) focusTrap() *Element {
+	for i := len(d.Elements) - 1; i >= 0; i-- {
+		elm := d.Elements[i]
+		if elm.UI != nil && elm.UI.IsActive() && elm.HasAttribute("focus-trap") {
+			return elm
+		}
+	}
+	return nil
+}
+
+func (d *Document) focusableElements(within *Element) []*Element {
+	out := make([]*Element, 0)
+	for _, elm := range d.Elements {
+		if elm.IsFocusable() && (within == nil || elm.isWithin(within)) {
+			out = append(out, elm)
+		}
+	}
+	return out
+}
+
+// FocusedElement returns the element that has the navigation focus
+func (d *Document) FocusedElement() (*Element, bool) {
+	elm := d.nav.focused
+	if elm == nil || !slices.Contains(d.Elements, elm) {
+		return nil, false
+	}
+	return elm, true
+}
+
+// FocusElement gives the navigation focus to the element, it shows the
+// focus (:focus-visible) if the last focus was given by navigation
+func (d *Document) FocusElement(elm *Element) {
+	d.focusElement(elm, d.nav.visible)
+}
+
+func (d *Document) focusElement(elm *Element, visible bool) {
+	prev, _ := d.FocusedElement()
+	d.nav.visible = visible
+	if prev == elm {
+		if elm != nil {
+			elm.Stylizer.SetFocusVisible(visible)
+		}
+		return
+	}
+	if prev != nil {
+		prev.Stylizer.SetFocusVisible(false)
+		if prev.isEditingText() {
+			removeTextFocus(prev)
+		} else {
+			prev.UI.ExecuteEvent(ui.EventTypeBlur)
+		}
+	}
+	d.nav.focused = elm
+	d.invalidateScreenReader()
+	if elm == nil {
+		return
+	}
+	for p := elm.Parent.Value(); p != nil; p = p.Parent.Value() {
+		if p.HasAttribute("focus-group") {
+			if d.nav.groups == nil {
+				d.nav.groups = map[*Element]*Element{}
+			}
+			d.nav.groups[p] = elm
+		}
+	}
+	elm.Stylizer.SetFocusVisible(visible)
+	elm.UI.ExecuteEvent(ui.EventTypeFocus)
+	ui.ScrollIntoView(elm.UI)
+}
+
+func removeTextFocus(elm *Element) {
+	switch elm.UI.Type() {
+	case ui.ElementTypeInput:
+		elm.UI.ToInput().RemoveFocus()
+	case ui.ElementTypeTextArea:
+		elm.UI.ToTextArea().RemoveFocus()
+	}
+}
+
+// BlurFocusedElement takes the navigation focus off of the focused element
+func (d *Document) BlurFocusedElement() { d.focusElement(nil, d.nav.visible) }
+
+// FocusFirst focuses the element with the autofocus attribute or else the
+// first focusable element, within the focus trap if there is one
+func (d *Document) FocusFirst() bool {
+	all := d.focusableElements(d.focusTrap())
+	if len(all) == 0 {
+		return false
+	}
+	target := all[0]
+	if idx := slices.IndexFunc(all, func(e *Element) bool { return e.HasAttribute("autofocus") }); idx >= 0 {
+		target = all[idx]
+	}
+	d.focusElement(target, true)
+	return true
+}
+
+// FocusNext moves the focus to the next (or previous) focusable element in
+// document order, wrapping around at the ends
+func (d *Document) FocusNext(reverse bool) bool {
+	all := d.focusableElements(d.focusTrap())
+	if len(all) == 0 {
+		return false
+	}
+	idx := -1
+	if elm, ok := d.FocusedElement(); ok {
+		idx = slices.Index(all, elm)
+	}
+	if reverse {
+		idx = (max(idx, 0) - 1 + len(all)) % len(all)
+	} else {
+		idx = (idx + 1) % len(all)
+	}
+	d.focusElement(all[idx], true)
+	return true
+}
+
+// Navigate moves the focus to the closest focusable element in the
+// direction. The nav-up, nav-down, nav-left and nav-right attributes of the
+// focused element override the closest element with the id of the element
+// to go to, or "none" to stay put. Entering an element with the focus-group
+// attribute goes back to the element that was last focused within it, and
+// the focus doesn't leave an element with the focus-trap attribute
+func (d *Document) Navigate(dir NavDirection) bool {
+	current, ok := d.FocusedElement()
+	trap := d.focusTrap()
+	if !ok || !current.IsFocusable() || (trap != nil && !current.isWithin(trap)) {
+		return d.FocusFirst()
+	}
+	if target, found := d.navOverride(current, dir); found {
+		if target == nil {
+			return false
+		}
+		d.focusElement(target, true)
+		return true
+	}
+	all := d.focusableElements(trap)
+	rects := make([]navRect, len(all))
+	for i := range all {
+		if all[i] == current {
+			// An empty rect at infinity is never in a direction
+			rects[i] = navRect{math.MaxFloat32, math.MaxFloat32, math.MaxFloat32, math.MaxFloat32}
+			continue
+		}
+		rects[i] = elementNavRect(all[i])
+	}
+	idx := nearestInDirection(elementNavRect(current), rects, dir)
+	if idx < 0 {
+		return false
+	}
+	d.focusElement(d.enterFocusGroup(current, all[idx]), true)
+	return true
+}
+
+func (d *Document) navOverride(current *Element, dir NavDirection) (*Element, bool) {
+	id := strings.TrimSpace(current.Attribute(navAttributes[dir]))
+	if id == "" {
+		return nil, false
+	}
+	if id == "none" {
+		return nil, true
+	}
+	target, ok := d.GetElementById(strings.TrimPrefix(id, "#"))
+	if !ok || !target.IsFocusable() {
+		// Fall back to the closest element rather than getting stuck
+		return nil, false
+	}
+	return target, true
+}
+
+// enterFocusGroup swaps the target for the element last focused in the
+// outermost focus group that the move enters
+func (d *Document) enterFocusGroup(current, target *Element) *Element {
+	var group *Element
+	for p := target.Parent.Value(); p != nil; p = p.Parent.Value() {
+		if p.HasAttribute("focus-group") && !current.isWithin(p) {
+			group = p
+		}
+	}
+	if group == nil {
+		return target
+	}
+	if last, ok := d.nav.groups[group]; ok && last.IsFocusable() && slices.Contains(d.Elements, last) {
+		return last
+	}
+	return target
+}
+
+// ActivateFocused clicks the focused element, or starts editing it if it is
+// a text field
+func (d *Document) ActivateFocused() bool {
+	elm, ok := d.FocusedElement()
+	if !ok || !elm.IsFocusable() {
+		return false
+	}
+	switch elm.UI.Type() {
+	case ui.ElementTypeInput:
+		elm.UI.ToInput().Focus()
+	case ui.ElementTypeTextArea:
+		elm.UI.ToTextArea().Focus()
+	default:
+		elm.UI.ExecuteEvent(ui.EventTypeClick)
+	}
+	return true
+}
+
+// EnableNavigation moves the focus with the arrow keys, tab, the D-pad and
+// the left stick of the controllers. Enter, space and the A button activate
+// the focused element and the B button stops editing a text field. Using the
+// mouse or touch hides the focus until the next navigation
+func (d *Document) EnableNavigation() {
+	host := d.host.Value()
+	if host == nil || d.nav.updateId.IsValid() {
+		return
+	}
+	wd := weak.Make(d)
+	d.nav.updateId = host.UILateUpdater.AddUpdate(func(deltaTime float64) {
+		if doc := wd.Value(); doc != nil {
+			doc.updateNavigation(deltaTime)
+		}
+	})
+	type navigationCleanup struct {
+		host weak.Pointer[engine.Host]
+		id   engine.UpdateId
+	}
+	runtime.AddCleanup(d, func(c navigationCleanup) {
+		if h := c.host.Value(); h != nil {
+			h.UILateUpdater.RemoveUpdate(&c.id)
+		}
+	}, navigationCleanup{d.host, d.nav.updateId})
+}
+
+func (d *Document) DisableNavigation() {
+	if host := d.host.Value(); host != nil {
+		host.UILateUpdater.RemoveUpdate(&d.nav.updateId)
+	}
+	d.nav.repeat = navRepeat{}
+}
+
+func (d *Document) updateNavigation(deltaTime float64) {
+	host := d.host.Value()
+	if host == nil || host.Window == nil {
+		return
+	}
+	w := host.Window
+	if w.Cursor.Pressed() || w.Mouse.Moved() {
+		if elm, ok := d.FocusedElement(); ok && d.nav.visible {
+			d.nav.visible = false
+			elm.Stylizer.SetFocusVisible(false)
+		}
+	}
+	editing := false
+	if elm, ok := d.FocusedElement(); ok {
+		editing = elm.isEditingText()
+	}
+	kb := &w.Keyboard
+	if !editing {
+		keys := [...]hid.KeyboardKey{
+			NavDirectionUp:    hid.KeyboardKeyUp,
+			NavDirectionDown:  hid.KeyboardKeyDown,
+			NavDirectionLeft:  hid.KeyboardKeyLeft,
+			NavDirectionRight: hid.KeyboardKeyRight,
+		}
+		for dir, key := range keys {
+			if kb.KeyDown(key) {
+				d.Navigate(NavDirection(dir))
+			}
+		}
+		if kb.KeyDown(hid.KeyboardKeyTab) {
+			d.FocusNext(kb.HasShift())
+		}
+		if kb.KeyDown(hid.KeyboardKeyReturn) || kb.KeyDown(hid.KeyboardKeyEnter) ||
+			kb.KeyDown(hid.KeyboardKeySpace) {
+			d.ActivateFocused()
+		}
+	}
+	d.updateControllerNavigation(&w.Controller, editing, deltaTime)
+}
+
+func (d *Document) updateControllerNavigation(c *hid.Controller, editing bool, deltaTime float64) {
+	buttons := [...]hid.ControllerButton{
+		NavDirectionUp:    hid.ControllerButtonUp,
+		NavDirectionDown:  hid.ControllerButtonDown,
+		NavDirectionLeft:  hid.ControllerButtonLeft,
+		NavDirectionRight: hid.ControllerButtonRight,
+	}
+	dir, held := NavDirectionUp, false
+	for id := range hid.ControllerMaxDevices {
+		if !c.Available(id) {
+			continue
+		}
+		// The button checks of the controller take the id as a button
+		cid := hid.ControllerButton(id)
+		for b, button := range buttons {
+			if c.IsButtonDown(cid, button) || c.IsButtonHeld(cid, button) {
+				dir, held = NavDirection(b), true
+			}
+		}
+		if !held {
+			x := c.Axis(id, hid.ControllerAxisLeftHorizontal)
+			y := c.Axis(id, hid.ControllerAxisLeftVertical)
+			if max(x, -x, y, -y) >= navStickThreshold {
+				held = true
+				if max(x, -x) > max(y, -y) {
+					dir = NavDirectionRight
+					if x < 0 {
+						dir = NavDirectionLeft
+					}
+				} else {
+					dir = NavDirectionUp
+					if y < 0 {
+						dir = NavDirectionDown
+					}
+				}
+			}
+		}
+		if c.IsButtonDown(cid, hid.ControllerButtonA) {
+			d.ActivateFocused()
+		}
+		if c.IsButtonDown(cid, hid.ControllerButtonB) && editing {
+			if elm, ok := d.FocusedElement(); ok {
+				removeTextFocus(elm)
+			}
+		}
+	}
+	if d.nav.repeat.step(dir, held, deltaTime) {
+		d.Navigate(dir)
+	}
+}
@@ -0,0 +1,94 @@
+/******************************************************************************/
+/* html_navigation_test.go                                                    */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import "testing"
+
+func navTestRect(x, y, w, h float32) navRect {
+	return navRect{left: x, top: y, right: x + w, bottom: y + h}
+}
+
+func TestNearestInDirectionGrid(t *testing.T) {
+	// A 3x3 grid of 100x40 buttons with a 10 pixel gap
+	rects := make([]navRect, 0, 9)
+	for row := range 3 {
+		for col := range 3 {
+			rects = append(rects, navTestRect(float32(col)*110, float32(row)*50, 100, 40))
+		}
+	}
+	center := rects[4]
+	tests := []struct {
+		dir  NavDirection
+		want int
+	}{
+		{NavDirectionUp, 1},
+		{NavDirectionDown, 7},
+		{NavDirectionLeft, 3},
+		{NavDirectionRight, 5},
+	}
+	for _, test := range tests {
+		if got := nearestInDirection(center, rects, test.dir); got != test.want {
+			t.Errorf("direction %d: expected %d but got %d", test.dir, test.want, got)
+		}
+	}
+	if got := nearestInDirection(rects[0], rects, NavDirectionUp); got != -1 {
+		t.Errorf("expected nothing above the top row, got %d", got)
+	}
+	if got := nearestInDirection(rects[8], rects, NavDirectionRight); got != -1 {
+		t.Errorf("expected nothing right of the last column, got %d", got)
+	}
+}
+
+func TestNearestInDirectionPrefersInLine(t *testing.T) {
+	from := navTestRect(0, 0, 100, 40)
+	rects := []navRect{
+		// Closer but off to the side
+		navTestRect(300, 50, 100, 40),
+		// Further down but in line
+		navTestRect(10, 120, 100, 40),
+	}
+	if got := nearestInDirection(from, rects, NavDirectionDown); got != 1 {
+		t.Errorf("expected the element in line to be chosen, got %d", got)
+	}
+}
+
+func TestNavScoreOverlapping(t *testing.T) {
+	from := navTestRect(0, 0, 100, 100)
+	// A rect that starts within the focused one but reaches past it still
+	// counts as being below it
+	if _, ok := navScore(from, navTestRect(0, 50, 100, 100), NavDirectionDown); !ok {
+		t.Error("expected an overlapping rect that ends further down to be below")
+	}
+	if _, ok := navScore(from, navTestRect(10, 10, 50, 50), NavDirectionDown); ok {
+		t.Error("expected a rect within the focused rect to not be below it")
+	}
+}
+
+func TestNavRepeat(t *testing.T) {
+	r := navRepeat{}
+	if !r.step(NavDirectionDown, true, 0.016) {
+		t.Fatal("expected a move on the press")
+	}
+	if r.step(NavDirectionDown, true, navRepeatDelay-0.1) {
+		t.Fatal("expected no move before the repeat delay")
+	}
+	if !r.step(NavDirectionDown, true, 0.2) {
+		t.Fatal("expected a move after the repeat delay")
+	}
+	if r.step(NavDirectionDown, true, navRepeatRate*0.5) {
+		t.Fatal("expected no move before the repeat rate")
+	}
+	if !r.step(NavDirectionLeft, true, 0.016) {
+		t.Fatal("expected a change of direction to move right away")
+	}
+	if r.step(NavDirectionLeft, false, 0.016) {
+		t.Fatal("expected no move once released")
+	}
+	if !r.step(NavDirectionLeft, true, 0.016) {
+		t.Fatal("expected a move on the next press")
+	}
+}
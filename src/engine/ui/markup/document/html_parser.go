@@ -25,6 +25,7 @@ import (
 	"kaijuengine.com/engine/ui"
 	"kaijuengine.com/engine/ui/markup/css/rules"
 	"kaijuengine.com/engine/ui/markup/elements"
+	"kaijuengine.com/engine/video"
 	"kaijuengine.com/klib"
 	"kaijuengine.com/matrix"
 	"kaijuengine.com/platform/profiler/tracing"
@@ -119,6 +120,12 @@ type Document struct {
 	firstFocusElement *ui.UI
 	lastFocusElement  *ui.UI
 	funcMap           map[string]func(*Element)
+	containerQueries  []ContainerQuery
+	containerUpdateId engine.UpdateId
+	relational        relationalSelectors
+	components        []*Component
+	nav               documentNavigation
+	screenReader      documentScreenReader
 	//Debug      struct {
 	//	ReloadEventId events.Id
 	//}
@@ -212,7 +219,7 @@ func (h *Document) recacheElementTags() {
 		if e.IsText() {
 			continue
 		}
-		if tag, ok := elements.ElementMap[strings.ToLower(e.Data)]; ok {
+		if tag, ok := elementTag(e); ok {
 			h.tagElement(e, tag.Key())
 		}
 	}
@@ -277,7 +284,7 @@ func (d *Document) createUIElement(uiMan *ui.Manager, e *Element, parent *ui.Pan
 		txt = klib.ReplaceStringRecursive(txt, "  ", " ")
 		label := uiMan.Add().ToLabel()
 		label.Init(txt)
-		label.SetJustify(rendering.FontJustifyLeft)
+		label.SetJustify(rendering.FontJustifyStart)
 		label.SetBaseline(rendering.FontBaselineTop)
 		label.SetBGColor(matrix.ColorTransparent())
 		if parent := e.Parent.Value(); parent != nil && parent.IsButton() && parent.UI != nil {
@@ -288,7 +295,7 @@ func (d *Document) createUIElement(uiMan *ui.Manager, e *Element, parent *ui.Pan
 			label.SetBaseline(rendering.FontBaselineCenter)
 		}
 		appendElement(label.Base(), nil)
-	} else if tag, ok := elements.ElementMap[strings.ToLower(e.Data)]; ok {
+	} else if tag, ok := elementTag(e); ok {
 		panel := uiMan.Add().ToPanel()
 		host := uiMan.Host
 		if e.IsImage() {
@@ -333,6 +340,44 @@ func (d *Document) createUIElement(uiMan *ui.Manager, e *Element, parent *ui.Pan
 			if missing {
 				panel.SetColor(matrix.ColorMagenta())
 			}
+		} else if e.IsCanvas() {
+			// The same default size as an HTML canvas
+			width, height := 300, 150
+			if w, err := strconv.Atoi(e.Attribute("width")); err == nil && w >= 0 {
+				width = w
+			}
+			if h, err := strconv.Atoi(e.Attribute("height")); err == nil && h >= 0 {
+				height = h
+			}
+			cvs := panel.Base().ToCanvas()
+			cvs.Init(width, height)
+			panel = (*ui.Panel)(cvs)
+			e.Children = nil
+		} else if e.IsVideo() {
+			src := e.Attribute("src")
+			for i := 0; src == "" && i < len(e.Children); i++ {
+				if e.Children[i].Data == "source" {
+					src = e.Children[i].Attribute("src")
+				}
+			}
+			vid := panel.Base().ToVideo()
+			var stream *video.Stream
+			if src != "" {
+				var err error
+				if stream, err = video.Load(host, src); err != nil {
+					slog.Error("failed to load the video", "src", src, "error", err)
+				}
+			}
+			vid.Init(stream)
+			if stream != nil {
+				stream.Player.SetLoop(e.HasAttribute("loop"))
+				stream.Player.SetMuted(e.HasAttribute("muted"))
+				if e.HasAttribute("autoplay") {
+					stream.Player.Play()
+				}
+			}
+			panel = (*ui.Panel)(vid)
+			e.Children = nil
 		} else if e.IsInput() {
 			initTextInput := func(inputType ui.InputType) {
 				input := panel.Base().ToInput()
@@ -412,6 +457,10 @@ func (d *Document) createUIElement(uiMan *ui.Manager, e *Element, parent *ui.Pan
 		} else {
 			panel.Init(nil, ui.ElementTypePanel)
 			panel.SetOverflow(ui.OverflowVisible)
+			e.setupTablePart(panel, tag.Key())
+		}
+		if e.HasAttribute("dir") {
+			panel.SetDirection(e.Direction())
 		}
 		entry := appendElement(panel.Base(), panel)
 		syncElementDisabledState(entry)
@@ -446,6 +495,20 @@ func (d *Document) createUIElement(uiMan *ui.Manager, e *Element, parent *ui.Pan
 	}
 }
 
+// elementTag finds the type of the element, the hosts of components are known
+// by the name of their component
+func elementTag(e *Element) (elements.Element, bool) {
+	if name := e.Attribute(rules.ComponentHostAttribute); name != "" {
+		return componentHostTag{name}, true
+	}
+	tag, ok := elements.ElementMap[strings.ToLower(e.Data)]
+	return tag, ok
+}
+
+type componentHostTag struct{ name string }
+
+func (t componentHostTag) Key() string { return t.name }
+
 func (e *Element) textAreaInitialValue() string {
 	if e.HasAttribute("value") {
 		return e.Attribute("value")
@@ -556,6 +619,7 @@ func DocumentFromHTMLString(uiMan *ui.Manager, htmlStr string, withData any, fun
 		return parsed
 	}
 	h := NewHTML(transformed)
+	parsed.expandComponents(h, nil)
 	body := parsed.setupBody(h, uiMan)
 	bodyPanel := body.UIPanel
 	bodyPanel.Base().Entity().SetName("htmlBody")
@@ -618,6 +682,9 @@ func (d *Document) Destroy() {
 		}
 	}
 	clear(d.funcMap)
+	d.stopWatchingContainerQueries()
+	d.DisableNavigation()
+	d.DisableScreenReader()
 	*d = Document{}
 }
 
@@ -641,7 +708,7 @@ func (d *Document) indexElement(elm *Element) {
 	if group := elm.Attribute("group"); group != "" {
 		d.groups[group] = append(d.groups[group], elm)
 	}
-	if tag, ok := elements.ElementMap[strings.ToLower(elm.Data)]; ok {
+	if tag, ok := elementTag(elm); ok {
 		d.tagElement(elm, tag.Key())
 	}
 	for _, c := range elm.ClassList() {
@@ -673,7 +740,7 @@ func (d *Document) removeIndexedElement(elm *Element) {
 			}
 		}
 	}
-	if tag, ok := elements.ElementMap[strings.ToLower(elm.Data)]; ok {
+	if tag, ok := elementTag(elm); ok {
 		if _, ok := d.tagElements[tag.Key()]; ok {
 			for i := range d.tagElements[tag.Key()] {
 				if d.tagElements[tag.Key()][i] == elm {
@@ -704,6 +771,7 @@ func (d *Document) removeIndexedElement(elm *Element) {
 func (d *Document) AddChildElement(parent *Element, elm *Element) {
 	parent.Children = append(parent.Children, elm)
 	d.indexElement(elm)
+	d.documentChanged()
 }
 
 // RemoveElement removes the specified element from the document by first
@@ -744,6 +812,7 @@ func (d *Document) RemoveElementWithoutApplyStyles(elm *Element) {
 		}
 	}
 	d.removeIndexedElement(elm)
+	d.documentChanged()
 }
 
 // SetElementClassesWithoutApply updates the class list of the given element
@@ -787,6 +856,7 @@ func (d *Document) SetElementClassesWithoutApply(elm *Element, classes ...string
 		}
 	}
 	elm.UI.SetDirty(ui.DirtyTypeLayout)
+	d.documentChanged()
 }
 
 // SetElementClasses updates the class list of the given element and applies
@@ -850,6 +920,7 @@ func (d *Document) SetElementDisabled(elm *Element, disabled bool) {
 	if d.stylizer != nil {
 		d.stylizer.ApplyStyles(d.style, d)
 	}
+	d.invalidateScreenReader()
 }
 
 func setInlineStyleProperty(style, property, value string) string {
@@ -953,8 +1024,21 @@ func inlineStyleDeclarationName(declaration string) (string, bool) {
 // ApplyStyles will go through and apply styles to all elements within the
 // document. This is typically used after [SetElementClassesWithoutApply]. The
 // typical flow is to call [SetElementClassesWithoutApply] in a loop to change
-// styles of many elements at the same time, then apply styles after.
-func (d *Document) ApplyStyles() { d.stylizer.ApplyStyles(d.style, d) }
+// styles of many elements at the same time, then apply styles after. When the
+// style sheet uses :has(), changes made through the document are also applied
+// on the next frame if this isn't called, see
+// [Document.WatchRelationalSelectors].
+func (d *Document) ApplyStyles() {
+	d.stylizer.ApplyStyles(d.style, d)
+	d.invalidateScreenReader()
+}
+
+// documentChanged is called after the structure of the document, or the
+// classes or ids of an element, change
+func (d *Document) documentChanged() {
+	d.invalidateRelationalSelectors()
+	d.invalidateScreenReader()
+}
 
 // DuplicateElement will create a duplicate of a given element, nesting it under
 // the same parent as the given element (at the end). If you wish to just
@@ -1045,6 +1129,7 @@ func (d *Document) SetElementIdWithoutApplyStyles(elm *Element, id string) {
 	if id != "" {
 		d.setId(id, elm)
 	}
+	d.documentChanged()
 }
 
 func (d *Document) SetElementId(elm *Element, id string) {
@@ -1080,6 +1165,8 @@ func (d *Document) ChangeElementParentWithoutApply(child, parent *Element) {
 	parent.Children = append(parent.Children, child)
 	child.Parent = weak.Make(parent)
 	parent.UIPanel.AddChild(child.UI)
+	child.refreshEventBridges()
+	d.documentChanged()
 }
 
 func (d *Document) appendElement(elm *Element) {
@@ -1092,7 +1179,9 @@ func (d *Document) appendElement(elm *Element) {
 		}
 	}
 	addChildren(elm)
+	elm.refreshEventBridges()
 	d.reloadElementCaches()
+	d.documentChanged()
 }
 
 func (d *Document) isElementInDocument(elm *Element) bool {
@@ -1118,14 +1207,16 @@ func (d *Document) isElementInDocument(elm *Element) bool {
 // Preconditions:
 //   - Both elm and before must not be nil (enforced by debug.Ensure)
 func (d *Document) InsertElementBefore(elm *Element, before *Element) {
+	d.InsertElementBeforeWithoutApplyStyles(elm, before)
+	d.stylizer.ApplyStyles(d.style, d)
+}
+
+func (d *Document) InsertElementBeforeWithoutApplyStyles(elm *Element, before *Element) {
 	debug.Ensure(elm != nil)
 	debug.Ensure(before != nil)
-	parent := before.Parent.Value()
-	idx := -1
-	if parent != nil {
-		idx = parent.IndexOfChild(before)
-	}
-	d.insertElementAt(elm, before.Parent.Value(), idx)
+	d.insertElementAt(elm, before.Parent.Value(), func(parent *Element) int {
+		return parent.IndexOfChild(before)
+	})
 }
 
 // InsertElementAfter inserts the given element elm into the document after
@@ -1142,17 +1233,22 @@ func (d *Document) InsertElementBefore(elm *Element, before *Element) {
 // Preconditions:
 //   - Both elm and after must not be nil (enforced by debug.Ensure)
 func (d *Document) InsertElementAfter(elm *Element, after *Element) {
+	d.InsertElementAfterWithoutApplyStyles(elm, after)
+	d.stylizer.ApplyStyles(d.style, d)
+}
+
+func (d *Document) InsertElementAfterWithoutApplyStyles(elm *Element, after *Element) {
 	debug.Ensure(elm != nil)
 	debug.Ensure(after != nil)
-	parent := after.Parent.Value()
-	idx := -1
-	if parent != nil {
-		idx = parent.IndexOfChild(after) + 1
-	}
-	d.insertElementAt(elm, after.Parent.Value(), idx)
+	d.insertElementAt(elm, after.Parent.Value(), func(parent *Element) int {
+		return parent.IndexOfChild(after) + 1
+	})
 }
 
-func (d *Document) insertElementAt(elm *Element, parent *Element, index int) {
+// insertElementAt moves the element into the parent, the index is read once
+// the element is out of its previous parent as moving it within the same
+// parent shifts the position of the siblings after it
+func (d *Document) insertElementAt(elm *Element, parent *Element, indexOf func(parent *Element) int) {
 	fromParent := elm.Parent.Value()
 	if fromParent != nil {
 		idx := fromParent.IndexOfChild(elm)
@@ -1160,14 +1256,17 @@ func (d *Document) insertElementAt(elm *Element, parent *Element, index int) {
 		elm.Parent = weak.Make[Element](nil)
 	}
 	if parent != nil {
+		index := indexOf(parent)
 		parent.Children = slices.Insert(parent.Children, index, elm)
 		elm.Parent = weak.Make(parent)
 		parent.UI.ToPanel().InsertChild(elm.UI, index)
 	}
 	if !d.isElementInDocument(elm) {
 		d.appendElement(elm)
+	} else {
+		elm.refreshEventBridges()
+		d.documentChanged()
 	}
-	d.stylizer.ApplyStyles(d.style, d)
 }
 
 func (d *Document) setId(id string, elm *Element) {
@@ -0,0 +1,18 @@
+/******************************************************************************/
+/* html_plugin_types.go                                                       */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import (
+	"reflect"
+
+	"kaijuengine.com/plugins"
+)
+
+func init() {
+	plugins.RegisterEngineType(reflect.TypeFor[Element]())
+	plugins.RegisterEngineType(reflect.TypeFor[DOMEvent]())
+}
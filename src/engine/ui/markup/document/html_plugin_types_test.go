@@ -0,0 +1,96 @@
+/******************************************************************************/
+/* html_plugin_types_test.go                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+package document
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"kaijuengine.com/engine/assets"
+	"kaijuengine.com/plugins"
+	"kaijuengine.com/plugins/lua"
+)
+
+func testPluginVM(t *testing.T) *plugins.LuaVM {
+	t.Helper()
+	adb := assets.NewMockDB(map[string][]byte{
+		filepath.Join("plugins", "debugger.lua"): []byte(`function breakpoint() end`),
+		filepath.Join("plugins", "globals.lua"): []byte(`
+function create_obj(self)
+	local o = {}
+	for k, v in pairs(self) do o[k] = v end
+	setmetatable(o, { __index = self, __gc = self.__gc })
+	return o
+end`),
+	})
+	vm, err := plugins.NewScriptVM(adb, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(vm.Close)
+	return vm
+}
+
+func TestPluginElementEventListeners(t *testing.T) {
+	_, row, _ := testEventElements(t)
+	vm := testPluginVM(t)
+	vm.SetGlobalGoFunction("test_element", func(state *lua.State) int {
+		state.PushUserData(reflect.ValueOf(row))
+		return 1
+	})
+	pings, sameId := 0, false
+	vm.SetGlobalGoFunction("report", func(state *lua.State) int {
+		pings = int(state.ToNumber(1))
+		sameId = state.ToBoolean(2)
+		return 0
+	})
+	err := vm.DoStringNamed(`
+local row = Element.New(test_element())
+local pings = 0
+local id = row:AddEventListener("ping", function(evt)
+	if evt:Type() == "ping" then pings = pings + 1 end
+end, false)
+function remove_listener() row:RemoveEventListener(id) end
+function report_pings() report(pings, row:Attribute("id") == "row") end
+`, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	row.DispatchEvent(NewEvent("ping", false, false))
+	vm.InvokeGlobalFunction("remove_listener")
+	row.DispatchEvent(NewEvent("ping", false, false))
+	vm.InvokeGlobalFunction("report_pings")
+	if !sameId {
+		t.Error("expected lua to read the attributes of the element")
+	}
+	if pings != 1 {
+		t.Errorf("expected the lua listener to be called once before it was removed, got %d", pings)
+	}
+}
+
+func TestPluginElementCanvasContext(t *testing.T) {
+	_, row, _ := testEventElements(t)
+	vm := testPluginVM(t)
+	vm.SetGlobalGoFunction("test_element", func(state *lua.State) int {
+		state.PushUserData(reflect.ValueOf(row))
+		return 1
+	})
+	noContext := false
+	vm.SetGlobalGoFunction("report", func(state *lua.State) int {
+		noContext = state.ToBoolean(1)
+		return 0
+	})
+	err := vm.DoStringNamed(`
+report(Element.New(test_element()):CanvasContext() == nil)
+`, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !noContext {
+		t.Error("expected lua to get no canvas context for an element that isn't a canvas")
+	}
+}
@@ -0,0 +1,46 @@
+/******************************************************************************/
+/* html_relational_selectors.go                                               */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import "weak"
+
+type relationalSelectors struct {
+	watching bool
+	pending  bool
+}
+
+// WatchRelationalSelectors is called by the stylizer each time the styles are
+// applied to tell the document if any of the selectors use :has(). While they
+// do, adding, removing, moving or duplicating elements and changing their
+// classes or ids through the document marks the styles as dirty, and they are
+// applied again on the next frame unless [Document.ApplyStyles] is called
+// first. Attributes changed directly on an [Element] are not watched, call
+// [Document.ApplyStyles] after changing them.
+func (d *Document) WatchRelationalSelectors(watch bool) {
+	d.relational.watching = watch
+	d.relational.pending = false
+}
+
+// invalidateRelationalSelectors is called after the document structure, or
+// the classes or ids of an element, change. A change to any element can make
+// one of its ancestors or previous siblings start or stop matching :has()
+func (d *Document) invalidateRelationalSelectors() {
+	if !d.relational.watching || d.relational.pending {
+		return
+	}
+	host := d.host.Value()
+	if host == nil {
+		return
+	}
+	d.relational.pending = true
+	wd := weak.Make(d)
+	host.RunNextFrame(func() {
+		if doc := wd.Value(); doc != nil && doc.relational.pending {
+			doc.ApplyStyles()
+		}
+	})
+}
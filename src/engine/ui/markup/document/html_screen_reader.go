@@ -0,0 +1,340 @@
+/******************************************************************************/
+/* html_screen_reader.go                                                      */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import (
+	"strconv"
+	"strings"
+	"weak"
+
+	"kaijuengine.com/engine/systems/events"
+	"kaijuengine.com/engine/ui"
+)
+
+// screenReaderEvents are the events that can change what the screen reader
+// says, they are listened for in the capture phase so focus and blur, which
+// don't bubble, are heard as well
+var screenReaderEvents = []string{"focus", "blur", "change"}
+
+// Speaker is what the screen reader of a document speaks through. The
+// engine doesn't have text to speech of its own, a game gives the speaker
+// that uses the text to speech of the platform it is running on
+type Speaker interface {
+	// Speak reads the text out loud, interrupt stops what is being read
+	// rather than waiting for it to finish
+	Speak(text string, interrupt bool)
+}
+
+// SpeakerFunc lets a function be used as a [Speaker]
+type SpeakerFunc func(text string, interrupt bool)
+
+func (f SpeakerFunc) Speak(text string, interrupt bool) { f(text, interrupt) }
+
+type documentScreenReader struct {
+	speaker   Speaker
+	tree      *AccessibilityNode
+	focused   *Element
+	root      *Element
+	listeners []ListenerId
+	live      []liveTextWatch
+	pending   bool
+}
+
+// liveTextWatch is a render event of a label in a live region, labels are
+// rendered again when their text changes
+type liveTextWatch struct {
+	elm *Element
+	id  events.Id
+}
+
+var roleSpeech = map[AccessibilityRole]string{
+	RoleAlert:        "alert",
+	RoleButton:       "button",
+	RoleCheckbox:     "checkbox",
+	RoleColumnHeader: "column header",
+	RoleComboBox:     "combo box",
+	RoleDialog:       "dialog",
+	RoleHeading:      "heading",
+	RoleImage:        "image",
+	RoleLink:         "link",
+	RoleList:         "list",
+	RoleListItem:     "list item",
+	RoleMenu:         "menu",
+	RoleMenuItem:     "menu item",
+	RoleOption:       "option",
+	RoleProgressBar:  "progress bar",
+	RoleRadio:        "radio button",
+	RoleSlider:       "slider",
+	RoleSpinButton:   "spin button",
+	RoleSwitch:       "switch",
+	RoleTab:          "tab",
+	RoleTabList:      "tab list",
+	RoleTable:        "table",
+	RoleTextBox:      "edit text",
+}
+
+// Speech is what a screen reader says when the node gets the focus, such as
+// "Music, checkbox, checked"
+func (n *AccessibilityNode) Speech() string {
+	parts := make([]string, 0, 4)
+	if n.Name != "" {
+		parts = append(parts, n.Name)
+	}
+	if role, ok := roleSpeech[n.Role]; ok {
+		if n.Level > 0 {
+			role += " level " + strconv.Itoa(n.Level)
+		}
+		parts = append(parts, role)
+	}
+	if n.Value != "" {
+		parts = append(parts, n.Value)
+	}
+	parts = append(parts, n.stateSpeech(^AccessibilityState(0))...)
+	if n.Description != "" {
+		parts = append(parts, n.Description)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// stateSpeech is how the states in the mask are spoken, checkable nodes say
+// that they are not checked as well
+func (n *AccessibilityNode) stateSpeech(mask AccessibilityState) []string {
+	out := make([]string, 0, 2)
+	has := func(state AccessibilityState) bool { return mask&state != 0 && n.Has(state) }
+	switch {
+	case has(StateChecked):
+		out = append(out, "checked")
+	case has(StateMixed):
+		out = append(out, "partially checked")
+	case mask&(StateChecked|StateMixed) != 0 &&
+		(n.Role == RoleCheckbox || n.Role == RoleRadio || n.Role == RoleSwitch):
+		out = append(out, "not checked")
+	}
+	if has(StateExpanded) {
+		out = append(out, "expanded")
+	} else if has(StateCollapsed) {
+		out = append(out, "collapsed")
+	}
+	if has(StateSelected) {
+		out = append(out, "selected")
+	}
+	if has(StatePressed) {
+		out = append(out, "pressed")
+	}
+	if has(StateDisabled) {
+		out = append(out, "disabled")
+	}
+	if has(StateReadOnly) {
+		out = append(out, "read only")
+	}
+	if has(StateRequired) {
+		out = append(out, "required")
+	}
+	if has(StateInvalid) {
+		out = append(out, "invalid entry")
+	}
+	if has(StateBusy) {
+		out = append(out, "busy")
+	}
+	return out
+}
+
+// changeSpeech is what is said when the focused node changes without the
+// focus moving, like a checkbox being checked or a slider being moved
+func changeSpeech(prev, next *AccessibilityNode) string {
+	parts := make([]string, 0, 2)
+	if next.Name != prev.Name && next.Name != "" {
+		parts = append(parts, next.Name)
+	}
+	if next.Value != prev.Value {
+		parts = append(parts, next.Value)
+	}
+	// The focus states change along with the focus, which is spoken already
+	changed := (prev.States ^ next.States) &^ (StateFocusable | StateFocused)
+	parts = append(parts, next.stateSpeech(changed)...)
+	return strings.Join(parts, ", ")
+}
+
+// EnableScreenReader speaks the focused element whenever the focus moves,
+// the changes to the focused element and the changes to live regions
+// (aria-live, role="alert" and role="status") through the speaker. The
+// accessibility tree is rebuilt when the focus moves, when an element raises
+// a change event, when the document is changed or styled and when the text
+// in a live region changes. Call [Document.RefreshScreenReader] after other
+// changes, such as attributes set directly on an [Element], to have them read
+func (d *Document) EnableScreenReader(speaker Speaker) {
+	d.DisableScreenReader()
+	d.screenReader.speaker = speaker
+	if len(d.Elements) > 0 {
+		sr := &d.screenReader
+		sr.root = d.Elements[0].Root()
+		wd := weak.Make(d)
+		for _, name := range screenReaderEvents {
+			sr.listeners = append(sr.listeners, sr.root.AddEventListener(name, func(*DOMEvent) {
+				if doc := wd.Value(); doc != nil {
+					doc.invalidateScreenReader()
+				}
+			}, true))
+		}
+	}
+	d.invalidateScreenReader()
+}
+
+func (d *Document) DisableScreenReader() {
+	sr := &d.screenReader
+	for _, id := range sr.listeners {
+		sr.root.RemoveEventListener(id)
+	}
+	d.unwatchLiveText()
+	d.screenReader = documentScreenReader{}
+}
+
+// Announce speaks the text through the screen reader without it being on
+// any element, such as "Game saved". Assertive announcements interrupt what
+// is being spoken
+func (d *Document) Announce(text string, politeness Politeness) {
+	text = strings.TrimSpace(text)
+	if d.screenReader.speaker == nil || politeness == PolitenessOff || text == "" {
+		return
+	}
+	d.screenReader.speaker.Speak(text, politeness == PolitenessAssertive)
+}
+
+// RefreshScreenReader rebuilds the accessibility tree now and speaks what
+// changed since the last time it was built
+func (d *Document) RefreshScreenReader() {
+	sr := &d.screenReader
+	if sr.speaker == nil {
+		return
+	}
+	sr.pending = false
+	prev := sr.tree
+	sr.tree = d.AccessibilityTree()
+	focused := sr.tree.Focused()
+	var focusedElm *Element
+	if focused != nil {
+		focusedElm = focused.Element
+	}
+	if focusedElm != sr.focused {
+		sr.focused = focusedElm
+		if focused != nil {
+			sr.speaker.Speak(focused.Speech(), true)
+		}
+	} else if focused != nil && prev != nil {
+		if old := prev.Find(focusedElm); old != nil {
+			if change := changeSpeech(old, focused); change != "" {
+				sr.speaker.Speak(change, true)
+			}
+		}
+	}
+	if prev != nil {
+		d.speakLiveChanges(prev, sr.tree)
+	}
+	d.watchLiveText()
+}
+
+// invalidateScreenReader is called when something that the screen reader
+// reads may have changed, the tree is rebuilt once on the next frame no
+// matter how many changes are made. Without a host, such as before the
+// style is set up, the tree is rebuilt right away
+func (d *Document) invalidateScreenReader() {
+	sr := &d.screenReader
+	if sr.speaker == nil || sr.pending {
+		return
+	}
+	host := d.host.Value()
+	if host == nil {
+		d.RefreshScreenReader()
+		return
+	}
+	sr.pending = true
+	wd := weak.Make(d)
+	host.RunNextFrame(func() {
+		if doc := wd.Value(); doc != nil && doc.screenReader.pending {
+			doc.RefreshScreenReader()
+		}
+	})
+}
+
+// watchLiveText listens for the labels of the live regions in the tree to
+// be rendered, which is how their text changing is heard
+func (d *Document) watchLiveText() {
+	d.unwatchLiveText()
+	wd := weak.Make(d)
+	changed := func() {
+		if doc := wd.Value(); doc != nil {
+			doc.invalidateScreenReader()
+		}
+	}
+	var watch func(e *Element)
+	watch = func(e *Element) {
+		if e.IsText() && e.UI != nil && e.UI.IsType(ui.ElementTypeLabel) {
+			d.screenReader.live = append(d.screenReader.live,
+				liveTextWatch{e, e.UI.AddEvent(ui.EventTypeRender, changed)})
+		}
+		for _, c := range e.Children {
+			watch(c)
+		}
+	}
+	var find func(n *AccessibilityNode)
+	find = func(n *AccessibilityNode) {
+		if n.Live != PolitenessOff && n.Element != nil {
+			watch(n.Element)
+			return
+		}
+		for _, c := range n.Children {
+			find(c)
+		}
+	}
+	find(d.screenReader.tree)
+}
+
+func (d *Document) unwatchLiveText() {
+	for _, w := range d.screenReader.live {
+		if w.elm.UI != nil {
+			w.elm.UI.RemoveEvent(ui.EventTypeRender, w.id)
+		}
+	}
+	d.screenReader.live = d.screenReader.live[:0]
+}
+
+func (d *Document) speakLiveChanges(prev, node *AccessibilityNode) {
+	if node.Live == PolitenessOff {
+		for _, c := range node.Children {
+			d.speakLiveChanges(prev, c)
+		}
+		return
+	}
+	if node.liveText == "" {
+		return
+	}
+	// A live region only speaks when its text changes, except for alerts
+	// that are also spoken when they are shown
+	if old := prev.Find(node.Element); old != nil {
+		if old.liveText == node.liveText {
+			return
+		}
+	} else if node.Role != RoleAlert {
+		return
+	}
+	d.Announce(node.liveText, node.Live)
+}
+
+// accessibleFocus is the element with the navigation focus, or else the
+// text field that is being typed in
+func (d *Document) accessibleFocus() *Element {
+	if elm, ok := d.FocusedElement(); ok {
+		return elm
+	}
+	for _, elm := range d.Elements {
+		if elm.UI != nil && (elm.UI.IsType(ui.ElementTypeInput) ||
+			elm.UI.IsType(ui.ElementTypeTextArea)) && elm.isEditingText() {
+			return elm
+		}
+	}
+	return nil
+}
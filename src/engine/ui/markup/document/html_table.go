@@ -0,0 +1,111 @@
+/******************************************************************************/
+/* html_table.go                                                              */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package document
+
+import (
+	"strconv"
+	"strings"
+
+	"kaijuengine.com/engine/ui"
+)
+
+var tableTagRoles = map[string]ui.TableRole{
+	"table":    ui.TableRoleTable,
+	"caption":  ui.TableRoleCaption,
+	"thead":    ui.TableRoleHeaderGroup,
+	"tbody":    ui.TableRoleRowGroup,
+	"tfoot":    ui.TableRoleFooterGroup,
+	"tr":       ui.TableRoleRow,
+	"td":       ui.TableRoleCell,
+	"th":       ui.TableRoleCell,
+	"colgroup": ui.TableRoleColumnGroup,
+	"col":      ui.TableRoleColumn,
+}
+
+func (e *Element) isTableCell() bool {
+	tag := strings.ToLower(e.Data)
+	return !e.IsText() && (tag == "td" || tag == "th")
+}
+
+// spanAttribute reads a colspan, rowspan or span attribute, which is 1 when
+// it is missing or invalid
+func (e *Element) spanAttribute(key string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(e.Attribute(key)))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// setupTablePart gives the panel the table role of its tag along with the
+// spans of cells and columns
+func (e *Element) setupTablePart(panel *ui.Panel, tag string) {
+	role, ok := tableTagRoles[tag]
+	if !ok {
+		return
+	}
+	panel.SetTableTagRole(role)
+	switch role {
+	case ui.TableRoleCell:
+		panel.SetTableCellSpan(e.spanAttribute("colspan"), e.spanAttribute("rowspan"))
+	case ui.TableRoleColumn, ui.TableRoleColumnGroup:
+		panel.SetTableCellSpan(e.spanAttribute("span"), 1)
+	}
+}
+
+// TableColumn finds the columns that a td or th covers within its table,
+// first is 1 based and count is the number of columns in the table. Cells
+// of the rows above that span down into the row push the cell along
+func (e *Element) TableColumn() (first, span, count int, ok bool) {
+	if !e.isTableCell() {
+		return 0, 0, 0, false
+	}
+	table := e.Parent.Value()
+	for table != nil && strings.ToLower(table.Data) != "table" {
+		table = table.Parent.Value()
+	}
+	if table == nil {
+		return 0, 0, 0, false
+	}
+	rows := []*Element{}
+	for _, c := range table.Children {
+		switch strings.ToLower(c.Data) {
+		case "tr":
+			rows = append(rows, c)
+		case "thead", "tbody", "tfoot":
+			for _, r := range c.Children {
+				if strings.ToLower(r.Data) == "tr" {
+					rows = append(rows, r)
+				}
+			}
+		}
+	}
+	covered := map[[2]int]bool{}
+	for r, row := range rows {
+		col := 0
+		for _, cell := range row.Children {
+			if !cell.isTableCell() {
+				continue
+			}
+			for covered[[2]int{r, col}] {
+				col++
+			}
+			colSpan, rowSpan := cell.spanAttribute("colspan"), cell.spanAttribute("rowspan")
+			for y := r; y < r+rowSpan; y++ {
+				for x := col; x < col+colSpan; x++ {
+					covered[[2]int{y, x}] = true
+				}
+			}
+			if cell == e {
+				first, span, ok = col+1, colSpan, true
+			}
+			col += colSpan
+			count = max(count, col)
+		}
+	}
+	return first, span, count, ok
+}
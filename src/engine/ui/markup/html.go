@@ -8,6 +8,7 @@ package markup
 
 import (
 	"fmt"
+	"log/slog"
 	"path"
 	"regexp"
 	"strings"
@@ -21,8 +22,10 @@ import (
 )
 
 var (
-	htmlIncludeTagRE = regexp.MustCompile(`(?is)<kaiju-include\b([^>]*)>\s*</kaiju-include>|<kaiju-include\b([^>]*)/>`)
-	htmlIncludeSrcRE = regexp.MustCompile(`(?is)\bsrc\s*=\s*"([^"]+)"|\bsrc\s*=\s*'([^']+)'`)
+	htmlIncludeTagRE    = regexp.MustCompile(`(?is)<kaiju-include\b([^>]*)>\s*</kaiju-include>|<kaiju-include\b([^>]*)/>`)
+	htmlIncludeSrcRE    = regexp.MustCompile(`(?is)\bsrc\s*=\s*"([^"]+)"|\bsrc\s*=\s*'([^']+)'`)
+	htmlComponentLinkRE = regexp.MustCompile(`(?is)<link\b[^>]*\brel\s*=\s*["']?component["']?[^>]*>`)
+	htmlHrefRE          = regexp.MustCompile(`(?is)\bhref\s*=\s*"([^"]+)"|\bhref\s*=\s*'([^']+)'`)
 )
 
 func DocumentFromHTMLAsset(uiMan *ui.Manager, htmlPath string, withData any, funcMap map[string]func(*document.Element)) (*document.Document, error) {
@@ -58,7 +61,8 @@ func DocumentFromHTMLAssetRooted(uiMan *ui.Manager, htmlPath string, withData an
 // explicit cssStr into the style cascade. DocumentFromHTMLAsset hardcodes an
 // empty cssStr, which prevents callers from theming asset-sourced templates;
 // this variant closes that gap. The cssStr is parsed after css.DefaultCSS/OverrideCSS
-// and before any document <style>/<link>, so later rules win (there is no specificity weighting).
+// and before any document <style>/<link>, so for selectors of equal specificity the
+// document rules win.
 func DocumentFromHTMLAssetWithCSS(uiMan *ui.Manager, htmlPath, cssStr string, withData any, funcMap map[string]func(*document.Element), root *document.Element) (*document.Document, error) {
 	host := uiMan.Host
 	m, err := host.AssetDatabase().ReadText(htmlPath)
@@ -135,9 +139,43 @@ func filepathToSlash(p string) string {
 	return strings.ReplaceAll(p, "\\", "/")
 }
 
+// LoadComponent reads the component at the path and registers it so it can
+// be used by any document, documents may also load the components they use
+// with <link rel="component" href="path">
+func LoadComponent(db assets.Database, componentPath string) (*document.Component, error) {
+	src, err := db.ReadText(componentPath)
+	if err != nil {
+		return nil, err
+	}
+	c, err := document.ParseComponent(src)
+	if err != nil {
+		return nil, err
+	}
+	document.RegisterComponent(c)
+	return c, nil
+}
+
+func loadHTMLComponents(db assets.Database, html string) {
+	for _, link := range htmlComponentLinkRE.FindAllString(html, -1) {
+		matches := htmlHrefRE.FindStringSubmatch(link)
+		if len(matches) == 0 {
+			slog.Error("component link is missing a href attribute", "link", link)
+			continue
+		}
+		componentPath := matches[1]
+		if componentPath == "" {
+			componentPath = matches[2]
+		}
+		if _, err := LoadComponent(db, componentPath); err != nil {
+			slog.Error("failed to load the component", "path", componentPath, "error", err)
+		}
+	}
+}
+
 func DocumentFromHTMLString(uiMan *ui.Manager, html, cssStr string, withData any, funcMap map[string]func(*document.Element), root *document.Element) *document.Document {
 	host := uiMan.Host
 	window := host.Window
+	loadHTMLComponents(host.AssetDatabase(), html)
 	doc := document.DocumentFromHTMLString(uiMan, html, withData, funcMap)
 	if root != nil {
 		// Root the HTML body under the provided root element so layout behaves
@@ -152,11 +190,16 @@ func DocumentFromHTMLString(uiMan *ui.Manager, html, cssStr string, withData any
 		}
 	}
 	s := rules.NewStyleSheet()
+	s.ImportResolver = host.AssetDatabase().ReadText
 	s.Parse(css.DefaultCSS, window)
+	s.MarkUserAgent()
 	if css.OverrideCSS != "" {
 		s.Parse(css.OverrideCSS, window)
 	}
 	s.Parse(cssStr, window)
+	for _, c := range doc.Components() {
+		s.ParseScoped(c.Style, c.Name, window)
+	}
 	for i := range doc.HeadElements {
 		if doc.HeadElements[i].Data == "style" {
 			if len(doc.HeadElements[i].Children) > 0 {
@@ -126,6 +126,7 @@ var genProps = []propertyData{
 	{"@charset", "Specifies the character encoding used in the style sheet"},
 	{"clear", "Specifies what should happen with the element that is next to a floating element"},
 	{"clip", "Clips an absolutely positioned element"},
+	{"clip-path", "Clips an element to a basic shape, the parts outside of the shape are hidden"},
 	{"color", "Sets the color of text"},
 	{"column-count", "Specifies the number of columns an element should be divided into"},
 	{"column-fill", "Specifies how to fill columns, balanced or not"},
@@ -137,6 +138,9 @@ var genProps = []propertyData{
 	{"column-span", "Specifies how many columns an element should span across"},
 	{"column-width", "Specifies the column width"},
 	{"columns", "A shorthand property for column-width and column-count"},
+	{"container", "A shorthand property for the container-name and container-type properties"},
+	{"container-name", "Specifies the names an element can be queried by in @container rules"},
+	{"container-type", "Specifies whether an element is a size container for @container rules"},
 	{"content", "Used with the :before and :after pseudo-elements, to insert generated content"},
 	{"counter-increment", "Increases or decreases the value of one or more CSS counters"},
 	{"counter-reset", "Creates or resets one or more CSS counters"},
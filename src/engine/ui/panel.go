@@ -8,6 +8,7 @@ package ui
 
 import (
 	"log/slog"
+	"slices"
 	"sort"
 
 	"kaijuengine.com/engine/assets"
@@ -120,23 +121,30 @@ type requestScroll struct {
 }
 
 type panelData struct {
-	scrollBarX, scrollBarY    *Panel
-	scrollBarsHidden          bool
-	scrollBarStart            float32
-	scrollBarDrag             matrix.Vec2
-	scroll, offset, maxScroll matrix.Vec2
-	scrollDirection           PanelScrollDirection
-	scrollEvent               events.Id
-	borderStyle               [4]BorderStyle
-	drawing                   rendering.Drawing
-	transparentDrawing        rendering.Drawing
-	fitContent                ContentFit
-	gridColumns               int
-	gridGap                   matrix.Vec2
+	scrollBarX, scrollBarY *Panel
+	scrollBarsHidden       bool
+	scrollBarStart         float32
+	scrollBarDrag          matrix.Vec2
+	scroll, maxScroll      matrix.Vec2
+	scrollDirection        PanelScrollDirection
+	scrollEvent            events.Id
+	borderStyle            [4]BorderStyle
+	drawing                rendering.Drawing
+	transparentDrawing     rendering.Drawing
+	fitContent             ContentFit
+	gridColumns            int
+	gridGap                matrix.Vec2
 	// Positive values are fixed pixel widths, negative values are fr units.
 	gridTemplateColumns []float32
+	gridTemplateRows    []float32
+	gridColumnNames     [][]string
+	gridRowNames        [][]string
+	gridTemplateAreas   [][]string
 	gridAutoColumns     float32
 	gridAutoRows        float32
+	gridAutoFlow        GridAutoFlow
+	gridAutoFlowDense   bool
+	gridJustifyItems    FlexAlign
 	requestScrollX      requestScroll
 	requestScrollY      requestScroll
 	overflow            Overflow
@@ -146,12 +154,24 @@ type panelData struct {
 	flexJustify         FlexJustify
 	flexAlignItems      FlexAlign
 	flexAlignContent    FlexAlignContent
+	direction           Direction
+	writingMode         WritingMode
+	textOrientation     TextOrientation
 	enforcedColorStack  []matrix.Color
 	flags               panelBits
 	minSize             matrix.Vec2
 	maxSize             matrix.Vec2
 	aspectRatio         float32
 	usesBorderBox       bool
+	boxShadows          []BoxShadow
+	shadowShaderData    []*ShadowShaderData
+	filters             []Filter
+	unfiltered          *panelColors
+	scrollStyle         *panelScroll
+	table               *panelTable
+	transform           *panelTransform
+	clip                *panelClip
+	borderImage         *panelBorderImage
 }
 
 func (b panelBits) isScrolling() bool        { return b&panelBitsIsScrolling != 0 }
@@ -202,14 +222,24 @@ func (panel *Panel) Init(texture *rendering.Texture, elmType ElementType) {
 	pd.gridColumns = 0
 	pd.gridGap = matrix.Vec2Zero()
 	pd.gridTemplateColumns = nil
+	pd.gridTemplateRows = nil
+	pd.gridColumnNames = nil
+	pd.gridRowNames = nil
+	pd.gridTemplateAreas = nil
 	pd.gridAutoColumns = 0
 	pd.gridAutoRows = 0
+	pd.gridAutoFlow = GridAutoFlowRow
+	pd.gridAutoFlowDense = false
+	pd.gridJustifyItems = FlexAlignStretch
 	pd.layoutMode = LayoutModeFlow
 	pd.flexDirection = FlexDirectionRow
 	pd.flexWrap = FlexWrapNoWrap
 	pd.flexJustify = FlexJustifyStart
 	pd.flexAlignItems = FlexAlignStretch
 	pd.flexAlignContent = FlexAlignContentStretch
+	pd.direction = DirectionInherit
+	pd.writingMode = WritingModeInherit
+	pd.textOrientation = TextOrientationInherit
 	pd.enforcedColorStack = make([]matrix.Color, 0)
 	panel.postLayoutUpdate = panel.panelPostLayoutUpdate
 	panel.render = panel.panelRender
@@ -226,9 +256,21 @@ func (panel *Panel) Init(texture *rendering.Texture, elmType ElementType) {
 	}
 	panel.entity.OnActivate.Add(func() {
 		panel.shaderData.Activate()
+		panel.activateBoxShadows()
+		panel.activateBorderImage()
 		base.SetDirty(DirtyTypeLayout)
 	})
-	panel.entity.OnDeactivate.Add(func() { panel.shaderData.Deactivate() })
+	panel.entity.OnDeactivate.Add(func() {
+		panel.shaderData.Deactivate()
+		panel.deactivateBoxShadows()
+		panel.deactivateBorderImage()
+	})
+	base.AddEvent(EventTypeDestroy, func() {
+		if panel.elmData != nil {
+			panel.clearBoxShadows()
+			panel.clearBorderImage()
+		}
+	})
 }
 
 func (p *Panel) MaxScroll() matrix.Vec2   { return p.PanelData().maxScroll }
@@ -341,38 +383,19 @@ func (p *Panel) FitContent() {
 func (p *Panel) onScroll() {
 	defer tracing.NewRegion("Panel.onScroll").End()
 	pd := p.PanelData()
-	host := p.man.Value().Host
-	mouse := &host.Window.Mouse
-	delta := mouse.Scroll()
-	scroll := pd.scroll
-	base := p.Base()
+	mouse := &p.man.Value().Host.Window.Mouse
 	if !mouse.Scrolled() {
-		pos := base.cursorPos(&host.Window.Cursor)
-		delta = pos.Subtract(p.downPos)
-		delta[matrix.Vy] *= -1.0
-	} else {
-		pd.offset = pd.scroll
-		delta.ScaleAssign(UIScrollSpeed)
+		return
 	}
+	delta := mouse.Scroll()
+	delta.ScaleAssign(UIScrollSpeed)
 	// If the panel can only scroll horizontally, use the Y scroll if there is no X
 	if pd.scrollDirection == PanelScrollDirectionHorizontal {
 		if matrix.ApproxTo(delta.X(), 0, matrix.Tiny) {
 			delta.SetX(-delta.Y())
 		}
 	}
-	if (pd.scrollDirection & PanelScrollDirectionHorizontal) != 0 {
-		x := matrix.Clamp(delta.X()+pd.offset.X(), 0.0, pd.maxScroll.X())
-		scroll.SetX(x)
-	}
-	if (pd.scrollDirection & PanelScrollDirectionVertical) != 0 {
-		y := matrix.Clamp(delta.Y()+pd.offset.Y(), -pd.maxScroll.Y(), 0)
-		scroll.SetY(y)
-	}
-	if !matrix.Vec2Approx(scroll, pd.scroll) {
-		pd.scroll = scroll
-		base.SetDirty(DirtyTypeLayout)
-		pd.flags.setIsScrolling()
-	}
+	p.wheelScroll(matrix.Vec2{delta.X(), -delta.Y()})
 }
 
 func (p *Panel) update(deltaTime float64) {
@@ -390,15 +413,10 @@ func (p *Panel) update(deltaTime float64) {
 	}
 	p.updateScrollBars()
 	if !pd.flags.isFrozen() {
-		if p.flags.isDown() && pd.flags.isDragging() {
-			if pd.flags.allowClickThrough() {
-				p.onScroll()
-			}
-		} else if pd.flags.isDragging() {
-			pd.flags.resetDragging()
-		} else {
+		if !pd.flags.isDragging() {
 			pd.flags.resetIsScrolling()
 		}
+		p.updateScroll(deltaTime)
 	}
 	if pd.flags.wasDirtied() {
 		// Update shader visibility based on scissor clipping
@@ -434,30 +452,23 @@ func (rb rowBuilder) Width() float32 {
 	return rb.x
 }
 
-type gridLayoutItem struct {
-	ui      *UI
-	row     int
-	col     int
-	rowSpan int
-	colSpan int
-}
-
 func (rb rowBuilder) Height() float32 {
 	return rb.height + rb.maxMarginTop + rb.maxMarginBottom
 }
 
-func (rb rowBuilder) setElements(offsetX, offsetY float32) {
+func (rb rowBuilder) setElements(offsetX, offsetY float32, mirror inlineMirror) {
 	defer tracing.NewRegion("Panel.Init").End()
 	for _, e := range rb.elements {
 		layout := e.Layout()
 		x, y := offsetX, offsetY
+		// Relative offsets stay on their physical side when mirrored
+		x = mirror.x(layout, x+layout.margin.X())
 		switch e.Layout().Positioning() {
 		case PositioningAbsolute:
 			fallthrough
 		case PositioningRelative:
 			x += layout.InnerOffset().Left()
 		}
-		x += layout.margin.X()
 		y += rb.maxMarginTop
 		layout.SetRowLayoutOffset(matrix.Vec2{x, y})
 		offsetX += layout.PixelSize().Width() + layout.margin.X() + layout.margin.Z()
@@ -472,6 +483,10 @@ func (p *Panel) updateShaderVisibility() {
 	if !p.entity.IsActive() {
 		return
 	}
+	if t := p.PanelData().table; t != nil && t.emptyHidden {
+		p.shaderData.Deactivate()
+		return
+	}
 	// Retrieve the current scissor rectangle from the root UI.
 	scissor := p.Base().selfScissor()
 	// Compute the panel's world‑space bounds.
@@ -537,7 +552,8 @@ func (p *Panel) panelPostLayoutUpdate() {
 		pd.scroll.SetY(y)
 		pd.requestScrollY.requested = false
 	}
-	offsetStart := matrix.Vec2{-pd.scroll.X(), pd.scroll.Y()}
+	overshoot := p.scrollOvershoot()
+	offsetStart := matrix.Vec2{-(pd.scroll.X() + overshoot.X()), pd.scroll.Y() - overshoot.Y()}
 	ps := p.layout.PixelSize()
 	maxSize := matrix.Vec2{}
 	maxRowsX := matrix.Float(0)
@@ -547,6 +563,11 @@ func (p *Panel) panelPostLayoutUpdate() {
 	} else if p.IsFlex() {
 		maxSize = p.layoutFlexChildren(pd, offsetStart, ps)
 		maxRowsX = maxSize.X()
+	} else if p.IsTable() {
+		maxSize = p.layoutTableChildren(pd, offsetStart, ps)
+		maxRowsX = maxSize.X()
+	} else if pd.table.laidOutByTable() {
+		// The table that holds this row (or group) places the children
 	} else {
 		rows := make([]rowBuilder, 0)
 		areaWidth := ps.X()
@@ -579,8 +600,13 @@ func (p *Panel) panelPostLayoutUpdate() {
 		nextPos[matrix.Vy] += addY
 		maxSize[matrix.Vy] += addY
 		maxRowsX = matrix.Float(0)
+		mirror := p.inlineMirror(offsetStart, ps)
+		if t := pd.table; t != nil && t.placed && t.role == TableRoleCell {
+			// The content of a cell sits at its vertical-align within the row
+			nextPos[matrix.Vy] += max(0, ps.Y()-t.contentHeight) * t.verticalAlign
+		}
 		for i := range rows {
-			rows[i].setElements(nextPos[matrix.Vx], nextPos[matrix.Vy])
+			rows[i].setElements(nextPos[matrix.Vx], nextPos[matrix.Vy], mirror)
 			addY = rows[i].height + rows[i].maxMarginTop + rows[i].maxMarginBottom
 			maxRowsX = max(maxRowsX, rows[i].x)
 			nextPos[matrix.Vy] += addY
@@ -604,6 +630,9 @@ func (p *Panel) panelPostLayoutUpdate() {
 		if pd.HasMaxHeight() && h > pd.maxSize.Y() {
 			h = pd.maxSize.Y()
 		}
+		if pd.table != nil {
+			pd.table.contentHeight = h
+		}
 		// Clamp fit-content to the parent's content box. A fit-content element
 		// must not grow past its container (the container scrolls, or the window
 		// resizes) — this is also what CSS fit-content means: min(max-content,
@@ -644,6 +673,9 @@ func (p *Panel) panelPostLayoutUpdate() {
 				}
 			}
 		}
+		if pd.table != nil && pd.table.placed {
+			w, h = pd.table.fitPlacedSize(w, h)
+		}
 		switch pd.fitContent {
 		case ContentFitWidth:
 			p.layout.ScaleWidth(max(1, w))
@@ -688,6 +720,9 @@ func (p *Panel) panelRender() {
 	if pd.drawing.IsValid() {
 		p.shaderData.setSize2d(p.Base())
 	}
+	p.refreshFilteredColors()
+	p.updateBoxShadows()
+	p.updateBorderImage()
 	pd.requestScrollX.requested = false
 	pd.requestScrollY.requested = false
 }
@@ -704,6 +739,11 @@ func (p *Panel) AddChild(target *UI) {
 }
 
 func (p *Panel) InsertChild(target *UI, idx int) {
+	if i := slices.Index(p.entity.Children, &target.entity); i >= 0 {
+		// Already a child, it's moved to the end so it can be moved to idx
+		p.entity.Children = append(slices.Delete(p.entity.Children, i, i+1), &target.entity)
+		p.Base().SetDirty(DirtyTypeGenerated)
+	}
 	p.AddChild(target)
 	kidLen := len(p.entity.Children)
 	idx = max(idx, 0)
@@ -735,7 +775,7 @@ func (p *Panel) recreateDrawing() {
 
 func (p *Panel) EnforceColor(color matrix.Color) {
 	pd := p.PanelData()
-	pd.enforcedColorStack = append(pd.enforcedColorStack, p.shaderData.FgColor)
+	pd.enforcedColorStack = append(pd.enforcedColorStack, p.Color())
 	p.setColorInternal(color)
 }
 
@@ -749,7 +789,12 @@ func (p *Panel) UnEnforceColor() {
 	pd.enforcedColorStack = pd.enforcedColorStack[:last]
 }
 
-func (p *Panel) Color() matrix.Color { return p.shaderData.FgColor }
+func (p *Panel) Color() matrix.Color {
+	if fc := p.PanelData().unfiltered; fc != nil {
+		return fc.fill
+	}
+	return p.shaderData.FgColor
+}
 
 // CalculatedBGColor returns this panel's opaque "used" background color: its own
 // fill (which may be partially or fully transparent) alpha-composited over its
@@ -983,6 +1028,9 @@ func (p *Panel) SetScrollDirection(direction PanelScrollDirection) {
 		pd.scrollBarY = p.createScrollBar()
 		p.AddChild((*UI)(pd.scrollBarY))
 	}
+	if pd.scrollStyle != nil && pd.scrollStyle.customColors {
+		p.applyScrollbarColors()
+	}
 }
 
 func (p *Panel) SetFlowLayout() {
@@ -1000,8 +1048,15 @@ func (p *Panel) ClearLayoutStyles() {
 	pd.gridColumns = 0
 	pd.gridGap = matrix.Vec2Zero()
 	pd.gridTemplateColumns = nil
+	pd.gridTemplateRows = nil
+	pd.gridColumnNames = nil
+	pd.gridRowNames = nil
+	pd.gridTemplateAreas = nil
 	pd.gridAutoColumns = 0
 	pd.gridAutoRows = 0
+	pd.gridAutoFlow = GridAutoFlowRow
+	pd.gridAutoFlowDense = false
+	pd.gridJustifyItems = FlexAlignStretch
 	pd.flexDirection = FlexDirectionRow
 	pd.flexWrap = FlexWrapNoWrap
 	pd.flexJustify = FlexJustifyStart
@@ -1012,6 +1067,12 @@ func (p *Panel) ClearLayoutStyles() {
 	pd.aspectRatio = 0
 	pd.usesBorderBox = false
 	pd.fitContent = ContentFitBoth
+	p.clearScrollStyles()
+	p.ResetScrollbarColor()
+	p.clearTableStyles()
+	p.clearTransformStyles()
+	p.clearClipStyles()
+	p.clearBorderImageStyles()
 	p.layout.ClearStyles()
 	p.Base().SetDirty(DirtyTypeLayout)
 }
@@ -1042,7 +1103,7 @@ func (p *Panel) GridCellWidth() float32 {
 	}
 	colW := (innerW - float32(pd.gridColumns-1)*gapX) / float32(pd.gridColumns)
 	if len(pd.gridTemplateColumns) == pd.gridColumns {
-		widths := p.computeGridColumnWidths(innerW, gapX)
+		widths := p.computeGridColumnWidths(innerW, gapX, 0, nil)
 		if len(widths) > 0 {
 			sum := float32(0)
 			for i := range widths {
@@ -1082,7 +1143,8 @@ func (p *Panel) SetGrid(columns int) {
 }
 
 // SetGridTemplateColumns configures explicit grid column widths.
-// Positive values are fixed pixels, negative values are fr units.
+// Positive values are fixed pixels, negative values are fr units and zero
+// sizes the column to its content.
 func (p *Panel) SetGridTemplateColumns(columns []float32) {
 	pd := p.PanelData()
 	if len(columns) == 0 {
@@ -1123,6 +1185,67 @@ func (p *Panel) SetGridAutoRows(height float32) {
 	p.Base().SetDirty(DirtyTypeLayout)
 }
 
+// SetGridTemplateRows configures explicit grid row heights using the same
+// units as SetGridTemplateColumns. Fr rows only share the remaining height
+// when the panel does not fit its content height.
+func (p *Panel) SetGridTemplateRows(rows []float32) {
+	pd := p.PanelData()
+	if slices.Equal(pd.gridTemplateRows, rows) {
+		return
+	}
+	pd.gridTemplateRows = slices.Clone(rows)
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetGridColumnNames names the column lines, index 0 is the first line
+func (p *Panel) SetGridColumnNames(names [][]string) {
+	p.PanelData().gridColumnNames = names
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetGridRowNames names the row lines, index 0 is the first line
+func (p *Panel) SetGridRowNames(names [][]string) {
+	p.PanelData().gridRowNames = names
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetGridTemplateAreas names the cells of the grid, one slice of cell names
+// per row. Cells named "." are left unnamed. Each named area implicitly names
+// the lines around it "name-start" and "name-end".
+func (p *Panel) SetGridTemplateAreas(areas [][]string) {
+	p.PanelData().gridTemplateAreas = areas
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+func (p *Panel) GridAutoFlow() (flow GridAutoFlow, dense bool) {
+	pd := p.PanelData()
+	return pd.gridAutoFlow, pd.gridAutoFlowDense
+}
+
+func (p *Panel) SetGridAutoFlow(flow GridAutoFlow, dense bool) {
+	pd := p.PanelData()
+	if pd.gridAutoFlow == flow && pd.gridAutoFlowDense == dense {
+		return
+	}
+	pd.gridAutoFlow = flow
+	pd.gridAutoFlowDense = dense
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+func (p *Panel) GridJustifyItems() FlexAlign { return p.PanelData().gridJustifyItems }
+
+func (p *Panel) SetGridJustifyItems(align FlexAlign) {
+	if align == FlexAlignAuto {
+		align = FlexAlignStretch
+	}
+	pd := p.PanelData()
+	if pd.gridJustifyItems == align {
+		return
+	}
+	pd.gridJustifyItems = align
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
 func (p *Panel) SetGridGap(x, y float32) {
 	pd := p.PanelData()
 	if matrix.Approx(pd.gridGap.X(), x) && matrix.Approx(pd.gridGap.Y(), y) {
@@ -1487,6 +1610,7 @@ func (p *Panel) layoutFlexChildren(pd *panelData, offsetStart matrix.Vec2, ps ma
 	startX := offsetStart.X() + innerLeft
 	startY := offsetStart.Y() + innerTop
 	innerWidth := ps.X() - p.layout.padding.Horizontal() - p.layout.border.Horizontal()
+	mirror := p.inlineMirror(offsetStart, ps)
 	innerHeight := ps.Y() - p.layout.padding.Vertical() - p.layout.border.Vertical()
 	if innerWidth < 1 {
 		innerWidth = 1
@@ -1572,12 +1696,12 @@ func (p *Panel) layoutFlexChildren(pd *panelData, offsetStart matrix.Vec2, ps ma
 			if row {
 				x := startX + mainPos + item.margin.Left()
 				y := startY + crossPos + crossOffset
-				item.ui.Layout().SetRowLayoutOffset(matrix.NewVec2(x, y))
+				item.ui.Layout().SetRowLayoutOffset(matrix.NewVec2(mirror.x(item.ui.Layout(), x), y))
 				mainPos += item.finalMain + item.margin.Horizontal() + gapMain + extraMainGap
 			} else {
 				x := startX + crossPos + crossOffset
 				y := startY + mainPos + item.margin.Top()
-				item.ui.Layout().SetRowLayoutOffset(matrix.NewVec2(x, y))
+				item.ui.Layout().SetRowLayoutOffset(matrix.NewVec2(mirror.x(item.ui.Layout(), x), y))
 				mainPos += item.finalMain + item.margin.Vertical() + gapMain + extraMainGap
 			}
 		}
@@ -1593,12 +1717,9 @@ func (p *Panel) layoutFlexChildren(pd *panelData, offsetStart matrix.Vec2, ps ma
 		maxMainUsed+innerTop+p.layout.padding.Bottom()+p.layout.border.Bottom())
 }
 
-func (p *Panel) computeGridColumnWidths(innerWidth, gapX float32, columns ...int) []float32 {
+func (p *Panel) computeGridColumnWidths(innerWidth, gapX float32, columns int, content []float32) []float32 {
 	pd := p.PanelData()
-	cols := pd.gridColumns
-	if len(columns) > 0 && columns[0] > cols {
-		cols = columns[0]
-	}
+	cols := max(pd.gridColumns, columns)
 	if cols <= 0 {
 		return []float32{}
 	}
@@ -1621,10 +1742,17 @@ func (p *Panel) computeGridColumnWidths(innerWidth, gapX float32, columns ...int
 		}
 		return out
 	}
+	template := slices.Clone(pd.gridTemplateColumns)
+	for i := range template {
+		// Auto columns are sized to the widest item within them
+		if template[i] == 0 && i < len(content) {
+			template[i] = content[i]
+		}
+	}
 	totalFixed := float32(0)
 	totalFr := float32(0)
 	for i := 0; i < explicitCols; i++ {
-		v := pd.gridTemplateColumns[i]
+		v := template[i]
 		if v >= 0 {
 			totalFixed += v
 		} else {
@@ -1647,7 +1775,7 @@ func (p *Panel) computeGridColumnWidths(innerWidth, gapX float32, columns ...int
 			}
 			continue
 		}
-		v := pd.gridTemplateColumns[i]
+		v := template[i]
 		if v >= 0 {
 			out[i] = v
 		} else if totalFr > 0 {
@@ -1660,90 +1788,18 @@ func (p *Panel) computeGridColumnWidths(innerWidth, gapX float32, columns ...int
 	return out
 }
 
-func gridTrackSpan(start, end int) int {
-	if start > 0 && end > start {
-		return end - start
-	}
-	return 1
-}
-
-func isGridAreaFree(occupied map[int]map[int]bool, columns, row, col, rowSpan, colSpan int) bool {
-	if col < 0 || col+colSpan > columns {
-		return false
-	}
-	for y := 0; y < rowSpan; y++ {
-		for x := 0; x < colSpan; x++ {
-			if occupied[row+y][col+x] {
-				return false
-			}
-		}
-	}
-	return true
-}
-
-func occupyGridArea(occupied map[int]map[int]bool, row, col, rowSpan, colSpan int) {
-	for y := 0; y < rowSpan; y++ {
-		r := row + y
-		if _, ok := occupied[r]; !ok {
-			occupied[r] = map[int]bool{}
-		}
-		for x := 0; x < colSpan; x++ {
-			occupied[r][col+x] = true
-		}
-	}
-}
-
-func nextGridCell(occupied map[int]map[int]bool, columns, row, col, rowSpan, colSpan int) (int, int) {
-	for {
-		if col >= columns || col+colSpan > columns {
-			row++
-			col = 0
-		}
-		if _, ok := occupied[row]; !ok {
-			occupied[row] = map[int]bool{}
-		}
-		if isGridAreaFree(occupied, columns, row, col, rowSpan, colSpan) {
-			return row, col
-		}
-		col++
-	}
-}
-
-func nextGridCellInColumn(occupied map[int]map[int]bool, columns, row, col, rowSpan, colSpan int) (int, int) {
-	if col+colSpan > columns {
-		return nextGridCell(occupied, columns, row, 0, rowSpan, colSpan)
-	}
-	for {
-		if _, ok := occupied[row]; !ok {
-			occupied[row] = map[int]bool{}
-		}
-		if isGridAreaFree(occupied, columns, row, col, rowSpan, colSpan) {
-			return row, col
-		}
-		row++
-	}
-}
-
-func firstGridCellInRow(occupied map[int]map[int]bool, columns, row, rowSpan, colSpan int) (int, int) {
-	for col := 0; col < columns; col++ {
-		if isGridAreaFree(occupied, columns, row, col, rowSpan, colSpan) {
-			return row, col
-		}
-	}
-	return nextGridCell(occupied, columns, row+1, 0, rowSpan, colSpan)
-}
-
-func requestedGridCell(occupied map[int]map[int]bool, columns, cursorRow, cursorCol, rowStart, colStart, rowSpan, colSpan int) (int, int) {
-	if rowStart > 0 && colStart > 0 {
-		return nextGridCellInColumn(occupied, columns, rowStart-1, colStart-1, rowSpan, colSpan)
-	}
-	if rowStart > 0 {
-		return firstGridCellInRow(occupied, columns, rowStart-1, rowSpan, colSpan)
-	}
-	if colStart > 0 {
-		return nextGridCellInColumn(occupied, columns, cursorRow, colStart-1, rowSpan, colSpan)
+func (p *Panel) gridTemplate() gridTemplate {
+	pd := p.PanelData()
+	columns := pd.gridColumns
+	if len(pd.gridTemplateColumns) == 0 && len(pd.gridTemplateAreas) > 0 {
+		// The areas decide the column count when no columns are given
+		columns = 0
 	}
-	return nextGridCell(occupied, columns, cursorRow, cursorCol, rowSpan, colSpan)
+	t := newGridTemplate(len(pd.gridTemplateRows), columns,
+		pd.gridRowNames, pd.gridColumnNames, pd.gridTemplateAreas)
+	t.flow = pd.gridAutoFlow
+	t.dense = pd.gridAutoFlowDense
+	return t
 }
 
 func (p *Panel) layoutGridChildren(pd *panelData, offsetStart matrix.Vec2, ps matrix.Vec2) matrix.Vec2 {
@@ -1753,9 +1809,14 @@ func (p *Panel) layoutGridChildren(pd *panelData, offsetStart matrix.Vec2, ps ma
 	startX := offsetStart.X() + innerLeft
 	startY := offsetStart.Y() + innerTop
 	innerWidth := ps.X() - p.layout.padding.Horizontal() - p.layout.border.Horizontal()
+	mirror := p.inlineMirror(offsetStart, ps)
 	if innerWidth < 1 {
 		innerWidth = 100
 	}
+	innerHeight := float32(-1)
+	if !p.FittingContentHeight() {
+		innerHeight = max(ps.Y()-p.layout.padding.Vertical()-p.layout.border.Vertical(), 0)
+	}
 	gapX := pd.gridGap.X()
 	if gapX < 0 {
 		gapX = 0
@@ -1764,31 +1825,7 @@ func (p *Panel) layoutGridChildren(pd *panelData, offsetStart matrix.Vec2, ps ma
 	if gapY < 0 {
 		gapY = 0
 	}
-	effectiveColumns := pd.gridColumns
-	for _, kid := range p.entity.Children {
-		if !kid.IsActive() || kid.IsDestroyed() {
-			continue
-		}
-		kui := FirstOnEntity(kid)
-		if kui == nil {
-			continue
-		}
-		kLayout := kui.Layout()
-		switch kLayout.Positioning() {
-		case PositioningAbsolute, PositioningFixed, PositioningSticky:
-			continue
-		}
-		if start := kLayout.GridColumnStart(); start > 0 {
-			span := gridTrackSpan(start, kLayout.GridColumnEnd())
-			effectiveColumns = max(effectiveColumns, start+span-1)
-		}
-	}
-	colWidths := p.computeGridColumnWidths(innerWidth, gapX, effectiveColumns)
-	items := make([]gridLayoutItem, 0, len(p.entity.Children))
-	occupied := map[int]map[int]bool{}
-	cursorRow := 0
-	cursorCol := 0
-	rowCount := 0
+	kids := make([]*UI, 0, len(p.entity.Children))
 	for _, kid := range p.entity.Children {
 		if !kid.IsActive() || kid.IsDestroyed() {
 			continue
@@ -1798,65 +1835,65 @@ func (p *Panel) layoutGridChildren(pd *panelData, offsetStart matrix.Vec2, ps ma
 			slog.Error("No UI component on entity")
 			continue
 		}
-		kLayout := kui.Layout()
-		switch kLayout.Positioning() {
+		switch kui.Layout().Positioning() {
 		case PositioningAbsolute, PositioningFixed, PositioningSticky:
 			continue
 		}
-		rowSpan := gridTrackSpan(kLayout.GridRowStart(), kLayout.GridRowEnd())
-		colSpan := gridTrackSpan(kLayout.GridColumnStart(), kLayout.GridColumnEnd())
-		if colSpan > effectiveColumns {
-			colSpan = effectiveColumns
-		}
-		row, col := requestedGridCell(occupied, effectiveColumns, cursorRow, cursorCol,
-			kLayout.GridRowStart(), kLayout.GridColumnStart(), rowSpan, colSpan)
-		if kLayout.GridRowStart() == 0 && kLayout.GridColumnStart() == 0 {
-			cursorRow, cursorCol = row, col+colSpan
-		}
-		occupyGridArea(occupied, row, col, rowSpan, colSpan)
-		rowCount = max(rowCount, row+rowSpan)
-		items = append(items, gridLayoutItem{
-			ui:      kui,
-			row:     row,
-			col:     col,
-			rowSpan: rowSpan,
-			colSpan: colSpan,
-		})
+		kids = append(kids, kui)
 	}
-	if rowCount == 0 {
+	if len(kids) == 0 {
 		return matrix.Vec2{innerWidth, innerTop + p.layout.padding.Bottom() + p.layout.border.Bottom()}
 	}
-	rowHeights := make([]float32, rowCount)
-	if pd.gridAutoRows > 0 {
-		for i := range rowHeights {
-			rowHeights[i] = pd.gridAutoRows
-		}
+	sort.SliceStable(kids, func(i, j int) bool {
+		return kids[i].Layout().FlexOrder() < kids[j].Layout().FlexOrder()
+	})
+	lines := make([]gridItemLines, len(kids))
+	for i, kui := range kids {
+		kLayout := kui.Layout()
+		lines[i].rowStart, lines[i].rowEnd = kLayout.GridRowLines()
+		lines[i].columnStart, lines[i].columnEnd = kLayout.GridColumnLines()
 	}
-	for i := range items {
-		kLayout := items[i].ui.Layout()
+	areas, rowCount, colCount := p.gridTemplate().place(lines)
+	rowItems := make([]gridTrackItem, len(kids))
+	colContent := make([]float32, colCount)
+	for i, kui := range kids {
+		kLayout := kui.Layout()
 		kSize := kLayout.PixelSize()
 		margin := kLayout.Margin()
-		rowHeights[items[i].row] = matrix.Max(rowHeights[items[i].row], kSize.Y()+margin.Vertical())
-	}
-	rowOffsets := make([]float32, rowCount)
-	y := startY
-	for i := 0; i < rowCount; i++ {
-		rowOffsets[i] = y
-		y += rowHeights[i] + gapY
+		rowItems[i] = gridTrackItem{
+			pos:  areas[i].row,
+			span: areas[i].rowSpan,
+			size: kSize.Y() + margin.Vertical(),
+		}
+		if areas[i].colSpan == 1 {
+			colContent[areas[i].col] = max(colContent[areas[i].col], kSize.X()+margin.Horizontal())
+		}
 	}
+	colWidths := p.computeGridColumnWidths(innerWidth, gapX, colCount, colContent)
+	rowHeights := sizeGridTracks(pd.gridTemplateRows, rowCount, pd.gridAutoRows, rowItems, innerHeight, gapY)
+	colOffsets := gridTrackOffsets(colWidths, gapX)
+	rowOffsets := gridTrackOffsets(rowHeights, gapY)
 	contentSize := matrix.Vec2{innerWidth, innerTop}
-	for i := range items {
-		kui := items[i].ui
+	for i, kui := range kids {
 		kLayout := kui.Layout()
 		kSize := kLayout.PixelSize()
 		margin := kLayout.Margin()
-		cellX := startX
-		for col := 0; col < items[i].col && col < len(colWidths); col++ {
-			cellX += colWidths[col] + gapX
-		}
-		x := cellX + margin.X() // left aligned like CSS start
-		itemY := rowOffsets[items[i].row] + margin.Y()
-		kLayout.SetRowLayoutOffset(matrix.NewVec2(x, itemY))
+		area := areas[i]
+		justify := kLayout.JustifySelf()
+		if justify == FlexAlignAuto {
+			justify = pd.gridJustifyItems
+		}
+		align := kLayout.AlignSelf()
+		if align == FlexAlignAuto {
+			align = pd.flexAlignItems
+		}
+		cellW := gridSpanSize(colWidths, area.col, area.colSpan, gapX)
+		cellH := gridSpanSize(rowHeights, area.row, area.rowSpan, gapY)
+		x := startX + colOffsets[area.col] + margin.X() +
+			gridAlignOffset(justify, cellW, kSize.X()+margin.Horizontal())
+		itemY := startY + rowOffsets[area.row] + margin.Y() +
+			gridAlignOffset(align, cellH, kSize.Y()+margin.Vertical())
+		kLayout.SetRowLayoutOffset(matrix.NewVec2(mirror.x(kLayout, x), itemY))
 		right := (x - startX) + kSize.X() + margin.Z()
 		contentSize.SetX(matrix.Max(contentSize.X(), right))
 		bottom := itemY - offsetStart.Y() + kSize.Y() + margin.W()
@@ -1873,12 +1910,12 @@ func (p *Panel) createScrollBar() *Panel {
 	sb := man.Add().ToPanel()
 	sb.Init(scrollBarTex, ElementTypePanel)
 	sb.DontFitContent()
-	sb.SetColor(matrix.ColorGray())
+	sb.SetColor(p.scrollbarThumbColor())
 	sb.layout.SetPositioning(PositioningAbsolute)
 	sb.layout.Scale(scrollBarWidth, scrollBarWidth)
 	sb.layout.SetZ(10)
 	sb.Base().AddEvent(EventTypeEnter, func() {
-		sb.EnforceColor(matrix.NewColor(0.575, 0.575, 0.575, 1.0))
+		sb.EnforceColor(p.scrollbarHoverColor())
 	})
 	sb.Base().AddEvent(EventTypeExit, func() {
 		sb.UnEnforceColor()
@@ -1903,26 +1940,27 @@ func (p *Panel) updateScrollBars() {
 	if pd.scrollBarX == nil && pd.scrollBarY == nil {
 		return
 	}
-	if pd.scrollBarsHidden {
-		if pd.scrollBarX != nil {
-			pd.scrollBarX.Base().Hide()
-		}
-		if pd.scrollBarY != nil {
-			pd.scrollBarY.Base().Hide()
-		}
-		return
+	var trackX, trackY *Panel
+	if pd.scrollStyle != nil {
+		trackX, trackY = pd.scrollStyle.trackX, pd.scrollStyle.trackY
 	}
-	if !p.flags.hovering() {
+	if pd.scrollBarsHidden || !p.flags.hovering() {
 		if pd.scrollBarX != nil {
 			pd.scrollBarX.Base().Hide()
 		}
 		if pd.scrollBarY != nil {
 			pd.scrollBarY.Base().Hide()
 		}
+		placeScrollTrack(trackX, false, 0, 0, 0, 0)
+		placeScrollTrack(trackY, false, 0, 0, 0, 0)
 		return
 	}
 	ps := p.layout.PixelSize()
 	panelW, panelH := ps.X(), ps.Y()
+	placeScrollTrack(trackX, pd.scrollBarX != nil && pd.maxScroll.X() > 0,
+		0, panelH-scrollBarWidth, panelW, 12)
+	placeScrollTrack(trackY, pd.scrollBarY != nil && pd.maxScroll.Y() > 0,
+		panelW-scrollBarWidth, 0, 12, panelH)
 	if pd.scrollBarX != nil && p.flags.hovering() {
 		y := panelH - scrollBarWidth
 		pd.scrollBarX.layout.SetOffsetY(y)
@@ -2005,6 +2043,9 @@ func (p *Panel) BorderSize() matrix.Vec4     { return p.layout.Border() }
 func (p *Panel) BorderStyle() [4]BorderStyle { return p.PanelData().borderStyle }
 
 func (p *Panel) BorderColor() [4]matrix.Color {
+	if fc := p.PanelData().unfiltered; fc != nil {
+		return fc.border
+	}
 	return p.shaderData.BorderColor
 }
 
@@ -2012,6 +2053,13 @@ func (p *Panel) OutlineOutset() float32 {
 	return p.shaderData.OutlineSize.X() + p.shaderData.OutlineSize.Y()
 }
 
+// paintOutset is how far past its border box the panel draws, from either its
+// outline, its outer box shadows or its border image
+func (p *Panel) paintOutset() float32 {
+	return max(p.OutlineOutset(), BoxShadowsOutset(p.PanelData().boxShadows),
+		p.borderImageOutset())
+}
+
 func (p *Panel) SetBorderRadius(topLeft, topRight, bottomRight, bottomLeft float32) {
 	p.shaderData.BorderRadius = matrix.Vec4{
 		bottomLeft, bottomRight, topRight, topLeft}
@@ -2026,7 +2074,9 @@ func (p *Panel) SetBorderSize(left, top, right, bottom float32) {
 	p.layout.SetBorder(left, top, right, bottom)
 	// TODO:  If there isn't a border, it should be transparent when created
 	p.ensureBGExists(nil)
-	p.shaderData.BorderSize = p.layout.Border()
+	if b := p.PanelData().borderImage; b == nil || !b.hidBorder {
+		p.shaderData.BorderSize = p.layout.Border()
+	}
 }
 
 func (p *Panel) SetBorderStyle(left, top, right, bottom BorderStyle) {
@@ -2034,10 +2084,22 @@ func (p *Panel) SetBorderStyle(left, top, right, bottom BorderStyle) {
 }
 
 func (p *Panel) SetBorderColor(left, top, right, bottom matrix.Color) {
-	p.shaderData.BorderColor = [4]matrix.Color{left, top, right, bottom}
+	colors := [4]matrix.Color{left, top, right, bottom}
+	if fc := p.PanelData().unfiltered; fc != nil {
+		fc.border = colors
+		for i := range colors {
+			colors[i] = p.filterColor(colors[i])
+		}
+	}
+	p.shaderData.BorderColor = colors
 }
 
-func (p *Panel) OutlineColor() matrix.Color { return p.shaderData.OutlineColor }
+func (p *Panel) OutlineColor() matrix.Color {
+	if fc := p.PanelData().unfiltered; fc != nil {
+		return fc.outline
+	}
+	return p.shaderData.OutlineColor
+}
 
 func (p *Panel) OutlineWidth() float32 { return p.shaderData.OutlineSize.X() }
 
@@ -2053,6 +2115,10 @@ func (p *Panel) SetOutline(width, offset float32, color matrix.Color) {
 	if width > 0 && color.A() > 0 {
 		p.ensureBGExists(nil)
 	}
+	if fc := p.PanelData().unfiltered; fc != nil {
+		fc.outline = color
+		color = p.filterColor(color)
+	}
 	p.shaderData.OutlineSize = matrix.NewVec2(width, offset)
 	p.shaderData.OutlineColor = color
 	p.Base().SetDirty(DirtyTypeLayout)
@@ -2092,6 +2158,14 @@ func (p *Panel) HasEnforcedColor() bool {
 
 func (p *Panel) setColorInternal(bgColor matrix.Color) {
 	p.ensureBGExists(nil)
+	if fc := p.PanelData().unfiltered; fc != nil {
+		fc.fill = bgColor
+		bgColor = p.filterColor(bgColor)
+	}
+	p.writeFillColor(bgColor)
+}
+
+func (p *Panel) writeFillColor(bgColor matrix.Color) {
 	if p.shaderData.FgColor.Equals(bgColor) {
 		return
 	}
@@ -0,0 +1,433 @@
+/******************************************************************************/
+/* panel_border_image.go                                                      */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"log/slog"
+
+	"kaijuengine.com/engine/assets"
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering"
+)
+
+// borderImageDepthBias places the border image in front of the panel's
+// background and behind its children, which are 0.01 in front
+const borderImageDepthBias = 0.004
+
+// maxBorderImageTiles limits how many times a side of a border image is
+// repeated, more than this and the tiles are rounded to fit this count
+const maxBorderImageTiles = 64
+
+type BorderImageRepeatMode = int
+
+const (
+	BorderImageStretch BorderImageRepeatMode = iota
+	BorderImageRepeat
+	BorderImageRound
+	BorderImageSpace
+)
+
+type BorderImageUnit = int
+
+const (
+	BorderImagePixels BorderImageUnit = iota
+	BorderImagePercent
+	BorderImageNumber
+	BorderImageAuto
+)
+
+// BorderImageLength is a slice, width or outset of a border image.
+// Percentages are fractions
+type BorderImageLength struct {
+	Value float32
+	Unit  BorderImageUnit
+}
+
+// BorderImage is the 9-slice image drawn over the border of a panel. The
+// sides are in the CSS order of top, right, bottom and left
+type BorderImage struct {
+	Texture *rendering.Texture
+	// Slice is the inset of each side into the image, numbers are pixels of
+	// the image
+	Slice [4]BorderImageLength
+	// Fill draws the middle of the image behind the content
+	Fill bool
+	// Width of each side, numbers are multiples of the border width and
+	// auto is the size of the slice
+	Width [4]BorderImageLength
+	// Outset of each side past the border box, numbers are multiples of the
+	// border width
+	Outset [4]BorderImageLength
+	// Repeat of the horizontal and the vertical sides
+	Repeat [2]BorderImageRepeatMode
+}
+
+// DefaultBorderImage is the border image of the initial CSS values without
+// a texture
+func DefaultBorderImage() BorderImage {
+	full := BorderImageLength{1, BorderImagePercent}
+	one := BorderImageLength{1, BorderImageNumber}
+	return BorderImage{
+		Slice: [4]BorderImageLength{full, full, full, full},
+		Width: [4]BorderImageLength{one, one, one, one},
+	}
+}
+
+type panelBorderImage struct {
+	image      BorderImage
+	shaderData []*ShaderData
+	// The texture and material the drawings were made with
+	texture     *rendering.Texture
+	transparent bool
+	hidBorder   bool
+}
+
+// borderImageQuad is a piece of the border image, rect is the x, y, width
+// and height from the top left of the border box with y going down
+type borderImageQuad struct {
+	rect matrix.Vec4
+	uvs  matrix.Vec4
+}
+
+// borderImageTile is a repeat of a side of the image along one axis, the
+// uv start and length are fractions of the slice of the image
+type borderImageTile struct {
+	start, length     float32
+	uvStart, uvLength float32
+}
+
+func (p *Panel) borderImageState() *panelBorderImage {
+	pd := p.PanelData()
+	if pd.borderImage == nil {
+		pd.borderImage = &panelBorderImage{image: DefaultBorderImage()}
+	}
+	return pd.borderImage
+}
+
+func (p *Panel) clearBorderImageStyles() {
+	if b := p.PanelData().borderImage; b != nil {
+		b.image = DefaultBorderImage()
+	}
+}
+
+func (p *Panel) BorderImage() BorderImage {
+	if b := p.PanelData().borderImage; b != nil {
+		return b.image
+	}
+	return DefaultBorderImage()
+}
+
+// SetBorderImage draws the image over the border of the panel in place of
+// the border colors, an image without a texture removes it
+func (p *Panel) SetBorderImage(image BorderImage) {
+	p.borderImageState().image = image
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// borderImageOutset is the furthest the border image paints past the
+// border box
+func (p *Panel) borderImageOutset() float32 {
+	b := p.PanelData().borderImage
+	if b == nil || b.image.Texture == nil {
+		return 0
+	}
+	outset := float32(0)
+	for i, s := range borderImageSides(p.layout.Border()) {
+		outset = max(outset, borderImageOutsetLength(b.image.Outset[i], s))
+	}
+	return outset
+}
+
+// borderImageSides reorders the layout border (left, top, right, bottom)
+// into the CSS order
+func borderImageSides(border matrix.Vec4) [4]float32 {
+	return [4]float32{border.Y(), border.Z(), border.W(), border.X()}
+}
+
+func borderImageOutsetLength(l BorderImageLength, side float32) float32 {
+	if l.Unit == BorderImageNumber {
+		return l.Value * side
+	}
+	return l.Value
+}
+
+func borderImageTiles(length, tile float32, mode BorderImageRepeatMode) []borderImageTile {
+	if length <= 0 {
+		return nil
+	}
+	if mode == BorderImageStretch || tile <= 0 {
+		return []borderImageTile{{0, length, 0, 1}}
+	}
+	if mode == BorderImageRepeat {
+		n := int(matrix.Ceil(length / tile))
+		// An odd count keeps a whole tile in the middle
+		if n%2 == 0 {
+			n++
+		}
+		if n <= maxBorderImageTiles {
+			tiles := make([]borderImageTile, 0, n)
+			offset := (length - float32(n)*tile) * 0.5
+			for i := range n {
+				s := offset + float32(i)*tile
+				start, end := max(s, 0), min(s+tile, length)
+				if end > start {
+					tiles = append(tiles, borderImageTile{start, end - start,
+						(start - s) / tile, (end - start) / tile})
+				}
+			}
+			return tiles
+		}
+		mode = BorderImageRound
+	}
+	if mode == BorderImageSpace {
+		n := min(int(matrix.Floor(length/tile)), maxBorderImageTiles)
+		if n == 0 {
+			return nil
+		}
+		gap := (length - float32(n)*tile) / float32(n+1)
+		tiles := make([]borderImageTile, n)
+		for i := range tiles {
+			tiles[i] = borderImageTile{gap + float32(i)*(tile+gap), tile, 0, 1}
+		}
+		return tiles
+	}
+	n := min(max(1, int(matrix.Round(length/tile))), maxBorderImageTiles)
+	size := length / float32(n)
+	tiles := make([]borderImageTile, n)
+	for i := range tiles {
+		tiles[i] = borderImageTile{float32(i) * size, size, 0, 1}
+	}
+	return tiles
+}
+
+func borderImageScale(width, slice float32) float32 {
+	if width > 0 && slice > 0 {
+		return width / slice
+	}
+	return 0
+}
+
+// borderImageQuads cuts the image into its 9 regions and places (and
+// repeats) them around the border box of the given size
+func borderImageQuads(img BorderImage, size matrix.Vec2, border matrix.Vec4, texSize matrix.Vec2) []borderImageQuad {
+	tw, th := texSize.X(), texSize.Y()
+	if tw <= 0 || th <= 0 || size.X() <= 0 || size.Y() <= 0 {
+		return nil
+	}
+	sides := borderImageSides(border)
+	var slice, outset, width [4]float32
+	for i := range 4 {
+		extent := th
+		if i%2 == 1 {
+			extent = tw
+		}
+		s := img.Slice[i]
+		if s.Unit == BorderImagePercent {
+			slice[i] = s.Value * extent
+		} else {
+			slice[i] = s.Value
+		}
+		slice[i] = matrix.Clamp(slice[i], 0, extent)
+		outset[i] = max(0, borderImageOutsetLength(img.Outset[i], sides[i]))
+	}
+	x0, y0 := -outset[3], -outset[0]
+	aw := size.X() + outset[1] + outset[3]
+	ah := size.Y() + outset[0] + outset[2]
+	for i := range 4 {
+		extent := ah
+		if i%2 == 1 {
+			extent = aw
+		}
+		switch w := img.Width[i]; w.Unit {
+		case BorderImagePercent:
+			width[i] = w.Value * extent
+		case BorderImageNumber:
+			width[i] = w.Value * sides[i]
+		case BorderImageAuto:
+			width[i] = slice[i]
+		default:
+			width[i] = w.Value
+		}
+		width[i] = max(0, width[i])
+	}
+	// Opposite sides that overlap are scaled down together
+	f := float32(1)
+	if lr := width[1] + width[3]; lr > aw {
+		f = min(f, aw/lr)
+	}
+	if tb := width[0] + width[2]; tb > ah {
+		f = min(f, ah/tb)
+	}
+	for i := range width {
+		width[i] *= f
+	}
+	xs := [4]float32{x0, x0 + width[3], x0 + aw - width[1], x0 + aw}
+	ys := [4]float32{y0, y0 + width[0], y0 + ah - width[2], y0 + ah}
+	us := [4]float32{0, slice[3], tw - slice[1], tw}
+	vs := [4]float32{0, slice[0], th - slice[2], th}
+	// The middle is scaled like the top (or bottom) and the left (or right)
+	scaleX := borderImageScale(width[0], slice[0])
+	if scaleX == 0 {
+		scaleX = borderImageScale(width[2], slice[2])
+	}
+	scaleY := borderImageScale(width[3], slice[3])
+	if scaleY == 0 {
+		scaleY = borderImageScale(width[1], slice[1])
+	}
+	quads := []borderImageQuad{}
+	for row := range 3 {
+		for col := range 3 {
+			if row == 1 && col == 1 && !img.Fill {
+				continue
+			}
+			dw, dh := xs[col+1]-xs[col], ys[row+1]-ys[row]
+			sw, sh := us[col+1]-us[col], vs[row+1]-vs[row]
+			if dw <= 0 || dh <= 0 || sw <= 0 || sh <= 0 {
+				continue
+			}
+			xTiles := []borderImageTile{{0, dw, 0, 1}}
+			if col == 1 {
+				scale := scaleX
+				if row != 1 {
+					scale = dh / sh
+				}
+				xTiles = borderImageTiles(dw, sw*scale, img.Repeat[0])
+			}
+			yTiles := []borderImageTile{{0, dh, 0, 1}}
+			if row == 1 {
+				scale := scaleY
+				if col != 1 {
+					scale = dw / sw
+				}
+				yTiles = borderImageTiles(dh, sh*scale, img.Repeat[1])
+			}
+			for _, yt := range yTiles {
+				for _, xt := range xTiles {
+					u := us[col] + xt.uvStart*sw
+					v := vs[row] + yt.uvStart*sh
+					uw, vh := xt.uvLength*sw/tw, yt.uvLength*sh/th
+					quads = append(quads, borderImageQuad{
+						rect: matrix.Vec4{xs[col] + xt.start, ys[row] + yt.start, xt.length, yt.length},
+						uvs:  matrix.Vec4{u / tw, 1 - v/th - vh, uw, vh},
+					})
+				}
+			}
+		}
+	}
+	return quads
+}
+
+func (p *Panel) borderImageMaterial(transparent bool, tex *rendering.Texture) *rendering.Material {
+	host := p.man.Value().Host
+	key := assets.MaterialDefinitionUI
+	if transparent {
+		key = assets.MaterialDefinitionUITransparent
+	}
+	material, err := host.MaterialCache().Material(key)
+	if err != nil {
+		slog.Error("failed to load the ui material for the border image", "error", err)
+		return nil
+	}
+	return material.CreateInstance([]*rendering.Texture{tex})
+}
+
+func (p *Panel) clearBorderImage() {
+	b := p.PanelData().borderImage
+	if b == nil {
+		return
+	}
+	for i := range b.shaderData {
+		b.shaderData[i].Destroy()
+	}
+	b.shaderData = b.shaderData[:0]
+	b.texture = nil
+}
+
+func (p *Panel) activateBorderImage() {
+	if b := p.PanelData().borderImage; b != nil {
+		for _, sd := range b.shaderData {
+			sd.Activate()
+		}
+	}
+}
+
+func (p *Panel) deactivateBorderImage() {
+	if b := p.PanelData().borderImage; b != nil {
+		for _, sd := range b.shaderData {
+			sd.Deactivate()
+		}
+	}
+}
+
+// updateBorderImage lays out the pieces of the border image for the
+// current size of the panel, the drawings are only remade when the number
+// of pieces, the texture or the blending of the panel changes
+func (p *Panel) updateBorderImage() {
+	pd := p.PanelData()
+	b := pd.borderImage
+	if b == nil {
+		return
+	}
+	var quads []borderImageQuad
+	tex := b.image.Texture
+	if tex != nil {
+		quads = borderImageQuads(b.image, p.layout.PixelSize(), p.layout.Border(), tex.Size())
+	}
+	if tex != nil && !b.hidBorder {
+		// The image is drawn in place of the border styles
+		p.shaderData.BorderSize = matrix.Vec4Zero()
+		b.hidBorder = true
+	} else if tex == nil && b.hidBorder {
+		p.shaderData.BorderSize = p.layout.Border()
+		b.hidBorder = false
+	}
+	transparent := pd.transparentDrawing.Material != nil
+	if len(quads) != len(b.shaderData) || tex != b.texture || transparent != b.transparent {
+		p.clearBorderImage()
+		if len(quads) == 0 {
+			return
+		}
+		material := p.borderImageMaterial(transparent, tex)
+		if material == nil {
+			return
+		}
+		host := p.man.Value().Host
+		for range quads {
+			sd := &ShaderData{ShaderDataBase: rendering.NewShaderDataBase()}
+			b.shaderData = append(b.shaderData, sd)
+			host.Drawings.AddDrawing(rendering.Drawing{
+				Material:   material,
+				Mesh:       rendering.NewMeshQuad(host.MeshCache()),
+				ShaderData: sd,
+				Transform:  &p.entity.Transform,
+				Layer:      rendering.RenderLayerUI,
+				ViewCuller: &host.Cameras.UI,
+			})
+		}
+		b.texture, b.transparent = tex, transparent
+		if !p.entity.IsActive() {
+			p.deactivateBorderImage()
+		}
+	}
+	ws := p.entity.Transform.WorldScale()
+	size := p.layout.PixelSize()
+	color := FilterColor(p.Base().effectiveFilters(), matrix.ColorWhite())
+	for i, q := range quads {
+		sd := b.shaderData[i]
+		sd.UVs = q.uvs
+		sd.FgColor = color
+		sd.Scissor = p.shaderData.Scissor
+		sd.Size2D = matrix.Vec4{q.rect.Z(), q.rect.W(), q.rect.Z(), q.rect.W()}
+		model := matrix.Mat4Identity()
+		model[0] = safeDivide(q.rect.Z(), size.X())
+		model[5] = safeDivide(q.rect.W(), size.Y())
+		model[12] = safeDivide(q.rect.X()+q.rect.Z()*0.5, size.X()) - 0.5
+		model[13] = 0.5 - safeDivide(q.rect.Y()+q.rect.W()*0.5, size.Y())
+		model[14] = safeDivide(borderImageDepthBias, ws.Z())
+		sd.SetModel(model)
+	}
+}
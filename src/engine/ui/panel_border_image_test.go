@@ -0,0 +1,101 @@
+/******************************************************************************/
+/* panel_border_image_test.go                                                 */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"testing"
+
+	"kaijuengine.com/matrix"
+)
+
+func TestBorderImageTiles(t *testing.T) {
+	if tiles := borderImageTiles(100, 30, BorderImageStretch); len(tiles) != 1 || tiles[0].length != 100 {
+		t.Fatalf("expected a single stretched tile, got %v", tiles)
+	}
+	tiles := borderImageTiles(100, 30, BorderImageRound)
+	if len(tiles) != 3 || !matrix.Approx(tiles[2].start, 100.0*2/3) {
+		t.Fatalf("expected 3 rounded tiles, got %v", tiles)
+	}
+	tiles = borderImageTiles(100, 30, BorderImageSpace)
+	if len(tiles) != 3 || !matrix.Approx(tiles[0].start, 2.5) || tiles[0].length != 30 {
+		t.Fatalf("expected 3 spaced tiles, got %v", tiles)
+	}
+	if tiles := borderImageTiles(20, 30, BorderImageSpace); len(tiles) != 0 {
+		t.Fatalf("expected no room for a spaced tile, got %v", tiles)
+	}
+	// 100/30 needs 4 tiles, made odd so one is centered, the ends are cut
+	tiles = borderImageTiles(100, 30, BorderImageRepeat)
+	if len(tiles) != 5 {
+		t.Fatalf("expected 5 repeated tiles, got %v", tiles)
+	}
+	if !matrix.Approx(tiles[2].start, 35) || tiles[2].uvLength != 1 {
+		t.Fatalf("expected a whole tile in the middle, got %v", tiles[2])
+	}
+	if tiles[0].start != 0 || !matrix.Approx(tiles[0].length, 5) ||
+		!matrix.Approx(tiles[0].uvStart, 25.0/30) {
+		t.Fatalf("expected the first tile to be cut at the start, got %v", tiles[0])
+	}
+}
+
+func TestBorderImageQuads(t *testing.T) {
+	img := DefaultBorderImage()
+	px := func(v float32) BorderImageLength { return BorderImageLength{v, BorderImagePixels} }
+	img.Slice = [4]BorderImageLength{px(10), px(10), px(10), px(10)}
+	img.Width = [4]BorderImageLength{px(20), px(20), px(20), px(20)}
+	quads := borderImageQuads(img, matrix.Vec2{100, 60}, matrix.Vec4{}, matrix.Vec2{30, 30})
+	if len(quads) != 8 {
+		t.Fatalf("expected 8 pieces without fill, got %d", len(quads))
+	}
+	// Top left corner, the top 10 pixels of the image are the last 1/3 of v
+	if quads[0].rect != (matrix.Vec4{0, 0, 20, 20}) ||
+		!matrix.Vec4ApproxTo(quads[0].uvs, matrix.Vec4{0, 2.0 / 3, 1.0 / 3, 1.0 / 3}, 0.001) {
+		t.Fatalf("unexpected top left corner %v", quads[0])
+	}
+	// Bottom right corner
+	if last := quads[len(quads)-1]; last.rect != (matrix.Vec4{80, 40, 20, 20}) {
+		t.Fatalf("unexpected bottom right corner %v", last)
+	}
+	img.Fill = true
+	img.Outset = [4]BorderImageLength{px(5), px(5), px(5), px(5)}
+	quads = borderImageQuads(img, matrix.Vec2{100, 60}, matrix.Vec4{}, matrix.Vec2{30, 30})
+	if len(quads) != 9 || quads[0].rect.X() != -5 || quads[4].rect != (matrix.Vec4{15, 15, 70, 30}) {
+		t.Fatalf("expected the outset area to be filled, got %v", quads)
+	}
+}
+
+func TestBorderImageWidthDefaultsToBorder(t *testing.T) {
+	img := DefaultBorderImage()
+	img.Slice[0] = BorderImageLength{0.25, BorderImagePercent}
+	img.Slice[1], img.Slice[2], img.Slice[3] = img.Slice[0], img.Slice[0], img.Slice[0]
+	img.Repeat = [2]BorderImageRepeatMode{BorderImageRound, BorderImageRound}
+	// left, top, right, bottom borders
+	quads := borderImageQuads(img, matrix.Vec2{100, 100}, matrix.Vec4{4, 8, 4, 8}, matrix.Vec2{40, 40})
+	if quads[0].rect != (matrix.Vec4{0, 0, 4, 8}) {
+		t.Fatalf("expected the corner to be the size of the borders, got %v", quads[0].rect)
+	}
+	// The top edge is scaled from 10px to 8px tall, so 16px tiles over 92px
+	top := 0
+	for _, q := range quads {
+		if q.rect.Y() == 0 && q.rect.X() >= 4 && q.rect.X() < 96 {
+			top++
+		}
+	}
+	if top != 6 {
+		t.Fatalf("expected 6 rounded tiles along the top, got %d", top)
+	}
+}
+
+func TestBorderImageOverlappingWidthsScaleDown(t *testing.T) {
+	img := DefaultBorderImage()
+	px := BorderImageLength{80, BorderImagePixels}
+	img.Width = [4]BorderImageLength{px, px, px, px}
+	quads := borderImageQuads(img, matrix.Vec2{100, 100}, matrix.Vec4{}, matrix.Vec2{30, 30})
+	// The slices cover the whole image, so only the scaled corners remain
+	if len(quads) != 4 || quads[0].rect != (matrix.Vec4{0, 0, 50, 50}) {
+		t.Fatalf("expected 4 corners scaled to half the size, got %v", quads)
+	}
+}
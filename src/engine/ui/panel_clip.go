@@ -0,0 +1,550 @@
+/******************************************************************************/
+/* panel_clip.go                                                              */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering"
+)
+
+// ClipLength is a length of a clip shape or a gradient in pixels, or a
+// fraction of the reference box when Percent is set
+type ClipLength = TransformLength
+
+type ClipShapeKind = int
+
+const (
+	ClipShapeNone ClipShapeKind = iota
+	ClipShapeCircle
+	ClipShapeEllipse
+	ClipShapeInset
+	ClipShapePolygon
+)
+
+// ClipBox is the box of the panel that a clip shape is placed in
+type ClipBox = int
+
+const (
+	ClipBoxBorder ClipBox = iota
+	ClipBoxPadding
+	ClipBoxContent
+	ClipBoxMargin
+)
+
+// The radius of a circle or an ellipse can be one of these keywords rather
+// than a length
+const (
+	ClipRadiusClosestSide  = -1
+	ClipRadiusFarthestSide = -2
+)
+
+// MaxClipPolygonPoints is the most points of a polygon that can be drawn
+const MaxClipPolygonPoints = 8
+
+// MaxMaskGradientStops is the most color stops a mask gradient can have
+const MaxMaskGradientStops = 4
+
+// The kinds of shapes and masks as they are read by inc_ui_clip.inl
+const (
+	clipShaderNone = iota
+	clipShaderEllipse
+	clipShaderInset
+	clipShaderPolygon
+)
+
+const (
+	clipMaskShaderNone = iota
+	clipMaskShaderLinear
+	clipMaskShaderRadial
+)
+
+// ClipShape is a basic shape of clip-path. Positions are from the top left
+// of the reference box with y going down, the same as in CSS
+type ClipShape struct {
+	Kind ClipShapeKind
+	Box  ClipBox
+	// Center of a circle or an ellipse
+	Center [2]ClipLength
+	// Radii of an ellipse, a circle only uses the first one. Either can be
+	// one of the ClipRadius keywords
+	Radii [2]ClipLength
+	// Insets from the top, right, bottom and left of the box
+	Insets [4]ClipLength
+	// Round is the corner radius of an inset
+	Round  ClipLength
+	Points [][2]ClipLength
+}
+
+type MaskMode = int
+
+const (
+	MaskModeAlpha MaskMode = iota
+	MaskModeLuminance
+)
+
+type GradientKind = int
+
+const (
+	GradientLinear GradientKind = iota
+	GradientRadial
+)
+
+// GradientExtent is where the ending shape of a radial gradient reaches to
+type GradientExtent = int
+
+const (
+	GradientExtentFarthestCorner GradientExtent = iota
+	GradientExtentFarthestSide
+	GradientExtentClosestCorner
+	GradientExtentClosestSide
+	GradientExtentSize
+)
+
+type GradientStop struct {
+	Color matrix.Color
+	// Position along the gradient line, only used when HasPosition is set
+	Position    ClipLength
+	HasPosition bool
+}
+
+// Gradient is a CSS linear or radial gradient. As a mask only the alpha (or
+// the luminance) of the color stops is used
+type Gradient struct {
+	Kind      GradientKind
+	Repeating bool
+	// Angle of a linear gradient in degrees, 0 goes up and 180 goes down
+	Angle float32
+	// Corner is set for a linear gradient that goes to a corner, x is -1
+	// (left) or 1 (right) and y is -1 (top) or 1 (bottom)
+	Corner matrix.Vec2
+	// Circle is set for a radial gradient shaped as a circle rather than an
+	// ellipse
+	Circle bool
+	Center [2]ClipLength
+	Extent GradientExtent
+	// Size is the radii of a radial gradient with GradientExtentSize
+	Size  [2]ClipLength
+	Stops []GradientStop
+}
+
+type panelClip struct {
+	shape    ClipShape
+	mask     *Gradient
+	maskMode MaskMode
+}
+
+// uiClip is a clip shape and mask resolved into UI world space, packed the
+// way that inc_ui_clip.inl reads them
+type uiClip struct {
+	// The packed kind of shape, point or stop count, kind of mask and mask
+	// repeat (see packClipShape), then the corner radius of an inset
+	shape matrix.Vec2
+	// Polygon points (2 per column), or the center and radii of the shape
+	// followed by the geometry, stop positions and stop values of the mask
+	data matrix.Mat4
+}
+
+// packClipShape packs the kinds of the shape and the mask into one float,
+// the count is the polygon points or the mask stops as the two can't be
+// used together
+func packClipShape(kind, count, mask int, repeat bool) float32 {
+	packed := kind | count<<2 | mask<<6
+	if repeat {
+		packed |= 1 << 8
+	}
+	return float32(packed)
+}
+
+func (c uiClip) kind() int       { return int(c.shape.X()) & 3 }
+func (c uiClip) count() int      { return int(c.shape.X()) >> 2 & 15 }
+func (c uiClip) maskKind() int   { return int(c.shape.X()) >> 6 & 3 }
+func (c uiClip) radius() float32 { return c.shape.Y() }
+
+func (c uiClip) rect() matrix.Vec4 {
+	return matrix.Vec4{c.data[0], c.data[1], c.data[2], c.data[3]}
+}
+
+func (c *uiClip) setRect(rect matrix.Vec4) {
+	c.data[0], c.data[1], c.data[2], c.data[3] = rect.X(), rect.Y(), rect.Z(), rect.W()
+}
+
+func (p *Panel) clipState() *panelClip {
+	pd := p.PanelData()
+	if pd.clip == nil {
+		pd.clip = &panelClip{}
+	}
+	return pd.clip
+}
+
+func (p *Panel) clearClipStyles() {
+	if c := p.PanelData().clip; c != nil {
+		*c = panelClip{}
+	}
+}
+
+func (c *panelClip) active() bool {
+	return c.shape.Kind != ClipShapeNone || c.mask != nil
+}
+
+func (p *Panel) ClipPath() ClipShape {
+	if c := p.PanelData().clip; c != nil {
+		return c.shape
+	}
+	return ClipShape{}
+}
+
+// SetClipPath clips the panel and all of its children to the shape, a
+// shape of ClipShapeNone removes the clip
+func (p *Panel) SetClipPath(shape ClipShape) {
+	p.clipState().shape = shape
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+func (p *Panel) MaskGradient() *Gradient {
+	if c := p.PanelData().clip; c != nil {
+		return c.mask
+	}
+	return nil
+}
+
+// SetMaskGradient masks the panel and all of its children with the
+// gradient, nil removes the mask. A polygon clip path uses the same shader
+// data as the mask, so the mask isn't drawn for a panel clipped to one
+func (p *Panel) SetMaskGradient(mask *Gradient) {
+	if mask != nil && len(mask.Stops) > MaxMaskGradientStops {
+		mask.Stops = mask.Stops[:MaxMaskGradientStops]
+	}
+	p.clipState().mask = mask
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+func (p *Panel) SetMaskMode(mode MaskMode) {
+	p.clipState().maskMode = mode
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// clipReferenceBox is the world center and the size of one of the boxes of
+// the panel
+func (p *Panel) clipReferenceBox(box ClipBox) (matrix.Vec2, matrix.Vec2) {
+	center := p.entity.Transform.WorldPosition().AsVec2()
+	size := p.layout.PixelSize()
+	// How far each side (left, top, right, bottom) moves out of the border box
+	var grow matrix.Vec4
+	switch box {
+	case ClipBoxPadding:
+		grow = p.layout.Border().Negative()
+	case ClipBoxContent:
+		grow = p.layout.Border().Add(p.layout.Padding()).Negative()
+	case ClipBoxMargin:
+		grow = p.layout.Margin()
+	}
+	size = matrix.Vec2{
+		max(0, size.X()+grow.X()+grow.Z()),
+		max(0, size.Y()+grow.Y()+grow.W()),
+	}
+	center.SetX(center.X() + (grow.Z()-grow.X())*0.5)
+	center.SetY(center.Y() + (grow.Y()-grow.W())*0.5)
+	return center, size
+}
+
+// clipBoxPoint moves a point from the top left of the box (y down) into
+// world space (y up)
+func clipBoxPoint(center, size matrix.Vec2, x, y float32) matrix.Vec2 {
+	return matrix.Vec2{center.X() - size.X()*0.5 + x, center.Y() + size.Y()*0.5 - y}
+}
+
+func clipRadius(r ClipLength, near, far, percentOf float32) float32 {
+	switch {
+	case r.Percent:
+		return r.Value * percentOf
+	case r.Value == ClipRadiusClosestSide:
+		return near
+	case r.Value == ClipRadiusFarthestSide:
+		return far
+	}
+	return r.Value
+}
+
+// sideDistances is the distance from a position to the nearest and the
+// farthest side along one axis of a box
+func sideDistances(pos, size float32) (float32, float32) {
+	a, b := matrix.Abs(pos), matrix.Abs(size-pos)
+	return min(a, b), max(a, b)
+}
+
+func (s ClipShape) resolve(center, size matrix.Vec2) uiClip {
+	w, h := size.X(), size.Y()
+	c := uiClip{}
+	switch s.Kind {
+	case ClipShapeCircle, ClipShapeEllipse:
+		x, y := s.Center[0].resolve(w), s.Center[1].resolve(h)
+		nearX, farX := sideDistances(x, w)
+		nearY, farY := sideDistances(y, h)
+		var rx, ry float32
+		if s.Kind == ClipShapeCircle {
+			rx = clipRadius(s.Radii[0], min(nearX, nearY), max(farX, farY),
+				matrix.Sqrt(w*w+h*h)/matrix.Sqrt(2))
+			ry = rx
+		} else {
+			rx = clipRadius(s.Radii[0], nearX, farX, w)
+			ry = clipRadius(s.Radii[1], nearY, farY, h)
+		}
+		p := clipBoxPoint(center, size, x, y)
+		c.shape[0] = packClipShape(clipShaderEllipse, 0, clipMaskShaderNone, false)
+		c.setRect(matrix.Vec4{p.X(), p.Y(), rx, ry})
+	case ClipShapeInset:
+		top, right := s.Insets[0].resolve(h), s.Insets[1].resolve(w)
+		bottom, left := s.Insets[2].resolve(h), s.Insets[3].resolve(w)
+		hw, hh := max(0, w-left-right)*0.5, max(0, h-top-bottom)*0.5
+		p := clipBoxPoint(center, size, left+hw, top+hh)
+		c.shape = matrix.Vec2{
+			packClipShape(clipShaderInset, 0, clipMaskShaderNone, false),
+			max(0, min(s.Round.resolve(w), hw, hh)),
+		}
+		c.setRect(matrix.Vec4{p.X(), p.Y(), hw, hh})
+	case ClipShapePolygon:
+		count := min(len(s.Points), MaxClipPolygonPoints)
+		c.shape[0] = packClipShape(clipShaderPolygon, count, clipMaskShaderNone, false)
+		for i := range count {
+			p := clipBoxPoint(center, size, s.Points[i][0].resolve(w), s.Points[i][1].resolve(h))
+			c.data[i*2], c.data[i*2+1] = p.X(), p.Y()
+		}
+	}
+	return c
+}
+
+func (g *Gradient) radii(x, y, w, h float32) (float32, float32) {
+	nearX, farX := sideDistances(x, w)
+	nearY, farY := sideDistances(y, h)
+	switch g.Extent {
+	case GradientExtentSize:
+		rx := g.Size[0].resolve(w)
+		if g.Circle {
+			return rx, rx
+		}
+		return rx, g.Size[1].resolve(h)
+	case GradientExtentClosestSide:
+		if g.Circle {
+			r := min(nearX, nearY)
+			return r, r
+		}
+		return nearX, nearY
+	case GradientExtentFarthestSide:
+		if g.Circle {
+			r := max(farX, farY)
+			return r, r
+		}
+		return farX, farY
+	case GradientExtentClosestCorner:
+		if g.Circle {
+			r := matrix.Sqrt(nearX*nearX + nearY*nearY)
+			return r, r
+		}
+		return nearX * matrix.Sqrt(2), nearY * matrix.Sqrt(2)
+	default:
+		if g.Circle {
+			r := matrix.Sqrt(farX*farX + farY*farY)
+			return r, r
+		}
+		return farX * matrix.Sqrt(2), farY * matrix.Sqrt(2)
+	}
+}
+
+// gradientStopPositions places every stop along the gradient line as a
+// fraction of its length. Stops without a position are spread evenly
+// between their neighbors and no stop can come before the one ahead of it
+func gradientStopPositions(stops []GradientStop, length float32) []float32 {
+	n := len(stops)
+	out := make([]float32, n)
+	set := make([]bool, n)
+	for i := range stops {
+		if !stops[i].HasPosition {
+			continue
+		}
+		if pos := stops[i].Position; pos.Percent {
+			out[i] = pos.Value
+		} else if length > 0 {
+			out[i] = pos.Value / length
+		}
+		set[i] = true
+	}
+	if n == 0 {
+		return out
+	}
+	if !set[0] {
+		out[0], set[0] = 0, true
+	}
+	if n > 1 && !set[n-1] {
+		out[n-1], set[n-1] = 1, true
+	}
+	furthest := out[0]
+	for i := range out {
+		if set[i] {
+			out[i] = max(out[i], furthest)
+			furthest = out[i]
+		}
+	}
+	for i := 1; i < n; i++ {
+		if set[i] {
+			continue
+		}
+		j := i
+		for !set[j] {
+			j++
+		}
+		for k := i; k < j; k++ {
+			out[k] = out[i-1] + (out[j]-out[i-1])*float32(k-i+1)/float32(j-i+1)
+		}
+		i = j
+	}
+	return out
+}
+
+func maskValue(c matrix.Color, mode MaskMode) float32 {
+	if mode == MaskModeLuminance {
+		return (0.2126*c.R() + 0.7152*c.G() + 0.0722*c.B()) * c.A()
+	}
+	return c.A()
+}
+
+// resolve places the gradient over the box, returning the kind of mask for
+// the shader, the stop count and the geometry, stop positions and stop
+// values of the gradient
+func (g *Gradient) resolve(center, size matrix.Vec2, mode MaskMode) (int, int, [12]float32) {
+	w, h := size.X(), size.Y()
+	var data [12]float32
+	var kind int
+	var length float32
+	if g.Kind == GradientLinear {
+		var dir matrix.Vec2
+		if g.Corner != (matrix.Vec2{}) {
+			// Perpendicular to the diagonal between the two other corners
+			dir = matrix.Vec2{g.Corner.X() * h, g.Corner.Y() * w}.Normal()
+		} else {
+			rad := matrix.Deg2Rad(g.Angle)
+			dir = matrix.Vec2{matrix.Sin(rad), -matrix.Cos(rad)}
+		}
+		length = matrix.Abs(w*dir.X()) + matrix.Abs(h*dir.Y())
+		world := matrix.Vec2{dir.X(), -dir.Y()}
+		start := world.X()*center.X() + world.Y()*center.Y() - length*0.5
+		data[0], data[1], data[2], data[3] = world.X(), world.Y(), start, length
+		kind = clipMaskShaderLinear
+	} else {
+		x, y := g.Center[0].resolve(w), g.Center[1].resolve(h)
+		rx, ry := g.radii(x, y, w, h)
+		p := clipBoxPoint(center, size, x, y)
+		data[0], data[1], data[2], data[3] = p.X(), p.Y(), rx, ry
+		length = rx
+		kind = clipMaskShaderRadial
+	}
+	count := min(len(g.Stops), MaxMaskGradientStops)
+	positions := gradientStopPositions(g.Stops[:count], length)
+	for i := range MaxMaskGradientStops {
+		if i < count {
+			data[4+i] = positions[i]
+			data[8+i] = maskValue(g.Stops[i].Color, mode)
+		} else if count > 0 {
+			data[4+i], data[8+i] = data[4+i-1], data[8+i-1]
+		}
+	}
+	return kind, count, data
+}
+
+func (p *Panel) resolveClip(c *panelClip) uiClip {
+	out := uiClip{}
+	if c.shape.Kind != ClipShapeNone {
+		center, size := p.clipReferenceBox(c.shape.Box)
+		out = c.shape.resolve(center, size)
+	}
+	if c.mask != nil && len(c.mask.Stops) > 0 && c.shape.Kind != ClipShapePolygon {
+		center, size := p.clipReferenceBox(ClipBoxBorder)
+		kind, count, data := c.mask.resolve(center, size, c.maskMode)
+		out.shape[0] = packClipShape(out.kind(), count, kind, c.mask.Repeating)
+		copy(out.data[4:], data[:])
+	}
+	return out
+}
+
+// contains tests a point against the clip shape, masks don't change what
+// can be clicked
+func (c uiClip) contains(point matrix.Vec2) bool {
+	switch c.kind() {
+	case clipShaderEllipse:
+		rect := c.rect()
+		if rect.Z() <= 0 || rect.W() <= 0 {
+			return false
+		}
+		x := (point.X() - rect.X()) / rect.Z()
+		y := (point.Y() - rect.Y()) / rect.W()
+		return x*x+y*y <= 1
+	case clipShaderInset:
+		rect, r := c.rect(), c.radius()
+		qx := matrix.Abs(point.X()-rect.X()) - rect.Z() + r
+		qy := matrix.Abs(point.Y()-rect.Y()) - rect.W() + r
+		outside := matrix.Vec2{max(qx, 0), max(qy, 0)}.Length()
+		return min(max(qx, qy), 0)+outside-r <= 0
+	case clipShaderPolygon:
+		count := c.count()
+		inside := false
+		for i, j := 0, count-1; i < count; j, i = i, i+1 {
+			xi, yi := c.data[i*2], c.data[i*2+1]
+			xj, yj := c.data[j*2], c.data[j*2+1]
+			if (yi > point.Y()) != (yj > point.Y()) &&
+				point.X() < (xj-xi)*(point.Y()-yi)/(yj-yi)+xi {
+				inside = !inside
+			}
+		}
+		return inside
+	}
+	return true
+}
+
+func (s *ShaderData) clip() uiClip {
+	return uiClip{s.ClipShape, s.ClipData}
+}
+
+func (s *ShaderData) setClip(c uiClip) {
+	s.ClipShape, s.ClipData = c.shape, c.data
+}
+
+func setTextClip(s *rendering.TextShaderData, c uiClip) {
+	s.ClipShape, s.ClipData = c.shape, c.data
+}
+
+// updateClip hands the clip path and the mask of the nearest clipped panel,
+// the element itself or one of its parents, to the drawings of the element.
+// Parents must be updated before their children
+func (ui *UI) updateClip() {
+	clip := uiClip{}
+	if !ui.entity.IsRoot() {
+		if parent := FirstOnEntity(ui.entity.Parent); parent != nil {
+			clip = parent.shaderData.clip()
+		}
+	}
+	if !ui.IsType(ElementTypeLabel) {
+		p := ui.ToPanel()
+		if c := p.PanelData().clip; c != nil && c.active() {
+			clip = p.resolveClip(c)
+		}
+	}
+	ui.shaderData.setClip(clip)
+	if ui.IsType(ElementTypeLabel) {
+		ld := ui.ToLabel().LabelData()
+		for i := range ld.runeDrawings {
+			setTextClip(ld.runeDrawings[i].ShaderData.(*rendering.TextShaderData), clip)
+		}
+		for _, layer := range ld.shadowLayers {
+			for _, sd := range layer.shaderData {
+				setTextClip(sd, clip)
+			}
+		}
+	} else if b := ui.ToPanel().PanelData().borderImage; b != nil {
+		for _, sd := range b.shaderData {
+			sd.setClip(clip)
+		}
+	}
+}
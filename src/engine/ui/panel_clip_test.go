@@ -0,0 +1,172 @@
+/******************************************************************************/
+/* panel_clip_test.go                                                         */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"testing"
+
+	"kaijuengine.com/matrix"
+)
+
+func clipTestPercent(v float32) ClipLength { return ClipLength{Value: v, Percent: true} }
+
+func TestClipShapeCircle(t *testing.T) {
+	shape := ClipShape{
+		Kind:   ClipShapeCircle,
+		Center: [2]ClipLength{clipTestPercent(0.5), clipTestPercent(0.5)},
+		Radii:  [2]ClipLength{{Value: ClipRadiusClosestSide}},
+	}
+	// A 100x50 box centered on (10, 20) in world space
+	c := shape.resolve(matrix.Vec2{10, 20}, matrix.Vec2{100, 50})
+	if c.kind() != clipShaderEllipse || c.rect() != (matrix.Vec4{10, 20, 25, 25}) {
+		t.Fatalf("unexpected circle %v %v", c.shape, c.rect())
+	}
+	if !c.contains(matrix.Vec2{10, 44}) || c.contains(matrix.Vec2{40, 20}) {
+		t.Fatal("expected the circle to only contain points within its radius")
+	}
+}
+
+func TestClipShapeEllipseTopLeft(t *testing.T) {
+	shape := ClipShape{
+		Kind:   ClipShapeEllipse,
+		Center: [2]ClipLength{{Value: 20}, {Value: 10}},
+		Radii:  [2]ClipLength{clipTestPercent(0.5), {Value: ClipRadiusFarthestSide}},
+	}
+	c := shape.resolve(matrix.Vec2{0, 0}, matrix.Vec2{100, 50})
+	// 20px from the left and 10px down from the top of the box
+	want := matrix.Vec4{-30, 15, 50, 40}
+	if !matrix.Vec4ApproxTo(c.rect(), want, 0.001) {
+		t.Fatalf("expected %v, got %v", want, c.rect())
+	}
+}
+
+func TestClipShapeInset(t *testing.T) {
+	shape := ClipShape{
+		Kind:   ClipShapeInset,
+		Insets: [4]ClipLength{{Value: 10}, {Value: 20}, {Value: 10}, clipTestPercent(0.1)},
+		Round:  ClipLength{Value: 5},
+	}
+	c := shape.resolve(matrix.Vec2{0, 0}, matrix.Vec2{100, 50})
+	if !matrix.Vec4ApproxTo(c.rect(), matrix.Vec4{-5, 0, 35, 15}, 0.001) || c.radius() != 5 {
+		t.Fatalf("unexpected inset %v %v", c.shape, c.rect())
+	}
+	if !c.contains(matrix.Vec2{0, 0}) || c.contains(matrix.Vec2{-45, 0}) {
+		t.Fatal("expected the inset to only contain points within its insets")
+	}
+	// The rounded corner cuts off the very corner of the inset
+	if c.contains(matrix.Vec2{-39.9, 14.9}) {
+		t.Fatal("expected the rounded corner to be outside of the inset")
+	}
+}
+
+func TestClipShapePolygon(t *testing.T) {
+	// A diamond touching the middle of each side
+	shape := ClipShape{Kind: ClipShapePolygon, Points: [][2]ClipLength{
+		{clipTestPercent(0.5), clipTestPercent(0)},
+		{clipTestPercent(1), clipTestPercent(0.5)},
+		{clipTestPercent(0.5), clipTestPercent(1)},
+		{clipTestPercent(0), clipTestPercent(0.5)},
+	}}
+	c := shape.resolve(matrix.Vec2{0, 0}, matrix.Vec2{100, 100})
+	if c.kind() != clipShaderPolygon || c.count() != 4 {
+		t.Fatalf("unexpected polygon %v", c.shape)
+	}
+	if c.data[0] != 0 || c.data[1] != 50 {
+		t.Fatalf("expected the first point at the top, got (%f, %f)", c.data[0], c.data[1])
+	}
+	if !c.contains(matrix.Vec2{0, 0}) || !c.contains(matrix.Vec2{20, 20}) {
+		t.Fatal("expected the middle of the diamond to be inside")
+	}
+	if c.contains(matrix.Vec2{40, 40}) || c.contains(matrix.Vec2{-49, -49}) {
+		t.Fatal("expected the corners of the box to be outside of the diamond")
+	}
+}
+
+func TestPackClipShape(t *testing.T) {
+	c := uiClip{shape: matrix.Vec2{packClipShape(clipShaderInset, 4, clipMaskShaderRadial, true), 3}}
+	if c.kind() != clipShaderInset || c.count() != 4 || c.maskKind() != clipMaskShaderRadial {
+		t.Fatalf("unexpected unpacked shape %d %d %d", c.kind(), c.count(), c.maskKind())
+	}
+	if int(c.shape.X())&(1<<8) == 0 {
+		t.Fatal("expected the mask to repeat")
+	}
+	c.shape[0] = packClipShape(clipShaderPolygon, MaxClipPolygonPoints, clipMaskShaderNone, false)
+	if c.kind() != clipShaderPolygon || c.count() != MaxClipPolygonPoints || c.maskKind() != clipMaskShaderNone {
+		t.Fatalf("unexpected unpacked polygon %d %d %d", c.kind(), c.count(), c.maskKind())
+	}
+}
+
+func TestClipNoneContainsEverything(t *testing.T) {
+	if !(uiClip{}).contains(matrix.Vec2{1e6, -1e6}) {
+		t.Fatal("expected no clip to contain every point")
+	}
+}
+
+func TestGradientStopPositions(t *testing.T) {
+	stops := []GradientStop{
+		{},
+		{},
+		{Position: ClipLength{Value: 50}, HasPosition: true},
+		{Position: clipTestPercent(0.25), HasPosition: true},
+	}
+	got := gradientStopPositions(stops, 100)
+	// The last stop can't come before the one ahead of it, the second is
+	// spread between the first and the third
+	want := []float32{0, 0.25, 0.5, 0.5}
+	for i := range want {
+		if !matrix.Approx(got[i], want[i]) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+	got = gradientStopPositions([]GradientStop{{}, {}, {}}, 100)
+	if got[0] != 0 || got[1] != 0.5 || got[2] != 1 {
+		t.Fatalf("expected evenly spread stops, got %v", got)
+	}
+}
+
+func TestGradientResolveLinear(t *testing.T) {
+	g := Gradient{Kind: GradientLinear, Angle: 90, Stops: []GradientStop{
+		{Color: matrix.Color{0, 0, 0, 1}},
+		{Color: matrix.Color{0, 0, 0, 0}},
+	}}
+	kind, count, data := g.resolve(matrix.Vec2{10, 0}, matrix.Vec2{100, 50}, MaskModeAlpha)
+	if kind != clipMaskShaderLinear {
+		t.Fatalf("expected a linear mask, got %d", kind)
+	}
+	// Going right, starting at the left edge of the box
+	if !matrix.Vec4ApproxTo(matrix.Vec4{data[0], data[1], data[2], data[3]},
+		matrix.Vec4{1, 0, -40, 100}, 0.001) {
+		t.Fatalf("unexpected gradient line %v", data[:4])
+	}
+	if data[8] != 1 || data[9] != 0 || count != 2 {
+		t.Fatalf("unexpected stop values %v (%d stops)", data[8:], count)
+	}
+	// "to bottom" goes down the box, which is -y in the world
+	g.Angle = 180
+	_, _, data = g.resolve(matrix.Vec2{0, 0}, matrix.Vec2{100, 50}, MaskModeAlpha)
+	if !matrix.Vec4ApproxTo(matrix.Vec4{data[0], data[1], data[2], data[3]},
+		matrix.Vec4{0, -1, -25, 50}, 0.001) {
+		t.Fatalf("unexpected downward gradient line %v", data[:4])
+	}
+}
+
+func TestGradientResolveRadialLuminance(t *testing.T) {
+	g := Gradient{
+		Kind:   GradientRadial,
+		Circle: true,
+		Center: [2]ClipLength{clipTestPercent(0.5), clipTestPercent(0.5)},
+		Extent: GradientExtentClosestSide,
+		Stops:  []GradientStop{{Color: matrix.ColorWhite()}, {Color: matrix.Color{0, 0, 0, 1}}},
+	}
+	kind, _, data := g.resolve(matrix.Vec2{0, 0}, matrix.Vec2{100, 50}, MaskModeLuminance)
+	if kind != clipMaskShaderRadial || data[2] != 25 || data[3] != 25 {
+		t.Fatalf("unexpected radial mask %d %v", kind, data[:4])
+	}
+	if !matrix.Approx(data[8], 1) || data[9] != 0 {
+		t.Fatalf("expected the luminance of the stops, got %v", data[8:10])
+	}
+}
@@ -0,0 +1,730 @@
+/******************************************************************************/
+/* panel_scroll.go                                                            */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"math"
+
+	"kaijuengine.com/matrix"
+)
+
+type ScrollSnapAxis = int
+
+const (
+	ScrollSnapAxisNone ScrollSnapAxis = iota
+	ScrollSnapAxisX
+	ScrollSnapAxisY
+	ScrollSnapAxisBoth
+)
+
+type ScrollSnapStrictness = int
+
+const (
+	ScrollSnapProximity ScrollSnapStrictness = iota
+	ScrollSnapMandatory
+)
+
+type ScrollSnapAlign = int
+
+const (
+	ScrollSnapAlignNone ScrollSnapAlign = iota
+	ScrollSnapAlignStart
+	ScrollSnapAlignCenter
+	ScrollSnapAlignEnd
+)
+
+type OverscrollBehavior = int
+
+const (
+	// OverscrollBehaviorAuto passes the scroll that is past the edge on to
+	// the scroll container that holds this one
+	OverscrollBehaviorAuto OverscrollBehavior = iota
+	// OverscrollBehaviorContain keeps the scroll within the container, it
+	// still stretches past the edge while it is being dragged
+	OverscrollBehaviorContain
+	// OverscrollBehaviorNone keeps the scroll within the container and
+	// doesn't stretch past the edge
+	OverscrollBehaviorNone
+)
+
+const (
+	// scrollSmoothSpeed is how quickly a smooth scroll closes in on its
+	// target, the remaining distance shrinks by e^-speed each second
+	scrollSmoothSpeed = 12
+	// scrollFriction slows a flung scroll down in the same way
+	scrollFriction    = 4
+	scrollMinVelocity = 20
+	// scrollSnapIdle is how long the wheel is still before snapping
+	scrollSnapIdle = 0.15
+	// scrollSnapProximity is the part of the viewport a proximity snap point
+	// is found within
+	scrollSnapProximity = 0.3
+	// scrollOverscrollResistance is how much a drag past the edge moves
+	scrollOverscrollResistance = 0.35
+	scrollSpringSpeed          = 14
+	scrollSettled              = 0.5
+)
+
+// panelScroll holds the scroll styles of a panel, for the panel as a scroll
+// container and as an item within one, and the motion of its scroll
+type panelScroll struct {
+	smooth         bool
+	padding        matrix.Vec4
+	snapAxis       ScrollSnapAxis
+	snapStrictness ScrollSnapStrictness
+	overscroll     [2]OverscrollBehavior
+	thumbColor     matrix.Color
+	trackColor     matrix.Color
+	customColors   bool
+	trackX, trackY *Panel
+
+	margin         matrix.Vec4
+	snapAlign      [2]ScrollSnapAlign
+	snapStopAlways bool
+
+	target      matrix.Vec2
+	animating   bool
+	velocity    matrix.Vec2
+	dragging    bool
+	lastDrag    matrix.Vec2
+	overshoot   matrix.Vec2
+	snapPending bool
+	idle        float64
+}
+
+func (p *Panel) scrollState() *panelScroll {
+	pd := p.PanelData()
+	if pd.scrollStyle == nil {
+		pd.scrollStyle = &panelScroll{}
+	}
+	return pd.scrollStyle
+}
+
+func (p *Panel) clearScrollStyles() {
+	s := p.PanelData().scrollStyle
+	if s == nil {
+		return
+	}
+	s.smooth = false
+	s.padding = matrix.Vec4Zero()
+	s.snapAxis = ScrollSnapAxisNone
+	s.snapStrictness = ScrollSnapProximity
+	s.overscroll = [2]OverscrollBehavior{}
+	s.margin = matrix.Vec4Zero()
+	s.snapAlign = [2]ScrollSnapAlign{}
+	s.snapStopAlways = false
+}
+
+// SetScrollBehaviorSmooth makes the scrolls of the wheel and of ScrollTo
+// glide to their position rather than jump to it
+func (p *Panel) SetScrollBehaviorSmooth(smooth bool) { p.scrollState().smooth = smooth }
+
+// SetScrollPadding insets the area that ScrollIntoView and scroll snapping
+// line items up with (left, top, right, bottom)
+func (p *Panel) SetScrollPadding(padding matrix.Vec4) { p.scrollState().padding = padding }
+
+func (p *Panel) ScrollPadding() matrix.Vec4 {
+	if s := p.PanelData().scrollStyle; s != nil {
+		return s.padding
+	}
+	return matrix.Vec4Zero()
+}
+
+// SetScrollMargin outsets the area of this panel that is lined up when it is
+// scrolled into view or snapped to (left, top, right, bottom)
+func (p *Panel) SetScrollMargin(margin matrix.Vec4) { p.scrollState().margin = margin }
+
+func (p *Panel) ScrollMargin() matrix.Vec4 {
+	if s := p.PanelData().scrollStyle; s != nil {
+		return s.margin
+	}
+	return matrix.Vec4Zero()
+}
+
+// SetScrollSnapType sets the axes that the scroll comes to rest at the snap
+// points of its items on. Mandatory always rests on a snap point, proximity
+// only when one is close
+func (p *Panel) SetScrollSnapType(axis ScrollSnapAxis, strictness ScrollSnapStrictness) {
+	s := p.scrollState()
+	s.snapAxis = axis
+	s.snapStrictness = strictness
+}
+
+// SetScrollSnapAlign sets the part of this panel that lines up with the
+// scroll container when it snaps on the x and y axes
+func (p *Panel) SetScrollSnapAlign(x, y ScrollSnapAlign) {
+	p.scrollState().snapAlign = [2]ScrollSnapAlign{x, y}
+}
+
+// SetScrollSnapStop makes a fling of the scroll container stop at this panel
+// rather than pass it by
+func (p *Panel) SetScrollSnapStop(always bool) { p.scrollState().snapStopAlways = always }
+
+// SetOverscrollBehavior sets what happens to a scroll that goes past the
+// edges of the panel on the x and y axes
+func (p *Panel) SetOverscrollBehavior(x, y OverscrollBehavior) {
+	p.scrollState().overscroll = [2]OverscrollBehavior{x, y}
+}
+
+func (p *Panel) OverscrollBehavior() (x, y OverscrollBehavior) {
+	if s := p.PanelData().scrollStyle; s != nil {
+		return s.overscroll[0], s.overscroll[1]
+	}
+	return OverscrollBehaviorAuto, OverscrollBehaviorAuto
+}
+
+// SetScrollbarColor colors the thumb and track of the scroll bars, a track
+// that is transparent isn't drawn
+func (p *Panel) SetScrollbarColor(thumb, track matrix.Color) {
+	s := p.scrollState()
+	s.thumbColor, s.trackColor, s.customColors = thumb, track, true
+	p.applyScrollbarColors()
+}
+
+// ResetScrollbarColor goes back to the default scroll bar colors
+func (p *Panel) ResetScrollbarColor() {
+	s := p.PanelData().scrollStyle
+	if s == nil || !s.customColors {
+		return
+	}
+	s.customColors = false
+	p.applyScrollbarColors()
+}
+
+func (p *Panel) scrollbarThumbColor() matrix.Color {
+	if s := p.PanelData().scrollStyle; s != nil && s.customColors {
+		return s.thumbColor
+	}
+	return matrix.ColorGray()
+}
+
+func (p *Panel) scrollbarHoverColor() matrix.Color {
+	if s := p.PanelData().scrollStyle; s != nil && s.customColors {
+		return matrix.ColorMix(s.thumbColor, matrix.ColorWhite(), 0.25)
+	}
+	return matrix.NewColor(0.575, 0.575, 0.575, 1.0)
+}
+
+func (p *Panel) applyScrollbarColors() {
+	pd := p.PanelData()
+	for _, sb := range [...]*Panel{pd.scrollBarX, pd.scrollBarY} {
+		if sb != nil {
+			sb.SetColor(p.scrollbarThumbColor())
+		}
+	}
+	s := pd.scrollStyle
+	if s == nil {
+		return
+	}
+	track := matrix.ColorTransparent()
+	if s.customColors {
+		track = s.trackColor
+	}
+	if track.A() > 0 {
+		if s.trackX == nil && pd.scrollBarX != nil {
+			s.trackX = p.createScrollTrack()
+		}
+		if s.trackY == nil && pd.scrollBarY != nil {
+			s.trackY = p.createScrollTrack()
+		}
+	}
+	for _, t := range [...]*Panel{s.trackX, s.trackY} {
+		if t != nil {
+			t.SetColor(track)
+		}
+	}
+}
+
+func (p *Panel) createScrollTrack() *Panel {
+	t := p.man.Value().Add().ToPanel()
+	t.Init(nil, ElementTypePanel)
+	t.DontFitContent()
+	t.AllowClickThrough()
+	t.layout.SetPositioning(PositioningAbsolute)
+	t.layout.SetZ(9)
+	p.AddChild(t.Base())
+	return t
+}
+
+// placeScrollTrack sizes the track behind a scroll bar, or hides it
+func placeScrollTrack(track *Panel, show bool, x, y, w, h float32) {
+	if track == nil {
+		return
+	}
+	if !show || track.Color().A() <= 0 {
+		track.Base().Hide()
+		return
+	}
+	track.layout.SetOffset(x, y)
+	track.layout.Scale(w, h)
+	track.Base().Show()
+}
+
+// scrollPosition is the scroll with both axes going positive as the content
+// moves towards its end
+func (p *Panel) scrollPosition() matrix.Vec2 {
+	pd := p.PanelData()
+	return matrix.Vec2{pd.scroll.X(), -pd.scroll.Y()}
+}
+
+func (p *Panel) setScrollPosition(pos matrix.Vec2) {
+	pd := p.PanelData()
+	next := matrix.Vec2{
+		matrix.Clamp(pos.X(), 0, pd.maxScroll.X()),
+		-matrix.Clamp(pos.Y(), 0, pd.maxScroll.Y()),
+	}
+	if !matrix.Vec2Approx(next, pd.scroll) {
+		pd.scroll = next
+		pd.flags.setIsScrolling()
+		p.Base().SetDirty(DirtyTypeLayout)
+	}
+}
+
+func (p *Panel) scrollsOn(axis int) bool {
+	dir := p.PanelData().scrollDirection
+	if axis == 0 {
+		return dir&PanelScrollDirectionHorizontal != 0
+	}
+	return dir&PanelScrollDirectionVertical != 0
+}
+
+// ScrollTo scrolls to the position, where y goes down the content. The
+// scroll glides there if the scroll behavior is smooth
+func (p *Panel) ScrollTo(x, y float32) {
+	s := p.scrollState()
+	s.velocity = matrix.Vec2Zero()
+	if s.smooth {
+		s.target = p.clampScroll(matrix.Vec2{x, y})
+		s.animating = true
+		p.Base().SetDirty(DirtyTypeLayout)
+		return
+	}
+	s.animating = false
+	p.SetScrollX(x)
+	p.SetScrollY(y)
+}
+
+// ScrollBy scrolls by the amount, see ScrollTo
+func (p *Panel) ScrollBy(x, y float32) {
+	pos := p.scrollPosition()
+	if s := p.PanelData().scrollStyle; s != nil && s.animating {
+		pos = s.target
+	}
+	p.ScrollTo(pos.X()+x, pos.Y()+y)
+}
+
+func (p *Panel) clampScroll(pos matrix.Vec2) matrix.Vec2 {
+	m := p.PanelData().maxScroll
+	return matrix.Vec2{matrix.Clamp(pos.X(), 0, m.X()), matrix.Clamp(pos.Y(), 0, m.Y())}
+}
+
+// contentRect is the area of the descendant within the scrolled content of
+// the panel (left, top, right, bottom) with y going down
+func (p *Panel) contentRect(child *UI) matrix.Vec4 {
+	pp, _, ps := p.entity.Transform.WorldTransform()
+	cp, _, cs := child.entity.Transform.WorldTransform()
+	pos := p.scrollPosition()
+	if s := p.PanelData().scrollStyle; s != nil {
+		pos.AddAssign(s.overshoot)
+	}
+	left := (cp.X() - cs.X()*0.5) - (pp.X() - ps.X()*0.5) + pos.X()
+	top := (pp.Y() + ps.Y()*0.5) - (cp.Y() + cs.Y()*0.5) + pos.Y()
+	return matrix.Vec4{left, top, left + cs.X(), top + cs.Y()}
+}
+
+func uiScrollMargin(elm *UI) matrix.Vec4 {
+	if elm.IsType(ElementTypeLabel) || !elm.IsValid() {
+		return matrix.Vec4Zero()
+	}
+	return elm.ToPanel().ScrollMargin()
+}
+
+// scrollIntoViewPosition returns the least scroll on an axis that shows the
+// item within the padded viewport, the start of an item that is larger than
+// the viewport is shown
+func scrollIntoViewPosition(pos, itemStart, itemEnd, padStart, padEnd, viewport float32) float32 {
+	if itemStart-padStart < pos || itemEnd-itemStart > viewport-padStart-padEnd {
+		return itemStart - padStart
+	}
+	if itemEnd+padEnd > pos+viewport {
+		return itemEnd + padEnd - viewport
+	}
+	return pos
+}
+
+// ScrollIntoView scrolls the least amount needed to show the descendant,
+// keeping the scroll padding of this panel and the scroll margin of the
+// descendant clear
+func (p *Panel) ScrollIntoView(child *UI) {
+	rect := p.contentRect(child)
+	margin := uiScrollMargin(child)
+	pad := p.ScrollPadding()
+	size := p.layout.PixelSize()
+	pos := p.scrollPosition()
+	if s := p.PanelData().scrollStyle; s != nil && s.animating {
+		pos = s.target
+	}
+	next := pos
+	for axis := range 2 {
+		if !p.scrollsOn(axis) {
+			continue
+		}
+		next[axis] = scrollIntoViewPosition(pos[axis],
+			rect[axis]-margin[axis], rect[axis+2]+margin[axis+2],
+			pad[axis], pad[axis+2], size[axis])
+	}
+	if !matrix.Vec2Approx(next, pos) {
+		p.ScrollTo(next.X(), next.Y())
+	}
+}
+
+// ScrollIntoView scrolls every scroll container (and virtual list) that the
+// element is in so that it is shown
+func ScrollIntoView(elm *UI) {
+	for e := elm.entity.Parent; e != nil; e = e.Parent {
+		p := FirstOnEntity(e)
+		if p == nil || p.IsType(ElementTypeLabel) {
+			continue
+		}
+		if p.IsType(ElementTypeVirtualList) {
+			p.ToVirtualList().ScrollIntoView(elm)
+		} else if p.ToPanel().ScrollDirection() != PanelScrollDirectionNone {
+			p.ToPanel().ScrollIntoView(elm)
+		}
+	}
+}
+
+// scrollParent is the closest scroll container that holds the panel
+func (p *Panel) scrollParent() *Panel {
+	for e := p.entity.Parent; e != nil; e = e.Parent {
+		u := FirstOnEntity(e)
+		if u != nil && !u.IsType(ElementTypeLabel) && u.ToPanel().ScrollDirection() != PanelScrollDirectionNone {
+			return u.ToPanel()
+		}
+	}
+	return nil
+}
+
+// consumeScroll scrolls by the delta and returns the part of it that went
+// past the edges or is on an axis the panel doesn't scroll
+func (p *Panel) consumeScroll(delta matrix.Vec2) matrix.Vec2 {
+	pos := p.scrollPosition()
+	limit := p.PanelData().maxScroll
+	rest := delta
+	for axis := range 2 {
+		if !p.scrollsOn(axis) {
+			continue
+		}
+		next := pos[axis] + delta[axis]
+		pos[axis] = matrix.Clamp(next, 0, limit[axis])
+		rest[axis] = next - pos[axis]
+	}
+	p.setScrollPosition(pos)
+	return rest
+}
+
+// chainedScroll returns the part of the rest of a scroll that the overscroll
+// behavior lets through to the scroll container holding this one
+func (p *Panel) chainedScroll(rest matrix.Vec2) matrix.Vec2 {
+	x, y := p.OverscrollBehavior()
+	for axis, behavior := range [...]OverscrollBehavior{x, y} {
+		if p.scrollsOn(axis) && behavior != OverscrollBehaviorAuto {
+			rest[axis] = 0
+		}
+	}
+	return rest
+}
+
+func (p *Panel) wheelScroll(delta matrix.Vec2) {
+	s := p.scrollState()
+	var rest matrix.Vec2
+	if s.smooth {
+		base := p.scrollPosition()
+		if s.animating {
+			base = s.target
+		}
+		want := base.Add(delta)
+		s.target = p.clampScroll(want)
+		s.animating = true
+		rest = want.Subtract(s.target)
+		for axis := range 2 {
+			if !p.scrollsOn(axis) {
+				s.target[axis] = base[axis]
+			}
+		}
+		p.Base().SetDirty(DirtyTypeLayout)
+	} else {
+		rest = p.consumeScroll(delta)
+	}
+	s.velocity = matrix.Vec2Zero()
+	if s.snapAxis != ScrollSnapAxisNone {
+		s.snapPending, s.idle = true, 0
+	}
+	rest = p.chainedScroll(rest)
+	if rest.X() != 0 || rest.Y() != 0 {
+		if parent := p.scrollParent(); parent != nil {
+			parent.wheelScroll(rest)
+		}
+	}
+}
+
+// dragScroll scrolls by the drag, the part past the edges goes on to the
+// scroll container holding this one or stretches the content past the edge
+func (p *Panel) dragScroll(delta matrix.Vec2) {
+	s := p.scrollState()
+	rest := p.consumeScroll(delta)
+	chained := p.chainedScroll(rest)
+	parent := p.scrollParent()
+	x, y := p.OverscrollBehavior()
+	for axis, behavior := range [...]OverscrollBehavior{x, y} {
+		if !p.scrollsOn(axis) || rest[axis] == 0 {
+			continue
+		}
+		if behavior == OverscrollBehaviorAuto && parent != nil {
+			continue
+		}
+		chained[axis] = 0
+		if behavior != OverscrollBehaviorNone {
+			s.overshoot[axis] += rest[axis] * scrollOverscrollResistance
+			p.Base().SetDirty(DirtyTypeLayout)
+		}
+	}
+	// Releasing the stretch back towards the content
+	for axis := range 2 {
+		if s.overshoot[axis] != 0 && rest[axis] == 0 && delta[axis] != 0 {
+			s.overshoot[axis] = 0
+			p.Base().SetDirty(DirtyTypeLayout)
+		}
+	}
+	if parent != nil && (chained.X() != 0 || chained.Y() != 0) {
+		// The parent panel is updated on another thread
+		p.Base().Host().RunOnMainThread(func() { parent.dragScroll(chained) })
+	}
+}
+
+// isInnermostScrollerAt returns false if a scroll container within this
+// panel is under the point, that panel takes the drag instead
+func (p *Panel) isInnermostScrollerAt(point matrix.Vec2) bool {
+	var check func(e *UI) bool
+	check = func(e *UI) bool {
+		for _, c := range e.entity.Children {
+			u := FirstOnEntity(c)
+			if u == nil || !u.IsActive() || u.IsType(ElementTypeLabel) {
+				continue
+			}
+			if u.ToPanel().ScrollDirection() != PanelScrollDirectionNone &&
+				u.ContainsPoint(point) {
+				return false
+			}
+			if !check(u) {
+				return false
+			}
+		}
+		return true
+	}
+	return check(p.Base())
+}
+
+type scrollSnapPoint struct {
+	pos    float32
+	always bool
+}
+
+// scrollSnapPosition is the scroll on an axis that lines the item up with
+// the padded viewport
+func scrollSnapPosition(align ScrollSnapAlign, itemStart, itemEnd, padStart, padEnd, viewport, maxScroll float32) float32 {
+	var pos float32
+	switch align {
+	case ScrollSnapAlignStart:
+		pos = itemStart - padStart
+	case ScrollSnapAlignEnd:
+		pos = itemEnd + padEnd - viewport
+	case ScrollSnapAlignCenter:
+		snapport := viewport - padStart - padEnd
+		pos = (itemStart+itemEnd)*0.5 - padStart - snapport*0.5
+	}
+	return matrix.Clamp(pos, 0, maxScroll)
+}
+
+// scrollSnapTarget picks the snap point for a scroll that starts at from and
+// would come to rest at to. A snap point that stops the scroll always wins
+// if the scroll would pass it
+func scrollSnapTarget(points []scrollSnapPoint, from, to, proximity float32, mandatory bool) (float32, bool) {
+	lo, hi := min(from, to), max(from, to)
+	best, bestDist := float32(0), float32(math.MaxFloat32)
+	for _, pt := range points {
+		if pt.always && pt.pos > lo+scrollSettled && pt.pos < hi-scrollSettled {
+			if d := matrix.Abs(pt.pos - from); d < bestDist {
+				best, bestDist = pt.pos, d
+			}
+		}
+	}
+	if bestDist < math.MaxFloat32 {
+		return best, true
+	}
+	for _, pt := range points {
+		if d := matrix.Abs(pt.pos - to); d < bestDist {
+			best, bestDist = pt.pos, d
+		}
+	}
+	if bestDist == math.MaxFloat32 || (!mandatory && bestDist > proximity) {
+		return to, false
+	}
+	return best, true
+}
+
+func (s *panelScroll) snapsOn(axis int) bool {
+	if axis == 0 {
+		return s.snapAxis == ScrollSnapAxisX || s.snapAxis == ScrollSnapAxisBoth
+	}
+	return s.snapAxis == ScrollSnapAxisY || s.snapAxis == ScrollSnapAxisBoth
+}
+
+// snapPoints collects the snap positions of the items within the panel on
+// the axis, items within nested scroll containers snap to those instead
+func (p *Panel) snapPoints(axis int) []scrollSnapPoint {
+	pad := p.ScrollPadding()
+	size := p.layout.PixelSize()
+	limit := p.PanelData().maxScroll
+	points := make([]scrollSnapPoint, 0)
+	var collect func(e *UI)
+	collect = func(e *UI) {
+		for _, c := range e.entity.Children {
+			u := FirstOnEntity(c)
+			if u == nil || !u.IsActive() || u.IsType(ElementTypeLabel) {
+				continue
+			}
+			child := u.ToPanel()
+			if s := child.PanelData().scrollStyle; s != nil && s.snapAlign[axis] != ScrollSnapAlignNone {
+				rect := p.contentRect(u)
+				points = append(points, scrollSnapPoint{
+					pos: scrollSnapPosition(s.snapAlign[axis],
+						rect[axis]-s.margin[axis], rect[axis+2]+s.margin[axis+2],
+						pad[axis], pad[axis+2], size[axis], limit[axis]),
+					always: s.snapStopAlways,
+				})
+			}
+			if child.ScrollDirection() == PanelScrollDirectionNone {
+				collect(u)
+			}
+		}
+	}
+	collect(p.Base())
+	return points
+}
+
+// snapFrom moves the scroll to the snap point for a scroll from the start
+// that would come to rest at the end
+func (p *Panel) snapFrom(from, to matrix.Vec2) bool {
+	s := p.scrollState()
+	target, snapped := to, false
+	size := p.layout.PixelSize()
+	for axis := range 2 {
+		if !s.snapsOn(axis) || !p.scrollsOn(axis) {
+			continue
+		}
+		if pos, ok := scrollSnapTarget(p.snapPoints(axis), from[axis], to[axis],
+			size[axis]*scrollSnapProximity, s.snapStrictness == ScrollSnapMandatory); ok {
+			target[axis], snapped = pos, true
+		}
+	}
+	if snapped {
+		s.target = p.clampScroll(target)
+		s.animating = true
+		s.velocity = matrix.Vec2Zero()
+	}
+	return snapped
+}
+
+func (p *Panel) updateScroll(deltaTime float64) {
+	pd := p.PanelData()
+	if pd.scrollDirection == PanelScrollDirectionNone {
+		return
+	}
+	s := pd.scrollStyle
+	base := p.Base()
+	host := base.Host()
+	cursor := &host.Window.Cursor
+	if s == nil || !s.dragging {
+		canDrag := pd.flags.allowDragScroll() || host.Window.Touch.Held()
+		if canDrag && p.flags.isDown() && p.flags.drag() && p.isInnermostScrollerAt(p.downPos) {
+			s = p.scrollState()
+			s.dragging, s.animating, s.snapPending = true, false, false
+			s.lastDrag = p.downPos
+			s.velocity = matrix.Vec2Zero()
+			pd.flags.setDragging()
+		}
+	}
+	if s == nil {
+		return
+	}
+	dt := float32(deltaTime)
+	if s.dragging {
+		if cursor.Held() {
+			pos := base.cursorPos(cursor)
+			moved := pos.Subtract(s.lastDrag)
+			s.lastDrag = pos
+			delta := matrix.Vec2{-moved.X(), moved.Y()}
+			p.dragScroll(delta)
+			if dt > 0 {
+				s.velocity = matrix.Vec2Lerp(s.velocity, delta.Scale(1/dt), 0.5)
+			}
+			return
+		}
+		s.dragging = false
+		pd.flags.resetDragging()
+		if s.snapAxis != ScrollSnapAxisNone {
+			from := p.scrollPosition()
+			p.snapFrom(from, from.Add(s.velocity.Scale(1/scrollFriction)))
+		}
+	}
+	if s.velocity.X() != 0 || s.velocity.Y() != 0 {
+		rest := p.consumeScroll(s.velocity.Scale(dt))
+		decay := float32(math.Exp(-scrollFriction * deltaTime))
+		s.velocity.ScaleAssign(decay)
+		for axis := range 2 {
+			if rest[axis] != 0 || matrix.Abs(s.velocity[axis]) < scrollMinVelocity {
+				s.velocity[axis] = 0
+			}
+		}
+	}
+	if s.animating {
+		pos := p.scrollPosition()
+		step := 1 - float32(math.Exp(-scrollSmoothSpeed*deltaTime))
+		next := matrix.Vec2Lerp(pos, s.target, step)
+		if next.Distance(s.target) < scrollSettled {
+			next, s.animating = s.target, false
+		}
+		p.setScrollPosition(next)
+	}
+	if s.overshoot.X() != 0 || s.overshoot.Y() != 0 {
+		s.overshoot.ScaleAssign(float32(math.Exp(-scrollSpringSpeed * deltaTime)))
+		if s.overshoot.Length() < scrollSettled {
+			s.overshoot = matrix.Vec2Zero()
+		}
+		base.SetDirty(DirtyTypeLayout)
+	}
+	if s.snapPending {
+		s.idle += deltaTime
+		if s.idle >= scrollSnapIdle {
+			s.snapPending = false
+			pos := p.scrollPosition()
+			if s.animating {
+				pos = s.target
+			}
+			p.snapFrom(pos, pos)
+		}
+	}
+}
+
+// scrollOvershoot is how far the content is stretched past its edges
+func (p *Panel) scrollOvershoot() matrix.Vec2 {
+	if s := p.PanelData().scrollStyle; s != nil {
+		return s.overshoot
+	}
+	return matrix.Vec2Zero()
+}
@@ -0,0 +1,73 @@
+/******************************************************************************/
+/* panel_scroll_test.go                                                       */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"testing"
+
+	"kaijuengine.com/matrix"
+)
+
+func TestScrollIntoViewPosition(t *testing.T) {
+	tests := []struct {
+		name                     string
+		pos, start, end, padS    float32
+		padE, viewport, expected float32
+	}{
+		{"already shown", 100, 150, 200, 0, 0, 200, 100},
+		{"above", 100, 50, 80, 0, 0, 200, 50},
+		{"below", 100, 290, 340, 0, 0, 200, 140},
+		{"padding above", 100, 110, 150, 20, 0, 200, 90},
+		{"padding below", 100, 260, 280, 0, 30, 200, 110},
+		{"larger than viewport", 0, 300, 600, 10, 10, 200, 290},
+	}
+	for _, test := range tests {
+		got := scrollIntoViewPosition(test.pos, test.start, test.end, test.padS, test.padE, test.viewport)
+		if !matrix.Approx(got, test.expected) {
+			t.Errorf("%s: expected %f but got %f", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestScrollSnapPosition(t *testing.T) {
+	// A 100 pixel item at 300 in a 200 pixel viewport with 1000 to scroll
+	if got := scrollSnapPosition(ScrollSnapAlignStart, 300, 400, 20, 0, 200, 1000); !matrix.Approx(got, 280) {
+		t.Errorf("start: expected 280 but got %f", got)
+	}
+	if got := scrollSnapPosition(ScrollSnapAlignEnd, 300, 400, 0, 20, 200, 1000); !matrix.Approx(got, 220) {
+		t.Errorf("end: expected 220 but got %f", got)
+	}
+	if got := scrollSnapPosition(ScrollSnapAlignCenter, 300, 400, 0, 0, 200, 1000); !matrix.Approx(got, 250) {
+		t.Errorf("center: expected 250 but got %f", got)
+	}
+	if got := scrollSnapPosition(ScrollSnapAlignStart, 10, 110, 20, 0, 200, 1000); !matrix.Approx(got, 0) {
+		t.Errorf("expected the snap position to be clamped to the scroll range, got %f", got)
+	}
+}
+
+func TestScrollSnapTarget(t *testing.T) {
+	points := []scrollSnapPoint{{pos: 0}, {pos: 200}, {pos: 400}, {pos: 600}}
+	if got, ok := scrollSnapTarget(points, 0, 260, 60, true); !ok || !matrix.Approx(got, 200) {
+		t.Errorf("mandatory: expected 200 but got %f (%v)", got, ok)
+	}
+	if _, ok := scrollSnapTarget(points, 0, 300, 60, false); ok {
+		t.Error("proximity: expected no snap when the points are far away")
+	}
+	if got, ok := scrollSnapTarget(points, 0, 390, 60, false); !ok || !matrix.Approx(got, 400) {
+		t.Errorf("proximity: expected 400 but got %f (%v)", got, ok)
+	}
+	points[1].always = true
+	if got, _ := scrollSnapTarget(points, 0, 590, 60, true); !matrix.Approx(got, 200) {
+		t.Errorf("expected a fling to stop at the snap-stop point, got %f", got)
+	}
+	if got, _ := scrollSnapTarget(points, 600, 10, 60, true); !matrix.Approx(got, 200) {
+		t.Errorf("expected a fling back to stop at the snap-stop point, got %f", got)
+	}
+	if got, _ := scrollSnapTarget(points, 200, 390, 60, true); !matrix.Approx(got, 400) {
+		t.Errorf("expected to leave the snap-stop point it starts on, got %f", got)
+	}
+}
@@ -0,0 +1,474 @@
+/******************************************************************************/
+/* panel_transform.go                                                         */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering"
+)
+
+// TransformLength is a transform length in pixels, or a fraction of the size
+// of the panel when Percent is set
+type TransformLength struct {
+	Value   float32
+	Percent bool
+}
+
+func (l TransformLength) resolve(size float32) float32 {
+	if l.Percent {
+		return l.Value * size
+	}
+	return l.Value
+}
+
+type TransformOpKind = int
+
+const (
+	TransformOpTranslate TransformOpKind = iota
+	TransformOpScale
+	TransformOpRotate
+	TransformOpSkew
+	TransformOpMatrix
+	TransformOpPerspective
+)
+
+// TransformOp is a single transform function. Transforms are in the
+// coordinates of CSS, x goes right, y goes down and z goes toward the viewer
+type TransformOp struct {
+	Kind TransformOpKind
+	// Values are the x, y and z of a translate or a scale, the axis and the
+	// angle (in degrees) of a rotate, the x and y angles (in degrees) of a
+	// skew or the distance of a perspective
+	Values matrix.Vec4
+	// Percent marks the x and y of a translate that are fractions of the size
+	Percent [2]bool
+	Matrix  matrix.Mat4
+}
+
+func TranslateOp(x, y TransformLength, z float32) TransformOp {
+	return TransformOp{
+		Kind:    TransformOpTranslate,
+		Values:  matrix.Vec4{x.Value, y.Value, z, 0},
+		Percent: [2]bool{x.Percent, y.Percent},
+	}
+}
+
+func ScaleOp(x, y, z float32) TransformOp {
+	return TransformOp{Kind: TransformOpScale, Values: matrix.Vec4{x, y, z, 0}}
+}
+
+func RotateOp(axis matrix.Vec3, degrees float32) TransformOp {
+	return TransformOp{Kind: TransformOpRotate,
+		Values: matrix.Vec4{axis.X(), axis.Y(), axis.Z(), degrees}}
+}
+
+func SkewOp(x, y float32) TransformOp {
+	return TransformOp{Kind: TransformOpSkew, Values: matrix.Vec4{x, y, 0, 0}}
+}
+
+// MatrixOp takes the 16 values in the order of matrix3d (column by column)
+func MatrixOp(m matrix.Mat4) TransformOp {
+	return TransformOp{Kind: TransformOpMatrix, Matrix: m}
+}
+
+func PerspectiveOp(distance float32) TransformOp {
+	return TransformOp{Kind: TransformOpPerspective, Values: matrix.Vec4{distance, 0, 0, 0}}
+}
+
+// mat builds the matrix of the function for points that are multiplied on
+// the left, like the rest of the engine
+func (op TransformOp) mat(size matrix.Vec2) matrix.Mat4 {
+	m := matrix.Mat4Identity()
+	v := op.Values
+	switch op.Kind {
+	case TransformOpTranslate:
+		m[12] = TransformLength{v.X(), op.Percent[0]}.resolve(size.X())
+		m[13] = TransformLength{v.Y(), op.Percent[1]}.resolve(size.Y())
+		m[14] = v.Z()
+	case TransformOpScale:
+		m[0], m[5], m[10] = v.X(), v.Y(), v.Z()
+	case TransformOpRotate:
+		axis := matrix.Vec3{v.X(), v.Y(), v.Z()}
+		if axis.Length() == 0 {
+			break
+		}
+		axis.Normalize()
+		x, y, z := axis.X(), axis.Y(), axis.Z()
+		half := matrix.Deg2Rad(v.W()) * 0.5
+		sc := matrix.Sin(half) * matrix.Cos(half)
+		sq := matrix.Sin(half) * matrix.Sin(half)
+		m[0] = 1 - 2*(y*y+z*z)*sq
+		m[1] = 2 * (x*y*sq + z*sc)
+		m[2] = 2 * (x*z*sq - y*sc)
+		m[4] = 2 * (x*y*sq - z*sc)
+		m[5] = 1 - 2*(x*x+z*z)*sq
+		m[6] = 2 * (y*z*sq + x*sc)
+		m[8] = 2 * (x*z*sq + y*sc)
+		m[9] = 2 * (y*z*sq - x*sc)
+		m[10] = 1 - 2*(x*x+y*y)*sq
+	case TransformOpSkew:
+		m[4] = matrix.Tan(matrix.Deg2Rad(v.X()))
+		m[1] = matrix.Tan(matrix.Deg2Rad(v.Y()))
+	case TransformOpMatrix:
+		m = op.Matrix
+	case TransformOpPerspective:
+		if v.X() > 0 {
+			m[11] = -1 / v.X()
+		}
+	}
+	return m
+}
+
+func (op TransformOp) isTranslate2D() bool {
+	return op.Kind == TransformOpTranslate && op.Values.Z() == 0
+}
+
+// composeTransformOps multiplies the functions in the order they are
+// written, so the last one is applied to the points first
+func composeTransformOps(ops []TransformOp, size matrix.Vec2) matrix.Mat4 {
+	m := matrix.Mat4Identity()
+	for i := range ops {
+		m = matrix.Mat4Multiply(ops[i].mat(size), m)
+	}
+	return m
+}
+
+// panelTransform holds the transform styles of a panel. A transform that
+// only moves the panel in 2D is applied to its layout position, anything
+// else is drawn through the render transform without changing the layout
+type panelTransform struct {
+	translate         TransformOp
+	rotate            TransformOp
+	scale             TransformOp
+	functions         []TransformOp
+	origin            [2]TransformLength
+	originZ           float32
+	perspective       float32
+	perspectiveOrigin [2]TransformLength
+	preserve3D        bool
+	backfaceHidden    bool
+}
+
+var transformCenter = [2]TransformLength{{0.5, true}, {0.5, true}}
+
+func (p *Panel) transformState() *panelTransform {
+	pd := p.PanelData()
+	if pd.transform == nil {
+		pd.transform = &panelTransform{}
+		pd.transform.reset()
+	}
+	return pd.transform
+}
+
+func (t *panelTransform) reset() {
+	t.translate = TranslateOp(TransformLength{}, TransformLength{}, 0)
+	t.rotate = RotateOp(matrix.Vec3{0, 0, 1}, 0)
+	t.scale = ScaleOp(1, 1, 1)
+	t.functions = t.functions[:0]
+	t.origin = transformCenter
+	t.originZ = 0
+	t.perspective = 0
+	t.perspectiveOrigin = transformCenter
+	t.preserve3D = false
+	t.backfaceHidden = false
+}
+
+func (p *Panel) clearTransformStyles() {
+	if t := p.PanelData().transform; t != nil {
+		t.reset()
+	}
+}
+
+// ops lists the individual transforms and then the transform functions in
+// the order that the spec multiplies them
+func (t *panelTransform) ops() []TransformOp {
+	return append([]TransformOp{t.translate, t.rotate, t.scale}, t.functions...)
+}
+
+func (t *panelTransform) translationOnly() bool {
+	if t.rotate.Values.W() != 0 || t.scale.Values != (matrix.Vec4{1, 1, 1, 0}) ||
+		!t.translate.isTranslate2D() {
+		return false
+	}
+	for i := range t.functions {
+		if !t.functions[i].isTranslate2D() {
+			return false
+		}
+	}
+	return true
+}
+
+// layoutOffset is the 2D translation of a transform that is only a
+// translation, in the panel's local space (y up)
+func (t *panelTransform) layoutOffset(size matrix.Vec2) matrix.Vec2 {
+	if t == nil || !t.translationOnly() {
+		return matrix.Vec2Zero()
+	}
+	m := composeTransformOps(t.ops(), size)
+	return matrix.Vec2{m[12], -m[13]}
+}
+
+// SetTranslate sets the translate property, which is applied before rotate,
+// scale and the transform functions
+func (p *Panel) SetTranslate(x, y TransformLength, z float32) {
+	p.transformState().translate = TranslateOp(x, y, z)
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetRotate sets the rotate property, a rotation by degrees around the axis
+func (p *Panel) SetRotate(axis matrix.Vec3, degrees float32) {
+	p.transformState().rotate = RotateOp(axis, degrees)
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+func (p *Panel) SetScale(x, y, z float32) {
+	p.transformState().scale = ScaleOp(x, y, z)
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetTransformFunctions sets the functions of the transform property, an
+// empty list is none
+func (p *Panel) SetTransformFunctions(ops []TransformOp) {
+	t := p.transformState()
+	t.functions = append(t.functions[:0], ops...)
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetTransformOrigin sets the point that the panel is rotated and scaled
+// around, from the top left of the panel
+func (p *Panel) SetTransformOrigin(x, y TransformLength, z float32) {
+	t := p.transformState()
+	t.origin = [2]TransformLength{x, y}
+	t.originZ = z
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetTransformPreserve3D keeps the children of the panel in 3D rather than
+// flattening them onto the panel
+func (p *Panel) SetTransformPreserve3D(preserve bool) {
+	p.transformState().preserve3D = preserve
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetPerspective sets the distance of the viewer from the plane of the panel
+// used to project the 3D transforms of its children, 0 is none
+func (p *Panel) SetPerspective(distance float32) {
+	p.transformState().perspective = max(0, distance)
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetPerspectiveOrigin sets the point the children are looked at from
+func (p *Panel) SetPerspectiveOrigin(x, y TransformLength) {
+	p.transformState().perspectiveOrigin = [2]TransformLength{x, y}
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetBackfaceHidden hides the panel, and what is in it, while it is turned
+// away from the viewer
+func (p *Panel) SetBackfaceHidden(hidden bool) {
+	p.transformState().backfaceHidden = hidden
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// panelPoint is the world point at an offset from the top left of the panel
+func panelPoint(center matrix.Vec3, size matrix.Vec2, x, y TransformLength, z float32) matrix.Vec3 {
+	return matrix.Vec3{
+		center.X() - size.X()*0.5 + x.resolve(size.X()),
+		center.Y() + size.Y()*0.5 - y.resolve(size.Y()),
+		center.Z() + z,
+	}
+}
+
+// aroundPoint moves a matrix in CSS coordinates to be applied around a point
+// in world space, flipping y as the world's y goes up
+func aroundPoint(m matrix.Mat4, point matrix.Vec3) matrix.Mat4 {
+	to, from, flip := matrix.Mat4Identity(), matrix.Mat4Identity(), matrix.Mat4Identity()
+	to.Translate(point.Negative())
+	from.Translate(point)
+	flip[5] = -1
+	out := matrix.Mat4Multiply(to, flip)
+	out = matrix.Mat4Multiply(out, m)
+	out = matrix.Mat4Multiply(out, flip)
+	return matrix.Mat4Multiply(out, from)
+}
+
+// flattenAt projects points onto the plane at the depth z
+func flattenAt(z float32) matrix.Mat4 {
+	m := matrix.Mat4Identity()
+	m[10] = 0
+	m[14] = z
+	return m
+}
+
+// localRenderTransform is the transform of the panel in world space, with
+// the perspective of its parent, or false if the panel isn't transformed
+// beyond its layout
+func (p *Panel) localRenderTransform(parent *UI) (matrix.Mat4, bool) {
+	t := p.PanelData().transform
+	if t == nil || t.translationOnly() {
+		return matrix.Mat4Identity(), false
+	}
+	size := p.layout.PixelSize()
+	center := p.entity.Transform.WorldPosition()
+	m := aroundPoint(composeTransformOps(t.ops(), size),
+		panelPoint(center, size, t.origin[0], t.origin[1], t.originZ))
+	if parent != nil && !parent.IsType(ElementTypeLabel) {
+		pt := parent.ToPanel().PanelData().transform
+		if pt != nil && pt.perspective > 0 {
+			pp := parent.ToPanel()
+			origin := panelPoint(pp.entity.Transform.WorldPosition(), pp.layout.PixelSize(),
+				pt.perspectiveOrigin[0], pt.perspectiveOrigin[1], 0)
+			m = matrix.Mat4Multiply(m, aroundPoint(PerspectiveOp(pt.perspective).mat(size), origin))
+		}
+		if pt != nil && pt.preserve3D {
+			return m, true
+		}
+	}
+	return matrix.Mat4Multiply(m, flattenAt(center.Z())), true
+}
+
+// projectPoint moves a world point through a render transform
+func projectPoint(m matrix.Mat4, point matrix.Vec3) matrix.Vec2 {
+	v := matrix.Mat4MultiplyVec4(m, matrix.Vec4{point.X(), point.Y(), point.Z(), 1})
+	if v.W() == 0 {
+		return matrix.Vec2{v.X(), v.Y()}
+	}
+	return matrix.Vec2{v.X() / v.W(), v.Y() / v.W()}
+}
+
+// facesAway returns true if the rectangle is turned over by the transform
+func facesAway(m matrix.Mat4, center matrix.Vec3, size matrix.Vec2) bool {
+	hw, hh := size.X()*0.5, size.Y()*0.5
+	z := center.Z()
+	a := projectPoint(m, matrix.Vec3{center.X() - hw, center.Y() - hh, z})
+	b := projectPoint(m, matrix.Vec3{center.X() + hw, center.Y() - hh, z})
+	c := projectPoint(m, matrix.Vec3{center.X() - hw, center.Y() + hh, z})
+	ab, ac := b.Subtract(a), c.Subtract(a)
+	return ab.X()*ac.Y()-ab.Y()*ac.X() < 0
+}
+
+// transformedContains returns true if the screen point lands within the
+// rectangle drawn through the render transform. The plane of the rectangle
+// is mapped onto the screen by a 3x3 projection, which is inverted to find
+// the point on the rectangle under the screen point
+func transformedContains(m matrix.Mat4, center matrix.Vec3, size matrix.Vec2, point matrix.Vec2) bool {
+	z := center.Z()
+	h := matrix.Mat3{
+		m[0], m[1], m[3],
+		m[4], m[5], m[7],
+		z*m[8] + m[12], z*m[9] + m[13], z*m[11] + m[15],
+	}
+	if h.Determinant() == 0 {
+		return false
+	}
+	inv := h.Inverted()
+	x := point.X()*inv[0] + point.Y()*inv[3] + inv[6]
+	y := point.X()*inv[1] + point.Y()*inv[4] + inv[7]
+	w := point.X()*inv[2] + point.Y()*inv[5] + inv[8]
+	if w == 0 {
+		return false
+	}
+	x, y = x/w, y/w
+	return matrix.Abs(x-center.X()) <= size.X()*0.5 && matrix.Abs(y-center.Y()) <= size.Y()*0.5
+}
+
+// uiRenderTransform is the transform that an element, and everything within
+// it, is drawn with after the layout
+type uiRenderTransform struct {
+	model  matrix.Mat4
+	hidden bool
+}
+
+// updateRenderTransform combines the transform of the element with the one
+// of its parent and hands it to the drawings of the element, parents must
+// be updated before their children
+func (ui *UI) updateRenderTransform() {
+	var parent *UI
+	if !ui.entity.IsRoot() {
+		parent = FirstOnEntity(ui.entity.Parent)
+	}
+	model, transformed, hidden := matrix.Mat4Identity(), false, false
+	if parent != nil && parent.renderTransform != nil {
+		model, transformed, hidden = parent.renderTransform.model, true, parent.renderTransform.hidden
+	}
+	if !ui.IsType(ElementTypeLabel) {
+		p := ui.ToPanel()
+		if local, ok := p.localRenderTransform(parent); ok {
+			model, transformed = matrix.Mat4Multiply(local, model), true
+		}
+		if t := p.PanelData().transform; transformed && t != nil && t.backfaceHidden {
+			hidden = hidden || facesAway(model, ui.entity.Transform.WorldPosition(), ui.layout.PixelSize())
+		}
+	}
+	wasHidden := ui.renderTransform != nil && ui.renderTransform.hidden
+	if !transformed {
+		if ui.renderTransform == nil {
+			return
+		}
+		ui.renderTransform = nil
+		ui.eachShaderData(func(sd *rendering.ShaderDataBase) { sd.SetPostModel(nil) })
+	} else {
+		if ui.renderTransform == nil {
+			ui.renderTransform = &uiRenderTransform{}
+		}
+		ui.renderTransform.model, ui.renderTransform.hidden = model, hidden
+		ui.eachShaderData(func(sd *rendering.ShaderDataBase) { sd.SetPostModel(&model) })
+	}
+	if hidden {
+		ui.eachShaderData(func(sd *rendering.ShaderDataBase) { sd.Deactivate() })
+	} else if wasHidden {
+		if ui.IsType(ElementTypeLabel) {
+			ui.ToLabel().activateDrawings()
+		} else {
+			ui.eachShaderData(func(sd *rendering.ShaderDataBase) { sd.Activate() })
+			ui.ToPanel().updateShaderVisibility()
+		}
+	}
+}
+
+// eachShaderData calls the function for every drawing of the element
+func (ui *UI) eachShaderData(call func(sd *rendering.ShaderDataBase)) {
+	call(&ui.shaderData.ShaderDataBase)
+	if ui.IsType(ElementTypeLabel) {
+		ld := ui.ToLabel().LabelData()
+		for i := range ld.runeDrawings {
+			call(ld.runeDrawings[i].ShaderData.Base())
+		}
+		for _, layer := range ld.shadowLayers {
+			for _, sd := range layer.shaderData {
+				call(&sd.ShaderDataBase)
+			}
+		}
+	} else {
+		pd := ui.ToPanel().PanelData()
+		for _, sd := range pd.shadowShaderData {
+			call(&sd.ShaderDataBase)
+		}
+		if pd.borderImage != nil {
+			for _, sd := range pd.borderImage.shaderData {
+				call(&sd.ShaderDataBase)
+			}
+		}
+	}
+}
+
+// ContainsPoint tests a point, relative to the center of the window like
+// the cursor, against the element as it is drawn with its transforms and
+// clip path
+func (ui *UI) ContainsPoint(point matrix.Vec2) bool {
+	if !ui.shaderData.clip().contains(point) {
+		return false
+	}
+	if ui.renderTransform == nil {
+		return ui.entity.Transform.ContainsPoint2D(point)
+	}
+	if ui.renderTransform.hidden {
+		return false
+	}
+	return transformedContains(ui.renderTransform.model,
+		ui.entity.Transform.WorldPosition(), ui.layout.PixelSize(), point)
+}
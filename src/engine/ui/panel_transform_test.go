@@ -0,0 +1,99 @@
+/******************************************************************************/
+/* panel_transform_test.go                                                    */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"testing"
+
+	"kaijuengine.com/matrix"
+)
+
+func transformTestPoint(m matrix.Mat4, x, y, z float32) matrix.Vec2 {
+	return projectPoint(m, matrix.Vec3{x, y, z})
+}
+
+func TestComposeTransformOpsOrder(t *testing.T) {
+	px := TransformLength{Value: 10}
+	ops := []TransformOp{TranslateOp(px, TransformLength{}, 0), ScaleOp(2, 2, 1)}
+	got := transformTestPoint(composeTransformOps(ops, matrix.Vec2{100, 100}), 1, 0, 0)
+	// The scale is applied first as it is written last
+	if !matrix.Vec2ApproxTo(got, matrix.Vec2{12, 0}, 0.001) {
+		t.Fatalf("expected (12, 0), got %v", got)
+	}
+	half := TransformLength{Value: 0.5, Percent: true}
+	got = transformTestPoint(composeTransformOps([]TransformOp{TranslateOp(half, half, 0)}, matrix.Vec2{40, 20}), 0, 0, 0)
+	if !matrix.Vec2ApproxTo(got, matrix.Vec2{20, 10}, 0.001) {
+		t.Fatalf("expected the percentages of the size (20, 10), got %v", got)
+	}
+}
+
+func TestTransformRotateIsClockwise(t *testing.T) {
+	// In CSS coordinates y goes down, so +x turns toward +y
+	m := RotateOp(matrix.Vec3{0, 0, 1}, 90).mat(matrix.Vec2{})
+	if got := transformTestPoint(m, 1, 0, 0); !matrix.Vec2ApproxTo(got, matrix.Vec2{0, 1}, 0.001) {
+		t.Fatalf("expected (0, 1), got %v", got)
+	}
+	// In the world y goes up, so the turn goes down around the origin
+	world := aroundPoint(m, matrix.Vec3{100, 100, 0})
+	if got := transformTestPoint(world, 110, 100, 0); !matrix.Vec2ApproxTo(got, matrix.Vec2{100, 90}, 0.001) {
+		t.Fatalf("expected (100, 90), got %v", got)
+	}
+}
+
+func TestTransformPerspective(t *testing.T) {
+	ops := []TransformOp{PerspectiveOp(200), TranslateOp(TransformLength{}, TransformLength{}, 100)}
+	got := transformTestPoint(composeTransformOps(ops, matrix.Vec2{}), 10, 0, 0)
+	// Half way to the viewer is drawn twice as large
+	if !matrix.Vec2ApproxTo(got, matrix.Vec2{20, 0}, 0.001) {
+		t.Fatalf("expected (20, 0), got %v", got)
+	}
+}
+
+func TestTransformedContains(t *testing.T) {
+	center, size := matrix.Vec3{0, 0, 0}, matrix.Vec2{100, 20}
+	m := aroundPoint(RotateOp(matrix.Vec3{0, 0, 1}, 90).mat(size), center)
+	if !transformedContains(m, center, size, matrix.Vec2{0, 40}) {
+		t.Error("expected the turned panel to cover (0, 40)")
+	}
+	if transformedContains(m, center, size, matrix.Vec2{40, 0}) {
+		t.Error("expected the turned panel to no longer cover (40, 0)")
+	}
+	m = aroundPoint(composeTransformOps([]TransformOp{
+		PerspectiveOp(300), RotateOp(matrix.Vec3{0, 1, 0}, 60),
+	}, size), center)
+	if transformedContains(m, center, size, matrix.Vec2{45, 0}) {
+		t.Error("expected the edge turned away to be drawn closer to the center")
+	}
+	if !transformedContains(m, center, size, matrix.Vec2{20, 0}) {
+		t.Error("expected the panel to cover (20, 0)")
+	}
+}
+
+func TestTransformFacesAway(t *testing.T) {
+	center, size := matrix.Vec3{10, 10, 0}, matrix.Vec2{50, 50}
+	flipped := aroundPoint(RotateOp(matrix.Vec3{0, 1, 0}, 180).mat(size), center)
+	if !facesAway(flipped, center, size) {
+		t.Error("expected a half turn to face away")
+	}
+	turned := aroundPoint(RotateOp(matrix.Vec3{0, 1, 0}, 45).mat(size), center)
+	if facesAway(turned, center, size) {
+		t.Error("expected an eighth of a turn to still face the viewer")
+	}
+}
+
+func TestTransformLayoutOffset(t *testing.T) {
+	pt := &panelTransform{}
+	pt.reset()
+	pt.functions = []TransformOp{TranslateOp(TransformLength{Value: -0.5, Percent: true}, TransformLength{Value: 10}, 0)}
+	if got := pt.layoutOffset(matrix.Vec2{200, 100}); !matrix.Vec2ApproxTo(got, matrix.Vec2{-100, -10}, 0.001) {
+		t.Fatalf("expected (-100, -10), got %v", got)
+	}
+	pt.rotate = RotateOp(matrix.Vec3{0, 0, 1}, 10)
+	if got := pt.layoutOffset(matrix.Vec2{200, 100}); got != matrix.Vec2Zero() {
+		t.Fatalf("expected a rotated panel to be moved by its render transform, got %v", got)
+	}
+}
@@ -351,6 +351,9 @@ func (s *Select) Value() string {
 	return data.options[data.selected].Value
 }
 
+// IsExpanded returns true while the list of options is open
+func (s *Select) IsExpanded() bool { return s.SelectData().isOpen }
+
 func (s *Select) SetColor(newColor matrix.Color) {
 	s.Base().ToPanel().SetColor(newColor)
 	s.SelectData().label.SetBGColor(newColor)
@@ -26,6 +26,14 @@ type ShaderData struct {
 	BorderLen    matrix.Vec2
 	OutlineColor matrix.Color
 	OutlineSize  matrix.Vec2
+	// The packed kinds of the clip shape and the mask followed by the
+	// inset corner radius. It is read together with OutlineSize as a single
+	// vec4 to save a vertex input location
+	ClipShape matrix.Vec2
+	// Polygon points (2 per column), or the center and radii of the clip
+	// shape followed by the geometry, stop positions and stop values of the
+	// mask gradient, in UI world space
+	ClipData matrix.Mat4
 }
 
 func (ShaderData) Size() int {
@@ -40,7 +48,9 @@ func (ShaderData) Size() int {
 		unsafe.Sizeof(ShaderData{}.BorderColor) +
 		unsafe.Sizeof(ShaderData{}.BorderLen) +
 		unsafe.Sizeof(ShaderData{}.OutlineColor) +
-		unsafe.Sizeof(ShaderData{}.OutlineSize))
+		unsafe.Sizeof(ShaderData{}.OutlineSize) +
+		unsafe.Sizeof(ShaderData{}.ClipShape) +
+		unsafe.Sizeof(ShaderData{}.ClipData))
 }
 
 func (s *ShaderData) setUVSize(width, height float32) {
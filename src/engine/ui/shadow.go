@@ -0,0 +1,285 @@
+/******************************************************************************/
+/* shadow.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"log/slog"
+	"unsafe"
+
+	"kaijuengine.com/engine/assets"
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering"
+)
+
+// shadowDepthBias is how far a shadow layer is pushed behind (outer) or in
+// front of (inset) the panel it belongs to. Children are placed 0.01 in front
+// of their parent, so this keeps inset shadows below the panel's content
+const shadowDepthBias = 0.005
+
+// shadowSigmaRange is how many standard deviations of the blur are drawn
+// before the shadow is considered fully faded out
+const shadowSigmaRange = 3
+
+// BoxShadow is a single layer of a CSS box-shadow. Offsets, blur and spread
+// are in pixels, with a positive Y offset moving the shadow down
+type BoxShadow struct {
+	Offset matrix.Vec2
+	Blur   float32
+	Spread float32
+	Color  matrix.Color
+	Inset  bool
+}
+
+// TextShadow is a single layer of a CSS text-shadow
+type TextShadow struct {
+	Offset matrix.Vec2
+	Blur   float32
+	Color  matrix.Color
+}
+
+type ShadowShaderData struct {
+	rendering.ShaderDataBase
+	Color   matrix.Color
+	Scissor matrix.Vec4
+	// Width, height, quad outset and inset (1) or outer (0)
+	Size2D       matrix.Vec4
+	BorderRadius matrix.Vec4
+	// Offset x, offset y (world space), blur sigma and spread
+	Shadow matrix.Vec4
+}
+
+func (ShadowShaderData) Size() int {
+	return int(rendering.ShaderBaseDataSize +
+		unsafe.Sizeof(ShadowShaderData{}.Color) +
+		unsafe.Sizeof(ShadowShaderData{}.Scissor) +
+		unsafe.Sizeof(ShadowShaderData{}.Size2D) +
+		unsafe.Sizeof(ShadowShaderData{}.BorderRadius) +
+		unsafe.Sizeof(ShadowShaderData{}.Shadow))
+}
+
+// Sigma converts the CSS blur radius into the standard deviation of the
+// gaussian used to draw it, which the spec defines as half the radius
+func (s BoxShadow) Sigma() float32 { return max(s.Blur, 0) * 0.5 }
+
+// Outset is how far past the border box this layer can paint
+func (s BoxShadow) Outset() float32 {
+	if s.Inset {
+		return 0
+	}
+	return max(0, max(matrix.Abs(s.Offset.X()), matrix.Abs(s.Offset.Y()))+
+		s.Spread+s.Sigma()*shadowSigmaRange)
+}
+
+// BoxShadowsOutset is the furthest any of the outer shadows paint past the
+// border box, used to grow the quad and scissor of the panel
+func BoxShadowsOutset(shadows []BoxShadow) float32 {
+	outset := float32(0)
+	for i := range shadows {
+		outset = max(outset, shadows[i].Outset())
+	}
+	return outset
+}
+
+// shadowRadius grows (or shrinks) the corner radius with the spread the same
+// way the spec does, square corners stay square
+func shadowRadius(radius matrix.Vec4, spread float32) matrix.Vec4 {
+	for i := range radius {
+		if radius[i] > 0 {
+			radius[i] = max(0, radius[i]+spread)
+		}
+	}
+	return radius
+}
+
+func (s BoxShadow) shaderParams() matrix.Vec4 {
+	return matrix.Vec4{s.Offset.X(), -s.Offset.Y(), s.Sigma(), s.Spread}
+}
+
+func (p *Panel) BoxShadows() []BoxShadow { return p.PanelData().boxShadows }
+
+// SetBoxShadows replaces the shadow layers drawn for this panel. The first
+// shadow in the list is drawn on top like it is in CSS
+func (p *Panel) SetBoxShadows(shadows []BoxShadow) {
+	pd := p.PanelData()
+	p.clearBoxShadows()
+	pd.boxShadows = shadows
+	p.Base().SetDirty(DirtyTypeLayout)
+	if len(shadows) == 0 {
+		return
+	}
+	host := p.man.Value().Host
+	material, err := host.MaterialCache().Material(assets.MaterialDefinitionUIShadow)
+	if err != nil {
+		slog.Error("failed to load the ui shadow material for panel", "error", err)
+		return
+	}
+	for i := len(shadows) - 1; i >= 0; i-- {
+		sd := &ShadowShaderData{ShaderDataBase: rendering.NewShaderDataBase()}
+		pd.shadowShaderData = append(pd.shadowShaderData, sd)
+		host.Drawings.AddDrawing(rendering.Drawing{
+			Material:   material,
+			Mesh:       rendering.NewMeshQuad(host.MeshCache()),
+			ShaderData: sd,
+			Transform:  &p.entity.Transform,
+			Layer:      rendering.RenderLayerUI,
+			ViewCuller: &host.Cameras.UI,
+		})
+	}
+	if !p.entity.IsActive() {
+		p.deactivateBoxShadows()
+	}
+	p.updateBoxShadows()
+}
+
+func (p *Panel) clearBoxShadows() {
+	pd := p.PanelData()
+	for i := range pd.shadowShaderData {
+		pd.shadowShaderData[i].Destroy()
+	}
+	pd.shadowShaderData = pd.shadowShaderData[:0]
+}
+
+func (p *Panel) activateBoxShadows() {
+	for _, sd := range p.PanelData().shadowShaderData {
+		sd.Activate()
+	}
+}
+
+func (p *Panel) deactivateBoxShadows() {
+	for _, sd := range p.PanelData().shadowShaderData {
+		sd.Deactivate()
+	}
+}
+
+// updateBoxShadows copies the current size, corners, scissor and (filtered)
+// colors of the panel over to the shadow layers
+func (p *Panel) updateBoxShadows() {
+	pd := p.PanelData()
+	if len(pd.shadowShaderData) == 0 {
+		return
+	}
+	ws := p.entity.Transform.WorldScale()
+	filters := p.Base().effectiveFilters()
+	last := len(pd.boxShadows) - 1
+	for i, sd := range pd.shadowShaderData {
+		s := pd.boxShadows[last-i]
+		sd.Color = FilterColor(filters, s.Color)
+		sd.Scissor = p.shaderData.Scissor
+		sd.Size2D = matrix.Vec4{ws.X(), ws.Y(), s.Outset(), 0}
+		if s.Inset {
+			sd.Size2D.SetW(1)
+		}
+		sd.BorderRadius = shadowRadius(p.shaderData.BorderRadius, s.Spread)
+		sd.Shadow = s.shaderParams()
+	}
+}
+
+func (label *Label) TextShadows() []TextShadow { return label.LabelData().textShadows }
+
+// SetTextShadows replaces the shadow layers drawn under the text. The shadows
+// are copies of the glyphs drawn through the regular MSDF text material with
+// the distance field edge widened to produce the blur
+func (label *Label) SetTextShadows(shadows []TextShadow) {
+	ld := label.LabelData()
+	ld.textShadows = shadows
+	ld.renderRequired = true
+	label.Base().SetDirty(DirtyTypeGenerated)
+}
+
+type textShadowLayer struct {
+	transform  matrix.Transform
+	shaderData []*rendering.TextShaderData
+	drawings   []rendering.Drawing
+}
+
+// blurredPxRange scales the distance field range handed to the text shader so
+// that the glyph edge fades out over roughly the blur radius in pixels rather
+// than the single pixel used for crisp text
+func blurredPxRange(pxRange matrix.Vec2, blur float32) matrix.Vec2 {
+	if blur <= 1 {
+		return pxRange
+	}
+	return pxRange.Scale(1 / blur)
+}
+
+func (label *Label) renderTextShadows(lines []rendering.TextLine, maxWidth float32) {
+	ld := label.LabelData()
+	host := label.man.Value().Host
+	for i := len(ld.textShadows) - 1; i >= 0; i-- {
+		// Colors and the blur are applied by updateTextShadows, these are only
+		// here to select the transparent text material
+		color := ld.textShadows[i].Color
+		bg := color
+		bg.SetA(0)
+		layer := &textShadowLayer{}
+		layer.transform.Initialize(host.WorkGroup())
+		layer.transform.SetParent(&label.entity.Transform)
+		layer.drawings, _ = label.renderLines(lines, maxWidth, color, bg)
+		layer.shaderData = make([]*rendering.TextShaderData, len(layer.drawings))
+		for j := range layer.drawings {
+			d := &layer.drawings[j]
+			d.Transform = &layer.transform
+			d.Layer = rendering.RenderLayerUI
+			layer.shaderData[j] = d.ShaderData.(*rendering.TextShaderData)
+			layer.shaderData[j].Scissor = label.shaderData.Scissor
+		}
+		ld.shadowLayers = append(ld.shadowLayers, layer)
+		host.Drawings.AddDrawings(layer.drawings)
+	}
+	label.placeTextShadows()
+}
+
+func (label *Label) updateTextShadows(filters []Filter) {
+	ld := label.LabelData()
+	last := len(ld.textShadows) - 1
+	for i, layer := range ld.shadowLayers {
+		s := ld.textShadows[last-i]
+		color := FilterColor(filters, s.Color)
+		bg := color
+		bg.SetA(0)
+		pxRange := blurredPxRange(ld.pxRange, s.Blur)
+		for _, sd := range layer.shaderData {
+			sd.FgColor = color
+			sd.BgColor = bg
+			sd.PxRange = pxRange
+		}
+	}
+}
+
+// placeTextShadows offsets each shadow layer from the label, the layers are
+// children of the label transform so the offset is divided by its scale
+func (label *Label) placeTextShadows() {
+	ld := label.LabelData()
+	ws := label.entity.Transform.WorldScale()
+	last := len(ld.textShadows) - 1
+	for i, layer := range ld.shadowLayers {
+		s := ld.textShadows[last-i]
+		layer.transform.SetPosition(matrix.Vec3{
+			safeDivide(s.Offset.X(), ws.X()),
+			safeDivide(-s.Offset.Y(), ws.Y()),
+			safeDivide(-shadowDepthBias, ws.Z()),
+		})
+	}
+}
+
+func (label *Label) clearTextShadows() {
+	ld := label.LabelData()
+	for _, layer := range ld.shadowLayers {
+		for _, sd := range layer.shaderData {
+			sd.Destroy()
+		}
+		layer.transform.SetParent(nil)
+	}
+	ld.shadowLayers = ld.shadowLayers[:0]
+}
+
+func safeDivide(a, b float32) float32 {
+	if matrix.Approx(b, 0) {
+		return a
+	}
+	return a / b
+}
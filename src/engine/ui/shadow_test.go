@@ -0,0 +1,74 @@
+/******************************************************************************/
+/* shadow_test.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"testing"
+
+	"kaijuengine.com/matrix"
+)
+
+func TestBoxShadowOutset(t *testing.T) {
+	s := BoxShadow{Offset: matrix.Vec2{4, -6}, Blur: 10, Spread: 2}
+	// Largest offset (6) + spread (2) + 3 sigma of a 10px blur (15)
+	if got := s.Outset(); !matrix.Approx(got, 23) {
+		t.Fatalf("expected an outset of 23, got %f", got)
+	}
+	s.Inset = true
+	if got := s.Outset(); got != 0 {
+		t.Fatalf("expected inset shadows not to paint outside the box, got %f", got)
+	}
+	s = BoxShadow{Offset: matrix.Vec2{1, 1}, Spread: -5}
+	if got := s.Outset(); got != 0 {
+		t.Fatalf("expected a negative spread to clamp the outset to 0, got %f", got)
+	}
+}
+
+func TestBoxShadowsOutset(t *testing.T) {
+	shadows := []BoxShadow{
+		{Offset: matrix.Vec2{2, 2}},
+		{Offset: matrix.Vec2{0, 0}, Blur: 4, Inset: true},
+		{Offset: matrix.Vec2{0, 8}},
+	}
+	if got := BoxShadowsOutset(shadows); !matrix.Approx(got, 8) {
+		t.Fatalf("expected the largest outer outset of 8, got %f", got)
+	}
+	if got := BoxShadowsOutset(nil); got != 0 {
+		t.Fatalf("expected no outset without shadows, got %f", got)
+	}
+}
+
+func TestShadowRadius(t *testing.T) {
+	got := shadowRadius(matrix.Vec4{0, 4, 8, 2}, 3)
+	want := matrix.Vec4{0, 7, 11, 5}
+	if !got.Equals(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	got = shadowRadius(matrix.Vec4{4, 4, 4, 4}, -10)
+	if !got.Equals(matrix.Vec4Zero()) {
+		t.Fatalf("expected radii to clamp at 0, got %v", got)
+	}
+}
+
+func TestBoxShadowShaderParams(t *testing.T) {
+	s := BoxShadow{Offset: matrix.Vec2{3, 5}, Blur: 8, Spread: 1}
+	got := s.shaderParams()
+	want := matrix.Vec4{3, -5, 4, 1}
+	if !got.Equals(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestBlurredPxRange(t *testing.T) {
+	base := matrix.Vec2{4, 4}
+	if got := blurredPxRange(base, 0); !got.Equals(base) {
+		t.Fatalf("expected no blur to keep the range, got %v", got)
+	}
+	if got := blurredPxRange(base, 8); !got.Equals(matrix.Vec2{0.5, 0.5}) {
+		t.Fatalf("expected the range to shrink with the blur, got %v", got)
+	}
+}
@@ -0,0 +1,706 @@
+/******************************************************************************/
+/* table.go                                                                   */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"log/slog"
+
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/platform/profiler/tracing"
+)
+
+// TableRole is the part a panel plays in a table layout, it comes from the
+// tag of the element (td, tr, thead, ...) or the table values of display
+type TableRole = int
+
+const (
+	TableRoleNone TableRole = iota
+	TableRoleTable
+	TableRoleCaption
+	TableRoleHeaderGroup
+	TableRoleRowGroup
+	TableRoleFooterGroup
+	TableRoleRow
+	TableRoleCell
+	TableRoleColumnGroup
+	TableRoleColumn
+)
+
+const (
+	// tableDefaultSpacing is the border-spacing browsers give tables
+	tableDefaultSpacing = 2
+	tableMaxColumnSpan  = 1000
+	tableMaxRowSpan     = 65534
+)
+
+type panelTable struct {
+	role    TableRole
+	tagRole TableRole
+	// colSpan and rowSpan are the colspan and rowspan of a cell, colSpan is
+	// also the span of a column or column group
+	colSpan int
+	rowSpan int
+	// The styles of a table
+	fixedLayout   bool
+	collapse      bool
+	spacing       matrix.Vec2
+	captionBottom bool
+	hideEmpty     bool
+	// verticalAlign is where the content of a cell sits, 0 is the top and 1
+	// is the bottom
+	verticalAlign float32
+	// The state given by the table that the panel is in
+	placed        bool
+	size          matrix.Vec2
+	contentHeight float32
+	emptyHidden   bool
+	// explicit is the size the styles gave the panel (0 on the axes that fit
+	// the content), read before the table first resizes it
+	explicit      matrix.Vec2
+	explicitTaken bool
+}
+
+// tableSpan is the colspan and rowspan of a cell as given to placeTableCells
+type tableSpan struct {
+	cols int
+	rows int
+}
+
+// tableSlot is where a cell was placed in the table grid
+type tableSlot struct {
+	row     int
+	col     int
+	rowSpan int
+	colSpan int
+}
+
+func (p *Panel) tableState() *panelTable {
+	pd := p.PanelData()
+	if pd.table == nil {
+		pd.table = &panelTable{colSpan: 1, rowSpan: 1, verticalAlign: 0.5,
+			spacing: matrix.Vec2{tableDefaultSpacing, tableDefaultSpacing}}
+	}
+	return pd.table
+}
+
+func (p *Panel) clearTableStyles() {
+	t := p.PanelData().table
+	if t == nil {
+		return
+	}
+	t.role = t.tagRole
+	t.fixedLayout = false
+	t.collapse = false
+	t.spacing = matrix.Vec2{tableDefaultSpacing, tableDefaultSpacing}
+	t.captionBottom = false
+	t.hideEmpty = false
+	t.verticalAlign = 0.5
+	t.placed = false
+	t.emptyHidden = false
+	t.explicitTaken = false
+}
+
+// SetTableTagRole sets the table role that the tag of the element gives it,
+// which the panel goes back to when its layout styles are cleared
+func (p *Panel) SetTableTagRole(role TableRole) {
+	t := p.tableState()
+	t.tagRole = role
+	p.SetTableRole(role)
+}
+
+// SetTableRole changes the part the panel plays in a table layout, a panel
+// with TableRoleTable lays out the rows and cells within it
+func (p *Panel) SetTableRole(role TableRole) {
+	if role == TableRoleNone && p.PanelData().table == nil {
+		return
+	}
+	t := p.tableState()
+	if t.role == role {
+		return
+	}
+	t.role = role
+	t.placed = false
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+func (p *Panel) TableRole() TableRole {
+	if t := p.PanelData().table; t != nil {
+		return t.role
+	}
+	return TableRoleNone
+}
+
+// IsTable returns true if the panel lays out its children as a table
+func (p *Panel) IsTable() bool {
+	pd := p.PanelData()
+	return pd.table != nil && pd.table.role == TableRoleTable && pd.layoutMode == LayoutModeFlow
+}
+
+// SetTableCellSpan sets how many columns and rows a cell covers, for a
+// column (or column group) only the column span is used
+func (p *Panel) SetTableCellSpan(cols, rows int) {
+	t := p.tableState()
+	cols = max(1, min(cols, tableMaxColumnSpan))
+	rows = max(1, min(rows, tableMaxRowSpan))
+	if t.colSpan == cols && t.rowSpan == rows {
+		return
+	}
+	t.colSpan, t.rowSpan = cols, rows
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+func (p *Panel) TableCellSpan() (cols, rows int) {
+	if t := p.PanelData().table; t != nil {
+		return t.colSpan, t.rowSpan
+	}
+	return 1, 1
+}
+
+// SetTableLayoutFixed sizes the columns from the first row and the column
+// elements alone, rather than from the content of every cell
+func (p *Panel) SetTableLayoutFixed(fixed bool) {
+	p.tableState().fixedLayout = fixed
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetTableBorderCollapse makes the borders of neighbouring cells overlap
+// into one, rather than being drawn apart with the border spacing between
+func (p *Panel) SetTableBorderCollapse(collapse bool) {
+	p.tableState().collapse = collapse
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetTableBorderSpacing sets the space between the cells of the table and
+// around them, it isn't used when the borders are collapsed
+func (p *Panel) SetTableBorderSpacing(horizontal, vertical float32) {
+	p.tableState().spacing = matrix.Vec2{max(0, horizontal), max(0, vertical)}
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetTableCaptionBottom places the captions of the table below it
+func (p *Panel) SetTableCaptionBottom(bottom bool) {
+	p.tableState().captionBottom = bottom
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetTableEmptyCellsHidden stops the background and borders of cells that
+// have no content from being drawn, it is set on the table or on the cell
+func (p *Panel) SetTableEmptyCellsHidden(hide bool) {
+	p.tableState().hideEmpty = hide
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+// SetTableCellVerticalAlign sets where the content of a cell sits within
+// the height of its row, 0 is the top, 0.5 the middle and 1 the bottom
+func (p *Panel) SetTableCellVerticalAlign(align float32) {
+	p.tableState().verticalAlign = matrix.Clamp(align, 0, 1)
+	p.Base().SetDirty(DirtyTypeLayout)
+}
+
+func (t *panelTable) isRowGroup() bool {
+	return t.role == TableRoleHeaderGroup || t.role == TableRoleRowGroup || t.role == TableRoleFooterGroup
+}
+
+// laidOutByTable returns true for the parts of a table (rows, groups and
+// columns) whose children are placed by the table rather than themselves
+func (t *panelTable) laidOutByTable() bool {
+	if t == nil || !t.placed {
+		return false
+	}
+	switch t.role {
+	case TableRoleRow, TableRoleColumn, TableRoleColumnGroup:
+		return true
+	}
+	return t.isRowGroup()
+}
+
+// fitPlacedSize changes the fitted size of a table part to the size the
+// table gave it. Cells and captions keep the height of their content if it
+// is larger, which the table reads back to size the rows
+func (t *panelTable) fitPlacedSize(w, h float32) (float32, float32) {
+	switch t.role {
+	case TableRoleCell, TableRoleCaption:
+		return t.size.X(), max(h, t.size.Y())
+	}
+	return t.size.X(), t.size.Y()
+}
+
+// explicitTableSize reads the size that the styles gave the panel, before the
+// table first resizes it
+func (p *Panel) explicitTableSize() matrix.Vec2 {
+	t := p.tableState()
+	if !t.explicitTaken {
+		t.explicitTaken = true
+		t.explicit = p.layout.PixelSize()
+		if p.FittingContentWidth() {
+			t.explicit.SetX(0)
+		}
+		if p.FittingContentHeight() {
+			t.explicit.SetY(0)
+		}
+	}
+	return t.explicit
+}
+
+// placeTableCells runs the HTML table cell placement. Each cell takes the
+// first column of its row that isn't covered by a cell from a row above
+// (rowspan), rowspans stop at the end of the row group, which groupEnds
+// gives for every row
+func placeTableCells(rows [][]tableSpan, groupEnds []int) (slots [][]tableSlot, columns int) {
+	covered := map[[2]int]bool{}
+	slots = make([][]tableSlot, len(rows))
+	for r := range rows {
+		slots[r] = make([]tableSlot, len(rows[r]))
+		col := 0
+		for i, span := range rows[r] {
+			for covered[[2]int{r, col}] {
+				col++
+			}
+			colSpan := max(1, span.cols)
+			rowSpan := max(1, min(span.rows, groupEnds[r]-r))
+			for y := r; y < r+rowSpan; y++ {
+				for x := col; x < col+colSpan; x++ {
+					covered[[2]int{y, x}] = true
+				}
+			}
+			slots[r][i] = tableSlot{row: r, col: col, rowSpan: rowSpan, colSpan: colSpan}
+			col += colSpan
+			columns = max(columns, col)
+		}
+	}
+	return slots, columns
+}
+
+// sizeFixedTableColumns shares the width of a fixed layout table between
+// the columns. The widths are those of the column elements or else of the
+// cells of the first row (0 when not given), the columns without a width
+// share what is left over equally
+func sizeFixedTableColumns(widths []float32, available, gap float32) []float32 {
+	sizes := make([]float32, len(widths))
+	remaining := available - float32(max(len(widths)-1, 0))*gap
+	open := 0
+	for i, w := range widths {
+		if w > 0 {
+			sizes[i] = w
+			remaining -= w
+		} else {
+			open++
+		}
+	}
+	if open == 0 {
+		return sizes
+	}
+	share := max(remaining, 0) / float32(open)
+	for i := range sizes {
+		if widths[i] <= 0 {
+			sizes[i] = share
+		}
+	}
+	return sizes
+}
+
+// fitTableColumns stretches (or shrinks) the content sized columns of an
+// auto layout table to the available width, in proportion to their size. A
+// negative available width leaves the columns at their content size
+func fitTableColumns(sizes []float32, available, gap, maxWidth float32) {
+	used := float32(max(len(sizes)-1, 0)) * gap
+	content := float32(0)
+	for _, s := range sizes {
+		content += s
+	}
+	used += content
+	target := available
+	if target < 0 {
+		if maxWidth < 0 || used <= maxWidth {
+			return
+		}
+		target = maxWidth
+	}
+	extra := target - used
+	if content <= 0 {
+		if extra > 0 && len(sizes) > 0 {
+			for i := range sizes {
+				sizes[i] = extra / float32(len(sizes))
+			}
+		}
+		return
+	}
+	scale := max(0, (content+extra)/content)
+	for i := range sizes {
+		sizes[i] = max(1, sizes[i]*scale)
+	}
+}
+
+// tableTrackStarts returns where each column (or row) starts. Separated
+// borders put the spacing before, between and after the tracks. Collapsed
+// borders pull each track back over the one before by its overlap
+func tableTrackStarts(sizes, overlaps []float32, spacing float32, collapse bool) (starts []float32, end float32) {
+	starts = make([]float32, len(sizes))
+	pos := spacing
+	if collapse {
+		pos = 0
+	}
+	for i := range sizes {
+		if collapse {
+			pos -= overlaps[i]
+		}
+		starts[i] = pos
+		pos += sizes[i]
+		if !collapse {
+			pos += spacing
+		}
+	}
+	return starts, pos
+}
+
+// tableSpanSize is the size of the tracks from pos covering span tracks
+func tableSpanSize(starts, sizes []float32, pos, span int) float32 {
+	last := min(pos+span, len(sizes)) - 1
+	if last < pos {
+		return 0
+	}
+	return starts[last] + sizes[last] - starts[pos]
+}
+
+// tableRowGroup is a thead, tbody or tfoot, or the rows directly within the
+// table (panel is nil)
+type tableRowGroup struct {
+	panel *Panel
+	rows  []*Panel
+}
+
+type tableCell struct {
+	panel *Panel
+	row   *Panel
+	slot  tableSlot
+}
+
+func tableChildPanels(p *Panel) []*Panel {
+	kids := make([]*Panel, 0, len(p.entity.Children))
+	for _, kid := range p.entity.Children {
+		if !kid.IsActive() || kid.IsDestroyed() {
+			continue
+		}
+		kui := FirstOnEntity(kid)
+		if kui == nil {
+			slog.Error("No UI component on entity")
+			continue
+		}
+		if kui.IsType(ElementTypeLabel) || kui.Layout().Positioning() == PositioningAbsolute {
+			continue
+		}
+		kids = append(kids, kui.ToPanel())
+	}
+	return kids
+}
+
+// collectTableParts sorts the children of the table into captions, columns
+// and row groups. Header groups go first and footer groups last no matter
+// where they are in the document
+func (p *Panel) collectTableParts() (captions []*Panel, columns []*Panel, groups []tableRowGroup) {
+	var headers, bodies, footers []tableRowGroup
+	for _, kid := range tableChildPanels(p) {
+		switch kid.TableRole() {
+		case TableRoleCaption:
+			captions = append(captions, kid)
+		case TableRoleColumn:
+			columns = append(columns, kid)
+		case TableRoleColumnGroup:
+			columns = append(columns, kid)
+		case TableRoleRow:
+			if len(bodies) == 0 || bodies[len(bodies)-1].panel != nil {
+				bodies = append(bodies, tableRowGroup{})
+			}
+			bodies[len(bodies)-1].rows = append(bodies[len(bodies)-1].rows, kid)
+		case TableRoleHeaderGroup, TableRoleRowGroup, TableRoleFooterGroup:
+			g := tableRowGroup{panel: kid}
+			for _, row := range tableChildPanels(kid) {
+				if row.TableRole() == TableRoleRow {
+					g.rows = append(g.rows, row)
+				}
+			}
+			switch kid.TableRole() {
+			case TableRoleHeaderGroup:
+				headers = append(headers, g)
+			case TableRoleFooterGroup:
+				footers = append(footers, g)
+			default:
+				bodies = append(bodies, g)
+			}
+		}
+	}
+	groups = append(append(headers, bodies...), footers...)
+	return captions, columns, groups
+}
+
+// tableColumnWidths reads the widths set on the column elements, a column
+// group without columns covers its span
+func tableColumnWidths(columns []*Panel) []float32 {
+	widths := []float32{}
+	add := func(col *Panel) {
+		t := col.tableState()
+		w := col.explicitTableSize().X()
+		for range t.colSpan {
+			widths = append(widths, w/float32(t.colSpan))
+		}
+	}
+	for _, col := range columns {
+		if col.TableRole() == TableRoleColumn {
+			add(col)
+			continue
+		}
+		inner := 0
+		for _, kid := range tableChildPanels(col) {
+			if kid.TableRole() == TableRoleColumn {
+				add(kid)
+				kid.tableState().placed, kid.tableState().emptyHidden = true, true
+				inner++
+			}
+		}
+		if inner == 0 {
+			add(col)
+		}
+	}
+	for _, col := range columns {
+		col.tableState().placed, col.tableState().emptyHidden = true, true
+	}
+	return widths
+}
+
+// tableCellContentWidth is the width of the content of the cell when none
+// of its text wraps
+func tableCellContentWidth(cell *Panel) float32 {
+	w := float32(0)
+	for _, kid := range cell.entity.Children {
+		kui := FirstOnEntity(kid)
+		if kui == nil || !kid.IsActive() || kui.Layout().Positioning() == PositioningAbsolute {
+			continue
+		}
+		if kui.IsType(ElementTypeLabel) {
+			w += kui.ToLabel().measure(matrix.FloatMax).X() + 0.1
+		} else {
+			w += kui.Layout().PixelSize().X() + kui.Layout().Margin().Horizontal()
+		}
+	}
+	return w + cell.layout.padding.Horizontal() + cell.layout.border.Horizontal()
+}
+
+func tableCellIsEmpty(cell *Panel) bool {
+	for _, kid := range cell.entity.Children {
+		if kid.IsActive() && !kid.IsDestroyed() {
+			return false
+		}
+	}
+	return true
+}
+
+// tableOverlaps is how far each track of a collapsed table goes back over
+// the one before, which is the widest border at the start of its cells,
+// the first track overlaps the border of the table
+func tableOverlaps(count int, cells []tableCell, horizontal bool, tableBorder float32) []float32 {
+	overlaps := make([]float32, count)
+	for _, c := range cells {
+		b := c.panel.layout.border
+		pos, border := c.slot.col, b.Left()
+		if !horizontal {
+			pos, border = c.slot.row, b.Top()
+		}
+		if pos < count {
+			overlaps[pos] = max(overlaps[pos], border)
+		}
+	}
+	if count > 0 {
+		overlaps[0] = min(overlaps[0], tableBorder)
+	}
+	return overlaps
+}
+
+func placeTablePart(part *Panel, x, y, w, h float32) {
+	t := part.tableState()
+	t.placed = true
+	t.size = matrix.Vec2{max(1, w), max(1, h)}
+	part.layout.SetRowLayoutOffset(matrix.Vec2{x, y})
+	part.layout.Scale(t.size.X(), t.size.Y())
+}
+
+func (p *Panel) layoutTableChildren(pd *panelData, offsetStart matrix.Vec2, ps matrix.Vec2) matrix.Vec2 {
+	defer tracing.NewRegion("Panel.layoutTableChildren").End()
+	style := pd.table
+	innerLeft := p.layout.padding.Left() + p.layout.border.Left()
+	innerTop := p.layout.padding.Top() + p.layout.border.Top()
+	spacing := style.spacing
+	if style.collapse {
+		spacing = matrix.Vec2Zero()
+	}
+	available := float32(-1)
+	if !p.FittingContentWidth() {
+		available = max(ps.X()-p.layout.padding.Horizontal()-p.layout.border.Horizontal(), 1)
+	}
+	maxWidth := float32(-1)
+	if !p.entity.IsRoot() {
+		if pu := FirstOnEntity(p.entity.Parent); pu != nil && !pu.IsType(ElementTypeLabel) {
+			maxWidth = pu.Layout().ContentSize().X() - p.layout.padding.Horizontal() - p.layout.border.Horizontal()
+		}
+	}
+	captions, columns, groups := p.collectTableParts()
+	rowSpans := [][]tableSpan{}
+	rowPanels := []*Panel{}
+	groupEnds := []int{}
+	cellPanels := [][]*Panel{}
+	for _, g := range groups {
+		end := len(rowPanels) + len(g.rows)
+		for _, row := range g.rows {
+			spans := []tableSpan{}
+			cells := []*Panel{}
+			for _, cell := range tableChildPanels(row) {
+				if cell.TableRole() != TableRoleCell {
+					continue
+				}
+				cols, rows := cell.TableCellSpan()
+				spans = append(spans, tableSpan{cols: cols, rows: rows})
+				cells = append(cells, cell)
+			}
+			rowSpans = append(rowSpans, spans)
+			rowPanels = append(rowPanels, row)
+			groupEnds = append(groupEnds, end)
+			cellPanels = append(cellPanels, cells)
+		}
+	}
+	slots, colCount := placeTableCells(rowSpans, groupEnds)
+	colWidths := tableColumnWidths(columns)
+	colCount = max(colCount, len(colWidths))
+	cells := []tableCell{}
+	for r := range slots {
+		for i := range slots[r] {
+			cells = append(cells, tableCell{panel: cellPanels[r][i], row: rowPanels[r], slot: slots[r][i]})
+		}
+	}
+	// Column widths
+	var widths []float32
+	if style.fixedLayout && available >= 0 {
+		fixed := make([]float32, colCount)
+		copy(fixed, colWidths)
+		for _, c := range cells {
+			if c.slot.row != 0 {
+				continue
+			}
+			w := c.panel.explicitTableSize().X()
+			if w > 0 && fixed[c.slot.col] <= 0 {
+				for x := c.slot.col; x < c.slot.col+c.slot.colSpan; x++ {
+					fixed[x] = w / float32(c.slot.colSpan)
+				}
+			}
+		}
+		widths = sizeFixedTableColumns(fixed, available-2*spacing.X(), spacing.X())
+	} else {
+		items := make([]gridTrackItem, 0, len(cells)+len(colWidths))
+		for i, w := range colWidths {
+			items = append(items, gridTrackItem{pos: i, span: 1, size: w})
+		}
+		for _, c := range cells {
+			w := c.panel.explicitTableSize().X()
+			if w <= 0 {
+				w = tableCellContentWidth(c.panel)
+			}
+			items = append(items, gridTrackItem{pos: c.slot.col, span: c.slot.colSpan, size: w})
+		}
+		widths = sizeGridTracks(nil, colCount, 0, items, -1, spacing.X())
+		fitTableColumns(widths, available-2*spacing.X(), spacing.X(), maxWidth-2*spacing.X())
+	}
+	var overlapX, overlapY []float32
+	if style.collapse {
+		overlapX = tableOverlaps(colCount, cells, true, p.layout.border.Left())
+		overlapY = tableOverlaps(len(rowPanels), cells, false, p.layout.border.Top())
+	}
+	colStarts, gridWidth := tableTrackStarts(widths, overlapX, spacing.X(), style.collapse)
+	if available >= 0 {
+		gridWidth = max(gridWidth, available)
+	}
+	// Cells take the width of their columns before their heights are read,
+	// the heights are from the content fitted in the last layout pass
+	rtl := p.ResolvedDirection() == DirectionRTL
+	for _, c := range cells {
+		t := c.panel.tableState()
+		w := tableSpanSize(colStarts, widths, c.slot.col, c.slot.colSpan)
+		t.size.SetX(max(1, w))
+		c.panel.layout.ScaleWidth(t.size.X())
+	}
+	rowItems := make([]gridTrackItem, 0, len(cells)+len(rowPanels))
+	for r, row := range rowPanels {
+		if h := row.explicitTableSize().Y(); h > 0 {
+			rowItems = append(rowItems, gridTrackItem{pos: r, span: 1, size: h})
+		}
+	}
+	for _, c := range cells {
+		h := c.panel.explicitTableSize().Y()
+		if t := c.panel.tableState(); h <= 0 {
+			h = t.contentHeight
+		}
+		rowItems = append(rowItems, gridTrackItem{pos: c.slot.row, span: c.slot.rowSpan, size: h})
+	}
+	heights := sizeGridTracks(nil, len(rowPanels), 0, rowItems, -1, spacing.Y())
+	rowStarts, gridHeight := tableTrackStarts(heights, overlapY, spacing.Y(), style.collapse)
+	startX := offsetStart.X() + innerLeft
+	y := offsetStart.Y() + innerTop
+	placeCaptions := func(bottom bool) {
+		for _, caption := range captions {
+			if style.captionBottom != bottom {
+				continue
+			}
+			m := caption.layout.margin
+			t := caption.tableState()
+			t.placed = true
+			t.size = matrix.Vec2{max(1, gridWidth-m.Horizontal()), 0}
+			caption.layout.SetRowLayoutOffset(matrix.Vec2{startX + m.Left(), y + m.Top()})
+			caption.layout.ScaleWidth(t.size.X())
+			y += caption.layout.PixelSize().Y() + m.Vertical()
+		}
+	}
+	placeCaptions(false)
+	gridTop := y
+	rowIndex := 0
+	for _, g := range groups {
+		if len(g.rows) == 0 {
+			if g.panel != nil {
+				g.panel.tableState().placed = true
+			}
+			continue
+		}
+		first := rowIndex
+		last := rowIndex + len(g.rows) - 1
+		groupTop := rowStarts[first]
+		groupHeight := rowStarts[last] + heights[last] - groupTop
+		// Rows are placed within their group, the rows directly in the
+		// table are placed within the table
+		rowOrigin := matrix.Vec2{startX, gridTop}
+		if g.panel != nil {
+			placeTablePart(g.panel, startX, gridTop+groupTop, gridWidth, groupHeight)
+			rowOrigin = matrix.Vec2{0, -groupTop}
+		}
+		for r := first; r <= last; r++ {
+			placeTablePart(rowPanels[r], rowOrigin.X(), rowOrigin.Y()+rowStarts[r], gridWidth, heights[r])
+		}
+		rowIndex = last + 1
+	}
+	for _, c := range cells {
+		t := c.panel.tableState()
+		w := t.size.X()
+		h := tableSpanSize(rowStarts, heights, c.slot.row, c.slot.rowSpan)
+		x := colStarts[c.slot.col]
+		if rtl {
+			x = gridWidth - x - w
+		}
+		t.placed = true
+		t.size = matrix.Vec2{w, max(1, h)}
+		c.panel.layout.SetRowLayoutOffset(matrix.Vec2{x, 0})
+		c.panel.layout.ScaleHeight(t.size.Y())
+		t.emptyHidden = (t.hideEmpty || style.hideEmpty) && tableCellIsEmpty(c.panel)
+	}
+	y = gridTop + gridHeight
+	placeCaptions(true)
+	return matrix.Vec2{gridWidth, y - offsetStart.Y()}
+}
@@ -0,0 +1,92 @@
+/******************************************************************************/
+/* table_test.go                                                              */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"testing"
+
+	"kaijuengine.com/matrix"
+)
+
+func TestPlaceTableCellsSpans(t *testing.T) {
+	// | a (rowspan 2) | b (colspan 2)  |
+	// |               | c      | d     |
+	// | e             | f      | g     |
+	rows := [][]tableSpan{
+		{{cols: 1, rows: 2}, {cols: 2, rows: 1}},
+		{{cols: 1, rows: 1}, {cols: 1, rows: 1}},
+		{{cols: 1, rows: 1}, {cols: 1, rows: 1}, {cols: 1, rows: 1}},
+	}
+	slots, columns := placeTableCells(rows, []int{3, 3, 3})
+	if columns != 3 {
+		t.Fatalf("expected 3 columns, got %d", columns)
+	}
+	want := [][]tableSlot{
+		{{0, 0, 2, 1}, {0, 1, 1, 2}},
+		{{1, 1, 1, 1}, {1, 2, 1, 1}},
+		{{2, 0, 1, 1}, {2, 1, 1, 1}, {2, 2, 1, 1}},
+	}
+	for r := range want {
+		for i := range want[r] {
+			if slots[r][i] != want[r][i] {
+				t.Errorf("row %d cell %d: expected %v, got %v", r, i, want[r][i], slots[r][i])
+			}
+		}
+	}
+}
+
+func TestPlaceTableCellsRowSpanStopsAtGroup(t *testing.T) {
+	rows := [][]tableSpan{{{cols: 1, rows: 5}}, {{cols: 1, rows: 1}}}
+	slots, _ := placeTableCells(rows, []int{1, 2})
+	if slots[0][0].rowSpan != 1 {
+		t.Errorf("expected the rowspan to stop at the end of the group, got %d", slots[0][0].rowSpan)
+	}
+	if slots[1][0].col != 0 {
+		t.Errorf("expected the next group to start in the first column, got %d", slots[1][0].col)
+	}
+}
+
+func TestSizeFixedTableColumns(t *testing.T) {
+	sizes := sizeFixedTableColumns([]float32{100, 0, 0}, 310, 5)
+	want := []float32{100, 100, 100}
+	for i := range want {
+		if !matrix.Approx(sizes[i], want[i]) {
+			t.Errorf("column %d: expected %f, got %f", i, want[i], sizes[i])
+		}
+	}
+}
+
+func TestFitTableColumns(t *testing.T) {
+	sizes := []float32{50, 150}
+	fitTableColumns(sizes, 400, 0, -1)
+	if !matrix.Approx(sizes[0], 100) || !matrix.Approx(sizes[1], 300) {
+		t.Errorf("expected the columns to grow in proportion, got %v", sizes)
+	}
+	sizes = []float32{50, 150}
+	fitTableColumns(sizes, -1, 0, -1)
+	if !matrix.Approx(sizes[0], 50) || !matrix.Approx(sizes[1], 150) {
+		t.Errorf("expected a content sized table to keep its columns, got %v", sizes)
+	}
+	fitTableColumns(sizes, -1, 0, 100)
+	if !matrix.Approx(sizes[0], 25) || !matrix.Approx(sizes[1], 75) {
+		t.Errorf("expected the columns to shrink to the max width, got %v", sizes)
+	}
+}
+
+func TestTableTrackStarts(t *testing.T) {
+	starts, end := tableTrackStarts([]float32{10, 20}, nil, 2, false)
+	if !matrix.Approx(starts[0], 2) || !matrix.Approx(starts[1], 14) || !matrix.Approx(end, 36) {
+		t.Errorf("separated: expected starts [2 14] and end 36, got %v %f", starts, end)
+	}
+	starts, end = tableTrackStarts([]float32{10, 20}, []float32{1, 1}, 2, true)
+	if !matrix.Approx(starts[0], -1) || !matrix.Approx(starts[1], 8) || !matrix.Approx(end, 28) {
+		t.Errorf("collapsed: expected starts [-1 8] and end 28, got %v %f", starts, end)
+	}
+	if w := tableSpanSize(starts, []float32{10, 20}, 0, 2); !matrix.Approx(w, 29) {
+		t.Errorf("expected a span over both columns to be 29, got %f", w)
+	}
+}
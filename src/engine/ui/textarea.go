@@ -1582,6 +1582,12 @@ func (textarea *TextArea) SetFGColor(newColor matrix.Color) {
 	data.list.RefreshVisible()
 }
 
+// SetPlaceholderColor overrides the placeholder text color, which otherwise
+// follows the foreground color set with SetFGColor
+func (textarea *TextArea) SetPlaceholderColor(newColor matrix.Color) {
+	textarea.Data().placeholder.SetColor(newColor)
+}
+
 func (textarea *TextArea) SetBGColor(newColor matrix.Color) {
 	data := textarea.Data()
 	data.bgColor = newColor
@@ -125,6 +125,8 @@ const (
 	ElementTypeSlider
 	ElementTypeTextArea
 	ElementTypeVirtualList
+	ElementTypeCanvas
+	ElementTypeVideo
 )
 
 const (
@@ -156,6 +158,7 @@ type UI struct {
 	elmType          ElementType
 	dirtyType        DirtyType
 	shaderData       *ShaderData
+	renderTransform  *uiRenderTransform
 	textureSize      matrix.Vec2
 	lastClick        float64
 	poolId           pooling.PoolGroupId
@@ -204,6 +207,7 @@ func (ui *UI) init(textureSize matrix.Vec2) {
 		ShaderDataBase: rendering.NewShaderDataBase(),
 	}
 	ui.shaderData.Scissor = matrix.Vec4{-matrix.FloatMax, -matrix.FloatMax, matrix.FloatMax, matrix.FloatMax}
+	ui.renderTransform = nil
 	ui.entity.AddNamedData(EntityDataName, ui)
 	ui.textureSize = textureSize
 	ui.layout.initialize(ui)
@@ -252,6 +256,10 @@ func (ui *UI) Host() *engine.Host {
 	return nil
 }
 
+// Manager returns the manager that created this UI element, it will be nil
+// if the manager has since been released
+func (ui *UI) Manager() *Manager { return ui.man.Value() }
+
 func (ui *UI) SetDontClean(val bool) {
 	if val {
 		ui.flags.setDontClean()
@@ -503,6 +511,8 @@ func (ui *UI) Clean() {
 		}
 		tree[i].GenerateScissor()
 		tree[i].render()
+		tree[i].updateClip()
+		tree[i].updateRenderTransform()
 	}
 }
 
@@ -595,7 +605,7 @@ func (ui *UI) GenerateScissor() {
 		pos.Y() + size.Y()*0.5,
 	}
 	if !ui.IsType(ElementTypeLabel) {
-		outset := ui.ToPanel().OutlineOutset()
+		outset := ui.ToPanel().paintOutset()
 		bounds.SetX(bounds.X() - outset)
 		bounds.SetY(bounds.Y() - outset)
 		bounds.SetZ(bounds.Z() + outset)
@@ -638,6 +648,15 @@ func (ui *UI) setScissorInternal(scissor matrix.Vec4) {
 		for i := range ld.runeDrawings {
 			ld.runeDrawings[i].ShaderData.(*rendering.TextShaderData).Scissor = scissor
 		}
+		for _, layer := range ld.shadowLayers {
+			for _, sd := range layer.shaderData {
+				sd.Scissor = scissor
+			}
+		}
+	} else {
+		for _, sd := range me.ToPanel().PanelData().shadowShaderData {
+			sd.Scissor = scissor
+		}
 	}
 }
 
@@ -794,7 +813,7 @@ func (ui *UI) cursorPos(cursor *hid.Cursor) matrix.Vec2 {
 func (ui *UI) containedCheck(cursor *hid.Cursor, entity *engine.Entity) {
 	defer tracing.NewRegion("UI.containedCheck").End()
 	cp := ui.cursorPos(cursor)
-	contained := entity.Transform.ContainsPoint2D(cp)
+	contained := ui.ContainsPoint(cp)
 	if contained && ui.hasScissor() {
 		contained = ui.shaderData.Scissor.ScreenAreaContains(cp.X(), cp.Y())
 	}
@@ -894,6 +913,10 @@ func (ui *UI) updateFromManager(deltaTime float64) {
 		ui.ToSlider().update(deltaTime)
 	case ElementTypeImage:
 		ui.ToImage().update(deltaTime)
+	case ElementTypeCanvas:
+		ui.ToCanvas().update(deltaTime)
+	case ElementTypeVideo:
+		ui.ToVideo().update(deltaTime)
 	case ElementTypeCheckbox:
 		ui.ToPanel().update(deltaTime)
 	}
@@ -989,6 +1012,13 @@ func (ui *UI) Clone(parent *engine.Entity) *UI {
 		cpy.ToSelect().Init(t.SelectData().text, t.SelectData().options)
 	case ElementTypeSlider:
 		cpy.ToSlider().Init()
+	case ElementTypeCanvas:
+		ctx := ui.ToCanvas().Context()
+		cpyCanvas := cpy.ToCanvas()
+		cpyCanvas.Init(ctx.Width(), ctx.Height())
+		cpyCanvas.Context().PutImage(ctx.GetImage(0, 0, ctx.Width(), ctx.Height()), 0, 0)
+	case ElementTypeVideo:
+		cpy.ToVideo().cloneFrom(ui.ToVideo())
 	}
 	cpy.SetDisabled(ui.IsDisabled())
 	if parent != nil {
@@ -0,0 +1,118 @@
+/******************************************************************************/
+/* video.go                                                                   */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"log/slog"
+
+	"kaijuengine.com/engine/video"
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/platform/profiler/tracing"
+)
+
+// Video is a panel showing the texture of a video stream, the stream is
+// updated along with the element
+type Video Panel
+
+type videoData struct {
+	panelData
+	stream *video.Stream
+}
+
+func (v *videoData) innerPanelData() *panelData { return &v.panelData }
+
+func (u *UI) ToVideo() *Video { return (*Video)(u) }
+func (v *Video) Base() *UI    { return (*UI)(v) }
+
+func (v *Video) VideoData() *videoData {
+	return v.elmData.(*videoData)
+}
+
+// Init shows the stream in the element, which is the size of the video
+// unless it is styled otherwise. The stream is destroyed along with the
+// element. A nil stream shows nothing until SetStream is called
+func (v *Video) Init(stream *video.Stream) {
+	data := &videoData{}
+	v.elmData = data
+	v.Base().ToPanel().Init(nil, ElementTypeVideo)
+	v.SetStream(stream)
+	v.Base().AddEvent(EventTypeDestroy, func() {
+		if data.stream != nil {
+			data.stream.Destroy()
+		}
+	})
+}
+
+// SetStream changes the stream that is shown, destroying the stream that was
+// shown before
+func (v *Video) SetStream(stream *video.Stream) {
+	data := v.VideoData()
+	if data.stream == stream {
+		return
+	}
+	if data.stream != nil {
+		data.stream.Destroy()
+	}
+	data.stream = stream
+	// :playing and :paused styles are picked up when the element restyles
+	restyle := func() { v.Base().SetDirty(DirtyTypeGenerated) }
+	if stream == nil {
+		restyle()
+		return
+	}
+	stream.Player.OnPlay.Add(restyle)
+	stream.Player.OnPause.Add(restyle)
+	p := (*Panel)(v)
+	p.SetBackground(stream.Texture())
+	if p.shaderData != nil {
+		p.shaderData.BorderLen = matrix.Vec2Zero()
+	}
+	restyle()
+}
+
+func (v *Video) Stream() *video.Stream { return v.VideoData().stream }
+
+// Player is what plays, pauses, seeks and loops the video, it is nil when
+// the element has no stream
+func (v *Video) Player() *video.Player {
+	if s := v.VideoData().stream; s != nil {
+		return s.Player
+	}
+	return nil
+}
+
+func (v *Video) IsPlaying() bool {
+	p := v.Player()
+	return p != nil && p.IsPlaying()
+}
+
+func (v *Video) cloneFrom(other *Video) {
+	var stream *video.Stream
+	if from := other.Player(); from != nil {
+		var err error
+		stream, err = video.NewStream(v.man.Value().Host, from.Video())
+		if err != nil {
+			slog.Error("failed to clone the video stream", "error", err)
+		} else {
+			stream.Player.SetLoop(from.IsLooping())
+			stream.Player.SetMuted(from.IsMuted())
+			stream.Player.Seek(from.Time())
+			if from.IsPlaying() {
+				stream.Player.Play()
+			}
+		}
+	}
+	v.Init(stream)
+}
+
+func (v *Video) update(deltaTime float64) {
+	defer tracing.NewRegion("Video.update").End()
+	(*Panel)(v).update(deltaTime)
+	if s := v.VideoData().stream; s != nil {
+		s.Update(deltaTime)
+	}
+}
@@ -90,6 +90,7 @@ type virtualListData struct {
 	frame          uint64
 	needsFill      bool
 	lastWindowSize int
+	focused        int
 
 	lastFirst     int
 	lastLast      int
@@ -111,6 +112,7 @@ func (vl *VirtualList) Init() {
 		overscan:   4,
 		fixedModel: newFixedHeightModel(virtualListDefaultEstimate),
 		lastLast:   -1,
+		focused:    -1,
 	}
 	data.model = data.fixedModel
 	vl.elmData = data
@@ -207,6 +209,9 @@ func (vl *VirtualList) ReloadData() {
 	data := vl.Data()
 	n := vl.rowCount()
 	data.model.setCount(n)
+	if data.focused >= n {
+		data.focused = n - 1
+	}
 	vl.remeasureAll()
 	vl.recycleAll()
 	vl.clampScroll()
@@ -276,6 +281,78 @@ func (vl *VirtualList) ScrollToIndex(index int, align VirtualAlign) {
 	(*Panel)(vl).SetScrollY(target)
 }
 
+// FocusedIndex returns the data index of the row that has the navigation
+// focus, or -1. Delegates read it in BindRow to draw the focused row
+func (vl *VirtualList) FocusedIndex() int { return vl.Data().focused }
+
+// SetFocusedIndex moves the navigation focus to the row, scrolling the least
+// amount needed to show it, and rebinds the visible rows. Passing -1 clears
+// the focus
+func (vl *VirtualList) SetFocusedIndex(index int) {
+	data := vl.Data()
+	if index < 0 || vl.rowCount() == 0 {
+		index = -1
+	} else {
+		index = min(index, vl.rowCount()-1)
+	}
+	if data.focused == index {
+		return
+	}
+	data.focused = index
+	if index >= 0 {
+		vl.ScrollToIndex(index, VirtualAlignNearest)
+	}
+	vl.RefreshVisible()
+}
+
+// MoveFocus moves the navigation focus by delta rows. It returns false when
+// the focus is already on the first or last row in that direction, so that
+// the caller can move the focus out of the list instead
+func (vl *VirtualList) MoveFocus(delta int) bool {
+	first, _ := vl.VisibleRange()
+	next, ok := virtualFocusStep(vl.Data().focused, first, delta, vl.rowCount())
+	if ok {
+		vl.SetFocusedIndex(next)
+	}
+	return ok
+}
+
+func virtualFocusStep(current, first, delta, n int) (int, bool) {
+	if n == 0 || delta == 0 {
+		return current, false
+	}
+	if current < 0 {
+		return min(max(first, 0), n-1), true
+	}
+	next := min(max(current+delta, 0), n-1)
+	return next, next != current
+}
+
+// RowIndexOf returns the data index of the realized row that holds the
+// element (the row itself or anything within it), or -1
+func (vl *VirtualList) RowIndexOf(elm *UI) int {
+	data := vl.Data()
+	for e := elm.Entity(); e != nil; e = e.Parent {
+		for idx, row := range data.active {
+			if row.Entity() == e {
+				return idx
+			}
+		}
+		if e == vl.Base().Entity() {
+			break
+		}
+	}
+	return -1
+}
+
+// ScrollIntoView scrolls the least amount needed to show the row that holds
+// the element, it does nothing if the element isn't in a realized row
+func (vl *VirtualList) ScrollIntoView(elm *UI) {
+	if idx := vl.RowIndexOf(elm); idx >= 0 {
+		vl.ScrollToIndex(idx, VirtualAlignNearest)
+	}
+}
+
 func (vl *VirtualList) onLayoutUpdating() {
 	vl.reflow(false)
 }
@@ -65,3 +65,24 @@ func TestVirtualWindowVariableHeights(t *testing.T) {
 	assertEqualI(t, first, 1, "first") // indexAt(35)=1
 	assertEqualI(t, last, 2, "last")   // indexAt(135)=2
 }
+
+func TestVirtualFocusStep(t *testing.T) {
+	next, ok := virtualFocusStep(-1, 12, 1, 100)
+	assertEqualI(t, next, 12, "unfocused starts at the first visible row")
+	if !ok {
+		t.Fatal("expected the focus to move onto the list")
+	}
+	next, ok = virtualFocusStep(5, 0, -1, 100)
+	assertEqualI(t, next, 4, "up")
+	next, ok = virtualFocusStep(99, 0, 1, 100)
+	assertEqualI(t, next, 99, "last row stays")
+	if ok {
+		t.Fatal("moving past the last row should report no movement")
+	}
+	if _, ok = virtualFocusStep(0, 0, -1, 100); ok {
+		t.Fatal("moving before the first row should report no movement")
+	}
+	if _, ok = virtualFocusStep(-1, 0, 1, 0); ok {
+		t.Fatal("an empty list can't take the focus")
+	}
+}
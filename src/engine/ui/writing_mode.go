@@ -0,0 +1,201 @@
+/******************************************************************************/
+/* writing_mode.go                                                            */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import (
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering"
+)
+
+type Direction = int
+type WritingMode = int
+type TextOrientation = int
+type Side = int
+
+const (
+	DirectionInherit = Direction(iota)
+	DirectionLTR
+	DirectionRTL
+)
+
+const (
+	WritingModeInherit = WritingMode(iota)
+	WritingModeHorizontalTB
+	WritingModeVerticalRL
+	WritingModeVerticalLR
+)
+
+const (
+	TextOrientationInherit = TextOrientation(iota)
+	TextOrientationMixed
+	TextOrientationUpright
+	TextOrientationSideways
+)
+
+// Sides are in the same order as the margin, padding and border vectors
+const (
+	SideLeft = Side(iota)
+	SideTop
+	SideRight
+	SideBottom
+)
+
+func IsVerticalWritingMode(mode WritingMode) bool {
+	return mode == WritingModeVerticalRL || mode == WritingModeVerticalLR
+}
+
+// InlineSides returns the physical sides that the inline start and end map to
+// for the writing mode and direction, vertical text flows from the top unless
+// the direction is right-to-left
+func InlineSides(mode WritingMode, direction Direction) (start, end Side) {
+	rtl := direction == DirectionRTL
+	if IsVerticalWritingMode(mode) {
+		if rtl {
+			return SideBottom, SideTop
+		}
+		return SideTop, SideBottom
+	}
+	if rtl {
+		return SideRight, SideLeft
+	}
+	return SideLeft, SideRight
+}
+
+// BlockSides returns the physical sides that the block start and end map to
+// for the writing mode
+func BlockSides(mode WritingMode) (start, end Side) {
+	switch mode {
+	case WritingModeVerticalRL:
+		return SideRight, SideLeft
+	case WritingModeVerticalLR:
+		return SideLeft, SideRight
+	default:
+		return SideTop, SideBottom
+	}
+}
+
+func textDirection(direction Direction) rendering.TextDirection {
+	if direction == DirectionRTL {
+		return rendering.TextDirectionRTL
+	}
+	return rendering.TextDirectionLTR
+}
+
+func (p *Panel) Direction() Direction { return p.PanelData().direction }
+
+// SetDirection sets the inline direction of the panel's content, children of
+// a right-to-left panel are laid out from the right edge and text within it
+// is ordered with the Unicode Bidirectional Algorithm from a right-to-left
+// base. DirectionInherit uses the direction of the parent panel
+func (p *Panel) SetDirection(direction Direction) {
+	pd := p.PanelData()
+	if pd.direction == direction {
+		return
+	}
+	pd.direction = direction
+	p.Base().SetDirty(DirtyTypeLayout)
+	p.dirtyChildLabels()
+}
+
+// ResolvedDirection walks up the panels until one has a direction set, it
+// returns DirectionInherit if none of them do
+func (p *Panel) ResolvedDirection() Direction {
+	for p != nil {
+		if d := p.PanelData().direction; d != DirectionInherit {
+			return d
+		}
+		p = p.parentPanel()
+	}
+	return DirectionInherit
+}
+
+func (p *Panel) WritingMode() WritingMode { return p.PanelData().writingMode }
+
+// SetWritingMode sets if lines of text are laid out horizontally or
+// vertically and in which direction the blocks progress
+func (p *Panel) SetWritingMode(mode WritingMode) {
+	pd := p.PanelData()
+	if pd.writingMode == mode {
+		return
+	}
+	pd.writingMode = mode
+	p.Base().SetDirty(DirtyTypeLayout)
+	p.dirtyChildLabels()
+}
+
+// ResolvedWritingMode walks up the panels until one has a writing mode set,
+// it is horizontal-tb if none of them do
+func (p *Panel) ResolvedWritingMode() WritingMode {
+	for p != nil {
+		if m := p.PanelData().writingMode; m != WritingModeInherit {
+			return m
+		}
+		p = p.parentPanel()
+	}
+	return WritingModeHorizontalTB
+}
+
+func (p *Panel) TextOrientation() TextOrientation {
+	return p.PanelData().textOrientation
+}
+
+// SetTextOrientation sets how the glyphs of vertical text are oriented. Only
+// upright glyphs are drawn so far, mixed and sideways are stored for when the
+// font cache can rotate them
+func (p *Panel) SetTextOrientation(orientation TextOrientation) {
+	pd := p.PanelData()
+	if pd.textOrientation == orientation {
+		return
+	}
+	pd.textOrientation = orientation
+	p.dirtyChildLabels()
+}
+
+func (p *Panel) parentPanel() *Panel {
+	if p.entity.IsRoot() {
+		return nil
+	}
+	parent := FirstOnEntity(p.entity.Parent)
+	if parent == nil || parent.IsType(ElementTypeLabel) {
+		return nil
+	}
+	return parent.ToPanel()
+}
+
+func (p *Panel) dirtyChildLabels() {
+	for _, kid := range p.entity.Children {
+		if kui := FirstOnEntity(kid); kui != nil && kui.IsType(ElementTypeLabel) {
+			kui.ToLabel().LabelData().renderRequired = true
+			kui.SetDirty(DirtyTypeGenerated)
+		}
+	}
+}
+
+// inlineMirror flips the horizontal position of children across the content
+// box of a right-to-left panel so they flow from its right edge
+type inlineMirror struct {
+	rtl    bool
+	startX float32
+	width  float32
+}
+
+func (p *Panel) inlineMirror(offsetStart, ps matrix.Vec2) inlineMirror {
+	return inlineMirror{
+		rtl:    p.ResolvedDirection() == DirectionRTL,
+		startX: offsetStart.X() + p.layout.padding.Left() + p.layout.border.Left(),
+		width:  ps.X() - p.layout.padding.Horizontal() - p.layout.border.Horizontal(),
+	}
+}
+
+// x takes the left-to-right position of the child and returns where it goes,
+// the margins swap sides as the child is mirrored
+func (m inlineMirror) x(l *Layout, x float32) float32 {
+	if !m.rtl {
+		return x
+	}
+	return 2*m.startX + m.width - x - l.PixelSize().X() + l.margin.Left() - l.margin.Right()
+}
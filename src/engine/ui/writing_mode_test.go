@@ -0,0 +1,54 @@
+/******************************************************************************/
+/* writing_mode_test.go                                                       */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package ui
+
+import "testing"
+
+func TestLogicalSides(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mode                   WritingMode
+		direction              Direction
+		inlineStart, inlineEnd Side
+		blockStart, blockEnd   Side
+	}{
+		{WritingModeHorizontalTB, DirectionLTR, SideLeft, SideRight, SideTop, SideBottom},
+		{WritingModeHorizontalTB, DirectionRTL, SideRight, SideLeft, SideTop, SideBottom},
+		{WritingModeVerticalRL, DirectionLTR, SideTop, SideBottom, SideRight, SideLeft},
+		{WritingModeVerticalLR, DirectionRTL, SideBottom, SideTop, SideLeft, SideRight},
+	}
+	for _, test := range tests {
+		start, end := InlineSides(test.mode, test.direction)
+		if start != test.inlineStart || end != test.inlineEnd {
+			t.Errorf("mode %d direction %d inline sides = %d, %d, want %d, %d",
+				test.mode, test.direction, start, end, test.inlineStart, test.inlineEnd)
+		}
+		start, end = BlockSides(test.mode)
+		if start != test.blockStart || end != test.blockEnd {
+			t.Errorf("mode %d block sides = %d, %d, want %d, %d",
+				test.mode, start, end, test.blockStart, test.blockEnd)
+		}
+	}
+}
+
+func TestInlineMirrorFlipsAcrossContentBox(t *testing.T) {
+	t.Parallel()
+
+	child := testLayoutUI(20, 10)
+	layout := child.Layout()
+	layout.SetMargin(2, 0, 6, 0)
+	mirror := inlineMirror{rtl: true, startX: 5, width: 100}
+	// Placed left-to-right the border box starts after the left margin, so in
+	// right-to-left it ends before the right margin at the content box edge
+	if got := mirror.x(layout, 5+2); got != 5+100-6-20 {
+		t.Errorf("mirrored x = %f, want %d", got, 5+100-6-20)
+	}
+	if got := (inlineMirror{startX: 5, width: 100}).x(layout, 7); got != 7 {
+		t.Errorf("left-to-right x = %f, want 7", got)
+	}
+}
@@ -0,0 +1,219 @@
+/******************************************************************************/
+/* player.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package video
+
+import (
+	"fmt"
+	"image"
+	"log/slog"
+	"math"
+
+	"kaijuengine.com/engine/systems/events"
+	"kaijuengine.com/platform/audio"
+)
+
+// audioSyncTolerance is how far (in seconds) the video can drift from its
+// audio before it jumps to the time of the audio
+const audioSyncTolerance = 0.1
+
+// Player keeps the time of a video and decodes the frame for that time as it
+// is updated. When it is given the audio system, the audio track of the video
+// is played along with it and the video follows the time of the audio.
+type Player struct {
+	OnPlay        events.Event
+	OnPause       events.Event
+	OnEnded       events.Event
+	video         *Video
+	frame         *image.RGBA
+	current       int
+	time          float64
+	playing       bool
+	ended         bool
+	loop          bool
+	muted         bool
+	audio         *audio.Audio
+	clip          *audio.AudioClip
+	voice         audio.VoiceHandle
+	reportedError bool
+}
+
+// NewPlayer creates a paused player at the start of the video
+func NewPlayer(video *Video) *Player {
+	return &Player{
+		video:   video,
+		frame:   image.NewRGBA(image.Rect(0, 0, video.Width, video.Height)),
+		current: -1,
+	}
+}
+
+func (p *Player) Video() *Video { return p.video }
+
+// Frame is the image of the frame that was decoded by the last update
+func (p *Player) Frame() *image.RGBA { return p.frame }
+
+// Time is how far (in seconds) the player is into the video
+func (p *Player) Time() float64     { return p.time }
+func (p *Player) Duration() float64 { return p.video.Duration() }
+func (p *Player) IsPlaying() bool   { return p.playing }
+func (p *Player) IsPaused() bool    { return !p.playing }
+
+// IsEnded is true when the player stopped because it reached the end of a
+// video that doesn't loop
+func (p *Player) IsEnded() bool   { return p.ended }
+func (p *Player) IsLooping() bool { return p.loop }
+func (p *Player) IsMuted() bool   { return p.muted }
+
+// SetAudio plays the audio track of the video through the audio system, if
+// the video has one. Each player loads its own clip of the track, so players
+// of the same video can be destroyed without cutting off each other's audio.
+func (p *Player) SetAudio(a *audio.Audio) error {
+	p.stopAudio()
+	if p.clip != nil {
+		p.audio.UnloadClip(p.clip)
+	}
+	p.audio, p.clip = nil, nil
+	if a == nil || !p.video.HasAudio() {
+		return nil
+	}
+	clip, err := a.LoadMusicData(fmt.Sprintf("video_%p", p), p.video.Audio())
+	if err != nil {
+		return err
+	}
+	p.audio, p.clip = a, clip
+	if p.playing {
+		p.startAudio()
+	}
+	return nil
+}
+
+// Play starts the video from where it is, a video that ended starts over
+func (p *Player) Play() {
+	if p.playing || p.video.FrameCount() == 0 {
+		return
+	}
+	if p.ended {
+		p.time = 0
+		p.ended = false
+	}
+	p.playing = true
+	if p.hasVoice() {
+		p.audio.SetPaused(p.voice, false)
+	} else {
+		p.startAudio()
+	}
+	p.OnPlay.Execute()
+}
+
+func (p *Player) Pause() {
+	if !p.playing {
+		return
+	}
+	p.playing = false
+	if p.hasVoice() {
+		p.audio.SetPaused(p.voice, true)
+	}
+	p.OnPause.Execute()
+}
+
+// Seek moves the player to the time (in seconds), the frame for that time is
+// decoded on the next update
+func (p *Player) Seek(seconds float64) {
+	p.time = max(0, min(seconds, p.Duration()))
+	p.ended = false
+	if p.hasVoice() {
+		p.audio.Seek(p.voice, p.time)
+	}
+}
+
+// SetLoop makes the video start over when it reaches the end instead of
+// stopping
+func (p *Player) SetLoop(loop bool) { p.loop = loop }
+
+// SetMuted stops playing the audio track of the video
+func (p *Player) SetMuted(muted bool) {
+	if p.muted == muted {
+		return
+	}
+	p.muted = muted
+	if muted {
+		p.stopAudio()
+	} else if p.playing {
+		p.startAudio()
+	}
+}
+
+// Update moves the time of the player forward if it is playing and decodes
+// the frame for the time, it returns true if the frame image has changed
+func (p *Player) Update(deltaTime float64) bool {
+	if p.playing {
+		p.time += deltaTime
+		if p.hasVoice() {
+			if pos := p.audio.StreamPosition(p.voice); math.Abs(pos-p.time) > audioSyncTolerance {
+				p.time = pos
+			}
+		}
+		if duration := p.Duration(); p.time >= duration {
+			if p.loop && duration > 0 {
+				p.time = math.Mod(p.time, duration)
+				p.stopAudio()
+				p.startAudio()
+			} else {
+				p.time = duration
+				p.playing = false
+				p.ended = true
+				p.stopAudio()
+				p.OnPause.Execute()
+				p.OnEnded.Execute()
+			}
+		}
+	}
+	index := p.video.FrameAt(p.time)
+	if index < 0 || index == p.current {
+		return false
+	}
+	if err := p.video.DecodeFrame(index, p.frame); err != nil {
+		// A broken frame would otherwise be reportedError on every update
+		if !p.reportedError {
+			slog.Error("failed to show the video frame", "error", err)
+			p.reportedError = true
+		}
+		return false
+	}
+	p.current = index
+	return true
+}
+
+// Destroy stops the audio of the player and unloads its audio track
+func (p *Player) Destroy() {
+	p.stopAudio()
+	if p.clip != nil {
+		p.audio.UnloadClip(p.clip)
+		p.clip = nil
+	}
+	p.playing = false
+}
+
+func (p *Player) hasVoice() bool {
+	return p.voice != audio.InvalidVoiceHandle && p.audio.IsValidVoiceHandle(p.voice)
+}
+
+func (p *Player) startAudio() {
+	if p.clip == nil || p.muted {
+		return
+	}
+	p.voice = p.audio.Play(p.clip)
+	if p.time > 0 {
+		p.audio.Seek(p.voice, p.time)
+	}
+}
+
+func (p *Player) stopAudio() {
+	if p.hasVoice() {
+		p.audio.Stop(p.voice)
+	}
+	p.voice = audio.InvalidVoiceHandle
+}
@@ -0,0 +1,86 @@
+/******************************************************************************/
+/* player_test.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package video
+
+import "testing"
+
+func TestPlayerShowsFrameForTime(t *testing.T) {
+	p := NewPlayer(decodeTestVideo(t, 4))
+	if !p.Update(0) || !colorsNear(p.Frame().RGBAAt(0, 0), testFrameColors[0]) {
+		t.Fatal("expected the first frame to be decoded on the first update")
+	}
+	if p.Update(0.5) {
+		t.Error("expected a paused player to keep its frame")
+	}
+	p.Play()
+	if !p.Update(0.5) || !colorsNear(p.Frame().RGBAAt(0, 0), testFrameColors[2]) {
+		t.Errorf("expected the third frame at 0.5 seconds, got %v", p.Frame().RGBAAt(0, 0))
+	}
+	if p.Update(0.1) {
+		t.Error("expected no new frame within the same frame time")
+	}
+	p.Seek(0.25)
+	if !p.Update(0) || !colorsNear(p.Frame().RGBAAt(0, 0), testFrameColors[1]) {
+		t.Errorf("expected the second frame after seeking, got %v", p.Frame().RGBAAt(0, 0))
+	}
+}
+
+func TestPlayerStopsAtEnd(t *testing.T) {
+	p := NewPlayer(decodeTestVideo(t, 4))
+	paused, ended := 0, 0
+	p.OnPause.Add(func() { paused++ })
+	p.OnEnded.Add(func() { ended++ })
+	p.Play()
+	p.Update(1.5)
+	if p.IsPlaying() || !p.IsEnded() || p.Time() != 1 {
+		t.Fatalf("expected the player to end at 1 second, playing %v, ended %v at %g",
+			p.IsPlaying(), p.IsEnded(), p.Time())
+	}
+	if paused != 1 || ended != 1 {
+		t.Errorf("expected to pause and end once, paused %d and ended %d times", paused, ended)
+	}
+	if !colorsNear(p.Frame().RGBAAt(0, 0), testFrameColors[3]) {
+		t.Errorf("expected the last frame to stay shown, got %v", p.Frame().RGBAAt(0, 0))
+	}
+	p.Play()
+	if p.Time() != 0 || p.IsEnded() {
+		t.Error("expected playing an ended video to start it over")
+	}
+}
+
+func TestPlayerLoops(t *testing.T) {
+	p := NewPlayer(decodeTestVideo(t, 4))
+	p.SetLoop(true)
+	p.Play()
+	p.Update(1.3)
+	if !p.IsPlaying() || p.IsEnded() {
+		t.Fatal("expected a looping player to keep playing")
+	}
+	if p.Time() < 0.29 || p.Time() > 0.31 {
+		t.Errorf("expected the time to wrap to 0.3, got %g", p.Time())
+	}
+	if !colorsNear(p.Frame().RGBAAt(0, 0), testFrameColors[1]) {
+		t.Errorf("expected the second frame after looping, got %v", p.Frame().RGBAAt(0, 0))
+	}
+}
+
+func TestPlayerPlayPauseEvents(t *testing.T) {
+	p := NewPlayer(decodeTestVideo(t, 4))
+	played, paused := 0, 0
+	p.OnPlay.Add(func() { played++ })
+	p.OnPause.Add(func() { paused++ })
+	p.Pause()
+	p.Play()
+	p.Play()
+	p.Pause()
+	if played != 1 || paused != 1 || !p.IsPaused() {
+		t.Errorf("expected one play and one pause, got %d and %d", played, paused)
+	}
+	if err := p.SetAudio(nil); err != nil {
+		t.Error(err)
+	}
+}
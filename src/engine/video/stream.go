@@ -0,0 +1,113 @@
+/******************************************************************************/
+/* stream.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package video
+
+import (
+	"fmt"
+	"log/slog"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/platform/profiler/tracing"
+	"kaijuengine.com/rendering"
+)
+
+// Stream plays a video into a texture, which can be used by materials and UI
+// like any other texture. The stream is updated by whatever shows it, or by
+// the host after calling Start.
+type Stream struct {
+	Player   *Player
+	host     *engine.Host
+	texture  *rendering.Texture
+	key      string
+	updateId engine.UpdateId
+	pending  bool
+	// uploads are swapped between so that the frame being decoded isn't the
+	// one waiting to be written on the render thread
+	uploads [2][]byte
+	upload  int
+}
+
+// Load reads the video file from the asset database and creates a stream
+// for it
+func Load(host *engine.Host, key string) (*Stream, error) {
+	data, err := host.AssetDatabase().Read(key)
+	if err != nil {
+		return nil, err
+	}
+	v, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the video %s: %w", key, err)
+	}
+	return NewStream(host, v)
+}
+
+// NewStream creates a paused stream showing the first frame of the video
+func NewStream(host *engine.Host, video *Video) (*Stream, error) {
+	s := &Stream{Player: NewPlayer(video), host: host}
+	if err := s.Player.SetAudio(host.Audio()); err != nil {
+		slog.Warn("the video will play without audio", "error", err)
+	}
+	s.Player.Update(0)
+	s.key = fmt.Sprintf("video_%p_%dx%d", s, video.Width, video.Height)
+	pixels := make([]byte, len(s.Player.Frame().Pix))
+	copy(pixels, s.Player.Frame().Pix)
+	tex, err := host.TextureCache().InsertRawTexture(s.key, pixels,
+		video.Width, video.Height, rendering.TextureFilterLinear)
+	if err != nil {
+		return nil, err
+	}
+	s.texture = tex
+	return s, nil
+}
+
+func (s *Stream) Texture() *rendering.Texture { return s.texture }
+
+// Start updates the stream with the host every frame, for streams that are
+// not updated by what shows them
+func (s *Stream) Start() {
+	if !s.updateId.IsValid() {
+		s.updateId = s.host.Updater.AddUpdate(s.Update)
+	}
+}
+
+// Update moves the player forward and writes the new frame into the
+// texture, once the texture is on the GPU
+func (s *Stream) Update(deltaTime float64) {
+	defer tracing.NewRegion("video.Stream.Update").End()
+	if s.Player.Update(deltaTime) {
+		s.pending = true
+	}
+	if !s.pending || !s.texture.RenderId.IsValid() {
+		return
+	}
+	s.pending = false
+	frame := s.Player.Frame()
+	pixels := s.uploads[s.upload]
+	if len(pixels) != len(frame.Pix) {
+		pixels = make([]byte, len(frame.Pix))
+		s.uploads[s.upload] = pixels
+	}
+	copy(pixels, frame.Pix)
+	s.upload = (s.upload + 1) % len(s.uploads)
+	request := rendering.GPUImageWriteRequest{
+		Region: matrix.Vec4i{0, 0, int32(frame.Rect.Dx()), int32(frame.Rect.Dy())},
+		Pixels: pixels,
+	}
+	tex := s.texture
+	s.host.RunOnRenderThread(func(device *rendering.GPUDevice) {
+		tex.WritePixels(device, []rendering.GPUImageWriteRequest{request})
+	})
+}
+
+// Destroy stops the stream and removes its texture from the texture cache
+func (s *Stream) Destroy() {
+	s.host.Updater.RemoveUpdate(&s.updateId)
+	s.updateId = 0
+	s.Player.Destroy()
+	s.host.TextureCache().ForceRemoveTexture(s.key, rendering.TextureFilterLinear)
+}
@@ -0,0 +1,126 @@
+/******************************************************************************/
+/* video.go                                                                   */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+// Package video plays Kaiju video files (.kvid) into textures, with their
+// audio played through the audio system of the host. A video file is a list
+// of JPEG frames, shown at a fixed frame rate, and an optional WAV track.
+// Every frame can be decoded on its own, so seeking to any time is as fast as
+// showing the next frame.
+package video
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"math"
+)
+
+const (
+	fileVersion = 1
+	headerSize  = 4 + 2 + 2 + 4 + 4 + 4
+	trailerSize = 4 + 8 + 8 + 8 + 4
+	indexSize   = 8 + 4
+)
+
+var fileMagic = [4]byte{'K', 'V', 'I', 'D'}
+
+type frameEntry struct {
+	offset uint64
+	size   uint32
+}
+
+// Video is a decoded video file, the frames stay compressed until they are
+// decoded with DecodeFrame
+type Video struct {
+	Width     int
+	Height    int
+	FrameRate float64
+	data      []byte
+	frames    []frameEntry
+	audio     []byte
+}
+
+// Decode reads a video file. The data is kept by the video, the frames are
+// read out of it as they are decoded
+func Decode(data []byte) (*Video, error) {
+	if len(data) < headerSize+trailerSize || !bytes.Equal(data[:4], fileMagic[:]) ||
+		!bytes.Equal(data[len(data)-4:], fileMagic[:]) {
+		return nil, errors.New("the data is not a video file")
+	}
+	le := binary.LittleEndian
+	if version := le.Uint16(data[4:]); version != fileVersion {
+		return nil, fmt.Errorf("unsupported video file version %d", version)
+	}
+	v := &Video{
+		Width:     int(le.Uint32(data[8:])),
+		Height:    int(le.Uint32(data[12:])),
+		FrameRate: float64(math.Float32frombits(le.Uint32(data[16:]))),
+		data:      data,
+	}
+	if v.Width <= 0 || v.Height <= 0 || !(v.FrameRate > 0) {
+		return nil, fmt.Errorf("invalid video size %dx%d at %g frames per second",
+			v.Width, v.Height, v.FrameRate)
+	}
+	trailer := data[len(data)-trailerSize:]
+	count := uint64(le.Uint32(trailer))
+	indexOffset := le.Uint64(trailer[4:])
+	audioOffset := le.Uint64(trailer[12:])
+	audioSize := le.Uint64(trailer[20:])
+	end := uint64(len(data) - trailerSize)
+	if indexOffset > end || count > (end-indexOffset)/indexSize ||
+		audioOffset > end || audioSize > end-audioOffset {
+		return nil, errors.New("the video file is corrupt")
+	}
+	v.frames = make([]frameEntry, count)
+	for i := range v.frames {
+		entry := data[indexOffset+uint64(i)*indexSize:]
+		v.frames[i] = frameEntry{le.Uint64(entry), le.Uint32(entry[8:])}
+		if v.frames[i].offset > end || uint64(v.frames[i].size) > end-v.frames[i].offset {
+			return nil, fmt.Errorf("frame %d of the video file is corrupt", i)
+		}
+	}
+	if audioSize > 0 {
+		v.audio = data[audioOffset : audioOffset+audioSize]
+	}
+	return v, nil
+}
+
+func (v *Video) FrameCount() int { return len(v.frames) }
+
+// Duration is the length of the video in seconds
+func (v *Video) Duration() float64 { return float64(len(v.frames)) / v.FrameRate }
+
+func (v *Video) HasAudio() bool { return len(v.audio) > 0 }
+
+// Audio returns the WAV data of the audio track, or nil if there is none
+func (v *Video) Audio() []byte { return v.audio }
+
+// FrameAt returns the index of the frame that is shown at the given time
+func (v *Video) FrameAt(seconds float64) int {
+	if len(v.frames) == 0 {
+		return -1
+	}
+	return max(0, min(int(seconds*v.FrameRate), len(v.frames)-1))
+}
+
+// DecodeFrame decodes the frame at index into dst, which should be as large
+// as the video
+func (v *Video) DecodeFrame(index int, dst *image.RGBA) error {
+	if index < 0 || index >= len(v.frames) {
+		return fmt.Errorf("frame %d is out of range of the %d video frames", index, len(v.frames))
+	}
+	f := v.frames[index]
+	img, err := jpeg.Decode(bytes.NewReader(v.data[f.offset : f.offset+uint64(f.size)]))
+	if err != nil {
+		return fmt.Errorf("failed to decode video frame %d: %w", index, err)
+	}
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Src)
+	return nil
+}
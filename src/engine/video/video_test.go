@@ -0,0 +1,131 @@
+/******************************************************************************/
+/* video_test.go                                                              */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package video
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+var testFrameColors = []color.RGBA{
+	{255, 0, 0, 255},
+	{0, 255, 0, 255},
+	{0, 0, 255, 255},
+	{255, 255, 255, 255},
+}
+
+func solidFrame(width, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func encodeTestVideo(t *testing.T, frameRate float64, audio []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, 16, 8, frameRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Quality = 100
+	for _, c := range testFrameColors {
+		if err := w.WriteFrame(solidFrame(16, 8, c)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.SetAudio(audio)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func decodeTestVideo(t *testing.T, frameRate float64) *Video {
+	t.Helper()
+	v, err := Decode(encodeTestVideo(t, frameRate, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func colorsNear(a, b color.RGBA) bool {
+	near := func(x, y uint8) bool { return max(x, y)-min(x, y) <= 8 }
+	return near(a.R, b.R) && near(a.G, b.G) && near(a.B, b.B) && near(a.A, b.A)
+}
+
+func TestDecodeReadsWrittenVideo(t *testing.T) {
+	v, err := Decode(encodeTestVideo(t, 4, []byte("RIFF-audio")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Width != 16 || v.Height != 8 || v.FrameRate != 4 {
+		t.Fatalf("expected a 16x8 video at 4 fps, got %dx%d at %g", v.Width, v.Height, v.FrameRate)
+	}
+	if v.FrameCount() != len(testFrameColors) || v.Duration() != 1 {
+		t.Fatalf("expected 4 frames lasting 1 second, got %d lasting %g", v.FrameCount(), v.Duration())
+	}
+	if string(v.Audio()) != "RIFF-audio" {
+		t.Errorf("expected the audio track to be kept, got %q", v.Audio())
+	}
+	img := image.NewRGBA(image.Rect(0, 0, v.Width, v.Height))
+	for i, want := range testFrameColors {
+		if err := v.DecodeFrame(i, img); err != nil {
+			t.Fatal(err)
+		}
+		if got := img.RGBAAt(8, 4); !colorsNear(got, want) {
+			t.Errorf("frame %d: expected %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestDecodeRejectsBadData(t *testing.T) {
+	data := encodeTestVideo(t, 4, nil)
+	if _, err := Decode(data[:len(data)-1]); err == nil {
+		t.Error("expected a truncated file to fail")
+	}
+	if _, err := Decode([]byte("not a video file at all, not even close to one")); err == nil {
+		t.Error("expected other data to fail")
+	}
+	corrupt := bytes.Clone(data)
+	// Point the frame index past the end of the file
+	copy(corrupt[len(corrupt)-trailerSize+4:], []byte{0xff, 0xff, 0xff, 0xff})
+	if _, err := Decode(corrupt); err == nil {
+		t.Error("expected a corrupt frame index to fail")
+	}
+}
+
+func TestFrameAtClampsToFrames(t *testing.T) {
+	v := decodeTestVideo(t, 4)
+	tests := map[float64]int{-1: 0, 0: 0, 0.24: 0, 0.25: 1, 0.9: 3, 5: 3}
+	for seconds, want := range tests {
+		if got := v.FrameAt(seconds); got != want {
+			t.Errorf("FrameAt(%g): expected %d, got %d", seconds, want, got)
+		}
+	}
+}
+
+func TestWriterRejectsMismatchedFrames(t *testing.T) {
+	w, err := NewWriter(&bytes.Buffer{}, 16, 8, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteFrame(solidFrame(8, 8, testFrameColors[0])); err == nil {
+		t.Error("expected a frame of the wrong size to fail")
+	}
+	w.Close()
+	if err := w.WriteFrame(solidFrame(16, 8, testFrameColors[0])); err == nil {
+		t.Error("expected writing after closing to fail")
+	}
+	if _, err := NewWriter(&bytes.Buffer{}, 16, 8, 0); err == nil {
+		t.Error("expected a frame rate of 0 to fail")
+	}
+}
@@ -0,0 +1,119 @@
+/******************************************************************************/
+/* writer.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package video
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"math"
+)
+
+// Writer creates a video file, the frames are written as they are given and
+// the audio track and frame index are written when the writer is closed
+type Writer struct {
+	// Quality is the JPEG quality (1-100) that frames are encoded with, the
+	// default JPEG quality is used when it is 0
+	Quality int
+	out     io.Writer
+	width   int
+	height  int
+	offset  uint64
+	frames  []frameEntry
+	audio   []byte
+	buffer  bytes.Buffer
+	closed  bool
+}
+
+func NewWriter(out io.Writer, width, height int, frameRate float64) (*Writer, error) {
+	if width <= 0 || height <= 0 || !(frameRate > 0) {
+		return nil, fmt.Errorf("invalid video size %dx%d at %g frames per second",
+			width, height, frameRate)
+	}
+	w := &Writer{out: out, width: width, height: height}
+	header := make([]byte, headerSize)
+	copy(header, fileMagic[:])
+	le := binary.LittleEndian
+	le.PutUint16(header[4:], fileVersion)
+	le.PutUint32(header[8:], uint32(width))
+	le.PutUint32(header[12:], uint32(height))
+	le.PutUint32(header[16:], math.Float32bits(float32(frameRate)))
+	return w, w.write(header)
+}
+
+// WriteFrame encodes the image as the next frame, it must be the size of the
+// video
+func (w *Writer) WriteFrame(img image.Image) error {
+	if s := img.Bounds().Size(); s.X != w.width || s.Y != w.height {
+		return fmt.Errorf("the %dx%d frame doesn't match the %dx%d video",
+			s.X, s.Y, w.width, w.height)
+	}
+	w.buffer.Reset()
+	quality := jpeg.DefaultQuality
+	if w.Quality > 0 {
+		quality = w.Quality
+	}
+	if err := jpeg.Encode(&w.buffer, img, &jpeg.Options{Quality: quality}); err != nil {
+		return err
+	}
+	return w.WriteJPEG(w.buffer.Bytes())
+}
+
+// WriteJPEG writes an already encoded JPEG as the next frame
+func (w *Writer) WriteJPEG(data []byte) error {
+	if w.closed {
+		return errors.New("the video writer is closed")
+	}
+	if len(data) == 0 {
+		return errors.New("the frame is empty")
+	}
+	w.frames = append(w.frames, frameEntry{w.offset, uint32(len(data))})
+	return w.write(data)
+}
+
+// SetAudio sets the WAV data that is played along with the video
+func (w *Writer) SetAudio(wav []byte) { w.audio = wav }
+
+// Close writes the audio track and the frame index, it doesn't close the
+// underlying writer
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	audioOffset := w.offset
+	if err := w.write(w.audio); err != nil {
+		return err
+	}
+	le := binary.LittleEndian
+	indexOffset := w.offset
+	index := make([]byte, len(w.frames)*indexSize)
+	for i, f := range w.frames {
+		le.PutUint64(index[i*indexSize:], f.offset)
+		le.PutUint32(index[i*indexSize+8:], f.size)
+	}
+	if err := w.write(index); err != nil {
+		return err
+	}
+	trailer := make([]byte, trailerSize)
+	le.PutUint32(trailer, uint32(len(w.frames)))
+	le.PutUint64(trailer[4:], indexOffset)
+	le.PutUint64(trailer[12:], audioOffset)
+	le.PutUint64(trailer[20:], uint64(len(w.audio)))
+	copy(trailer[28:], fileMagic[:])
+	return w.write(trailer)
+}
+
+func (w *Writer) write(data []byte) error {
+	n, err := w.out.Write(data)
+	w.offset += uint64(n)
+	return err
+}
@@ -10,6 +10,7 @@ import (
 	"kaijuengine.com/engine/encoding/pod"
 )
 
+type BoneMap string
 type Css string
 type Font string
 type Html string
@@ -28,6 +29,7 @@ type Texture string
 type Stage string
 
 func init() {
+	pod.Register(BoneMap(""))
 	pod.Register(Css(""))
 	pod.Register(Font(""))
 	pod.Register(Html(""))
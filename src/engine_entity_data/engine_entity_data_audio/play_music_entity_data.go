@@ -30,8 +30,9 @@ func MusicBindingKey() string {
 }
 
 type PlayMusicEntityData struct {
-	MusicId content_id.Music
-	Loop    bool
+	MusicId    content_id.Music
+	Loop       bool
+	Compressed bool `tip:"Keep the music compressed in memory and decode it while it plays"`
 }
 
 type MusicPlayer struct {
@@ -47,7 +48,11 @@ func (c PlayMusicEntityData) Init(e *engine.Entity, host *engine.Host) {
 		return
 	}
 	a := host.Audio()
-	clip, err := a.LoadMusic(adb, string(c.MusicId))
+	load := a.LoadMusic
+	if c.Compressed {
+		load = a.LoadCompressedMusic
+	}
+	clip, err := load(adb, string(c.MusicId))
 	if err != nil {
 		slog.Error("failed to load the music clip", "id", c.MusicId, "error", err)
 		return
@@ -13,6 +13,8 @@ import (
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/encoding/pod"
 	"kaijuengine.com/engine_entity_data/content_id"
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/platform/audio"
 )
 
 var soundBindingKey = ""
@@ -31,6 +33,24 @@ func SoundBindingKey() string {
 type PlaySoundEntityData struct {
 	SoundId      content_id.Sound
 	DelaySeconds float32
+	Bus          string                 `tip:"Blank = master bus"`
+	Spatial      bool                   `tip:"Play the sound from the position of the entity"`
+	Attenuation  audio.AttenuationModel `default:"1" tip:"0 = none, 1 = inverse, 2 = linear, 3 = exponential"`
+	Rolloff      float32                `default:"1"`
+	MinDistance  float32                `default:"1"`
+	MaxDistance  float32                `default:"100"`
+	Doppler      float32                `default:"1"`
+}
+
+// SpatialSoundPlayer keeps a 3D voice positioned at its entity while the
+// voice is playing
+type SpatialSoundPlayer struct {
+	entity   *engine.Entity
+	audio    *audio.Audio
+	updater  *engine.Updater
+	Handle   audio.VoiceHandle
+	position matrix.Vec3
+	updateId engine.UpdateId
 }
 
 func (c PlaySoundEntityData) Init(e *engine.Entity, host *engine.Host) {
@@ -45,12 +65,67 @@ func (c PlaySoundEntityData) Init(e *engine.Entity, host *engine.Host) {
 		slog.Error("failed to load the sound clip", "id", c.SoundId, "error", err)
 		return
 	}
+	var bus *audio.Bus
+	if c.Bus != "" {
+		var ok bool
+		if bus, ok = a.FindBus(c.Bus); !ok {
+			slog.Warn("the audio bus could not be found, using master", "bus", c.Bus)
+		}
+	}
+	play := func() {
+		if c.Spatial {
+			c.playSpatial(e, host, clip, bus)
+		} else {
+			a.PlayOnBus(clip, bus)
+		}
+	}
 	if c.DelaySeconds <= 0 {
-		a.Play(clip)
+		play()
 	} else {
 		ms := c.DelaySeconds * 1000
-		host.RunAfterTime(time.Millisecond*time.Duration(ms), func() {
-			a.Play(clip)
-		})
+		host.RunAfterTime(time.Millisecond*time.Duration(ms), play)
+	}
+}
+
+func (c PlaySoundEntityData) spatial() audio.Spatial {
+	return audio.Spatial{
+		Attenuation:   c.Attenuation,
+		Rolloff:       c.Rolloff,
+		MinDistance:   c.MinDistance,
+		MaxDistance:   c.MaxDistance,
+		DopplerFactor: c.Doppler,
+	}
+}
+
+func (c PlaySoundEntityData) playSpatial(e *engine.Entity, host *engine.Host, clip *audio.AudioClip, bus *audio.Bus) {
+	if e.IsDestroyed() {
+		return
+	}
+	a := host.Audio()
+	p := &SpatialSoundPlayer{
+		entity:   e,
+		audio:    a,
+		updater:  &host.Updater,
+		position: e.Transform.WorldPosition(),
+	}
+	p.Handle = a.Play3D(clip, p.position, matrix.Vec3Zero(), c.spatial(), bus)
+	p.updateId = p.updater.AddUpdate(p.update)
+	e.OnDestroy.Add(func() { p.updater.RemoveUpdate(&p.updateId) })
+}
+
+func (p *SpatialSoundPlayer) update(deltaTime float64) {
+	if p.updateId == 0 {
+		return
+	}
+	if !p.audio.IsValidVoiceHandle(p.Handle) {
+		p.updater.RemoveUpdate(&p.updateId)
+		return
+	}
+	pos := p.entity.Transform.WorldPosition()
+	var velocity matrix.Vec3
+	if deltaTime > 0 {
+		velocity = pos.Subtract(p.position).Scale(matrix.Float(1.0 / deltaTime))
 	}
+	p.position = pos
+	p.audio.SetVoicePosition(p.Handle, pos, velocity)
 }
@@ -8,11 +8,13 @@ package engine_entity_data_skin_animation
 
 import (
 	"log/slog"
+	"slices"
 	"strings"
 	"weak"
 
 	"kaijuengine.com/engine"
 	"kaijuengine.com/engine/encoding/pod"
+	"kaijuengine.com/engine/systems/events"
 	"kaijuengine.com/engine_entity_data/content_id"
 	"kaijuengine.com/framework"
 	"kaijuengine.com/klib"
@@ -38,6 +40,26 @@ func BindingKey() string {
 type SkinAnimationEntityData struct {
 	MeshId   content_id.Mesh
 	AnimName string `options:"animations"`
+	// AnimationMeshId is an optional mesh to take the animations from, they
+	// will be retargeted onto the skeleton of MeshId using BoneMapId
+	AnimationMeshId content_id.Mesh
+	BoneMapId       content_id.BoneMap
+	RootMotion      bool
+}
+
+// LoadAnimations reads the animations that should be played on the skeleton
+// of the given mesh, retargeting them from AnimationMeshId when it is set
+func (c SkinAnimationEntityData) LoadAnimations(km kaiju_mesh.KaijuMesh, host *engine.Host) []kaiju_mesh.KaijuMeshAnimation {
+	if c.AnimationMeshId == "" {
+		return km.Animations
+	}
+	anims, err := kaiju_mesh.ReadRetargetedAnimations(string(c.AnimationMeshId),
+		string(c.BoneMapId), km.Joints, host)
+	if err != nil {
+		slog.Error("failed to retarget the animations", "id", c.AnimationMeshId, "error", err)
+		return km.Animations
+	}
+	return anims
 }
 
 type MeshSkinningAnimation struct {
@@ -50,7 +72,26 @@ type MeshSkinningAnimation struct {
 	skin           weak.Pointer[rendering.SkinnedShaderDataHeader]
 	shaderDataBase weak.Pointer[rendering.ShaderDataBase]
 	current        framework.SkinAnimation
+	ikPasses       []framework.IKSolver
+	pose           map[int32]bonePose
 	isPlaying      bool
+	rootMotion     bool
+	rootMotionBone int32
+	rootMotionMask matrix.Vec3
+	// OnEvent is called for the named events within the animation as they
+	// are passed over by the playhead
+	OnEvent events.EventWithArg[kaiju_mesh.AnimEvent]
+	// OnRootMotion is called with the world space root motion each frame, if
+	// there is nothing bound to this, the motion will be applied to the entity
+	OnRootMotion events.EventWithArg[matrix.Vec3]
+}
+
+// bonePose is the local transform a bone was last given by the bind pose or
+// the animation, it is what the bones are put back to before IK is solved
+type bonePose struct {
+	position matrix.Vec3
+	rotation matrix.Vec3
+	scale    matrix.Vec3
 }
 
 func (c SkinAnimationEntityData) Init(e *engine.Entity, host *engine.Host) {
@@ -61,12 +102,15 @@ func (c SkinAnimationEntityData) Init(e *engine.Entity, host *engine.Host) {
 	}
 	sd := e.ShaderData()
 	anim := &MeshSkinningAnimation{
-		anims:          km.Animations,
+		anims:          c.LoadAnimations(km, host),
 		joints:         km.Joints,
 		entity:         weak.Make(e),
 		skin:           weak.Make(sd.SkinningHeader()),
 		shaderDataBase: weak.Make(sd.Base()),
 	}
+	if c.RootMotion {
+		anim.EnableRootMotion(anim.rootBoneId(), matrix.NewVec3(1, 0, 1))
+	}
 	anim.SetAnimation(c.AnimName)
 	wh := weak.Make(host)
 	e.OnDestroy.Add(func() {
@@ -87,9 +131,71 @@ func (a *MeshSkinningAnimation) SetAnimation(name string) {
 		}
 	}
 	a.current = framework.NewSkinAnimation(a.anims[a.animIdx])
+	a.current.OnEvent.Add(a.OnEvent.Execute)
+	if a.rootMotion {
+		a.current.EnableRootMotion(a.rootMotionBone, a.rootMotionMask)
+	}
 	a.isPlaying = true
 }
 
+// EnableRootMotion will move the entity by the masked motion of the given
+// bone rather than letting the bone drift away from the entity
+func (a *MeshSkinningAnimation) EnableRootMotion(boneId int32, mask matrix.Vec3) {
+	a.rootMotion = true
+	a.rootMotionBone = boneId
+	a.rootMotionMask = mask
+	if a.current.IsValid() {
+		a.current.EnableRootMotion(boneId, mask)
+	}
+}
+
+func (a *MeshSkinningAnimation) DisableRootMotion() {
+	a.rootMotion = false
+	a.current.DisableRootMotion()
+}
+
+// rootBoneId is the first joint that isn't parented to another joint
+func (a *MeshSkinningAnimation) rootBoneId() int32 {
+	for i := range a.joints {
+		if !slices.ContainsFunc(a.joints, func(j kaiju_mesh.KaijuMeshJoint) bool {
+			return j.Id == a.joints[i].Parent
+		}) {
+			return a.joints[i].Id
+		}
+	}
+	return 0
+}
+
+// BoneTransform returns the transform of the bone with the given joint id so
+// that it can be used by IK solvers, or nil if the skin has no such bone
+func (a *MeshSkinningAnimation) BoneTransform(id int32) *matrix.Transform {
+	skin := a.skin.Value()
+	if skin == nil {
+		return nil
+	}
+	if bone := skin.FindBone(id); bone != nil {
+		return &bone.Transform
+	}
+	return nil
+}
+
+// AddIKPass registers a solver to be run, in the order it was added, on the
+// pose after it has been sampled from the animation each frame. The passes
+// also run while the animation is stopped, starting from the bind pose or the
+// last sampled pose so that the corrections do not build up over frames.
+func (a *MeshSkinningAnimation) AddIKPass(solver framework.IKSolver) {
+	a.ikPasses = append(a.ikPasses, solver)
+}
+
+func (a *MeshSkinningAnimation) RemoveIKPass(solver framework.IKSolver) {
+	for i := range a.ikPasses {
+		if a.ikPasses[i] == solver {
+			a.ikPasses = slices.Delete(a.ikPasses, i, i+1)
+			return
+		}
+	}
+}
+
 func (a *MeshSkinningAnimation) setup(host *engine.Host) {
 	e := a.entity.Value()
 	sd := e.ShaderData()
@@ -125,41 +231,92 @@ func (a *MeshSkinningAnimation) setup(host *engine.Host) {
 			}
 		}
 	}
+	a.pose = make(map[int32]bonePose, len(a.joints))
+	for i := range a.joints {
+		j := &a.joints[i]
+		a.pose[j.Id] = bonePose{j.Position, j.Rotation, j.Scale}
+	}
 	if !a.updateId.IsValid() {
 		a.updateId = host.Updater.AddUpdate(a.update)
 	}
 }
 
 func (a *MeshSkinningAnimation) update(deltaTime float64) {
-	if !a.isPlaying {
-		return
-	}
 	sd := a.shaderDataBase.Value()
 	skin := a.skin.Value()
 	if skin == nil || (sd != nil && !sd.IsInView()) {
 		return
 	}
+	if a.isPlaying {
+		a.sample(skin, deltaTime)
+	}
+	a.solveIK(skin)
+}
+
+func (a *MeshSkinningAnimation) sample(skin *rendering.SkinnedShaderDataHeader, deltaTime float64) {
 	a.current.Update(deltaTime)
-	frame := a.current.CurrentFrame()
-	for i := range frame.Key.Bones {
-		frame.Bone = &frame.Key.Bones[i]
-		bone := skin.FindBone(int32(frame.Bone.NodeIndex))
+	samples := a.current.Sample()
+	for i := range samples {
+		bone := skin.FindBone(samples[i].BoneId)
 		if bone == nil {
 			continue
 		}
-		nextFrame, ok := a.current.FindNextFrameForBone(bone.Id, frame.Bone.PathType)
-		if !ok {
-			nextFrame = frame
-			nextFrame.Bone = frame.Bone
-		}
-		data := a.current.Interpolate(frame, nextFrame)
-		switch frame.Bone.PathType {
+		data := a.current.ExtractRootMotion(bone.Id, samples[i].PathType, samples[i].Data)
+		pose := a.pose[bone.Id]
+		switch samples[i].PathType {
 		case load_result.AnimPathTranslation:
-			bone.Transform.SetLocalPosition(matrix.Vec3FromSlice(data[:]))
+			pose.position = matrix.Vec3FromSlice(data[:])
+			bone.Transform.SetLocalPosition(pose.position)
 		case load_result.AnimPathRotation:
-			bone.Transform.SetRotation(matrix.Quaternion(data).ToEuler())
+			pose.rotation = matrix.Quaternion(data).ToEuler()
+			bone.Transform.SetRotation(pose.rotation)
 		case load_result.AnimPathScale:
-			bone.Transform.SetScale(matrix.Vec3FromSlice(data[:]))
+			pose.scale = matrix.Vec3FromSlice(data[:])
+			bone.Transform.SetScale(pose.scale)
 		}
+		a.pose[bone.Id] = pose
+	}
+	if a.current.RootMotionEnabled() {
+		a.applyRootMotion(skin)
+	}
+}
+
+// solveIK puts the bones back to their bind or sampled pose before running
+// the IK passes, otherwise bones that the animation doesn't key would keep
+// the corrections of the previous frames and drift further every frame
+func (a *MeshSkinningAnimation) solveIK(skin *rendering.SkinnedShaderDataHeader) {
+	if len(a.ikPasses) == 0 {
+		return
+	}
+	for id, pose := range a.pose {
+		if bone := skin.FindBone(id); bone != nil {
+			bone.Transform.SetLocalPosition(pose.position)
+			bone.Transform.SetRotation(pose.rotation)
+			bone.Transform.SetScale(pose.scale)
+		}
+	}
+	for i := range a.ikPasses {
+		a.ikPasses[i].Solve()
+	}
+}
+
+func (a *MeshSkinningAnimation) applyRootMotion(skin *rendering.SkinnedShaderDataHeader) {
+	delta := a.current.ConsumeRootMotion()
+	e := a.entity.Value()
+	if e == nil || delta.Equals(matrix.Vec3Zero()) {
+		return
+	}
+	// The motion is in the space of the root bone's parent, which is the entity
+	space := e.Transform.WorldMatrix()
+	if bone := skin.FindBone(a.current.RootMotionBone()); bone != nil {
+		if p := bone.Transform.Parent(); p != nil {
+			space = p.WorldMatrix()
+		}
+	}
+	world := space.TransformPoint(delta).Subtract(space.TransformPoint(matrix.Vec3Zero()))
+	if !a.OnRootMotion.IsEmpty() {
+		a.OnRootMotion.Execute(world)
+	} else {
+		e.Transform.SetWorldPosition(e.Transform.WorldPosition().Add(world))
 	}
 }
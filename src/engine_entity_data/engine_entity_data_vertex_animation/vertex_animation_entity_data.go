@@ -0,0 +1,110 @@
+/******************************************************************************/
+/* vertex_animation_entity_data.go                                            */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package engine_entity_data_vertex_animation
+
+import (
+	"log/slog"
+	"strings"
+	"weak"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/encoding/pod"
+	"kaijuengine.com/engine_entity_data/content_id"
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/registry/shader_data_registry"
+	"kaijuengine.com/rendering/loaders/kaiju_mesh"
+)
+
+var bindingKey = ""
+
+func init() {
+	engine.RegisterEntityData(VertexAnimationEntityData{})
+}
+
+func BindingKey() string {
+	if bindingKey == "" {
+		bindingKey = pod.QualifiedNameForLayout(VertexAnimationEntityData{})
+	}
+	return bindingKey
+}
+
+// VertexAnimationEntityData plays an animation that was baked into a vertex
+// animation texture when the mesh was imported. The entity is expected to be
+// drawn using the basic_vertex_animation material with the baked texture as
+// its second texture. All of the playback happens within the vertex shader.
+type VertexAnimationEntityData struct {
+	MeshId     content_id.Mesh
+	AnimName   string `options:"animations"`
+	TimeOffset float32
+	Speed      float32 `default:"1"`
+}
+
+type MeshVertexAnimation struct {
+	anims      []kaiju_mesh.VertexAnimation
+	current    int
+	speed      float32
+	timeOffset float32
+	host       weak.Pointer[engine.Host]
+	entity     weak.Pointer[engine.Entity]
+}
+
+func (c VertexAnimationEntityData) Init(e *engine.Entity, host *engine.Host) {
+	km, err := kaiju_mesh.ReadMesh(string(c.MeshId), host)
+	if err != nil {
+		slog.Error("failed to deserialize kaiju mesh", "id", c.MeshId, "error", err)
+		return
+	}
+	if len(km.VertexAnimations) == 0 {
+		slog.Error("the mesh has no baked vertex animations", "id", c.MeshId)
+		return
+	}
+	anim := &MeshVertexAnimation{
+		anims:      km.VertexAnimations,
+		speed:      c.Speed,
+		timeOffset: c.TimeOffset,
+		host:       weak.Make(host),
+		entity:     weak.Make(e),
+	}
+	e.AddNamedData(bindingKey, anim)
+	// The shader data hasn't been assigned yet, wait until the next frame to setup
+	host.RunNextFrame(func() { anim.SetAnimation(c.AnimName) })
+}
+
+// SetAnimation restarts playback using the baked animation with the given name
+func (a *MeshVertexAnimation) SetAnimation(name string) {
+	for i := range a.anims {
+		if strings.EqualFold(a.anims[i].Animation, name) {
+			a.current = i
+		}
+	}
+	a.apply()
+}
+
+// SetSpeed changes the playback speed, the animation is restarted as the
+// shader derives the current frame from the time that playback started
+func (a *MeshVertexAnimation) SetSpeed(speed float32) {
+	a.speed = speed
+	a.apply()
+}
+
+func (a *MeshVertexAnimation) apply() {
+	e := a.entity.Value()
+	host := a.host.Value()
+	if e == nil || host == nil {
+		return
+	}
+	sd, ok := e.ShaderData().(*shader_data_registry.ShaderDataVertexAnimation)
+	if !ok {
+		slog.Error("failed to find vertex animation shader data on entity for MeshVertexAnimation", "entity", e.Id())
+		return
+	}
+	va := &a.anims[a.current]
+	start := float32(host.Runtime()) - a.timeOffset
+	sd.Animation = matrix.NewVec4(va.FrameRate, matrix.Float(va.FrameCount), start, a.speed)
+	sd.BoundsMin = matrix.NewVec4(va.BoundsMin.X(), va.BoundsMin.Y(), va.BoundsMin.Z(), matrix.Float(va.Width))
+	sd.BoundsSize = matrix.NewVec4(va.BoundsSize.X(), va.BoundsSize.Y(), va.BoundsSize.Z(), matrix.Float(va.RowsPerFrame))
+}
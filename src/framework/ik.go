@@ -0,0 +1,99 @@
+/******************************************************************************/
+/* ik.go                                                                      */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package framework
+
+import (
+	"kaijuengine.com/engine/graviton"
+	"kaijuengine.com/matrix"
+)
+
+// IKSolver is a procedural pass that adjusts the joint transforms of an
+// already sampled pose. Solvers are expected to be run after the animation
+// has written its keyframe data to the bones for the current frame.
+type IKSolver interface {
+	Solve()
+}
+
+// IKRaycaster is used by solvers that need to probe the world, such as foot
+// placement. A *graviton.System satisfies this interface directly, so any
+// terrain registered as a rigid body in the stage physics is considered.
+type IKRaycaster interface {
+	Raycast(from, to matrix.Vec3) (graviton.Hit, bool)
+}
+
+// TerrainRaycaster adapts a single graviton.TerrainCollision to the
+// IKRaycaster interface for cases where the terrain is not part of a physics
+// system. Transform is optional and describes where the terrain sits in world.
+type TerrainRaycaster struct {
+	Terrain   *graviton.TerrainCollision
+	Transform *matrix.Transform
+}
+
+const ikEpsilon = 0.0001
+
+func (r TerrainRaycaster) Raycast(from, to matrix.Vec3) (graviton.Hit, bool) {
+	delta := to.Subtract(from)
+	length := delta.Length()
+	if r.Terrain == nil || length <= ikEpsilon {
+		return graviton.Hit{}, false
+	}
+	ray := graviton.Ray{
+		Origin:    from,
+		Direction: delta.Scale(1.0 / length),
+	}
+	return r.Terrain.Raycast(ray, length, r.Transform)
+}
+
+func ikWorldRotation(t *matrix.Transform) matrix.Quaternion {
+	return matrix.QuaternionFromEuler(t.WorldRotation())
+}
+
+// ikRotateWorld applies a rotation that is expressed in world space on top of
+// the current world rotation of the transform
+func ikRotateWorld(t *matrix.Transform, rotation matrix.Quaternion) {
+	world := rotation.Multiply(ikWorldRotation(t))
+	world.Normalize()
+	t.SetWorldRotation(world.ToEuler())
+}
+
+// ikRotateTowards rotates the transform so that the world direction from
+// becomes the world direction to, scaled by weight (0 to 1)
+func ikRotateTowards(t *matrix.Transform, from, to matrix.Vec3, weight matrix.Float) {
+	if from.LengthSquared() < ikEpsilon || to.LengthSquared() < ikEpsilon || weight <= 0 {
+		return
+	}
+	from = from.Normal()
+	to = to.Normal()
+	if matrix.Vec3Dot(from, to) > 1.0-ikEpsilon*ikEpsilon {
+		return
+	}
+	delta := matrix.QuatAngleBetween(from, to)
+	if weight < 1 {
+		delta = matrix.QuaternionSlerp(matrix.QuaternionIdentity(), delta, weight)
+	}
+	ikRotateWorld(t, delta)
+}
+
+// ikRotateAxisAngle rotates the transform around a world axis by the angle
+// which is given in radians
+func ikRotateAxisAngle(t *matrix.Transform, axis matrix.Vec3, angle matrix.Float) {
+	if matrix.Abs(angle) < ikEpsilon || axis.LengthSquared() < ikEpsilon {
+		return
+	}
+	ikRotateWorld(t, matrix.QuaternionAxisAngle(axis.Normal(), angle))
+}
+
+func ikClampWeight(weight matrix.Float) matrix.Float {
+	return matrix.Clamp(weight, 0, 1)
+}
+
+func ikAngleBetween(a, b matrix.Vec3) matrix.Float {
+	if a.LengthSquared() < ikEpsilon || b.LengthSquared() < ikEpsilon {
+		return 0
+	}
+	return matrix.Acos(matrix.Clamp(matrix.Vec3Dot(a.Normal(), b.Normal()), -1, 1))
+}
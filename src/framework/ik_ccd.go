@@ -0,0 +1,61 @@
+/******************************************************************************/
+/* ik_ccd.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package framework
+
+import "kaijuengine.com/matrix"
+
+// CCDChain solves an arbitrary length chain of joints using Cyclic Coordinate
+// Descent. It converges slower than FABRIK for long chains but tends to curl
+// the tip first, which reads well for tails and tentacles. Joints are ordered
+// from the root of the chain to the tip.
+type CCDChain struct {
+	Joints     []*matrix.Transform
+	Target     matrix.Vec3
+	Iterations int
+	Tolerance  matrix.Float
+	Weight     matrix.Float
+	// MaxAngle limits the rotation (in degrees) each joint may take per
+	// iteration, a value of zero or less means there is no limit
+	MaxAngle matrix.Float
+}
+
+func NewCCDChain(joints ...*matrix.Transform) *CCDChain {
+	return &CCDChain{
+		Joints:     joints,
+		Iterations: defaultIKIterations,
+		Tolerance:  defaultIKTolerance,
+		Weight:     1,
+	}
+}
+
+func (ik *CCDChain) Solve() {
+	weight := ikClampWeight(ik.Weight)
+	count := len(ik.Joints)
+	if count < 2 || weight <= 0 {
+		return
+	}
+	tip := ik.Joints[count-1]
+	for range max(1, ik.Iterations) {
+		if tip.WorldPosition().Distance(ik.Target) <= ik.Tolerance {
+			break
+		}
+		for i := count - 2; i >= 0; i-- {
+			joint := ik.Joints[i]
+			pos := joint.WorldPosition()
+			toTip := tip.WorldPosition().Subtract(pos)
+			toTarget := ik.Target.Subtract(pos)
+			w := weight
+			if ik.MaxAngle > 0 {
+				angle := matrix.Rad2Deg(ikAngleBetween(toTip, toTarget))
+				if angle > ik.MaxAngle {
+					w *= ik.MaxAngle / angle
+				}
+			}
+			ikRotateTowards(joint, toTip, toTarget, w)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/******************************************************************************/
+/* ik_fabrik.go                                                               */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package framework
+
+import "kaijuengine.com/matrix"
+
+const (
+	defaultIKIterations = 10
+	defaultIKTolerance  = 0.001
+)
+
+// FABRIKChain solves an arbitrary length chain of joints (tails, spines,
+// tentacles) using Forward And Backward Reaching Inverse Kinematics. Joints
+// are ordered from the root of the chain to the tip, each joint is expected
+// to be a descendant of the one before it.
+type FABRIKChain struct {
+	Joints     []*matrix.Transform
+	Target     matrix.Vec3
+	Iterations int
+	Tolerance  matrix.Float
+	Weight     matrix.Float
+	positions  []matrix.Vec3
+	lengths    []matrix.Float
+}
+
+func NewFABRIKChain(joints ...*matrix.Transform) *FABRIKChain {
+	return &FABRIKChain{
+		Joints:     joints,
+		Iterations: defaultIKIterations,
+		Tolerance:  defaultIKTolerance,
+		Weight:     1,
+	}
+}
+
+func (ik *FABRIKChain) Solve() {
+	weight := ikClampWeight(ik.Weight)
+	count := len(ik.Joints)
+	if count < 2 || weight <= 0 {
+		return
+	}
+	ik.readPose()
+	original := make([]matrix.Vec3, count)
+	copy(original, ik.positions)
+	target := ik.Target
+	total := matrix.Float(0)
+	for i := range ik.lengths {
+		total += ik.lengths[i]
+	}
+	root := ik.positions[0]
+	if root.Distance(target) >= total {
+		// Unreachable, stretch the chain straight towards the target
+		dir := target.Subtract(root).Normal()
+		for i := 1; i < count; i++ {
+			ik.positions[i] = ik.positions[i-1].Add(dir.Scale(ik.lengths[i-1]))
+		}
+	} else {
+		for range max(1, ik.Iterations) {
+			if ik.positions[count-1].Distance(target) <= ik.Tolerance {
+				break
+			}
+			ik.backward(target)
+			ik.forward(root)
+		}
+	}
+	if weight < 1 {
+		for i := range ik.positions {
+			ik.positions[i] = matrix.Vec3Lerp(original[i], ik.positions[i], weight)
+		}
+	}
+	ik.writePose()
+}
+
+func (ik *FABRIKChain) readPose() {
+	count := len(ik.Joints)
+	if cap(ik.positions) < count {
+		ik.positions = make([]matrix.Vec3, count)
+		ik.lengths = make([]matrix.Float, count-1)
+	}
+	ik.positions = ik.positions[:count]
+	ik.lengths = ik.lengths[:count-1]
+	for i := range ik.Joints {
+		ik.positions[i] = ik.Joints[i].WorldPosition()
+	}
+	for i := range ik.lengths {
+		ik.lengths[i] = ik.positions[i+1].Distance(ik.positions[i])
+	}
+}
+
+func (ik *FABRIKChain) backward(target matrix.Vec3) {
+	last := len(ik.positions) - 1
+	ik.positions[last] = target
+	for i := last - 1; i >= 0; i-- {
+		ik.positions[i] = ikReach(ik.positions[i+1], ik.positions[i], ik.lengths[i])
+	}
+}
+
+func (ik *FABRIKChain) forward(root matrix.Vec3) {
+	ik.positions[0] = root
+	for i := 1; i < len(ik.positions); i++ {
+		ik.positions[i] = ikReach(ik.positions[i-1], ik.positions[i], ik.lengths[i-1])
+	}
+}
+
+// writePose converts the solved joint positions back into rotations, starting
+// from the root so that every child is carried by its already solved parent
+func (ik *FABRIKChain) writePose() {
+	for i := 0; i < len(ik.Joints)-1; i++ {
+		joint := ik.Joints[i]
+		from := ik.Joints[i+1].WorldPosition().Subtract(joint.WorldPosition())
+		to := ik.positions[i+1].Subtract(joint.WorldPosition())
+		ikRotateTowards(joint, from, to, 1)
+	}
+}
+
+// ikReach places a point along the line from anchor to point at the given
+// distance from the anchor
+func ikReach(anchor, point matrix.Vec3, length matrix.Float) matrix.Vec3 {
+	dir := point.Subtract(anchor)
+	if dir.LengthSquared() < ikEpsilon*ikEpsilon {
+		return anchor
+	}
+	return anchor.Add(dir.Normal().Scale(length))
+}
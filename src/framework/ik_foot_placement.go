@@ -0,0 +1,132 @@
+/******************************************************************************/
+/* ik_foot_placement.go                                                       */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package framework
+
+import (
+	"kaijuengine.com/engine/graviton"
+	"kaijuengine.com/matrix"
+)
+
+// FootPlacement plants a single foot on the ground below it. A ray is cast
+// from RayHeight above the animated foot down to RayDepth below it, and the
+// leg is then solved with two bone IK so the ankle rests FootHeight above the
+// surface that was hit. When AlignToNormal is set, the foot is also rotated
+// to match the slope of the ground.
+type FootPlacement struct {
+	Leg           TwoBoneIK
+	Raycaster     IKRaycaster
+	Up            matrix.Vec3
+	RayHeight     matrix.Float
+	RayDepth      matrix.Float
+	FootHeight    matrix.Float
+	AlignToNormal bool
+	hit           graviton.Hit
+	grounded      bool
+}
+
+// FootPlacementRig plants multiple feet at once. When a Pelvis is supplied, it
+// is lowered so that the foot that needs to reach the furthest down can touch
+// the ground without over extending the leg.
+type FootPlacementRig struct {
+	Pelvis *matrix.Transform
+	Feet   []*FootPlacement
+	Weight matrix.Float
+}
+
+func NewFootPlacement(hip, knee, ankle *matrix.Transform, raycaster IKRaycaster) *FootPlacement {
+	return &FootPlacement{
+		Leg:           *NewTwoBoneIK(hip, knee, ankle),
+		Raycaster:     raycaster,
+		Up:            matrix.Vec3Up(),
+		RayHeight:     0.5,
+		RayDepth:      0.5,
+		AlignToNormal: true,
+	}
+}
+
+// Grounded reports if the last probe found ground within reach of the foot
+func (f *FootPlacement) Grounded() bool { return f.grounded }
+
+// GroundHit is the surface the foot was placed on during the last solve
+func (f *FootPlacement) GroundHit() graviton.Hit { return f.hit }
+
+// Probe casts the ground ray for the foot and returns how far (along Up) the
+// ankle must move to be planted. The result is negative when the ground is
+// below the animated foot.
+func (f *FootPlacement) Probe() (matrix.Float, bool) {
+	f.grounded = false
+	if f.Raycaster == nil || !f.Leg.IsValid() {
+		return 0, false
+	}
+	up := f.up()
+	foot := f.Leg.End.WorldPosition()
+	from := foot.Add(up.Scale(f.RayHeight))
+	to := foot.Subtract(up.Scale(f.RayDepth))
+	hit, ok := f.Raycaster.Raycast(from, to)
+	if !ok {
+		return 0, false
+	}
+	f.hit = hit
+	f.grounded = true
+	planted := hit.Point.Add(up.Scale(f.FootHeight))
+	return matrix.Vec3Dot(planted.Subtract(foot), up), true
+}
+
+func (f *FootPlacement) Solve() {
+	if _, ok := f.Probe(); !ok {
+		return
+	}
+	f.plant()
+}
+
+func (f *FootPlacement) plant() {
+	up := f.up()
+	f.Leg.Target = f.hit.Point.Add(up.Scale(f.FootHeight))
+	f.Leg.Solve()
+	if f.AlignToNormal {
+		weight := ikClampWeight(f.Leg.Weight)
+		ikRotateTowards(f.Leg.End, up, f.hit.Normal, weight)
+	}
+}
+
+func (f *FootPlacement) up() matrix.Vec3 {
+	if f.Up.LengthSquared() < ikEpsilon {
+		return matrix.Vec3Up()
+	}
+	return f.Up.Normal()
+}
+
+func NewFootPlacementRig(pelvis *matrix.Transform, feet ...*FootPlacement) *FootPlacementRig {
+	return &FootPlacementRig{
+		Pelvis: pelvis,
+		Feet:   feet,
+		Weight: 1,
+	}
+}
+
+func (r *FootPlacementRig) Solve() {
+	weight := ikClampWeight(r.Weight)
+	if weight <= 0 {
+		return
+	}
+	lowest := matrix.Float(0)
+	for _, f := range r.Feet {
+		f.Leg.Weight = weight
+		if offset, ok := f.Probe(); ok {
+			lowest = min(lowest, offset)
+		}
+	}
+	if r.Pelvis != nil && lowest < 0 && len(r.Feet) > 0 {
+		up := r.Feet[0].up()
+		r.Pelvis.SetWorldPosition(r.Pelvis.WorldPosition().Add(up.Scale(lowest * weight)))
+	}
+	for _, f := range r.Feet {
+		if f.grounded {
+			f.plant()
+		}
+	}
+}
@@ -0,0 +1,45 @@
+/******************************************************************************/
+/* ik_look_at.go                                                              */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package framework
+
+import "kaijuengine.com/matrix"
+
+// LookAtConstraint rotates a single joint (commonly a head, eye, or turret)
+// so that its local Forward axis points at the Target. MaxAngle (in degrees)
+// limits how far the joint may turn away from its sampled orientation, a
+// value of zero or less means there is no limit.
+type LookAtConstraint struct {
+	Bone     *matrix.Transform
+	Target   matrix.Vec3
+	Forward  matrix.Vec3
+	MaxAngle matrix.Float
+	Weight   matrix.Float
+}
+
+func NewLookAtConstraint(bone *matrix.Transform) *LookAtConstraint {
+	return &LookAtConstraint{
+		Bone:    bone,
+		Forward: matrix.Vec3Forward(),
+		Weight:  1,
+	}
+}
+
+func (ik *LookAtConstraint) Solve() {
+	weight := ikClampWeight(ik.Weight)
+	if ik.Bone == nil || weight <= 0 {
+		return
+	}
+	forward := ikWorldRotation(ik.Bone).MultiplyVec3(ik.Forward)
+	desired := ik.Target.Subtract(ik.Bone.WorldPosition())
+	if ik.MaxAngle > 0 {
+		angle := matrix.Rad2Deg(ikAngleBetween(forward, desired))
+		if angle > ik.MaxAngle {
+			weight *= ik.MaxAngle / angle
+		}
+	}
+	ikRotateTowards(ik.Bone, forward, desired, weight)
+}
@@ -0,0 +1,171 @@
+/******************************************************************************/
+/* ik_test.go                                                                 */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package framework
+
+import (
+	"testing"
+
+	"kaijuengine.com/engine/graviton"
+	"kaijuengine.com/matrix"
+)
+
+func newIKTestChain(offsets ...matrix.Vec3) []*matrix.Transform {
+	joints := make([]*matrix.Transform, len(offsets))
+	for i := range offsets {
+		joints[i] = &matrix.Transform{}
+		joints[i].SetupRawTransform()
+		if i > 0 {
+			joints[i].SetParent(joints[i-1])
+		}
+		joints[i].SetLocalPosition(offsets[i])
+	}
+	return joints
+}
+
+func TestTwoBoneIKReachesTarget(t *testing.T) {
+	j := newIKTestChain(matrix.Vec3Zero(), matrix.NewVec3(0, -1, 0), matrix.NewVec3(0, -1, 0))
+	ik := NewTwoBoneIK(j[0], j[1], j[2])
+	ik.Target = matrix.NewVec3(0.5, -1.2, 0.3)
+	ik.Pole = matrix.NewVec3(0, -1, 2)
+	ik.UsePole = true
+	ik.Solve()
+	if got := j[2].WorldPosition(); !matrix.Vec3ApproxTo(got, ik.Target, 0.01) {
+		t.Fatalf("expected end joint at %v, got %v", ik.Target, got)
+	}
+	if j[1].WorldPosition().Z() <= 0 {
+		t.Fatalf("expected the knee to bend towards the pole, got %v", j[1].WorldPosition())
+	}
+	if l := j[1].WorldPosition().Distance(j[0].WorldPosition()); matrix.Abs(l-1) > 0.001 {
+		t.Fatalf("expected bone length to be preserved, got %f", l)
+	}
+}
+
+func TestTwoBoneIKUnreachableTargetStretches(t *testing.T) {
+	j := newIKTestChain(matrix.Vec3Zero(), matrix.NewVec3(1, 0, 0), matrix.NewVec3(1, 0, 0))
+	j[1].SetRotation(matrix.NewVec3(0, 0, 45))
+	ik := NewTwoBoneIK(j[0], j[1], j[2])
+	ik.Target = matrix.NewVec3(0, 10, 0)
+	ik.Solve()
+	end := j[2].WorldPosition()
+	if !matrix.Vec3ApproxTo(end.Normal(), matrix.Vec3Up(), 0.01) || end.Length() > 2.001 {
+		t.Fatalf("expected chain to point at the target at full length, got %v", end)
+	}
+}
+
+func TestTwoBoneIKZeroWeightKeepsPose(t *testing.T) {
+	j := newIKTestChain(matrix.Vec3Zero(), matrix.NewVec3(0, -1, 0), matrix.NewVec3(0, -1, 0))
+	before := j[2].WorldPosition()
+	ik := NewTwoBoneIK(j[0], j[1], j[2])
+	ik.Target = matrix.NewVec3(1, -1, 0)
+	ik.Weight = 0
+	ik.Solve()
+	if !matrix.Vec3Approx(before, j[2].WorldPosition()) {
+		t.Fatalf("expected pose to be untouched, got %v", j[2].WorldPosition())
+	}
+}
+
+func TestFABRIKChainReachesTarget(t *testing.T) {
+	j := newIKTestChain(matrix.Vec3Zero(), matrix.NewVec3(1, 0, 0),
+		matrix.NewVec3(1, 0, 0), matrix.NewVec3(1, 0, 0))
+	ik := NewFABRIKChain(j...)
+	ik.Target = matrix.NewVec3(1, 1.5, 0.5)
+	ik.Solve()
+	if got := j[3].WorldPosition(); !matrix.Vec3ApproxTo(got, ik.Target, 0.01) {
+		t.Fatalf("expected tip at %v, got %v", ik.Target, got)
+	}
+	for i := 1; i < len(j); i++ {
+		l := j[i].WorldPosition().Distance(j[i-1].WorldPosition())
+		if matrix.Abs(l-1) > 0.001 {
+			t.Fatalf("expected segment %d length to be preserved, got %f", i, l)
+		}
+	}
+}
+
+func TestCCDChainReachesTarget(t *testing.T) {
+	j := newIKTestChain(matrix.Vec3Zero(), matrix.NewVec3(1, 0, 0),
+		matrix.NewVec3(1, 0, 0), matrix.NewVec3(1, 0, 0))
+	ik := NewCCDChain(j...)
+	ik.Iterations = 30
+	ik.Target = matrix.NewVec3(-1, 1.5, 0.5)
+	ik.Solve()
+	if got := j[3].WorldPosition(); !matrix.Vec3ApproxTo(got, ik.Target, 0.01) {
+		t.Fatalf("expected tip at %v, got %v", ik.Target, got)
+	}
+}
+
+func TestLookAtConstraintFacesTarget(t *testing.T) {
+	j := newIKTestChain(matrix.NewVec3(0, 1, 0))
+	ik := NewLookAtConstraint(j[0])
+	ik.Target = matrix.NewVec3(5, 1, 0)
+	ik.Solve()
+	forward := matrix.QuaternionFromEuler(j[0].WorldRotation()).MultiplyVec3(ik.Forward)
+	if !matrix.Vec3ApproxTo(forward, matrix.Vec3Right(), 0.001) {
+		t.Fatalf("expected bone to face +X, got %v", forward)
+	}
+}
+
+func TestLookAtConstraintRespectsMaxAngle(t *testing.T) {
+	j := newIKTestChain(matrix.Vec3Zero())
+	ik := NewLookAtConstraint(j[0])
+	ik.Target = matrix.NewVec3(5, 0, 0)
+	ik.MaxAngle = 30
+	ik.Solve()
+	forward := matrix.QuaternionFromEuler(j[0].WorldRotation()).MultiplyVec3(ik.Forward)
+	if angle := matrix.Rad2Deg(ikAngleBetween(forward, matrix.Vec3Forward())); matrix.Abs(angle-30) > 0.1 {
+		t.Fatalf("expected the bone to turn 30 degrees, turned %f", angle)
+	}
+}
+
+func newIKTestTerrain(t *testing.T, height matrix.Float) TerrainRaycaster {
+	const resolution = 4
+	heights := make([]matrix.Float, resolution*resolution)
+	for i := range heights {
+		heights[i] = height
+	}
+	terrain, err := graviton.NewTerrainCollision(resolution, matrix.NewVec2(20, 20), heights, -10, 10)
+	if err != nil {
+		t.Fatalf("failed to create terrain collision: %v", err)
+	}
+	return TerrainRaycaster{Terrain: terrain}
+}
+
+func TestFootPlacementPlantsOnTerrain(t *testing.T) {
+	j := newIKTestChain(matrix.NewVec3(0, 1, 0), matrix.NewVec3(0, -0.5, 0.05), matrix.NewVec3(0, -0.5, -0.05))
+	foot := NewFootPlacement(j[0], j[1], j[2], newIKTestTerrain(t, 0.2))
+	foot.FootHeight = 0.1
+	foot.Solve()
+	if !foot.Grounded() {
+		t.Fatal("expected the foot to find the terrain")
+	}
+	if got := j[2].WorldPosition().Y(); matrix.Abs(got-0.3) > 0.01 {
+		t.Fatalf("expected ankle to rest at 0.3, got %f", got)
+	}
+}
+
+func TestFootPlacementRigLowersPelvis(t *testing.T) {
+	pelvis := newIKTestChain(matrix.NewVec3(0, 1, 0))[0]
+	makeLeg := func(x matrix.Float) []*matrix.Transform {
+		leg := newIKTestChain(matrix.NewVec3(x, 1, 0), matrix.NewVec3(0, -0.5, 0.05), matrix.NewVec3(0, -0.5, -0.05))
+		leg[0].SetParent(pelvis)
+		return leg
+	}
+	left := makeLeg(-0.2)
+	right := makeLeg(0.2)
+	terrain := newIKTestTerrain(t, -0.25)
+	rig := NewFootPlacementRig(pelvis,
+		NewFootPlacement(left[0], left[1], left[2], terrain),
+		NewFootPlacement(right[0], right[1], right[2], terrain))
+	rig.Solve()
+	if got := pelvis.WorldPosition().Y(); matrix.Abs(got-0.75) > 0.01 {
+		t.Fatalf("expected pelvis to lower to 0.75, got %f", got)
+	}
+	for _, leg := range [][]*matrix.Transform{left, right} {
+		if got := leg[2].WorldPosition().Y(); matrix.Abs(got+0.25) > 0.01 {
+			t.Fatalf("expected ankle on the ground at -0.25, got %f", got)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+/******************************************************************************/
+/* ik_two_bone.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package framework
+
+import "kaijuengine.com/matrix"
+
+// TwoBoneIK analytically solves a three joint chain such as an arm (shoulder,
+// elbow, wrist) or a leg (hip, knee, ankle) so that the End joint reaches the
+// Target. When UsePole is set, the chain will bend towards the Pole position,
+// otherwise the bend direction of the sampled pose is preserved.
+type TwoBoneIK struct {
+	Root    *matrix.Transform
+	Mid     *matrix.Transform
+	End     *matrix.Transform
+	Target  matrix.Vec3
+	Pole    matrix.Vec3
+	UsePole bool
+	Weight  matrix.Float
+}
+
+func NewTwoBoneIK(root, mid, end *matrix.Transform) *TwoBoneIK {
+	return &TwoBoneIK{
+		Root:   root,
+		Mid:    mid,
+		End:    end,
+		Weight: 1,
+	}
+}
+
+func (ik *TwoBoneIK) IsValid() bool {
+	return ik.Root != nil && ik.Mid != nil && ik.End != nil
+}
+
+func (ik *TwoBoneIK) Solve() {
+	weight := ikClampWeight(ik.Weight)
+	if !ik.IsValid() || weight <= 0 {
+		return
+	}
+	a := ik.Root.WorldPosition()
+	b := ik.Mid.WorldPosition()
+	c := ik.End.WorldPosition()
+	t := ik.Target
+	lab := b.Distance(a)
+	lcb := b.Distance(c)
+	if lab < ikEpsilon || lcb < ikEpsilon {
+		return
+	}
+	lat := matrix.Clamp(t.Distance(a), ikEpsilon, lab+lcb-ikEpsilon)
+	ac := c.Subtract(a)
+	ab := b.Subtract(a)
+	at := t.Subtract(a)
+	// Interior angles of the current pose
+	acAB0 := ikAngleBetween(ac, ab)
+	baBC0 := ikAngleBetween(a.Subtract(b), c.Subtract(b))
+	acAT0 := ikAngleBetween(ac, at)
+	// Interior angles required to reach the target (law of cosines)
+	acAB1 := matrix.Acos(matrix.Clamp((lcb*lcb-lab*lab-lat*lat)/(-2*lab*lat), -1, 1))
+	baBC1 := matrix.Acos(matrix.Clamp((lat*lat-lab*lab-lcb*lcb)/(-2*lab*lcb), -1, 1))
+	axis0 := matrix.Vec3Cross(ac, ab)
+	if axis0.LengthSquared() < ikEpsilon*ikEpsilon {
+		// The chain is fully straight, pick a bend axis from the pole or the
+		// orientation of the middle joint
+		bend := ik.Mid.Up()
+		if ik.UsePole {
+			bend = ik.Pole.Subtract(a)
+		}
+		axis0 = matrix.Vec3Cross(ac, bend)
+		if axis0.LengthSquared() < ikEpsilon*ikEpsilon {
+			axis0 = ac.Orthogonal()
+		}
+	}
+	axis0.Normalize()
+	axis1 := matrix.Vec3Cross(ac, at)
+	// The middle joint is rotated first so that it is carried along with the
+	// root rotation afterwards
+	ikRotateAxisAngle(ik.Mid, axis0, (baBC1-baBC0)*weight)
+	ikRotateAxisAngle(ik.Root, axis0, (acAB1-acAB0)*weight)
+	ikRotateAxisAngle(ik.Root, axis1, acAT0*weight)
+	if ik.UsePole {
+		ik.applyPole(weight)
+	}
+}
+
+// applyPole twists the whole chain around the root to target axis so that the
+// middle joint faces the pole position
+func (ik *TwoBoneIK) applyPole(weight matrix.Float) {
+	a := ik.Root.WorldPosition()
+	axis := ik.End.WorldPosition().Subtract(a)
+	if axis.LengthSquared() < ikEpsilon {
+		return
+	}
+	axis.Normalize()
+	mid := ik.Mid.WorldPosition().Subtract(a)
+	pole := ik.Pole.Subtract(a)
+	mid = mid.Subtract(axis.Scale(matrix.Vec3Dot(mid, axis)))
+	pole = pole.Subtract(axis.Scale(matrix.Vec3Dot(pole, axis)))
+	if mid.LengthSquared() < ikEpsilon || pole.LengthSquared() < ikEpsilon {
+		return
+	}
+	angle := ikAngleBetween(mid, pole)
+	if matrix.Vec3Dot(matrix.Vec3Cross(mid, pole), axis) < 0 {
+		angle = -angle
+	}
+	ikRotateAxisAngle(ik.Root, axis, angle*weight)
+}
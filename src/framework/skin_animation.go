@@ -9,18 +9,45 @@ package framework
 import (
 	"math"
 
+	"kaijuengine.com/engine/systems/events"
 	"kaijuengine.com/matrix"
 	"kaijuengine.com/rendering/loaders/kaiju_mesh"
 	"kaijuengine.com/rendering/loaders/load_result"
 )
 
 type SkinAnimation struct {
-	Animation     kaiju_mesh.KaijuMeshAnimation
+	Animation kaiju_mesh.KaijuMeshAnimation
+	// OnEvent is called for each of the named animation events that the
+	// playhead passes over during Update
+	OnEvent       events.EventWithArg[kaiju_mesh.AnimEvent]
 	frame         int
 	nextFrame     int
 	time          float64
 	totalTime     float64
 	absFrameTimes []float64
+	looped        bool
+	rootMotion    skinRootMotion
+	tracks        []kaiju_mesh.AnimTrack
+	samples       []SkinAnimationSample
+}
+
+// SkinAnimationSample is the interpolated value of a single path of a bone at
+// the current time of the animation
+type SkinAnimationSample struct {
+	BoneId   int32
+	PathType kaiju_mesh.AnimationPathType
+	Data     [4]float32
+}
+
+type skinRootMotion struct {
+	enabled bool
+	primed  bool
+	boneId  int32
+	mask    matrix.Vec3
+	start   matrix.Vec3
+	end     matrix.Vec3
+	last    matrix.Vec3
+	delta   matrix.Vec3
 }
 
 type SkinAnimationFrame struct {
@@ -39,6 +66,8 @@ func NewSkinAnimation(anim kaiju_mesh.KaijuMeshAnimation) SkinAnimation {
 		s.absFrameTimes[i] = s.totalTime
 		s.totalTime += float64(s.Animation.Frames[i].Time)
 	}
+	s.tracks = s.Animation.Tracks()
+	s.samples = make([]SkinAnimationSample, len(s.tracks))
 	return s
 }
 
@@ -87,6 +116,7 @@ func (a *SkinAnimation) NextFrame() SkinAnimationFrame {
 }
 
 func (a *SkinAnimation) Update(deltaTime float64) {
+	a.looped = false
 	if len(a.Animation.Frames) <= 1 {
 		return
 	}
@@ -94,7 +124,9 @@ func (a *SkinAnimation) Update(deltaTime float64) {
 	if a.frame < 0 {
 		a.frame = 0
 		a.nextFrame = min(a.frame+1, len(a.Animation.Frames)-1)
+		a.fireEvents(-1, 0)
 	} else {
+		prevTime := a.time
 		a.time += deltaTime
 		nextTime := a.absFrameTimes[a.nextFrame]
 		for a.time >= nextTime {
@@ -102,11 +134,136 @@ func (a *SkinAnimation) Update(deltaTime float64) {
 			if a.time > a.totalTime {
 				a.time = math.Mod(a.time, a.totalTime)
 				a.frame = 0
+				a.looped = true
 			}
 			a.nextFrame = min(a.frame+1, len(a.Animation.Frames)-1)
 			nextTime = a.absFrameTimes[a.nextFrame]
 		}
+		if a.looped {
+			a.fireEvents(prevTime, a.totalTime)
+			a.fireEvents(-1, a.time)
+		} else {
+			a.fireEvents(prevTime, a.time)
+		}
+	}
+}
+
+// Looped reports if the animation wrapped back to the start during the last
+// call to Update
+func (a *SkinAnimation) Looped() bool { return a.looped }
+
+// Time is the current playhead time (in seconds) of the animation
+func (a *SkinAnimation) Time() float64 { return a.time }
+
+// fireEvents executes OnEvent for each event within the (from, to] range
+func (a *SkinAnimation) fireEvents(from, to float64) {
+	if a.OnEvent.IsEmpty() {
+		return
+	}
+	for i := range a.Animation.Events {
+		t := float64(a.Animation.Events[i].Time)
+		if t > to {
+			break
+		}
+		if t > from {
+			a.OnEvent.Execute(a.Animation.Events[i])
+		}
+	}
+}
+
+// EnableRootMotion will extract the motion of the translation track of the
+// given bone so that it can be applied to the entity (or a character
+// controller) rather than the skeleton. The mask selects which axes are
+// extracted, typically (1, 0, 1) so that vertical motion (like a jump) stays
+// within the animation.
+func (a *SkinAnimation) EnableRootMotion(boneId int32, mask matrix.Vec3) {
+	a.rootMotion = skinRootMotion{
+		enabled: true,
+		boneId:  boneId,
+		mask:    mask,
+	}
+	first, last := -1, -1
+	for i := range a.Animation.Frames {
+		if a.findBoneKey(i, boneId, load_result.AnimPathTranslation) != nil {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first >= 0 {
+		a.rootMotion.start = matrix.Vec3FromSlice(
+			a.findBoneKey(first, boneId, load_result.AnimPathTranslation).Data[:])
+		a.rootMotion.end = matrix.Vec3FromSlice(
+			a.findBoneKey(last, boneId, load_result.AnimPathTranslation).Data[:])
+	}
+}
+
+func (a *SkinAnimation) DisableRootMotion() { a.rootMotion = skinRootMotion{} }
+
+func (a *SkinAnimation) RootMotionEnabled() bool { return a.rootMotion.enabled }
+
+func (a *SkinAnimation) RootMotionBone() int32 { return a.rootMotion.boneId }
+
+// ExtractRootMotion should be called with every sampled bone value before it
+// is applied to the skeleton. If the bone is the root motion bone, the masked
+// motion since the last sample is accumulated (see ConsumeRootMotion) and the
+// returned data will have the masked axes pinned to the start of the clip.
+func (a *SkinAnimation) ExtractRootMotion(boneId int32, pathType kaiju_mesh.AnimationPathType, data [4]float32) [4]float32 {
+	rm := &a.rootMotion
+	if !rm.enabled || boneId != rm.boneId || pathType != load_result.AnimPathTranslation {
+		return data
+	}
+	pos := matrix.Vec3FromSlice(data[:])
+	if rm.primed {
+		var delta matrix.Vec3
+		if a.looped {
+			delta = rm.end.Subtract(rm.last).Add(pos.Subtract(rm.start))
+		} else {
+			delta = pos.Subtract(rm.last)
+		}
+		rm.delta.AddAssign(delta.Multiply(rm.mask))
+	}
+	rm.last = pos
+	rm.primed = true
+	keep := matrix.Vec3One().Subtract(rm.mask)
+	pinned := pos.Multiply(keep).Add(rm.start.Multiply(rm.mask))
+	return [4]float32{pinned.X(), pinned.Y(), pinned.Z(), data[3]}
+}
+
+// ConsumeRootMotion returns the root motion (in the space of the root bone's
+// parent) accumulated since the last time it was consumed
+func (a *SkinAnimation) ConsumeRootMotion() matrix.Vec3 {
+	delta := a.rootMotion.delta
+	a.rootMotion.delta = matrix.Vec3Zero()
+	return delta
+}
+
+func (a *SkinAnimation) findBoneKey(frame int, boneId int32, pathType kaiju_mesh.AnimationPathType) *kaiju_mesh.AnimBone {
+	for i := range a.Animation.Frames[frame].Bones {
+		bone := &a.Animation.Frames[frame].Bones[i]
+		if bone.NodeIndex == int(boneId) && bone.PathType == pathType {
+			return bone
+		}
+	}
+	return nil
+}
+
+// Sample interpolates every animated bone path at the current time of the
+// animation. Unlike walking the bones of the current frame, this also updates
+// the bones that have no key within the current frame, as is the case for
+// animations that have been compressed. The returned slice is reused by each
+// call to Sample.
+func (a *SkinAnimation) Sample() []SkinAnimationSample {
+	for i := range a.tracks {
+		t := &a.tracks[i]
+		a.samples[i] = SkinAnimationSample{
+			BoneId:   int32(t.NodeIndex),
+			PathType: t.PathType,
+			Data:     t.Sample(float32(a.time)),
+		}
 	}
+	return a.samples
 }
 
 func (a *SkinAnimation) Interpolate(from, to SkinAnimationFrame) [4]float32 {
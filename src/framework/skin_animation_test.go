@@ -0,0 +1,103 @@
+/******************************************************************************/
+/* skin_animation_test.go                                                     */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package framework
+
+import (
+	"testing"
+
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering/loaders/kaiju_mesh"
+)
+
+func testWalkAnimation() kaiju_mesh.KaijuMeshAnimation {
+	key := func(time float32, z matrix.Float) kaiju_mesh.AnimKeyFrame {
+		return kaiju_mesh.AnimKeyFrame{
+			Time: time,
+			Bones: []kaiju_mesh.AnimBone{{
+				NodeIndex: 0,
+				PathType:  kaiju_mesh.AnimPathTranslation,
+				Data:      matrix.Vec3{0, 1, z}.AsAligned16(),
+			}},
+		}
+	}
+	anim := kaiju_mesh.KaijuMeshAnimation{
+		Name:   "walk",
+		Frames: []kaiju_mesh.AnimKeyFrame{key(0.5, 0), key(0.5, 1), key(0, 2)},
+	}
+	anim.AddEvent("start", 0)
+	anim.AddEvent("step", 0.25)
+	anim.AddEvent("step", 0.75)
+	return anim
+}
+
+func TestSkinAnimationFiresEventsAcrossLoop(t *testing.T) {
+	anim := NewSkinAnimation(testWalkAnimation())
+	fired := []float32{}
+	anim.OnEvent.Add(func(e kaiju_mesh.AnimEvent) { fired = append(fired, e.Time) })
+	anim.Update(0)
+	if len(fired) != 1 || fired[0] != 0 {
+		t.Fatalf("expected the start event on the first update, got %v", fired)
+	}
+	anim.Update(0.5)
+	if len(fired) != 2 || fired[1] != 0.25 {
+		t.Fatalf("expected the first step event, got %v", fired)
+	}
+	anim.Update(0.8)
+	if !anim.Looped() {
+		t.Fatal("expected the animation to loop")
+	}
+	want := []float32{0, 0.25, 0.75, 0, 0.25}
+	if len(fired) != len(want) {
+		t.Fatalf("fired events = %v, want %v", fired, want)
+	}
+	for i := range want {
+		if fired[i] != want[i] {
+			t.Fatalf("fired events = %v, want %v", fired, want)
+		}
+	}
+}
+
+func sampleRootBone(anim *SkinAnimation) [4]float32 {
+	frame := anim.CurrentFrame()
+	frame.Bone = &frame.Key.Bones[0]
+	next, ok := anim.FindNextFrameForBone(0, frame.Bone.PathType)
+	if !ok {
+		next = frame
+	}
+	data := anim.Interpolate(frame, next)
+	return anim.ExtractRootMotion(0, frame.Bone.PathType, data)
+}
+
+func TestSkinAnimationRootMotion(t *testing.T) {
+	anim := NewSkinAnimation(testWalkAnimation())
+	anim.EnableRootMotion(0, matrix.NewVec3(1, 0, 1))
+	total := matrix.Vec3Zero()
+	anim.Update(0)
+	sampleRootBone(&anim)
+	total.AddAssign(anim.ConsumeRootMotion())
+	for range 3 {
+		anim.Update(0.25)
+		pinned := sampleRootBone(&anim)
+		if !matrix.Vec3Approx(matrix.Vec3FromSlice(pinned[:]), matrix.Vec3{0, 1, 0}) {
+			t.Fatalf("expected the root bone to be pinned, got %v", pinned)
+		}
+		total.AddAssign(anim.ConsumeRootMotion())
+	}
+	if !matrix.Vec3Approx(total, matrix.Vec3{0, 0, 1.5}) {
+		t.Fatalf("root motion = %v, want (0, 0, 1.5)", total)
+	}
+	// Looping should continue the motion rather than snapping back
+	anim.Update(0.5)
+	sampleRootBone(&anim)
+	if !anim.Looped() {
+		t.Fatal("expected the animation to loop")
+	}
+	total.AddAssign(anim.ConsumeRootMotion())
+	if !matrix.Vec3Approx(total, matrix.Vec3{0, 0, 2.5}) {
+		t.Fatalf("root motion after loop = %v, want (0, 0, 2.5)", total)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"strings"
 
 	"kaijuengine.com/klib"
+	"kaijuengine.com/tools/font_to_msdf"
 )
 
 const binDir = "../tools/content_tools/"
@@ -30,6 +31,7 @@ type Rect struct {
 }
 
 type Glyph struct {
+	Index       int     `json:"index"`
 	Unicode     int     `json:"unicode"`
 	Advance     float32 `json:"advance"`
 	PlaneBounds Rect    `json:"planeBounds"`
@@ -92,12 +94,14 @@ func processFile(ttfName string) {
 	binFile := filepath.Join(ttfDir, "out", name+".bin")
 	pngFile := filepath.Join(ttfDir, "out", name+".png")
 	charSetFile := filepath.Join(ttfDir, "charset.txt")
+	glyphSetFile := filepath.Join(ttfDir, "out", name+".glyphset.txt")
+	font, shaping := klib.MustReturn2(font_to_msdf.PrepareGlyphset(ttfFile, charSetFile, glyphSetFile))
 
 	cmd := exec.Command(msdfAtlasGenPath(),
 		"-font", ttfFile,
 		"-pxrange", "4",
 		"-size", "64",
-		"-charset", charSetFile,
+		"-glyphset", glyphSetFile,
 		"-fontname", ttfName,
 		"-type", "msdf",
 		"-format", "png",
@@ -128,7 +132,7 @@ func processFile(ttfName string) {
 	binary.Write(fout, binary.LittleEndian, fontData.Metrics.UnderlineY)
 	binary.Write(fout, binary.LittleEndian, fontData.Metrics.UnderlineThickness)
 	for _, glyph := range fontData.Glyphs {
-		binary.Write(fout, binary.LittleEndian, int32(glyph.Unicode))
+		binary.Write(fout, binary.LittleEndian, int32(font.GlyphRune(uint16(glyph.Index))))
 		binary.Write(fout, binary.LittleEndian, glyph.Advance)
 		binary.Write(fout, binary.LittleEndian, glyph.PlaneBounds.Left)
 		binary.Write(fout, binary.LittleEndian, glyph.PlaneBounds.Top)
@@ -139,7 +143,9 @@ func processFile(ttfName string) {
 		binary.Write(fout, binary.LittleEndian, glyph.AtlasBounds.Right)
 		binary.Write(fout, binary.LittleEndian, glyph.AtlasBounds.Bottom)
 	}
+	klib.Must(shaping.Encode(fout))
 	os.Remove(jsonFile)
+	os.Remove(glyphSetFile)
 }
 
 func main() {
@@ -0,0 +1,86 @@
+/******************************************************************************/
+/* integration_test_accessibility.go                                          */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+const accessibilityScreenshotOutput = "integration_test_accessibility.png"
+
+func init() {
+	tests["accessibility"] = IntegrationTestAccessibility
+}
+
+func IntegrationTestAccessibility(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, accessibilityHTML, "", nil, nil, nil)
+	spoken := make([]string, 0)
+	doc.EnableScreenReader(document.SpeakerFunc(func(text string, interrupt bool) {
+		spoken = append(spoken, text)
+	}))
+	fail := func(err error) {
+		takeScreenshotToFile(host, accessibilityScreenshotOutput)
+		slog.Error("accessibility integration test failed", "error", err)
+		os.Exit(1)
+	}
+	host.RunAfterFrames(4, func() {
+		tree := doc.AccessibilityTree().Dump()
+		for _, want := range []string{`heading "Options" level=1`, `checkbox "Subtitles"`, `button "Continue"`} {
+			if !strings.Contains(tree, want) {
+				fail(fmt.Errorf("expected %s in the tree\n%s", want, tree))
+			}
+		}
+		play, _ := doc.GetElementById("continue")
+		doc.FocusElement(play)
+	})
+	host.RunAfterFrames(8, func() {
+		if !slices.Contains(spoken, "Continue, button, Go back to the game") {
+			fail(fmt.Errorf("expected the focused button to be spoken, got %q", spoken))
+		}
+		status, _ := doc.GetElementById("status")
+		status.Children[0].UI.ToLabel().SetText("Settings saved")
+		doc.RefreshScreenReader()
+		if spoken[len(spoken)-1] != "Settings saved" {
+			fail(fmt.Errorf("expected the status to be spoken, got %q", spoken))
+		}
+		takeScreenshotToFile(host, accessibilityScreenshotOutput)
+		os.Exit(0)
+	})
+}
+
+const accessibilityHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				color: white;
+				margin: 24px;
+			}
+			button { display: block; margin-top: 12px; }
+		</style>
+	</head>
+	<body>
+		<h1>Options</h1>
+		<label><input type="checkbox"> Subtitles</label>
+		<button id="continue" aria-describedby="continue-hint">Continue</button>
+		<span id="continue-hint" hidden>Go back to the game</span>
+		<div id="status" role="status">Nothing changed</div>
+	</body>
+</html>
+`
@@ -0,0 +1,152 @@
+/******************************************************************************/
+/* integration_test_canvas.go                                                 */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"os"
+	"reflect"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/canvas"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/plugins"
+	"kaijuengine.com/plugins/lua"
+)
+
+const canvasScreenshotOutput = "integration_test_canvas.png"
+
+func init() {
+	tests["canvas"] = IntegrationTestCanvas
+}
+
+func IntegrationTestCanvas(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, canvasHTML, "", nil, nil, nil)
+	elm, ok := doc.GetElementById("graph")
+	if !ok || !elm.UI.IsType(ui.ElementTypeCanvas) {
+		slog.Error("canvas integration test failed", "error", "expected the canvas element")
+		os.Exit(1)
+	}
+	ctx := elm.UI.ToCanvas().Context()
+	drawCanvasGraph(ctx)
+	if err := drawCanvasFromPlugin(host, doc); err != nil {
+		slog.Error("canvas integration test failed", "error", err)
+		os.Exit(1)
+	}
+	host.RunAfterFrames(8, func() {
+		// Draw again once the texture is on the GPU so the dirty region
+		// is uploaded rather than the whole canvas
+		ctx.SetFillColor(matrix.ColorWhite())
+		ctx.FillText("Radar", 10, 24)
+	})
+	host.RunAfterFrames(12, func() {
+		if err := assertCanvas(doc, ctx); err != nil {
+			takeScreenshotToFile(host, canvasScreenshotOutput)
+			slog.Error("canvas integration test failed", "error", err)
+			os.Exit(1)
+		}
+		takeScreenshotToFile(host, canvasScreenshotOutput)
+		os.Exit(0)
+	})
+}
+
+func drawCanvasGraph(ctx *canvas.Context) {
+	bg := ctx.CreateRadialGradient(150, 100, 0, 150, 100, 90)
+	bg.AddColorStop(0, matrix.ColorRGBInt(41, 128, 185))
+	bg.AddColorStop(1, matrix.ColorRGBAInt(41, 128, 185, 0))
+	ctx.SetFillGradient(bg)
+	ctx.FillRect(0, 0, 300, 200)
+	ctx.SetStrokeColor(matrix.ColorRGBInt(238, 241, 246))
+	ctx.SetLineWidth(2)
+	ctx.SetLineJoin(canvas.LineJoinRound)
+	values := []matrix.Float{0.9, 0.5, 0.7, 0.3, 0.8}
+	ctx.BeginPath()
+	for i, v := range values {
+		angle := matrix.Float(i)*2*math.Pi/matrix.Float(len(values)) - math.Pi/2
+		x, y := 150+80*v*matrix.Cos(angle), 100+80*v*matrix.Sin(angle)
+		if i == 0 {
+			ctx.MoveTo(x, y)
+		} else {
+			ctx.LineTo(x, y)
+		}
+	}
+	ctx.ClosePath()
+	ctx.SetFillColor(matrix.ColorRGBAInt(241, 196, 15, 160))
+	ctx.Fill()
+	ctx.Stroke()
+	ctx.BeginPath()
+	ctx.Arc(150, 100, 80, 0, 2*math.Pi, false)
+	ctx.Stroke()
+}
+
+// drawCanvasFromPlugin draws on the "plugin" canvas through the element from
+// Lua, the same way a plugin script would reach the canvas of a document
+func drawCanvasFromPlugin(host *engine.Host, doc *document.Document) error {
+	elm, _ := doc.GetElementById("plugin")
+	vm, err := plugins.NewScriptVM(host.AssetDatabase(), "")
+	if err != nil {
+		return err
+	}
+	defer vm.Close()
+	vm.SetGlobalGoFunction("canvas_element", func(state *lua.State) int {
+		state.PushUserData(reflect.ValueOf(elm))
+		return 1
+	})
+	return vm.DoStringNamed(`
+local ctx = Element.New(canvas_element()):CanvasContext()
+ctx:SetFillColor({0, 1, 0, 1})
+ctx:FillRect(0, 0, 50, 50)
+`, "canvas_plugin")
+}
+
+func assertCanvas(doc *document.Document, ctx *canvas.Context) error {
+	elm, _ := doc.GetElementById("graph")
+	if s := elm.UI.Layout().PixelSize(); s.X() != 300 || s.Y() != 200 {
+		return errors.New("expected the canvas to be sized by its attributes")
+	}
+	if ctx.Pixel(150, 100).A() == 0 {
+		return errors.New("expected the radar to be drawn in the middle of the canvas")
+	}
+	if ctx.IsDirty() {
+		return errors.New("expected the drawing to be uploaded to the texture")
+	}
+	small, _ := doc.GetElementById("small")
+	if c := small.UI.ToCanvas().Context(); c.Width() != 300 || c.Height() != 150 {
+		return errors.New("expected a canvas without a size to be 300x150")
+	}
+	plugin, _ := doc.GetElementById("plugin")
+	if p := plugin.CanvasContext().Pixel(25, 25); p.G() != 1 || p.R() != 0 {
+		return errors.New("expected the plugin to draw on the canvas through its element")
+	}
+	return nil
+}
+
+const canvasHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				margin: 24px;
+			}
+			canvas { display: block; margin-bottom: 16px; }
+		</style>
+	</head>
+	<body>
+		<canvas id="graph" width="300" height="200">Fallback</canvas>
+		<canvas id="small"></canvas>
+		<canvas id="plugin" width="50" height="50"></canvas>
+	</body>
+</html>
+`
@@ -0,0 +1,111 @@
+/******************************************************************************/
+/* integration_test_clip.go                                                   */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
+)
+
+const clipScreenshotOutput = "integration_test_clip.png"
+
+func init() {
+	tests["clip_mask"] = IntegrationTestClipMask
+}
+
+func IntegrationTestClipMask(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, clipHTML, "", nil, nil, nil)
+
+	host.RunAfterFrames(8, func() {
+		if err := assertClipMask(doc); err != nil {
+			takeScreenshotToFile(host, clipScreenshotOutput)
+			slog.Error("clip and mask integration test failed", "error", err)
+			os.Exit(1)
+		}
+		takeScreenshotToFile(host, clipScreenshotOutput)
+		os.Exit(0)
+	})
+}
+
+func assertClipMask(doc *document.Document) error {
+	circle, _ := doc.GetElementById("circle")
+	center := circle.UI.Entity().Transform.WorldPosition().AsVec2()
+	if !circle.UI.ContainsPoint(center.Add(matrix.Vec2{0, 45})) {
+		return errors.New("expected the circle to be under a point inside of it")
+	}
+	// The corner of the 100x100 box is outside of the circle
+	if circle.UI.ContainsPoint(center.Add(matrix.Vec2{45, 45})) {
+		return errors.New("expected the corner of the circle's box to be clipped")
+	}
+	child, _ := doc.GetElementById("circleChild")
+	if child.UI.ContainsPoint(center.Add(matrix.Vec2{-45, -45})) {
+		return errors.New("expected the child to be clipped by the circle")
+	}
+	framed, _ := doc.GetElementById("framed")
+	img := framed.UI.ToPanel().BorderImage()
+	if img.Texture == nil || !img.Fill || img.Repeat[0] != ui.BorderImageRound {
+		return errors.New("expected the border image to be set from the shorthand")
+	}
+	masked, _ := doc.GetElementById("masked")
+	if mask := masked.UI.ToPanel().MaskGradient(); mask == nil || len(mask.Stops) != 2 {
+		return errors.New("expected the mask gradient to be set")
+	}
+	return nil
+}
+
+const clipHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				color: #eef1f6;
+				margin: 24px;
+			}
+			#circle {
+				width: 100px;
+				height: 100px;
+				margin-bottom: 16px;
+				background-color: #2980b9;
+				clip-path: circle(50%);
+			}
+			#circleChild {
+				width: 100px;
+				height: 100px;
+				background-color: #c0392b;
+			}
+			#framed {
+				width: 200px;
+				height: 60px;
+				margin-bottom: 16px;
+				border: 12px solid transparent;
+				border-image: url("square.png") 30% fill / 12px round;
+			}
+			#masked {
+				width: 200px;
+				height: 60px;
+				background-color: #27ae60;
+				mask-image: linear-gradient(to right, black, transparent);
+			}
+		</style>
+	</head>
+	<body>
+		<div id="circle"><div id="circleChild"></div></div>
+		<div id="framed">Framed</div>
+		<div id="masked">Masked</div>
+	</body>
+</html>
+`
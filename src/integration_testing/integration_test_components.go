@@ -0,0 +1,121 @@
+/******************************************************************************/
+/* integration_test_components.go                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
+)
+
+const componentsScreenshotOutput = "integration_test_components.png"
+
+func init() {
+	tests["components"] = IntegrationTestComponents
+}
+
+func IntegrationTestComponents(host *engine.Host) {
+	c, err := document.ParseComponent(componentsCardHTML)
+	if err != nil {
+		slog.Error("components integration test failed to parse the component", "error", err)
+		os.Exit(1)
+	}
+	document.RegisterComponent(c)
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, componentsHTML, "", nil, nil, nil)
+
+	host.RunAfterFrames(8, func() {
+		if err := assertComponentsLayout(doc); err != nil {
+			takeScreenshotToFile(host, componentsScreenshotOutput)
+			slog.Error("components integration test failed", "error", err)
+			os.Exit(1)
+		}
+		takeScreenshotToFile(host, componentsScreenshotOutput)
+		os.Exit(0)
+	})
+}
+
+func assertComponentsLayout(doc *document.Document) error {
+	cards := doc.GetElementsByTagName("test-card")
+	if len(cards) != 2 {
+		return fmt.Errorf("expected 2 component hosts but got %d", len(cards))
+	}
+	if w := cards[0].UI.Layout().PixelSize().X(); !matrix.Approx(w, 200) {
+		return fmt.Errorf("expected :host to set the width to 200 but got %f", w)
+	}
+	if w := cards[1].UI.Layout().PixelSize().X(); !matrix.Approx(w, 300) {
+		return fmt.Errorf("expected :host(.wide) to set the width to 300 but got %f", w)
+	}
+	titles := doc.GetElementsByClass("title")
+	heights := map[string]float32{}
+	for _, t := range titles {
+		key := t.Attribute("id")
+		if key == "" {
+			key = "component"
+		}
+		heights[key] = t.UI.Layout().PixelSize().Y()
+	}
+	if h := heights["component"]; !matrix.Approx(h, 30) {
+		return fmt.Errorf("expected the document .title style to not reach into the component, got height %f", h)
+	}
+	if h := heights["outside"]; !matrix.Approx(h, 60) {
+		return fmt.Errorf("expected the component .title style to not leak out, got height %f", h)
+	}
+	slotted, ok := doc.GetElementById("slotted")
+	if !ok {
+		return fmt.Errorf("missing element #slotted")
+	}
+	if p := slotted.Parent.Value(); p == nil || !p.HasClass("body") {
+		return fmt.Errorf("expected the content to be placed into the default slot")
+	}
+	if h := slotted.UI.Layout().PixelSize().Y(); !matrix.Approx(h, 60) {
+		return fmt.Errorf("expected the slotted content to be styled by the document, got height %f", h)
+	}
+	return nil
+}
+
+const componentsCardHTML = `
+<kaiju-component name="test-card">
+	<property name="title" default="Untitled"></property>
+	<style>
+		:host { display: block; width: 200px; background-color: #3a404b; }
+		:host(.wide) { width: 300px; }
+		.title { height: 30px; color: #f5c542; }
+	</style>
+	<template>
+		<div class="title">{{.title}}</div>
+		<div class="body"><slot>Nothing to show</slot></div>
+	</template>
+</kaiju-component>
+`
+
+const componentsHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				color: #eef1f6;
+				margin: 24px;
+			}
+			.title, .tall { height: 60px; }
+		</style>
+	</head>
+	<body>
+		<div id="outside" class="title">Document title</div>
+		<test-card title="Sword"><div id="slotted" class="tall">A sharp blade</div></test-card>
+		<test-card class="wide"></test-card>
+	</body>
+</html>
+`
@@ -0,0 +1,142 @@
+/******************************************************************************/
+/* integration_test_data_binding.go                                           */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/binding"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+const dataBindingScreenshotOutput = "integration_test_data_binding.png"
+
+func init() {
+	tests["data-binding"] = IntegrationTestDataBinding
+}
+
+func IntegrationTestDataBinding(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, dataBindingHTML, "", nil, nil, nil)
+	model := binding.NewModelFrom(map[string]any{
+		"title":    "Inventory",
+		"selected": true,
+		"filter":   "sw",
+	})
+	items := model.List("items")
+	for _, name := range []string{"sword", "shield", "potion"} {
+		items.Append(name, binding.NewModelFrom(map[string]any{"name": name}))
+	}
+	binding.Bind(doc, model)
+
+	host.RunAfterFrames(4, func() {
+		if err := assertDataBindingRows(doc, []string{"0 sword", "1 shield", "2 potion"}); err != nil {
+			failDataBinding(host, err)
+		}
+		if err := assertDataBindingValues(doc, "Inventory", "sw", true); err != nil {
+			failDataBinding(host, err)
+		}
+		sword := dataBindingRows(doc)[0]
+		items.Remove("shield")
+		items.Move("potion", 0)
+		items.Append("bow", binding.NewModelFrom(map[string]any{"name": "bow"}))
+		model.Set("title", "Bag")
+		model.Set("selected", false)
+		model.Set("filter", "bo")
+		host.RunAfterFrames(4, func() {
+			if err := assertDataBindingRows(doc, []string{"0 potion", "1 sword", "2 bow"}); err != nil {
+				failDataBinding(host, err)
+			}
+			if dataBindingRows(doc)[1] != sword {
+				failDataBinding(host, fmt.Errorf("expected the row of a kept key to be moved rather than recreated"))
+			}
+			if err := assertDataBindingValues(doc, "Bag", "bo", false); err != nil {
+				failDataBinding(host, err)
+			}
+			takeScreenshotToFile(host, dataBindingScreenshotOutput)
+			os.Exit(0)
+		})
+	})
+}
+
+func failDataBinding(host *engine.Host, err error) {
+	takeScreenshotToFile(host, dataBindingScreenshotOutput)
+	slog.Error("data-binding integration test failed", "error", err)
+	os.Exit(1)
+}
+
+func dataBindingRows(doc *document.Document) []*document.Element {
+	list, _ := doc.GetElementById("list")
+	rows := []*document.Element{}
+	for _, c := range list.Children {
+		if c.HasClass("row") && !c.HasAttribute("bind-each") {
+			rows = append(rows, c)
+		}
+	}
+	return rows
+}
+
+func assertDataBindingRows(doc *document.Document, want []string) error {
+	texts := []string{}
+	for _, row := range dataBindingRows(doc) {
+		texts = append(texts, row.InnerLabel().Text())
+	}
+	if !slices.Equal(texts, want) {
+		return fmt.Errorf("expected the rows %v but got %v", want, texts)
+	}
+	return nil
+}
+
+func assertDataBindingValues(doc *document.Document, title, filter string, selected bool) error {
+	heading, _ := doc.GetElementById("title")
+	if text := heading.InnerLabel().Text(); text != "Title: "+title {
+		return fmt.Errorf("expected the interpolated title but got %q", text)
+	}
+	if heading.HasClass("selected") != selected {
+		return fmt.Errorf("expected the selected class to be %v", selected)
+	}
+	input, _ := doc.GetElementById("filter")
+	if text := input.UI.ToInput().Text(); text != filter {
+		return fmt.Errorf("expected the bound input value %q but got %q", filter, text)
+	}
+	return nil
+}
+
+const dataBindingHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				color: #eef1f6;
+				margin: 24px;
+			}
+			#title { font-size: 24px; }
+			.selected { color: #f5c542; }
+			.row {
+				background-color: #3a404b;
+				margin-bottom: 4px;
+				width: 240px;
+			}
+		</style>
+	</head>
+	<body>
+		<div id="title" bind-class-selected="selected">Title: ${title}</div>
+		<input id="filter" type="text" bind-value="filter" />
+		<div id="list">
+			<div class="row" bind-each="items">${$index} ${name}</div>
+		</div>
+	</body>
+</html>
+`
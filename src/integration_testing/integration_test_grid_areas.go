@@ -0,0 +1,147 @@
+/******************************************************************************/
+/* integration_test_grid_areas.go                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
+)
+
+const gridAreasScreenshotOutput = "integration_test_grid_areas.png"
+
+func init() {
+	tests["grid-areas"] = IntegrationTestGridAreas
+}
+
+func IntegrationTestGridAreas(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, gridAreasHTML, "", nil, nil, nil)
+
+	host.RunAfterFrames(8, func() {
+		if err := assertGridAreasLayout(doc); err != nil {
+			takeScreenshotToFile(host, gridAreasScreenshotOutput)
+			slog.Error("grid-areas integration test failed", "error", err)
+			os.Exit(1)
+		}
+		takeScreenshotToFile(host, gridAreasScreenshotOutput)
+		os.Exit(0)
+	})
+}
+
+func assertGridAreasLayout(doc *document.Document) error {
+	offsets := map[string]matrix.Vec2{}
+	for _, id := range []string{"head", "side", "main", "foot", "note"} {
+		offset, err := gridAreasOffset(doc, id)
+		if err != nil {
+			return err
+		}
+		offsets[id] = offset
+	}
+	head, side, main, foot, note := offsets["head"], offsets["side"], offsets["main"], offsets["foot"], offsets["note"]
+	if math.Abs(float64(side.X()-head.X())) > 1 {
+		return fmt.Errorf("expected #side to start in the first column like #head, got x %.2f instead of %.2f", side.X(), head.X())
+	}
+	// The side column is 80px wide with a 10px gap
+	if math.Abs(float64(main.X()-side.X()-90)) > 1 {
+		return fmt.Errorf("expected #main to start after the 80px side column, got x %.2f from %.2f", main.X(), side.X())
+	}
+	// The head row is 40px tall with a 10px gap
+	if math.Abs(float64(side.Y()-head.Y()-50)) > 1 || math.Abs(float64(main.Y()-side.Y())) > 1 {
+		return fmt.Errorf("expected #side and #main on the second row, got y %.2f and %.2f from %.2f", side.Y(), main.Y(), head.Y())
+	}
+	// The middle row is 100px tall with a 10px gap
+	if math.Abs(float64(foot.Y()-side.Y()-110)) > 1 {
+		return fmt.Errorf("expected #foot on the last row, got y %.2f from %.2f", foot.Y(), side.Y())
+	}
+	// #note uses the line named after the main area, and sits at the end of it
+	if math.Abs(float64(note.Y()-foot.Y())) > 1 {
+		return fmt.Errorf("expected #note on the foot row, got y %.2f instead of %.2f", note.Y(), foot.Y())
+	}
+	if note.X() <= main.X()+100 {
+		return fmt.Errorf("expected #note to be justified to the end of the main columns, got x %.2f", note.X())
+	}
+	return nil
+}
+
+func gridAreasOffset(doc *document.Document, id string) (matrix.Vec2, error) {
+	elm, ok := doc.GetElementById(id)
+	if !ok {
+		return matrix.Vec2Zero(), fmt.Errorf("missing element #%s", id)
+	}
+	return elm.UI.Layout().CalcOffset(), nil
+}
+
+const gridAreasHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				margin: 24px;
+			}
+			#grid {
+				background-color: #eef1f6;
+				display: grid;
+				gap: 10px;
+				grid-template-areas:
+					"head head head"
+					"side main main"
+					"foot foot foot";
+				grid-template-columns: 80px 1fr 1fr;
+				grid-template-rows: 40px 100px 40px;
+				height: 220px;
+				padding: 10px;
+				width: 360px;
+			}
+			.tile {
+				height: 30px;
+				width: 60px;
+			}
+			#head {
+				background-color: #4f7cac;
+				grid-area: head;
+			}
+			#side {
+				background-color: #19a974;
+				grid-area: side;
+			}
+			#main {
+				background-color: #f2b134;
+				grid-area: main;
+			}
+			#foot {
+				background-color: #d64550;
+				grid-area: foot;
+			}
+			#note {
+				background-color: #7b4fac;
+				grid-column: main-start / main-end;
+				grid-row-start: foot;
+				justify-self: end;
+			}
+		</style>
+	</head>
+	<body>
+		<div id="grid">
+			<div id="main" class="tile"></div>
+			<div id="foot" class="tile"></div>
+			<div id="head" class="tile"></div>
+			<div id="side" class="tile"></div>
+			<div id="note" class="tile"></div>
+		</div>
+	</body>
+</html>
+`
@@ -0,0 +1,134 @@
+/******************************************************************************/
+/* integration_test_grid_auto_flow.go                                         */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
+)
+
+const gridAutoFlowScreenshotOutput = "integration_test_grid_auto_flow.png"
+
+func init() {
+	tests["grid-auto-flow"] = IntegrationTestGridAutoFlow
+}
+
+func IntegrationTestGridAutoFlow(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, gridAutoFlowHTML, "", nil, nil, nil)
+
+	host.RunAfterFrames(8, func() {
+		if err := assertGridAutoFlowLayout(doc); err != nil {
+			takeScreenshotToFile(host, gridAutoFlowScreenshotOutput)
+			slog.Error("grid-auto-flow integration test failed", "error", err)
+			os.Exit(1)
+		}
+		takeScreenshotToFile(host, gridAutoFlowScreenshotOutput)
+		os.Exit(0)
+	})
+}
+
+func assertGridAutoFlowLayout(doc *document.Document) error {
+	first, err := gridAutoFlowOffset(doc, "first")
+	if err != nil {
+		return err
+	}
+	second, err := gridAutoFlowOffset(doc, "second")
+	if err != nil {
+		return err
+	}
+	filler, err := gridAutoFlowOffset(doc, "filler")
+	if err != nil {
+		return err
+	}
+	last, err := gridAutoFlowOffset(doc, "last")
+	if err != nil {
+		return err
+	}
+	if second.Y() <= first.Y()+40 {
+		return fmt.Errorf("expected the wide #second to wrap below #first, got y %.2f <= %.2f", second.Y(), first.Y()+40)
+	}
+	if math.Abs(float64(filler.Y()-first.Y())) > 1 {
+		return fmt.Errorf("expected dense packing to move #filler up beside #first, got y %.2f instead of %.2f", filler.Y(), first.Y())
+	}
+	// Two 90px columns with a 10px gap, then centered by place-items in the
+	// third column: 100 + 100 + (90 - 50) / 2
+	if math.Abs(float64(filler.X()-first.X()-220)) > 1 {
+		return fmt.Errorf("expected #filler centered in the third column, got x %.2f from %.2f", filler.X(), first.X())
+	}
+	if math.Abs(float64(last.Y()-second.Y())) > 1 {
+		return fmt.Errorf("expected #last to fill the hole beside #second, got y %.2f instead of %.2f", last.Y(), second.Y())
+	}
+	return nil
+}
+
+func gridAutoFlowOffset(doc *document.Document, id string) (matrix.Vec2, error) {
+	elm, ok := doc.GetElementById(id)
+	if !ok {
+		return matrix.Vec2Zero(), fmt.Errorf("missing element #%s", id)
+	}
+	return elm.UI.Layout().CalcOffset(), nil
+}
+
+const gridAutoFlowHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				margin: 24px;
+			}
+			#grid {
+				background-color: #eef1f6;
+				display: grid;
+				gap: 10px;
+				grid-auto-flow: row dense;
+				grid-template-columns: repeat(3, 90px);
+				height: 160px;
+				padding: 18px;
+				place-items: start center;
+				width: 320px;
+			}
+			.tile {
+				height: 44px;
+				width: 50px;
+			}
+			.wide {
+				grid-column: span 2;
+				justify-self: start;
+			}
+			#first,
+			#second {
+				background-color: #4f7cac;
+			}
+			#filler {
+				background-color: #19a974;
+			}
+			#last {
+				background-color: #f2b134;
+			}
+		</style>
+	</head>
+	<body>
+		<div id="grid">
+			<div id="first" class="tile wide"></div>
+			<div id="second" class="tile wide"></div>
+			<div id="filler" class="tile"></div>
+			<div id="last" class="tile"></div>
+		</div>
+	</body>
+</html>
+`
@@ -0,0 +1,117 @@
+/******************************************************************************/
+/* integration_test_navigation.go                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/document"
+)
+
+const navigationScreenshotOutput = "integration_test_navigation.png"
+
+func init() {
+	tests["navigation"] = IntegrationTestNavigation
+}
+
+func IntegrationTestNavigation(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, navigationHTML, "", nil, nil, nil)
+	doc.EnableNavigation()
+
+	host.RunAfterFrames(8, func() {
+		if err := assertNavigation(doc); err != nil {
+			takeScreenshotToFile(host, navigationScreenshotOutput)
+			slog.Error("navigation integration test failed", "error", err)
+			os.Exit(1)
+		}
+		host.RunAfterFrames(2, func() {
+			takeScreenshotToFile(host, navigationScreenshotOutput)
+			os.Exit(0)
+		})
+	})
+}
+
+func assertNavigation(doc *document.Document) error {
+	expectFocus := func(id string) error {
+		elm, ok := doc.FocusedElement()
+		if !ok {
+			return fmt.Errorf("expected #%s to be focused but nothing is", id)
+		}
+		if got := elm.Attribute("id"); got != id {
+			return fmt.Errorf("expected #%s to be focused but #%s is", id, got)
+		}
+		return nil
+	}
+	steps := []struct {
+		dir  document.NavDirection
+		want string
+	}{
+		// The first move focuses the autofocus element
+		{document.NavDirectionDown, "b"},
+		{document.NavDirectionRight, "c"},
+		{document.NavDirectionDown, "f"},
+		{document.NavDirectionLeft, "e"},
+		// nav-down sends the focus to #a rather than the row below
+		{document.NavDirectionDown, "a"},
+	}
+	for _, step := range steps {
+		doc.Navigate(step.dir)
+		if err := expectFocus(step.want); err != nil {
+			return err
+		}
+	}
+	modal, _ := doc.GetElementById("modal")
+	modal.UI.Show()
+	doc.Navigate(document.NavDirectionDown)
+	if err := expectFocus("ok"); err != nil {
+		return fmt.Errorf("expected the focus to move into the focus trap: %w", err)
+	}
+	if doc.Navigate(document.NavDirectionUp) {
+		return fmt.Errorf("expected the focus to not leave the focus trap")
+	}
+	return nil
+}
+
+const navigationHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				color: #eef1f6;
+				margin: 24px;
+			}
+			.row { display: flex; flex-direction: row; margin-bottom: 8px; }
+			button { width: 100px; height: 40px; margin-right: 8px; background-color: #3a404b; }
+			button:focus-visible { background-color: #f5c542; }
+			#modal { width: 240px; height: 80px; background-color: #444b57; }
+		</style>
+	</head>
+	<body>
+		<div class="row">
+			<button id="a">A</button>
+			<button id="b" autofocus>B</button>
+			<button id="c">C</button>
+		</div>
+		<div class="row">
+			<button id="d">D</button>
+			<button id="e" nav-down="#a">E</button>
+			<button id="f">F</button>
+		</div>
+		<div id="modal" focus-trap style="display: none;">
+			<button id="ok">OK</button>
+		</div>
+	</body>
+</html>
+`
@@ -0,0 +1,118 @@
+/******************************************************************************/
+/* integration_test_scroll.go                                                 */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
+)
+
+const scrollScreenshotOutput = "integration_test_scroll.png"
+
+func init() {
+	tests["scroll"] = IntegrationTestScroll
+}
+
+func IntegrationTestScroll(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, scrollHTML, "", nil, nil, nil)
+
+	host.RunAfterFrames(8, func() {
+		list, _ := doc.GetElementById("list")
+		last, _ := doc.GetElementById("last")
+		list.UI.ToPanel().ScrollIntoView(last.UI)
+		carousel, _ := doc.GetElementById("carousel")
+		// The carousel scrolls smoothly, so it is only at the slide once
+		// the animation has settled
+		carousel.UI.ToPanel().ScrollTo(200, 0)
+		host.RunAfterFrames(60, func() {
+			if err := assertScroll(doc); err != nil {
+				takeScreenshotToFile(host, scrollScreenshotOutput)
+				slog.Error("scroll integration test failed", "error", err)
+				os.Exit(1)
+			}
+			takeScreenshotToFile(host, scrollScreenshotOutput)
+			os.Exit(0)
+		})
+	})
+}
+
+func assertScroll(doc *document.Document) error {
+	list, _ := doc.GetElementById("list")
+	// 10 rows of 40px in a 200px list, the last row ends at 400 and the
+	// scroll padding keeps 20px below it clear
+	if got := list.UI.ToPanel().ScrollY(); !matrix.Approx(got, 220) {
+		return fmt.Errorf("expected the list to scroll to 220 but it is at %f", got)
+	}
+	carousel, _ := doc.GetElementById("carousel")
+	if got := carousel.UI.ToPanel().ScrollX(); !matrix.Approx(got, 200) {
+		return fmt.Errorf("expected the carousel to glide to 200 but it is at %f", got)
+	}
+	return nil
+}
+
+const scrollHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				color: #eef1f6;
+				margin: 24px;
+			}
+			#list {
+				width: 240px;
+				height: 200px;
+				overflow-y: scroll;
+				scroll-padding-bottom: 20px;
+				scrollbar-color: #f5c542 #3a404b;
+				background-color: #2d323b;
+			}
+			.row { height: 40px; }
+			#carousel {
+				display: flex;
+				flex-direction: row;
+				width: 200px;
+				height: 120px;
+				margin-top: 16px;
+				overflow-x: scroll;
+				scroll-snap-type: x mandatory;
+				scroll-behavior: smooth;
+				overscroll-behavior: contain;
+			}
+			.slide { width: 200px; height: 120px; scroll-snap-align: start; }
+		</style>
+	</head>
+	<body>
+		<div id="list">
+			<div class="row">1</div>
+			<div class="row">2</div>
+			<div class="row">3</div>
+			<div class="row">4</div>
+			<div class="row">5</div>
+			<div class="row">6</div>
+			<div class="row">7</div>
+			<div class="row">8</div>
+			<div class="row">9</div>
+			<div id="last" class="row">10</div>
+		</div>
+		<div id="carousel">
+			<div class="slide" style="background-color: #c0392b;"></div>
+			<div class="slide" style="background-color: #27ae60;"></div>
+			<div class="slide" style="background-color: #2980b9;"></div>
+		</div>
+	</body>
+</html>
+`
@@ -0,0 +1,107 @@
+/******************************************************************************/
+/* integration_test_table.go                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
+)
+
+const tableScreenshotOutput = "integration_test_table.png"
+
+func init() {
+	tests["table"] = IntegrationTestTable
+}
+
+func IntegrationTestTable(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, tableHTML, "", nil, nil, nil)
+
+	host.RunAfterFrames(8, func() {
+		if err := assertTable(doc); err != nil {
+			takeScreenshotToFile(host, tableScreenshotOutput)
+			slog.Error("table integration test failed", "error", err)
+			os.Exit(1)
+		}
+		takeScreenshotToFile(host, tableScreenshotOutput)
+		os.Exit(0)
+	})
+}
+
+func assertTable(doc *document.Document) error {
+	get := func(id string) (matrix.Vec2, matrix.Vec2) {
+		elm, _ := doc.GetElementById(id)
+		pos := elm.UI.Entity().Transform.WorldPosition()
+		size := elm.UI.Layout().PixelSize()
+		return matrix.Vec2{pos.X() - size.X()*0.5, pos.Y() + size.Y()*0.5}, size
+	}
+	namePos, nameSize := get("name")
+	scorePos, _ := get("score")
+	alicePos, aliceSize := get("alice")
+	totalPos, totalSize := get("total")
+	if !matrix.Approx(namePos.X(), alicePos.X()) || !matrix.Approx(nameSize.X(), aliceSize.X()) {
+		return fmt.Errorf("expected the cells of the first column to line up")
+	}
+	if scorePos.X() <= namePos.X()+nameSize.X() {
+		return fmt.Errorf("expected the score column to be right of the name column")
+	}
+	// The footer is written first in the document but goes last
+	if totalPos.Y() >= alicePos.Y() {
+		return fmt.Errorf("expected the footer row below the body rows")
+	}
+	if totalSize.X() < nameSize.X()*2 {
+		return fmt.Errorf("expected the colspan cell to cover both columns, it is %f wide", totalSize.X())
+	}
+	_, tallSize := get("tall")
+	if tallSize.Y() < aliceSize.Y()*2 {
+		return fmt.Errorf("expected the rowspan cell to cover two rows, it is %f high", tallSize.Y())
+	}
+	return nil
+}
+
+const tableHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				color: #eef1f6;
+				margin: 24px;
+			}
+			table { border-collapse: collapse; border: 1px solid #8a93a3; }
+			th, td { border: 1px solid #8a93a3; padding: 4px 8px; }
+			th { background-color: #3a404b; }
+			tfoot td { background-color: #444b57; }
+			td:nth-col(even) { color: #f5c542; }
+			caption { caption-side: bottom; }
+		</style>
+	</head>
+	<body>
+		<table>
+			<caption>Scoreboard</caption>
+			<tfoot>
+				<tr><td id="total" colspan="2">Total</td><td>31</td></tr>
+			</tfoot>
+			<thead>
+				<tr><th id="name">Name</th><th id="score">Score</th><th>Rank</th></tr>
+			</thead>
+			<tbody>
+				<tr><td id="alice">Alice</td><td>17</td><td id="tall" rowspan="2">Top</td></tr>
+				<tr><td>Bob</td><td>14</td></tr>
+			</tbody>
+		</table>
+	</body>
+</html>
+`
@@ -49,7 +49,7 @@ func assertTextAlignValues(doc *document.Document) error {
 		{"right", rendering.FontJustifyRight},
 		{"center", rendering.FontJustifyCenter},
 		{"justify", rendering.FontJustifyJustify},
-		{"initial", rendering.FontJustifyLeft},
+		{"initial", rendering.FontJustifyStart},
 		{"inherit", rendering.FontJustifyRight},
 	}
 	for _, test := range tests {
@@ -0,0 +1,119 @@
+/******************************************************************************/
+/* integration_test_text_direction.go                                         */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering"
+)
+
+const textDirectionScreenshotOutput = "integration_test_text_direction.png"
+
+func init() {
+	tests["text-direction"] = IntegrationTestTextDirection
+}
+
+func IntegrationTestTextDirection(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, textDirectionHTML, "", nil, nil, nil)
+
+	host.RunAfterFrames(8, func() {
+		if err := assertTextDirectionLayout(doc); err != nil {
+			takeScreenshotToFile(host, textDirectionScreenshotOutput)
+			slog.Error("text-direction integration test failed", "error", err)
+			os.Exit(1)
+		}
+		takeScreenshotToFile(host, textDirectionScreenshotOutput)
+		os.Exit(0)
+	})
+}
+
+func assertTextDirectionLayout(doc *document.Document) error {
+	xs := make([]float32, 0, 3)
+	for _, id := range []string{"first", "second", "third"} {
+		elm, ok := doc.GetElementById(id)
+		if !ok {
+			return fmt.Errorf("missing element #%s", id)
+		}
+		xs = append(xs, elm.UI.Entity().Transform.WorldPosition().X())
+	}
+	if !(xs[0] > xs[1] && xs[1] > xs[2]) {
+		return fmt.Errorf("expected the rtl flex items to run right to left, got x %v", xs)
+	}
+	logical, ok := doc.GetElementById("logical")
+	if !ok {
+		return fmt.Errorf("missing element #logical")
+	}
+	if m := logical.UI.Layout().Margin(); !matrix.Approx(m.Z(), 12) || !matrix.Approx(m.X(), 0) {
+		return fmt.Errorf("expected margin-inline-start to be the right margin in rtl, got %v", m)
+	}
+	if d := logical.UIPanel.ResolvedDirection(); d != ui.DirectionRTL {
+		return fmt.Errorf("expected #logical to inherit rtl but got %d", d)
+	}
+	attr, ok := doc.GetElementById("attr")
+	if !ok {
+		return fmt.Errorf("missing element #attr")
+	}
+	if d := attr.UIPanel.Direction(); d != ui.DirectionRTL {
+		return fmt.Errorf("expected the dir attribute to set rtl but got %d", d)
+	}
+	labels := labelsForElement(attr)
+	if len(labels) != 1 || labels[0].Justify() != rendering.FontJustifyStart {
+		return fmt.Errorf("expected #attr text to be aligned to the start")
+	}
+	return nil
+}
+
+const textDirectionHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				color: #111827;
+				margin: 24px;
+			}
+			#row {
+				direction: rtl;
+				display: flex;
+				width: 300px;
+			}
+			.box {
+				background-color: #eef1f6;
+				height: 40px;
+				width: 60px;
+			}
+			#logical {
+				margin-inline-start: 12px;
+			}
+			#attr {
+				background-color: #eef1f6;
+				font-size: 18px;
+				width: 300px;
+			}
+		</style>
+	</head>
+	<body>
+		<div id="row">
+			<div id="first" class="box"></div>
+			<div id="second" class="box"></div>
+			<div id="third" class="box" style="margin-inline-start: 12px;"></div>
+		</div>
+		<div style="direction: rtl;"><div id="logical" class="box"></div></div>
+		<div id="attr" dir="rtl">שלום (world) 123</div>
+	</body>
+</html>
+`
@@ -0,0 +1,137 @@
+/******************************************************************************/
+/* integration_test_text_wrap.go                                              */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering"
+)
+
+const textWrapScreenshotOutput = "integration_test_text_wrap.png"
+
+func init() {
+	tests["text-wrap"] = IntegrationTestTextWrap
+}
+
+func IntegrationTestTextWrap(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, textWrapHTML, "", nil, nil, nil)
+
+	host.RunAfterFrames(8, func() {
+		if err := assertTextWrapLayout(doc); err != nil {
+			takeScreenshotToFile(host, textWrapScreenshotOutput)
+			slog.Error("text-wrap integration test failed", "error", err)
+			os.Exit(1)
+		}
+		takeScreenshotToFile(host, textWrapScreenshotOutput)
+		os.Exit(0)
+	})
+}
+
+func assertTextWrapLayout(doc *document.Document) error {
+	single, err := textWrapLabel(doc, "single")
+	if err != nil {
+		return err
+	}
+	ellipsis, err := textWrapLabel(doc, "ellipsis")
+	if err != nil {
+		return err
+	}
+	clamp, err := textWrapLabel(doc, "clamp")
+	if err != nil {
+		return err
+	}
+	pre, err := textWrapLabel(doc, "pre")
+	if err != nil {
+		return err
+	}
+	anywhere, err := textWrapLabel(doc, "anywhere")
+	if err != nil {
+		return err
+	}
+	lineHeight := single.Measure().Y()
+	if l := ellipsis.TextLayout(); l.WhiteSpace != rendering.TextWhiteSpaceNoWrap || l.Overflow != rendering.TextOverflowEllipsis {
+		return fmt.Errorf("expected #ellipsis to be nowrap with an ellipsis, got %+v", l)
+	}
+	if !matrix.Approx(ellipsis.Measure().Y(), lineHeight) {
+		return fmt.Errorf("expected #ellipsis to stay on one line, got height %.2f for line %.2f", ellipsis.Measure().Y(), lineHeight)
+	}
+	if !matrix.Approx(clamp.Measure().Y(), lineHeight*2) {
+		return fmt.Errorf("expected #clamp to be clamped to 2 lines, got height %.2f for line %.2f", clamp.Measure().Y(), lineHeight)
+	}
+	if !strings.Contains(pre.Text(), "\n") || !matrix.Approx(pre.Measure().Y(), lineHeight*2) {
+		return fmt.Errorf("expected #pre to keep its new line, got %q", pre.Text())
+	}
+	if anywhere.Measure().Y() < lineHeight*2 {
+		return fmt.Errorf("expected #anywhere to break the long word, got height %.2f", anywhere.Measure().Y())
+	}
+	return nil
+}
+
+func textWrapLabel(doc *document.Document, id string) (*ui.Label, error) {
+	labels, err := labelsForElementId(doc, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) != 1 {
+		return nil, fmt.Errorf("expected one label under #%s but found %d", id, len(labels))
+	}
+	return labels[0], nil
+}
+
+const textWrapHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				color: #111827;
+				margin: 24px;
+			}
+			.row {
+				background-color: #eef1f6;
+				display: block;
+				font-size: 18px;
+				margin-bottom: 12px;
+				padding: 6px;
+				width: 180px;
+			}
+			#ellipsis {
+				text-overflow: ellipsis;
+				white-space: nowrap;
+			}
+			#clamp {
+				line-clamp: 2;
+			}
+			#pre {
+				white-space: pre;
+			}
+			#anywhere {
+				overflow-wrap: anywhere;
+			}
+		</style>
+	</head>
+	<body>
+		<div id="single" class="row">Short</div>
+		<div id="ellipsis" class="row">A long localized button caption that overflows</div>
+		<div id="clamp" class="row">A long localized description that would wrap over many lines when it is not clamped</div>
+		<div id="pre" class="row">first
+second</div>
+		<div id="anywhere" class="row">Donaudampfschifffahrtsgesellschaftskapitän</div>
+	</body>
+</html>
+`
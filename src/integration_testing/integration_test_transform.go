@@ -0,0 +1,114 @@
+/******************************************************************************/
+/* integration_test_transform.go                                              */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/ui/markup/document"
+	"kaijuengine.com/matrix"
+)
+
+const transformScreenshotOutput = "integration_test_transform.png"
+
+func init() {
+	tests["transform"] = IntegrationTestTransform
+}
+
+func IntegrationTestTransform(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, transformHTML, "", nil, nil, nil)
+
+	host.RunAfterFrames(8, func() {
+		if err := assertTransform(doc); err != nil {
+			takeScreenshotToFile(host, transformScreenshotOutput)
+			slog.Error("transform integration test failed", "error", err)
+			os.Exit(1)
+		}
+		takeScreenshotToFile(host, transformScreenshotOutput)
+		os.Exit(0)
+	})
+}
+
+func assertTransform(doc *document.Document) error {
+	card, _ := doc.GetElementById("card")
+	center := card.UI.Entity().Transform.WorldPosition().AsVec2()
+	// The card is 200x40 and turned a quarter, so it reaches above its box
+	// but no longer out to its sides
+	if !card.UI.ContainsPoint(center.Add(matrix.Vec2{0, 80})) {
+		return errors.New("expected the turned card to be under a point above its center")
+	}
+	if card.UI.ContainsPoint(center.Add(matrix.Vec2{80, 0})) {
+		return errors.New("expected the turned card to no longer be under a point to its right")
+	}
+	front, _ := doc.GetElementById("front")
+	back, _ := doc.GetElementById("back")
+	flipCenter := front.UI.Entity().Transform.WorldPosition().AsVec2()
+	if front.UI.ContainsPoint(flipCenter) {
+		return errors.New("expected the front of the flipped card to be hidden")
+	}
+	if !back.UI.ContainsPoint(flipCenter) {
+		return errors.New("expected the back of the flipped card to be shown")
+	}
+	return nil
+}
+
+const transformHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				color: #eef1f6;
+				margin: 24px;
+			}
+			#card {
+				width: 200px;
+				height: 40px;
+				margin: 100px 0px;
+				background-color: #2980b9;
+				rotate: 90deg;
+			}
+			#scene {
+				width: 160px;
+				height: 100px;
+				perspective: 400px;
+			}
+			#flip {
+				position: relative;
+				width: 160px;
+				height: 100px;
+				transform-style: preserve-3d;
+				transform: rotateY(180deg);
+			}
+			.face {
+				position: absolute;
+				width: 160px;
+				height: 100px;
+				backface-visibility: hidden;
+			}
+			#front { background-color: #c0392b; }
+			#back { background-color: #27ae60; transform: rotateY(180deg); }
+		</style>
+	</head>
+	<body>
+		<div id="card">Card</div>
+		<div id="scene">
+			<div id="flip">
+				<div id="front" class="face">Front</div>
+				<div id="back" class="face">Back</div>
+			</div>
+		</div>
+	</body>
+</html>
+`
@@ -0,0 +1,124 @@
+/******************************************************************************/
+/* integration_test_video_playback.go                                         */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package integration_testing
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"log/slog"
+	"os"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/engine/ui"
+	"kaijuengine.com/engine/ui/markup"
+	"kaijuengine.com/engine/video"
+)
+
+const videoPlaybackScreenshotOutput = "integration_test_video_playback.png"
+
+func init() {
+	tests["video_playback"] = IntegrationTestVideoPlayback
+}
+
+func IntegrationTestVideoPlayback(host *engine.Host) {
+	uiMan := ui.Manager{}
+	uiMan.Init(host)
+	doc := markup.DocumentFromHTMLString(&uiMan, videoPlaybackHTML, "", nil, nil, nil)
+	elm, ok := doc.GetElementById("intro")
+	if !ok || !elm.UI.IsType(ui.ElementTypeVideo) {
+		slog.Error("video playback integration test failed", "error", "expected the video element")
+		os.Exit(1)
+	}
+	v, err := createPlaybackTestVideo()
+	if err == nil {
+		var stream *video.Stream
+		if stream, err = video.NewStream(host, v); err == nil {
+			elm.UI.ToVideo().SetStream(stream)
+		}
+	}
+	if err != nil {
+		slog.Error("video playback integration test failed", "error", err)
+		os.Exit(1)
+	}
+	vid := elm.UI.ToVideo()
+	fail := func(err error) {
+		takeScreenshotToFile(host, videoPlaybackScreenshotOutput)
+		slog.Error("video playback integration test failed", "error", err)
+		os.Exit(1)
+	}
+	host.RunAfterFrames(4, func() {
+		if w := vid.Base().Layout().PixelSize().X(); w != 64 {
+			fail(fmt.Errorf("expected the :paused width of 64, got %v", w))
+		}
+		vid.Player().SetLoop(true)
+		vid.Player().Play()
+	})
+	host.RunAfterFrames(10, func() {
+		if w := vid.Base().Layout().PixelSize().X(); w != 128 {
+			fail(fmt.Errorf("expected the :playing width of 128, got %v", w))
+		}
+		if vid.Player().Time() <= 0 {
+			fail(fmt.Errorf("expected the video to move forward while playing"))
+		}
+		vid.Player().Pause()
+	})
+	host.RunAfterFrames(16, func() {
+		if w := vid.Base().Layout().PixelSize().X(); w != 64 {
+			fail(fmt.Errorf("expected the :paused width after pausing, got %v", w))
+		}
+		takeScreenshotToFile(host, videoPlaybackScreenshotOutput)
+		os.Exit(0)
+	})
+}
+
+// createPlaybackTestVideo makes a short video that fades from red to blue, so the
+// test doesn't need a video file in the content
+func createPlaybackTestVideo() (*video.Video, error) {
+	const frames = 12
+	var buf bytes.Buffer
+	w, err := video.NewWriter(&buf, 32, 32, 24)
+	if err != nil {
+		return nil, err
+	}
+	for i := range frames {
+		img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+		c := color.RGBA{uint8(255 - i*255/frames), 0, uint8(i * 255 / frames), 255}
+		for y := range 32 {
+			for x := range 32 {
+				img.SetRGBA(x, y, c)
+			}
+		}
+		if err = w.WriteFrame(img); err != nil {
+			return nil, err
+		}
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return video.Decode(buf.Bytes())
+}
+
+const videoPlaybackHTML = `
+<html>
+	<head>
+		<style>
+			body {
+				background-color: #23272e;
+				margin: 24px;
+			}
+			video { display: block; height: 64px; }
+			video:paused { width: 64px; }
+			video:playing { width: 128px; }
+		</style>
+	</head>
+	<body>
+		<video id="intro"></video>
+	</body>
+</html>
+`
@@ -0,0 +1,88 @@
+/******************************************************************************/
+/* buffer.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio_pcm
+
+import "math"
+
+// Buffer is decoded audio where the samples of each frame are interleaved by
+// channel and are in the range [-1, 1]
+type Buffer struct {
+	SampleRate int
+	Channels   int
+	Samples    []float32
+}
+
+// Frames returns the number of samples per channel
+func (b *Buffer) Frames() int {
+	if b.Channels <= 0 {
+		return 0
+	}
+	return len(b.Samples) / b.Channels
+}
+
+// Duration returns the length of the audio in seconds
+func (b *Buffer) Duration() float64 {
+	if b.SampleRate <= 0 {
+		return 0
+	}
+	return float64(b.Frames()) / float64(b.SampleRate)
+}
+
+// Downmix averages all of the channels into a single mono channel
+func (b *Buffer) Downmix() Buffer {
+	if b.Channels <= 1 {
+		return *b
+	}
+	frames := b.Frames()
+	out := Buffer{
+		SampleRate: b.SampleRate,
+		Channels:   1,
+		Samples:    make([]float32, frames),
+	}
+	scale := 1 / float32(b.Channels)
+	for i := range frames {
+		sum := float32(0)
+		for c := range b.Channels {
+			sum += b.Samples[i*b.Channels+c]
+		}
+		out.Samples[i] = sum * scale
+	}
+	return out
+}
+
+// Trim removes all of the frames after the given frame
+func (b *Buffer) Trim(frames int) {
+	if frames >= 0 && frames < b.Frames() {
+		b.Samples = b.Samples[:frames*b.Channels]
+	}
+}
+
+// Peak returns the largest absolute sample value
+func (b *Buffer) Peak() float32 {
+	peak := float32(0)
+	for _, s := range b.Samples {
+		peak = max(peak, float32(math.Abs(float64(s))))
+	}
+	return peak
+}
+
+// ApplyGain scales all of the samples by the gain given in decibels
+func (b *Buffer) ApplyGain(db float64) {
+	scale := float32(DecibelsToLinear(db))
+	for i := range b.Samples {
+		b.Samples[i] *= scale
+	}
+}
+
+func DecibelsToLinear(db float64) float64 { return math.Pow(10, db/20) }
+
+func LinearToDecibels(linear float64) float64 {
+	if linear <= 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(linear)
+}
@@ -0,0 +1,141 @@
+/******************************************************************************/
+/* loop.go                                                                    */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio_pcm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"strings"
+)
+
+// VorbisLoopStartComment is the Vorbis comment that holds the frame that a
+// looping Ogg Vorbis file will loop back to
+const VorbisLoopStartComment = "LOOPSTART"
+
+// ReadLoopStart finds the loop point (in seconds) that was stored in either
+// the "smpl" chunk of WAV data or the LOOPSTART comment of Ogg Vorbis data
+func ReadLoopStart(data []byte) (float64, bool) {
+	switch {
+	case IsWav(data):
+		return wavLoopStart(data)
+	case IsOggVorbis(data):
+		return oggVorbisLoopStart(data)
+	}
+	return 0, false
+}
+
+func wavLoopStart(data []byte) (float64, bool) {
+	sampleRate := 0
+	loopStart := -1
+	wavChunks(data, func(id string, chunk []byte) bool {
+		switch id {
+		case "fmt ":
+			if len(chunk) >= 8 {
+				sampleRate = int(binary.LittleEndian.Uint32(chunk[4:8]))
+			}
+		case "smpl":
+			if len(chunk) >= 36+24 && binary.LittleEndian.Uint32(chunk[28:32]) > 0 {
+				loopStart = int(binary.LittleEndian.Uint32(chunk[44:48]))
+			}
+		}
+		return sampleRate == 0 || loopStart < 0
+	})
+	if sampleRate <= 0 || loopStart <= 0 {
+		return 0, false
+	}
+	return float64(loopStart) / float64(sampleRate), true
+}
+
+// IsOggVorbis returns true if the data is an Ogg stream that holds Vorbis
+func IsOggVorbis(data []byte) bool {
+	packets := oggPackets(data, 1)
+	return len(packets) == 1 && bytes.HasPrefix(packets[0], []byte("\x01vorbis"))
+}
+
+func oggVorbisLoopStart(data []byte) (float64, bool) {
+	packets := oggPackets(data, 2)
+	if len(packets) < 2 || len(packets[0]) < 16 {
+		return 0, false
+	}
+	sampleRate := int(binary.LittleEndian.Uint32(packets[0][12:16]))
+	comment := packets[1]
+	if sampleRate <= 0 || !bytes.HasPrefix(comment, []byte("\x03vorbis")) {
+		return 0, false
+	}
+	read := func() (string, bool) {
+		if len(comment) < 4 {
+			return "", false
+		}
+		size := int(binary.LittleEndian.Uint32(comment))
+		if size > len(comment)-4 {
+			return "", false
+		}
+		s := string(comment[4 : 4+size])
+		comment = comment[4+size:]
+		return s, true
+	}
+	comment = comment[7:]
+	if _, ok := read(); !ok { // Vendor
+		return 0, false
+	}
+	if len(comment) < 4 {
+		return 0, false
+	}
+	count := int(binary.LittleEndian.Uint32(comment))
+	comment = comment[4:]
+	for range count {
+		c, ok := read()
+		if !ok {
+			break
+		}
+		key, value, found := strings.Cut(c, "=")
+		if !found || !strings.EqualFold(key, VorbisLoopStartComment) {
+			continue
+		}
+		if frame, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && frame > 0 {
+			return float64(frame) / float64(sampleRate), true
+		}
+	}
+	return 0, false
+}
+
+// oggPackets reads up to the given number of packets from the start of the
+// Ogg stream
+func oggPackets(data []byte, count int) [][]byte {
+	const headerSize = 27
+	var packets [][]byte
+	var packet []byte
+	for len(data) >= headerSize && len(packets) < count {
+		if string(data[:4]) != "OggS" {
+			break
+		}
+		segments := int(data[26])
+		if len(data) < headerSize+segments {
+			break
+		}
+		lacing := data[headerSize : headerSize+segments]
+		body := data[headerSize+segments:]
+		for _, l := range lacing {
+			if int(l) > len(body) {
+				return packets
+			}
+			packet = append(packet, body[:l]...)
+			body = body[l:]
+			// A lacing value less than 255 ends the packet
+			if l < 255 {
+				packets = append(packets, packet)
+				packet = nil
+				if len(packets) == count {
+					return packets
+				}
+			}
+		}
+		data = body
+	}
+	return packets
+}
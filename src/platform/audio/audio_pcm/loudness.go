@@ -0,0 +1,171 @@
+/******************************************************************************/
+/* loudness.go                                                                */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio_pcm
+
+import "math"
+
+// EBUR128TargetLoudness is the integrated loudness (in LUFS) that is
+// recommended by EBU R128 for normalized audio
+const EBUR128TargetLoudness = -23
+
+const (
+	loudnessBlockSeconds = 0.4
+	loudnessStepSeconds  = 0.1
+	loudnessAbsoluteGate = -70
+	loudnessRelativeGate = -10
+)
+
+// biquad is a second order IIR filter with normalized coefficients
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	z1, z2             float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.z1
+	f.z1 = f.b1*x - f.a1*y + f.z2
+	f.z2 = f.b2*x - f.a2*y
+	return y
+}
+
+// kWeighting creates the two stage K-weighting filter described by ITU-R
+// BS.1770 for the given sample rate, a high shelf that accounts for the
+// acoustic effects of the head followed by the RLB high pass filter
+func kWeighting(sampleRate int) (biquad, biquad) {
+	fs := float64(sampleRate)
+	// Stage 1, the pre-filter
+	f0 := 1681.974450955533
+	gain := 3.999843853973347
+	q := 0.7071752369554196
+	k := math.Tan(math.Pi * f0 / fs)
+	vh := math.Pow(10, gain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	shelf := biquad{
+		b0: (vh + vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/q + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+	// Stage 2, the RLB weighting curve
+	f0 = 38.13547087602444
+	q = 0.5003270373238773
+	k = math.Tan(math.Pi * f0 / fs)
+	a0 = 1 + k/q + k*k
+	highPass := biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+	return shelf, highPass
+}
+
+// loudnessChannelWeight is the weight of the channel when summing the
+// loudness, the LFE channel of 5.1 audio is ignored and the surround
+// channels are boosted
+func loudnessChannelWeight(channel, channels int) float64 {
+	if channels == 6 {
+		switch channel {
+		case 3:
+			return 0
+		case 4, 5:
+			return 1.41
+		}
+	}
+	return 1
+}
+
+func blockLoudness(meanSquares []float64, channels int) float64 {
+	sum := 0.0
+	for c := range channels {
+		sum += loudnessChannelWeight(c, channels) * meanSquares[c]
+	}
+	if sum <= 0 {
+		return math.Inf(-1)
+	}
+	return -0.691 + 10*math.Log10(sum)
+}
+
+// Loudness measures the integrated loudness of the buffer in LUFS as
+// described by EBU R128 (ITU-R BS.1770) using 400ms gated blocks. Audio that
+// is shorter than a single block is measured as a single block. Silence
+// returns negative infinity.
+func (b *Buffer) Loudness() float64 {
+	frames := b.Frames()
+	if frames == 0 || b.SampleRate <= 0 {
+		return math.Inf(-1)
+	}
+	// K-weight each channel and store the squared samples
+	squares := make([]float64, len(b.Samples))
+	for c := range b.Channels {
+		shelf, highPass := kWeighting(b.SampleRate)
+		for i := range frames {
+			idx := i*b.Channels + c
+			y := highPass.process(shelf.process(float64(b.Samples[idx])))
+			squares[idx] = y * y
+		}
+	}
+	blockSize := min(frames, int(loudnessBlockSeconds*float64(b.SampleRate)))
+	step := max(1, int(loudnessStepSeconds*float64(b.SampleRate)))
+	var blocks [][]float64
+	for start := 0; start+blockSize <= frames; start += step {
+		ms := make([]float64, b.Channels)
+		for i := start; i < start+blockSize; i++ {
+			for c := range b.Channels {
+				ms[c] += squares[i*b.Channels+c]
+			}
+		}
+		for c := range ms {
+			ms[c] /= float64(blockSize)
+		}
+		blocks = append(blocks, ms)
+	}
+	gated := func(threshold float64) (float64, int) {
+		mean := make([]float64, b.Channels)
+		count := 0
+		for _, ms := range blocks {
+			if l := blockLoudness(ms, b.Channels); l > threshold && l > loudnessAbsoluteGate {
+				for c := range mean {
+					mean[c] += ms[c]
+				}
+				count++
+			}
+		}
+		if count == 0 {
+			return math.Inf(-1), 0
+		}
+		for c := range mean {
+			mean[c] /= float64(count)
+		}
+		return blockLoudness(mean, b.Channels), count
+	}
+	absolute, count := gated(loudnessAbsoluteGate)
+	if count == 0 {
+		return math.Inf(-1)
+	}
+	integrated, _ := gated(absolute + loudnessRelativeGate)
+	return integrated
+}
+
+// NormalizationGain returns the gain (in decibels) that will bring the
+// buffer to the target loudness. The gain is reduced when needed so that the
+// peak of the buffer does not go over the ceiling (in dBFS). Silent audio
+// returns 0.
+func (b *Buffer) NormalizationGain(targetLoudness, peakCeiling float64) float64 {
+	loudness := b.Loudness()
+	if math.IsInf(loudness, -1) {
+		return 0
+	}
+	gain := targetLoudness - loudness
+	if peak := LinearToDecibels(float64(b.Peak())); !math.IsInf(peak, -1) {
+		gain = min(gain, peakCeiling-peak)
+	}
+	return gain
+}
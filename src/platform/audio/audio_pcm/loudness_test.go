@@ -0,0 +1,60 @@
+/******************************************************************************/
+/* loudness_test.go                                                           */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio_pcm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLoudnessSine(t *testing.T) {
+	// A stereo 997Hz sine at -20dBFS measures -20 LUFS
+	for _, rate := range []int{44100, 48000} {
+		buf := testSine(rate, 2, 997, DecibelsToLinear(-20), 3)
+		if l := buf.Loudness(); math.Abs(l+20) > 0.1 {
+			t.Errorf("expected -20 LUFS at %dHz, got %f", rate, l)
+		}
+	}
+	// The same sine in only one channel is 3dB quieter
+	buf := testSine(48000, 1, 997, DecibelsToLinear(-20), 3)
+	if l := buf.Loudness(); math.Abs(l+23.01) > 0.1 {
+		t.Errorf("expected -23.01 LUFS, got %f", l)
+	}
+}
+
+func TestLoudnessGating(t *testing.T) {
+	loud := testSine(48000, 1, 997, DecibelsToLinear(-20), 2)
+	quiet := testSine(48000, 1, 997, DecibelsToLinear(-50), 2)
+	silence := make([]float32, 48000*2)
+	buf := loud
+	buf.Samples = append(append(append([]float32{}, loud.Samples...), quiet.Samples...), silence...)
+	// Blocks that overlap the end of the loud section still pass the gate
+	if l := buf.Loudness(); math.Abs(l-loud.Loudness()) > 0.5 {
+		t.Errorf("expected the quiet and silent blocks to be gated, got %f instead of %f",
+			l, loud.Loudness())
+	}
+	empty := Buffer{SampleRate: 48000, Channels: 1, Samples: silence}
+	if l := empty.Loudness(); !math.IsInf(l, -1) {
+		t.Errorf("expected silence to be -inf LUFS, got %f", l)
+	}
+}
+
+func TestNormalizationGain(t *testing.T) {
+	buf := testSine(48000, 2, 997, DecibelsToLinear(-30), 2)
+	gain := buf.NormalizationGain(EBUR128TargetLoudness, -1)
+	if math.Abs(gain-7) > 0.1 {
+		t.Errorf("expected a gain of 7dB, got %f", gain)
+	}
+	buf.ApplyGain(gain)
+	if l := buf.Loudness(); math.Abs(l-EBUR128TargetLoudness) > 0.1 {
+		t.Errorf("expected the normalized loudness to be %d LUFS, got %f", EBUR128TargetLoudness, l)
+	}
+	// The gain is limited by the peak ceiling
+	if g := buf.NormalizationGain(0, -1); math.Abs(g-(-1-LinearToDecibels(float64(buf.Peak())))) > 1e-6 {
+		t.Errorf("expected the gain to be limited by the peak, got %f", g)
+	}
+}
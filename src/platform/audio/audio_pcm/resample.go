@@ -0,0 +1,74 @@
+/******************************************************************************/
+/* resample.go                                                                */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio_pcm
+
+import "math"
+
+// resampleZeroCrossings is the number of zero crossings on each side of the
+// windowed sinc filter used for resampling
+const resampleZeroCrossings = 16
+
+// Resample converts the buffer to the given sample rate using a windowed
+// sinc filter. When down-sampling, the filter cutoff is lowered to the new
+// Nyquist frequency to prevent aliasing.
+func (b *Buffer) Resample(sampleRate int) Buffer {
+	if sampleRate <= 0 || sampleRate == b.SampleRate || b.Frames() == 0 {
+		return *b
+	}
+	ratio := float64(sampleRate) / float64(b.SampleRate)
+	cutoff := min(1, ratio)
+	inFrames := b.Frames()
+	outFrames := int(math.Round(float64(inFrames) * ratio))
+	out := Buffer{
+		SampleRate: sampleRate,
+		Channels:   b.Channels,
+		Samples:    make([]float32, outFrames*b.Channels),
+	}
+	radius := resampleZeroCrossings / cutoff
+	weights := make([]float64, 0, int(2*radius)+2)
+	for i := range outFrames {
+		center := float64(i) / ratio
+		first := max(0, int(math.Ceil(center-radius)))
+		last := min(inFrames-1, int(math.Floor(center+radius)))
+		weights = weights[:0]
+		total := 0.0
+		for j := first; j <= last; j++ {
+			x := (float64(j) - center) * cutoff
+			w := sinc(x) * blackman(x/resampleZeroCrossings)
+			weights = append(weights, w)
+			total += w
+		}
+		if total == 0 {
+			continue
+		}
+		for c := range b.Channels {
+			sum := 0.0
+			for k, w := range weights {
+				sum += w * float64(b.Samples[(first+k)*b.Channels+c])
+			}
+			out.Samples[i*b.Channels+c] = float32(sum / total)
+		}
+	}
+	return out
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	x *= math.Pi
+	return math.Sin(x) / x
+}
+
+// blackman is the Blackman window for x in the range [-1, 1]
+func blackman(x float64) float64 {
+	if x <= -1 || x >= 1 {
+		return 0
+	}
+	t := math.Pi * (x + 1)
+	return 0.42 - 0.5*math.Cos(t) + 0.08*math.Cos(2*t)
+}
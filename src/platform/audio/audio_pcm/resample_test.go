@@ -0,0 +1,67 @@
+/******************************************************************************/
+/* resample_test.go                                                           */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio_pcm
+
+import (
+	"math"
+	"testing"
+)
+
+// testFrequency estimates the frequency of a sine by counting the rising zero
+// crossings of the first channel
+func testFrequency(buf Buffer) float64 {
+	crossings := 0
+	for i := 1; i < buf.Frames(); i++ {
+		if buf.Samples[(i-1)*buf.Channels] < 0 && buf.Samples[i*buf.Channels] >= 0 {
+			crossings++
+		}
+	}
+	return float64(crossings) / buf.Duration()
+}
+
+func TestResample(t *testing.T) {
+	in := testSine(44100, 2, 1000, 0.5, 1)
+	for _, rate := range []int{22050, 48000} {
+		out := in.Resample(rate)
+		if out.SampleRate != rate || out.Channels != 2 {
+			t.Fatalf("expected 2 channels at %dHz, got %d at %dHz", rate, out.Channels, out.SampleRate)
+		}
+		if out.Frames() != rate {
+			t.Errorf("expected %d frames, got %d", rate, out.Frames())
+		}
+		if f := testFrequency(out); math.Abs(f-1000) > 2 {
+			t.Errorf("expected the frequency to stay 1000Hz at %dHz, got %f", rate, f)
+		}
+		if p := out.Peak(); math.Abs(float64(p)-0.5) > 0.01 {
+			t.Errorf("expected the amplitude to stay 0.5 at %dHz, got %f", rate, p)
+		}
+	}
+}
+
+func TestResampleRemovesAliasing(t *testing.T) {
+	// 15kHz can't be represented at 16kHz and should be filtered out
+	in := testSine(44100, 1, 15000, 0.5, 0.5)
+	out := in.Resample(16000)
+	out.Samples = out.Samples[100 : len(out.Samples)-100]
+	if p := out.Peak(); p > 0.01 {
+		t.Errorf("expected the frequency above Nyquist to be removed, got a peak of %f", p)
+	}
+}
+
+func TestDownmix(t *testing.T) {
+	buf := Buffer{SampleRate: 100, Channels: 2, Samples: []float32{1, 0, 0.5, 0.5, -1, 1}}
+	mono := buf.Downmix()
+	want := []float32{0.5, 0.5, 0}
+	if mono.Channels != 1 || len(mono.Samples) != len(want) {
+		t.Fatalf("expected %d mono samples, got %d with %d channels", len(want), len(mono.Samples), mono.Channels)
+	}
+	for i := range want {
+		if mono.Samples[i] != want[i] {
+			t.Errorf("sample %d expected %f, got %f", i, want[i], mono.Samples[i])
+		}
+	}
+}
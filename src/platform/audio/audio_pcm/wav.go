@@ -0,0 +1,180 @@
+/******************************************************************************/
+/* wav.go                                                                     */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio_pcm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+)
+
+// IsWav returns true if the data starts with a RIFF WAVE header
+func IsWav(data []byte) bool {
+	return len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WAVE"
+}
+
+type wavFormat struct {
+	format        uint16
+	channels      int
+	sampleRate    int
+	bitsPerSample int
+}
+
+// wavChunks calls the function for each of the chunks in the RIFF data, the
+// iteration stops if the function returns false
+func wavChunks(data []byte, fn func(id string, chunk []byte) bool) error {
+	if !IsWav(data) {
+		return errors.New("the data is not a RIFF WAVE file")
+	}
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		start := pos + 8
+		// Streamed WAV data doesn't know the size of the data ahead of time
+		if id == "data" && (size == 0 || size == math.MaxUint32) {
+			size = len(data) - start
+		}
+		end := min(start+size, len(data))
+		if !fn(id, data[start:end]) {
+			return nil
+		}
+		// Chunks are padded to an even number of bytes
+		pos = end + size&1
+	}
+	return nil
+}
+
+// DecodeWav decodes 8, 16, 24, or 32 bit integer PCM and 32 or 64 bit float
+// WAV data into a [Buffer]
+func DecodeWav(data []byte) (Buffer, error) {
+	var fmtChunk, dataChunk []byte
+	err := wavChunks(data, func(id string, chunk []byte) bool {
+		switch id {
+		case "fmt ":
+			fmtChunk = chunk
+		case "data":
+			dataChunk = chunk
+		}
+		return fmtChunk == nil || dataChunk == nil
+	})
+	if err != nil {
+		return Buffer{}, err
+	}
+	if len(fmtChunk) < 16 || dataChunk == nil {
+		return Buffer{}, errors.New("the WAV data is missing the fmt or data chunk")
+	}
+	f := wavFormat{
+		format:        binary.LittleEndian.Uint16(fmtChunk[0:2]),
+		channels:      int(binary.LittleEndian.Uint16(fmtChunk[2:4])),
+		sampleRate:    int(binary.LittleEndian.Uint32(fmtChunk[4:8])),
+		bitsPerSample: int(binary.LittleEndian.Uint16(fmtChunk[14:16])),
+	}
+	if f.format == wavFormatExtensible && len(fmtChunk) >= 26 {
+		f.format = binary.LittleEndian.Uint16(fmtChunk[24:26])
+	}
+	if f.channels <= 0 || f.sampleRate <= 0 {
+		return Buffer{}, errors.New("the WAV data has an invalid channel count or sample rate")
+	}
+	bytesPerSample := f.bitsPerSample / 8
+	valid := (f.format == wavFormatPCM && bytesPerSample >= 1 && bytesPerSample <= 4) ||
+		(f.format == wavFormatFloat && (bytesPerSample == 4 || bytesPerSample == 8))
+	if !valid {
+		return Buffer{}, fmt.Errorf("unsupported WAV format %d with %d bits per sample",
+			f.format, f.bitsPerSample)
+	}
+	count := len(dataChunk) / bytesPerSample
+	count -= count % f.channels
+	buf := Buffer{
+		SampleRate: f.sampleRate,
+		Channels:   f.channels,
+		Samples:    make([]float32, count),
+	}
+	for i := range count {
+		s := dataChunk[i*bytesPerSample:]
+		switch {
+		case f.format == wavFormatFloat && bytesPerSample == 4:
+			buf.Samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(s))
+		case f.format == wavFormatFloat:
+			buf.Samples[i] = float32(math.Float64frombits(binary.LittleEndian.Uint64(s)))
+		case bytesPerSample == 1:
+			// 8 bit WAV data is the only unsigned format
+			buf.Samples[i] = (float32(s[0]) - 128) / 128
+		case bytesPerSample == 2:
+			buf.Samples[i] = float32(int16(binary.LittleEndian.Uint16(s))) / (1 << 15)
+		case bytesPerSample == 3:
+			v := int32(uint32(s[0])<<8|uint32(s[1])<<16|uint32(s[2])<<24) >> 8
+			buf.Samples[i] = float32(v) / (1 << 23)
+		default:
+			buf.Samples[i] = float32(int32(binary.LittleEndian.Uint32(s))) / (1 << 31)
+		}
+	}
+	return buf, nil
+}
+
+// EncodeWav encodes the buffer as 16 bit PCM WAV data. When loopStart is
+// greater than 0, a "smpl" chunk is written that loops from the loopStart
+// frame to the end of the audio.
+func EncodeWav(buf Buffer, loopStart int) []byte {
+	const bytesPerSample = 2
+	dataSize := len(buf.Samples) * bytesPerSample
+	var smpl []byte
+	if loopStart > 0 && loopStart < buf.Frames() {
+		smpl = wavSampleChunk(buf, loopStart)
+	}
+	out := bytes.NewBuffer(make([]byte, 0, 44+dataSize+len(smpl)))
+	w := func(v any) { binary.Write(out, binary.LittleEndian, v) }
+	out.WriteString("RIFF")
+	w(uint32(4 + 8 + 16 + 8 + dataSize + len(smpl)))
+	out.WriteString("WAVEfmt ")
+	w(uint32(16))
+	w(uint16(wavFormatPCM))
+	w(uint16(buf.Channels))
+	w(uint32(buf.SampleRate))
+	w(uint32(buf.SampleRate * buf.Channels * bytesPerSample))
+	w(uint16(buf.Channels * bytesPerSample))
+	w(uint16(bytesPerSample * 8))
+	out.WriteString("data")
+	w(uint32(dataSize))
+	pcm := make([]int16, len(buf.Samples))
+	for i, s := range buf.Samples {
+		pcm[i] = int16(math.Round(float64(min(max(s, -1), 1)) * math.MaxInt16))
+	}
+	w(pcm)
+	out.Write(smpl)
+	return out.Bytes()
+}
+
+func wavSampleChunk(buf Buffer, loopStart int) []byte {
+	const loopCount = 1
+	chunk := make([]byte, 0, 8+36+24*loopCount)
+	chunk = append(chunk, "smpl"...)
+	chunk = binary.LittleEndian.AppendUint32(chunk, 36+24*loopCount)
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0) // Manufacturer
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0) // Product
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(1e9/buf.SampleRate))
+	chunk = binary.LittleEndian.AppendUint32(chunk, 60) // MIDI unity note
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0)  // MIDI pitch fraction
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0)  // SMPTE format
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0)  // SMPTE offset
+	chunk = binary.LittleEndian.AppendUint32(chunk, loopCount)
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0) // Sampler data
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0) // Cue point id
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0) // Forward loop
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(loopStart))
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(buf.Frames()-1))
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0) // Fraction
+	chunk = binary.LittleEndian.AppendUint32(chunk, 0) // Play count, infinite
+	return chunk
+}
@@ -0,0 +1,119 @@
+/******************************************************************************/
+/* wav_test.go                                                                */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio_pcm
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func testSine(sampleRate, channels int, frequency, amplitude, seconds float64) Buffer {
+	frames := int(float64(sampleRate) * seconds)
+	buf := Buffer{
+		SampleRate: sampleRate,
+		Channels:   channels,
+		Samples:    make([]float32, frames*channels),
+	}
+	for i := range frames {
+		s := float32(amplitude * math.Sin(2*math.Pi*frequency*float64(i)/float64(sampleRate)))
+		for c := range channels {
+			buf.Samples[i*channels+c] = s
+		}
+	}
+	return buf
+}
+
+func TestWavRoundTrip(t *testing.T) {
+	in := testSine(22050, 2, 440, 0.5, 0.25)
+	data := EncodeWav(in, 0)
+	if !IsWav(data) {
+		t.Fatal("expected the encoded data to be a WAV file")
+	}
+	out, err := DecodeWav(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.SampleRate != in.SampleRate || out.Channels != in.Channels || out.Frames() != in.Frames() {
+		t.Fatalf("expected %d frames at %dHz with %d channels, got %d frames at %dHz with %d channels",
+			in.Frames(), in.SampleRate, in.Channels, out.Frames(), out.SampleRate, out.Channels)
+	}
+	for i := range in.Samples {
+		if math.Abs(float64(in.Samples[i]-out.Samples[i])) > 1.0/math.MaxInt16 {
+			t.Fatalf("sample %d expected %f, got %f", i, in.Samples[i], out.Samples[i])
+		}
+	}
+	if _, ok := ReadLoopStart(data); ok {
+		t.Error("expected no loop point without a smpl chunk")
+	}
+}
+
+func TestWavDecode24Bit(t *testing.T) {
+	data := EncodeWav(Buffer{SampleRate: 8000, Channels: 1, Samples: []float32{0}}, 0)
+	// Rewrite the header and data as a single 24 bit sample of -0.5
+	binary.LittleEndian.PutUint16(data[32:], 3)
+	binary.LittleEndian.PutUint16(data[34:], 24)
+	binary.LittleEndian.PutUint32(data[40:], 3)
+	data = append(data[:44], 0x00, 0x00, 0xC0)
+	buf, err := DecodeWav(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buf.Samples) != 1 || buf.Samples[0] != -0.5 {
+		t.Errorf("expected a single sample of -0.5, got %v", buf.Samples)
+	}
+}
+
+func TestWavLoopStart(t *testing.T) {
+	in := testSine(8000, 1, 440, 0.5, 1)
+	data := EncodeWav(in, 2000)
+	loop, ok := ReadLoopStart(data)
+	if !ok {
+		t.Fatal("expected to find the loop point")
+	}
+	if loop != 0.25 {
+		t.Errorf("expected the loop to start at 0.25 seconds, got %f", loop)
+	}
+	if out, err := DecodeWav(data); err != nil || out.Frames() != in.Frames() {
+		t.Errorf("expected the smpl chunk not to change the decoded audio")
+	}
+}
+
+func TestOggVorbisLoopStart(t *testing.T) {
+	page := func(packet []byte) []byte {
+		p := append([]byte("OggS"), make([]byte, 22)...)
+		lacing := []byte{}
+		for n := len(packet); ; n -= 255 {
+			if n < 255 {
+				lacing = append(lacing, byte(n))
+				break
+			}
+			lacing = append(lacing, 255)
+		}
+		p = append(p, byte(len(lacing)))
+		p = append(p, lacing...)
+		return append(p, packet...)
+	}
+	ident := append([]byte("\x01vorbis"), make([]byte, 23)...)
+	binary.LittleEndian.PutUint32(ident[12:], 48000)
+	str := func(b []byte, s string) []byte {
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+		return append(b, s...)
+	}
+	comment := str([]byte("\x03vorbis"), "test vendor")
+	comment = binary.LittleEndian.AppendUint32(comment, 2)
+	comment = str(comment, "TITLE=loop")
+	comment = str(comment, "LOOPSTART=96000")
+	data := append(page(ident), page(comment)...)
+	if !IsOggVorbis(data) {
+		t.Fatal("expected the data to be Ogg Vorbis")
+	}
+	loop, ok := ReadLoopStart(data)
+	if !ok || loop != 2 {
+		t.Errorf("expected the loop to start at 2 seconds, got %f (%t)", loop, ok)
+	}
+}
@@ -0,0 +1,189 @@
+/******************************************************************************/
+/* bus.go                                                                     */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"kaijuengine.com/klib"
+)
+
+const MasterBusName = "master"
+
+// Bus is a mixing group that sounds can be played on. Busses form a
+// hierarchy where each bus is mixed into its parent, so the volume, mute, and
+// effects of a bus also apply to all of the busses below it.
+type Bus struct {
+	audio    *Audio
+	name     string
+	parent   *Bus
+	children []*Bus
+	volume   float32
+	muted    bool
+	effects  [MaxBusEffects]Effect
+	filters  [MaxBusEffects]SoloudFilter
+	bus      SoloudBus
+	handle   VoiceHandle
+}
+
+func newBus(a *Audio, name string, parent *Bus) *Bus {
+	b := &Bus{
+		audio:  a,
+		name:   name,
+		parent: parent,
+		volume: 1,
+	}
+	if parent != nil {
+		parent.children = append(parent.children, b)
+	}
+	if a.isActive() {
+		b.bus = busCreate()
+		var parentBus SoloudBus
+		if parent != nil {
+			parentBus = parent.bus
+		}
+		b.handle = busPlay(a.soloud, b.bus, parentBus)
+		// Busses must never be killed to make room for other voices
+		setProtectVoice(a.soloud, b.handle, true)
+	}
+	return b
+}
+
+func (b *Bus) Name() string     { return b.name }
+func (b *Bus) Parent() *Bus     { return b.parent }
+func (b *Bus) Children() []*Bus { return b.children }
+func (b *Bus) Volume() float32  { return b.volume }
+func (b *Bus) IsMuted() bool    { return b.muted }
+
+// EffectiveVolume is the volume that sounds on this bus will actually be
+// played at once the volume and mute of all of the parent busses are applied
+func (b *Bus) EffectiveVolume() float32 {
+	v := float32(1)
+	for p := b; p != nil; p = p.parent {
+		if p.muted {
+			return 0
+		}
+		v *= p.volume
+	}
+	return v
+}
+
+func (b *Bus) SetVolume(volume float32) {
+	b.volume = klib.Clamp(volume, 0.0, 1.0)
+	b.apply()
+}
+
+func (b *Bus) Mute() {
+	b.muted = true
+	b.apply()
+}
+
+func (b *Bus) Unmute() {
+	b.muted = false
+	b.apply()
+}
+
+// Find searches this bus and all of the busses below it for the bus with the
+// given name
+func (b *Bus) Find(name string) (*Bus, bool) {
+	if b.name == name {
+		return b, true
+	}
+	for _, c := range b.children {
+		if found, ok := c.Find(name); ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+// Effect returns the effect that is in the given slot of the bus
+func (b *Bus) Effect(slot int) Effect {
+	if slot < 0 || slot >= MaxBusEffects {
+		return Effect{}
+	}
+	return b.effects[slot]
+}
+
+// SetEffect places the effect into one of the [MaxBusEffects] slots of the
+// bus, replacing any effect that was already in that slot. Effects are
+// processed in the order of their slots.
+func (b *Bus) SetEffect(slot int, effect Effect) error {
+	if slot < 0 || slot >= MaxBusEffects {
+		return fmt.Errorf("bus effect slot %d is out of range [0, %d)", slot, MaxBusEffects)
+	}
+	if b.audio.isActive() {
+		filter := effect.createFilter()
+		if filter == nil && effect.Type != EffectNone {
+			return fmt.Errorf("unsupported bus effect type %d", effect.Type)
+		}
+		busSetFilter(b.bus, uint32(slot), filter)
+		if b.filters[slot] != nil {
+			filterDestroy(b.effects[slot].Type, b.filters[slot])
+		}
+		b.filters[slot] = filter
+		if filter != nil {
+			setFilterParameter(b.audio.soloud, b.handle, uint32(slot), filterWetAttribute, effect.Wet)
+		}
+	}
+	b.effects[slot] = effect
+	return nil
+}
+
+// SetEffectWet changes the dry/wet mix of the effect in the given slot
+func (b *Bus) SetEffectWet(slot int, wet float32) error {
+	if slot < 0 || slot >= MaxBusEffects || b.effects[slot].Type == EffectNone {
+		return errors.New("there is no effect in the bus slot")
+	}
+	b.effects[slot].Wet = klib.Clamp(wet, 0.0, 1.0)
+	if b.audio.isActive() {
+		setFilterParameter(b.audio.soloud, b.handle, uint32(slot),
+			filterWetAttribute, b.effects[slot].Wet)
+	}
+	return nil
+}
+
+func (b *Bus) ClearEffect(slot int) error {
+	return b.SetEffect(slot, Effect{})
+}
+
+func (b *Bus) apply() {
+	if !b.audio.isActive() {
+		return
+	}
+	if b.muted {
+		setVolume(b.audio.soloud, b.handle, 0)
+	} else {
+		setVolume(b.audio.soloud, b.handle, b.volume)
+	}
+}
+
+// destroy stops the bus (and all sounds playing on it) and releases it along
+// with all of the busses below it
+func (b *Bus) destroy() {
+	for len(b.children) > 0 {
+		b.children[len(b.children)-1].destroy()
+	}
+	if b.parent != nil {
+		if i := slices.Index(b.parent.children, b); i >= 0 {
+			b.parent.children = slices.Delete(b.parent.children, i, i+1)
+		}
+	}
+	if b.audio.isActive() && b.bus != nil {
+		stopAudio(b.audio.soloud, b.handle)
+		busDestroy(b.bus)
+		for i := range b.filters {
+			if b.filters[i] != nil {
+				filterDestroy(b.effects[i].Type, b.filters[i])
+			}
+		}
+	}
+	b.bus = nil
+	b.filters = [MaxBusEffects]SoloudFilter{}
+}
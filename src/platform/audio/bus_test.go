@@ -0,0 +1,76 @@
+/******************************************************************************/
+/* bus_test.go                                                                */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio
+
+import "testing"
+
+func testBusTree() (*Bus, *Bus, *Bus) {
+	master := newBus(nil, MasterBusName, nil)
+	sfx := newBus(nil, "sfx", master)
+	footsteps := newBus(nil, "footsteps", sfx)
+	return master, sfx, footsteps
+}
+
+func TestBusEffectiveVolume(t *testing.T) {
+	master, sfx, footsteps := testBusTree()
+	master.SetVolume(0.5)
+	sfx.SetVolume(0.5)
+	footsteps.SetVolume(2)
+	if footsteps.Volume() != 1 {
+		t.Errorf("expected the volume to be clamped to 1, got %f", footsteps.Volume())
+	}
+	if v := footsteps.EffectiveVolume(); v != 0.25 {
+		t.Errorf("expected an effective volume of 0.25, got %f", v)
+	}
+	sfx.Mute()
+	if v := footsteps.EffectiveVolume(); v != 0 {
+		t.Errorf("expected a muted parent to silence the child, got %f", v)
+	}
+	sfx.Unmute()
+	if v := footsteps.EffectiveVolume(); v != 0.25 {
+		t.Errorf("expected unmute to restore the volume, got %f", v)
+	}
+}
+
+func TestBusFindAndDestroy(t *testing.T) {
+	master, sfx, footsteps := testBusTree()
+	if b, ok := master.Find("footsteps"); !ok || b != footsteps {
+		t.Fatal("failed to find the nested bus")
+	}
+	if _, ok := sfx.Find(MasterBusName); ok {
+		t.Error("a bus should not find its parent")
+	}
+	sfx.destroy()
+	if len(master.Children()) != 0 {
+		t.Errorf("expected the destroyed bus to be removed from its parent")
+	}
+	if _, ok := master.Find("footsteps"); ok {
+		t.Error("expected the child of a destroyed bus to be destroyed")
+	}
+}
+
+func TestBusSetEffect(t *testing.T) {
+	master, _, _ := testBusTree()
+	if err := master.SetEffect(MaxBusEffects, LowPassEffect(800, 2)); err == nil {
+		t.Error("expected an error for an out of range effect slot")
+	}
+	if err := master.SetEffect(-1, LowPassEffect(800, 2)); err == nil {
+		t.Error("expected an error for a negative effect slot")
+	}
+	if err := master.SetEffect(1, ReverbEffect(0.5, 0.5, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if master.Effect(1).Type != EffectReverb {
+		t.Errorf("expected a reverb effect in slot 1, got %d", master.Effect(1).Type)
+	}
+	if err := master.ClearEffect(1); err != nil {
+		t.Fatal(err)
+	}
+	if master.Effect(1).Type != EffectNone {
+		t.Errorf("expected slot 1 to be cleared, got %d", master.Effect(1).Type)
+	}
+}
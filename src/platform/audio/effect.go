@@ -0,0 +1,73 @@
+/******************************************************************************/
+/* effect.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio
+
+type EffectType int
+
+const (
+	EffectNone EffectType = iota
+	EffectLowPass
+	EffectHighPass
+	EffectEcho
+	EffectReverb
+)
+
+// MaxBusEffects is the number of effect slots on each bus, this matches the
+// number of filters that SoLoud allows per audio source
+const MaxBusEffects = 8
+
+// Effect describes a DSP filter that is applied to everything that plays on a
+// [Bus]. Only the fields that are relevant to the Type are used, the helper
+// constructors such as [LowPassEffect] should typically be used to create one.
+type Effect struct {
+	Type EffectType
+	// Wet is the mix of the filtered signal with the original, 0 is fully dry
+	// and 1 is fully wet
+	Wet float32
+	// Frequency and Resonance are used by low and high pass effects
+	Frequency float32
+	Resonance float32
+	// Delay, Decay, and Filter are used by echo effects
+	Delay  float32
+	Decay  float32
+	Filter float32
+	// RoomSize, Damp, and Width are used by reverb effects
+	RoomSize float32
+	Damp     float32
+	Width    float32
+}
+
+func LowPassEffect(frequency, resonance float32) Effect {
+	return Effect{Type: EffectLowPass, Wet: 1, Frequency: frequency, Resonance: resonance}
+}
+
+func HighPassEffect(frequency, resonance float32) Effect {
+	return Effect{Type: EffectHighPass, Wet: 1, Frequency: frequency, Resonance: resonance}
+}
+
+func EchoEffect(delay, decay float32) Effect {
+	return Effect{Type: EffectEcho, Wet: 1, Delay: delay, Decay: decay}
+}
+
+func ReverbEffect(roomSize, damp, width float32) Effect {
+	return Effect{Type: EffectReverb, Wet: 1, RoomSize: roomSize, Damp: damp, Width: width}
+}
+
+func (e Effect) createFilter() SoloudFilter {
+	switch e.Type {
+	case EffectLowPass:
+		return lowPassFilterCreate(biquadLowPass, e.Frequency, e.Resonance)
+	case EffectHighPass:
+		return lowPassFilterCreate(biquadHighPass, e.Frequency, e.Resonance)
+	case EffectEcho:
+		return echoFilterCreate(e.Delay, e.Decay, e.Filter)
+	case EffectReverb:
+		return reverbFilterCreate(e.RoomSize, e.Damp, e.Width)
+	default:
+		return nil
+	}
+}
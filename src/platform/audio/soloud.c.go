@@ -23,6 +23,8 @@ import (
 
 type SoloudHandle = *C.Soloud
 type SoloudWav = *C.Wav
+type SoloudWavStream = *C.WavStream
+type SoloudSource = *C.AudioSource
 type VoiceHandle = uint32
 
 const InvalidVoiceHandle = VoiceHandle(0)
@@ -63,6 +65,49 @@ func wavDestroy(wav SoloudWav) {
 	C.Wav_destroy(wav)
 }
 
+func wavSource(wav SoloudWav) SoloudSource {
+	return (*C.AudioSource)(wav)
+}
+
+func wavSetLoopPoint(wav SoloudWav, seconds float64) {
+	C.Wav_setLoopPoint(wav, C.double(seconds))
+}
+
+func streamCreate() SoloudWavStream {
+	return C.WavStream_create()
+}
+
+// streamLoadMem copies the encoded data into memory owned by SoLoud, so the
+// caller's slice can be released once this returns. The data is decoded while
+// it plays rather than all at once.
+func streamLoadMem(stream SoloudWavStream, data []byte) error {
+	res := int(C.WavStream_loadMemEx(stream, (*C.uchar)(unsafe.Pointer(&data[0])), C.uint(len(data)), C.int(1), C.int(0)))
+	if res != 0 {
+		return fmt.Errorf("there was an error loading the audio stream memory: %d", res)
+	}
+	return nil
+}
+
+func streamDestroy(stream SoloudWavStream) {
+	C.WavStream_destroy(stream)
+}
+
+func streamSource(stream SoloudWavStream) SoloudSource {
+	return (*C.AudioSource)(stream)
+}
+
+func streamSetVolume(stream SoloudWavStream, volume float32) {
+	C.WavStream_setVolume(stream, C.float(volume))
+}
+
+func streamLength(stream SoloudWavStream) float64 {
+	return float64(C.WavStream_getLength(stream))
+}
+
+func streamSetLoopPoint(stream SoloudWavStream, seconds float64) {
+	C.WavStream_setLoopPoint(stream, C.double(seconds))
+}
+
 func wavSetVolume(wav SoloudWav, volume float32) {
 	C.Wav_setVolume(wav, C.float(volume))
 }
@@ -75,16 +120,12 @@ func clipLength(wav SoloudWav) float64 {
 	return float64(C.Wav_getLength(wav))
 }
 
-func play(soloud SoloudHandle, wav SoloudWav) VoiceHandle {
-	return VoiceHandle(C.Soloud_play(soloud, (*C.AudioSource)(wav)))
-}
-
 func stopAudio(soloud SoloudHandle, handle VoiceHandle) {
 	C.Soloud_stop(soloud, (C.uint)(handle))
 }
 
-func stopAudioSource(soloud SoloudHandle, wav SoloudWav) {
-	C.Soloud_stopAudioSource(soloud, (*C.AudioSource)(wav))
+func stopAudioSource(soloud SoloudHandle, src SoloudSource) {
+	C.Soloud_stopAudioSource(soloud, src)
 }
 
 func isValidVoiceHandle(soloud SoloudHandle, handle VoiceHandle) bool {
@@ -95,6 +136,22 @@ func seek(soloud SoloudHandle, handle VoiceHandle, seconds float64) int {
 	return int(C.Soloud_seek(soloud, (C.uint)(handle), C.double(seconds)))
 }
 
+func setPause(soloud SoloudHandle, handle VoiceHandle, paused bool) {
+	if paused {
+		C.Soloud_setPause(soloud, C.uint(handle), C.int(1))
+	} else {
+		C.Soloud_setPause(soloud, C.uint(handle), C.int(0))
+	}
+}
+
+func getPause(soloud SoloudHandle, handle VoiceHandle) bool {
+	return int(C.Soloud_getPause(soloud, C.uint(handle))) != 0
+}
+
+func getStreamPosition(soloud SoloudHandle, handle VoiceHandle) float64 {
+	return float64(C.Soloud_getStreamPosition(soloud, C.uint(handle)))
+}
+
 func setLooping(soloud SoloudHandle, handle uint32, loop bool) {
 	if loop {
 		C.Soloud_setLooping(soloud, C.uint(handle), C.int(1))
@@ -102,3 +159,120 @@ func setLooping(soloud SoloudHandle, handle uint32, loop bool) {
 		C.Soloud_setLooping(soloud, C.uint(handle), C.int(0))
 	}
 }
+
+type SoloudBus = *C.Bus
+type SoloudFilter = *C.Filter
+
+func playEx(soloud SoloudHandle, src SoloudSource, volume float32, bus VoiceHandle) VoiceHandle {
+	return VoiceHandle(C.Soloud_playEx(soloud, src,
+		C.float(volume), C.float(0), C.int(0), C.uint(bus)))
+}
+
+func play3dEx(soloud SoloudHandle, src SoloudSource, position, velocity [3]float32, volume float32, bus VoiceHandle) VoiceHandle {
+	return VoiceHandle(C.Soloud_play3dEx(soloud, src,
+		C.float(position[0]), C.float(position[1]), C.float(position[2]),
+		C.float(velocity[0]), C.float(velocity[1]), C.float(velocity[2]),
+		C.float(volume), C.int(0), C.uint(bus)))
+}
+
+func update3dAudio(soloud SoloudHandle) {
+	C.Soloud_update3dAudio(soloud)
+}
+
+func set3dSoundSpeed(soloud SoloudHandle, speed float32) {
+	C.Soloud_set3dSoundSpeed(soloud, C.float(speed))
+}
+
+func set3dListenerParameters(soloud SoloudHandle, position, at, up, velocity [3]float32) {
+	C.Soloud_set3dListenerParametersEx(soloud,
+		C.float(position[0]), C.float(position[1]), C.float(position[2]),
+		C.float(at[0]), C.float(at[1]), C.float(at[2]),
+		C.float(up[0]), C.float(up[1]), C.float(up[2]),
+		C.float(velocity[0]), C.float(velocity[1]), C.float(velocity[2]))
+}
+
+func set3dSourceParameters(soloud SoloudHandle, handle VoiceHandle, position, velocity [3]float32) {
+	C.Soloud_set3dSourceParametersEx(soloud, C.uint(handle),
+		C.float(position[0]), C.float(position[1]), C.float(position[2]),
+		C.float(velocity[0]), C.float(velocity[1]), C.float(velocity[2]))
+}
+
+func set3dSourceMinMaxDistance(soloud SoloudHandle, handle VoiceHandle, minDistance, maxDistance float32) {
+	C.Soloud_set3dSourceMinMaxDistance(soloud, C.uint(handle), C.float(minDistance), C.float(maxDistance))
+}
+
+func set3dSourceAttenuation(soloud SoloudHandle, handle VoiceHandle, model uint32, rolloff float32) {
+	C.Soloud_set3dSourceAttenuation(soloud, C.uint(handle), C.uint(model), C.float(rolloff))
+}
+
+func set3dSourceDopplerFactor(soloud SoloudHandle, handle VoiceHandle, factor float32) {
+	C.Soloud_set3dSourceDopplerFactor(soloud, C.uint(handle), C.float(factor))
+}
+
+func busCreate() SoloudBus {
+	return C.Bus_create()
+}
+
+func busDestroy(bus SoloudBus) {
+	C.Bus_destroy(bus)
+}
+
+// busPlay starts the bus playing into the parent bus, or directly into the
+// output when the parent is nil, the handle is used to control the bus voice
+func busPlay(soloud SoloudHandle, bus SoloudBus, parent SoloudBus) VoiceHandle {
+	if parent == nil {
+		return VoiceHandle(C.Soloud_play(soloud, (*C.AudioSource)(bus)))
+	}
+	return VoiceHandle(C.Bus_play(parent, (*C.AudioSource)(bus)))
+}
+
+func busSetFilter(bus SoloudBus, slot uint32, filter SoloudFilter) {
+	C.Bus_setFilter(bus, C.uint(slot), filter)
+}
+
+func setProtectVoice(soloud SoloudHandle, handle VoiceHandle, protect bool) {
+	if protect {
+		C.Soloud_setProtectVoice(soloud, C.uint(handle), C.int(1))
+	} else {
+		C.Soloud_setProtectVoice(soloud, C.uint(handle), C.int(0))
+	}
+}
+
+func setFilterParameter(soloud SoloudHandle, handle VoiceHandle, slot, attribute uint32, value float32) {
+	C.Soloud_setFilterParameter(soloud, C.uint(handle), C.uint(slot), C.uint(attribute), C.float(value))
+}
+
+func lowPassFilterCreate(filterType int, frequency, resonance float32) SoloudFilter {
+	f := C.BiquadResonantFilter_create()
+	C.BiquadResonantFilter_setParams(f, C.int(filterType), C.float(frequency), C.float(resonance))
+	return (*C.Filter)(f)
+}
+
+func echoFilterCreate(delay, decay, filter float32) SoloudFilter {
+	f := C.EchoFilter_create()
+	C.EchoFilter_setParamsEx(f, C.float(delay), C.float(decay), C.float(filter))
+	return (*C.Filter)(f)
+}
+
+func reverbFilterCreate(roomSize, damp, width float32) SoloudFilter {
+	f := C.FreeverbFilter_create()
+	C.FreeverbFilter_setParams(f, C.float(0), C.float(roomSize), C.float(damp), C.float(width))
+	return (*C.Filter)(f)
+}
+
+func filterDestroy(effect EffectType, filter SoloudFilter) {
+	switch effect {
+	case EffectLowPass, EffectHighPass:
+		C.BiquadResonantFilter_destroy((*C.BiquadResonantFilter)(filter))
+	case EffectEcho:
+		C.EchoFilter_destroy((*C.EchoFilter)(filter))
+	case EffectReverb:
+		C.FreeverbFilter_destroy((*C.FreeverbFilter)(filter))
+	}
+}
+
+const (
+	filterWetAttribute = uint32(C.BIQUADRESONANTFILTER_WET)
+	biquadLowPass      = int(C.BIQUADRESONANTFILTER_LOWPASS)
+	biquadHighPass     = int(C.BIQUADRESONANTFILTER_HIGHPASS)
+)
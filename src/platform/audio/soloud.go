@@ -13,11 +13,17 @@ import (
 	"runtime"
 
 	"kaijuengine.com/engine/assets"
+	"kaijuengine.com/engine/cameras"
 	"kaijuengine.com/klib"
+	"kaijuengine.com/platform/audio/audio_pcm"
 )
 
+// AudioClip is a loaded sound or music file. A clip is either fully decoded
+// into memory or kept compressed in memory and decoded while it is playing.
 type AudioClip struct {
 	wav     SoloudWav
+	stream  SoloudWavStream
+	src     SoloudSource
 	key     string
 	handles []VoiceHandle
 	isSFX   bool
@@ -31,8 +37,14 @@ type Audio struct {
 	bgmUnmutedVolume float32
 	sfx              map[string]*AudioClip
 	bgm              map[string]*AudioClip
+	master           *Bus
+	listener         Listener
+	listenerCamera   func() cameras.Camera
 }
 
+// sourceVolume tells SoLoud to use the volume of the clip for a new voice
+const sourceVolume = -1
+
 func New() (*Audio, error) {
 	audio := &Audio{
 		sfx:    make(map[string]*AudioClip),
@@ -49,6 +61,7 @@ func New() (*Audio, error) {
 	}
 	audio.SetSoundVolume(0.5)
 	audio.SetMusicVolume(0.5)
+	audio.master = newBus(audio, MasterBusName, nil)
 	runtime.AddCleanup(audio, func(soloud SoloudHandle) {
 		deinitialize(soloud)
 		destroy(soloud)
@@ -56,6 +69,59 @@ func New() (*Audio, error) {
 	return audio, nil
 }
 
+func (a *Audio) isActive() bool { return a != nil && a.soloud != nil }
+
+// MasterBus is the root of the bus hierarchy, all busses are mixed into it
+func (a *Audio) MasterBus() *Bus { return a.master }
+
+// CreateBus creates a new bus that is mixed into the parent bus, a nil parent
+// will mix the new bus into the master bus
+func (a *Audio) CreateBus(name string, parent *Bus) *Bus {
+	if parent == nil {
+		parent = a.master
+	}
+	return newBus(a, name, parent)
+}
+
+// FindBus searches the entire bus hierarchy for a bus with the given name
+func (a *Audio) FindBus(name string) (*Bus, bool) {
+	if a.master == nil {
+		return nil, false
+	}
+	return a.master.Find(name)
+}
+
+// DestroyBus destroys the bus and all of the busses below it, the master bus
+// can not be destroyed
+func (a *Audio) DestroyBus(bus *Bus) {
+	if bus == nil || bus == a.master {
+		return
+	}
+	bus.destroy()
+}
+
+func (a *Audio) busHandle(bus *Bus) VoiceHandle {
+	if bus == nil {
+		bus = a.master
+	}
+	if bus == nil {
+		return 0
+	}
+	return bus.handle
+}
+
+func (a *Audio) trackHandle(clip *AudioClip, handle VoiceHandle) {
+	if clip.isSFX {
+		if sfx, ok := a.sfx[clip.key]; ok {
+			sfx.handles = append(sfx.handles, handle)
+		}
+	} else {
+		if bgm, ok := a.bgm[clip.key]; ok {
+			bgm.handles = append(bgm.handles, handle)
+		}
+	}
+}
+
 func (a *Audio) MusicById(id string) (*AudioClip, bool) {
 	c, ok := a.bgm[id]
 	return c, ok
@@ -121,7 +187,56 @@ func (a *Audio) LoadMusic(adb assets.Database, key string) (*AudioClip, error) {
 	}
 	clip := newClip(a, key, data)
 	a.bgm[clip.key] = clip
-	wavSetVolume(clip.wav, a.bgmVolume)
+	clip.setVolume(a.bgmVolume)
+	return clip, nil
+}
+
+// LoadCompressedMusic loads the music so that it is decoded while it plays
+// instead of being fully decoded into memory. The whole file is still read
+// from the asset database, but only a copy of the encoded data is kept, which
+// greatly reduces the memory used by long, compressed, music tracks. If the
+// music was already loaded, the loaded clip is returned.
+func (a *Audio) LoadCompressedMusic(adb assets.Database, key string) (*AudioClip, error) {
+	if key == "" {
+		return nil, errors.New("blank key requeseted to Audio.LoadCompressedMusic")
+	}
+	if !a.isActive() {
+		return nil, errors.New("the audio system has not been initialized")
+	}
+	if c, ok := a.bgm[key]; ok {
+		return c, nil
+	}
+	data, err := adb.Read(key)
+	if err != nil {
+		return nil, err
+	}
+	clip, err := newCompressedClip(a, key, data)
+	if err != nil {
+		return nil, err
+	}
+	a.bgm[clip.key] = clip
+	clip.setVolume(a.bgmVolume)
+	return clip, nil
+}
+
+// LoadMusicData loads music from data that is already in memory, like the
+// audio track of a video, rather than from the asset database
+func (a *Audio) LoadMusicData(key string, data []byte) (*AudioClip, error) {
+	if key == "" {
+		return nil, errors.New("blank key requested to Audio.LoadMusicData")
+	}
+	if !a.isActive() {
+		return nil, errors.New("the audio system has not been initialized")
+	}
+	if c, ok := a.bgm[key]; ok {
+		return c, nil
+	}
+	if len(data) == 0 {
+		return nil, errors.New("the music data to load is empty")
+	}
+	clip := newClip(a, key, data)
+	a.bgm[clip.key] = clip
+	clip.setVolume(a.bgmVolume)
 	return clip, nil
 }
 
@@ -139,21 +254,20 @@ func (a *Audio) LoadSound(adb assets.Database, key string) (*AudioClip, error) {
 	clip := newClip(a, key, data)
 	clip.isSFX = true
 	a.sfx[clip.key] = clip
-	wavSetVolume(clip.wav, a.sfxVolume)
+	clip.setVolume(a.sfxVolume)
 	return clip, nil
 }
 
+// Play plays the clip on the master bus, see [Audio.PlayOnBus]
 func (a *Audio) Play(clip *AudioClip) VoiceHandle {
-	handle := play(a.soloud, clip.wav)
-	if clip.isSFX {
-		if sfx, ok := a.sfx[clip.key]; ok {
-			sfx.handles = append(sfx.handles, handle)
-		}
-	} else {
-		if bgm, ok := a.bgm[clip.key]; ok {
-			bgm.handles = append(bgm.handles, handle)
-		}
-	}
+	return a.PlayOnBus(clip, nil)
+}
+
+// PlayOnBus plays the clip so that it is mixed through the given bus, a nil
+// bus will play the clip on the master bus
+func (a *Audio) PlayOnBus(clip *AudioClip, bus *Bus) VoiceHandle {
+	handle := playEx(a.soloud, clip.src, sourceVolume, a.busHandle(bus))
+	a.trackHandle(clip, handle)
 	return handle
 }
 
@@ -162,7 +276,7 @@ func (a *Audio) Stop(handle VoiceHandle) {
 }
 
 func (a *Audio) StopSource(clip *AudioClip) {
-	stopAudioSource(a.soloud, clip.wav)
+	stopAudioSource(a.soloud, clip.src)
 	clip.handles = clip.handles[:0]
 }
 
@@ -174,16 +288,30 @@ func (a *Audio) Seek(handle VoiceHandle, seconds float64) bool {
 	return seek(a.soloud, handle, seconds) != 0
 }
 
+// SetPaused pauses or resumes the voice, a paused voice keeps its position
+func (a *Audio) SetPaused(handle VoiceHandle, paused bool) {
+	setPause(a.soloud, handle, paused)
+}
+
+func (a *Audio) IsPaused(handle VoiceHandle) bool {
+	return getPause(a.soloud, handle)
+}
+
+// StreamPosition returns how far (in seconds) the voice is into its clip
+func (a *Audio) StreamPosition(handle VoiceHandle) float64 {
+	return getStreamPosition(a.soloud, handle)
+}
+
 func (a *Audio) PlaySound(key string) (*AudioClip, VoiceHandle) {
 	if sfx, ok := a.sfx[key]; ok {
-		return sfx, play(a.soloud, sfx.wav)
+		return sfx, playEx(a.soloud, sfx.src, sourceVolume, a.busHandle(nil))
 	}
 	return nil, 0
 }
 
 func (a *Audio) PlayMusic(key string) (*AudioClip, VoiceHandle) {
 	if bgm, ok := a.bgm[key]; ok {
-		handle := play(a.soloud, bgm.wav)
+		handle := playEx(a.soloud, bgm.src, sourceVolume, a.busHandle(nil))
 		setLooping(a.soloud, handle, true)
 		bgm.handles = append(bgm.handles, handle)
 		return bgm, handle
@@ -198,14 +326,14 @@ func (a *Audio) SetLooping(handle VoiceHandle, looping bool) {
 func (a *Audio) SetSoundVolume(volume float32) {
 	a.sfxVolume = klib.Clamp(volume, 0.0, 1.0)
 	for k := range a.sfx {
-		wavSetVolume(a.sfx[k].wav, volume)
+		a.sfx[k].setVolume(volume)
 	}
 }
 
 func (a *Audio) SetMusicVolume(volume float32) {
 	a.bgmVolume = klib.Clamp(volume, 0.0, 1.0)
 	for _, v := range a.bgm {
-		wavSetVolume(v.wav, volume)
+		v.setVolume(volume)
 		for i := range v.handles {
 			setVolume(a.soloud, v.handles[i], volume)
 		}
@@ -213,9 +341,40 @@ func (a *Audio) SetMusicVolume(volume float32) {
 }
 
 func (c *AudioClip) Length() float64 {
+	if c.stream != nil {
+		return streamLength(c.stream)
+	}
 	return clipLength(c.wav)
 }
 
+// IsCompressed returns true if the clip is decoded while it plays
+func (c *AudioClip) IsCompressed() bool { return c.stream != nil }
+
+func (c *AudioClip) setVolume(volume float32) {
+	if c.stream != nil {
+		streamSetVolume(c.stream, volume)
+	} else {
+		wavSetVolume(c.wav, volume)
+	}
+}
+
+// SetLoopPoint sets the time (in seconds) that a looping voice of this clip
+// will jump back to when it reaches the end. Loop points that were set when
+// the clip was imported are applied when the clip is loaded.
+func (c *AudioClip) SetLoopPoint(seconds float64) {
+	if c.stream != nil {
+		streamSetLoopPoint(c.stream, seconds)
+	} else {
+		wavSetLoopPoint(c.wav, seconds)
+	}
+}
+
+func (c *AudioClip) applyImportedLoopPoint(data []byte) {
+	if loop, ok := audio_pcm.ReadLoopStart(data); ok {
+		c.SetLoopPoint(loop)
+	}
+}
+
 func newClip(a *Audio, key string, data []byte) *AudioClip {
 	// TODO:  This should use the asset database to load the wav rather than
 	// the file path to the audio file
@@ -223,7 +382,9 @@ func newClip(a *Audio, key string, data []byte) *AudioClip {
 		key: key,
 		wav: wavCreate(),
 	}
+	clip.src = wavSource(clip.wav)
 	wavLoadMem(clip.wav, data)
+	clip.applyImportedLoopPoint(data)
 	type ClipFreeState struct {
 		audio *Audio // Hold the audio pointer so the system isn't cleaned up before wav
 		wav   SoloudWav
@@ -233,3 +394,26 @@ func newClip(a *Audio, key string, data []byte) *AudioClip {
 	}, ClipFreeState{a, clip.wav})
 	return clip
 }
+
+func newCompressedClip(a *Audio, key string, data []byte) (*AudioClip, error) {
+	if len(data) == 0 {
+		return nil, errors.New("the compressed music data is empty")
+	}
+	clip := &AudioClip{
+		key:    key,
+		stream: streamCreate(),
+	}
+	clip.src = streamSource(clip.stream)
+	type StreamFreeState struct {
+		audio  *Audio // Hold the audio pointer so the system isn't cleaned up before stream
+		stream SoloudWavStream
+	}
+	runtime.AddCleanup(clip, func(s StreamFreeState) {
+		streamDestroy(s.stream)
+	}, StreamFreeState{a, clip.stream})
+	if err := streamLoadMem(clip.stream, data); err != nil {
+		return nil, err
+	}
+	clip.applyImportedLoopPoint(data)
+	return clip, nil
+}
@@ -0,0 +1,173 @@
+/******************************************************************************/
+/* spatial.go                                                                 */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio
+
+import (
+	"math"
+
+	"kaijuengine.com/engine/cameras"
+	"kaijuengine.com/matrix"
+)
+
+// AttenuationModel is how the volume of a 3D voice falls off over distance,
+// the values match the attenuation models of SoLoud
+type AttenuationModel uint32
+
+const (
+	AttenuationNone AttenuationModel = iota
+	AttenuationInverseDistance
+	AttenuationLinearDistance
+	AttenuationExponentialDistance
+)
+
+// DefaultSoundSpeed is the speed of sound (in units per second) used for the
+// doppler effect, assuming that 1 unit is 1 meter
+const DefaultSoundSpeed = 343
+
+// Spatial holds the settings for how a 3D voice is heard by the listener
+type Spatial struct {
+	Attenuation AttenuationModel
+	Rolloff     float32
+	MinDistance float32
+	MaxDistance float32
+	// DopplerFactor scales the pitch shift caused by the relative velocity of
+	// the voice and the listener, 0 disables the doppler effect
+	DopplerFactor float32
+}
+
+func DefaultSpatial() Spatial {
+	return Spatial{
+		Attenuation:   AttenuationInverseDistance,
+		Rolloff:       1,
+		MinDistance:   1,
+		MaxDistance:   100,
+		DopplerFactor: 1,
+	}
+}
+
+// Gain returns the volume multiplier for a voice that is the given distance
+// away from the listener, this is the same calculation that SoLoud uses
+func (s Spatial) Gain(distance float32) float32 {
+	minDistance := max(s.MinDistance, math.SmallestNonzeroFloat32)
+	maxDistance := max(s.MaxDistance, minDistance)
+	d := min(max(distance, minDistance), maxDistance)
+	switch s.Attenuation {
+	case AttenuationInverseDistance:
+		return minDistance / (minDistance + s.Rolloff*(d-minDistance))
+	case AttenuationLinearDistance:
+		if maxDistance == minDistance {
+			return 1
+		}
+		return max(0, 1-s.Rolloff*(d-minDistance)/(maxDistance-minDistance))
+	case AttenuationExponentialDistance:
+		return float32(math.Pow(float64(d/minDistance), float64(-s.Rolloff)))
+	default:
+		return 1
+	}
+}
+
+// Listener is the point in the world that 3D voices are heard from
+type Listener struct {
+	Position matrix.Vec3
+	Forward  matrix.Vec3
+	Up       matrix.Vec3
+	Velocity matrix.Vec3
+}
+
+// ListenerFromCamera creates a listener at the camera facing the way that the
+// camera is facing. The velocity is found using the previous listener, this
+// is needed for the doppler effect.
+func ListenerFromCamera(camera cameras.Camera, previous Listener, deltaTime float64) Listener {
+	l := Listener{
+		Position: camera.Position(),
+		Forward:  camera.Forward(),
+		Up:       camera.Up(),
+	}
+	if deltaTime > 0 {
+		l.Velocity = l.Position.Subtract(previous.Position).Scale(matrix.Float(1.0 / deltaTime))
+	}
+	return l
+}
+
+// FollowCamera will update the listener to match the camera every time that
+// [Audio.Update3D] is called, pass nil to stop following a camera
+func (a *Audio) FollowCamera(camera cameras.Camera) {
+	if camera == nil {
+		a.listenerCamera = nil
+		return
+	}
+	a.listenerCamera = func() cameras.Camera { return camera }
+}
+
+// FollowCameraFunc is like [Audio.FollowCamera] but the camera is resolved on
+// every call to [Audio.Update3D], so the listener follows whichever camera
+// the function returns at that time. Pass nil to stop following a camera.
+func (a *Audio) FollowCameraFunc(camera func() cameras.Camera) {
+	a.listenerCamera = camera
+}
+
+func (a *Audio) Listener() Listener { return a.listener }
+
+// SetListener manually sets the listener, this should be used when the
+// listener isn't following a camera
+func (a *Audio) SetListener(listener Listener) {
+	a.listener = listener
+	if a.isActive() {
+		set3dListenerParameters(a.soloud, vec3f(listener.Position),
+			vec3f(listener.Forward), vec3f(listener.Up),
+			vec3f(listener.Velocity))
+	}
+}
+
+// SetSoundSpeed sets the speed of sound that is used for the doppler effect
+func (a *Audio) SetSoundSpeed(speed float32) {
+	if a.isActive() {
+		set3dSoundSpeed(a.soloud, speed)
+	}
+}
+
+// Update3D moves the listener to the followed camera and applies the changes
+// to all of the 3D voices, this is called by the host every frame
+func (a *Audio) Update3D(deltaTime float64) {
+	if a.listenerCamera != nil {
+		if camera := a.listenerCamera(); camera != nil {
+			a.SetListener(ListenerFromCamera(camera, a.listener, deltaTime))
+		}
+	}
+	if a.isActive() {
+		update3dAudio(a.soloud)
+	}
+}
+
+// Play3D plays the clip as a voice that is positioned in the world, the
+// volume of the voice is attenuated by its distance from the listener and it
+// is panned based on which side of the listener it is on. A nil bus will play
+// the voice on the master bus.
+func (a *Audio) Play3D(clip *AudioClip, position, velocity matrix.Vec3, spatial Spatial, bus *Bus) VoiceHandle {
+	if !a.isActive() {
+		return 0
+	}
+	handle := play3dEx(a.soloud, clip.src, vec3f(position),
+		vec3f(velocity), sourceVolume, a.busHandle(bus))
+	set3dSourceAttenuation(a.soloud, handle, uint32(spatial.Attenuation), spatial.Rolloff)
+	set3dSourceMinMaxDistance(a.soloud, handle, spatial.MinDistance, spatial.MaxDistance)
+	set3dSourceDopplerFactor(a.soloud, handle, spatial.DopplerFactor)
+	a.trackHandle(clip, handle)
+	return handle
+}
+
+// SetVoicePosition moves a voice that was started with [Audio.Play3D], the
+// change is heard on the next call to [Audio.Update3D]
+func (a *Audio) SetVoicePosition(handle VoiceHandle, position, velocity matrix.Vec3) {
+	if a.isActive() {
+		set3dSourceParameters(a.soloud, handle, vec3f(position), vec3f(velocity))
+	}
+}
+
+func vec3f(v matrix.Vec3) [3]float32 {
+	return [3]float32{float32(v.X()), float32(v.Y()), float32(v.Z())}
+}
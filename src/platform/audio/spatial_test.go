@@ -0,0 +1,52 @@
+/******************************************************************************/
+/* spatial_test.go                                                            */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"kaijuengine.com/engine/cameras"
+	"kaijuengine.com/matrix"
+)
+
+func TestSpatialGain(t *testing.T) {
+	tests := []struct {
+		model    AttenuationModel
+		distance float32
+		want     float32
+	}{
+		{AttenuationNone, 50, 1},
+		{AttenuationInverseDistance, 0.5, 1},
+		{AttenuationInverseDistance, 2, 0.5},
+		{AttenuationInverseDistance, 4, 0.25},
+		{AttenuationLinearDistance, 1, 1},
+		{AttenuationLinearDistance, 5.5, 0.5},
+		{AttenuationLinearDistance, 50, 0},
+		{AttenuationExponentialDistance, 2, 0.5},
+		{AttenuationExponentialDistance, 20, 0.1},
+	}
+	for _, tt := range tests {
+		s := Spatial{Attenuation: tt.model, Rolloff: 1, MinDistance: 1, MaxDistance: 10}
+		if got := s.Gain(tt.distance); math.Abs(float64(got-tt.want)) > 1e-5 {
+			t.Errorf("model %d at %f: expected %f, got %f", tt.model, tt.distance, tt.want, got)
+		}
+	}
+}
+
+func TestListenerFromCamera(t *testing.T) {
+	cam := cameras.NewStandardCamera(100, 100, 100, 100, matrix.NewVec3(0, 0, 0))
+	l := ListenerFromCamera(cam, Listener{}, 0.5)
+	cam.SetPosition(matrix.NewVec3(1, 0, 0))
+	l = ListenerFromCamera(cam, l, 0.5)
+	if !matrix.Vec3Approx(l.Velocity, matrix.NewVec3(2, 0, 0)) {
+		t.Errorf("expected a velocity of (2, 0, 0), got %v", l.Velocity)
+	}
+	if !matrix.Vec3Approx(l.Forward, cam.Forward()) {
+		t.Errorf("expected the listener to face the camera forward")
+	}
+}
@@ -124,6 +124,18 @@ static int go_func_wrapper(lua_State* L) {
     return results;
 }
 
+static int m_luaL_ref(lua_State* L) {
+	return luaL_ref(L, LUA_REGISTRYINDEX);
+}
+
+static void m_luaL_unref(lua_State* L, int ref) {
+	luaL_unref(L, LUA_REGISTRYINDEX, ref);
+}
+
+static void m_lua_pushref(lua_State* L, int ref) {
+	lua_rawgeti(L, LUA_REGISTRYINDEX, ref);
+}
+
 static void push_go_function(lua_State* L, int id) {
     lua_pushinteger(L, id);
     lua_pushcclosure(L, go_func_wrapper, 1);
@@ -136,6 +148,7 @@ import (
 	"log/slog"
 	"reflect"
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -151,25 +164,36 @@ var (
 type pinnedPointer struct {
 	pinner  *runtime.Pinner
 	pointer any
+	target  unsafe.Pointer
 }
 
 type State struct {
-	state      *C.lua_State
-	pinned     map[unsafe.Pointer]pinnedPointer
+	state  *C.lua_State
+	pinned map[unsafe.Pointer]pinnedPointer
+	// handles are the light userdata given to lua for each Go pointer
+	handles    map[unsafe.Pointer]unsafe.Pointer
 	funcs      map[int]func(state *State) int
 	nextFuncId int
 	closed     bool
-}
-
-func pinPointer(ptr any) pinnedPointer {
+	// released are the refs given to UnrefLater, they are released on the
+	// next call into lua as the state can't be touched from other goroutines
+	released      []int
+	releasedMutex sync.Mutex
+}
+
+// pinPointer creates the handle that lua holds in place of the Go pointer.
+// cgo doesn't allow C to keep Go memory that has Go pointers inside of it,
+// which most structs do, so lua is given a pinned handle that has none and
+// the pointer is kept alive by the pinnedPointer instead
+func pinPointer(ptr any, target unsafe.Pointer) (unsafe.Pointer, pinnedPointer) {
+	handle := new(uintptr)
 	pp := pinnedPointer{
 		pinner:  new(runtime.Pinner),
 		pointer: ptr,
+		target:  target,
 	}
-	pp.pinner.Pin(ptr)
-	// I don't believe I need to pin any innter-pointers as only the top
-	// level pointer is accessed when coming into Go from lua
-	return pp
+	pp.pinner.Pin(handle)
+	return unsafe.Pointer(handle), pp
 }
 
 func New() State {
@@ -177,6 +201,7 @@ func New() State {
 		state:      C.luaL_newstate(),
 		nextFuncId: 1,
 		pinned:     make(map[unsafe.Pointer]pinnedPointer),
+		handles:    make(map[unsafe.Pointer]unsafe.Pointer),
 		funcs:      make(map[int]func(state *State) int),
 	}
 }
@@ -193,6 +218,7 @@ func (l *State) Close() {
 		pp.pinner.Unpin()
 		delete(l.pinned, ptr)
 	}
+	clear(l.handles)
 	clear(l.funcs)
 }
 
@@ -381,10 +407,14 @@ func (l *State) PushString(value string) {
 
 func (l *State) PushUserData(value reflect.Value) {
 	p := unsafe.Pointer(value.Pointer())
-	if _, ok := l.pinned[p]; !ok {
-		l.pinned[p] = pinPointer(value.Interface())
+	handle, ok := l.handles[p]
+	if !ok {
+		var pp pinnedPointer
+		handle, pp = pinPointer(value.Interface(), p)
+		l.pinned[handle] = pp
+		l.handles[p] = handle
 	}
-	C.lua_pushlightuserdata(l.state, p)
+	C.lua_pushlightuserdata(l.state, handle)
 }
 
 func (l *State) ToUserData(idx int) any {
@@ -404,8 +434,46 @@ func (l *State) RemovePinnedPointer(idx int) {
 	}
 	pp.pinner.Unpin()
 	delete(l.pinned, ptr)
+	delete(l.handles, pp.target)
+}
+
+// Ref pops the value on the top of the stack and keeps it in the registry
+// so it outlives the call it was given in, the value is pushed back with
+// PushRef until it is released with Unref
+func (l *State) Ref() int {
+	return int(C.m_luaL_ref(l.state))
 }
 
+func (l *State) Unref(ref int) {
+	if !l.closed {
+		C.m_luaL_unref(l.state, C.int(ref))
+	}
+}
+
+// UnrefLater queues the ref to be released the next time a function is
+// called, unlike Unref it is safe to call from any goroutine, such as from a
+// cleanup added with runtime.AddCleanup
+func (l *State) UnrefLater(ref int) {
+	l.releasedMutex.Lock()
+	defer l.releasedMutex.Unlock()
+	l.released = append(l.released, ref)
+}
+
+func (l *State) releaseRefs() {
+	l.releasedMutex.Lock()
+	defer l.releasedMutex.Unlock()
+	for _, ref := range l.released {
+		l.Unref(ref)
+	}
+	l.released = l.released[:0]
+}
+
+func (l *State) PushRef(ref int) {
+	C.m_lua_pushref(l.state, C.int(ref))
+}
+
+func (l *State) IsClosed() bool { return l == nil || l.closed }
+
 func (l *State) PushGoFunction(fn func(state *State) int) {
 	id := l.nextFuncId
 	l.nextFuncId++
@@ -482,6 +550,7 @@ func (l *State) ToString(idx int) string {
 }
 
 func (l *State) Call(args, returns int) error {
+	l.releaseRefs()
 	if C.m_lua_pcall(l.state, C.int(args), C.int(returns)) != errorOK {
 		gString := C.GoString(C.m_lua_tostring(l.state, C.int(-1)))
 		l.Pop(1)
@@ -55,6 +55,8 @@ func reflectCommentDocCommonType(t reflect.Type) string {
 		reflect.Int64, reflect.Uint8, reflect.Uint16, reflect.Uint32,
 		reflect.Uint64, reflect.Float32, reflect.Float64:
 		return "number"
+	case reflect.Func:
+		return "function"
 	case reflect.Pointer:
 		//if t.Elem().Kind() == reflect.Pointer {
 		return "Pointer"
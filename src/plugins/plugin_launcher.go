@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"runtime"
 	"strings"
 
 	"kaijuengine.com/engine/assets"
@@ -218,6 +219,18 @@ func wrapperTypeName(t reflect.Type) string {
 	return t.Name()
 }
 
+// pushLuaWrapper pushes the table of the reflected type, types that are not
+// in the plugin registry have no table and can't be given to Lua
+func pushLuaWrapper(state *lua.State, t reflect.Type) error {
+	name := wrapperTypeName(t)
+	state.Global(name)
+	if !state.IsTable(-1) {
+		state.Pop(1)
+		return fmt.Errorf("%s is not registered for plugins", name)
+	}
+	return nil
+}
+
 func pushReflectValue(state *lua.State, v reflect.Value) error {
 	if !v.IsValid() {
 		state.PushNil()
@@ -236,7 +249,9 @@ func pushReflectValue(state *lua.State, v reflect.Value) error {
 			return nil
 		}
 		if hasLuaWrapper(v.Type()) {
-			state.Global(wrapperTypeName(v.Type()))
+			if err := pushLuaWrapper(state, v.Type()); err != nil {
+				return err
+			}
 			state.Field(-1, "New")
 			state.PushUserData(v)
 			if err := state.Call(1, 1); err != nil {
@@ -249,7 +264,9 @@ func pushReflectValue(state *lua.State, v reflect.Value) error {
 	if hasLuaWrapper(v.Type()) {
 		ptr := reflect.New(v.Type())
 		ptr.Elem().Set(v)
-		state.Global(wrapperTypeName(v.Type()))
+		if err := pushLuaWrapper(state, v.Type()); err != nil {
+			return err
+		}
 		state.Field(-1, "New")
 		state.PushUserData(ptr)
 		if err := state.Call(1, 1); err != nil {
@@ -417,11 +434,64 @@ func luaValueToReflect(state *lua.State, idx int, target reflect.Type) (reflect.
 			return reflect.Value{}, fmt.Errorf("expected table")
 		}
 		return reflect.Value{}, fmt.Errorf("slice arguments are not supported yet")
+	case reflect.Func:
+		if !state.IsFunction(idx) {
+			return reflect.Value{}, fmt.Errorf("expected function")
+		}
+		return luaFunctionToReflect(state, idx, target), nil
 	default:
 		return reflect.Value{}, fmt.Errorf("unsupported argument type %s", target)
 	}
 }
 
+// luaFunctionToReflect wraps a Lua function so it can be given to Go as a
+// callback, such as an event listener. The function is kept in the registry
+// until Go drops the callback, like when the listener is removed with
+// RemoveEventListener, it is then released on the next call into the VM
+func luaFunctionToReflect(state *lua.State, idx int, target reflect.Type) reflect.Value {
+	state.PushValue(idx)
+	ref := &luaCallbackRef{state.Ref()}
+	runtime.AddCleanup(ref, func(r int) { state.UnrefLater(r) }, ref.ref)
+	return reflect.MakeFunc(target, func(args []reflect.Value) []reflect.Value {
+		out := make([]reflect.Value, target.NumOut())
+		for i := range out {
+			out[i] = reflect.Zero(target.Out(i))
+		}
+		if state.IsClosed() {
+			return out
+		}
+		top := state.Top()
+		defer func() { state.Pop(state.Top() - top) }()
+		state.PushRef(ref.ref)
+		for i := range args {
+			if err := pushReflectValue(state, args[i]); err != nil {
+				slog.Error("failed to pass the argument to the lua callback",
+					"argument", i+1, "error", err)
+				return out
+			}
+		}
+		if err := state.Call(len(args), len(out)); err != nil {
+			slog.Error("lua callback failed", "error", err)
+			return out
+		}
+		for i := range out {
+			v, err := luaValueToReflect(state, top+1+i, target.Out(i))
+			if err != nil {
+				slog.Error("invalid lua callback return", "return", i+1, "error", err)
+				continue
+			}
+			out[i] = v
+		}
+		return out
+	})
+}
+
+// luaCallbackRef holds the registry ref of a Lua function that was given to
+// Go, the ref is released once the callback holding it is collected
+type luaCallbackRef struct {
+	ref int
+}
+
 func (vm *LuaVM) setupPrerequisites(adb assets.Database) error {
 	defer tracing.NewRegion("LuaVM.setupPrerequisites").End()
 	vm.runtime.PushGoFunction(func(state *lua.State) int { return 0 })
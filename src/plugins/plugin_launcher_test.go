@@ -12,7 +12,8 @@ import (
 )
 
 type luaBridgeThing struct {
-	Value int
+	Value    int
+	onChange func(*luaBridgeThing)
 }
 
 func (t *luaBridgeThing) SetValue(v int) {
@@ -40,6 +41,14 @@ func (t *luaBridgeThing) Number() int {
 	return 7
 }
 
+func (t *luaBridgeThing) Apply(fn func(int) int) int {
+	return fn(t.Value)
+}
+
+func (t *luaBridgeThing) OnChange(fn func(*luaBridgeThing)) {
+	t.onChange = fn
+}
+
 func testPluginDB() assets.Database {
 	return assets.NewMockDB(map[string][]byte{
 		filepath.Join(plugins, "debugger.lua"): []byte(`function breakpoint() end`),
@@ -165,3 +174,71 @@ thing:SetValue("not a number")
 		t.Fatalf("expected reflected argument error, got %v", err)
 	}
 }
+
+func TestLaunchPluginLuaCallbacks(t *testing.T) {
+	withTestRegistry(t)
+	entry := writePlugin(t, map[string]string{
+		"main.lua": `
+thing = luaBridgeThing.New()
+thing:SetValue(4)
+doubled = thing:Apply(function(v) return v * 2 end)
+changed = 0
+thing:OnChange(function(other) changed = other:Number() end)
+`,
+	})
+	vm, err := launchPlugin(testPluginDB(), entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Close()
+	vm.runtime.Global("doubled")
+	if got := vm.runtime.ToNumber(-1); got != 8 {
+		t.Errorf("expected the lua callback to return 8, got %v", got)
+	}
+	vm.runtime.Pop(1)
+	vm.runtime.Global("thing")
+	vm.runtime.Field(-1, goPtrField)
+	thing, ok := vm.runtime.ToUserData(-1).(*luaBridgeThing)
+	vm.runtime.Pop(2)
+	if !ok || thing.onChange == nil {
+		t.Fatal("expected the lua function to be stored as a Go callback")
+	}
+	thing.onChange(thing)
+	vm.runtime.Global("changed")
+	defer vm.runtime.Pop(1)
+	if got := vm.runtime.ToNumber(-1); got != 7 {
+		t.Errorf("expected the callback to run after the script, got %v", got)
+	}
+}
+
+func TestLaunchPluginDrawsOnCanvas(t *testing.T) {
+	withTestRegistry(t)
+	entry := writePlugin(t, map[string]string{
+		"main.lua": `
+local ctx = Context.New()
+ctx:Resize(8, 4)
+ctx:SetFillColor({1, 0, 0, 1})
+ctx:FillRect(0, 0, 4, 4)
+local g = ctx:CreateLinearGradient(4, 0, 8, 0)
+g:AddColorStop(0, {0, 0, 1, 1})
+g:AddColorStop(1, {0, 0, 1, 1})
+ctx:SetFillGradient(g)
+ctx:BeginPath()
+ctx:Arc(6, 2, 2, 0, math.pi * 2, false)
+ctx:Fill()
+local left = ctx:Pixel(1, 1)
+local right = ctx:Pixel(6, 2)
+result = left:R() == 1 and left:B() == 0 and right:B() == 1 and ctx:IsDirty()
+`,
+	})
+	vm, err := launchPlugin(testPluginDB(), entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Close()
+	vm.runtime.Global("result")
+	defer vm.runtime.Pop(1)
+	if !vm.runtime.IsBoolean(-1) || !vm.runtime.ToBoolean(-1) {
+		t.Fatal("expected lua to draw on the canvas")
+	}
+}
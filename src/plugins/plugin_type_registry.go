@@ -9,17 +9,26 @@ package plugins
 import (
 	"reflect"
 
+	"kaijuengine.com/engine/ui/canvas"
 	"kaijuengine.com/matrix"
 	"kaijuengine.com/platform/profiler/tracing"
 )
 
 var (
 	GamePluginRegistry = []reflect.Type{}
+	engineTypes        = []reflect.Type{}
 )
 
+// RegisterEngineType adds a type of the engine to the types that are given to
+// every plugin. Packages that this package can't import, because they import
+// the engine, register their types with this from an init function.
+func RegisterEngineType(t reflect.Type) {
+	engineTypes = append(engineTypes, t)
+}
+
 func reflectedTypes() []reflect.Type {
 	defer tracing.NewRegion("plugins.reflectedTypes").End()
-	return append([]reflect.Type{
+	types := append([]reflect.Type{
 		reflect.TypeFor[matrix.Vec2](),
 		reflect.TypeFor[matrix.Vec2i](),
 		reflect.TypeFor[matrix.Vec3](),
@@ -29,5 +38,10 @@ func reflectedTypes() []reflect.Type {
 		reflect.TypeFor[matrix.Quaternion](),
 		reflect.TypeFor[matrix.Mat3](),
 		reflect.TypeFor[matrix.Mat4](),
-	}, GamePluginRegistry...)
+		reflect.TypeFor[matrix.Color](),
+		reflect.TypeFor[canvas.Context](),
+		reflect.TypeFor[canvas.Gradient](),
+		reflect.TypeFor[canvas.Image](),
+	}, engineTypes...)
+	return append(types, GamePluginRegistry...)
 }
@@ -0,0 +1,52 @@
+/******************************************************************************/
+/* shader_data_vertex_animation.go                                            */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package shader_data_registry
+
+import (
+	"unsafe"
+
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering"
+)
+
+func init() {
+	register(func() rendering.DrawInstance {
+		return &ShaderDataVertexAnimation{
+			ShaderDataBase: rendering.NewShaderDataBase(),
+			Color:          matrix.ColorWhite(),
+			Animation:      matrix.NewVec4(0, 0, 0, 1),
+		}
+	}, fallback+"_vertex_animation")
+}
+
+// ShaderDataVertexAnimation is the instance data for meshes that are animated
+// using a baked vertex animation texture, which is the second texture of the
+// material.
+type ShaderDataVertexAnimation struct {
+	rendering.ShaderDataBase `visible:"false"`
+
+	Color matrix.Color
+	Flags StandardShaderDataFlags `visible:"false"`
+	// Animation is the frame rate (x), frame count (y), runtime that the
+	// animation was started at (z), and the playback speed (w)
+	Animation matrix.Vec4 `visible:"false"`
+	// BoundsMin is the minimum of the baked positions (xyz), along with the
+	// width of the vertex animation texture (w)
+	BoundsMin matrix.Vec4 `visible:"false"`
+	// BoundsSize is the size of the baked positions (xyz), along with the
+	// number of texture rows used for each frame (w)
+	BoundsSize matrix.Vec4 `visible:"false"`
+}
+
+func (ShaderDataVertexAnimation) Size() int {
+	return int(rendering.ShaderBaseDataSize +
+		unsafe.Sizeof(ShaderDataVertexAnimation{}.Color) +
+		unsafe.Sizeof(ShaderDataVertexAnimation{}.Flags) +
+		unsafe.Sizeof(ShaderDataVertexAnimation{}.Animation) +
+		unsafe.Sizeof(ShaderDataVertexAnimation{}.BoundsMin) +
+		unsafe.Sizeof(ShaderDataVertexAnimation{}.BoundsSize))
+}
@@ -0,0 +1,520 @@
+/******************************************************************************/
+/* bidi.go                                                                    */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package rendering
+
+import (
+	"golang.org/x/text/unicode/bidi"
+	"kaijuengine.com/matrix"
+)
+
+// TextDirection is the base (paragraph) direction of text
+type TextDirection int
+
+const (
+	TextDirectionLTR = TextDirection(iota)
+	TextDirectionRTL
+)
+
+// TextBidi selects how the base direction is applied to the text. Embedding
+// and isolation behave like normal as each text is its own paragraph.
+type TextBidi int
+
+const (
+	TextBidiNormal = TextBidi(iota)
+	// TextBidiOverride forces every character to the base direction
+	TextBidiOverride
+	// TextBidiPlaintext picks the direction of each paragraph from its first
+	// strong character rather than using the base direction
+	TextBidiPlaintext
+)
+
+const (
+	bidiMaxDepth   = 125
+	bidiNoOverride = ^bidi.Class(0)
+)
+
+var bidiMirrors = map[rune]rune{
+	'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{', '<': '>',
+	'>': '<', '«': '»', '»': '«', '‹': '›', '›': '‹', '⁅': '⁆', '⁆': '⁅',
+	'≤': '≥', '≥': '≤', '「': '」', '」': '「', '『': '』', '』': '『',
+	'（': '）', '）': '（', '［': '］', '］': '［', '｛': '｝', '｝': '｛',
+}
+
+type bidiStackEntry struct {
+	level    uint8
+	override bidi.Class
+	isolate  bool
+}
+
+func bidiClass(r rune) bidi.Class {
+	p, _ := bidi.LookupRune(r)
+	return p.Class()
+}
+
+func bidiLevelDirection(level uint8) bidi.Class {
+	if level%2 == 1 {
+		return bidi.R
+	}
+	return bidi.L
+}
+
+func (d TextDirection) level() uint8 {
+	if d == TextDirectionRTL {
+		return 1
+	}
+	return 0
+}
+
+// FirstStrongDirection returns the direction of the first strongly typed
+// character of the text, skipping over any isolated text
+func FirstStrongDirection(text []rune) (TextDirection, bool) {
+	isolates := 0
+	for _, r := range text {
+		switch bidiClass(r) {
+		case bidi.L:
+			if isolates == 0 {
+				return TextDirectionLTR, true
+			}
+		case bidi.R, bidi.AL:
+			if isolates == 0 {
+				return TextDirectionRTL, true
+			}
+		case bidi.LRI, bidi.RLI, bidi.FSI:
+			isolates++
+		case bidi.PDI:
+			isolates = max(0, isolates-1)
+		case bidi.B:
+			return TextDirectionLTR, false
+		}
+	}
+	return TextDirectionLTR, false
+}
+
+// BidiLevels runs the Unicode Bidirectional Algorithm (UAX #9) on a single
+// paragraph and returns the resolved embedding level of each character.
+// Bracket pairs (N0) are resolved as regular neutrals and isolating run
+// sequences are approximated by their level runs.
+func BidiLevels(text []rune, direction TextDirection, mode TextBidi) []uint8 {
+	base := direction.level()
+	if mode == TextBidiPlaintext {
+		if dir, ok := FirstStrongDirection(text); ok {
+			base = dir.level()
+		}
+	}
+	classes := make([]bidi.Class, len(text))
+	for i, r := range text {
+		classes[i] = bidiClass(r)
+	}
+	levels := make([]uint8, len(text))
+	bidiExplicitLevels(text, classes, levels, base, mode == TextBidiOverride)
+	bidiResolveRuns(classes, levels, base)
+	return levels
+}
+
+// bidiExplicitLevels applies the X1-X9 rules, removed characters (X9) are
+// turned into BN and are given the level of the character before them
+func bidiExplicitLevels(text []rune, classes []bidi.Class, levels []uint8, base uint8, override bool) {
+	stack := make([]bidiStackEntry, 1, 8)
+	stack[0] = bidiStackEntry{level: base, override: bidiNoOverride}
+	if override {
+		stack[0].override = bidiLevelDirection(base)
+	}
+	overflowIsolates, overflowEmbeddings, validIsolates := 0, 0, 0
+	nextLevel := func(odd bool) uint8 {
+		l := stack[len(stack)-1].level + 1
+		if (l%2 == 1) != odd {
+			l++
+		}
+		return l
+	}
+	for i, c := range classes {
+		top := stack[len(stack)-1]
+		switch c {
+		case bidi.RLE, bidi.LRE, bidi.RLO, bidi.LRO:
+			l := nextLevel(c == bidi.RLE || c == bidi.RLO)
+			if l <= bidiMaxDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				e := bidiStackEntry{level: l, override: bidiNoOverride}
+				if c == bidi.RLO {
+					e.override = bidi.R
+				} else if c == bidi.LRO {
+					e.override = bidi.L
+				}
+				stack = append(stack, e)
+			} else if overflowIsolates == 0 {
+				overflowEmbeddings++
+			}
+			levels[i] = top.level
+			classes[i] = bidi.BN
+		case bidi.RLI, bidi.LRI, bidi.FSI:
+			levels[i] = top.level
+			if top.override != bidiNoOverride {
+				classes[i] = top.override
+			}
+			odd := c == bidi.RLI
+			if c == bidi.FSI {
+				dir, _ := FirstStrongDirection(bidiIsolatedText(text, i+1))
+				odd = dir == TextDirectionRTL
+			}
+			l := nextLevel(odd)
+			if l <= bidiMaxDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				validIsolates++
+				stack = append(stack, bidiStackEntry{level: l, override: bidiNoOverride, isolate: true})
+			} else {
+				overflowIsolates++
+			}
+			if classes[i] != bidi.L && classes[i] != bidi.R {
+				classes[i] = bidi.ON
+			}
+		case bidi.PDI:
+			if overflowIsolates > 0 {
+				overflowIsolates--
+			} else if validIsolates > 0 {
+				overflowEmbeddings = 0
+				for !stack[len(stack)-1].isolate {
+					stack = stack[:len(stack)-1]
+				}
+				stack = stack[:len(stack)-1]
+				validIsolates--
+			}
+			top = stack[len(stack)-1]
+			levels[i] = top.level
+			if top.override != bidiNoOverride {
+				classes[i] = top.override
+			} else {
+				classes[i] = bidi.ON
+			}
+		case bidi.PDF:
+			if overflowIsolates == 0 {
+				if overflowEmbeddings > 0 {
+					overflowEmbeddings--
+				} else if !top.isolate && len(stack) > 1 {
+					stack = stack[:len(stack)-1]
+				}
+			}
+			levels[i] = top.level
+			classes[i] = bidi.BN
+		case bidi.B:
+			levels[i] = base
+		case bidi.BN:
+			levels[i] = top.level
+		default:
+			levels[i] = top.level
+			if top.override != bidiNoOverride {
+				classes[i] = top.override
+			}
+		}
+	}
+}
+
+// bidiIsolatedText returns the text up to the PDI that closes the isolate
+// starting at the given index
+func bidiIsolatedText(text []rune, start int) []rune {
+	depth := 1
+	for i := start; i < len(text); i++ {
+		switch bidiClass(text[i]) {
+		case bidi.LRI, bidi.RLI, bidi.FSI:
+			depth++
+		case bidi.PDI:
+			depth--
+			if depth == 0 {
+				return text[start:i]
+			}
+		}
+	}
+	return text[start:]
+}
+
+// bidiResolveRuns applies the W, N and I rules to each level run
+func bidiResolveRuns(classes []bidi.Class, levels []uint8, base uint8) {
+	idx := make([]int, 0, len(classes))
+	for i, c := range classes {
+		if c != bidi.BN {
+			idx = append(idx, i)
+		}
+	}
+	for start := 0; start < len(idx); {
+		end := start
+		level := levels[idx[start]]
+		for end < len(idx) && levels[idx[end]] == level {
+			end++
+		}
+		prev, next := base, base
+		if start > 0 {
+			prev = levels[idx[start-1]]
+		}
+		if end < len(idx) {
+			next = levels[idx[end]]
+		}
+		sos := bidiLevelDirection(max(prev, level))
+		eos := bidiLevelDirection(max(next, level))
+		bidiResolveRun(classes, idx[start:end], level, sos, eos)
+		for _, i := range idx[start:end] {
+			levels[i] = bidiImplicitLevel(classes[i], level)
+		}
+		start = end
+	}
+	// Removed characters take the level of the character before them
+	for i, c := range classes {
+		if c == bidi.BN {
+			if i > 0 {
+				levels[i] = levels[i-1]
+			} else {
+				levels[i] = base
+			}
+		}
+	}
+}
+
+func isBidiNeutral(c bidi.Class) bool {
+	return c == bidi.B || c == bidi.S || c == bidi.WS || c == bidi.ON
+}
+
+func bidiResolveRun(classes []bidi.Class, run []int, level uint8, sos, eos bidi.Class) {
+	// W1: non spacing marks take the class of the character before them
+	prev := sos
+	for _, i := range run {
+		if classes[i] == bidi.NSM {
+			classes[i] = prev
+		}
+		prev = classes[i]
+	}
+	// W2 and W3: numbers after arabic letters are arabic numbers
+	strong := sos
+	for _, i := range run {
+		switch classes[i] {
+		case bidi.L, bidi.R, bidi.AL:
+			strong = classes[i]
+		case bidi.EN:
+			if strong == bidi.AL {
+				classes[i] = bidi.AN
+			}
+		}
+	}
+	for _, i := range run {
+		if classes[i] == bidi.AL {
+			classes[i] = bidi.R
+		}
+	}
+	// W4: a single separator between two numbers of the same type
+	for j := 1; j < len(run)-1; j++ {
+		c, before, after := classes[run[j]], classes[run[j-1]], classes[run[j+1]]
+		if c == bidi.ES && before == bidi.EN && after == bidi.EN {
+			classes[run[j]] = bidi.EN
+		} else if c == bidi.CS && before == after && (before == bidi.EN || before == bidi.AN) {
+			classes[run[j]] = before
+		}
+	}
+	// W5: terminators next to european numbers become numbers
+	for j := 0; j < len(run); j++ {
+		if classes[run[j]] != bidi.ET {
+			continue
+		}
+		end := j
+		for end < len(run) && classes[run[end]] == bidi.ET {
+			end++
+		}
+		if (j > 0 && classes[run[j-1]] == bidi.EN) || (end < len(run) && classes[run[end]] == bidi.EN) {
+			for k := j; k < end; k++ {
+				classes[run[k]] = bidi.EN
+			}
+		}
+		j = end - 1
+	}
+	// W6 and W7
+	strong = sos
+	for _, i := range run {
+		switch classes[i] {
+		case bidi.ES, bidi.ET, bidi.CS:
+			classes[i] = bidi.ON
+		case bidi.L, bidi.R:
+			strong = classes[i]
+		case bidi.EN:
+			if strong == bidi.L {
+				classes[i] = bidi.L
+			}
+		}
+	}
+	// N1 and N2: neutrals take the direction of matching surrounding text,
+	// otherwise the embedding direction
+	embedding := bidiLevelDirection(level)
+	strongOf := func(c bidi.Class) bidi.Class {
+		if c == bidi.EN || c == bidi.AN {
+			return bidi.R
+		}
+		return c
+	}
+	for j := 0; j < len(run); j++ {
+		if !isBidiNeutral(classes[run[j]]) {
+			continue
+		}
+		end := j
+		for end < len(run) && isBidiNeutral(classes[run[end]]) {
+			end++
+		}
+		before, after := sos, eos
+		if j > 0 {
+			before = strongOf(classes[run[j-1]])
+		}
+		if end < len(run) {
+			after = strongOf(classes[run[end]])
+		}
+		dir := embedding
+		if before == after {
+			dir = before
+		}
+		for k := j; k < end; k++ {
+			classes[run[k]] = dir
+		}
+		j = end - 1
+	}
+}
+
+// bidiImplicitLevel applies the I1 and I2 rules
+func bidiImplicitLevel(c bidi.Class, level uint8) uint8 {
+	if level%2 == 0 {
+		switch c {
+		case bidi.R:
+			return level + 1
+		case bidi.AN, bidi.EN:
+			return level + 2
+		}
+	} else if c == bidi.L || c == bidi.EN || c == bidi.AN {
+		return level + 1
+	}
+	return level
+}
+
+// BidiVisualOrder applies the L2 rule to the levels of a line, returning the
+// logical indexes in the order they are displayed from left to right
+func BidiVisualOrder(levels []uint8) []int {
+	order := make([]int, len(levels))
+	for i := range order {
+		order[i] = i
+	}
+	highest, lowestOdd := uint8(0), uint8(bidiMaxDepth+2)
+	for _, l := range levels {
+		highest = max(highest, l)
+		if l%2 == 1 {
+			lowestOdd = min(lowestOdd, l)
+		}
+	}
+	for level := highest; level >= lowestOdd && level > 0; level-- {
+		for i := 0; i < len(order); {
+			if levels[order[i]] < level {
+				i++
+				continue
+			}
+			end := i
+			for end < len(order) && levels[order[end]] >= level {
+				end++
+			}
+			for a, b := i, end-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+			}
+			i = end
+		}
+	}
+	return order
+}
+
+// BidiMirror returns the mirrored glyph of a character that is displayed
+// right to left, such as ')' for '('
+func BidiMirror(r rune) rune {
+	if m, ok := bidiMirrors[r]; ok {
+		return m
+	}
+	return r
+}
+
+// CaretPositions returns the x of the caret for each of the count+1 offsets
+// into the text that was laid out on the line. The caret after a rune sits on
+// its right edge, or on its left edge when the rune is displayed right to left
+func CaretPositions(line TextLine, count int) []float32 {
+	glyphs := make([]*TextGlyph, count)
+	for i := range line.Glyphs {
+		if idx := line.Glyphs[i].Index; idx >= 0 && idx < count {
+			glyphs[idx] = &line.Glyphs[i]
+		}
+	}
+	pos := make([]float32, count+1)
+	for i := range pos {
+		switch {
+		case i > 0 && glyphs[i-1] != nil:
+			g := glyphs[i-1]
+			pos[i] = g.X + g.Advance
+			if g.RightToLeft() {
+				pos[i] = g.X
+			}
+		case i < count && glyphs[i] != nil:
+			g := glyphs[i]
+			pos[i] = g.X
+			if g.RightToLeft() {
+				pos[i] = g.X + g.Advance
+			}
+		case i > 0:
+			pos[i] = pos[i-1]
+		case line.Direction == TextDirectionRTL:
+			pos[i] = line.Width
+		}
+	}
+	return pos
+}
+
+// CaretOffsetAt returns the offset into the text whose caret is the nearest to
+// the given x on the line
+func CaretOffsetAt(line TextLine, count int, x float32) int {
+	pos := CaretPositions(line, count)
+	best := 0
+	for i := range pos {
+		if matrix.Abs(pos[i]-x) < matrix.Abs(pos[best]-x) {
+			best = i
+		}
+	}
+	return best
+}
+
+// CaretOffsetBeside returns the offset whose caret is visually next to the one
+// at offset, to the right for a positive dir and to the left otherwise. When
+// several offsets share a position the one logically closest is used, offset
+// is returned if there is nothing further in that direction
+func CaretOffsetBeside(line TextLine, count, offset, dir int) int {
+	pos := CaretPositions(line, count)
+	offset = max(0, min(offset, count))
+	best := -1
+	for i := range pos {
+		d := pos[i] - pos[offset]
+		if dir < 0 {
+			d = -d
+		}
+		if d <= textLayoutEpsilon {
+			continue
+		}
+		if best < 0 {
+			best = i
+			continue
+		}
+		bd := pos[best] - pos[offset]
+		if dir < 0 {
+			bd = -bd
+		}
+		if d < bd-textLayoutEpsilon || (d < bd+textLayoutEpsilon &&
+			absInt(i-offset) < absInt(best-offset)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return offset
+	}
+	return best
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,125 @@
+/******************************************************************************/
+/* bidi_test.go                                                               */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package rendering
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBidiLevelsMixedText(t *testing.T) {
+	got := BidiLevels([]rune("abc אבג def"), TextDirectionLTR, TextBidiNormal)
+	want := []uint8{0, 0, 0, 0, 1, 1, 1, 0, 0, 0, 0}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected levels %v but got %v", want, got)
+	}
+	got = BidiLevels([]rune("אב 12"), TextDirectionRTL, TextBidiNormal)
+	want = []uint8{1, 1, 1, 2, 2}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected numbers in right to left text at level 2 but got %v", got)
+	}
+}
+
+func TestBidiArabicNumbers(t *testing.T) {
+	// W2 turns european numbers after arabic letters into arabic numbers, the
+	// separator between them stays part of the number
+	got := BidiLevels([]rune("ب 1,2"), TextDirectionLTR, TextBidiNormal)
+	want := []uint8{1, 1, 2, 2, 2}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected levels %v but got %v", want, got)
+	}
+}
+
+func TestBidiVisualOrder(t *testing.T) {
+	got := BidiVisualOrder([]uint8{0, 0, 1, 1, 2, 2, 0})
+	want := []int{0, 1, 4, 5, 3, 2, 6}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected order %v but got %v", want, got)
+	}
+}
+
+func TestFirstStrongDirection(t *testing.T) {
+	if dir, ok := FirstStrongDirection([]rune("123 שלום abc")); !ok || dir != TextDirectionRTL {
+		t.Errorf("expected right to left but got %v %v", dir, ok)
+	}
+	if dir, ok := FirstStrongDirection([]rune("\u2067שלום\u2069 abc")); !ok || dir != TextDirectionLTR {
+		t.Errorf("expected isolated text to be skipped but got %v %v", dir, ok)
+	}
+	if _, ok := FirstStrongDirection([]rune("123 !")); ok {
+		t.Error("expected no strong direction for neutral text")
+	}
+}
+
+func TestLayoutTextBidi(t *testing.T) {
+	expectLines(t, layoutStrings("abc אבג def", TextLayout{}), "abc גבא def")
+	rtl := TextLayout{Direction: TextDirectionRTL}
+	expectLines(t, layoutStrings("אבג abc", rtl), "abc גבא")
+	expectLines(t, layoutStrings("אבג 123", rtl), "123 גבא")
+	expectLines(t, layoutStrings("(אב)", rtl), "(בא)")
+	expectLines(t, layoutStrings("a\u202Bb c\u202Cd", TextLayout{}), "ab cd")
+	expectLines(t, layoutStrings("abc", TextLayout{Direction: TextDirectionRTL,
+		Bidi: TextBidiOverride}), "cba")
+}
+
+func TestLayoutTextBidiPlaintext(t *testing.T) {
+	lines := LayoutText([]rune("אבג\nabc"), TextLayout{Bidi: TextBidiPlaintext}, monoAdvance)
+	if len(lines) != 2 || lines[0].Direction != TextDirectionRTL || lines[1].Direction != TextDirectionLTR {
+		t.Fatalf("expected each paragraph to use its own direction")
+	}
+}
+
+func TestLayoutTextBidiWrapsInLogicalOrder(t *testing.T) {
+	rtl := TextLayout{Direction: TextDirectionRTL, MaxWidth: 30}
+	expectLines(t, layoutStrings("אבג דהו", rtl), " גבא", "והד")
+	lines := LayoutText([]rune("אבג דהו"), rtl, monoAdvance)
+	if lines[0].Glyphs[0].X != -10 || lines[0].Width != 30 {
+		t.Errorf("expected the trailing space to hang left, got x %f and width %f",
+			lines[0].Glyphs[0].X, lines[0].Width)
+	}
+	if lines[0].Glyphs[1].Index != 2 {
+		t.Errorf("expected the left most letter to be the last logical letter but was %d",
+			lines[0].Glyphs[1].Index)
+	}
+}
+
+func TestLayoutTextBidiEllipsis(t *testing.T) {
+	layout := TextLayout{Direction: TextDirectionRTL, MaxWidth: 40,
+		WhiteSpace: TextWhiteSpaceNoWrap, Overflow: TextOverflowEllipsis}
+	expectLines(t, layoutStrings("אבגדהו", layout), "…גבא")
+}
+
+func TestCaretPositionsBidi(t *testing.T) {
+	lines := LayoutText([]rune("abcאבג"), TextLayout{}, monoAdvance)
+	pos := CaretPositions(lines[0], 6)
+	expected := []float32{0, 10, 20, 30, 50, 40, 30}
+	for i := range expected {
+		if pos[i] != expected[i] {
+			t.Fatalf("expected caret positions %v but got %v", expected, pos)
+		}
+	}
+	if o := CaretOffsetAt(lines[0], 6, 41); o != 5 {
+		t.Errorf("expected offset 5 at x 41 but got %d", o)
+	}
+	moves := []struct{ from, dir, to int }{
+		{3, 1, 5}, {5, 1, 4}, {4, 1, 4}, {3, -1, 2}, {4, -1, 5},
+	}
+	for _, m := range moves {
+		if o := CaretOffsetBeside(lines[0], 6, m.from, m.dir); o != m.to {
+			t.Errorf("expected moving %d from %d to reach %d but got %d",
+				m.dir, m.from, m.to, o)
+		}
+	}
+}
+
+func TestFontJustifyResolve(t *testing.T) {
+	if FontJustifyStart.Resolve(TextDirectionRTL) != FontJustifyRight ||
+		FontJustifyEnd.Resolve(TextDirectionRTL) != FontJustifyLeft ||
+		FontJustifyStart.Resolve(TextDirectionLTR) != FontJustifyLeft ||
+		FontJustifyCenter.Resolve(TextDirectionRTL) != FontJustifyCenter {
+		t.Error("expected start and end to follow the direction")
+	}
+}
@@ -134,6 +134,8 @@ type ShaderDataBase struct {
 	viewCullStates map[*RenderView]bool
 	shadows        []DrawInstance
 	transform      *matrix.Transform
+	postModel      *matrix.Mat4
+	modelDirty     bool
 	InitModel      matrix.Mat4
 	model          matrix.Mat4
 }
@@ -208,6 +210,7 @@ func (s *ShaderDataBase) setTransform(transform *matrix.Transform) {
 
 func (s *ShaderDataBase) addShadow(shadow DrawInstance) {
 	s.shadows = append(s.shadows, shadow)
+	shadow.Base().SetPostModel(s.postModel)
 	if s.deactivated {
 		shadow.Deactivate()
 	}
@@ -217,14 +220,49 @@ func (s *ShaderDataBase) SetModel(model matrix.Mat4) {
 	s.InitModel = model
 	if s.transform == nil {
 		s.model = model
+	} else {
+		s.modelDirty = true
 	}
 }
 
+// SetPostModel sets a matrix that is applied after the world matrix of the
+// transform, such as the visual transform of a UI element that shouldn't
+// change its layout. Passing nil removes it
+func (s *ShaderDataBase) SetPostModel(model *matrix.Mat4) {
+	if model == nil && s.postModel == nil {
+		return
+	}
+	if model != nil {
+		m := *model
+		if s.postModel != nil && *s.postModel == m {
+			return
+		}
+		s.postModel = &m
+	} else {
+		s.postModel = nil
+	}
+	s.modelDirty = true
+	for i := range s.shadows {
+		s.shadows[i].Base().SetPostModel(model)
+	}
+}
+
+func (s *ShaderDataBase) PostModel() (matrix.Mat4, bool) {
+	if s.postModel == nil {
+		return matrix.Mat4Identity(), false
+	}
+	return *s.postModel, true
+}
+
 func (s *ShaderDataBase) forceUpdateTransformModel() {
+	s.modelDirty = false
 	if s.transform == nil {
 		return
 	}
 	s.model = matrix.Mat4Multiply(s.InitModel, s.transform.WorldMatrix())
+	if s.postModel != nil {
+		s.model = matrix.Mat4Multiply(s.model, *s.postModel)
+	}
 }
 
 func (s *ShaderDataBase) UpdateModel(viewCuller ViewCuller, container graviton.AABB) {
@@ -236,7 +274,7 @@ func (s *ShaderDataBase) UpdateModelForView(view *RenderView, viewCuller ViewCul
 	if viewCuller != nil {
 		recalcCulling = viewCuller.ViewChanged()
 	}
-	if s.transform != nil && s.transform.IsDirty() {
+	if s.transform != nil && (s.transform.IsDirty() || s.modelDirty) {
 		s.forceUpdateTransformModel()
 		s.aabb = container.Transform(s.model)
 		recalcCulling = true
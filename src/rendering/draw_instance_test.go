@@ -375,3 +375,42 @@ func TestDrawInstanceGroupClear(t *testing.T) {
 		t.Fatalf("Clear should no-op after destruction")
 	}
 }
+
+func TestShaderDataBasePostModel(t *testing.T) {
+	base := NewShaderDataBase()
+	container := graviton.AABBFromWidth(matrix.Vec3Zero(), 1)
+	var transform matrix.Transform
+	transform.SetupRawTransform()
+	transform.SetPosition(matrix.Vec3{5, 0, 0})
+	base.setTransform(&transform)
+	post := matrix.Mat4Identity()
+	post.Translate(matrix.Vec3{0, 3, 0})
+	base.SetPostModel(&post)
+	base.UpdateModel(nil, container)
+	if got := base.Model().TransformPoint(matrix.Vec3Zero()); got != (matrix.Vec3{5, 3, 0}) {
+		t.Fatalf("post model translation = %v", got)
+	}
+	base.SetPostModel(nil)
+	base.UpdateModel(nil, container)
+	if got := base.Model().TransformPoint(matrix.Vec3Zero()); got != (matrix.Vec3{5, 0, 0}) {
+		t.Fatalf("removed post model translation = %v", got)
+	}
+}
+
+func TestShaderDataBaseSetModelWithTransform(t *testing.T) {
+	base := NewShaderDataBase()
+	container := graviton.AABBFromWidth(matrix.Vec3Zero(), 1)
+	var transform matrix.Transform
+	transform.SetupRawTransform()
+	transform.SetPosition(matrix.Vec3{5, 0, 0})
+	base.setTransform(&transform)
+	base.UpdateModel(nil, container)
+	transform.ResetDirty()
+	model := matrix.Mat4Identity()
+	model.Translate(matrix.Vec3{0, 2, 0})
+	base.SetModel(model)
+	base.UpdateModel(nil, container)
+	if got := base.Model().TransformPoint(matrix.Vec3Zero()); got != (matrix.Vec3{5, 2, 0}) {
+		t.Fatalf("init model translation = %v", got)
+	}
+}
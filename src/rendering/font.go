@@ -22,6 +22,7 @@ import (
 	"kaijuengine.com/klib"
 	"kaijuengine.com/matrix"
 	"kaijuengine.com/platform/profiler/tracing"
+	"kaijuengine.com/rendering/loaders/opentype"
 )
 
 const (
@@ -41,8 +42,30 @@ const (
 	FontJustifyCenter
 	FontJustifyRight
 	FontJustifyJustify
+	// FontJustifyStart and FontJustifyEnd follow the direction of each line,
+	// left and right for left-to-right text and the other way for right-to-left
+	FontJustifyStart
+	FontJustifyEnd
 )
 
+// Resolve turns the logical start and end justification into the physical
+// side for text flowing in the given direction
+func (j FontJustify) Resolve(direction TextDirection) FontJustify {
+	switch j {
+	case FontJustifyStart:
+		if direction == TextDirectionRTL {
+			return FontJustifyRight
+		}
+		return FontJustifyLeft
+	case FontJustifyEnd:
+		if direction == TextDirectionRTL {
+			return FontJustifyLeft
+		}
+		return FontJustifyRight
+	}
+	return j
+}
+
 type FontBaseline int
 
 const (
@@ -71,6 +94,9 @@ type fontBin struct {
 	metrics                           fontBinMetrics
 	letters                           map[rune]fontBinChar
 	cachedLetters, cachedOrthoLetters map[rune]*cachedLetterMesh
+	// shaping is optional, it is appended to the .bin by the font generator
+	// when the source font has substitution or kerning tables
+	shaping *opentype.Shaping
 }
 
 type cachedLetterMesh struct {
@@ -91,6 +117,7 @@ type FontCache struct {
 	renderCaches                 RenderCaches
 	assetDb                      assets.Database
 	fontFaces                    map[string]fontBin
+	atlasImages                  map[string]*fontAtlasImage
 	instanceKey                  int64
 	FaceMutex                    sync.RWMutex
 }
@@ -102,6 +129,11 @@ type TextShaderData struct {
 	BgColor matrix.Color
 	Scissor matrix.Vec4
 	PxRange matrix.Vec2
+	// ClipShape and ClipData are the clip-path and mask of the UI element,
+	// see ui.ShaderData for their layout. ClipShape is read together with
+	// PxRange as a single vec4
+	ClipShape matrix.Vec2
+	ClipData  matrix.Mat4
 }
 
 func (s TextShaderData) Size() int {
@@ -110,7 +142,9 @@ func (s TextShaderData) Size() int {
 		unsafe.Sizeof(TextShaderData{}.FgColor) +
 		unsafe.Sizeof(TextShaderData{}.BgColor) +
 		unsafe.Sizeof(TextShaderData{}.Scissor) +
-		unsafe.Sizeof(TextShaderData{}.PxRange))
+		unsafe.Sizeof(TextShaderData{}.PxRange) +
+		unsafe.Sizeof(TextShaderData{}.ClipShape) +
+		unsafe.Sizeof(TextShaderData{}.ClipData))
 }
 
 func msdfAtlasPxRange() matrix.Vec2 {
@@ -298,6 +332,15 @@ func (cache *FontCache) initFont(face FontFace, adb assets.Database) bool {
 	if bin.texture == nil || out == nil || len(out) == 0 {
 		return false
 	}
+	count := bin.decode(out, face.string())
+	for i := int32(0); i < count; i++ {
+		cache.createLetterMesh(bin, bin.letters[i].letter, bin.letters[i], cache.renderCaches.MeshCache())
+	}
+	cache.fontFaces[face.string()] = bin
+	return true
+}
+
+func (bin *fontBin) decode(out []byte, name string) int32 {
 	read := bytes.NewReader(out)
 	// Create an int32 variable named count that is read from read
 	var count int32
@@ -318,7 +361,12 @@ func (cache *FontCache) initFont(face FontFace, adb assets.Database) bool {
 		binary.Read(read, binary.LittleEndian, &fbc.atlasBounds)
 		bin.letters[fbc.letter] = fbc
 	}
-	sample := findBinChar(bin, '-')
+	if shaping, err := opentype.DecodeShaping(read); err == nil {
+		bin.shaping = shaping
+	} else if err != opentype.ErrNoShaping {
+		slog.Error("failed to read the font shaping tables", "font", name, "error", err)
+	}
+	sample := findBinChar(*bin, '-')
 	cSpace := fontBinChar{
 		letter:      ' ',
 		advance:     sample.advance,
@@ -343,11 +391,7 @@ func (cache *FontCache) initFont(face FontFace, adb assets.Database) bool {
 	cReturn := fontBinChar{letter: '\r', advance: 0.0,
 		planeBounds: [4]float32{0, 0, 0, 0}, atlasBounds: [4]float32{0.999, 0.001, 1.0, 0.0}}
 	bin.letters['\r'] = cReturn
-	for i := int32(0); i < count; i++ {
-		cache.createLetterMesh(bin, bin.letters[i].letter, bin.letters[i], cache.renderCaches.MeshCache())
-	}
-	cache.fontFaces[face.string()] = bin
-	return true
+	return count
 }
 
 func (cache *FontCache) Init(caches RenderCaches) error {
@@ -374,6 +418,52 @@ func (cache *FontCache) Init(caches RenderCaches) error {
 	return nil
 }
 
+func (cache *FontCache) materialFor(fgColor, bgColor matrix.Color, is3D bool) *Material {
+	if fgColor.A() < 1 || bgColor.A() < 1 {
+		if is3D {
+			return cache.textMaterialTransparent
+		}
+		return cache.textOrthoMaterialTransparent
+	}
+	if is3D {
+		return cache.textMaterial
+	}
+	return cache.textOrthoMaterial
+}
+
+// lineOffsets returns the normalized offset of the start of a line within the
+// root scale based on the justification and baseline
+func lineOffsets(justify FontJustify, baseline FontBaseline, es matrix.Vec3,
+	maxWidth, lineWidth, maxHeight, descender float32) (float32, float32) {
+	left := -es.X() * 0.5
+	var xOffset, yOffset float32
+	switch justify {
+	case FontJustifyRight:
+		xOffset = left + (maxWidth - lineWidth)
+	case FontJustifyCenter:
+		xOffset = -(lineWidth * 0.5)
+	case FontJustifyJustify:
+		xOffset = left
+	case FontJustifyLeft:
+		xOffset = left
+	default:
+		xOffset = left
+	}
+	switch baseline {
+	case FontBaselineTop:
+		yOffset = (es.Y() * 0.5) + maxHeight
+	case FontBaselineCenter:
+		yOffset = maxHeight * 0.5
+	case FontBaselineBottom:
+	default:
+		yOffset = es.Y() * -0.5
+	}
+	xOffset /= es.X()
+	yOffset -= descender
+	yOffset /= es.Y()
+	return xOffset, yOffset
+}
+
 func (cache *FontCache) RenderMeshes(caches RenderCaches,
 	text string, x, y, z, scale, maxWidth float32, fgColor, bgColor matrix.Color,
 	justify FontJustify, baseline FontBaseline, rootScale matrix.Vec3, instanced,
@@ -390,26 +480,12 @@ func (cache *FontCache) RenderMeshesWithLetterSpacing(caches RenderCaches,
 	defer tracing.NewRegion("FontCache.RenderMeshes").End()
 	cache.requireFace(face)
 	es := rootScale
-	left := -es.X() * 0.5
 	inverseWidth := 1.0 / es.X()
 	inverseHeight := 1.0 / es.Y()
 	cx := x * inverseWidth
 	cy := y * inverseHeight
 	fontFace := cache.fontFaces[face.string()]
-	var material *Material
-	if fgColor.A() < 1 || bgColor.A() < 1 {
-		if is3D {
-			material = cache.textMaterialTransparent
-		} else {
-			material = cache.textOrthoMaterialTransparent
-		}
-	} else {
-		if is3D {
-			material = cache.textMaterial
-		} else {
-			material = cache.textOrthoMaterial
-		}
-	}
+	material := cache.materialFor(fgColor, bgColor, is3D)
 	// Iterate through all characters
 	runes := []rune(text)
 	textLen := len(runes)
@@ -439,31 +515,8 @@ func (cache *FontCache) RenderMeshesWithLetterSpacing(caches RenderCaches,
 				}
 			}
 		}
-		var xOffset, yOffset float32
-		switch justify {
-		case FontJustifyRight:
-			xOffset = left + (maxWidth - lineWidth)
-		case FontJustifyCenter:
-			xOffset = -(lineWidth * 0.5)
-		case FontJustifyJustify:
-			xOffset = left
-		case FontJustifyLeft:
-			xOffset = left
-		default:
-			xOffset = left
-		}
-		switch baseline {
-		case FontBaselineTop:
-			yOffset = (es.Y() * 0.5) + maxHeight
-		case FontBaselineCenter:
-			yOffset = maxHeight * 0.5
-		case FontBaselineBottom:
-		default:
-			yOffset = es.Y() * -0.5
-		}
-		xOffset *= inverseWidth
-		yOffset -= fontFace.metrics.Descender * scale
-		yOffset *= inverseHeight
+		xOffset, yOffset := lineOffsets(justify, baseline, es, maxWidth,
+			lineWidth, maxHeight, fontFace.metrics.Descender*scale)
 		justifySpaceAdvance := float32(0)
 		if justify == FontJustifyJustify && current+charLen < textLen && maxWidth > lineWidth {
 			spaceCount := 0
@@ -487,75 +540,9 @@ func (cache *FontCache) RenderMeshesWithLetterSpacing(caches RenderCaches,
 				yPos := cy + (ch.planeBounds[1] * scale * inverseHeight)
 				xPos += xOffset
 				yPos += yOffset
-				w := ch.Width() * scale * inverseWidth
-				h := ch.Height() * scale * inverseHeight
-				pxRange := msdfAtlasPxRange()
-				var uvs matrix.Vec4
-				var clm *cachedLetterMesh = nil
-				if instanced {
-					clm = cache.cachedMeshLetter(fontFace, c, !is3D)
-					if clm == nil {
-						cache.createLetterMesh(fontFace, c, fontFace.letters[c], cache.renderCaches.MeshCache())
-						clm = cache.cachedMeshLetter(fontFace, c, !is3D)
-					}
-				}
-				var m *Mesh
-				model := matrix.Mat4Identity()
-				zPos := z
-				if slices.Contains(overlappingLetters, c) {
-					zPos -= 0.0001
-				}
-				if clm == nil {
-					var verts [4]Vertex
-					verts[0].Position = matrix.Vec3{xPos, yPos, zPos}
-					verts[0].Normal = matrix.Vec3{0.0, 0.0, 1.0}
-					verts[0].UV0 = matrix.Vec2{0.0, 1.0}
-					verts[0].Color = matrix.ColorWhite()
-					verts[1].Position = matrix.Vec3{xPos, yPos + h, zPos}
-					verts[1].Normal = matrix.Vec3{0.0, 0.0, 1.0}
-					verts[1].UV0 = matrix.Vec2{0.0, 0.0}
-					verts[1].Color = matrix.ColorWhite()
-					verts[2].Position = matrix.Vec3{xPos + w, yPos + h, zPos}
-					verts[2].Normal = matrix.Vec3{0.0, 0.0, 1.0}
-					verts[2].UV0 = matrix.Vec2{1.0, 0.0}
-					verts[2].Color = matrix.ColorWhite()
-					verts[3].Position = matrix.Vec3{xPos + w, yPos, zPos}
-					verts[3].Normal = matrix.Vec3{0.0, 0.0, 1.0}
-					verts[3].UV0 = matrix.Vec2{1.0, 1.0}
-					verts[3].Color = matrix.ColorWhite()
-					indexes := [6]uint32{0, 1, 2, 0, 2, 3}
-					m = caches.MeshCache().Mesh(cache.nextInstanceKey(c), verts[:], indexes[:])
-					uvx := ch.atlasBounds[0]
-					uvy := ch.atlasBounds[1]
-					uvw := ch.atlasBounds[2] - ch.atlasBounds[0]
-					uvh := ch.atlasBounds[3] - ch.atlasBounds[1]
-					uvs = matrix.Vec4{
-						uvx / float32(fontFace.width), uvy / float32(fontFace.height),
-						uvw / float32(fontFace.width), uvh / float32(fontFace.height)}
-				} else {
-					// TODO:  Scale and place the mesh based on justify, baseline, etc.
-					model.MultiplyAssign(clm.transformation)
-					model.Scale(matrix.Vec3{scale * inverseWidth, scale * inverseHeight, 1.0})
-					model.Translate(matrix.Vec3{xPos, (yPos + h), zPos})
-					uvs = clm.uvs
-					m = clm.mesh
-				}
-				shaderData := &TextShaderData{
-					ShaderDataBase: NewShaderDataBase(),
-					FgColor:        fgColor,
-					BgColor:        bgColor,
-					PxRange:        pxRange,
-					UVs:            uvs,
-					Scissor:        matrix.Vec4{-matrix.FloatMax, -matrix.FloatMax, matrix.FloatMax, matrix.FloatMax},
-				}
-				shaderData.SetModel(model)
-				drawing := Drawing{
-					Material:   material.CreateInstance([]*Texture{fontFace.texture}),
-					Mesh:       m,
-					ShaderData: shaderData,
-					Transform:  nil,
-					ViewCuller: cam,
-				}
+				drawing := cache.letterDrawing(caches, fontFace, material, c,
+					matrix.Vec3{xPos, yPos, z}, scale, inverseWidth, inverseHeight,
+					fgColor, bgColor, instanced, is3D, cam)
 				fontMeshes = append(fontMeshes, drawing)
 				cx += ch.advance * scale * inverseWidth
 				if i < current+charLen-1 {
@@ -574,6 +561,249 @@ func (cache *FontCache) RenderMeshesWithLetterSpacing(caches RenderCaches,
 	return fontMeshes
 }
 
+// LayoutText breaks the text into lines using the glyph advances of the face
+func (cache *FontCache) LayoutText(face FontFace, text string, scale float32, layout TextLayout) []TextLine {
+	defer tracing.NewRegion("FontCache.LayoutText").End()
+	cache.requireFace(face)
+	fontFace := cache.fontFaces[face.string()]
+	if layout.Ellipsis == "" {
+		if _, ok := fontFace.letters['…']; !ok {
+			layout.Ellipsis = "..."
+		}
+	}
+	advance := func(r rune) float32 {
+		return findBinChar(fontFace, r).advance * scale
+	}
+	if fontFace.shaping == nil {
+		return LayoutText([]rune(text), layout, advance)
+	}
+	features := layout.Features.Tags(layout.LetterSpacing != 0)
+	return LayoutShapedText([]rune(text), layout, advance, func(line []rune) []ShapedGlyph {
+		glyphs := fontFace.shaping.Shape(line, features)
+		shaped := make([]ShapedGlyph, len(glyphs))
+		for i, g := range glyphs {
+			shaped[i] = ShapedGlyph{Rune: g.Rune, Cluster: g.Cluster, Kern: g.Kern * scale}
+		}
+		return shaped
+	})
+}
+
+func (cache *FontCache) lineAdvance(face FontFace, scale, lineHeight float32) float32 {
+	if lineHeight > 0 {
+		return lineHeight
+	}
+	return cache.fontFaces[face.string()].metrics.LineHeight * scale
+}
+
+// MeasureTextLines returns the size of the lines returned from LayoutText
+func (cache *FontCache) MeasureTextLines(face FontFace, lines []TextLine, scale, lineHeight float32) matrix.Vec2 {
+	cache.requireFace(face)
+	size := matrix.Vec2{0, cache.lineAdvance(face, scale, lineHeight) * float32(len(lines))}
+	for i := range lines {
+		size.SetX(max(size.X(), lines[i].Width))
+	}
+	return size
+}
+
+// LayoutVerticalText lays out the text in columns for the vertical writing
+// modes, each glyph is stacked upright taking up a full line advance. The X
+// and Width of the glyphs and lines are then the distance down the column
+func (cache *FontCache) LayoutVerticalText(face FontFace, text string, scale, lineHeight float32, layout TextLayout) []TextLine {
+	defer tracing.NewRegion("FontCache.LayoutVerticalText").End()
+	cache.requireFace(face)
+	advance := cache.lineAdvance(face, scale, lineHeight)
+	layout.Direction = TextDirectionLTR
+	layout.Bidi = TextBidiNormal
+	if layout.Ellipsis == "" {
+		if _, ok := cache.fontFaces[face.string()].letters['…']; !ok {
+			layout.Ellipsis = "..."
+		}
+	}
+	return LayoutText([]rune(text), layout, func(r rune) float32 { return advance })
+}
+
+// MeasureVerticalTextLines returns the size of the columns returned from
+// LayoutVerticalText
+func (cache *FontCache) MeasureVerticalTextLines(face FontFace, lines []TextLine, scale, lineHeight float32) matrix.Vec2 {
+	size := cache.MeasureTextLines(face, lines, scale, lineHeight)
+	return matrix.Vec2{size.Y(), size.X()}
+}
+
+// RenderVerticalTextLines creates the drawings for the columns returned from
+// LayoutVerticalText. The columns are placed from the right edge when
+// rightToLeft is set (vertical-rl) and from the left edge otherwise
+func (cache *FontCache) RenderVerticalTextLines(caches RenderCaches, lines []TextLine,
+	x, y, z, scale float32, fgColor, bgColor matrix.Color, rightToLeft bool,
+	rootScale matrix.Vec3, instanced, is3D bool, face FontFace, lineHeight float32,
+	cam *cameras.Container) ([]Drawing, []int) {
+	defer tracing.NewRegion("FontCache.RenderVerticalTextLines").End()
+	cache.requireFace(face)
+	es := rootScale
+	inverseWidth := 1.0 / es.X()
+	inverseHeight := 1.0 / es.Y()
+	fontFace := cache.fontFaces[face.string()]
+	material := cache.materialFor(fgColor, bgColor, is3D)
+	advance := cache.lineAdvance(face, scale, lineHeight)
+	top := es.Y()*0.5 - advance - fontFace.metrics.Descender*scale
+	fontMeshes := make([]Drawing, 0)
+	sources := make([]int, 0)
+	for i := range lines {
+		columnX := -es.X()*0.5 + float32(i)*advance
+		if rightToLeft {
+			columnX = es.X()*0.5 - float32(i+1)*advance
+		}
+		for _, g := range lines[i].Glyphs {
+			if !g.Visible() {
+				continue
+			}
+			ch := findBinChar(fontFace, g.Rune)
+			gx := columnX + (advance-ch.advance*scale)*0.5
+			xPos := (x+gx)*inverseWidth + (ch.planeBounds[0] * scale * inverseWidth)
+			yPos := (y+top-g.X)*inverseHeight + (ch.planeBounds[1] * scale * inverseHeight)
+			fontMeshes = append(fontMeshes, cache.letterDrawing(caches, fontFace,
+				material, g.Rune, matrix.Vec3{xPos, yPos, z}, scale, inverseWidth,
+				inverseHeight, fgColor, bgColor, instanced, is3D, cam))
+			sources = append(sources, g.Index)
+		}
+	}
+	return fontMeshes, sources
+}
+
+// RenderTextLines creates the drawings for the lines returned from LayoutText.
+// Along with the drawings, it returns the index of the source rune for each
+// of the drawings (-1 for inserted hyphens and ellipses).
+func (cache *FontCache) RenderTextLines(caches RenderCaches, lines []TextLine,
+	x, y, z, scale, maxWidth float32, fgColor, bgColor matrix.Color,
+	justify FontJustify, baseline FontBaseline, rootScale matrix.Vec3, instanced,
+	is3D bool, face FontFace, lineHeight float32, cam *cameras.Container) ([]Drawing, []int) {
+	defer tracing.NewRegion("FontCache.RenderTextLines").End()
+	cache.requireFace(face)
+	es := rootScale
+	inverseWidth := 1.0 / es.X()
+	inverseHeight := 1.0 / es.Y()
+	fontFace := cache.fontFaces[face.string()]
+	material := cache.materialFor(fgColor, bgColor, is3D)
+	lineAdvance := cache.lineAdvance(face, scale, lineHeight)
+	cy := y * inverseHeight
+	fontMeshes := make([]Drawing, 0)
+	sources := make([]int, 0)
+	for i := range lines {
+		line := &lines[i]
+		lineJustify := justify.Resolve(line.Direction)
+		xOffset, yOffset := lineOffsets(lineJustify, baseline, es, maxWidth,
+			line.Width, -lineAdvance, fontFace.metrics.Descender*scale)
+		justifySpaceAdvance := float32(0)
+		if lineJustify == FontJustifyJustify && i < len(lines)-1 && !line.Forced() && maxWidth > line.Width {
+			spaceCount := 0
+			for _, g := range line.Glyphs {
+				if isBreakSpace(g.Rune) && g.X >= 0 && g.X+g.Advance < line.Width {
+					spaceCount++
+				}
+			}
+			if spaceCount > 0 {
+				justifySpaceAdvance = (maxWidth - line.Width) / float32(spaceCount)
+			}
+		}
+		justifyOffset := float32(0)
+		for _, g := range line.Glyphs {
+			if !g.Visible() {
+				continue
+			}
+			ch := findBinChar(fontFace, g.Rune)
+			xPos := (x+g.X+justifyOffset)*inverseWidth + (ch.planeBounds[0] * scale * inverseWidth)
+			yPos := cy + (ch.planeBounds[1] * scale * inverseHeight)
+			xPos += xOffset
+			yPos += yOffset
+			fontMeshes = append(fontMeshes, cache.letterDrawing(caches, fontFace,
+				material, g.Rune, matrix.Vec3{xPos, yPos, z}, scale, inverseWidth,
+				inverseHeight, fgColor, bgColor, instanced, is3D, cam))
+			sources = append(sources, g.Index)
+			if justifySpaceAdvance > 0 && isBreakSpace(g.Rune) {
+				justifyOffset += justifySpaceAdvance
+			}
+		}
+		cy -= lineAdvance * inverseHeight
+	}
+	return fontMeshes, sources
+}
+
+func (cache *FontCache) letterDrawing(caches RenderCaches, fontFace fontBin,
+	material *Material, c rune, pos matrix.Vec3, scale, inverseWidth,
+	inverseHeight float32, fgColor, bgColor matrix.Color, instanced, is3D bool,
+	cam *cameras.Container) Drawing {
+	ch := findBinChar(fontFace, c)
+	xPos, yPos := pos.X(), pos.Y()
+	w := ch.Width() * scale * inverseWidth
+	h := ch.Height() * scale * inverseHeight
+	pxRange := msdfAtlasPxRange()
+	var uvs matrix.Vec4
+	var clm *cachedLetterMesh = nil
+	if instanced {
+		clm = cache.cachedMeshLetter(fontFace, c, !is3D)
+		if clm == nil {
+			cache.createLetterMesh(fontFace, c, fontFace.letters[c], cache.renderCaches.MeshCache())
+			clm = cache.cachedMeshLetter(fontFace, c, !is3D)
+		}
+	}
+	var m *Mesh
+	model := matrix.Mat4Identity()
+	zPos := pos.Z()
+	if slices.Contains(overlappingLetters, c) {
+		zPos -= 0.0001
+	}
+	if clm == nil {
+		var verts [4]Vertex
+		verts[0].Position = matrix.Vec3{xPos, yPos, zPos}
+		verts[0].Normal = matrix.Vec3{0.0, 0.0, 1.0}
+		verts[0].UV0 = matrix.Vec2{0.0, 1.0}
+		verts[0].Color = matrix.ColorWhite()
+		verts[1].Position = matrix.Vec3{xPos, yPos + h, zPos}
+		verts[1].Normal = matrix.Vec3{0.0, 0.0, 1.0}
+		verts[1].UV0 = matrix.Vec2{0.0, 0.0}
+		verts[1].Color = matrix.ColorWhite()
+		verts[2].Position = matrix.Vec3{xPos + w, yPos + h, zPos}
+		verts[2].Normal = matrix.Vec3{0.0, 0.0, 1.0}
+		verts[2].UV0 = matrix.Vec2{1.0, 0.0}
+		verts[2].Color = matrix.ColorWhite()
+		verts[3].Position = matrix.Vec3{xPos + w, yPos, zPos}
+		verts[3].Normal = matrix.Vec3{0.0, 0.0, 1.0}
+		verts[3].UV0 = matrix.Vec2{1.0, 1.0}
+		verts[3].Color = matrix.ColorWhite()
+		indexes := [6]uint32{0, 1, 2, 0, 2, 3}
+		m = caches.MeshCache().Mesh(cache.nextInstanceKey(c), verts[:], indexes[:])
+		uvx := ch.atlasBounds[0]
+		uvy := ch.atlasBounds[1]
+		uvw := ch.atlasBounds[2] - ch.atlasBounds[0]
+		uvh := ch.atlasBounds[3] - ch.atlasBounds[1]
+		uvs = matrix.Vec4{
+			uvx / float32(fontFace.width), uvy / float32(fontFace.height),
+			uvw / float32(fontFace.width), uvh / float32(fontFace.height)}
+	} else {
+		// TODO:  Scale and place the mesh based on justify, baseline, etc.
+		model.MultiplyAssign(clm.transformation)
+		model.Scale(matrix.Vec3{scale * inverseWidth, scale * inverseHeight, 1.0})
+		model.Translate(matrix.Vec3{xPos, (yPos + h), zPos})
+		uvs = clm.uvs
+		m = clm.mesh
+	}
+	shaderData := &TextShaderData{
+		ShaderDataBase: NewShaderDataBase(),
+		FgColor:        fgColor,
+		BgColor:        bgColor,
+		PxRange:        pxRange,
+		UVs:            uvs,
+		Scissor:        matrix.Vec4{-matrix.FloatMax, -matrix.FloatMax, matrix.FloatMax, matrix.FloatMax},
+	}
+	shaderData.SetModel(model)
+	return Drawing{
+		Material:   material.CreateInstance([]*Texture{fontFace.texture}),
+		Mesh:       m,
+		ShaderData: shaderData,
+		Transform:  nil,
+		ViewCuller: cam,
+	}
+}
+
 func (cache *FontCache) MeasureString(face FontFace, text string, scale float32) float32 {
 	return cache.MeasureStringWithLetterSpacing(face, text, scale, 0)
 }
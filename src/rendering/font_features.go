@@ -0,0 +1,171 @@
+/******************************************************************************/
+/* font_features.go                                                           */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package rendering
+
+import (
+	"strconv"
+	"strings"
+)
+
+type FontKerning int
+
+const (
+	FontKerningAuto = FontKerning(iota)
+	FontKerningNormal
+	FontKerningNone
+)
+
+// FontLigatures are flags that change the ligatures enabled by default
+type FontLigatures int
+
+const (
+	FontLigaturesNoCommon = FontLigatures(1 << iota)
+	FontLigaturesDiscretionary
+	FontLigaturesHistorical
+	FontLigaturesNoContextual
+	FontLigaturesNone = FontLigaturesNoCommon | FontLigaturesNoContextual
+)
+
+type FontCaps int
+
+const (
+	FontCapsNormal = FontCaps(iota)
+	FontCapsSmall
+	FontCapsAllSmall
+	FontCapsPetite
+	FontCapsAllPetite
+	FontCapsUnicase
+	FontCapsTitling
+)
+
+type FontNumeric int
+
+const (
+	FontNumericLining = FontNumeric(1 << iota)
+	FontNumericOldstyle
+	FontNumericProportional
+	FontNumericTabular
+	FontNumericDiagonalFractions
+	FontNumericStackedFractions
+	FontNumericOrdinal
+	FontNumericSlashedZero
+)
+
+type FontEastAsian int
+
+const (
+	FontEastAsianJIS78 = FontEastAsian(1 << iota)
+	FontEastAsianJIS83
+	FontEastAsianJIS90
+	FontEastAsianJIS04
+	FontEastAsianSimplified
+	FontEastAsianTraditional
+	FontEastAsianFullWidth
+	FontEastAsianProportionalWidth
+	FontEastAsianRuby
+)
+
+type FontPosition int
+
+const (
+	FontPositionNormal = FontPosition(iota)
+	FontPositionSub
+	FontPositionSuper
+)
+
+type FontAlternates int
+
+const (
+	FontAlternatesHistoricalForms = FontAlternates(1 << iota)
+)
+
+// FontFeatures selects the OpenType features used when shaping text, the
+// zero value enables the features a font applies by default
+type FontFeatures struct {
+	Kerning    FontKerning
+	Ligatures  FontLigatures
+	Caps       FontCaps
+	Numeric    FontNumeric
+	EastAsian  FontEastAsian
+	Position   FontPosition
+	Alternates FontAlternates
+	// Settings are low level feature overrides written as "tag=value"
+	// separated by commas, a value of 0 disables the feature
+	Settings string
+}
+
+// Features that are on unless disabled, the Indic features are applied to
+// the whole run rather than by syllable position
+var defaultFontFeatures = []string{
+	"ccmp", "locl", "rlig", "rclt", "liga", "clig", "calt", "kern", "mark",
+	"mkmk", "nukt", "akhn", "rphf", "rkrf", "pref", "blwf", "abvf", "half",
+	"pstf", "vatu", "cjct", "pres", "abvs", "blws", "psts", "haln",
+}
+
+var (
+	fontCapsFeatures = map[FontCaps][]string{
+		FontCapsSmall:     {"smcp"},
+		FontCapsAllSmall:  {"smcp", "c2sc"},
+		FontCapsPetite:    {"pcap"},
+		FontCapsAllPetite: {"pcap", "c2pc"},
+		FontCapsUnicase:   {"unic"},
+		FontCapsTitling:   {"titl"},
+	}
+	fontNumericFeatures = []string{"lnum", "onum", "pnum", "tnum", "frac", "afrc", "ordn", "zero"}
+	fontEastAsianTags   = []string{"jp78", "jp83", "jp90", "jp04", "smpl", "trad", "fwid", "pwid", "ruby"}
+)
+
+// Tags returns the OpenType feature tags that are enabled. Optional
+// ligatures are disabled for letter spaced text as they would not be spaced
+func (f FontFeatures) Tags(letterSpaced bool) map[string]bool {
+	tags := make(map[string]bool, len(defaultFontFeatures))
+	for _, tag := range defaultFontFeatures {
+		tags[tag] = true
+	}
+	if f.Kerning == FontKerningNone {
+		tags["kern"] = false
+	}
+	if f.Ligatures&FontLigaturesNoCommon != 0 || letterSpaced {
+		tags["liga"], tags["clig"] = false, false
+	}
+	if f.Ligatures&FontLigaturesNoContextual != 0 || letterSpaced {
+		tags["calt"] = false
+	}
+	tags["dlig"] = f.Ligatures&FontLigaturesDiscretionary != 0 && !letterSpaced
+	tags["hlig"] = f.Ligatures&FontLigaturesHistorical != 0 && !letterSpaced
+	for _, tag := range fontCapsFeatures[f.Caps] {
+		tags[tag] = true
+	}
+	for i, tag := range fontNumericFeatures {
+		if f.Numeric&(1<<i) != 0 {
+			tags[tag] = true
+		}
+	}
+	for i, tag := range fontEastAsianTags {
+		if f.EastAsian&(1<<i) != 0 {
+			tags[tag] = true
+		}
+	}
+	switch f.Position {
+	case FontPositionSub:
+		tags["subs"] = true
+	case FontPositionSuper:
+		tags["sups"] = true
+	}
+	if f.Alternates&FontAlternatesHistoricalForms != 0 {
+		tags["hist"] = true
+	}
+	for setting := range strings.SplitSeq(f.Settings, ",") {
+		tag, value, _ := strings.Cut(setting, "=")
+		if len(tag) != 4 {
+			continue
+		}
+		v, err := strconv.Atoi(value)
+		tags[tag] = err != nil || v != 0
+	}
+	return tags
+}
@@ -0,0 +1,146 @@
+/******************************************************************************/
+/* font_raster.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package rendering
+
+import (
+	"errors"
+	"image"
+	"math"
+	"strings"
+
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/platform/profiler/tracing"
+)
+
+// fontAtlasImage is the CPU copy of the MSDF atlas of a font, it is only
+// read when text is drawn into an image rather than onto the screen
+type fontAtlasImage struct {
+	pixels        []byte
+	width, height int
+}
+
+// sample bilinearly reads the median of the distance channels at a point of
+// the atlas, y is measured up from the bottom the same as the glyph bounds
+func (a *fontAtlasImage) sample(x, y float32) float32 {
+	row := float32(a.height) - y - 0.5
+	col := x - 0.5
+	x0, y0 := int(math.Floor(float64(col))), int(math.Floor(float64(row)))
+	fx, fy := col-float32(x0), row-float32(y0)
+	read := func(px, py int) [3]float32 {
+		px = max(0, min(a.width-1, px))
+		py = max(0, min(a.height-1, py))
+		i := (py*a.width + px) * 4
+		return [3]float32{float32(a.pixels[i]), float32(a.pixels[i+1]), float32(a.pixels[i+2])}
+	}
+	c00, c10 := read(x0, y0), read(x0+1, y0)
+	c01, c11 := read(x0, y0+1), read(x0+1, y0+1)
+	var rgb [3]float32
+	for i := range rgb {
+		top := c00[i] + (c10[i]-c00[i])*fx
+		bottom := c01[i] + (c11[i]-c01[i])*fx
+		rgb[i] = (top + (bottom-top)*fy) / 255
+	}
+	return max(min(rgb[0], rgb[1]), min(max(rgb[0], rgb[1]), rgb[2]))
+}
+
+func (cache *FontCache) atlasImage(face FontFace) (*fontAtlasImage, error) {
+	cache.FaceMutex.Lock()
+	defer cache.FaceMutex.Unlock()
+	if cache.atlasImages == nil {
+		cache.atlasImages = make(map[string]*fontAtlasImage)
+	}
+	if a, ok := cache.atlasImages[face.string()]; ok {
+		return a, nil
+	}
+	mem, err := cache.assetDb.Read(face.string() + ".png")
+	if err != nil {
+		return nil, err
+	}
+	data := ReadRawTextureData(mem, TextureFileFormatPng)
+	if len(data.Mem) != data.Width*data.Height*4 || data.Width == 0 {
+		return nil, errors.New("failed to read the font atlas image")
+	}
+	a := &fontAtlasImage{pixels: data.Mem, width: data.Width, height: data.Height}
+	cache.atlasImages[face.string()] = a
+	return a, nil
+}
+
+// RasterizeText draws a single line of text into an alpha mask with the
+// glyphs sized to pixelSize, the same as the scale used by RenderMeshes.
+// The returned origin is the left of the baseline of the text within the
+// mask, which can be placed anywhere as glyphs reach past their advance
+func (cache *FontCache) RasterizeText(face FontFace, text string, pixelSize float32) (*image.Alpha, matrix.Vec2, error) {
+	defer tracing.NewRegion("FontCache.RasterizeText").End()
+	cache.requireFace(face)
+	cache.FaceMutex.RLock()
+	font, ok := cache.fontFaces[face.string()]
+	cache.FaceMutex.RUnlock()
+	if !ok {
+		return nil, matrix.Vec2{}, errors.New("failed to load the font " + face.string())
+	}
+	atlas, err := cache.atlasImage(face)
+	if err != nil {
+		return nil, matrix.Vec2{}, err
+	}
+	mask, origin := rasterizeText(font, atlas, text, pixelSize)
+	return mask, origin, nil
+}
+
+func rasterizeText(font fontBin, atlas *fontAtlasImage, text string, pixelSize float32) (*image.Alpha, matrix.Vec2) {
+	text = strings.ReplaceAll(text, "\n", " ")
+	type placed struct {
+		ch fontBinChar
+		x  float32
+	}
+	glyphs := make([]placed, 0, len(text))
+	pen := float32(0)
+	// Bounds of the ink with y going up from the baseline
+	minX, minY := float32(math.MaxFloat32), float32(math.MaxFloat32)
+	maxX, maxY := float32(-math.MaxFloat32), float32(-math.MaxFloat32)
+	for _, r := range text {
+		ch := findBinChar(font, r)
+		// Spaces and tabs point at an empty corner of the atlas
+		if ch.AtlasWidth() >= 1 && ch.Width() > 0 {
+			glyphs = append(glyphs, placed{ch, pen})
+			minX = min(minX, pen+ch.planeBounds[0]*pixelSize)
+			maxX = max(maxX, pen+ch.planeBounds[2]*pixelSize)
+			minY = min(minY, ch.planeBounds[3]*pixelSize)
+			maxY = max(maxY, ch.planeBounds[1]*pixelSize)
+		}
+		pen += ch.advance * pixelSize
+	}
+	if len(glyphs) == 0 {
+		return image.NewAlpha(image.Rect(0, 0, 0, 0)), matrix.Vec2{}
+	}
+	left := float32(math.Floor(float64(minX))) - 1
+	top := float32(math.Ceil(float64(maxY))) + 1
+	w := int(math.Ceil(float64(maxX-left))) + 1
+	h := int(math.Ceil(float64(top-minY))) + 1
+	mask := image.NewAlpha(image.Rect(0, 0, w, h))
+	for _, g := range glyphs {
+		pb, ab := g.ch.planeBounds, g.ch.atlasBounds
+		gx0, gx1 := g.x+pb[0]*pixelSize-left, g.x+pb[2]*pixelSize-left
+		gy0, gy1 := top-pb[1]*pixelSize, top-pb[3]*pixelSize
+		// How many pixels of the mask a pixel of the atlas covers
+		pxRange := distanceFieldRange * (gx1 - gx0) / max(ab[2]-ab[0], 1)
+		for y := max(0, int(gy0)); y < min(h, int(math.Ceil(float64(gy1)))); y++ {
+			ty := (float32(y) + 0.5 - gy0) / (gy1 - gy0)
+			ay := ab[1] + (ab[3]-ab[1])*ty
+			for x := max(0, int(gx0)); x < min(w, int(math.Ceil(float64(gx1)))); x++ {
+				tx := (float32(x) + 0.5 - gx0) / (gx1 - gx0)
+				if tx < 0 || tx > 1 || ty < 0 || ty > 1 {
+					continue
+				}
+				dist := atlas.sample(ab[0]+(ab[2]-ab[0])*tx, ay) - 0.5
+				a := matrix.Clamp(dist*max(pxRange, 1)+0.5, 0, 1)
+				i := mask.PixOffset(x, y)
+				mask.Pix[i] = max(mask.Pix[i], uint8(a*255+0.5))
+			}
+		}
+	}
+	return mask, matrix.Vec2{-left, top}
+}
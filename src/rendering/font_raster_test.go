@@ -0,0 +1,67 @@
+/******************************************************************************/
+/* font_raster_test.go                                                        */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package rendering
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readTestFont(t *testing.T) (fontBin, *fontAtlasImage) {
+	dir := filepath.Join("..", "editor", "editor_embedded_content", "editor_content", "fonts")
+	bin, err := os.ReadFile(filepath.Join(dir, "OpenSans-Regular.bin"))
+	if err != nil {
+		t.Skip("font files are not available:", err)
+	}
+	img, err := os.ReadFile(filepath.Join(dir, "OpenSans-Regular.png"))
+	if err != nil {
+		t.Skip("font files are not available:", err)
+	}
+	data := ReadRawTextureData(img, TextureFileFormatPng)
+	font := fontBin{}
+	font.decode(bin, "OpenSans-Regular")
+	return font, &fontAtlasImage{pixels: data.Mem, width: data.Width, height: data.Height}
+}
+
+func TestRasterizeTextPlacesInkAboveBaseline(t *testing.T) {
+	font, atlas := readTestFont(t)
+	mask, origin := rasterizeText(font, atlas, "A", 32)
+	if mask.Rect.Empty() {
+		t.Fatal("expected the mask to have a size")
+	}
+	baseline := int(origin.Y())
+	above, below := 0, 0
+	for y := 0; y < mask.Rect.Dy(); y++ {
+		for x := 0; x < mask.Rect.Dx(); x++ {
+			if mask.Pix[mask.PixOffset(x, y)] > 200 {
+				if y < baseline {
+					above++
+				} else if y > baseline+1 {
+					below++
+				}
+			}
+		}
+	}
+	if above == 0 || below != 0 {
+		t.Fatalf("expected the ink of A to sit on the baseline, %d pixels above and %d below", above, below)
+	}
+}
+
+func TestRasterizeTextGrowsWithText(t *testing.T) {
+	font, atlas := readTestFont(t)
+	one, _ := rasterizeText(font, atlas, "a", 20)
+	two, _ := rasterizeText(font, atlas, "aa", 20)
+	advance := findBinChar(font, 'a').advance * 20
+	if diff := float32(two.Rect.Dx() - one.Rect.Dx()); diff < advance-1 || diff > advance+1 {
+		t.Fatalf("expected the second letter to add %f pixels, added %f", advance, diff)
+	}
+	empty, _ := rasterizeText(font, atlas, "   ", 20)
+	if !empty.Rect.Empty() {
+		t.Fatalf("expected spaces to have no ink, got %v", empty.Rect)
+	}
+}
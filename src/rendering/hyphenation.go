@@ -0,0 +1,182 @@
+/******************************************************************************/
+/* hyphenation.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package rendering
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Hyphenator finds the positions within a word where it may be hyphenated,
+// each position is the rune index the second half of the word starts at
+type Hyphenator interface {
+	Hyphenate(word []rune) []int
+}
+
+// HyphenationPatterns is a Hyphenator using Liang's algorithm with TeX
+// hyphenation patterns (hyph-*.pat.txt) and exceptions (hyph-*.hyp.txt)
+type HyphenationPatterns struct {
+	patterns   map[string][]uint8
+	exceptions map[string][]int
+	maxLen     int
+	// The fewest runes to leave before and after a hyphen
+	LeftMin, RightMin int
+}
+
+var hyphenators = struct {
+	sync.RWMutex
+	langs map[string]Hyphenator
+}{langs: map[string]Hyphenator{}}
+
+// RegisterHyphenator sets the dictionary used for the given language, such
+// as "en" or "en-us", when text is laid out with TextHyphensAuto
+func RegisterHyphenator(lang string, h Hyphenator) {
+	hyphenators.Lock()
+	defer hyphenators.Unlock()
+	hyphenators.langs[strings.ToLower(lang)] = h
+}
+
+// HyphenatorFor finds the dictionary for a language, falling back from the
+// region to the base language ("en-gb" to "en")
+func HyphenatorFor(lang string) (Hyphenator, bool) {
+	hyphenators.RLock()
+	defer hyphenators.RUnlock()
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	for lang != "" {
+		if h, ok := hyphenators.langs[lang]; ok {
+			return h, true
+		}
+		idx := strings.LastIndex(lang, "-")
+		if idx < 0 {
+			break
+		}
+		lang = lang[:idx]
+	}
+	return nil, false
+}
+
+func NewHyphenationPatterns(patterns, exceptions []string) *HyphenationPatterns {
+	h := &HyphenationPatterns{
+		patterns:   make(map[string][]uint8, len(patterns)),
+		exceptions: make(map[string][]int, len(exceptions)),
+		LeftMin:    2,
+		RightMin:   3,
+	}
+	for _, p := range patterns {
+		h.AddPattern(p)
+	}
+	for _, e := range exceptions {
+		h.AddException(e)
+	}
+	return h
+}
+
+// LoadHyphenationPatterns reads whitespace separated patterns and, if given,
+// exceptions in the TeX format. Lines starting with % are comments.
+func LoadHyphenationPatterns(patterns io.Reader, exceptions io.Reader) (*HyphenationPatterns, error) {
+	h := NewHyphenationPatterns(nil, nil)
+	if err := readHyphenationWords(patterns, h.AddPattern); err != nil {
+		return nil, err
+	}
+	if exceptions != nil {
+		if err := readHyphenationWords(exceptions, h.AddException); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+func readHyphenationWords(r io.Reader, add func(string)) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "%"); idx >= 0 {
+			line = line[:idx]
+		}
+		for _, word := range strings.Fields(line) {
+			add(word)
+		}
+	}
+	return scanner.Err()
+}
+
+// AddPattern adds a Liang pattern such as ".hy3p" or "4m1p"
+func (h *HyphenationPatterns) AddPattern(pattern string) {
+	letters := make([]rune, 0, len(pattern))
+	values := []uint8{0}
+	for _, r := range strings.ToLower(pattern) {
+		if r >= '0' && r <= '9' {
+			values[len(values)-1] = uint8(r - '0')
+		} else {
+			letters = append(letters, r)
+			values = append(values, 0)
+		}
+	}
+	if len(letters) == 0 {
+		return
+	}
+	h.patterns[string(letters)] = values
+	h.maxLen = max(h.maxLen, len(letters))
+}
+
+// AddException adds a word with its hyphens written out, e.g. "ta-ble"
+func (h *HyphenationPatterns) AddException(word string) {
+	points := []int{}
+	letters := make([]rune, 0, len(word))
+	for _, r := range strings.ToLower(word) {
+		if r == '-' {
+			points = append(points, len(letters))
+		} else {
+			letters = append(letters, r)
+		}
+	}
+	h.exceptions[string(letters)] = points
+}
+
+func (h *HyphenationPatterns) Hyphenate(word []rune) []int {
+	lower := make([]rune, len(word))
+	for i, r := range word {
+		if !unicode.IsLetter(r) {
+			return nil
+		}
+		lower[i] = unicode.ToLower(r)
+	}
+	if points, ok := h.exceptions[string(lower)]; ok {
+		return points
+	}
+	if len(word) < h.LeftMin+h.RightMin {
+		return nil
+	}
+	padded := make([]rune, 0, len(lower)+2)
+	padded = append(padded, '.')
+	padded = append(padded, lower...)
+	padded = append(padded, '.')
+	values := make([]uint8, len(padded)+1)
+	for i := range padded {
+		for j := i + 1; j <= len(padded) && j-i <= h.maxLen; j++ {
+			pattern, ok := h.patterns[string(padded[i:j])]
+			if !ok {
+				continue
+			}
+			for k, v := range pattern {
+				values[i+k] = max(values[i+k], v)
+			}
+		}
+	}
+	points := []int{}
+	for i := max(h.LeftMin, 1); i <= len(word)-h.RightMin; i++ {
+		// values[i+1] is the gap between word[i-1] and word[i], offset by
+		// the leading "." of the padded word
+		if values[i+1]%2 == 1 {
+			points = append(points, i)
+		}
+	}
+	return points
+}
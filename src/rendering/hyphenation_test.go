@@ -0,0 +1,50 @@
+/******************************************************************************/
+/* hyphenation_test.go                                                        */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package rendering
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHyphenationPatterns(t *testing.T) {
+	// Patterns from the TeX US English set that hyphenate "hyphenation"
+	h, err := LoadHyphenationPatterns(strings.NewReader(
+		"% comment\nhy3ph he2n hena4 hen5at 1na n2at 1tio 2io o2n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := h.Hyphenate([]rune("Hyphenation"))
+	if want := []int{2, 6}; !slices.Equal(got, want) {
+		t.Errorf("expected %v but got %v", want, got)
+	}
+}
+
+func TestHyphenationExceptionsAndLimits(t *testing.T) {
+	h := NewHyphenationPatterns([]string{"1b"}, []string{"ta-ble"})
+	if got := h.Hyphenate([]rune("table")); !slices.Equal(got, []int{2}) {
+		t.Errorf("expected the exception to be used but got %v", got)
+	}
+	if got := h.Hyphenate([]rune("abbbbb")); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("expected the edges to be left alone but got %v", got)
+	}
+	if got := h.Hyphenate([]rune("ab1bbb")); got != nil {
+		t.Errorf("expected non letters to skip hyphenation but got %v", got)
+	}
+}
+
+func TestHyphenatorForFallsBackToLanguage(t *testing.T) {
+	h := NewHyphenationPatterns(nil, nil)
+	RegisterHyphenator("zz", h)
+	if got, ok := HyphenatorFor("ZZ_ab"); !ok || got != h {
+		t.Error("expected the region to fall back to the base language")
+	}
+	if _, ok := HyphenatorFor("yy"); ok {
+		t.Error("expected no hyphenator for an unknown language")
+	}
+}
@@ -436,6 +436,24 @@ func gltfValidateAccessor(acc gltfAccessorView, componentType gltf.ComponentType
 	return nil
 }
 
+// gltfValidateRotationAccessor allows for the float or normalized integer
+// component types that glTF permits for animation rotation outputs
+func gltfValidateRotationAccessor(acc gltfAccessorView) error {
+	if acc.accessor.Type != gltf.VEC4 {
+		return fmt.Errorf("animation output accessor must be %s", gltf.VEC4)
+	}
+	switch acc.accessor.ComponentType {
+	case gltf.FLOAT:
+		return nil
+	case gltf.BYTE, gltf.UNSIGNED_BYTE, gltf.SHORT, gltf.UNSIGNED_SHORT:
+		if acc.accessor.Normalized {
+			return nil
+		}
+	}
+	return fmt.Errorf("animation output accessor must be %s/%d or normalized integers",
+		gltf.VEC4, gltf.FLOAT)
+}
+
 func gltfValidateAccessorCount(acc gltfAccessorView, count int32, name string) error {
 	if acc.accessor.Count != count {
 		return fmt.Errorf("%s accessor count %d does not match position count %d", name, acc.accessor.Count, count)
@@ -487,6 +505,23 @@ func (a gltfAccessorView) float(element, component int) matrix.Float {
 	return matrix.Float(math.Float32frombits(binary.LittleEndian.Uint32(bytes)))
 }
 
+// normalizedFloat reads the component as a float, converting normalized
+// integer component types into their [-1, 1] or [0, 1] range
+func (a gltfAccessorView) normalizedFloat(element, component int) matrix.Float {
+	switch a.accessor.ComponentType {
+	case gltf.BYTE:
+		return max(matrix.Float(a.int32(element, component))/math.MaxInt8, -1)
+	case gltf.UNSIGNED_BYTE:
+		return matrix.Float(a.int32(element, component)) / math.MaxUint8
+	case gltf.SHORT:
+		return max(matrix.Float(a.int32(element, component))/math.MaxInt16, -1)
+	case gltf.UNSIGNED_SHORT:
+		return matrix.Float(a.int32(element, component)) / math.MaxUint16
+	default:
+		return a.float(element, component)
+	}
+}
+
 func (a gltfAccessorView) int32(element, component int) int32 {
 	bytes := a.componentBytes(element, component)
 	switch a.accessor.ComponentType {
@@ -923,12 +958,40 @@ func gltfReadAnimations(doc *fullGLTF, workers int) ([]load_result.Animation, er
 	return anims, nil
 }
 
+// gltfAnimationEvents reads the named events that are authored in the extras
+// of an animation, they are expected to be in the form of:
+// "extras": { "events": [{ "name": "footstep", "time": 0.25 }] }
+func gltfAnimationEvents(extras map[string]any) []load_result.AnimEvent {
+	list, ok := extras["events"].([]any)
+	if !ok {
+		return nil
+	}
+	events := make([]load_result.AnimEvent, 0, len(list))
+	for i := range list {
+		entry, ok := list[i].(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := entry["name"].(string)
+		time, _ := entry["time"].(float64)
+		if name == "" {
+			continue
+		}
+		events = append(events, load_result.AnimEvent{
+			Name: name,
+			Time: float32(time),
+		})
+	}
+	return events
+}
+
 func gltfReadAnimation(doc *fullGLTF, index int) (load_result.Animation, error) {
 	a := &doc.glTF.Animations[index]
 	anim := load_result.Animation{
 		Name:   a.Name,
 		Frames: make([]load_result.AnimKeyFrame, 0),
 	}
+	anim.Events = gltfAnimationEvents(a.Extras)
 	for j := range a.Channels {
 		c := a.Channels[j]
 		if c.Sampler < 0 || int(c.Sampler) >= len(a.Samplers) {
@@ -957,9 +1020,12 @@ func gltfReadAnimation(doc *fullGLTF, index int) (load_result.Animation, error)
 				return anim, err
 			}
 		case load_result.AnimPathRotation:
-			if err = gltfValidateAccessor(outAcc, gltf.FLOAT, gltf.VEC4, "animation output"); err != nil {
+			if err = gltfValidateRotationAccessor(outAcc); err != nil {
 				return anim, err
 			}
+			if outAcc.accessor.ComponentType != gltf.FLOAT {
+				anim.QuantizedRotations = true
+			}
 		case load_result.AnimPathWeights:
 			// TODO:  Implement reading weights data
 			continue
@@ -983,10 +1049,10 @@ func gltfReadAnimation(doc *fullGLTF, index int) (load_result.Animation, error)
 			case load_result.AnimPathRotation:
 				// glTF stores quaternions as XYZW.
 				bone.Data = matrix.QuaternionFromXYZW([4]matrix.Float{
-					outAcc.float(k, 0),
-					outAcc.float(k, 1),
-					outAcc.float(k, 2),
-					outAcc.float(k, 3),
+					outAcc.normalizedFloat(k, 0),
+					outAcc.normalizedFloat(k, 1),
+					outAcc.normalizedFloat(k, 2),
+					outAcc.normalizedFloat(k, 3),
 				})
 			case load_result.AnimPathScale:
 				bone.Data = matrix.Vec3{
@@ -86,6 +86,7 @@ type Animation struct {
 	Name     string             `json:"name"`
 	Channels []AnimationChannel `json:"channels"`
 	Samplers []AnimationSampler `json:"samplers"`
+	Extras   map[string]any     `json:"extras"`
 }
 
 type TextureId struct {
@@ -157,6 +158,7 @@ type Accessor struct {
 	ByteOffset    int32         `json:"byteOffset"`
 	ComponentType ComponentType `json:"componentType"`
 	Count         int32         `json:"count"`
+	Normalized    bool          `json:"normalized"`
 	Max           matrix.Vec3   `json:"max"`
 	Min           matrix.Vec3   `json:"min"`
 	Type          AccessorType  `json:"type"`
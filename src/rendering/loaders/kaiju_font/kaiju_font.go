@@ -21,6 +21,9 @@ import (
 type KaijuFont struct {
 	Details FontData
 	PNG     []byte
+	// Shaping holds the encoded [opentype.Shaping] tables of the font, it is
+	// appended to the .bin of the font
+	Shaping []byte
 }
 
 type Rect struct {
@@ -31,6 +34,9 @@ type Rect struct {
 }
 
 type Glyph struct {
+	// Index is the glyph index in the source font, glyphs without a character
+	// are stored under a private use rune in Unicode
+	Index       int     `json:"index"`
 	Unicode     int     `json:"unicode"`
 	Advance     float32 `json:"advance"`
 	PlaneBounds Rect    `json:"planeBounds"`
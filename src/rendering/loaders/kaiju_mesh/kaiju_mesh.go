@@ -63,6 +63,9 @@ type KaijuMesh struct {
 	BVH        *graviton.TriangleBVH
 	Animations []KaijuMeshAnimation
 	Joints     []KaijuMeshJoint
+	// VertexAnimations are the animations of this mesh that have been baked
+	// into vertex animation textures, see [BakeVertexAnimation]
+	VertexAnimations []VertexAnimation
 }
 
 type KaijuMeshNode struct {
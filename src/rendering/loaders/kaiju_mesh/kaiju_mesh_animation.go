@@ -7,6 +7,8 @@
 package kaiju_mesh
 
 import (
+	"slices"
+
 	"kaijuengine.com/matrix"
 	"kaijuengine.com/rendering/loaders/load_result"
 )
@@ -14,6 +16,7 @@ import (
 type KaijuMeshJoint struct {
 	Id       int32
 	Parent   int32
+	Name     string
 	Skin     matrix.Mat4
 	Position matrix.Vec3
 	Rotation matrix.Vec3
@@ -23,6 +26,17 @@ type KaijuMeshJoint struct {
 type KaijuMeshAnimation struct {
 	Name   string
 	Frames []AnimKeyFrame
+	Events []AnimEvent
+	// QuantizedRotations marks that the rotation keys have been quantized
+	// (see [AnimationCompression]) and can be stored as 16 bit integers
+	QuantizedRotations bool
+}
+
+// AnimEvent is a named marker (footstep, hit frame, etc.) that is placed at a
+// time (in seconds from the start of the animation) within the animation
+type AnimEvent struct {
+	Name string
+	Time float32
 }
 
 type AnimKeyFrame struct {
@@ -43,6 +57,7 @@ func (j *KaijuMeshJoint) fromLoadResult(res *load_result.Result, r *load_result.
 	j.Skin = r.Skin
 	n := &res.Nodes[j.Id]
 	j.Parent = int32(n.Parent)
+	j.Name = n.Name
 	j.Position = n.Position
 	j.Rotation = n.Rotation.ToEuler()
 	j.Scale = n.Scale
@@ -50,10 +65,42 @@ func (j *KaijuMeshJoint) fromLoadResult(res *load_result.Result, r *load_result.
 
 func (a *KaijuMeshAnimation) fromLoadResult(r *load_result.Animation) {
 	a.Name = r.Name
+	a.QuantizedRotations = r.QuantizedRotations
 	a.Frames = make([]AnimKeyFrame, len(r.Frames))
 	for i := range r.Frames {
 		a.Frames[i].fromLoadResult(&r.Frames[i])
 	}
+	for i := range r.Events {
+		a.AddEvent(r.Events[i].Name, r.Events[i].Time)
+	}
+}
+
+// AddEvent will insert a named event into the animation, events are kept
+// sorted by their time
+func (a *KaijuMeshAnimation) AddEvent(name string, time float32) {
+	idx, _ := slices.BinarySearchFunc(a.Events, time, func(e AnimEvent, t float32) int {
+		if e.Time <= t {
+			return -1
+		}
+		return 1
+	})
+	a.Events = slices.Insert(a.Events, idx, AnimEvent{Name: name, Time: time})
+}
+
+// RemoveEvent removes all events with the matching name and time
+func (a *KaijuMeshAnimation) RemoveEvent(name string, time float32) {
+	a.Events = slices.DeleteFunc(a.Events, func(e AnimEvent) bool {
+		return e.Name == name && e.Time == time
+	})
+}
+
+// Duration is the total length of the animation in seconds
+func (a *KaijuMeshAnimation) Duration() float32 {
+	total := float32(0)
+	for i := range a.Frames {
+		total += a.Frames[i].Time
+	}
+	return total
 }
 
 func (f *AnimKeyFrame) fromLoadResult(r *load_result.AnimKeyFrame) {
@@ -0,0 +1,193 @@
+/******************************************************************************/
+/* kaiju_mesh_animation_compression.go                                        */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package kaiju_mesh
+
+import (
+	"math"
+
+	"kaijuengine.com/matrix"
+)
+
+// AnimationCompression controls how animations are reduced when they are
+// imported. A key is only removed from a track if the track, when sampled
+// without the key, stays within the error tolerance of the original.
+type AnimationCompression struct {
+	// TranslationError is the maximum distance (in the units of the mesh)
+	// that a sampled translation is allowed to drift from the original
+	TranslationError matrix.Float
+	// RotationError is the maximum angle (in degrees) that a sampled
+	// rotation is allowed to drift from the original
+	RotationError matrix.Float
+	// ScaleError is the maximum difference that a sampled scale is allowed
+	// to drift from the original
+	ScaleError matrix.Float
+	// QuantizeRotations stores the rotation keys as 16 bit normalized
+	// integers rather than 32 bit floats
+	QuantizeRotations bool
+}
+
+func DefaultAnimationCompression() AnimationCompression {
+	return AnimationCompression{
+		TranslationError:  0.001,
+		RotationError:     0.1,
+		ScaleError:        0.001,
+		QuantizeRotations: true,
+	}
+}
+
+// IsEnabled reports if the compression settings would change the animation
+func (c AnimationCompression) IsEnabled() bool {
+	return c.TranslationError > 0 || c.RotationError > 0 ||
+		c.ScaleError > 0 || c.QuantizeRotations
+}
+
+// Tolerance returns the allowed error for the given animation path
+func (c AnimationCompression) Tolerance(path AnimationPathType) matrix.Float {
+	switch path {
+	case AnimPathTranslation:
+		return c.TranslationError
+	case AnimPathRotation:
+		return c.RotationError
+	case AnimPathScale:
+		return c.ScaleError
+	}
+	return 0
+}
+
+// CompressAnimation removes the keys from each track of the animation that can
+// be reconstructed by interpolating the remaining keys within the tolerance of
+// the compression settings, and optionally quantizes the rotation keys
+func CompressAnimation(anim KaijuMeshAnimation, c AnimationCompression) KaijuMeshAnimation {
+	if !c.IsEnabled() || len(anim.Frames) == 0 {
+		return anim
+	}
+	tracks := anim.Tracks()
+	for i := range tracks {
+		CompressTrack(&tracks[i], c)
+	}
+	out := AnimationFromTracks(anim.Name, tracks, anim.Duration())
+	out.Events = append([]AnimEvent(nil), anim.Events...)
+	out.QuantizedRotations = anim.QuantizedRotations ||
+		(c.QuantizeRotations && hasRotationTrack(tracks))
+	return out
+}
+
+// CompressTrack reduces the keys of a single track in place, see
+// [CompressAnimation] for more details
+func CompressTrack(track *AnimTrack, c AnimationCompression) {
+	if c.QuantizeRotations && track.PathType == AnimPathRotation {
+		for i := range track.Values {
+			track.Values[i] = QuantizeRotation(track.Values[i])
+		}
+	}
+	if track.Interpolation == AnimInterpolateCubicSpline {
+		return
+	}
+	keep := ReduceKeys(track, c.Tolerance(track.PathType))
+	if len(keep) == len(track.Times) {
+		return
+	}
+	times := make([]float32, len(keep))
+	values := make([][4]matrix.Float, len(keep))
+	for i, k := range keep {
+		times[i] = track.Times[k]
+		values[i] = track.Values[k]
+	}
+	track.Times = times
+	track.Values = values
+}
+
+// ReduceKeys returns the indexes of the keys of the track that need to be kept
+// so that sampling the track never drifts more than the tolerance from the
+// original keys. A track that doesn't change is reduced to a single key.
+func ReduceKeys(track *AnimTrack, tolerance matrix.Float) []int {
+	count := len(track.Times)
+	if count == 0 {
+		return []int{}
+	}
+	constant := true
+	for i := 1; i < count && constant; i++ {
+		constant = animValueError(track.PathType, track.Values[0], track.Values[i]) <= tolerance
+	}
+	if constant {
+		return []int{0}
+	}
+	keep := []int{0}
+	anchor := 0
+	for end := 2; end < count; end++ {
+		if !track.segmentFits(anchor, end, tolerance) {
+			anchor = end - 1
+			keep = append(keep, anchor)
+		}
+	}
+	return append(keep, count-1)
+}
+
+// segmentFits checks that all of the keys between from and to can be sampled
+// from the keys at from and to within the tolerance
+func (t *AnimTrack) segmentFits(from, to int, tolerance matrix.Float) bool {
+	span := t.Times[to] - t.Times[from]
+	for k := from + 1; k < to; k++ {
+		var sampled [4]matrix.Float
+		if t.Interpolation == AnimInterpolateStep || span <= 0 {
+			sampled = t.Values[from]
+		} else {
+			f := matrix.Float((t.Times[k] - t.Times[from]) / span)
+			sampled = interpolateAnimValue(t.PathType, t.Values[from], t.Values[to], f)
+		}
+		if animValueError(t.PathType, sampled, t.Values[k]) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// QuantizeRotation snaps each component of the (normalized) quaternion to the
+// nearest value that can be represented by a 16 bit normalized integer
+func QuantizeRotation(q [4]matrix.Float) [4]matrix.Float {
+	n := matrix.Quaternion(q)
+	n.Normalize()
+	for i := range n {
+		n[i] = matrix.Float(QuantizeNormalizedInt16(n[i])) / math.MaxInt16
+	}
+	return n
+}
+
+// QuantizeNormalizedInt16 converts a value in the range of [-1, 1] to the
+// 16 bit integer representation used for normalized glTF accessors
+func QuantizeNormalizedInt16(v matrix.Float) int16 {
+	v = matrix.Clamp(v, -1, 1)
+	return int16(math.Round(float64(v) * math.MaxInt16))
+}
+
+func animValueError(path AnimationPathType, a, b [4]matrix.Float) matrix.Float {
+	if path == AnimPathRotation {
+		// The angle is found from the chord between the quaternions rather
+		// than the acos of their dot product, which is too imprecise near 1
+		sign := 1.0
+		if a[0]*b[0]+a[1]*b[1]+a[2]*b[2]+a[3]*b[3] < 0 {
+			sign = -1
+		}
+		chord := 0.0
+		for i := range a {
+			d := float64(a[i]) - sign*float64(b[i])
+			chord += d * d
+		}
+		half := min(math.Sqrt(chord)*0.5, 1)
+		return matrix.Float(4 * math.Asin(half) * 180 / math.Pi)
+	}
+	return matrix.Vec3FromSlice(a[:]).Distance(matrix.Vec3FromSlice(b[:]))
+}
+
+func hasRotationTrack(tracks []AnimTrack) bool {
+	for i := range tracks {
+		if tracks[i].PathType == AnimPathRotation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+/******************************************************************************/
+/* kaiju_mesh_animation_compression_test.go                                   */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package kaiju_mesh
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering"
+)
+
+func TestReduceKeysLinearTrack(t *testing.T) {
+	track := AnimTrack{PathType: AnimPathTranslation, Interpolation: AnimInterpolateLinear}
+	for i := range 11 {
+		x := matrix.Float(i) * 0.1
+		track.Times = append(track.Times, float32(x))
+		track.Values = append(track.Values, [4]matrix.Float{x * 2, 1, 0, 0})
+	}
+	if keep := ReduceKeys(&track, 0.001); !slices.Equal(keep, []int{0, 10}) {
+		t.Fatalf("kept keys = %v, want [0 10]", keep)
+	}
+}
+
+func TestReduceKeysConstantTrack(t *testing.T) {
+	track := AnimTrack{
+		PathType:      AnimPathScale,
+		Interpolation: AnimInterpolateLinear,
+		Times:         []float32{0, 0.5, 1},
+		Values:        [][4]matrix.Float{{1, 1, 1, 0}, {1, 1, 1, 0}, {1, 1, 1, 0}},
+	}
+	if keep := ReduceKeys(&track, 0.001); !slices.Equal(keep, []int{0}) {
+		t.Fatalf("kept keys = %v, want [0]", keep)
+	}
+}
+
+func TestReduceKeysKeepsCorners(t *testing.T) {
+	track := AnimTrack{
+		PathType:      AnimPathTranslation,
+		Interpolation: AnimInterpolateLinear,
+		Times:         []float32{0, 0.25, 0.5, 0.75, 1},
+		Values:        [][4]matrix.Float{{0}, {1}, {2}, {1}, {0}},
+	}
+	if keep := ReduceKeys(&track, 0.001); !slices.Equal(keep, []int{0, 2, 4}) {
+		t.Fatalf("kept keys = %v, want [0 2 4]", keep)
+	}
+}
+
+func TestCompressAnimationWithinError(t *testing.T) {
+	const keys = 61
+	translation := AnimTrack{NodeIndex: 0, PathType: AnimPathTranslation, Interpolation: AnimInterpolateLinear}
+	rotation := AnimTrack{NodeIndex: 1, PathType: AnimPathRotation, Interpolation: AnimInterpolateLinear}
+	scale := AnimTrack{NodeIndex: 1, PathType: AnimPathScale, Interpolation: AnimInterpolateLinear}
+	for i := range keys {
+		time := float32(i) / (keys - 1)
+		s := matrix.Float(math.Sin(float64(time) * math.Pi))
+		translation.Times = append(translation.Times, time)
+		translation.Values = append(translation.Values, [4]matrix.Float{s, 0, matrix.Float(time), 0})
+		rotation.Times = append(rotation.Times, time)
+		rotation.Values = append(rotation.Values,
+			matrix.QuaternionFromEuler(matrix.Vec3{0, 90 * matrix.Float(time), 0}))
+		scale.Times = append(scale.Times, time)
+		scale.Values = append(scale.Values, [4]matrix.Float{1, 1, 1, 0})
+	}
+	anim := AnimationFromTracks("wave", []AnimTrack{translation, rotation, scale}, 1)
+	c := DefaultAnimationCompression()
+	compressed := CompressAnimation(anim, c)
+	if !compressed.QuantizedRotations {
+		t.Fatal("expected the rotations to be quantized")
+	}
+	before, after := anim.Tracks(), compressed.Tracks()
+	if len(after) != len(before) {
+		t.Fatalf("tracks = %d, want %d", len(after), len(before))
+	}
+	totalBefore, totalAfter := 0, 0
+	for i := range before {
+		totalBefore += len(before[i].Times)
+		totalAfter += len(after[i].Times)
+		tolerance := c.Tolerance(before[i].PathType)
+		if before[i].PathType == AnimPathRotation {
+			// Quantization adds a tiny amount of error on top of the reduction
+			tolerance += 0.01
+		}
+		for s := range 200 {
+			time := float32(s) / 199
+			want := before[i].Sample(time)
+			got := after[i].Sample(time)
+			if err := animValueError(before[i].PathType, want, got); err > tolerance*1.01 {
+				t.Fatalf("track %d at %f drifted by %f, want at most %f", i, time, err, tolerance)
+			}
+		}
+	}
+	if totalAfter >= totalBefore/2 {
+		t.Fatalf("keys after compression = %d, want less than half of %d", totalAfter, totalBefore)
+	}
+	if after[2].PathType != AnimPathScale || len(after[2].Times) != 1 {
+		t.Fatalf("constant scale track = %#v, want a single key", after[2])
+	}
+	if !matrix.Approx(compressed.Duration(), anim.Duration()) {
+		t.Fatalf("duration = %f, want %f", compressed.Duration(), anim.Duration())
+	}
+}
+
+func TestKaijuMeshSerializeQuantizedRotations(t *testing.T) {
+	rotations := []matrix.Quaternion{
+		matrix.QuaternionFromEuler(matrix.Vec3{0, 0, 0}),
+		matrix.QuaternionFromEuler(matrix.Vec3{10, 45, -30}),
+		matrix.QuaternionFromEuler(matrix.Vec3{0, 170, 0}),
+	}
+	track := AnimTrack{NodeIndex: 0, PathType: AnimPathRotation, Interpolation: AnimInterpolateLinear}
+	for i := range rotations {
+		track.Times = append(track.Times, float32(i)*0.5)
+		track.Values = append(track.Values, QuantizeRotation(rotations[i]))
+	}
+	anim := AnimationFromTracks("turn", []AnimTrack{track}, 1)
+	anim.QuantizedRotations = true
+	km := KaijuMesh{
+		Name: "quantized",
+		Verts: []rendering.Vertex{
+			{Normal: matrix.Vec3Forward(), Color: matrix.ColorWhite(), JointWeights: matrix.Vec4{1, 0, 0, 0}},
+			{Position: matrix.Vec3{1, 0, 0}, Normal: matrix.Vec3Forward(), Color: matrix.ColorWhite(), JointWeights: matrix.Vec4{1, 0, 0, 0}},
+			{Position: matrix.Vec3{0, 1, 0}, Normal: matrix.Vec3Forward(), Color: matrix.ColorWhite(), JointWeights: matrix.Vec4{1, 0, 0, 0}},
+		},
+		Indexes:    []uint32{0, 1, 2},
+		Joints:     []KaijuMeshJoint{{Id: 0, Parent: -1, Skin: matrix.Mat4Identity(), Scale: matrix.Vec3One()}},
+		Animations: []KaijuMeshAnimation{anim},
+	}
+	data, err := km.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Deserialize(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Animations) != 1 || !loaded.Animations[0].QuantizedRotations {
+		t.Fatalf("animations = %#v, want a single animation with quantized rotations", loaded.Animations)
+	}
+	tracks := loaded.Animations[0].Tracks()
+	if len(tracks) != 1 || len(tracks[0].Values) != len(rotations) {
+		t.Fatalf("tracks = %#v, want a single rotation track with %d keys", tracks, len(rotations))
+	}
+	for i := range rotations {
+		if err := animValueError(AnimPathRotation, rotations[i], tracks[0].Values[i]); err > 0.01 {
+			t.Fatalf("rotation %d is off by %f degrees", i, err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+/******************************************************************************/
+/* kaiju_mesh_animation_track.go                                              */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package kaiju_mesh
+
+import (
+	"slices"
+	"sort"
+
+	"kaijuengine.com/matrix"
+)
+
+// AnimTrack holds all of the keys of a single path (translation, rotation,
+// scale) of a single node within an animation. The key times are absolute (in
+// seconds from the start of the animation) rather than the per-frame duration
+// that is used by [AnimKeyFrame].
+type AnimTrack struct {
+	NodeIndex     int
+	PathType      AnimationPathType
+	Interpolation AnimationInterpolation
+	Times         []float32
+	Values        [][4]matrix.Float
+}
+
+// Tracks splits the key frames of the animation into a track for each of the
+// animated node paths, ordered by node and then path
+func (a *KaijuMeshAnimation) Tracks() []AnimTrack {
+	type trackKey struct {
+		node int
+		path AnimationPathType
+	}
+	absTimes := animationAbsoluteTimes(a)
+	lookup := make(map[trackKey]int)
+	tracks := []AnimTrack{}
+	for f := range a.Frames {
+		for b := range a.Frames[f].Bones {
+			bone := &a.Frames[f].Bones[b]
+			key := trackKey{bone.NodeIndex, bone.PathType}
+			idx, ok := lookup[key]
+			if !ok {
+				idx = len(tracks)
+				lookup[key] = idx
+				tracks = append(tracks, AnimTrack{
+					NodeIndex:     bone.NodeIndex,
+					PathType:      bone.PathType,
+					Interpolation: bone.Interpolation,
+				})
+			}
+			tracks[idx].Times = append(tracks[idx].Times, absTimes[f])
+			tracks[idx].Values = append(tracks[idx].Values, bone.Data)
+		}
+	}
+	slices.SortFunc(tracks, func(a, b AnimTrack) int {
+		if a.NodeIndex == b.NodeIndex {
+			return a.PathType - b.PathType
+		}
+		return a.NodeIndex - b.NodeIndex
+	})
+	return tracks
+}
+
+// Sample returns the value of the track at the given time, times outside of
+// the range of the keys are clamped to the first or last key
+func (t *AnimTrack) Sample(time float32) [4]matrix.Float {
+	if len(t.Times) == 0 {
+		return [4]matrix.Float{}
+	}
+	next := sort.Search(len(t.Times), func(i int) bool { return t.Times[i] > time })
+	if next == 0 {
+		return t.Values[0]
+	} else if next == len(t.Times) {
+		return t.Values[len(t.Values)-1]
+	}
+	prev := next - 1
+	if t.Interpolation == AnimInterpolateStep {
+		return t.Values[prev]
+	}
+	span := t.Times[next] - t.Times[prev]
+	if span <= 0 {
+		return t.Values[next]
+	}
+	return interpolateAnimValue(t.PathType, t.Values[prev],
+		t.Values[next], matrix.Float((time-t.Times[prev])/span))
+}
+
+// AnimationFromTracks builds the key frames of an animation from the given
+// tracks, keys that share the same time are placed within the same frame. The
+// duration is used to pad the last frame so that the length of the animation
+// is preserved when the tracks end before the animation does.
+func AnimationFromTracks(name string, tracks []AnimTrack, duration float32) KaijuMeshAnimation {
+	times := []float32{}
+	for i := range tracks {
+		times = append(times, tracks[i].Times...)
+	}
+	slices.Sort(times)
+	times = slices.CompactFunc(times, func(a, b float32) bool {
+		return max(a, b)-min(a, b) <= animTimeEpsilon
+	})
+	anim := KaijuMeshAnimation{
+		Name:   name,
+		Frames: make([]AnimKeyFrame, len(times)),
+	}
+	for i := range tracks {
+		t := &tracks[i]
+		for k := range t.Times {
+			f := animFrameForTime(times, t.Times[k])
+			anim.Frames[f].Bones = append(anim.Frames[f].Bones, AnimBone{
+				NodeIndex:     t.NodeIndex,
+				PathType:      t.PathType,
+				Interpolation: t.Interpolation,
+				Data:          t.Values[k],
+			})
+		}
+	}
+	for i := 0; i < len(times)-1; i++ {
+		anim.Frames[i].Time = times[i+1] - times[i]
+	}
+	if last := len(times) - 1; last >= 0 && duration > times[last] {
+		anim.Frames[last].Time = duration - times[last]
+	}
+	return anim
+}
+
+const animTimeEpsilon = 0.00001
+
+func animFrameForTime(times []float32, time float32) int {
+	idx := sort.Search(len(times), func(i int) bool { return times[i] >= time-animTimeEpsilon })
+	return min(idx, len(times)-1)
+}
+
+func interpolateAnimValue(path AnimationPathType, a, b [4]matrix.Float, t matrix.Float) [4]matrix.Float {
+	if path == AnimPathRotation {
+		return matrix.QuaternionSlerp(matrix.Quaternion(a), matrix.Quaternion(b), t)
+	}
+	return matrix.Vec4Lerp(matrix.Vec4(a), matrix.Vec4(b), t)
+}
@@ -0,0 +1,178 @@
+/******************************************************************************/
+/* kaiju_mesh_bone_map.go                                                     */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package kaiju_mesh
+
+import (
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"kaijuengine.com/engine"
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/platform/profiler/tracing"
+)
+
+// BoneMap is the asset used to retarget animations that were authored on one
+// skeleton so that they can be played on another. Bones maps the joint name
+// of the source skeleton to the joint name of the target skeleton. Source
+// joints that are not in the map are matched to a target joint with the same
+// name (case-insensitive), unless Strict is set, in which case they are
+// dropped from the retargeted animation.
+type BoneMap struct {
+	Bones  map[string]string
+	Strict bool
+}
+
+func NewBoneMap() BoneMap {
+	return BoneMap{Bones: make(map[string]string)}
+}
+
+func DeserializeBoneMap(data []byte) (BoneMap, error) {
+	bm := NewBoneMap()
+	err := json.Unmarshal(data, &bm)
+	if bm.Bones == nil {
+		bm.Bones = make(map[string]string)
+	}
+	return bm, err
+}
+
+func (b BoneMap) Serialize() ([]byte, error) { return json.Marshal(b) }
+
+// TargetName returns the name of the target joint that the source joint name
+// should drive, or false if the source joint should not be retargeted
+func (b BoneMap) TargetName(source string) (string, bool) {
+	if target, ok := b.Bones[source]; ok {
+		return target, target != ""
+	}
+	// Sorted so that keys that only differ by case always resolve the same
+	for _, k := range slices.Sorted(maps.Keys(b.Bones)) {
+		if strings.EqualFold(k, source) {
+			v := b.Bones[k]
+			return v, v != ""
+		}
+	}
+	return source, !b.Strict && source != ""
+}
+
+type retargetJoint struct {
+	source *KaijuMeshJoint
+	target *KaijuMeshJoint
+	// ratio is the proportion of the target bone length to the source bone
+	// length, used to scale translation keys
+	ratio matrix.Float
+}
+
+// RetargetAnimation converts an animation authored for the source skeleton so
+// that it drives the joints of the target skeleton. Rotation keys are applied
+// as a delta from the source bind pose onto the target bind pose, and
+// translation keys are scaled by the proportions of the matching bones so that
+// a clip authored on a tall skeleton doesn't over-stride on a short one.
+func RetargetAnimation(anim KaijuMeshAnimation, source, target []KaijuMeshJoint, boneMap BoneMap) KaijuMeshAnimation {
+	mapping := make(map[int]retargetJoint, len(source))
+	for i := range source {
+		s := &source[i]
+		name, ok := boneMap.TargetName(s.Name)
+		if !ok {
+			continue
+		}
+		t := findJointByName(target, name)
+		if t == nil {
+			continue
+		}
+		ratio := matrix.Float(1)
+		if sLen := s.Position.Length(); sLen > matrix.Tiny {
+			ratio = t.Position.Length() / sLen
+		}
+		mapping[int(s.Id)] = retargetJoint{source: s, target: t, ratio: ratio}
+	}
+	out := KaijuMeshAnimation{
+		Name:   anim.Name,
+		Frames: make([]AnimKeyFrame, len(anim.Frames)),
+		Events: append([]AnimEvent(nil), anim.Events...),
+	}
+	for i := range anim.Frames {
+		frame := &anim.Frames[i]
+		out.Frames[i].Time = frame.Time
+		out.Frames[i].Bones = make([]AnimBone, 0, len(frame.Bones))
+		for j := range frame.Bones {
+			bone := frame.Bones[j]
+			m, ok := mapping[bone.NodeIndex]
+			if !ok {
+				continue
+			}
+			bone.NodeIndex = int(m.target.Id)
+			bone.Data = m.retarget(bone.PathType, bone.Data)
+			out.Frames[i].Bones = append(out.Frames[i].Bones, bone)
+		}
+	}
+	return out
+}
+
+func (m retargetJoint) retarget(path AnimationPathType, data [4]matrix.Float) [4]matrix.Float {
+	switch path {
+	case AnimPathRotation:
+		sourceBind := matrix.QuaternionFromEuler(m.source.Rotation)
+		targetBind := matrix.QuaternionFromEuler(m.target.Rotation)
+		sourceBind.Inverse()
+		delta := sourceBind.Multiply(matrix.Quaternion(data))
+		q := targetBind.Multiply(delta)
+		q.Normalize()
+		return q
+	case AnimPathTranslation:
+		offset := matrix.Vec3FromSlice(data[:]).Subtract(m.source.Position)
+		return m.target.Position.Add(offset.Scale(m.ratio)).AsAligned16()
+	}
+	return data
+}
+
+func findJointByName(joints []KaijuMeshJoint, name string) *KaijuMeshJoint {
+	for i := range joints {
+		if joints[i].Name == name {
+			return &joints[i]
+		}
+	}
+	for i := range joints {
+		if strings.EqualFold(joints[i].Name, name) {
+			return &joints[i]
+		}
+	}
+	return nil
+}
+
+func ReadBoneMap(id string, host *engine.Host) (BoneMap, error) {
+	defer tracing.NewRegion("kaiju_mesh.ReadBoneMap").End()
+	data, err := host.AssetDatabase().Read(id)
+	if err != nil {
+		slog.Error("failed to read the bone map", "id", id, "error", err)
+		return BoneMap{}, err
+	}
+	return DeserializeBoneMap(data)
+}
+
+// ReadRetargetedAnimations reads the animations from the mesh with the given
+// id and retargets them onto the target joints using the bone map with the
+// given id. If the bone map id is empty, joints are matched by name.
+func ReadRetargetedAnimations(meshId, boneMapId string, target []KaijuMeshJoint, host *engine.Host) ([]KaijuMeshAnimation, error) {
+	defer tracing.NewRegion("kaiju_mesh.ReadRetargetedAnimations").End()
+	src, err := ReadMesh(meshId, host)
+	if err != nil {
+		return nil, err
+	}
+	boneMap := NewBoneMap()
+	if boneMapId != "" {
+		if boneMap, err = ReadBoneMap(boneMapId, host); err != nil {
+			return nil, err
+		}
+	}
+	out := make([]KaijuMeshAnimation, len(src.Animations))
+	for i := range src.Animations {
+		out[i] = RetargetAnimation(src.Animations[i], src.Joints, target, boneMap)
+	}
+	return out, nil
+}
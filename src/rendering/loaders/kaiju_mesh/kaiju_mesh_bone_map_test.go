@@ -0,0 +1,93 @@
+/******************************************************************************/
+/* kaiju_mesh_bone_map_test.go                                                */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package kaiju_mesh
+
+import (
+	"testing"
+
+	"kaijuengine.com/matrix"
+)
+
+func TestBoneMapTargetName(t *testing.T) {
+	bm, err := DeserializeBoneMap([]byte(`{"Bones":{"mixamorig:Hips":"pelvis","Tail":""}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := bm.TargetName("mixamorig:hips"); !ok || name != "pelvis" {
+		t.Fatalf("TargetName(hips) = %q, %v, want pelvis", name, ok)
+	}
+	if _, ok := bm.TargetName("Tail"); ok {
+		t.Fatal("expected an empty mapping to exclude the joint")
+	}
+	if name, ok := bm.TargetName("Head"); !ok || name != "Head" {
+		t.Fatalf("TargetName(Head) = %q, %v, want Head", name, ok)
+	}
+	bm.Strict = true
+	if _, ok := bm.TargetName("Head"); ok {
+		t.Fatal("expected a strict bone map to exclude unmapped joints")
+	}
+}
+
+func TestBoneMapTargetNameCaseCollision(t *testing.T) {
+	bm := NewBoneMap()
+	bm.Bones["Spine"] = "spine_a"
+	bm.Bones["SPINE"] = "spine_b"
+	for range 20 {
+		if name, _ := bm.TargetName("spine"); name != "spine_b" {
+			t.Fatalf("TargetName(spine) = %q, want the first key in sorted order", name)
+		}
+	}
+}
+
+func TestRetargetAnimation(t *testing.T) {
+	source := []KaijuMeshJoint{
+		{Id: 3, Parent: -1, Name: "src_root", Position: matrix.Vec3{0, 2, 0}},
+		{Id: 4, Parent: 3, Name: "src_arm", Position: matrix.Vec3{0, 1, 0}, Rotation: matrix.Vec3{0, 0, 90}},
+		{Id: 5, Parent: 4, Name: "src_extra"},
+	}
+	target := []KaijuMeshJoint{
+		{Id: 0, Parent: -1, Name: "Root", Position: matrix.Vec3{0, 1, 0}},
+		{Id: 1, Parent: 0, Name: "Arm", Position: matrix.Vec3{0, 0.5, 0}},
+	}
+	bm := NewBoneMap()
+	bm.Bones["src_root"] = "root"
+	bm.Bones["src_arm"] = "arm"
+	turn := matrix.QuaternionFromEuler(matrix.Vec3{0, 0, 135})
+	anim := KaijuMeshAnimation{
+		Name: "wave",
+		Frames: []AnimKeyFrame{{
+			Time: 1,
+			Bones: []AnimBone{
+				{NodeIndex: 3, PathType: AnimPathTranslation, Data: matrix.Vec3{1, 2, 0}.AsAligned16()},
+				{NodeIndex: 4, PathType: AnimPathRotation, Data: turn},
+				{NodeIndex: 5, PathType: AnimPathRotation, Data: matrix.QuaternionIdentity()},
+			},
+		}},
+		Events: []AnimEvent{{Name: "wave", Time: 0.5}},
+	}
+	out := RetargetAnimation(anim, source, target, bm)
+	if out.Name != "wave" || len(out.Events) != 1 || len(out.Frames) != 1 {
+		t.Fatalf("retargeted animation = %#v", out)
+	}
+	bones := out.Frames[0].Bones
+	if len(bones) != 2 {
+		t.Fatalf("expected the unmapped joint to be dropped, got %d bones", len(bones))
+	}
+	if bones[0].NodeIndex != 0 || bones[1].NodeIndex != 1 {
+		t.Fatalf("node indexes = %d, %d, want 0, 1", bones[0].NodeIndex, bones[1].NodeIndex)
+	}
+	// The target root is half the height of the source, so is the stride
+	pos := matrix.Vec3FromSlice(bones[0].Data[:])
+	if !matrix.Vec3Approx(pos, matrix.Vec3{0.5, 1, 0}) {
+		t.Fatalf("retargeted translation = %v, want (0.5, 1, 0)", pos)
+	}
+	// 45 degrees past the source bind pose, applied to the target bind pose
+	rot := matrix.Quaternion(bones[1].Data).ToEuler()
+	if !matrix.Vec3ApproxTo(rot, matrix.Vec3{0, 0, 45}, 0.01) {
+		t.Fatalf("retargeted rotation = %v, want (0, 0, 45)", rot)
+	}
+}
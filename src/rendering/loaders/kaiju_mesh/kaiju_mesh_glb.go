@@ -29,6 +29,7 @@ const (
 	glbArrayBufferTarget        = 34962
 	glbElementArrayBufferTarget = 34963
 
+	glbComponentShort         = 5122
 	glbComponentUnsignedShort = 5123
 	glbComponentUnsignedInt   = 5125
 	glbComponentFloat         = 5126
@@ -111,6 +112,7 @@ type glbAccessor struct {
 	ByteOffset    int       `json:"byteOffset,omitempty"`
 	ComponentType int       `json:"componentType"`
 	Count         int       `json:"count"`
+	Normalized    bool      `json:"normalized,omitempty"`
 	Max           []float32 `json:"max,omitempty"`
 	Min           []float32 `json:"min,omitempty"`
 	Type          string    `json:"type"`
@@ -150,10 +152,20 @@ type glbAnimationSampler struct {
 	Output        int    `json:"output"`
 }
 
+type glbAnimationEvent struct {
+	Name string  `json:"name"`
+	Time float32 `json:"time"`
+}
+
+type glbAnimationExtras struct {
+	Events []glbAnimationEvent `json:"events,omitempty"`
+}
+
 type glbAnimation struct {
 	Name     string                `json:"name,omitempty"`
 	Channels []glbAnimationChannel `json:"channels,omitempty"`
 	Samplers []glbAnimationSampler `json:"samplers,omitempty"`
+	Extras   *glbAnimationExtras   `json:"extras,omitempty"`
 }
 
 type glbDocument struct {
@@ -199,6 +211,8 @@ type glbKaijuMeshExtra struct {
 	Node     int          `json:"node"`
 	Material string       `json:"material,omitempty"`
 	Blobs    *glbBlobRefs `json:"blobs,omitempty"`
+
+	VertexAnimations []VertexAnimation `json:"vertexAnimations,omitempty"`
 }
 
 type glbWriter struct {
@@ -298,6 +312,8 @@ func serializeGLBSet(set KaijuMeshSet, options SerializeOptions) ([]byte, error)
 			Mesh:     meshIdx,
 			Node:     nodeIdx,
 			Material: mesh.Material,
+
+			VertexAnimations: mesh.VertexAnimations,
 		}
 		extras.Meshes = append(extras.Meshes, extra)
 	}
@@ -402,7 +418,7 @@ func (w *glbWriter) buildNodes(k KaijuMesh) int {
 			continue
 		}
 		node := &w.doc.Nodes[j.Id]
-		node.Name = fmt.Sprintf("Joint_%d", j.Id)
+		node.Name = jointNodeName(j)
 		node.Translation = vec3JSON(j.Position)
 		node.Scale = vec3JSON(j.Scale)
 		q := matrix.QuaternionFromEuler(j.Rotation)
@@ -457,7 +473,7 @@ func (w *glbWriter) buildSkeletonNodes(k KaijuMesh) {
 			continue
 		}
 		node := &w.doc.Nodes[j.Id]
-		node.Name = fmt.Sprintf("Joint_%d", j.Id)
+		node.Name = jointNodeName(j)
 		node.Translation = vec3JSON(j.Position)
 		node.Scale = vec3JSON(j.Scale)
 		q := matrix.QuaternionFromEuler(j.Rotation)
@@ -490,6 +506,14 @@ func (w *glbWriter) addAnimations(k KaijuMesh) {
 	for i := range k.Animations {
 		anim := &k.Animations[i]
 		out := glbAnimation{Name: anim.Name}
+		if len(anim.Events) > 0 {
+			out.Extras = &glbAnimationExtras{
+				Events: make([]glbAnimationEvent, len(anim.Events)),
+			}
+			for e := range anim.Events {
+				out.Extras.Events[e] = glbAnimationEvent(anim.Events[e])
+			}
+		}
 		absTimes := animationAbsoluteTimes(anim)
 		type channelKey struct {
 			node          int
@@ -538,10 +562,17 @@ func (w *glbWriter) addAnimations(k KaijuMesh) {
 					valueBytes = appendF32(valueBytes, float32(bone.Data[2]))
 				case AnimPathRotation:
 					q := matrix.Quaternion(bone.Data)
-					valueBytes = appendF32(valueBytes, float32(q.X()))
-					valueBytes = appendF32(valueBytes, float32(q.Y()))
-					valueBytes = appendF32(valueBytes, float32(q.Z()))
-					valueBytes = appendF32(valueBytes, float32(q.W()))
+					if anim.QuantizedRotations {
+						valueBytes = appendNormalizedI16(valueBytes, q.X())
+						valueBytes = appendNormalizedI16(valueBytes, q.Y())
+						valueBytes = appendNormalizedI16(valueBytes, q.Z())
+						valueBytes = appendNormalizedI16(valueBytes, q.W())
+					} else {
+						valueBytes = appendF32(valueBytes, float32(q.X()))
+						valueBytes = appendF32(valueBytes, float32(q.Y()))
+						valueBytes = appendF32(valueBytes, float32(q.Z()))
+						valueBytes = appendF32(valueBytes, float32(q.W()))
+					}
 				default:
 					continue
 				}
@@ -552,8 +583,13 @@ func (w *glbWriter) addAnimations(k KaijuMesh) {
 			}
 			input := w.addAccessor(w.addBufferView(timeBytes, glbArrayBufferTarget),
 				glbComponentFloat, len(frames), glbTypeScalar, nil, nil)
+			outputComponent := glbComponentFloat
+			if key.path == AnimPathRotation && anim.QuantizedRotations {
+				outputComponent = glbComponentShort
+			}
 			output := w.addAccessor(w.addBufferView(valueBytes, glbArrayBufferTarget),
-				glbComponentFloat, len(frames), accessorType, nil, nil)
+				outputComponent, len(frames), accessorType, nil, nil)
+			w.doc.Accessors[output].Normalized = outputComponent == glbComponentShort
 			sampler := len(out.Samplers)
 			out.Samplers = append(out.Samplers, glbAnimationSampler{
 				Input:         input,
@@ -810,6 +846,7 @@ func applyGLBExtrasToMeshes(doc *glbDocument, bin []byte, meshes []KaijuMesh) er
 			mesh.Name = doc.Meshes[extra.Mesh].Name
 		}
 		mesh.Material = extra.Material
+		mesh.VertexAnimations = extra.VertexAnimations
 		if extra.Node >= 0 && extra.Node < len(doc.Nodes) {
 			mesh.Node = kaijuNodeFromGLB(doc.Nodes[extra.Node])
 		}
@@ -910,6 +947,10 @@ func appendF32(out []byte, v float32) []byte {
 	return binary.LittleEndian.AppendUint32(out, math.Float32bits(v))
 }
 
+func appendNormalizedI16(out []byte, v matrix.Float) []byte {
+	return appendU16(out, uint16(QuantizeNormalizedInt16(v)))
+}
+
 func appendU16(out []byte, v uint16) []byte {
 	return binary.LittleEndian.AppendUint16(out, v)
 }
@@ -1073,6 +1114,13 @@ func glbRootNodes(nodes []glbNode) []int {
 
 func ptrInt(v int) *int { return &v }
 
+func jointNodeName(j *KaijuMeshJoint) string {
+	if j.Name != "" {
+		return j.Name
+	}
+	return fmt.Sprintf("Joint_%d", j.Id)
+}
+
 func animationAbsoluteTimes(anim *KaijuMeshAnimation) []float32 {
 	out := make([]float32, len(anim.Frames))
 	var total float32
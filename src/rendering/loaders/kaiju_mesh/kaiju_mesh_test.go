@@ -343,6 +343,49 @@ func TestKaijuMeshSerializeRoundTripSkinAnimation(t *testing.T) {
 	}
 }
 
+func TestKaijuMeshSerializeRoundTripAnimationEvents(t *testing.T) {
+	km := KaijuMesh{
+		Name: "skinned-point",
+		Verts: []rendering.Vertex{
+			{Normal: matrix.Vec3Forward(), Color: matrix.ColorWhite(), JointWeights: matrix.Vec4{1, 0, 0, 0}},
+			{Position: matrix.Vec3{1, 0, 0}, Normal: matrix.Vec3Forward(), Color: matrix.ColorWhite(), JointWeights: matrix.Vec4{1, 0, 0, 0}},
+			{Position: matrix.Vec3{0, 1, 0}, Normal: matrix.Vec3Forward(), Color: matrix.ColorWhite(), JointWeights: matrix.Vec4{1, 0, 0, 0}},
+		},
+		Indexes: []uint32{0, 1, 2},
+		Joints: []KaijuMeshJoint{
+			{Id: 0, Parent: -1, Name: "Hips", Skin: matrix.Mat4Identity(), Scale: matrix.Vec3One()},
+		},
+		Animations: []KaijuMeshAnimation{{
+			Name: "walk",
+			Frames: []AnimKeyFrame{
+				{Time: 0.5, Bones: []AnimBone{{NodeIndex: 0, PathType: AnimPathTranslation, Data: matrix.Vec3Zero().AsAligned16()}}},
+				{Time: 0, Bones: []AnimBone{{NodeIndex: 0, PathType: AnimPathTranslation, Data: matrix.Vec3{0, 0, 1}.AsAligned16()}}},
+			},
+		}},
+	}
+	km.Animations[0].AddEvent("footstep_right", 0.4)
+	km.Animations[0].AddEvent("footstep_left", 0.1)
+	data, err := km.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Deserialize(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Joints) != 1 || loaded.Joints[0].Name != "Hips" {
+		t.Fatalf("joints = %#v, want a single joint named Hips", loaded.Joints)
+	}
+	events := loaded.Animations[0].Events
+	if len(events) != 2 {
+		t.Fatalf("events = %#v, want 2", events)
+	}
+	if events[0].Name != "footstep_left" || !matrix.Approx(events[0].Time, 0.1) ||
+		events[1].Name != "footstep_right" || !matrix.Approx(events[1].Time, 0.4) {
+		t.Fatalf("events = %#v, want footstep_left@0.1, footstep_right@0.4", events)
+	}
+}
+
 func TestKaijuMeshDeserializeLegacyGob(t *testing.T) {
 	km := KaijuMesh{
 		Name: "legacy-gob-triangle",
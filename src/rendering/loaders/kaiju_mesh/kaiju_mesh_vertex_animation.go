@@ -0,0 +1,241 @@
+/******************************************************************************/
+/* kaiju_mesh_vertex_animation.go                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package kaiju_mesh
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/platform/profiler/tracing"
+)
+
+const (
+	DefaultVertexAnimationFrameRate = 30
+	// VertexAnimationRowsPerVertex is the number of texture rows used for
+	// each row of vertices. The first two rows are the high and low bytes of
+	// the 16 bit quantized position and the third row is the normal.
+	VertexAnimationRowsPerVertex = 3
+	vertexAnimationMaxWidth      = 4096
+	vertexAnimationMaxHeight     = 16384
+)
+
+// VertexAnimation describes an animation that has been baked into a vertex
+// animation texture. Each frame of the animation contains the skinned position
+// and normal of every vertex of the mesh, so that many instances of the mesh
+// can be animated on the GPU without any per-entity skinning work on the CPU.
+//
+// Vertex i of frame f is found at column i%Width and starting at row
+// f*RowsPerFrame + (i/Width)*[VertexAnimationRowsPerVertex]. Positions are
+// quantized to 16 bits within BoundsMin and BoundsMin+BoundsSize.
+type VertexAnimation struct {
+	Animation    string      `json:"animation"`
+	Texture      string      `json:"texture,omitempty"`
+	FrameCount   int32       `json:"frameCount"`
+	FrameRate    float32     `json:"frameRate"`
+	Width        int32       `json:"width"`
+	RowsPerFrame int32       `json:"rowsPerFrame"`
+	BoundsMin    matrix.Vec3 `json:"boundsMin"`
+	BoundsSize   matrix.Vec3 `json:"boundsSize"`
+}
+
+// Duration is the length of the baked animation in seconds
+func (v *VertexAnimation) Duration() float32 {
+	if v.FrameRate <= 0 || v.FrameCount <= 1 {
+		return 0
+	}
+	return float32(v.FrameCount-1) / v.FrameRate
+}
+
+// FindVertexAnimation returns the baked vertex animation of the mesh for the
+// animation with the given name
+func (k *KaijuMesh) FindVertexAnimation(animation string) (VertexAnimation, bool) {
+	for i := range k.VertexAnimations {
+		if k.VertexAnimations[i].Animation == animation {
+			return k.VertexAnimations[i], true
+		}
+	}
+	return VertexAnimation{}, false
+}
+
+// BakeVertexAnimation skins the mesh on the CPU for every frame of the
+// animation at the given frame rate and stores the results into an image
+// that can be imported as a texture. The Texture field of the returned
+// [VertexAnimation] is left empty, it should be set to the id of the texture
+// once the image has been imported.
+func BakeVertexAnimation(k *KaijuMesh, anim *KaijuMeshAnimation, frameRate float32) (VertexAnimation, *image.NRGBA, error) {
+	defer tracing.NewRegion("kaiju_mesh.BakeVertexAnimation").End()
+	if len(k.Verts) == 0 || len(k.Joints) == 0 {
+		return VertexAnimation{}, nil, errors.New("vertex animations can only be baked for skinned meshes")
+	}
+	if frameRate <= 0 {
+		frameRate = DefaultVertexAnimationFrameRate
+	}
+	duration := anim.Duration()
+	va := VertexAnimation{
+		Animation:  anim.Name,
+		FrameCount: int32(math.Ceil(float64(duration*frameRate))) + 1,
+		FrameRate:  frameRate,
+		Width:      int32(min(len(k.Verts), vertexAnimationMaxWidth)),
+	}
+	vertexRows := (int32(len(k.Verts)) + va.Width - 1) / va.Width
+	va.RowsPerFrame = vertexRows * VertexAnimationRowsPerVertex
+	if height := va.FrameCount * va.RowsPerFrame; height > vertexAnimationMaxHeight {
+		return va, nil, fmt.Errorf("vertex animation %q requires a texture height of %d, which is more than the max of %d, try lowering the frame rate",
+			anim.Name, height, vertexAnimationMaxHeight)
+	}
+	positions, normals := skinAnimationFrames(k, anim, va.FrameCount, frameRate)
+	va.BoundsMin, va.BoundsSize = vertexAnimationBounds(positions)
+	img := image.NewNRGBA(image.Rect(0, 0, int(va.Width), int(va.FrameCount*va.RowsPerFrame)))
+	for f := range positions {
+		for v := range positions[f] {
+			x := v % int(va.Width)
+			y := f*int(va.RowsPerFrame) + (v/int(va.Width))*VertexAnimationRowsPerVertex
+			var hi, lo color.NRGBA
+			hi.A, lo.A = 255, 255
+			for c := range 3 {
+				n := (positions[f][v][c] - va.BoundsMin[c]) / va.BoundsSize[c]
+				q := uint16(math.Round(float64(matrix.Clamp(n, 0, 1)) * math.MaxUint16))
+				setColorChannel(&hi, c, uint8(q>>8))
+				setColorChannel(&lo, c, uint8(q))
+			}
+			img.SetNRGBA(x, y, hi)
+			img.SetNRGBA(x, y+1, lo)
+			n := normals[f][v]
+			img.SetNRGBA(x, y+2, color.NRGBA{
+				R: unitToByte(n.X()),
+				G: unitToByte(n.Y()),
+				B: unitToByte(n.Z()),
+				A: 255,
+			})
+		}
+	}
+	return va, img, nil
+}
+
+// DecodeVertexAnimationPosition reads the position of a vertex for a frame
+// from a baked vertex animation image, this mirrors what the shader does
+func DecodeVertexAnimationPosition(va VertexAnimation, img *image.NRGBA, frame, vertex int) matrix.Vec3 {
+	x := vertex % int(va.Width)
+	y := frame*int(va.RowsPerFrame) + (vertex/int(va.Width))*VertexAnimationRowsPerVertex
+	hi, lo := img.NRGBAAt(x, y), img.NRGBAAt(x, y+1)
+	his := [3]uint8{hi.R, hi.G, hi.B}
+	los := [3]uint8{lo.R, lo.G, lo.B}
+	var out matrix.Vec3
+	for c := range out {
+		q := matrix.Float(uint16(his[c])<<8|uint16(los[c])) / math.MaxUint16
+		out[c] = va.BoundsMin[c] + q*va.BoundsSize[c]
+	}
+	return out
+}
+
+// skinAnimationFrames poses the skeleton for each frame of the animation and
+// skins the vertices in the same way as the skinning vertex shader does
+func skinAnimationFrames(k *KaijuMesh, anim *KaijuMeshAnimation, frameCount int32, frameRate float32) ([][]matrix.Vec3, [][]matrix.Vec3) {
+	bones := make([]matrix.Transform, len(k.Joints))
+	jointIndex := make(map[int]int, len(k.Joints))
+	for i := range k.Joints {
+		jointIndex[int(k.Joints[i].Id)] = i
+		bones[i].SetupRawTransform()
+	}
+	for i := range k.Joints {
+		if p, ok := jointIndex[int(k.Joints[i].Parent)]; ok {
+			bones[i].SetParent(&bones[p])
+		}
+	}
+	resetPose := func() {
+		for i := range k.Joints {
+			bones[i].SetLocalPosition(k.Joints[i].Position)
+			bones[i].SetRotation(k.Joints[i].Rotation)
+			bones[i].SetScale(k.Joints[i].Scale)
+		}
+	}
+	tracks := anim.Tracks()
+	duration := anim.Duration()
+	skins := make([]matrix.Mat4, len(k.Joints))
+	positions := make([][]matrix.Vec3, frameCount)
+	normals := make([][]matrix.Vec3, frameCount)
+	for f := range frameCount {
+		resetPose()
+		time := min(float32(f)/frameRate, duration)
+		for i := range tracks {
+			b, ok := jointIndex[tracks[i].NodeIndex]
+			if !ok {
+				continue
+			}
+			data := tracks[i].Sample(time)
+			switch tracks[i].PathType {
+			case AnimPathTranslation:
+				bones[b].SetLocalPosition(matrix.Vec3FromSlice(data[:]))
+			case AnimPathRotation:
+				bones[b].SetRotation(matrix.Quaternion(data).ToEuler())
+			case AnimPathScale:
+				bones[b].SetScale(matrix.Vec3FromSlice(data[:]))
+			}
+		}
+		for i := range bones {
+			skins[i] = matrix.Mat4Multiply(k.Joints[i].Skin, bones[i].WorldMatrix())
+		}
+		positions[f] = make([]matrix.Vec3, len(k.Verts))
+		normals[f] = make([]matrix.Vec3, len(k.Verts))
+		for v := range k.Verts {
+			vert := &k.Verts[v]
+			var m matrix.Mat4
+			for w := range 4 {
+				weight := vert.JointWeights[w]
+				joint := int(vert.JointIds[w])
+				if weight == 0 || joint < 0 || joint >= len(skins) {
+					continue
+				}
+				for e := range m {
+					m[e] += skins[joint][e] * weight
+				}
+			}
+			p := matrix.Mat4MultiplyVec4(m, matrix.Vec4{
+				vert.Position.X(), vert.Position.Y(), vert.Position.Z(), 1})
+			n := matrix.Mat4MultiplyVec4(m, matrix.Vec4{
+				vert.Normal.X(), vert.Normal.Y(), vert.Normal.Z(), 0})
+			positions[f][v] = p.AsVec3()
+			normals[f][v] = n.AsVec3().Normal()
+		}
+	}
+	return positions, normals
+}
+
+func vertexAnimationBounds(positions [][]matrix.Vec3) (matrix.Vec3, matrix.Vec3) {
+	minV := matrix.Vec3Inf(1)
+	maxV := matrix.Vec3Inf(-1)
+	for f := range positions {
+		for v := range positions[f] {
+			minV = matrix.Vec3Min(minV, positions[f][v])
+			maxV = matrix.Vec3Max(maxV, positions[f][v])
+		}
+	}
+	size := maxV.Subtract(minV)
+	for c := range size {
+		size[c] = max(size[c], matrix.Tiny)
+	}
+	return minV, size
+}
+
+func setColorChannel(c *color.NRGBA, channel int, value uint8) {
+	switch channel {
+	case 0:
+		c.R = value
+	case 1:
+		c.G = value
+	case 2:
+		c.B = value
+	}
+}
+
+func unitToByte(v matrix.Float) uint8 {
+	return uint8(math.Round(float64(matrix.Clamp(v*0.5+0.5, 0, 1)) * math.MaxUint8))
+}
@@ -0,0 +1,118 @@
+/******************************************************************************/
+/* kaiju_mesh_vertex_animation_test.go                                        */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package kaiju_mesh
+
+import (
+	"testing"
+
+	"kaijuengine.com/matrix"
+	"kaijuengine.com/rendering"
+)
+
+func vertexAnimationTestMesh() KaijuMesh {
+	childSkin := matrix.Mat4Identity()
+	childSkin.Translate(matrix.Vec3{0, -1, 0})
+	return KaijuMesh{
+		Name: "skinned-quad",
+		Verts: []rendering.Vertex{
+			{Position: matrix.Vec3{0, 0, 0}, Normal: matrix.Vec3Forward(), JointIds: matrix.Vec4i{0, 0, 0, 0}, JointWeights: matrix.Vec4{1, 0, 0, 0}},
+			{Position: matrix.Vec3{1, 0, 0}, Normal: matrix.Vec3Forward(), JointIds: matrix.Vec4i{0, 0, 0, 0}, JointWeights: matrix.Vec4{1, 0, 0, 0}},
+			{Position: matrix.Vec3{0, 2, 0}, Normal: matrix.Vec3Forward(), JointIds: matrix.Vec4i{1, 0, 0, 0}, JointWeights: matrix.Vec4{1, 0, 0, 0}},
+			{Position: matrix.Vec3{1, 2, 0}, Normal: matrix.Vec3Forward(), JointIds: matrix.Vec4i{0, 1, 0, 0}, JointWeights: matrix.Vec4{0.5, 0.5, 0, 0}},
+		},
+		Indexes: []uint32{0, 1, 2, 2, 1, 3},
+		Joints: []KaijuMeshJoint{
+			{Id: 0, Parent: -1, Skin: matrix.Mat4Identity(), Scale: matrix.Vec3One()},
+			{Id: 1, Parent: 0, Skin: childSkin, Position: matrix.Vec3{0, 1, 0}, Scale: matrix.Vec3One()},
+		},
+	}
+}
+
+func TestBakeVertexAnimation(t *testing.T) {
+	km := vertexAnimationTestMesh()
+	move := AnimTrack{
+		NodeIndex:     1,
+		PathType:      AnimPathTranslation,
+		Interpolation: AnimInterpolateLinear,
+		Times:         []float32{0, 1},
+		Values:        [][4]matrix.Float{{0, 1, 0, 0}, {2, 1, 0, 0}},
+	}
+	anim := AnimationFromTracks("slide", []AnimTrack{move}, 1)
+	va, img, err := BakeVertexAnimation(&km, &anim, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if va.Animation != "slide" || va.FrameCount != 11 || va.Width != 4 ||
+		va.RowsPerFrame != VertexAnimationRowsPerVertex {
+		t.Fatalf("vertex animation = %#v, want 11 frames of 4x%d texels", va, VertexAnimationRowsPerVertex)
+	}
+	if img.Bounds().Dx() != 4 || img.Bounds().Dy() != 11*VertexAnimationRowsPerVertex {
+		t.Fatalf("image size = %v, want 4x%d", img.Bounds().Size(), 11*VertexAnimationRowsPerVertex)
+	}
+	// The child joint moves 2 units along X, fully weighted vertices follow it
+	// entirely while the blended vertex moves half as far
+	offsets := []matrix.Float{0, 0, 2, 1}
+	tolerance := va.BoundsSize.Length() / 65535 * 2
+	for _, frame := range []int{0, 5, 10} {
+		progress := matrix.Float(frame) / 10
+		for v := range km.Verts {
+			want := km.Verts[v].Position.Add(matrix.Vec3{offsets[v] * progress, 0, 0})
+			got := DecodeVertexAnimationPosition(va, img, frame, v)
+			if got.Distance(want) > tolerance {
+				t.Fatalf("frame %d vertex %d = %v, want %v", frame, v, got, want)
+			}
+		}
+	}
+	for y := range img.Bounds().Dy() {
+		for x := range img.Bounds().Dx() {
+			if img.NRGBAAt(x, y).A != 255 {
+				t.Fatalf("texel %d,%d is not opaque", x, y)
+			}
+		}
+	}
+}
+
+func TestBakeVertexAnimationRequiresSkin(t *testing.T) {
+	km := vertexAnimationTestMesh()
+	km.Joints = nil
+	anim := KaijuMeshAnimation{Name: "none"}
+	if _, _, err := BakeVertexAnimation(&km, &anim, 30); err == nil {
+		t.Fatal("expected an error when baking a mesh without joints")
+	}
+}
+
+func TestKaijuMeshSerializeRoundTripVertexAnimations(t *testing.T) {
+	km := vertexAnimationTestMesh()
+	km.VertexAnimations = []VertexAnimation{{
+		Animation:    "slide",
+		Texture:      "slide_vat.png",
+		FrameCount:   11,
+		FrameRate:    10,
+		Width:        4,
+		RowsPerFrame: VertexAnimationRowsPerVertex,
+		BoundsMin:    matrix.Vec3{0, 0, 0},
+		BoundsSize:   matrix.Vec3{3, 2, 0.0001},
+	}}
+	data, err := km.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Deserialize(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	va, ok := loaded.FindVertexAnimation("slide")
+	if !ok {
+		t.Fatalf("vertex animations = %#v, want slide", loaded.VertexAnimations)
+	}
+	if va != km.VertexAnimations[0] {
+		t.Fatalf("vertex animation = %#v, want %#v", va, km.VertexAnimations[0])
+	}
+	if !matrix.Approx(va.Duration(), 1) {
+		t.Fatalf("duration = %f, want 1", va.Duration())
+	}
+}
@@ -53,9 +53,18 @@ type AnimKeyFrame struct {
 	Time  float32
 }
 
+type AnimEvent struct {
+	Name string
+	Time float32
+}
+
 type Animation struct {
 	Name   string
 	Frames []AnimKeyFrame
+	Events []AnimEvent
+	// QuantizedRotations is true when the rotation keys were stored as
+	// normalized integers rather than floats
+	QuantizedRotations bool
 }
 
 type Node struct {
@@ -0,0 +1,167 @@
+/******************************************************************************/
+/* joining.go                                                                 */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package opentype
+
+import "unicode"
+
+type joiningType int
+
+const (
+	joinNone joiningType = iota
+	joinRight
+	joinDual
+	joinCausing
+	joinTransparent
+)
+
+// Arabic, Syriac and N'Ko letters that only join to the character before
+// them, checked before the dual joining ranges
+var rightJoining = [][2]rune{
+	{0x0622, 0x0625}, {0x0627, 0x0627}, {0x0629, 0x0629}, {0x062F, 0x0632},
+	{0x0648, 0x0648}, {0x0671, 0x0673}, {0x0675, 0x0677}, {0x0688, 0x0699},
+	{0x06C0, 0x06C0}, {0x06C3, 0x06CB}, {0x06CD, 0x06CD}, {0x06CF, 0x06CF},
+	{0x06D2, 0x06D3}, {0x06D5, 0x06D5}, {0x06EE, 0x06EF}, {0x0710, 0x0710},
+	{0x0715, 0x0719}, {0x071E, 0x071E}, {0x0728, 0x0728}, {0x072A, 0x072A},
+	{0x072C, 0x072C}, {0x072F, 0x072F}, {0x074D, 0x074D}, {0x0759, 0x075B},
+	{0x076B, 0x076C}, {0x0771, 0x0771}, {0x0773, 0x0774}, {0x0778, 0x0779},
+	{0x08AA, 0x08AC}, {0x08AE, 0x08AE}, {0x08B1, 0x08B2}, {0x08B9, 0x08B9},
+}
+
+var dualJoining = [][2]rune{
+	{0x0620, 0x0620}, {0x0626, 0x0626}, {0x0628, 0x0628}, {0x062A, 0x062E},
+	{0x0633, 0x063F}, {0x0641, 0x0647}, {0x0649, 0x064A}, {0x066E, 0x066F},
+	{0x0678, 0x0687}, {0x069A, 0x06BF}, {0x06C1, 0x06C2}, {0x06CC, 0x06CC},
+	{0x06CE, 0x06CE}, {0x06D0, 0x06D1}, {0x06FA, 0x06FC}, {0x06FF, 0x06FF},
+	{0x0712, 0x0714}, {0x071A, 0x071D}, {0x071F, 0x0727}, {0x0729, 0x0729},
+	{0x072B, 0x072B}, {0x072D, 0x072E}, {0x074E, 0x077F}, {0x07CA, 0x07EA},
+	{0x08A0, 0x08A9}, {0x08AF, 0x08B0}, {0x08B3, 0x08B8}, {0x08BA, 0x08C8},
+}
+
+func inRanges(r rune, ranges [][2]rune) bool {
+	for _, rng := range ranges {
+		if r >= rng[0] && r <= rng[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func joiningOf(r rune) joiningType {
+	switch {
+	case r == 0x200D || r == 0x0640 || r == 0x07FA:
+		return joinCausing
+	case r == 0x200C:
+		return joinNone
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return joinTransparent
+	case inRanges(r, rightJoining):
+		return joinRight
+	case inRanges(r, dualJoining):
+		return joinDual
+	}
+	return joinNone
+}
+
+// joiningMasks enables the isol, init, medi or fina form of each joining
+// letter depending on whether the letters around it join to it
+func joiningMasks(buf []shapeGlyph) {
+	types := make([]joiningType, len(buf))
+	for i := range buf {
+		types[i] = joiningOf(buf[i].Rune)
+	}
+	neighbor := func(i, step int) joiningType {
+		for i += step; i >= 0 && i < len(types); i += step {
+			if types[i] != joinTransparent {
+				return types[i]
+			}
+		}
+		return joinNone
+	}
+	for i, t := range types {
+		if t != joinRight && t != joinDual {
+			continue
+		}
+		prev := neighbor(i, -1)
+		joinsPrev := prev == joinDual || prev == joinCausing
+		joinsNext := false
+		if t == joinDual {
+			next := neighbor(i, 1)
+			joinsNext = next == joinRight || next == joinDual || next == joinCausing
+		}
+		switch {
+		case joinsPrev && joinsNext:
+			buf[i].mask |= maskMedi
+		case joinsPrev:
+			buf[i].mask |= maskFina
+		case joinsNext:
+			buf[i].mask |= maskInit
+		default:
+			buf[i].mask |= maskIsol
+		}
+	}
+}
+
+// Indic vowel signs that are written before the consonant cluster they
+// follow in the text
+var preBaseMatras = [][2]rune{
+	{0x093F, 0x093F}, {0x094E, 0x094E}, {0x09BF, 0x09BF}, {0x09C7, 0x09C8},
+	{0x0A3F, 0x0A3F}, {0x0ABF, 0x0ABF}, {0x0B47, 0x0B47}, {0x0BC6, 0x0BC8},
+	{0x0D46, 0x0D48}, {0x0DD9, 0x0DDB}, {0x1031, 0x1031},
+}
+
+var indicConsonants = [][2]rune{
+	{0x0915, 0x0939}, {0x0958, 0x095F}, {0x0978, 0x097F}, {0x0995, 0x09B9},
+	{0x09DC, 0x09DF}, {0x0A15, 0x0A39}, {0x0A59, 0x0A5E}, {0x0A95, 0x0AB9},
+	{0x0B15, 0x0B39}, {0x0B5C, 0x0B5F}, {0x0B95, 0x0BB9}, {0x0D15, 0x0D3A},
+	{0x0D9A, 0x0DC6}, {0x1000, 0x1021},
+}
+
+var indicViramas = [][2]rune{
+	{0x094D, 0x094D}, {0x09CD, 0x09CD}, {0x0A4D, 0x0A4D}, {0x0ACD, 0x0ACD},
+	{0x0B4D, 0x0B4D}, {0x0BCD, 0x0BCD}, {0x0D4D, 0x0D4D}, {0x0DCA, 0x0DCA},
+	{0x1039, 0x1039},
+}
+
+var indicNuktas = [][2]rune{
+	{0x093C, 0x093C}, {0x09BC, 0x09BC}, {0x0A3C, 0x0A3C}, {0x0ABC, 0x0ABC},
+	{0x0B3C, 0x0B3C},
+}
+
+// reorderIndic moves pre-base vowel signs in front of the consonant cluster
+// (consonants joined by viramas) they follow. Reph and the per syllable
+// position of the Indic features are not handled, those features apply to
+// the whole run
+func reorderIndic(buf []shapeGlyph) {
+	consonantAt := func(j int) int {
+		if j >= 0 && inRanges(buf[j].Rune, indicNuktas) {
+			j--
+		}
+		if j >= 0 && inRanges(buf[j].Rune, indicConsonants) {
+			return j
+		}
+		return -1
+	}
+	for i := range buf {
+		if !inRanges(buf[i].Rune, preBaseMatras) {
+			continue
+		}
+		start := consonantAt(i - 1)
+		if start < 0 {
+			continue
+		}
+		for start >= 2 && inRanges(buf[start-1].Rune, indicViramas) {
+			prev := consonantAt(start - 2)
+			if prev < 0 {
+				break
+			}
+			start = prev
+		}
+		matra := buf[i]
+		copy(buf[start+1:i+1], buf[start:i])
+		buf[start] = matra
+	}
+}
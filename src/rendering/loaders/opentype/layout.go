@@ -0,0 +1,445 @@
+/******************************************************************************/
+/* layout.go                                                                  */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package opentype
+
+// layoutTable is a GSUB or GPOS table with its lookups still addressed by
+// glyph index
+type layoutTable struct {
+	// features maps a feature tag to the lookups it enables for the default
+	// language system of every script in the font
+	features map[string][]int
+	lookups  []glyphLookup
+}
+
+// glyphSet matches the glyphs it holds, or every glyph it doesn't hold when
+// not is set (class 0 of a class definition)
+type glyphSet struct {
+	glyphs map[uint16]bool
+	not    bool
+}
+
+func (s glyphSet) has(g uint16) bool { return s.glyphs[g] != s.not }
+
+type glyphLigature struct {
+	components []uint16
+	glyph      uint16
+}
+
+type glyphChain struct {
+	// backtrack is stored nearest glyph first
+	backtrack, input, lookahead []glyphSet
+	actions                     []ChainAction
+}
+
+type pairClasses struct {
+	coverage       map[uint16]bool
+	class1, class2 map[uint16]uint16
+	values         [][]int16
+}
+
+type glyphLookup struct {
+	single    map[uint16]uint16
+	multiple  map[uint16][]uint16
+	ligatures map[uint16][]glyphLigature
+	chains    []glyphChain
+	pairs     map[[2]uint16]int16
+	classes   []pairClasses
+}
+
+const (
+	gsubSingle     = 1
+	gsubMultiple   = 2
+	gsubAlternate  = 3
+	gsubLigature   = 4
+	gsubContext    = 5
+	gsubChain      = 6
+	gsubExtension  = 7
+	gposPair       = 2
+	gposExtension  = 9
+	valueXAdvance  = 0x0004
+	noRequiredFeat = 0xFFFF
+)
+
+func parseLayoutTable(r reader, positioning bool) layoutTable {
+	t := layoutTable{features: map[string][]int{}}
+	if r == nil {
+		return t
+	}
+	scripts, features, lookups := r.sub(int(r.u16(4))), r.sub(int(r.u16(6))), r.sub(int(r.u16(8)))
+	used := map[int]bool{}
+	addLangSys := func(ls reader) {
+		if ls == nil {
+			return
+		}
+		if req := ls.u16(2); req != noRequiredFeat {
+			used[int(req)] = true
+		}
+		for i := range int(ls.u16(4)) {
+			used[int(ls.u16(6+i*2))] = true
+		}
+	}
+	for i := range int(scripts.u16(0)) {
+		script := scripts.sub(int(scripts.u16(2 + i*6 + 4)))
+		addLangSys(script.sub(int(script.u16(0))))
+	}
+	featureCount := int(features.u16(0))
+	if len(used) == 0 {
+		for i := range featureCount {
+			used[i] = true
+		}
+	}
+	for i := range featureCount {
+		if !used[i] {
+			continue
+		}
+		rec := 2 + i*6
+		tag, feat := features.tag(rec), features.sub(int(features.u16(rec+4)))
+		for j := range int(feat.u16(2)) {
+			t.features[tag] = appendUnique(t.features[tag], int(feat.u16(4+j*2)))
+		}
+	}
+	t.lookups = make([]glyphLookup, lookups.u16(0))
+	for i := range t.lookups {
+		lookup := lookups.sub(int(lookups.u16(2 + i*2)))
+		kind := lookup.u16(0)
+		for j := range int(lookup.u16(4)) {
+			sub := lookup.sub(int(lookup.u16(6 + j*2)))
+			subKind := kind
+			if (positioning && kind == gposExtension) || (!positioning && kind == gsubExtension) {
+				subKind = sub.u16(2)
+				sub = sub.sub(int(sub.u32(4)))
+			}
+			if positioning {
+				t.lookups[i].parsePositioning(subKind, sub)
+			} else {
+				t.lookups[i].parseSubstitution(subKind, sub)
+			}
+		}
+	}
+	return t
+}
+
+func appendUnique(list []int, v int) []int {
+	for _, e := range list {
+		if e == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
+
+// parseCoverage returns the glyphs of the coverage table in coverage index
+// order
+func parseCoverage(r reader) []uint16 {
+	var out []uint16
+	switch r.u16(0) {
+	case 1:
+		for i := range int(r.u16(2)) {
+			out = append(out, r.u16(4+i*2))
+		}
+	case 2:
+		for i := range int(r.u16(2)) {
+			rec := 4 + i*6
+			for g := int(r.u16(rec)); g <= int(r.u16(rec+2)); g++ {
+				out = append(out, uint16(g))
+			}
+		}
+	}
+	return out
+}
+
+func coverageSet(r reader) glyphSet {
+	s := glyphSet{glyphs: map[uint16]bool{}}
+	for _, g := range parseCoverage(r) {
+		s.glyphs[g] = true
+	}
+	return s
+}
+
+// parseClassDef returns the class of every glyph with a class other than 0
+func parseClassDef(r reader) map[uint16]uint16 {
+	out := map[uint16]uint16{}
+	switch r.u16(0) {
+	case 1:
+		start := int(r.u16(2))
+		for i := range int(r.u16(4)) {
+			if c := r.u16(6 + i*2); c != 0 {
+				out[uint16(start+i)] = c
+			}
+		}
+	case 2:
+		for i := range int(r.u16(2)) {
+			rec := 4 + i*6
+			c := r.u16(rec + 4)
+			for g := int(r.u16(rec)); g <= int(r.u16(rec+2)) && c != 0; g++ {
+				out[uint16(g)] = c
+			}
+		}
+	}
+	return out
+}
+
+func classSet(classes map[uint16]uint16, class uint16) glyphSet {
+	s := glyphSet{glyphs: map[uint16]bool{}, not: class == 0}
+	for g, c := range classes {
+		if (class == 0) || c == class {
+			s.glyphs[g] = true
+		}
+	}
+	return s
+}
+
+func (l *glyphLookup) parseSubstitution(kind uint16, r reader) {
+	format := r.u16(0)
+	switch kind {
+	case gsubSingle:
+		if l.single == nil {
+			l.single = map[uint16]uint16{}
+		}
+		for i, g := range parseCoverage(r.sub(int(r.u16(2)))) {
+			if _, ok := l.single[g]; ok {
+				continue
+			}
+			if format == 1 {
+				l.single[g] = uint16(int(g) + int(r.i16(4)))
+			} else if i < int(r.u16(4)) {
+				l.single[g] = r.u16(6 + i*2)
+			}
+		}
+	case gsubMultiple:
+		if l.multiple == nil {
+			l.multiple = map[uint16][]uint16{}
+		}
+		for i, g := range parseCoverage(r.sub(int(r.u16(2)))) {
+			if _, ok := l.multiple[g]; ok || i >= int(r.u16(4)) {
+				continue
+			}
+			seq := r.sub(int(r.u16(6 + i*2)))
+			out := make([]uint16, seq.u16(0))
+			for j := range out {
+				out[j] = seq.u16(2 + j*2)
+			}
+			l.multiple[g] = out
+		}
+	case gsubAlternate:
+		// Alternates are picked through font-variant-alternates indexes,
+		// which are not supported, the first alternate is used instead
+		if l.single == nil {
+			l.single = map[uint16]uint16{}
+		}
+		for i, g := range parseCoverage(r.sub(int(r.u16(2)))) {
+			set := r.sub(int(r.u16(6 + i*2)))
+			if _, ok := l.single[g]; !ok && i < int(r.u16(4)) && set.u16(0) > 0 {
+				l.single[g] = set.u16(2)
+			}
+		}
+	case gsubLigature:
+		if l.ligatures == nil {
+			l.ligatures = map[uint16][]glyphLigature{}
+		}
+		for i, g := range parseCoverage(r.sub(int(r.u16(2)))) {
+			if i >= int(r.u16(4)) {
+				break
+			}
+			set := r.sub(int(r.u16(6 + i*2)))
+			for j := range int(set.u16(0)) {
+				lig := set.sub(int(set.u16(2 + j*2)))
+				components := make([]uint16, max(int(lig.u16(2))-1, 0))
+				for k := range components {
+					components[k] = lig.u16(4 + k*2)
+				}
+				l.ligatures[g] = append(l.ligatures[g], glyphLigature{components, lig.u16(0)})
+			}
+		}
+	case gsubContext, gsubChain:
+		l.chains = append(l.chains, parseContext(r, kind == gsubChain)...)
+	}
+}
+
+// parseContext converts the three formats of (chained) contextual lookups
+// into a list of rules that match glyph sets
+func parseContext(r reader, chained bool) []glyphChain {
+	var out []glyphChain
+	readSets := func(r reader, off int, toSet func(uint16) glyphSet) ([]glyphSet, int) {
+		sets := make([]glyphSet, r.u16(off))
+		for i := range sets {
+			sets[i] = toSet(r.u16(off + 2 + i*2))
+		}
+		return sets, off + 2 + len(sets)*2
+	}
+	readActions := func(r reader, off, count int) []ChainAction {
+		actions := make([]ChainAction, count)
+		for i := range actions {
+			actions[i] = ChainAction{int(r.u16(off + i*4)), int(r.u16(off + i*4 + 2))}
+		}
+		return actions
+	}
+	// readRule reads a format 1 or 2 rule whose first input glyph is matched
+	// by first, values are glyphs or classes depending on the to* functions
+	readRule := func(rule reader, first glyphSet, back, in, ahead func(uint16) glyphSet) glyphChain {
+		c := glyphChain{}
+		off := 0
+		if chained {
+			c.backtrack, off = readSets(rule, off, back)
+		}
+		inputCount := int(rule.u16(off))
+		off += 2
+		actionCount := 0
+		if !chained {
+			actionCount = int(rule.u16(off))
+			off += 2
+		}
+		c.input = []glyphSet{first}
+		for i := 1; i < inputCount; i++ {
+			c.input = append(c.input, in(rule.u16(off)))
+			off += 2
+		}
+		if chained {
+			c.lookahead, off = readSets(rule, off, ahead)
+			actionCount = int(rule.u16(off))
+			off += 2
+		}
+		c.actions = readActions(rule, off, actionCount)
+		return c
+	}
+	single := func(g uint16) glyphSet { return glyphSet{glyphs: map[uint16]bool{g: true}} }
+	switch r.u16(0) {
+	case 1:
+		for i, g := range parseCoverage(r.sub(int(r.u16(2)))) {
+			if i >= int(r.u16(4)) {
+				break
+			}
+			set := r.sub(int(r.u16(6 + i*2)))
+			for j := range int(set.u16(0)) {
+				out = append(out, readRule(set.sub(int(set.u16(2+j*2))), single(g), single, single, single))
+			}
+		}
+	case 2:
+		coverage := coverageSet(r.sub(int(r.u16(2))))
+		var back, in, ahead map[uint16]uint16
+		off := 4
+		if chained {
+			back = parseClassDef(r.sub(int(r.u16(off))))
+			off += 2
+		}
+		in = parseClassDef(r.sub(int(r.u16(off))))
+		off += 2
+		if chained {
+			ahead = parseClassDef(r.sub(int(r.u16(off))))
+			off += 2
+		}
+		toBack := func(c uint16) glyphSet { return classSet(back, c) }
+		toIn := func(c uint16) glyphSet { return classSet(in, c) }
+		toAhead := func(c uint16) glyphSet { return classSet(ahead, c) }
+		for i := range int(r.u16(off)) {
+			set := r.sub(int(r.u16(off + 2 + i*2)))
+			first := glyphSet{glyphs: map[uint16]bool{}}
+			for g := range coverage.glyphs {
+				if in[g] == uint16(i) {
+					first.glyphs[g] = true
+				}
+			}
+			for j := range int(set.u16(0)) {
+				out = append(out, readRule(set.sub(int(set.u16(2+j*2))), first, toBack, toIn, toAhead))
+			}
+		}
+	case 3:
+		c := glyphChain{}
+		toCoverage := func(off uint16) glyphSet { return coverageSet(r.sub(int(off))) }
+		off := 2
+		if chained {
+			c.backtrack, off = readSets(r, off, toCoverage)
+			c.input, off = readSets(r, off, toCoverage)
+			c.lookahead, off = readSets(r, off, toCoverage)
+			c.actions = readActions(r, off+2, int(r.u16(off)))
+		} else {
+			inputCount, actionCount := int(r.u16(2)), int(r.u16(4))
+			for i := range inputCount {
+				c.input = append(c.input, toCoverage(r.u16(6+i*2)))
+			}
+			c.actions = readActions(r, 6+inputCount*2, actionCount)
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *glyphLookup) parsePositioning(kind uint16, r reader) {
+	if kind != gposPair {
+		// Mark and cursive attachment are not supported, the atlas glyphs
+		// are drawn without vertical offsets
+		return
+	}
+	format1, format2 := r.u16(4), r.u16(6)
+	size1, size2 := valueRecordSize(format1), valueRecordSize(format2)
+	xAdvance := func(rec int) int16 {
+		if format1&valueXAdvance == 0 {
+			return 0
+		}
+		return r.i16(rec + valueRecordSize(format1&(valueXAdvance-1)))
+	}
+	switch r.u16(0) {
+	case 1:
+		if l.pairs == nil {
+			l.pairs = map[[2]uint16]int16{}
+		}
+		for i, g := range parseCoverage(r.sub(int(r.u16(2)))) {
+			if i >= int(r.u16(8)) {
+				break
+			}
+			setOff := int(r.u16(10 + i*2))
+			set := r.sub(setOff)
+			for j := range int(set.u16(0)) {
+				rec := 2 + j*(2+size1+size2)
+				key := [2]uint16{g, set.u16(rec)}
+				if _, ok := l.pairs[key]; !ok {
+					l.pairs[key] = xAdvance(setOff + rec + 2)
+				}
+			}
+		}
+	case 2:
+		p := pairClasses{
+			coverage: coverageSet(r.sub(int(r.u16(2)))).glyphs,
+			class1:   parseClassDef(r.sub(int(r.u16(8)))),
+			class2:   parseClassDef(r.sub(int(r.u16(10)))),
+		}
+		count1, count2 := int(r.u16(12)), int(r.u16(14))
+		p.values = make([][]int16, count1)
+		for c1 := range p.values {
+			p.values[c1] = make([]int16, count2)
+			for c2 := range p.values[c1] {
+				p.values[c1][c2] = xAdvance(16 + (c1*count2+c2)*(size1+size2))
+			}
+		}
+		l.classes = append(l.classes, p)
+	}
+}
+
+func valueRecordSize(format uint16) int {
+	size := 0
+	for ; format != 0; format &= format - 1 {
+		size += 2
+	}
+	return size
+}
+
+// kerning returns the advance adjustment of the pair in font units
+func (l *glyphLookup) kerning(a, b uint16) (int16, bool) {
+	if v, ok := l.pairs[[2]uint16{a, b}]; ok {
+		return v, true
+	}
+	for _, p := range l.classes {
+		if !p.coverage[a] {
+			continue
+		}
+		c1, c2 := int(p.class1[a]), int(p.class2[b])
+		if c1 < len(p.values) && c2 < len(p.values[c1]) {
+			return p.values[c1][c2], true
+		}
+	}
+	return 0, false
+}
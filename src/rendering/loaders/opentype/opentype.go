@@ -0,0 +1,170 @@
+/******************************************************************************/
+/* opentype.go                                                                */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+// Package opentype reads the tables of TrueType and OpenType fonts that are
+// needed to shape text (cmap, GSUB and GPOS) and converts them into compact
+// rune based tables that are stored alongside the MSDF atlas of a font.
+package opentype
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotOpenType = errors.New("the data is not a TrueType or OpenType font")
+	ErrNoShaping   = errors.New("the data does not contain shaping tables")
+)
+
+// Font holds the parsed tables of a font needed to create the [Shaping]
+// tables for a set of characters
+type Font struct {
+	UnitsPerEm uint16
+	NumGlyphs  uint16
+	// Cmap maps the characters of the font to their glyph index
+	Cmap map[rune]uint16
+	gsub layoutTable
+	gpos layoutTable
+	// runes is the lowest character that maps to each glyph
+	runes map[uint16]rune
+}
+
+// reader reads big endian values out of a table, reads past the end of the
+// data return zero so that malformed fonts don't panic
+type reader []byte
+
+func (r reader) u16(off int) uint16 {
+	if off < 0 || off+2 > len(r) {
+		return 0
+	}
+	return uint16(r[off])<<8 | uint16(r[off+1])
+}
+
+func (r reader) i16(off int) int16 { return int16(r.u16(off)) }
+
+func (r reader) u32(off int) uint32 {
+	if off < 0 || off+4 > len(r) {
+		return 0
+	}
+	return uint32(r[off])<<24 | uint32(r[off+1])<<16 | uint32(r[off+2])<<8 | uint32(r[off+3])
+}
+
+func (r reader) tag(off int) string {
+	if off < 0 || off+4 > len(r) {
+		return ""
+	}
+	return string(r[off : off+4])
+}
+
+func (r reader) sub(off int) reader {
+	if off <= 0 || off >= len(r) {
+		return nil
+	}
+	return r[off:]
+}
+
+// Parse reads the font from the contents of a TTF or OTF file, a collection
+// (TTC) is not supported
+func Parse(data []byte) (*Font, error) {
+	r := reader(data)
+	switch r.u32(0) {
+	case 0x00010000, 0x4F54544F, 0x74727565: // 1.0, OTTO, true
+	default:
+		return nil, ErrNotOpenType
+	}
+	tables := map[string]reader{}
+	count := int(r.u16(4))
+	for i := range count {
+		rec := 12 + i*16
+		off, length := int(r.u32(rec+8)), int(r.u32(rec+12))
+		if off+length > len(data) {
+			return nil, fmt.Errorf("the %q table is outside of the font data", r.tag(rec))
+		}
+		tables[r.tag(rec)] = reader(data[off : off+length])
+	}
+	f := &Font{
+		UnitsPerEm: tables["head"].u16(18),
+		NumGlyphs:  tables["maxp"].u16(4),
+		runes:      map[uint16]rune{},
+	}
+	if f.UnitsPerEm == 0 {
+		return nil, ErrNotOpenType
+	}
+	f.Cmap = parseCmap(tables["cmap"])
+	for r, g := range f.Cmap {
+		if cur, ok := f.runes[g]; !ok || r < cur {
+			f.runes[g] = r
+		}
+	}
+	f.gsub = parseLayoutTable(tables["GSUB"], false)
+	f.gpos = parseLayoutTable(tables["GPOS"], true)
+	return f, nil
+}
+
+// GlyphRune returns the rune that the glyph is stored under in the atlas.
+// Glyphs without a character, such as ligatures and joining forms, use the
+// supplementary private use area A starting at U+F0000
+func (f *Font) GlyphRune(glyph uint16) rune {
+	if r, ok := f.runes[glyph]; ok {
+		return r
+	}
+	return privateGlyphBase + rune(glyph)
+}
+
+const privateGlyphBase = 0xF0000
+
+func parseCmap(r reader) map[rune]uint16 {
+	out := map[rune]uint16{}
+	var best reader
+	bestScore := 0
+	for i := range int(r.u16(2)) {
+		rec := 4 + i*8
+		platform, encoding := r.u16(rec), r.u16(rec+2)
+		sub := r.sub(int(r.u32(rec + 4)))
+		score := 0
+		switch {
+		case (platform == 3 && encoding == 10) || (platform == 0 && (encoding == 4 || encoding == 6)):
+			score = 3
+		case (platform == 3 && encoding == 1) || platform == 0:
+			score = 2
+		case platform == 3 && encoding == 0:
+			score = 1
+		}
+		if score > bestScore && sub != nil {
+			best, bestScore = sub, score
+		}
+	}
+	switch best.u16(0) {
+	case 4:
+		segX2 := int(best.u16(6))
+		ends, starts := 14, 16+segX2
+		deltas, ranges := starts+segX2, starts+2*segX2
+		for s := 0; s < segX2; s += 2 {
+			start, end := rune(best.u16(starts+s)), rune(best.u16(ends+s))
+			delta, rangeOff := best.u16(deltas+s), int(best.u16(ranges+s))
+			for c := start; c <= end && c != 0xFFFF; c++ {
+				var g uint16
+				if rangeOff == 0 {
+					g = uint16(c) + delta
+				} else if g = best.u16(ranges + s + rangeOff + int(c-start)*2); g != 0 {
+					g += delta
+				}
+				if g != 0 {
+					out[c] = g
+				}
+			}
+		}
+	case 12:
+		for i := range int(best.u32(12)) {
+			grp := 16 + i*12
+			start, end, g := rune(best.u32(grp)), rune(best.u32(grp+4)), best.u32(grp+8)
+			for c := start; c <= end && c <= 0x10FFFF; c++ {
+				out[c] = uint16(g + uint32(c-start))
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,303 @@
+/******************************************************************************/
+/* opentype_test.go                                                           */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package opentype
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"testing"
+)
+
+const (
+	glyphF = iota + 1
+	glyphI
+	glyphFI
+	glyphA
+	glyphV
+	glyphLowerA
+	glyphSmallA
+	glyphBeh
+	glyphBehInit
+	glyphBehMedi
+	glyphBehFina
+	glyphAlef
+	glyphAlefFina
+	glyphX
+	glyphXAlt
+	glyphCount
+)
+
+var testCmap = map[rune]uint16{
+	'f': glyphF, 'i': glyphI, 'A': glyphA, 'V': glyphV, 'a': glyphLowerA,
+	'x': glyphX, 0x0628: glyphBeh, 0x0627: glyphAlef,
+}
+
+func be16(values ...int) []byte {
+	out := make([]byte, len(values)*2)
+	for i, v := range values {
+		binary.BigEndian.PutUint16(out[i*2:], uint16(v))
+	}
+	return out
+}
+
+// withChildren appends the children after the header and writes their
+// offsets, relative to the start of the header, at the given positions
+func withChildren(header []byte, offsetAt []int, children ...[]byte) []byte {
+	out := slices.Clone(header)
+	for i, c := range children {
+		binary.BigEndian.PutUint16(out[offsetAt[i]:], uint16(len(out)))
+		out = append(out, c...)
+	}
+	return out
+}
+
+func coverage(glyphs ...int) []byte { return be16(append([]int{1, len(glyphs)}, glyphs...)...) }
+
+type testFeature struct {
+	tag     string
+	lookups []int
+}
+
+type testLookup struct {
+	kind int
+	sub  []byte
+}
+
+func testLayout(features []testFeature, lookups []testLookup) []byte {
+	indices := []int{0, 0xFFFF, len(features)}
+	for i := range features {
+		indices = append(indices, i)
+	}
+	script := withChildren(be16(0, 0), []int{0}, be16(indices...))
+	scripts := withChildren(append(be16(1), append([]byte("DFLT"), be16(0)...)...), []int{6}, script)
+	featHeader := be16(len(features))
+	var featTables [][]byte
+	var featOffsets []int
+	for i, f := range features {
+		featHeader = append(append(featHeader, f.tag...), be16(0)...)
+		featOffsets = append(featOffsets, 2+i*6+4)
+		featTables = append(featTables, be16(append([]int{0, len(f.lookups)}, f.lookups...)...))
+	}
+	featureList := withChildren(featHeader, featOffsets, featTables...)
+	lookupHeader := be16(len(lookups))
+	var lookupTables [][]byte
+	var lookupOffsets []int
+	for i, l := range lookups {
+		lookupHeader = append(lookupHeader, be16(0)...)
+		lookupOffsets = append(lookupOffsets, 2+i*2)
+		lookupTables = append(lookupTables, withChildren(be16(l.kind, 0, 1, 0), []int{6}, l.sub))
+	}
+	lookupList := withChildren(lookupHeader, lookupOffsets, lookupTables...)
+	return withChildren(be16(1, 0, 0, 0, 0), []int{4, 6, 8}, scripts, featureList, lookupList)
+}
+
+func testFont() []byte {
+	runes := make([]rune, 0, len(testCmap))
+	for r := range testCmap {
+		runes = append(runes, r)
+	}
+	slices.Sort(runes)
+	segs := len(runes) + 1
+	var ends, starts, deltas, ranges []int
+	for _, r := range runes {
+		ends, starts = append(ends, int(r)), append(starts, int(r))
+		deltas, ranges = append(deltas, int(testCmap[r])-int(r)), append(ranges, 0)
+	}
+	ends, starts, deltas, ranges = append(ends, 0xFFFF), append(starts, 0xFFFF), append(deltas, 1), append(ranges, 0)
+	format4 := be16(4, 0, 0, segs*2, 0, 0, 0)
+	format4 = append(format4, be16(ends...)...)
+	format4 = append(format4, be16(0)...)
+	format4 = append(format4, be16(starts...)...)
+	format4 = append(format4, be16(deltas...)...)
+	format4 = append(format4, be16(ranges...)...)
+	cmap := append(be16(0, 1, 3, 1, 0, 12), format4...)
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint16(head[18:], 1000)
+	gsub := testLayout([]testFeature{
+		{"liga", []int{0}}, {"smcp", []int{1}}, {"init", []int{2}},
+		{"medi", []int{3}}, {"fina", []int{4}}, {"calt", []int{5}},
+	}, []testLookup{
+		{gsubLigature, withChildren(be16(1, 0, 1, 0), []int{2, 6}, coverage(glyphF),
+			withChildren(be16(1, 0), []int{2}, be16(glyphFI, 2, glyphI)))},
+		{gsubSingle, withChildren(be16(2, 0, 1, glyphSmallA), []int{2}, coverage(glyphLowerA))},
+		{gsubSingle, withChildren(be16(1, 0, glyphBehInit-glyphBeh), []int{2}, coverage(glyphBeh))},
+		{gsubSingle, withChildren(be16(1, 0, glyphBehMedi-glyphBeh), []int{2}, coverage(glyphBeh))},
+		{gsubSingle, withChildren(be16(2, 0, 2, glyphBehFina, glyphAlefFina), []int{2}, coverage(glyphBeh, glyphAlef))},
+		{gsubChain, withChildren(be16(3, 0, 1, 0, 1, 0, 1, 0, 6), []int{6, 10}, coverage(glyphX), coverage(glyphF))},
+		{gsubSingle, withChildren(be16(1, 0, glyphXAlt-glyphX), []int{2}, coverage(glyphX))},
+	})
+	classDef1 := be16(1, glyphV, 1, 1)
+	classDef2 := be16(2, 1, glyphLowerA, glyphLowerA, 1)
+	gpos := testLayout([]testFeature{{"kern", []int{0, 1}}}, []testLookup{
+		{gposPair, withChildren(be16(1, 0, valueXAdvance, 0, 1, 0), []int{2, 10}, coverage(glyphA),
+			be16(1, glyphV, 0x10000-80))},
+		{gposPair, withChildren(be16(2, 0, valueXAdvance, 0, 0, 0, 2, 2, 0, 0, 0, 0x10000-50), []int{2, 8, 10},
+			coverage(glyphV), classDef1, classDef2)},
+	})
+	tables := []struct {
+		tag  string
+		data []byte
+	}{{"GPOS", gpos}, {"GSUB", gsub}, {"cmap", cmap}, {"head", head}, {"maxp", be16(0, 0x5000, glyphCount)}}
+	out := be16(1, 0, len(tables), 0, 0, 0)
+	offset := len(out) + len(tables)*16
+	for _, t := range tables {
+		out = append(out, t.tag...)
+		out = binary.BigEndian.AppendUint32(out, 0)
+		out = binary.BigEndian.AppendUint32(out, uint32(offset))
+		out = binary.BigEndian.AppendUint32(out, uint32(len(t.data)))
+		offset += len(t.data)
+	}
+	for _, t := range tables {
+		out = append(out, t.data...)
+	}
+	return out
+}
+
+func testShaping(t *testing.T, charset string) ([]uint16, *Shaping) {
+	t.Helper()
+	f, err := Parse(testFont())
+	if err != nil {
+		t.Fatal(err)
+	}
+	glyphs, s := f.Subset([]rune(charset))
+	return glyphs, s
+}
+
+func shapedRunes(glyphs []Glyph) []rune {
+	out := make([]rune, len(glyphs))
+	for i := range glyphs {
+		out[i] = glyphs[i].Rune
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	f, err := Parse(testFont())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.UnitsPerEm != 1000 || f.NumGlyphs != glyphCount {
+		t.Errorf("expected 1000 units per em and %d glyphs, got %d and %d", glyphCount, f.UnitsPerEm, f.NumGlyphs)
+	}
+	for r, g := range testCmap {
+		if f.Cmap[r] != g {
+			t.Errorf("expected %q to map to glyph %d, got %d", r, g, f.Cmap[r])
+		}
+	}
+	if f.GlyphRune(glyphF) != 'f' || f.GlyphRune(glyphFI) != privateGlyphBase+glyphFI {
+		t.Errorf("unexpected glyph runes %q and %X", f.GlyphRune(glyphF), f.GlyphRune(glyphFI))
+	}
+	if _, err := Parse([]byte("not a font")); !errors.Is(err, ErrNotOpenType) {
+		t.Errorf("expected ErrNotOpenType, got %v", err)
+	}
+}
+
+func TestSubsetIncludesSubstitutions(t *testing.T) {
+	glyphs, _ := testShaping(t, "fia")
+	for _, g := range []uint16{glyphF, glyphI, glyphFI, glyphLowerA, glyphSmallA} {
+		if !slices.Contains(glyphs, g) {
+			t.Errorf("expected glyph %d in the subset %v", g, glyphs)
+		}
+	}
+	if slices.Contains(glyphs, glyphBehInit) || slices.Contains(glyphs, glyphXAlt) {
+		t.Errorf("expected the subset to not contain forms of missing characters %v", glyphs)
+	}
+	glyphs, _ = testShaping(t, "f")
+	if slices.Contains(glyphs, glyphFI) {
+		t.Error("expected the ligature to be dropped when a component is missing")
+	}
+}
+
+func TestShapeLigature(t *testing.T) {
+	_, s := testShaping(t, "fia")
+	got := s.Shape([]rune("afi"), map[string]bool{"liga": true})
+	if !slices.Equal(shapedRunes(got), []rune{'a', privateGlyphBase + glyphFI}) || got[1].Cluster != 1 {
+		t.Errorf("unexpected ligature result %v", got)
+	}
+	if got := s.Shape([]rune("fi"), map[string]bool{}); !slices.Equal(shapedRunes(got), []rune("fi")) {
+		t.Errorf("expected no ligature when liga is disabled, got %v", got)
+	}
+	got = s.Shape([]rune("a"), map[string]bool{"smcp": true})
+	if !slices.Equal(shapedRunes(got), []rune{privateGlyphBase + glyphSmallA}) {
+		t.Errorf("expected small caps glyph, got %v", got)
+	}
+}
+
+func TestShapeKerning(t *testing.T) {
+	_, s := testShaping(t, "AVa")
+	got := s.Shape([]rune("AVa"), map[string]bool{"kern": true})
+	if got[0].Kern != -0.08 || got[1].Kern != -0.05 || got[2].Kern != 0 {
+		t.Errorf("unexpected kerning %v", got)
+	}
+	got = s.Shape([]rune("AV"), map[string]bool{})
+	if got[0].Kern != 0 {
+		t.Errorf("expected no kerning when kern is disabled, got %v", got)
+	}
+}
+
+func TestShapeJoining(t *testing.T) {
+	_, s := testShaping(t, "با")
+	tests := []struct {
+		text string
+		want []rune
+	}{
+		{"ب", []rune{0x0628}},
+		{"ببب", []rune{privateGlyphBase + glyphBehInit, privateGlyphBase + glyphBehMedi, privateGlyphBase + glyphBehFina}},
+		{"با", []rune{privateGlyphBase + glyphBehInit, privateGlyphBase + glyphAlefFina}},
+		{"اب", []rune{0x0627, 0x0628}},
+		{"بَب", []rune{privateGlyphBase + glyphBehInit, 0x064E, privateGlyphBase + glyphBehFina}},
+	}
+	for _, test := range tests {
+		if got := shapedRunes(s.Shape([]rune(test.text), nil)); !slices.Equal(got, test.want) {
+			t.Errorf("%q: expected %X, got %X", test.text, test.want, got)
+		}
+	}
+}
+
+func TestShapeContextual(t *testing.T) {
+	_, s := testShaping(t, "xfa")
+	got := s.Shape([]rune("xfxa"), map[string]bool{"calt": true})
+	if !slices.Equal(shapedRunes(got), []rune{privateGlyphBase + glyphXAlt, 'f', 'x', 'a'}) {
+		t.Errorf("unexpected contextual result %X", shapedRunes(got))
+	}
+}
+
+func TestShapeIndicReorder(t *testing.T) {
+	s := &Shaping{}
+	got := s.Shape([]rune("कि"), nil)
+	if !slices.Equal(shapedRunes(got), []rune{0x093F, 0x0915}) || got[0].Cluster != 1 {
+		t.Errorf("expected the matra to move before the consonant, got %v", got)
+	}
+	got = s.Shape([]rune("क्षि"), nil)
+	if !slices.Equal(shapedRunes(got), []rune{0x093F, 0x0915, 0x094D, 0x0937}) {
+		t.Errorf("expected the matra to move before the conjunct, got %X", shapedRunes(got))
+	}
+}
+
+func TestEncodeDecodeShaping(t *testing.T) {
+	_, s := testShaping(t, "fiaAVxبا")
+	var buf bytes.Buffer
+	if err := s.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeShaping(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	features := map[string]bool{"liga": true, "calt": true, "kern": true}
+	for _, text := range []string{"fixfAV", "ببا", "xa"} {
+		want, got := s.Shape([]rune(text), features), decoded.Shape([]rune(text), features)
+		if !slices.Equal(want, got) {
+			t.Errorf("%q: expected %v, got %v", text, want, got)
+		}
+	}
+	if _, err := DecodeShaping(bytes.NewReader(nil)); !errors.Is(err, ErrNoShaping) {
+		t.Errorf("expected ErrNoShaping, got %v", err)
+	}
+}
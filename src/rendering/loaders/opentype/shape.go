@@ -0,0 +1,166 @@
+/******************************************************************************/
+/* shape.go                                                                   */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package opentype
+
+// Glyph is a single glyph produced by shaping, Cluster is the index of the
+// character in the source text it was created from and Kern is the advance
+// adjustment to the next glyph in em units
+type Glyph struct {
+	Rune    rune
+	Cluster int
+	Kern    float32
+}
+
+type shapeGlyph struct {
+	Glyph
+	mask uint8
+}
+
+const (
+	maskGlobal = 1 << iota
+	maskIsol
+	maskFina
+	maskMedi
+	maskInit
+	maxNestedLookups = 8
+)
+
+var joiningFeatures = map[string]uint8{
+	"isol": maskIsol,
+	"fina": maskFina,
+	"medi": maskMedi,
+	"init": maskInit,
+}
+
+// Shape replaces the characters of the text with the glyphs of the font by
+// applying the substitution lookups of the enabled features in lookup order
+// and then kerning. The joining forms (isol, init, medi, fina) are enabled
+// per character based on the joining of the surrounding characters. The text
+// should be a single line in logical order
+func (s *Shaping) Shape(text []rune, features map[string]bool) []Glyph {
+	buf := make([]shapeGlyph, len(text))
+	for i, r := range text {
+		buf[i] = shapeGlyph{Glyph{Rune: r, Cluster: i}, maskGlobal}
+	}
+	reorderIndic(buf)
+	joiningMasks(buf)
+	masks := make([]uint8, len(s.Lookups))
+	for tag, lookups := range s.Features {
+		mask, joining := joiningFeatures[tag]
+		if !joining {
+			if !features[tag] {
+				continue
+			}
+			mask = maskGlobal
+		}
+		for _, idx := range lookups {
+			if idx < len(masks) {
+				masks[idx] |= mask
+			}
+		}
+	}
+	for li, mask := range masks {
+		if mask == 0 {
+			continue
+		}
+		for i := 0; i < len(buf); {
+			if buf[i].mask&mask == 0 {
+				i++
+			} else if n, ok := s.apply(li, &buf, i, 0); ok {
+				i += n
+			} else {
+				i++
+			}
+		}
+	}
+	out := make([]Glyph, len(buf))
+	for i := range buf {
+		out[i] = buf[i].Glyph
+		if features["kern"] && i+1 < len(buf) {
+			out[i].Kern = s.Kerning[[2]rune{buf[i].Rune, buf[i+1].Rune}]
+		}
+	}
+	return out
+}
+
+// apply runs the lookup on the glyph at i and returns how many glyphs the
+// result takes up in the buffer
+func (s *Shaping) apply(li int, buf *[]shapeGlyph, i, depth int) (int, bool) {
+	if li < 0 || li >= len(s.Lookups) || i >= len(*buf) || depth > maxNestedLookups {
+		return 0, false
+	}
+	l := &s.Lookups[li]
+	g := (*buf)[i]
+	if to, ok := l.Single[g.Rune]; ok {
+		(*buf)[i].Rune = to
+		return 1, true
+	}
+	if seq, ok := l.Multiple[g.Rune]; ok {
+		out := make([]shapeGlyph, len(seq))
+		for j, r := range seq {
+			out[j] = g
+			out[j].Rune = r
+		}
+		*buf = append((*buf)[:i], append(out, (*buf)[i+1:]...)...)
+		return len(seq), true
+	}
+	for _, lig := range l.Ligatures[g.Rune] {
+		if i+len(lig.Components) >= len(*buf) {
+			continue
+		}
+		match := true
+		for j, r := range lig.Components {
+			if (*buf)[i+1+j].Rune != r {
+				match = false
+				break
+			}
+		}
+		if match {
+			(*buf)[i].Rune = lig.Glyph
+			*buf = append((*buf)[:i+1], (*buf)[i+1+len(lig.Components):]...)
+			return 1, true
+		}
+	}
+	for _, c := range l.Chains {
+		if !c.matches(*buf, i) {
+			continue
+		}
+		length := len(c.Input)
+		for _, a := range c.Actions {
+			if a.Sequence >= length {
+				continue
+			}
+			before := len(*buf)
+			s.apply(a.Lookup, buf, i+a.Sequence, depth+1)
+			length += len(*buf) - before
+		}
+		return max(length, 0), true
+	}
+	return 0, false
+}
+
+func (c *ChainRule) matches(buf []shapeGlyph, i int) bool {
+	if len(c.Input) == 0 || i < len(c.Backtrack) || i+len(c.Input)+len(c.Lookahead) > len(buf) {
+		return false
+	}
+	for j, set := range c.Backtrack {
+		if !set.Has(buf[i-1-j].Rune) {
+			return false
+		}
+	}
+	for j, set := range c.Input {
+		if !set.Has(buf[i+j].Rune) {
+			return false
+		}
+	}
+	for j, set := range c.Lookahead {
+		if !set.Has(buf[i+len(c.Input)+j].Rune) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,384 @@
+/******************************************************************************/
+/* shaping.go                                                                 */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package opentype
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+	"sort"
+)
+
+// Shaping holds the substitution and kerning tables of a font for the
+// characters that were packed into its atlas. Glyphs are addressed by the
+// rune they are stored under in the atlas, see [Font.GlyphRune]
+type Shaping struct {
+	Lookups []Lookup
+	// Features maps a feature tag to the lookups it enables
+	Features map[string][]int
+	// Kerning is the advance adjustment between two glyphs in em units
+	Kerning map[[2]rune]float32
+}
+
+// Lookup is a single substitution lookup, only one of the tables is set
+// for a lookup as all subtables of a lookup share the same type
+type Lookup struct {
+	Single    map[rune]rune
+	Multiple  map[rune][]rune
+	Ligatures map[rune][]Ligature
+	Chains    []ChainRule
+}
+
+// Ligature replaces the glyph it is stored under followed by the components
+// with a single glyph
+type Ligature struct {
+	Components []rune
+	Glyph      rune
+}
+
+// ChainRule applies the actions to the input sequence when the glyphs before
+// and after it match the backtrack and lookahead sets
+type ChainRule struct {
+	// Backtrack is stored nearest glyph first
+	Backtrack, Input, Lookahead []RuneSet
+	Actions                     []ChainAction
+}
+
+// RuneSet matches the runes it holds, or every rune it doesn't hold when Not
+// is set
+type RuneSet struct {
+	Runes []rune
+	Not   bool
+}
+
+// ChainAction applies the lookup to the glyph at the input sequence index
+type ChainAction struct {
+	Sequence int
+	Lookup   int
+}
+
+func (s RuneSet) Has(r rune) bool {
+	_, found := slices.BinarySearch(s.Runes, r)
+	return found != s.Not
+}
+
+// Subset returns the glyphs needed to draw the charset, including every
+// glyph the font can substitute them with, along with the shaping tables
+// for those glyphs
+func (f *Font) Subset(charset []rune) ([]uint16, *Shaping) {
+	keep := map[uint16]bool{0: true}
+	for _, r := range charset {
+		if g, ok := f.Cmap[r]; ok {
+			keep[g] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		add := func(g uint16) {
+			if !keep[g] {
+				keep[g], changed = true, true
+			}
+		}
+		for _, l := range f.gsub.lookups {
+			for from, to := range l.single {
+				if keep[from] {
+					add(to)
+				}
+			}
+			for from, to := range l.multiple {
+				if keep[from] {
+					for _, g := range to {
+						add(g)
+					}
+				}
+			}
+			for from, ligs := range l.ligatures {
+				for _, lig := range ligs {
+					if keep[from] && !slices.ContainsFunc(lig.components, func(g uint16) bool { return !keep[g] }) {
+						add(lig.glyph)
+					}
+				}
+			}
+		}
+	}
+	glyphs := make([]uint16, 0, len(keep))
+	for g := range keep {
+		glyphs = append(glyphs, g)
+	}
+	slices.Sort(glyphs)
+	return glyphs, f.shaping(keep)
+}
+
+func (f *Font) shaping(keep map[uint16]bool) *Shaping {
+	s := &Shaping{
+		Lookups:  make([]Lookup, len(f.gsub.lookups)),
+		Features: f.gsub.features,
+		Kerning:  map[[2]rune]float32{},
+	}
+	runes := func(glyphs []uint16) []rune {
+		out := make([]rune, len(glyphs))
+		for i, g := range glyphs {
+			out[i] = f.GlyphRune(g)
+		}
+		return out
+	}
+	sets := func(in []glyphSet) []RuneSet {
+		out := make([]RuneSet, len(in))
+		for i, set := range in {
+			out[i].Not = set.not
+			for g := range set.glyphs {
+				if keep[g] {
+					out[i].Runes = append(out[i].Runes, f.GlyphRune(g))
+				}
+			}
+			slices.Sort(out[i].Runes)
+		}
+		return out
+	}
+	for i, l := range f.gsub.lookups {
+		dst := &s.Lookups[i]
+		for from, to := range l.single {
+			if keep[from] {
+				if dst.Single == nil {
+					dst.Single = map[rune]rune{}
+				}
+				dst.Single[f.GlyphRune(from)] = f.GlyphRune(to)
+			}
+		}
+		for from, to := range l.multiple {
+			if keep[from] {
+				if dst.Multiple == nil {
+					dst.Multiple = map[rune][]rune{}
+				}
+				dst.Multiple[f.GlyphRune(from)] = runes(to)
+			}
+		}
+		for from, ligs := range l.ligatures {
+			for _, lig := range ligs {
+				if keep[from] && keep[lig.glyph] {
+					if dst.Ligatures == nil {
+						dst.Ligatures = map[rune][]Ligature{}
+					}
+					r := f.GlyphRune(from)
+					dst.Ligatures[r] = append(dst.Ligatures[r], Ligature{runes(lig.components), f.GlyphRune(lig.glyph)})
+				}
+			}
+		}
+		for _, c := range l.chains {
+			dst.Chains = append(dst.Chains, ChainRule{
+				Backtrack: sets(c.backtrack),
+				Input:     sets(c.input),
+				Lookahead: sets(c.lookahead),
+				Actions:   c.actions,
+			})
+		}
+	}
+	kern := f.gpos.features["kern"]
+	for a := range keep {
+		for b := range keep {
+			for _, idx := range kern {
+				if v, ok := f.gpos.lookups[idx].kerning(a, b); ok {
+					if v != 0 {
+						s.Kerning[[2]rune{f.GlyphRune(a), f.GlyphRune(b)}] = float32(v) / float32(f.UnitsPerEm)
+					}
+					break
+				}
+			}
+		}
+	}
+	return s
+}
+
+var shapingMagic = [4]byte{'S', 'H', 'P', '1'}
+
+// Encode writes the shaping tables in the binary form that is appended to
+// the font .bin files
+func (s *Shaping) Encode(w io.Writer) error {
+	var buf bytes.Buffer
+	u32 := func(v int) { binary.Write(&buf, binary.LittleEndian, int32(v)) }
+	runes := func(list []rune) {
+		u32(len(list))
+		binary.Write(&buf, binary.LittleEndian, list)
+	}
+	sets := func(list []RuneSet) {
+		u32(len(list))
+		for _, set := range list {
+			runes(set.Runes)
+			binary.Write(&buf, binary.LittleEndian, set.Not)
+		}
+	}
+	buf.Write(shapingMagic[:])
+	tags := make([]string, 0, len(s.Features))
+	for tag := range s.Features {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	u32(len(tags))
+	for _, tag := range tags {
+		buf.WriteString((tag + "    ")[:4])
+		u32(len(s.Features[tag]))
+		for _, idx := range s.Features[tag] {
+			u32(idx)
+		}
+	}
+	u32(len(s.Lookups))
+	for _, l := range s.Lookups {
+		keys := sortedKeys(l.Single)
+		u32(len(keys))
+		for _, k := range keys {
+			runes([]rune{k, l.Single[k]})
+		}
+		keys = sortedKeys(l.Multiple)
+		u32(len(keys))
+		for _, k := range keys {
+			runes(append([]rune{k}, l.Multiple[k]...))
+		}
+		keys = sortedKeys(l.Ligatures)
+		u32(len(keys))
+		for _, k := range keys {
+			u32(int(k))
+			u32(len(l.Ligatures[k]))
+			for _, lig := range l.Ligatures[k] {
+				runes(append([]rune{lig.Glyph}, lig.Components...))
+			}
+		}
+		u32(len(l.Chains))
+		for _, c := range l.Chains {
+			sets(c.Backtrack)
+			sets(c.Input)
+			sets(c.Lookahead)
+			u32(len(c.Actions))
+			for _, a := range c.Actions {
+				u32(a.Sequence)
+				u32(a.Lookup)
+			}
+		}
+	}
+	pairs := make([][2]rune, 0, len(s.Kerning))
+	for p := range s.Kerning {
+		pairs = append(pairs, p)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i][0] < pairs[j][0] || (pairs[i][0] == pairs[j][0] && pairs[i][1] < pairs[j][1])
+	})
+	u32(len(pairs))
+	for _, p := range pairs {
+		binary.Write(&buf, binary.LittleEndian, p)
+		binary.Write(&buf, binary.LittleEndian, s.Kerning[p])
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func sortedKeys[V any](m map[rune]V) []rune {
+	keys := make([]rune, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// DecodeShaping reads shaping tables written by [Shaping.Encode], it returns
+// [ErrNoShaping] when the reader is at its end or holds something else
+func DecodeShaping(r io.Reader) (*Shaping, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil || magic != shapingMagic {
+		return nil, ErrNoShaping
+	}
+	var err error
+	read := func(v any) {
+		if err == nil {
+			err = binary.Read(r, binary.LittleEndian, v)
+		}
+	}
+	count := func() int {
+		var v int32
+		read(&v)
+		if v < 0 || err != nil {
+			err = errors.Join(err, errors.New("invalid count in the shaping tables"))
+			return 0
+		}
+		return int(v)
+	}
+	runes := func() []rune {
+		list := make([]rune, count())
+		read(list)
+		return list
+	}
+	sets := func() []RuneSet {
+		list := make([]RuneSet, count())
+		for i := range list {
+			list[i].Runes = runes()
+			read(&list[i].Not)
+		}
+		return list
+	}
+	s := &Shaping{Features: map[string][]int{}, Kerning: map[[2]rune]float32{}}
+	for range count() {
+		var tag [4]byte
+		read(&tag)
+		list := make([]int, count())
+		for i := range list {
+			list[i] = count()
+		}
+		s.Features[string(tag[:])] = list
+	}
+	s.Lookups = make([]Lookup, count())
+	for i := range s.Lookups {
+		l := &s.Lookups[i]
+		if n := count(); n > 0 {
+			l.Single = make(map[rune]rune, n)
+			for range n {
+				if pair := runes(); len(pair) == 2 {
+					l.Single[pair[0]] = pair[1]
+				}
+			}
+		}
+		if n := count(); n > 0 {
+			l.Multiple = make(map[rune][]rune, n)
+			for range n {
+				if seq := runes(); len(seq) > 0 {
+					l.Multiple[seq[0]] = seq[1:]
+				}
+			}
+		}
+		if n := count(); n > 0 {
+			l.Ligatures = make(map[rune][]Ligature, n)
+			for range n {
+				first := rune(count())
+				for range count() {
+					if seq := runes(); len(seq) > 0 {
+						l.Ligatures[first] = append(l.Ligatures[first], Ligature{seq[1:], seq[0]})
+					}
+				}
+			}
+		}
+		l.Chains = make([]ChainRule, count())
+		for j := range l.Chains {
+			c := &l.Chains[j]
+			c.Backtrack, c.Input, c.Lookahead = sets(), sets(), sets()
+			c.Actions = make([]ChainAction, count())
+			for k := range c.Actions {
+				c.Actions[k] = ChainAction{count(), count()}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for range count() {
+		var pair [2]rune
+		var v float32
+		read(&pair)
+		read(&v)
+		s.Kerning[pair] = v
+	}
+	return s, err
+}
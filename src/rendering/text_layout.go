@@ -0,0 +1,700 @@
+/******************************************************************************/
+/* text_layout.go                                                             */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package rendering
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// TextWhiteSpace controls how white space in the source text is collapsed
+// and whether lines are allowed to wrap. The zero value matches the
+// historical label behavior of keeping the text as is while wrapping.
+type TextWhiteSpace int
+
+const (
+	TextWhiteSpacePreWrap = TextWhiteSpace(iota)
+	TextWhiteSpaceNormal
+	TextWhiteSpaceNoWrap
+	TextWhiteSpacePre
+	TextWhiteSpacePreLine
+	TextWhiteSpaceBreakSpaces
+)
+
+type TextWordBreak int
+
+const (
+	TextWordBreakNormal = TextWordBreak(iota)
+	TextWordBreakBreakAll
+	TextWordBreakKeepAll
+)
+
+type TextOverflowWrap int
+
+const (
+	TextOverflowWrapNormal = TextOverflowWrap(iota)
+	TextOverflowWrapBreakWord
+	TextOverflowWrapAnywhere
+)
+
+type TextHyphens int
+
+const (
+	TextHyphensManual = TextHyphens(iota)
+	TextHyphensNone
+	TextHyphensAuto
+)
+
+type TextLineBreak int
+
+const (
+	TextLineBreakAuto = TextLineBreak(iota)
+	TextLineBreakLoose
+	TextLineBreakNormal
+	TextLineBreakStrict
+	TextLineBreakAnywhere
+)
+
+type TextOverflow int
+
+const (
+	TextOverflowClip = TextOverflow(iota)
+	TextOverflowEllipsis
+)
+
+const (
+	softHyphen        = '\u00AD'
+	zeroWidthSpace    = '\u200B'
+	defaultTabSize    = 8
+	defaultEllipsis   = "…"
+	textLayoutEpsilon = 0.001
+	// Characters that may not start a line (kinsoku shori)
+	kinsokuNoStart = "、。，．,.・：；:;？！?!）)］]｝}」』】〕〉》〟’”ゝゞ々"
+	// Characters that may start a line when line-break is loose
+	kinsokuLooseStart = "・：；ゝゞ々"
+	// Small kana and the prolonged sound mark only stay attached when strict
+	kinsokuStrictNoStart = "ぁぃぅぇぉっゃゅょゎァィゥェォッャュョヮヵヶー"
+	// Characters that may not end a line
+	kinsokuNoEnd = "（(［[｛{「『【〔〈《〝‘“"
+)
+
+// TextLayout describes how a run of text is broken into lines. Widths are in
+// the same units returned by the advance function given to LayoutText.
+type TextLayout struct {
+	// MaxWidth is the width lines wrap at, zero or less will not wrap
+	MaxWidth      float32
+	LetterSpacing float32
+	WordSpacing   float32
+	// TabSize is the width of a tab stop in spaces, zero uses the default 8
+	TabSize float32
+	// Indent offsets the first line of the text
+	Indent       float32
+	WhiteSpace   TextWhiteSpace
+	WordBreak    TextWordBreak
+	OverflowWrap TextOverflowWrap
+	LineBreak    TextLineBreak
+	Hyphens      TextHyphens
+	// Language selects the dictionary used for TextHyphensAuto
+	Language string
+	Overflow TextOverflow
+	// Direction is the base direction of the text, lines are reordered for
+	// display with the Unicode Bidirectional Algorithm
+	Direction TextDirection
+	Bidi      TextBidi
+	// Ellipsis replaces the clipped text when Overflow is TextOverflowEllipsis,
+	// an empty string will use "…"
+	Ellipsis string
+	// MaxLines clamps the number of lines, zero or less is unlimited
+	MaxLines int
+	// Features selects the font features used when the text is shaped
+	Features FontFeatures
+}
+
+// TextGlyph is a single placed character of a laid out line. Index is the
+// rune index in the source text, or -1 for characters that were inserted by
+// the layout, such as an automatic hyphen or the ellipsis.
+type TextGlyph struct {
+	Rune    rune
+	Index   int
+	X       float32
+	Advance float32
+	level   uint8
+}
+
+type TextLine struct {
+	Glyphs []TextGlyph
+	// Width is the extent of the line, not counting trailing hanging spaces
+	Width float32
+	// Direction is the resolved base direction of the paragraph of the line
+	Direction TextDirection
+}
+
+// ShapedGlyph is a glyph returned from a TextShaper. Cluster is the index of
+// the character it was created from within the text given to the shaper and
+// Kern adjusts the advance to the next glyph
+type ShapedGlyph struct {
+	Rune    rune
+	Cluster int
+	Kern    float32
+}
+
+// TextShaper replaces a line of characters in logical order with the glyphs
+// of the font that draw them, applying kerning, ligatures and joining forms
+type TextShaper func(text []rune) []ShapedGlyph
+
+type textItem struct {
+	r     rune
+	idx   int
+	level uint8
+}
+
+type textBreaker struct {
+	layout    TextLayout
+	advance   func(rune) float32
+	shaper    TextShaper
+	hyph      Hyphenator
+	lines     []TextLine
+	line      []TextGlyph
+	x         float32
+	direction TextDirection
+	bidi      bool
+}
+
+// RightToLeft reports if the glyph is displayed in a right-to-left run
+func (g TextGlyph) RightToLeft() bool { return g.level&1 == 1 }
+
+// Visible reports if the glyph should be drawn
+func (g TextGlyph) Visible() bool {
+	return !isInvisibleRune(g.Rune)
+}
+
+func isInvisibleRune(r rune) bool {
+	switch r {
+	case '\n', '\r', softHyphen, zeroWidthSpace:
+		return true
+	}
+	// Bidirectional formatting characters
+	return r == '\u200E' || r == '\u200F' || (r >= '\u202A' && r <= '\u202E') ||
+		(r >= '\u2066' && r <= '\u2069')
+}
+
+// Forced reports if the line ended because of a new line in the text
+func (l TextLine) Forced() bool {
+	return len(l.Glyphs) > 0 && l.Glyphs[len(l.Glyphs)-1].Rune == '\n'
+}
+
+// Wraps reports if the layout allows soft wrapping of lines
+func (l TextLayout) Wraps() bool {
+	return l.MaxWidth > 0 && l.WhiteSpace != TextWhiteSpaceNoWrap &&
+		l.WhiteSpace != TextWhiteSpacePre
+}
+
+func (l TextLayout) collapsesSpaces() bool {
+	switch l.WhiteSpace {
+	case TextWhiteSpaceNormal, TextWhiteSpaceNoWrap, TextWhiteSpacePreLine:
+		return true
+	}
+	return false
+}
+
+func (l TextLayout) keepsNewLines() bool {
+	return l.WhiteSpace != TextWhiteSpaceNormal && l.WhiteSpace != TextWhiteSpaceNoWrap
+}
+
+func (l TextLayout) hangsSpaces() bool {
+	return l.WhiteSpace != TextWhiteSpaceBreakSpaces
+}
+
+func isBreakSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\u3000'
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
+
+// LayoutText breaks the text into lines following the rules of the layout.
+// The advance function returns the scaled width of a character.
+func LayoutText(text []rune, layout TextLayout, advance func(rune) float32) []TextLine {
+	return LayoutShapedText(text, layout, advance, nil)
+}
+
+// LayoutShapedText is LayoutText with each line passed through the shaper
+// once it is broken. Lines are broken using the unshaped advances so that
+// ligatures never span a line break.
+func LayoutShapedText(text []rune, layout TextLayout, advance func(rune) float32, shaper TextShaper) []TextLine {
+	b := textBreaker{layout: layout, advance: advance, shaper: shaper, x: layout.Indent}
+	if layout.Hyphens == TextHyphensAuto {
+		b.hyph, _ = HyphenatorFor(layout.Language)
+	}
+	items := layout.collapse(text)
+	for start := 0; start < len(items); {
+		end := start
+		for end < len(items) && items[end].r != '\n' {
+			end++
+		}
+		forced := end < len(items)
+		if forced {
+			end++
+		}
+		b.layoutParagraph(items[start:end])
+		if forced {
+			b.endLine()
+		}
+		start = end
+	}
+	if len(b.line) > 0 {
+		b.endLine()
+	}
+	b.clamp()
+	b.reorder()
+	return b.lines
+}
+
+func (l TextLayout) collapse(text []rune) []textItem {
+	items := make([]textItem, 0, len(text))
+	collapse := l.collapsesSpaces()
+	keepNewLines := l.keepsNewLines()
+	for i, r := range text {
+		if r == '\n' && !keepNewLines {
+			r = ' '
+		}
+		if collapse {
+			if r == '\t' || r == '\r' {
+				r = ' '
+			}
+			last := len(items) - 1
+			if r == ' ' && (last < 0 || items[last].r == ' ' || items[last].r == '\n') {
+				continue
+			}
+			if r == '\n' && last >= 0 && items[last].r == ' ' {
+				items = items[:last]
+			}
+		}
+		items = append(items, textItem{r: r, idx: i})
+	}
+	return items
+}
+
+func (l TextLayout) canBreakAfter(p []textItem, i int) bool {
+	r, next := p[i].r, p[i+1].r
+	if next == '\n' {
+		return false
+	}
+	if isBreakSpace(r) {
+		return l.WhiteSpace == TextWhiteSpaceBreakSpaces || !isBreakSpace(next)
+	}
+	if isBreakSpace(next) {
+		return false
+	}
+	if l.LineBreak == TextLineBreakAnywhere {
+		return true
+	}
+	switch r {
+	case zeroWidthSpace:
+		return true
+	case softHyphen:
+		return l.Hyphens != TextHyphensNone
+	case '-', '\u2010':
+		if i > 0 && unicode.IsLetter(p[i-1].r) && unicode.IsLetter(next) {
+			return true
+		}
+	}
+	if l.WordBreak == TextWordBreakBreakAll {
+		return !l.noBreakBetween(r, next)
+	}
+	if isCJK(r) || isCJK(next) {
+		if l.WordBreak == TextWordBreakKeepAll {
+			return false
+		}
+		return !l.noBreakBetween(r, next)
+	}
+	return false
+}
+
+func (l TextLayout) noBreakBetween(r, next rune) bool {
+	if strings.ContainsRune(kinsokuNoEnd, r) {
+		return true
+	}
+	if strings.ContainsRune(kinsokuNoStart, next) {
+		return l.LineBreak != TextLineBreakLoose ||
+			!strings.ContainsRune(kinsokuLooseStart, next)
+	}
+	return l.LineBreak == TextLineBreakStrict &&
+		strings.ContainsRune(kinsokuStrictNoStart, next)
+}
+
+func (b *textBreaker) glyphAdvance(r rune, x float32) float32 {
+	if isInvisibleRune(r) {
+		return 0
+	}
+	switch r {
+	case '\t':
+		tabSize := b.layout.TabSize
+		if tabSize <= 0 {
+			tabSize = defaultTabSize
+		}
+		stop := tabSize * (b.advance(' ') + b.layout.WordSpacing)
+		if stop <= 0 {
+			return 0
+		}
+		next := (float32(int((x+textLayoutEpsilon)/stop)) + 1) * stop
+		return next - x
+	case ' ', '\u00A0', '\u3000':
+		return b.advance(r) + b.layout.WordSpacing
+	}
+	return b.advance(r)
+}
+
+func (b *textBreaker) place(it textItem, x float32) (TextGlyph, float32) {
+	g := TextGlyph{Rune: it.r, Index: it.idx, X: x, Advance: b.glyphAdvance(it.r, x), level: it.level}
+	x += g.Advance
+	if g.Advance > 0 {
+		x += b.layout.LetterSpacing
+	}
+	return g, x
+}
+
+// contentEnd is where the items would end if placed at x, not counting any
+// trailing spaces that are allowed to hang past the end of the line
+func (b *textBreaker) contentEnd(items []textItem, x float32) float32 {
+	end := x
+	hang := b.layout.hangsSpaces()
+	for _, it := range items {
+		var g TextGlyph
+		g, x = b.place(it, x)
+		if g.Advance > 0 && !(hang && isBreakSpace(it.r)) {
+			end = g.X + g.Advance
+		}
+	}
+	return end
+}
+
+func (b *textBreaker) fits(end float32) bool {
+	return end <= b.layout.MaxWidth+textLayoutEpsilon
+}
+
+func (b *textBreaker) appendItems(items []textItem) {
+	for _, it := range items {
+		var g TextGlyph
+		g, b.x = b.place(it, b.x)
+		b.line = append(b.line, g)
+	}
+}
+
+func (b *textBreaker) lineWidth(glyphs []TextGlyph, start float32) float32 {
+	hang := b.layout.hangsSpaces()
+	for i := len(glyphs) - 1; i >= 0; i-- {
+		g := glyphs[i]
+		if g.Advance > 0 && !(hang && isBreakSpace(g.Rune)) {
+			return g.X + g.Advance
+		}
+	}
+	return start
+}
+
+func (b *textBreaker) lineStart() float32 {
+	if len(b.lines) == 0 {
+		return b.layout.Indent
+	}
+	return 0
+}
+
+func (b *textBreaker) endLine() {
+	if n := len(b.line); n > 0 && b.line[n-1].Rune == softHyphen {
+		// A soft hyphen that ends a line is shown as a hyphen
+		g := &b.line[n-1]
+		g.Rune = '-'
+		g.Advance = b.advance('-')
+	}
+	b.shapeLine()
+	b.lines = append(b.lines, TextLine{
+		Glyphs:    b.line,
+		Width:     b.lineWidth(b.line, b.lineStart()),
+		Direction: b.direction,
+	})
+	b.line = nil
+	b.x = 0
+}
+
+func (b *textBreaker) shapeLine() {
+	if b.shaper == nil || len(b.line) == 0 {
+		return
+	}
+	text := make([]rune, len(b.line))
+	for i := range b.line {
+		text[i] = b.line[i].Rune
+	}
+	shaped := b.shaper(text)
+	glyphs := make([]TextGlyph, 0, len(shaped))
+	x := b.line[0].X
+	for _, s := range shaped {
+		if s.Cluster < 0 || s.Cluster >= len(b.line) {
+			continue
+		}
+		src := b.line[s.Cluster]
+		var g TextGlyph
+		g, x = b.place(textItem{r: s.Rune, idx: src.Index, level: src.level}, x)
+		if g.Advance > 0 {
+			g.Advance += s.Kern
+			x += s.Kern
+		}
+		glyphs = append(glyphs, g)
+	}
+	b.line = glyphs
+}
+
+func (b *textBreaker) hasContent() bool {
+	for _, g := range b.line {
+		if g.Advance > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *textBreaker) layoutParagraph(p []textItem) {
+	b.resolveLevels(p)
+	if !b.layout.Wraps() {
+		b.appendItems(p)
+		return
+	}
+	start := 0
+	for i := range p {
+		if i == len(p)-1 || b.layout.canBreakAfter(p, i) {
+			b.layoutSegment(p[start : i+1])
+			start = i + 1
+		}
+	}
+}
+
+func (b *textBreaker) layoutSegment(seg []textItem) {
+	for len(seg) > 0 {
+		end := b.contentEnd(seg, b.x)
+		if seg[len(seg)-1].r == softHyphen && b.layout.Hyphens != TextHyphensNone {
+			end += b.layout.LetterSpacing + b.advance('-')
+		}
+		if b.fits(end) {
+			b.appendItems(seg)
+			return
+		}
+		if cut := b.hyphenate(seg); cut > 0 {
+			b.appendItems(seg[:cut])
+			var g TextGlyph
+			g, b.x = b.place(textItem{r: '-', idx: -1, level: seg[cut-1].level}, b.x)
+			b.line = append(b.line, g)
+			b.endLine()
+			seg = seg[cut:]
+			continue
+		}
+		if b.hasContent() {
+			b.endLine()
+			continue
+		}
+		if b.layout.OverflowWrap == TextOverflowWrapNormal {
+			b.appendItems(seg)
+			return
+		}
+		// Nothing else fits on the line, break the word between characters
+		cut := 1
+		for cut < len(seg) && b.fits(b.contentEnd(seg[:cut+1], b.x)) {
+			cut++
+		}
+		b.appendItems(seg[:cut])
+		if cut == len(seg) {
+			return
+		}
+		b.endLine()
+		seg = seg[cut:]
+	}
+}
+
+// hyphenate returns how many items of the segment fit on the current line when
+// followed by a hyphen, or zero if the word can't be hyphenated to fit
+func (b *textBreaker) hyphenate(seg []textItem) int {
+	if b.hyph == nil {
+		return 0
+	}
+	start := 0
+	for start < len(seg) && !unicode.IsLetter(seg[start].r) {
+		start++
+	}
+	end := start
+	for end < len(seg) && unicode.IsLetter(seg[end].r) {
+		end++
+	}
+	if end-start < 2 {
+		return 0
+	}
+	word := make([]rune, end-start)
+	for i := range word {
+		word[i] = seg[start+i].r
+	}
+	points := b.hyph.Hyphenate(word)
+	hyphen := b.layout.LetterSpacing + b.advance('-')
+	for i := len(points) - 1; i >= 0; i-- {
+		cut := start + points[i]
+		if cut <= start || cut >= end {
+			continue
+		}
+		if b.fits(b.contentEnd(seg[:cut], b.x) + hyphen) {
+			return cut
+		}
+	}
+	return 0
+}
+
+func (b *textBreaker) clamp() {
+	l := &b.layout
+	if l.MaxLines > 0 && len(b.lines) > l.MaxLines {
+		b.lines = b.lines[:l.MaxLines]
+		if l.Overflow == TextOverflowEllipsis {
+			b.ellipsize(l.MaxLines - 1)
+		}
+	}
+	if l.Overflow != TextOverflowEllipsis || l.MaxWidth <= 0 {
+		return
+	}
+	for i := range b.lines {
+		if !b.fits(b.lines[i].Width) {
+			b.ellipsize(i)
+		}
+	}
+}
+
+func (b *textBreaker) ellipsize(lineIdx int) {
+	line := &b.lines[lineIdx]
+	start := float32(0)
+	if lineIdx == 0 {
+		start = b.layout.Indent
+	}
+	ellipsis := []rune(b.layout.Ellipsis)
+	if len(ellipsis) == 0 {
+		ellipsis = []rune(defaultEllipsis)
+	}
+	ellipsisWidth := float32(0)
+	for _, r := range ellipsis {
+		ellipsisWidth += b.advance(r) + b.layout.LetterSpacing
+	}
+	ellipsisWidth -= b.layout.LetterSpacing
+	glyphs := line.Glyphs
+	trim := func() {
+		for len(glyphs) > 0 {
+			last := glyphs[len(glyphs)-1]
+			if last.Visible() && !isBreakSpace(last.Rune) {
+				break
+			}
+			glyphs = glyphs[:len(glyphs)-1]
+		}
+	}
+	trim()
+	penEnd := func() float32 {
+		if len(glyphs) == 0 {
+			return start
+		}
+		last := glyphs[len(glyphs)-1]
+		return last.X + last.Advance + b.layout.LetterSpacing
+	}
+	if b.layout.MaxWidth > 0 {
+		for len(glyphs) > 0 && !b.fits(penEnd()+ellipsisWidth) {
+			glyphs = glyphs[:len(glyphs)-1]
+			trim()
+		}
+	}
+	x := penEnd()
+	for _, r := range ellipsis {
+		var g TextGlyph
+		g, x = b.place(textItem{r: r, idx: -1, level: line.Direction.level()}, x)
+		glyphs = append(glyphs, g)
+	}
+	line.Glyphs = glyphs
+	line.Width = b.lineWidth(glyphs, start)
+}
+
+func (b *textBreaker) resolveLevels(p []textItem) {
+	b.direction = b.layout.Direction
+	runes := make([]rune, len(p))
+	for i := range p {
+		runes[i] = p[i].r
+	}
+	if b.layout.Bidi == TextBidiPlaintext {
+		if dir, ok := FirstStrongDirection(runes); ok {
+			b.direction = dir
+		}
+	}
+	levels := BidiLevels(runes, b.direction, b.layout.Bidi)
+	rtl := b.direction == TextDirectionRTL
+	for i := range p {
+		p[i].level = levels[i]
+		rtl = rtl || levels[i] > 0
+	}
+	b.bidi = b.bidi || rtl
+}
+
+// reorder places the glyphs of each line in display order, the glyph order
+// is left as is for lines that are purely left to right
+func (b *textBreaker) reorder() {
+	if !b.bidi {
+		return
+	}
+	for i := range b.lines {
+		line := &b.lines[i]
+		start := float32(0)
+		if i == 0 {
+			start = b.layout.Indent
+		}
+		base := line.Direction.level()
+		levels := make([]uint8, len(line.Glyphs))
+		// L1: trailing white space and tabs take the paragraph level
+		trailing := true
+		hanging := 0
+		for j := len(line.Glyphs) - 1; j >= 0; j-- {
+			g := line.Glyphs[j]
+			levels[j] = g.level
+			c := bidiClass(g.Rune)
+			reset := c == bidi.S || c == bidi.B ||
+				(trailing && (c == bidi.WS || c == bidi.BN || !g.Visible()))
+			if reset {
+				levels[j] = base
+				if b.layout.hangsSpaces() && hanging == len(line.Glyphs)-1-j {
+					hanging++
+				}
+			}
+			trailing = reset
+		}
+		order := BidiVisualOrder(levels)
+		glyphs := make([]TextGlyph, len(order))
+		x := start
+		if base == 1 {
+			// Hanging spaces at the end of a right to left line hang off
+			// the left edge of the line
+			for _, g := range line.Glyphs[len(line.Glyphs)-hanging:] {
+				x -= g.Advance + b.layout.LetterSpacing
+			}
+		}
+		for j, o := range order {
+			g := line.Glyphs[o]
+			g.level = levels[o]
+			if g.level%2 == 1 {
+				g.Rune = BidiMirror(g.Rune)
+			}
+			g.X = x
+			x += g.Advance
+			if g.Advance > 0 {
+				x += b.layout.LetterSpacing
+			}
+			glyphs[j] = g
+		}
+		line.Glyphs = glyphs
+		if base == 1 {
+			line.Width = b.lineWidth(glyphs, start)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+/******************************************************************************/
+/* text_layout_test.go                                                        */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package rendering
+
+import (
+	"strings"
+	"testing"
+)
+
+func monoAdvance(r rune) float32 { return 10 }
+
+func layoutStrings(text string, layout TextLayout) []string {
+	lines := LayoutText([]rune(text), layout, monoAdvance)
+	out := make([]string, len(lines))
+	for i := range lines {
+		sb := strings.Builder{}
+		for _, g := range lines[i].Glyphs {
+			if g.Visible() {
+				sb.WriteRune(g.Rune)
+			}
+		}
+		out[i] = sb.String()
+	}
+	return out
+}
+
+func expectLines(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d lines %q but got %d lines %q", len(want), want, len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d expected %q but got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestLayoutTextWrapsAtSpaces(t *testing.T) {
+	got := layoutStrings("hello big world", TextLayout{MaxWidth: 80})
+	expectLines(t, got, "hello ", "big ", "world")
+}
+
+func TestLayoutTextHangingSpacesDoNotCountToWidth(t *testing.T) {
+	lines := LayoutText([]rune("abc def"), TextLayout{MaxWidth: 30}, monoAdvance)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines but got %d", len(lines))
+	}
+	if lines[0].Width != 30 {
+		t.Errorf("expected the first line to be 30 wide but was %f", lines[0].Width)
+	}
+}
+
+func TestLayoutTextWhiteSpaceNormalCollapses(t *testing.T) {
+	got := layoutStrings("  a \t\n  b  ", TextLayout{WhiteSpace: TextWhiteSpaceNormal})
+	expectLines(t, got, "a b ")
+}
+
+func TestLayoutTextWhiteSpacePreKeepsEverything(t *testing.T) {
+	got := layoutStrings("  a  b\nc", TextLayout{MaxWidth: 20, WhiteSpace: TextWhiteSpacePre})
+	expectLines(t, got, "  a  b", "c")
+}
+
+func TestLayoutTextWhiteSpacePreLine(t *testing.T) {
+	got := layoutStrings("a   b  \n  c", TextLayout{WhiteSpace: TextWhiteSpacePreLine})
+	expectLines(t, got, "a b", "c")
+}
+
+func TestLayoutTextNoWrap(t *testing.T) {
+	got := layoutStrings("one two three", TextLayout{MaxWidth: 30, WhiteSpace: TextWhiteSpaceNoWrap})
+	expectLines(t, got, "one two three")
+}
+
+func TestLayoutTextBreakSpaces(t *testing.T) {
+	lines := LayoutText([]rune("ab   cd"), TextLayout{MaxWidth: 40,
+		WhiteSpace: TextWhiteSpaceBreakSpaces}, monoAdvance)
+	if len(lines) != 2 || lines[0].Width != 40 {
+		t.Fatalf("expected spaces to take up the first line, got %d lines", len(lines))
+	}
+}
+
+func TestLayoutTextTabStops(t *testing.T) {
+	lines := LayoutText([]rune("a\tb"), TextLayout{TabSize: 4}, monoAdvance)
+	if got := lines[0].Glyphs[2].X; got != 40 {
+		t.Errorf("expected the tab to advance to 40 but b was at %f", got)
+	}
+}
+
+func TestLayoutTextIndentAndWordSpacing(t *testing.T) {
+	lines := LayoutText([]rune("ab cd"), TextLayout{Indent: 15, WordSpacing: 5}, monoAdvance)
+	g := lines[0].Glyphs
+	if g[0].X != 15 || g[3].X != 50 {
+		t.Errorf("expected glyphs at 15 and 50 but got %f and %f", g[0].X, g[3].X)
+	}
+}
+
+func TestLayoutTextLetterSpacing(t *testing.T) {
+	lines := LayoutText([]rune("abc"), TextLayout{LetterSpacing: 2}, monoAdvance)
+	if lines[0].Width != 34 {
+		t.Errorf("expected the width to skip the trailing spacing but got %f", lines[0].Width)
+	}
+}
+
+func TestLayoutTextLongWordOverflowsByDefault(t *testing.T) {
+	got := layoutStrings("abcdefgh ij", TextLayout{MaxWidth: 50})
+	expectLines(t, got, "abcdefgh ", "ij")
+}
+
+func TestLayoutTextOverflowWrapBreakWord(t *testing.T) {
+	got := layoutStrings("abcdefgh ij", TextLayout{MaxWidth: 60,
+		OverflowWrap: TextOverflowWrapBreakWord})
+	expectLines(t, got, "abcdef", "gh ij")
+}
+
+func TestLayoutTextWordBreakBreakAll(t *testing.T) {
+	got := layoutStrings("ab cdefg", TextLayout{MaxWidth: 50, WordBreak: TextWordBreakBreakAll})
+	expectLines(t, got, "ab cd", "efg")
+}
+
+func TestLayoutTextLineBreakAnywhere(t *testing.T) {
+	got := layoutStrings("abcdef", TextLayout{MaxWidth: 30, LineBreak: TextLineBreakAnywhere})
+	expectLines(t, got, "abc", "def")
+}
+
+func TestLayoutTextCJK(t *testing.T) {
+	got := layoutStrings("日本語です。", TextLayout{MaxWidth: 50})
+	expectLines(t, got, "日本語で", "す。")
+	got = layoutStrings("日本語です。", TextLayout{MaxWidth: 50, WordBreak: TextWordBreakKeepAll})
+	expectLines(t, got, "日本語です。")
+}
+
+func TestLayoutTextStrictLineBreak(t *testing.T) {
+	got := layoutStrings("あいうぇお", TextLayout{MaxWidth: 30})
+	expectLines(t, got, "あいう", "ぇお")
+	got = layoutStrings("あいうぇお", TextLayout{MaxWidth: 30, LineBreak: TextLineBreakStrict})
+	expectLines(t, got, "あい", "うぇお")
+}
+
+func TestLayoutTextHardHyphen(t *testing.T) {
+	got := layoutStrings("well-known", TextLayout{MaxWidth: 60})
+	expectLines(t, got, "well-", "known")
+}
+
+func TestLayoutTextSoftHyphen(t *testing.T) {
+	text := "hyph\u00ADen\u00ADation"
+	got := layoutStrings(text, TextLayout{MaxWidth: 70})
+	expectLines(t, got, "hyphen-", "ation")
+	got = layoutStrings(text, TextLayout{MaxWidth: 70, Hyphens: TextHyphensNone})
+	expectLines(t, got, "hyphenation")
+	got = layoutStrings(text, TextLayout{})
+	expectLines(t, got, "hyphenation")
+}
+
+func TestLayoutTextAutoHyphens(t *testing.T) {
+	RegisterHyphenator("xx-test", NewHyphenationPatterns(nil, []string{"lo-ca-lized"}))
+	layout := TextLayout{MaxWidth: 60, Hyphens: TextHyphensAuto, Language: "xx-test-region"}
+	lines := LayoutText([]rune("localized"), layout, monoAdvance)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines but got %d", len(lines))
+	}
+	last := lines[0].Glyphs[len(lines[0].Glyphs)-1]
+	if last.Rune != '-' || last.Index != -1 {
+		t.Errorf("expected an inserted hyphen but got %q at %d", last.Rune, last.Index)
+	}
+	expectLines(t, layoutStrings("localized", layout), "loca-", "lized")
+}
+
+func TestLayoutTextEllipsis(t *testing.T) {
+	layout := TextLayout{MaxWidth: 50, WhiteSpace: TextWhiteSpaceNoWrap,
+		Overflow: TextOverflowEllipsis}
+	expectLines(t, layoutStrings("abcdefgh", layout), "abcd…")
+	expectLines(t, layoutStrings("abc", layout), "abc")
+	layout.Ellipsis = "..."
+	expectLines(t, layoutStrings("abcdefgh", layout), "ab...")
+}
+
+func TestLayoutTextLineClamp(t *testing.T) {
+	layout := TextLayout{MaxWidth: 50, MaxLines: 2, Overflow: TextOverflowEllipsis}
+	expectLines(t, layoutStrings("one two three four", layout), "one ", "two…")
+	layout.Overflow = TextOverflowClip
+	expectLines(t, layoutStrings("one two three four", layout), "one ", "two ")
+}
+
+func TestLayoutTextSourceIndexes(t *testing.T) {
+	lines := LayoutText([]rune("a  b"), TextLayout{WhiteSpace: TextWhiteSpaceNormal}, monoAdvance)
+	g := lines[0].Glyphs
+	if len(g) != 3 || g[0].Index != 0 || g[1].Index != 1 || g[2].Index != 3 {
+		t.Errorf("unexpected source indexes %v", g)
+	}
+}
+
+func TestLayoutTextNewLines(t *testing.T) {
+	expectLines(t, layoutStrings("a\n\nb\n", TextLayout{}), "a", "", "b")
+	if lines := LayoutText(nil, TextLayout{}, monoAdvance); len(lines) != 0 {
+		t.Errorf("expected no lines for empty text but got %d", len(lines))
+	}
+}
+
+// testShaper joins "fi" into a ligature and tightens "AV" by 2 units
+func testShaper(text []rune) []ShapedGlyph {
+	var out []ShapedGlyph
+	for i := 0; i < len(text); i++ {
+		if text[i] == 'f' && i+1 < len(text) && text[i+1] == 'i' {
+			out = append(out, ShapedGlyph{Rune: 'ﬁ', Cluster: i})
+			i++
+			continue
+		}
+		g := ShapedGlyph{Rune: text[i], Cluster: i}
+		if text[i] == 'A' && i+1 < len(text) && text[i+1] == 'V' {
+			g.Kern = -2
+		}
+		out = append(out, g)
+	}
+	return out
+}
+
+func TestLayoutShapedTextAppliesShaperPerLine(t *testing.T) {
+	lines := LayoutShapedText([]rune("fix AV\nfi"), TextLayout{}, monoAdvance, testShaper)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines but got %d", len(lines))
+	}
+	first := lines[0].Glyphs
+	if first[0].Rune != 'ﬁ' || first[0].Index != 0 || first[1].Rune != 'x' || first[1].Index != 2 {
+		t.Errorf("expected the ligature to keep the index of its first character, got %+v", first[:2])
+	}
+	if first[3].Advance != 8 || first[4].X != 38 {
+		t.Errorf("expected the kerning to pull V closer, got %+v", first[3:5])
+	}
+	if lines[0].Width != 48 {
+		t.Errorf("expected the shaped line to be 48 wide but was %f", lines[0].Width)
+	}
+	if lines[1].Glyphs[0].Rune != 'ﬁ' {
+		t.Errorf("expected the second line to be shaped, got %+v", lines[1].Glyphs)
+	}
+}
+
+func TestFontFeaturesTags(t *testing.T) {
+	tags := FontFeatures{}.Tags(false)
+	if !tags["liga"] || !tags["kern"] || !tags["calt"] || tags["dlig"] || tags["smcp"] {
+		t.Errorf("unexpected default features %v", tags)
+	}
+	tags = FontFeatures{
+		Kerning:   FontKerningNone,
+		Ligatures: FontLigaturesNone | FontLigaturesDiscretionary,
+		Caps:      FontCapsAllSmall,
+		Numeric:   FontNumericTabular | FontNumericSlashedZero,
+		Position:  FontPositionSuper,
+		Settings:  "ss01=1,smcp=0",
+	}.Tags(false)
+	for tag, want := range map[string]bool{"kern": false, "liga": false, "calt": false,
+		"dlig": true, "smcp": false, "c2sc": true, "tnum": true, "zero": true, "sups": true, "ss01": true} {
+		if tags[tag] != want {
+			t.Errorf("expected %s to be %t", tag, want)
+		}
+	}
+	if tags := (FontFeatures{}).Tags(true); tags["liga"] || tags["calt"] || !tags["rlig"] {
+		t.Errorf("expected letter spacing to disable optional ligatures, got %v", tags)
+	}
+}
@@ -7,6 +7,7 @@
 package font_to_msdf
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"os/exec"
@@ -25,8 +26,9 @@ const binDir = "../tools/content_tools/"
 // data object. To do this, it uses the open source msdf-atlas-gen executable
 // that is packaged with the engine, and generate the .json and .png files, then
 // reads the .json file into the [kaiju_font.FontData] structure. A
-// [kaiju_font.KaijuFont] is returned with the font details and the MSDF version
-// of a PNG file.
+// [kaiju_font.KaijuFont] is returned with the font details, the MSDF version
+// of a PNG file and the shaping tables of the font. The atlas also holds the
+// ligatures and alternate forms that the characters can be substituted with.
 func ProcessTTF(ttfFile string, charsetFile string) (kaiju_font.KaijuFont, error) {
 	var f *os.File
 	var err error
@@ -40,8 +42,18 @@ func ProcessTTF(ttfFile string, charsetFile string) (kaiju_font.KaijuFont, error
 	}
 	pngFile := f.Name()
 	f.Close()
+	if f, err = os.CreateTemp("", "*.txt"); err != nil {
+		return kaiju_font.KaijuFont{}, err
+	}
+	glyphsetFile := f.Name()
+	f.Close()
 	defer os.Remove(jsonFile)
 	defer os.Remove(pngFile)
+	defer os.Remove(glyphsetFile)
+	font, shaping, err := PrepareGlyphset(ttfFile, charsetFile, glyphsetFile)
+	if err != nil {
+		return kaiju_font.KaijuFont{}, err
+	}
 	if build.Debug && runtime.GOOS == "linux" {
 		klib.NotYetImplemented(325)
 	}
@@ -49,7 +61,7 @@ func ProcessTTF(ttfFile string, charsetFile string) (kaiju_font.KaijuFont, error
 		"-font", ttfFile,
 		"-pxrange", "4",
 		"-size", "64",
-		"-charset", charsetFile,
+		"-glyphset", glyphsetFile,
 		"-fontname", ttfFile,
 		"-type", "msdf",
 		"-format", "png",
@@ -65,6 +77,15 @@ func ProcessTTF(ttfFile string, charsetFile string) (kaiju_font.KaijuFont, error
 	if err = json.NewDecoder(strings.NewReader(string(jsonBin))).Decode(&kf.Details); err != nil {
 		return kaiju_font.KaijuFont{}, nil
 	}
+	for i := range kf.Details.Glyphs {
+		g := &kf.Details.Glyphs[i]
+		g.Unicode = int(font.GlyphRune(uint16(g.Index)))
+	}
+	shapingData := bytes.Buffer{}
+	if err = shaping.Encode(&shapingData); err != nil {
+		return kaiju_font.KaijuFont{}, err
+	}
+	kf.Shaping = shapingData.Bytes()
 	kf.PNG, err = os.ReadFile(pngFile)
 	return kf, err
 }
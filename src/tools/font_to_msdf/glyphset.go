@@ -0,0 +1,118 @@
+/******************************************************************************/
+/* glyphset.go                                                                */
+/******************************************************************************/
+/* MIT License, Copyright (c) 2015-present Brent Farris, (John 4:13-14)       */
+/******************************************************************************/
+
+package font_to_msdf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"kaijuengine.com/rendering/loaders/opentype"
+)
+
+// PrepareGlyphset reads the TTF and the msdf-atlas-gen charset file and writes
+// a glyph set file to glyphsetFile holding every glyph needed to shape the
+// charset, including the ligatures and alternate forms the font substitutes.
+// The returned font maps the glyph indexes of the atlas back to the runes the
+// glyphs are stored under, see [opentype.Font.GlyphRune]
+func PrepareGlyphset(ttfFile, charsetFile, glyphsetFile string) (*opentype.Font, *opentype.Shaping, error) {
+	ttf, err := os.ReadFile(ttfFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	font, err := opentype.Parse(ttf)
+	if err != nil {
+		return nil, nil, err
+	}
+	src, err := os.ReadFile(charsetFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	charset, err := ParseCharset(string(src))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the charset %s: %w", charsetFile, err)
+	}
+	glyphs, shaping := font.Subset(charset)
+	sb := strings.Builder{}
+	for i, g := range glyphs {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(strconv.Itoa(int(g)))
+	}
+	return font, shaping, os.WriteFile(glyphsetFile, []byte(sb.String()), os.ModePerm)
+}
+
+// ParseCharset reads the msdf-atlas-gen charset syntax, a list of quoted
+// characters ('a'), code points (97 or 0x61), ranges ([0x20, 0x7E]) and
+// strings ("abc") separated by commas or white space
+func ParseCharset(src string) ([]rune, error) {
+	var out []rune
+	var rangeStart []rune
+	inRange := false
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ',' || c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '[':
+			inRange, rangeStart = true, nil
+			i++
+		case c == ']':
+			if !inRange || len(rangeStart) != 2 {
+				return nil, errors.New("a range must have a start and an end")
+			}
+			for r := rangeStart[0]; r <= rangeStart[1]; r++ {
+				out = append(out, r)
+			}
+			inRange = false
+			i++
+		case c == '\'' || c == '"':
+			end := i + 1
+			var runes []rune
+			for end < len(src) && src[end] != c {
+				if src[end] == '\\' && end+1 < len(src) {
+					end++
+				}
+				r, size := utf8.DecodeRuneInString(src[end:])
+				runes = append(runes, r)
+				end += size
+			}
+			if end >= len(src) || (c == '\'' && len(runes) != 1) {
+				return nil, fmt.Errorf("unterminated or invalid quote at %d", i)
+			}
+			if inRange {
+				rangeStart = append(rangeStart, runes...)
+			} else {
+				out = append(out, runes...)
+			}
+			i = end + 1
+		default:
+			end := i
+			for end < len(src) && strings.IndexByte(", \t\r\n[]'\"", src[end]) < 0 {
+				end++
+			}
+			v, err := strconv.ParseInt(src[i:end], 0, 32)
+			if err != nil {
+				return nil, err
+			}
+			if inRange {
+				rangeStart = append(rangeStart, rune(v))
+			} else {
+				out = append(out, rune(v))
+			}
+			i = end
+		}
+	}
+	if inRange {
+		return nil, errors.New("unterminated range")
+	}
+	return out, nil
+}